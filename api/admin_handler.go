@@ -2,17 +2,23 @@
 package api
 
 import (
+	"cmp"
+	"context"
 	"database/sql"
 	"errors"
-	"log"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	db "github.com/pranav244872/synapse/db/sqlc"
+	"github.com/pranav244872/synapse/skillz"
+	"github.com/pranav244872/synapse/util"
 )
 
 // Generic type in Go for paginated responses using Go 1.18+ generics.
@@ -34,28 +40,27 @@ type listTeamsRequest struct {
 
 // listTeams handles retrieving teams with proper pagination and filtering
 func (server *Server) listTeams(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting listTeams handler")
+	slog.Debug("Starting listTeams handler")
 
 	var req listTeamsRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		log.Printf("DEBUG: Teams query bind error: %v", err)
+		slog.Debug("Teams query bind error", "error", err)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Teams request params - PageID: %d, PageSize: %d, Unmanaged: %v", 
-		req.PageID, req.PageSize, req.Unmanaged)
+	slog.Debug("Teams request params", "page_id", req.PageID, "page_size", req.PageSize, "unmanaged", req.Unmanaged)
 
 	// This branch is optimized for dropdowns or selection lists in UIs
 	if req.Unmanaged != nil && *req.Unmanaged {
-		log.Printf("DEBUG: Processing unmanaged teams request")
+		slog.Debug("Processing unmanaged teams request")
 		unmanagedTeams, err := server.store.ListUnmanagedTeams(ctx)
 		if err != nil {
-			log.Printf("DEBUG: Error listing unmanaged teams: %v", err)
+			slog.Debug("Error listing unmanaged teams", "error", err)
 			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 			return
 		}
-		log.Printf("DEBUG: Successfully retrieved %d unmanaged teams", len(unmanagedTeams))
+		slog.Debug("Successfully retrieved unmanaged teams", "count", len(unmanagedTeams))
 		ctx.JSON(http.StatusOK, unmanagedTeams)
 		return
 	}
@@ -66,23 +71,23 @@ func (server *Server) listTeams(ctx *gin.Context) {
 		Offset: (req.PageID - 1) * req.PageSize,
 	}
 
-	log.Printf("DEBUG: Querying teams with limit: %d, offset: %d", arg.Limit, arg.Offset)
+	slog.Debug("Querying teams", "limit", arg.Limit, "offset", arg.Offset)
 
 	teams, err := server.store.ListTeamsWithManagers(ctx, arg)
 	if err != nil {
-		log.Printf("DEBUG: Error listing teams with managers: %v", err)
+		slog.Debug("Error listing teams with managers", "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
 	totalCount, err := server.store.CountTeams(ctx) // Needed for pagination metadata
 	if err != nil {
-		log.Printf("DEBUG: Error counting teams: %v", err)
+		slog.Debug("Error counting teams", "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Successfully retrieved %d teams, total count: %d", len(teams), totalCount)
+	slog.Debug("Successfully retrieved teams", "count", len(teams), "total_count", totalCount)
 
 	rsp := paginatedResponse[db.ListTeamsWithManagersRow]{
 		TotalCount: totalCount,
@@ -98,16 +103,16 @@ type createTeamRequest struct {
 
 // createTeamAdmin handles creating a new team by admin users
 func (server *Server) createTeamAdmin(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting createTeamAdmin handler")
+	slog.Debug("Starting createTeamAdmin handler")
 
 	var req createTeamRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		log.Printf("DEBUG: Create team JSON bind error: %v", err)
+		slog.Debug("Create team JSON bind error", "error", err)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Creating team with name: '%s'", req.TeamName)
+	slog.Debug("Creating team", "team_name", req.TeamName)
 
 	arg := db.CreateTeamParams{
 		TeamName: req.TeamName,
@@ -115,24 +120,165 @@ func (server *Server) createTeamAdmin(ctx *gin.Context) {
 
 	team, err := server.store.CreateTeam(ctx, arg)
 	if err != nil {
-		log.Printf("DEBUG: Error creating team: %v", err)
+		slog.Debug("Error creating team", "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Successfully created team with ID: %d", team.ID)
+	slog.Debug("Successfully created team", "team_id", team.ID)
 	ctx.JSON(http.StatusCreated, team)
 }
 
+// teamMemberSummaries lists the fields of each member the team deletion responses show
+func teamMemberSummaries(members []db.User) []gin.H {
+	summaries := make([]gin.H, len(members))
+	for i, member := range members {
+		summaries[i] = gin.H{
+			"id":    member.ID,
+			"name":  member.Name,
+			"email": member.Email,
+			"role":  member.Role,
+		}
+	}
+	return summaries
+}
+
+// GET /admin/teams/:id/delete-impact - Analyze team deletion impact without deleting
+// Returns the same summary as the user deletion impact, for admin UI confirmation dialogs
+func (server *Server) getTeamDeletionImpact(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("invalid team ID")))
+		return
+	}
+
+	// Execute dry-run deletion impact analysis
+	result, err := server.store.GetTeamDeletionImpactTx(ctx, db.SafeDeleteTeamTxParams{
+		TeamID: id,
+	})
+	if err != nil {
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
+		if errors.Is(err, db.ErrTeamNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Construct detailed impact response for UI
+	response := gin.H{
+		"team": gin.H{
+			"id":         result.Team.ID,
+			"team_name":  result.Team.TeamName,
+			"manager_id": result.Team.ManagerID,
+		},
+		"can_delete": result.CanDelete,
+		"impact": gin.H{
+			"members_to_unassign": gin.H{
+				"count":   len(result.MembersToUnassign),
+				"details": teamMemberSummaries(result.MembersToUnassign),
+			},
+			"active_projects":       result.ActiveProjects,
+			"projects_to_remove":    result.ProjectsToRemove,
+			"invitations_to_remove": result.InvitationsToRemove,
+		},
+	}
+
+	// Add blocking reason if deletion is not allowed
+	if !result.CanDelete {
+		response["blocking_reason"] = result.BlockingReason
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// DELETE /admin/teams/:id - Safely delete a team whose projects are all archived
+// Members are unassigned, and archived projects and invitations to the team are removed
+func (server *Server) deleteTeamAdmin(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("invalid team ID")))
+		return
+	}
+
+	// Execute safe deletion transaction
+	result, err := server.store.SafeDeleteTeamTx(ctx, db.SafeDeleteTeamTxParams{
+		TeamID: id,
+	})
+	if err != nil {
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, db.ErrTeamNotFound):
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		case errors.Is(err, db.ErrTeamHasActiveProjects):
+			ctx.JSON(http.StatusConflict, gin.H{
+				"error":           err.Error(),
+				"blocking_reason": err.Error(),
+			})
+		default:
+			slog.Error("Failed to delete team", "team_id", id, "error", err)
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	slog.Info("Team deleted",
+		"team_id", id,
+		"removed_members", len(result.RemovedMembers),
+		"removed_projects", result.RemovedProjects,
+		"removed_invitations", result.RemovedInvitations,
+	)
+
+	// Team membership feeds recommendations, so earlier answers are stale
+	server.notifyRecommender()
+
+	// Return comprehensive summary of deletion impact
+	ctx.JSON(http.StatusOK, gin.H{
+		"deleted_team": result.DeletedTeam,
+		"removed_members": gin.H{
+			"count":   len(result.RemovedMembers),
+			"details": teamMemberSummaries(result.RemovedMembers),
+		},
+		"removed_projects":    result.RemovedProjects,
+		"removed_invitations": result.RemovedInvitations,
+	})
+}
+
 ////////////////////////////////////////////////////////////////////////
 // Invitations Management
 ////////////////////////////////////////////////////////////////////////
 
 type listAdminInvitationsRequest struct {
-	PageID      int32  `form:"page_id" binding:"required,min=1"`
-	PageSize    int32  `form:"page_size" binding:"required,min=5,max=20"`
-	InviterID   string `form:"inviter_id"`
-	InviterRole string `form:"inviter_role" binding:"omitempty,oneof=admin manager"`
+	PageID      int32     `form:"page_id" binding:"required,min=1"`
+	PageSize    int32     `form:"page_size" binding:"required,min=5,max=20"`
+	InviterID   string    `form:"inviter_id"`
+	InviterRole string    `form:"inviter_role" binding:"omitempty,oneof=admin manager"`
+	From        time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"` // Optional RFC 3339 lower bound on created_at
+	To          time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`   // Optional RFC 3339 upper bound on created_at
+}
+
+// createdAtRange converts the optional from/to bounds into nullable timestamps.
+func (req listAdminInvitationsRequest) createdAtRange() (pgtype.Timestamp, pgtype.Timestamp, error) {
+	return timestampRange(req.From, req.To)
+}
+
+// timestampRange converts optional from/to query bounds into nullable timestamps, rejecting inverted ranges.
+// Timestamps are stored without a time zone in UTC, so bounds are normalized to UTC.
+func timestampRange(fromTime, toTime time.Time) (pgtype.Timestamp, pgtype.Timestamp, error) {
+	if !fromTime.IsZero() && !toTime.IsZero() && fromTime.After(toTime) {
+		return pgtype.Timestamp{}, pgtype.Timestamp{}, errors.New("from must not be after to")
+	}
+
+	from := pgtype.Timestamp{Time: fromTime.UTC(), Valid: !fromTime.IsZero()}
+	to := pgtype.Timestamp{Time: toTime.UTC(), Valid: !toTime.IsZero()}
+	return from, to, nil
 }
 
 type invitationResponse struct {
@@ -147,21 +293,26 @@ type invitationResponse struct {
 
 // listInvitations handles retrieving invitations with filtering and pagination
 func (server *Server) listInvitations(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting listInvitations handler")
+	slog.Debug("Starting listInvitations handler")
 
 	var req listAdminInvitationsRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		log.Printf("DEBUG: Invitations query bind error: %v", err)
+		slog.Debug("Invitations query bind error", "error", err)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Invitations request params - PageID: %d, PageSize: %d, InviterID: '%s', InviterRole: '%s'", 
-		req.PageID, req.PageSize, req.InviterID, req.InviterRole)
+	slog.Debug("Invitations request params", "page_id", req.PageID, "page_size", req.PageSize, "inviter_id", req.InviterID, "inviter_role", req.InviterRole)
+
+	createdFrom, createdTo, err := req.createdAtRange()
+	if err != nil {
+		slog.Debug("Invalid invitation date range", "from", req.From, "to", req.To)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
 
 	var finalInvitations []invitationResponse
 	var totalCount int64
-	var err error
 
 	// Helper function to convert different SQLC row types to our unified response type
 	toResponse := func(i any) invitationResponse {
@@ -172,27 +323,27 @@ func (server *Server) listInvitations(ctx *gin.Context) {
 			if role, ok := v.InviterRole.(string); ok {
 				inviterRole = role
 			}
-			log.Printf("DEBUG: Converting ListAllInvitationsRow - ID: %d, InviterRole: %s", v.ID, inviterRole)
+			slog.Debug("Converting ListAllInvitationsRow", "invitation_id", v.ID, "inviter_role", inviterRole)
 			return invitationResponse{
 				ID: v.ID, Email: v.Email, RoleToInvite: v.RoleToInvite, Status: v.Status,
 				InviterName: v.InviterName, InviterRole: inviterRole, CreatedAt: v.CreatedAt,
 			}
 		case db.ListInvitationsByInviterRow:
 			// InviterRole is already string type for this struct
-			log.Printf("DEBUG: Converting ListInvitationsByInviterRow - ID: %d, InviterRole: %s", v.ID, v.InviterRole)
+			slog.Debug("Converting ListInvitationsByInviterRow", "invitation_id", v.ID, "inviter_role", v.InviterRole)
 			return invitationResponse{
 				ID: v.ID, Email: v.Email, RoleToInvite: v.RoleToInvite, Status: v.Status,
 				InviterName: v.InviterName, InviterRole: v.InviterRole, CreatedAt: v.CreatedAt,
 			}
 		case db.ListInvitationsByInviterRoleRow:
 			// InviterRole is already string type for this struct
-			log.Printf("DEBUG: Converting ListInvitationsByInviterRoleRow - ID: %d, InviterRole: %s", v.ID, v.InviterRole)
+			slog.Debug("Converting ListInvitationsByInviterRoleRow", "invitation_id", v.ID, "inviter_role", v.InviterRole)
 			return invitationResponse{
 				ID: v.ID, Email: v.Email, RoleToInvite: v.RoleToInvite, Status: v.Status,
 				InviterName: v.InviterName, InviterRole: v.InviterRole, CreatedAt: v.CreatedAt,
 			}
 		default:
-			log.Printf("DEBUG: Unknown invitation type: %T", v)
+			slog.Debug("Unknown invitation type", "type", fmt.Sprintf("%T", v))
 			return invitationResponse{}
 		}
 	}
@@ -200,110 +351,117 @@ func (server *Server) listInvitations(ctx *gin.Context) {
 	// Route to appropriate query based on request parameters
 	switch {
 	case req.InviterID == "me":
-		log.Printf("DEBUG: Processing 'me' case - getting current user's invitations")
+		slog.Debug("Processing 'me' case - getting current user's invitations")
 
 		// Get authorization payload with proper error handling
-		authPayload, err := getAuthorizationPayload(ctx)
+		adminID, err := getUserIDFromPayload(ctx)
 		if err != nil {
-			log.Printf("DEBUG: Failed to get authorization payload: %v", err)
-			ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("unauthorized")))
+			slog.Debug("Failed to get user ID from authorization payload", "error", err)
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 			return
 		}
-
-		// Safely extract user_id with type assertion
-		userIDFloat, ok := authPayload["user_id"].(float64)
-		if !ok {
-			log.Printf("DEBUG: user_id not found or not a float64 in auth payload. Payload: %+v", authPayload)
-			ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("invalid user_id in token")))
-			return
-		}
-
-		adminID := int64(userIDFloat)
-		log.Printf("DEBUG: Extracted Admin ID: %d", adminID)
+		slog.Debug("Extracted admin ID", "admin_id", adminID)
 
 		// Query invitations by specific inviter
 		invitations, dbErr := server.store.ListInvitationsByInviter(ctx, db.ListInvitationsByInviterParams{
-			InviterID: adminID,
-			Limit:     req.PageSize,
-			Offset:    (req.PageID - 1) * req.PageSize,
+			InviterID:   adminID,
+			Limit:       req.PageSize,
+			Offset:      (req.PageID - 1) * req.PageSize,
+			CreatedFrom: createdFrom,
+			CreatedTo:   createdTo,
 		})
 		err = dbErr
 		if err == nil {
-			log.Printf("DEBUG: Retrieved %d invitations by inviter", len(invitations))
-			totalCount, err = server.store.CountInvitationsByInviter(ctx, adminID)
+			slog.Debug("Retrieved invitations by inviter", "count", len(invitations))
+			totalCount, err = server.store.CountInvitationsByInviter(ctx, db.CountInvitationsByInviterParams{
+				InviterID:   adminID,
+				CreatedFrom: createdFrom,
+				CreatedTo:   createdTo,
+			})
 			if err != nil {
-				log.Printf("DEBUG: Error counting invitations by inviter: %v", err)
+				slog.Debug("Error counting invitations by inviter", "error", err)
 			} else {
-				log.Printf("DEBUG: Total count by inviter: %d", totalCount)
+				slog.Debug("Counted invitations by inviter", "total_count", totalCount)
 			}
 			// Convert each invitation to response format
 			for _, inv := range invitations {
 				finalInvitations = append(finalInvitations, toResponse(inv))
 			}
 		} else {
-			log.Printf("DEBUG: Error listing invitations by inviter: %v", err)
+			slog.Debug("Error listing invitations by inviter", "error", err)
 		}
 
 	case req.InviterRole != "":
-		log.Printf("DEBUG: Processing inviter role case: %s", req.InviterRole)
+		slog.Debug("Processing inviter role case", "inviter_role", req.InviterRole)
 
 		// Query invitations by inviter role
 		invitations, dbErr := server.store.ListInvitationsByInviterRole(ctx, db.ListInvitationsByInviterRoleParams{
-			Role:   db.UserRole(req.InviterRole),
-			Limit:  req.PageSize,
-			Offset: (req.PageID - 1) * req.PageSize,
+			Role:        db.UserRole(req.InviterRole),
+			Limit:       req.PageSize,
+			Offset:      (req.PageID - 1) * req.PageSize,
+			CreatedFrom: createdFrom,
+			CreatedTo:   createdTo,
 		})
 		err = dbErr
 		if err == nil {
-			log.Printf("DEBUG: Retrieved %d invitations by role", len(invitations))
-			totalCount, err = server.store.CountInvitationsByInviterRole(ctx, db.UserRole(req.InviterRole))
+			slog.Debug("Retrieved invitations by role", "count", len(invitations))
+			totalCount, err = server.store.CountInvitationsByInviterRole(ctx, db.CountInvitationsByInviterRoleParams{
+				Role:        db.UserRole(req.InviterRole),
+				CreatedFrom: createdFrom,
+				CreatedTo:   createdTo,
+			})
 			if err != nil {
-				log.Printf("DEBUG: Error counting invitations by role: %v", err)
+				slog.Debug("Error counting invitations by role", "error", err)
 			} else {
-				log.Printf("DEBUG: Total count by role: %d", totalCount)
+				slog.Debug("Counted invitations by role", "total_count", totalCount)
 			}
 			// Convert each invitation to response format
 			for _, inv := range invitations {
 				finalInvitations = append(finalInvitations, toResponse(inv))
 			}
 		} else {
-			log.Printf("DEBUG: Error listing invitations by role: %v", err)
+			slog.Debug("Error listing invitations by role", "error", err)
 		}
 
 	default:
-		log.Printf("DEBUG: Processing default case (all invitations)")
+		slog.Debug("Processing default case (all invitations)")
 
 		// Query all invitations
 		invitations, dbErr := server.store.ListAllInvitations(ctx, db.ListAllInvitationsParams{
-			Limit:  req.PageSize,
-			Offset: (req.PageID - 1) * req.PageSize,
+			Limit:       req.PageSize,
+			Offset:      (req.PageID - 1) * req.PageSize,
+			CreatedFrom: createdFrom,
+			CreatedTo:   createdTo,
 		})
 		err = dbErr
 		if err == nil {
-			log.Printf("DEBUG: Retrieved %d all invitations", len(invitations))
-			totalCount, err = server.store.CountAllInvitations(ctx)
+			slog.Debug("Retrieved all invitations", "count", len(invitations))
+			totalCount, err = server.store.CountAllInvitations(ctx, db.CountAllInvitationsParams{
+				CreatedFrom: createdFrom,
+				CreatedTo:   createdTo,
+			})
 			if err != nil {
-				log.Printf("DEBUG: Error counting all invitations: %v", err)
+				slog.Debug("Error counting all invitations", "error", err)
 			} else {
-				log.Printf("DEBUG: Total count all: %d", totalCount)
+				slog.Debug("Counted all invitations", "total_count", totalCount)
 			}
 			// Convert each invitation to response format
 			for _, inv := range invitations {
 				finalInvitations = append(finalInvitations, toResponse(inv))
 			}
 		} else {
-			log.Printf("DEBUG: Error listing all invitations: %v", err)
+			slog.Debug("Error listing all invitations", "error", err)
 		}
 	}
 
 	// Handle any errors that occurred during database operations
 	if err != nil {
-		log.Printf("DEBUG: Final error before returning 500: %v", err)
+		slog.Debug("Final error before returning 500", "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Successfully processed, returning %d invitations", len(finalInvitations))
+	slog.Debug("Successfully processed, returning invitations", "count", len(finalInvitations))
 
 	// Build paginated response
 	rsp := paginatedResponse[invitationResponse]{
@@ -313,6 +471,51 @@ func (server *Server) listInvitations(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, rsp)
 }
 
+type invitationStatsRequest struct {
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=20"`
+	SortBy   string `form:"sort_by" binding:"omitempty,oneof=sent acceptance_rate"` // Defaults to "sent"
+}
+
+// getInvitationStats reports, per inviter, how many invitations were sent, accepted, pending and expired
+func (server *Server) getInvitationStats(ctx *gin.Context) {
+	slog.Debug("Starting getInvitationStats handler")
+
+	var req invitationStatsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	stats, err := server.store.ListInvitationStatsByInviter(ctx, db.ListInvitationStatsByInviterParams{
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+		SortBy: cmp.Or(req.SortBy, "sent"),
+	})
+	if err != nil {
+		slog.Error("Failed to list invitation stats", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	totalCount, err := server.store.CountInvitationInviters(ctx)
+	if err != nil {
+		slog.Error("Failed to count inviters", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if stats == nil {
+		stats = []db.ListInvitationStatsByInviterRow{}
+	}
+
+	slog.Debug("Returning invitation stats", "inviters", len(stats), "total_count", totalCount)
+	ctx.JSON(http.StatusOK, paginatedResponse[db.ListInvitationStatsByInviterRow]{
+		TotalCount: totalCount,
+		Data:       stats,
+	})
+}
+
 type createManagerInvitationRequest struct {
 	Email  string `json:"email" binding:"required,email"`
 	TeamID int64  `json:"team_id" binding:"required,min=1"`
@@ -320,35 +523,25 @@ type createManagerInvitationRequest struct {
 
 // createManagerInvitation handles creating invitations for manager role
 func (server *Server) createManagerInvitation(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting createManagerInvitation handler")
+	slog.Debug("Starting createManagerInvitation handler")
 
 	var req createManagerInvitationRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		log.Printf("DEBUG: Create manager invitation JSON bind error: %v", err)
+		slog.Debug("Create manager invitation JSON bind error", "error", err)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Creating manager invitation - Email: %s, TeamID: %d", req.Email, req.TeamID)
+	slog.Debug("Creating manager invitation", "email", req.Email, "team_id", req.TeamID)
 
 	// Get authorization payload with proper error handling
-	authPayload, err := getAuthorizationPayload(ctx)
+	inviterID, err := getUserIDFromPayload(ctx)
 	if err != nil {
-		log.Printf("DEBUG: Failed to get authorization payload for invitation creation: %v", err)
-		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("unauthorized")))
-		return
-	}
-
-	// Safely extract user_id with type assertion
-	userIDFloat, ok := authPayload["user_id"].(float64)
-	if !ok {
-		log.Printf("DEBUG: user_id not found or not a float64 in auth payload for invitation creation. Payload: %+v", authPayload)
-		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("invalid user_id in token")))
+		slog.Debug("Failed to get user ID from authorization payload for invitation creation", "error", err)
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
-
-	inviterID := int64(userIDFloat)
-	log.Printf("DEBUG: Extracted Inviter ID: %d", inviterID)
+	slog.Debug("Extracted inviter ID", "inviter_id", inviterID)
 
 	// Use the new CreateInvitationTx transaction function instead of the basic CreateInvitation
 	arg := db.CreateInvitationTxParams{
@@ -356,94 +549,245 @@ func (server *Server) createManagerInvitation(ctx *gin.Context) {
 		EmailToInvite: req.Email,
 		RoleToInvite:  db.UserRoleManager,
 		TeamID:        pgtype.Int8{Int64: req.TeamID, Valid: true},
+		Duration:      server.config.InvitationTTL,
 	}
 
-	log.Printf("DEBUG: Calling CreateInvitationTx with params: %+v", arg)
+	slog.Debug("Calling CreateInvitationTx", "inviter_id", arg.InviterID, "role", arg.RoleToInvite, "team_id", arg.TeamID.Int64)
 
 	result, err := server.store.CreateInvitationTx(ctx, arg)
 	if err != nil {
-		log.Printf("DEBUG: Error creating invitation: %v", err)
-
-		// Handle specific business logic errors from the transaction
-		switch {
-		case errors.Is(err, db.ErrPermissionDenied):
-			ctx.JSON(http.StatusForbidden, errorResponse(err))
-			return
-		case errors.Is(err, db.ErrDuplicateInvitation):
-			ctx.JSON(http.StatusConflict, errorResponse(err))
-			return
-		case errors.Is(err, db.ErrInvalidRoleSequence):
-			ctx.JSON(http.StatusBadRequest, errorResponse(err))
-			return
-		case errors.Is(err, db.ErrTeamIDRequiredForManager):
-			ctx.JSON(http.StatusBadRequest, errorResponse(err))
-			return
-		case errors.Is(err, db.ErrTeamNotFound):
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		case errors.Is(err, db.ErrTeamAlreadyHasManager):
-			ctx.JSON(http.StatusConflict, errorResponse(err))
-			return
-		default:
-			// Generic database or system error
-			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		if abortIfTimedOut(ctx, err) {
 			return
 		}
+		slog.Debug("Error creating invitation", "error", err)
+		ctx.JSON(invitationErrorStatus(err), errorResponse(err))
+		return
 	}
 
-	log.Printf("DEBUG: Successfully created invitation with ID: %d, Token: %s, Expires: %v", 
-		result.Invitation.ID, result.Invitation.InvitationToken, result.Invitation.ExpiresAt.Time)
+	slog.Debug("Successfully created invitation", "invitation_id", result.Invitation.ID, "expires_at", result.Invitation.ExpiresAt.Time)
+
+	// Email the invitee their accept link; failures are logged and do not fail the request
+	server.sendInvitationEmail(result.Invitation)
 
 	// Return the created invitation details
 	ctx.JSON(http.StatusCreated, result.Invitation)
 }
 
+// invitationErrorStatus maps a CreateInvitationTx error to the HTTP status it is answered with
+func invitationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, db.ErrPermissionDenied):
+		return http.StatusForbidden
+	case errors.Is(err, db.ErrDuplicateInvitation), errors.Is(err, db.ErrTeamAlreadyHasManager):
+		return http.StatusConflict
+	case errors.Is(err, db.ErrInvalidRoleSequence), errors.Is(err, db.ErrTeamIDRequiredForManager):
+		return http.StatusBadRequest
+	case errors.Is(err, db.ErrTeamNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// maxBulkManagerInvitations caps how many invitations a single bulk request may carry
+const maxBulkManagerInvitations = 100
+
+// bulkInvitationResult reports what happened to one entry of a bulk invitation, with the
+// status a single POST /admin/invitations for it would have answered
+type bulkInvitationResult struct {
+	Email      string                  `json:"email"`
+	TeamID     int64                   `json:"team_id"`
+	Status     int                     `json:"status"`
+	Error      string                  `json:"error,omitempty"`
+	Invitation *db.CreateInvitationRow `json:"invitation,omitempty"`
+}
+
+// bulkCreateManagerInvitations invites many managers at once. Each invitation gets its own
+// transaction, so a conflicting entry is reported in its result without undoing the others.
+func (server *Server) bulkCreateManagerInvitations(ctx *gin.Context) {
+	slog.Debug("Starting bulkCreateManagerInvitations handler")
+
+	var req []createManagerInvitationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		slog.Debug("Bulk manager invitation JSON bind error", "error", err)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if len(req) == 0 || len(req) > maxBulkManagerInvitations {
+		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("between 1 and %d invitations are required", maxBulkManagerInvitations)))
+		return
+	}
+
+	inviterID, err := getUserIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	created := 0
+	results := make([]bulkInvitationResult, len(req))
+	for i, item := range req {
+		results[i] = bulkInvitationResult{Email: item.Email, TeamID: item.TeamID}
+
+		result, err := server.store.CreateInvitationTx(ctx, db.CreateInvitationTxParams{
+			InviterID:     inviterID,
+			EmailToInvite: item.Email,
+			RoleToInvite:  db.UserRoleManager,
+			TeamID:        pgtype.Int8{Int64: item.TeamID, Valid: true},
+			Duration:      server.config.InvitationTTL,
+		})
+		if err != nil {
+			results[i].Status = invitationErrorStatus(err)
+			results[i].Error = err.Error()
+			if results[i].Status == http.StatusInternalServerError {
+				slog.Error("Failed to create invitation in bulk", "email", item.Email, "team_id", item.TeamID, "error", err)
+			}
+			continue
+		}
+
+		created++
+		results[i].Status = http.StatusCreated
+		results[i].Invitation = &result.Invitation
+
+		// Email the invitee their accept link; failures are logged and do not fail the entry
+		server.sendInvitationEmail(result.Invitation)
+	}
+	slog.Info("Bulk created manager invitations", "created", created, "requested", len(req))
+
+	ctx.JSON(http.StatusMultiStatus, gin.H{
+		"created": created,
+		"results": results,
+	})
+}
+
+// expireInvitations marks every pending invitation past its expiry as expired right away,
+// instead of waiting for the background job.
+func (server *Server) expireInvitations(ctx *gin.Context) {
+	expired, err := server.store.ExpirePendingInvitations(ctx)
+	if err != nil {
+		slog.Error("Failed to expire pending invitations", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	slog.Info("Expired stale pending invitations on request", "count", expired)
+	ctx.JSON(http.StatusOK, gin.H{"expired_count": expired})
+}
+
+// escalateStaleTasks raises the priority of old or overdue open, unassigned tasks right away,
+// instead of waiting for the background job.
+func (server *Server) escalateStaleTasks(ctx *gin.Context) {
+	authPayload, err := getAuthorizationPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.EscalateStaleTasksTx(ctx, server.taskEscalationParams(actorIDFromPayload(authPayload)))
+	if err != nil {
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
+		slog.Error("Failed to escalate stale tasks", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	tasks := result.Tasks
+	if tasks == nil {
+		tasks = []db.EscalateStaleTasksRow{}
+	}
+	slog.Info("Escalated stale tasks on request", "count", len(tasks))
+	ctx.JSON(http.StatusOK, gin.H{
+		"escalated_count": len(tasks),
+		"tasks":           tasks,
+	})
+}
+
 type deleteInvitationRequest struct {
 	ID int64 `uri:"id" binding:"required,min=1"`
 }
 
-// deleteInvitation handles removing pending invitations
+// removeInvitationQuery controls how a pending invitation is removed.
+// By default it is revoked (kept for auditing); hard=true deletes the row.
+type removeInvitationQuery struct {
+	Hard bool `form:"hard"`
+}
+
+// errInvitationNoLongerPending is returned when an invitation stops being pending before it could be removed
+var errInvitationNoLongerPending = errors.New("invitation is no longer pending")
+
+// removePendingInvitation revokes a pending invitation, or deletes it outright when hard is set
+func (server *Server) removePendingInvitation(ctx *gin.Context, invitationID int64, hard bool) error {
+	if hard {
+		return server.store.DeleteInvitation(ctx, invitationID)
+	}
+
+	revoked, err := server.store.RevokeInvitation(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	if revoked == 0 {
+		return errInvitationNoLongerPending
+	}
+	return nil
+}
+
+// deleteInvitation handles revoking (or, with hard=true, deleting) pending invitations
 func (server *Server) deleteInvitation(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting deleteInvitation handler")
+	slog.Debug("Starting deleteInvitation handler")
 
 	var req deleteInvitationRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		log.Printf("DEBUG: Delete invitation URI bind error: %v", err)
+		slog.Debug("Delete invitation URI bind error", "error", err)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var query removeInvitationQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		slog.Debug("Delete invitation query bind error", "error", err)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Deleting invitation with ID: %d", req.ID)
+	slog.Debug("Deleting invitation", "invitation_id", req.ID)
 
 	// First, check if the invitation exists and get its status
 	invitation, err := server.store.GetInvitationByID(ctx, req.ID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			log.Printf("DEBUG: Invitation not found")
+			slog.Debug("Invitation not found")
 			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("invitation not found")))
 			return
 		}
-		log.Printf("DEBUG: Error checking invitation: %v", err)
+		slog.Debug("Error checking invitation", "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
 	// Check if invitation can be deleted
 	if invitation.Status != "pending" {
-		log.Printf("DEBUG: Cannot delete invitation with status: %s", invitation.Status)
+		slog.Debug("Cannot delete invitation with status", "status", invitation.Status)
 		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("only pending invitations can be deleted")))
 		return
 	}
 
-	// Proceed with deletion
-	err = server.store.DeleteInvitation(ctx, req.ID)
+	// Proceed with revocation (or deletion when explicitly requested)
+	err = server.removePendingInvitation(ctx, req.ID, query.Hard)
 	if err != nil {
-		log.Printf("DEBUG: Error deleting invitation: %v", err)
+		if errors.Is(err, errInvitationNoLongerPending) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("only pending invitations can be deleted")))
+			return
+		}
+		slog.Debug("Error removing invitation", "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Successfully deleted invitation with ID: %d", req.ID)
+	slog.Debug("Successfully removed invitation", "invitation_id", req.ID, "hard_delete", query.Hard)
 	ctx.Status(http.StatusNoContent)
 }
 
@@ -460,17 +804,16 @@ type listSkillsAdminRequest struct {
 
 // listSkillsAdmin handles retrieving skills with verification status filtering
 func (server *Server) listSkillsAdmin(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting listSkillsAdmin handler")
+	slog.Debug("Starting listSkillsAdmin handler")
 
 	var req listSkillsAdminRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		log.Printf("DEBUG: Skills admin query bind error: %v", err)
+		slog.Debug("Skills admin query bind error", "error", err)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Skills admin request params - PageID: %d, PageSize: %d, Verified: %v, Search: '%s'", 
-		req.PageID, req.PageSize, *req.Verified, req.Search)
+	slog.Debug("Skills admin request params", "page_id", req.PageID, "page_size", req.PageSize, "verified", *req.Verified, "search", req.Search)
 
 	var skills []db.Skill
 	var totalCount int64
@@ -481,7 +824,7 @@ func (server *Server) listSkillsAdmin(ctx *gin.Context) {
 		// Use search functionality
 		searchPattern := "%" + req.Search + "%"
 
-		log.Printf("DEBUG: Searching skills with pattern: %s", searchPattern)
+		slog.Debug("Searching skills", "search_pattern", searchPattern)
 
 		searchArg := db.SearchSkillsByStatusParams{
 			IsVerified: *req.Verified,
@@ -492,7 +835,7 @@ func (server *Server) listSkillsAdmin(ctx *gin.Context) {
 
 		skills, err = server.store.SearchSkillsByStatus(ctx, searchArg)
 		if err != nil {
-			log.Printf("DEBUG: Error searching skills by status: %v", err)
+			slog.Debug("Error searching skills by status", "error", err)
 			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 			return
 		}
@@ -505,7 +848,7 @@ func (server *Server) listSkillsAdmin(ctx *gin.Context) {
 
 		totalCount, err = server.store.CountSearchSkillsByStatus(ctx, countArg)
 		if err != nil {
-			log.Printf("DEBUG: Error counting search skills by status: %v", err)
+			slog.Debug("Error counting search skills by status", "error", err)
 			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 			return
 		}
@@ -517,25 +860,24 @@ func (server *Server) listSkillsAdmin(ctx *gin.Context) {
 			Offset:     (req.PageID - 1) * req.PageSize,
 		}
 
-		log.Printf("DEBUG: Querying skills with verification status: %v, limit: %d, offset: %d", 
-			listArg.IsVerified, listArg.Limit, listArg.Offset)
+		slog.Debug("Querying skills", "is_verified", listArg.IsVerified, "limit", listArg.Limit, "offset", listArg.Offset)
 
 		skills, err = server.store.ListSkillsByStatus(ctx, listArg)
 		if err != nil {
-			log.Printf("DEBUG: Error listing skills by status: %v", err)
+			slog.Debug("Error listing skills by status", "error", err)
 			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 			return
 		}
 
 		totalCount, err = server.store.CountSkillsByStatus(ctx, *req.Verified)
 		if err != nil {
-			log.Printf("DEBUG: Error counting skills by status: %v", err)
+			slog.Debug("Error counting skills by status", "error", err)
 			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 			return
 		}
 	}
 
-	log.Printf("DEBUG: Successfully retrieved %d skills, total count: %d", len(skills), totalCount)
+	slog.Debug("Successfully retrieved skills", "count", len(skills), "total_count", totalCount)
 
 	rsp := paginatedResponse[db.Skill]{
 		TotalCount: totalCount,
@@ -556,80 +898,248 @@ type updateSkillBody struct {
 
 // updateSkillVerification handles updating skill verification status
 func (server *Server) updateSkillVerification(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting updateSkillVerification handler")
+	slog.Debug("Starting updateSkillVerification handler")
 
 	var uriReq updateSkillRequest
 	if err := ctx.ShouldBindUri(&uriReq); err != nil {
-		log.Printf("DEBUG: Update skill URI bind error: %v", err)
+		slog.Debug("Update skill URI bind error", "error", err)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
 	var bodyReq updateSkillBody
 	if err := ctx.ShouldBindJSON(&bodyReq); err != nil {
-		log.Printf("DEBUG: Update skill JSON bind error: %v", err)
+		slog.Debug("Update skill JSON bind error", "error", err)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	slog.Debug("Updating skill verification", "skill_id", uriReq.ID, "is_verified", bodyReq.IsVerified)
+
+	arg := db.UpdateSkillVerificationParams{
+		ID:         uriReq.ID,
+		IsVerified: bodyReq.IsVerified,
+	}
+
+	skill, err := server.store.UpdateSkillVerification(ctx, arg)
+	if err != nil {
+		slog.Debug("Error updating skill verification", "error", err)
+
+		if err == sql.ErrNoRows {
+			slog.Debug("Skill not found for verification update")
+			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("skill not found")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	slog.Debug("Successfully updated skill verification", "skill_id", skill.ID)
+	ctx.JSON(http.StatusOK, skill)
+}
+
+////////////////////////////////////////////////////////////////////////
+
+type deleteSkillRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// deleteSkill handles removing skills from the system
+func (server *Server) deleteSkill(ctx *gin.Context) {
+	slog.Debug("Starting deleteSkill handler")
+
+	var req deleteSkillRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		slog.Debug("Delete skill URI bind error", "error", err)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	slog.Debug("Deleting skill", "skill_id", req.ID)
+
+	err := server.store.DeleteSkill(ctx, req.ID)
+	if err != nil {
+		slog.Debug("Error deleting skill", "error", err)
+
+		if err == sql.ErrNoRows {
+			slog.Debug("Skill not found for deletion")
+			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("skill not found")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	slog.Debug("Successfully deleted skill", "skill_id", req.ID)
+	ctx.Status(http.StatusNoContent)
+}
+
+////////////////////////////////////////////////////////////////////////
+
+type mergeSkillRequest struct {
+	TargetSkillID int64 `json:"target_skill_id" binding:"required,min=1"`
+}
+
+// mergeSkill folds a duplicate skill (the one in the URL) into the target skill and returns
+// how many users, tasks and aliases were repointed.
+func (server *Server) mergeSkill(ctx *gin.Context) {
+	slog.Debug("Starting mergeSkill handler")
+
+	var uriReq struct {
+		ID int64 `uri:"id" binding:"required,min=1"`
+	}
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req mergeSkillRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Updating skill verification - ID: %d, IsVerified: %v", uriReq.ID, bodyReq.IsVerified)
+	result, err := server.store.MergeSkillsTx(ctx, db.MergeSkillsTxParams{
+		SourceID: uriReq.ID,
+		TargetID: req.TargetSkillID,
+	})
+	if err != nil {
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, db.ErrMergeSameSkill):
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		case errors.Is(err, db.ErrSkillNotFound):
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		default:
+			slog.Error("Failed to merge skill", "skill_id", uriReq.ID, "target_skill_id", req.TargetSkillID, "error", err)
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	slog.Info("Merged skill", "skill_id", uriReq.ID, "target_skill_id", req.TargetSkillID, "result", result)
+
+	// The merge is committed at this point, so a failed refresh is only logged
+	if err := server.reloadSkillAliases(ctx); err != nil {
+		slog.Error("Failed to refresh skill alias map after skill merge", "error", err)
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+////////////////////////////////////////////////////////////////////////
+
+// Fallback thresholds for auto-verifying skills when none are configured
+const (
+	defaultSkillAutoVerifyMinUsers int64 = 5
+	defaultSkillAutoVerifyMinTasks int64 = 10
+)
+
+type autoVerifySkillsRequest struct {
+	MinUsers int64 `json:"min_users" binding:"omitempty,min=1"` // Overrides SKILL_AUTO_VERIFY_MIN_USERS for this run
+	MinTasks int64 `json:"min_tasks" binding:"omitempty,min=1"` // Overrides SKILL_AUTO_VERIFY_MIN_TASKS for this run
+}
+
+// autoVerifySkills verifies every unverified skill that is held by enough users or required by enough tasks.
+// Thresholds come from the config and can be overridden per run in the optional JSON body.
+func (server *Server) autoVerifySkills(ctx *gin.Context) {
+	slog.Debug("Starting autoVerifySkills handler")
+
+	var req autoVerifySkillsRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	arg := db.AutoVerifySkillsByUsageParams{
+		MinUsers: cmp.Or(req.MinUsers, server.config.SkillAutoVerifyMinUsers, defaultSkillAutoVerifyMinUsers),
+		MinTasks: cmp.Or(req.MinTasks, server.config.SkillAutoVerifyMinTasks, defaultSkillAutoVerifyMinTasks),
+	}
+
+	skills, err := server.store.AutoVerifySkillsByUsage(ctx, arg)
+	if err != nil {
+		slog.Error("Failed to auto-verify skills", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// There is no audit table yet, so each verification is recorded in the server log
+	for _, skill := range skills {
+		slog.Info("Auto-verified skill", "skill_id", skill.ID, "skill_name", skill.SkillName, "min_users", arg.MinUsers, "min_tasks", arg.MinTasks)
+	}
+
+	if skills == nil {
+		skills = []db.Skill{}
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"verified_skills": skills,
+		"min_users":       arg.MinUsers,
+		"min_tasks":       arg.MinTasks,
+	})
+}
+
+////////////////////////////////////////////////////////////////////////
 
-	arg := db.UpdateSkillVerificationParams{
-		ID:         uriReq.ID,
-		IsVerified: bodyReq.IsVerified,
-	}
+// getPendingSkillCount returns how many skills are waiting for an admin to verify them
+func (server *Server) getPendingSkillCount(ctx *gin.Context) {
+	slog.Debug("Starting getPendingSkillCount handler")
 
-	skill, err := server.store.UpdateSkillVerification(ctx, arg)
+	count, err := server.store.CountSkillsByStatus(ctx, false)
 	if err != nil {
-		log.Printf("DEBUG: Error updating skill verification: %v", err)
-
-		if err == sql.ErrNoRows {
-			log.Printf("DEBUG: Skill not found for verification update")
-			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("skill not found")))
-			return
-		}
+		slog.Debug("Error counting unverified skills", "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Successfully updated skill verification for ID: %d", skill.ID)
-	ctx.JSON(http.StatusOK, skill)
+	ctx.JSON(http.StatusOK, gin.H{"pending_count": count})
 }
 
 ////////////////////////////////////////////////////////////////////////
 
-type deleteSkillRequest struct {
-	ID int64 `uri:"id" binding:"required,min=1"`
+type listRankedUnverifiedSkillsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
 }
 
-// deleteSkill handles removing skills from the system
-func (server *Server) deleteSkill(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting deleteSkill handler")
+// listRankedUnverifiedSkills lists unverified skills with the most used first, so admins
+// can verify the ones that affect the most users and tasks before the rest
+func (server *Server) listRankedUnverifiedSkills(ctx *gin.Context) {
+	slog.Debug("Starting listRankedUnverifiedSkills handler")
 
-	var req deleteSkillRequest
-	if err := ctx.ShouldBindUri(&req); err != nil {
-		log.Printf("DEBUG: Delete skill URI bind error: %v", err)
+	var req listRankedUnverifiedSkillsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		slog.Debug("Ranked unverified skills query bind error", "error", err)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Deleting skill with ID: %d", req.ID)
-
-	err := server.store.DeleteSkill(ctx, req.ID)
+	skills, err := server.store.ListMostUsedUnverifiedSkills(ctx, db.ListMostUsedUnverifiedSkillsParams{
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
 	if err != nil {
-		log.Printf("DEBUG: Error deleting skill: %v", err)
+		slog.Debug("Error listing most used unverified skills", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
 
-		if err == sql.ErrNoRows {
-			log.Printf("DEBUG: Skill not found for deletion")
-			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("skill not found")))
-			return
-		}
+	totalCount, err := server.store.CountSkillsByStatus(ctx, false)
+	if err != nil {
+		slog.Debug("Error counting unverified skills", "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Successfully deleted skill with ID: %d", req.ID)
-	ctx.Status(http.StatusNoContent)
+	slog.Debug("Successfully retrieved ranked unverified skills", "count", len(skills), "total_count", totalCount)
+
+	ctx.JSON(http.StatusOK, paginatedResponse[db.ListMostUsedUnverifiedSkillsRow]{
+		TotalCount: totalCount,
+		Data:       skills,
+	})
 }
 
 ////////////////////////////////////////////////////////////////////////
@@ -641,20 +1151,20 @@ type createSkillAliasRequest struct {
 
 // createSkillAlias handles creating alternative names for skills
 func (server *Server) createSkillAlias(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting createSkillAlias handler")
+	slog.Debug("Starting createSkillAlias handler")
 
 	var req createSkillAliasRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		log.Printf("DEBUG: Create skill alias JSON bind error: %v", err)
+		slog.Debug("Create skill alias JSON bind error", "error", err)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Creating skill alias - AliasName: %s, SkillID: %d", req.AliasName, req.SkillID)
+	slog.Debug("Creating skill alias", "alias_name", req.AliasName, "skill_id", req.SkillID)
 
 	// Convert alias name to lowercase for consistency
 	normalizedAliasName := strings.ToLower(req.AliasName)
-	log.Printf("DEBUG: Normalized alias name: %s", normalizedAliasName)
+	slog.Debug("Normalized alias name", "alias_name", normalizedAliasName)
 
 	arg := db.CreateSkillAliasParams{
 		AliasName: normalizedAliasName,
@@ -663,17 +1173,101 @@ func (server *Server) createSkillAlias(ctx *gin.Context) {
 
 	alias, err := server.store.CreateSkillAlias(ctx, arg)
 	if err != nil {
-		log.Printf("DEBUG: Error creating skill alias: %v", err)
+		slog.Debug("Error creating skill alias", "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Successfully created skill alias with ID: %d", alias.SkillID)
+	slog.Debug("Successfully created skill alias", "skill_id", alias.SkillID)
+
+	// The alias is committed at this point, so a failed refresh is only logged
+	if err := server.reloadSkillAliases(ctx); err != nil {
+		slog.Error("Failed to refresh skill alias map after creating alias", "error", err)
+	}
+
 	ctx.JSON(http.StatusCreated, alias)
 }
 
 ////////////////////////////////////////////////////////////////////////
 
+// maxBulkSkillAliases caps how many aliases a single bulk import may carry
+const maxBulkSkillAliases = 500
+
+// bulkSkillAliasItem names the target skill either by ID or by canonical name
+type bulkSkillAliasItem struct {
+	AliasName string `json:"alias_name" binding:"required"`
+	SkillID   int64  `json:"skill_id" binding:"required_without=SkillName,omitempty,min=1"`
+	SkillName string `json:"skill_name" binding:"required_without=SkillID"`
+}
+
+// bulkCreateSkillAliases imports many aliases in one transaction, reporting duplicates per row
+func (server *Server) bulkCreateSkillAliases(ctx *gin.Context) {
+	slog.Debug("Starting bulkCreateSkillAliases handler")
+
+	var req []bulkSkillAliasItem
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		slog.Debug("Bulk skill alias JSON bind error", "error", err)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if len(req) == 0 || len(req) > maxBulkSkillAliases {
+		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("between 1 and %d aliases are required", maxBulkSkillAliases)))
+		return
+	}
+
+	// Normalize alias names the same way createSkillAlias does
+	aliases := make([]db.BulkSkillAlias, len(req))
+	for i, item := range req {
+		aliases[i] = db.BulkSkillAlias{
+			AliasName: strings.ToLower(strings.TrimSpace(item.AliasName)),
+			SkillID:   item.SkillID,
+			SkillName: strings.TrimSpace(item.SkillName),
+		}
+	}
+
+	result, err := server.store.BulkCreateSkillAliasesTx(ctx, db.BulkCreateSkillAliasesTxParams{Aliases: aliases})
+	if err != nil {
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
+		slog.Error("Bulk skill alias import failed", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	slog.Debug("Bulk imported skill aliases", "created", result.Created, "requested", len(aliases))
+
+	// The import is committed at this point, so a failed refresh is only logged
+	if result.Created > 0 {
+		if err := server.reloadSkillAliases(ctx); err != nil {
+			slog.Error("Failed to refresh skill alias map after bulk import", "error", err)
+		}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"created": result.Created,
+		"results": result.Results,
+	})
+}
+
+// reloadSkillAliases rebuilds the skill processor's alias map from the database,
+// if the processor keeps one in memory.
+func (server *Server) reloadSkillAliases(ctx context.Context) error {
+	reloader, ok := server.skillzProcessor.(skillz.AliasReloader)
+	if !ok {
+		return nil
+	}
+
+	count, err := reloader.ReloadAliases(ctx)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Reloaded skill aliases", "count", count)
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////
+
 // New handler for listing skill aliases
 type listSkillAliasesRequest struct {
 	ID int64 `uri:"id" binding:"required,min=1"`
@@ -681,26 +1275,26 @@ type listSkillAliasesRequest struct {
 
 // listSkillAliases handles retrieving all aliases for a specific skill
 func (server *Server) listSkillAliases(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting listSkillAliases handler")
+	slog.Debug("Starting listSkillAliases handler")
 
 	var req listSkillAliasesRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		log.Printf("DEBUG: List skill aliases URI bind error: %v", err)
+		slog.Debug("List skill aliases URI bind error", "error", err)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Listing aliases for skill ID: %d", req.ID)
+	slog.Debug("Listing aliases for skill", "skill_id", req.ID)
 
 	// First, verify that the skill exists
 	skill, err := server.store.GetSkill(ctx, req.ID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			log.Printf("DEBUG: Skill not found for aliases listing")
+			slog.Debug("Skill not found for aliases listing")
 			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("skill not found")))
 			return
 		}
-		log.Printf("DEBUG: Error checking skill existence: %v", err)
+		slog.Debug("Error checking skill existence", "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
@@ -708,12 +1302,12 @@ func (server *Server) listSkillAliases(ctx *gin.Context) {
 	// Get all aliases for this skill
 	aliases, err := server.store.ListAliasesForSkill(ctx, req.ID)
 	if err != nil {
-		log.Printf("DEBUG: Error listing aliases for skill: %v", err)
+		slog.Debug("Error listing aliases for skill", "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Successfully retrieved %d aliases for skill '%s'", len(aliases), skill.SkillName)
+	slog.Debug("Successfully retrieved aliases for skill", "count", len(aliases), "skill_name", skill.SkillName)
 
 	// Return both skill info and its aliases
 	response := gin.H{
@@ -726,28 +1320,121 @@ func (server *Server) listSkillAliases(ctx *gin.Context) {
 
 ////////////////////////////////////////////////////////////////////////
 
+type listAllSkillAliasesRequest struct {
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=100"`
+	Search   string `form:"search" binding:"max=100"` // Part of an alias or of its skill's name
+}
+
+// listAllSkillAliases pages through every alias with its canonical skill name, for auditing
+func (server *Server) listAllSkillAliases(ctx *gin.Context) {
+	slog.Debug("Starting listAllSkillAliases handler")
+
+	var req listAllSkillAliasesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		slog.Debug("List all skill aliases query bind error", "error", err)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// Wildcards in the search are escaped, so "c_" looks for those two characters
+	searchPattern := "%" + util.EscapeLike(req.Search) + "%"
+
+	aliases, err := server.store.ListAllSkillAliases(ctx, db.ListAllSkillAliasesParams{
+		Search: searchPattern,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		slog.Debug("Error listing all skill aliases", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	totalCount, err := server.store.CountAllSkillAliases(ctx, searchPattern)
+	if err != nil {
+		slog.Debug("Error counting all skill aliases", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	slog.Debug("Successfully retrieved skill aliases", "count", len(aliases), "total_count", totalCount)
+
+	ctx.JSON(http.StatusOK, paginatedResponse[db.ListAllSkillAliasesRow]{
+		TotalCount: totalCount,
+		Data:       aliases,
+	})
+}
+
+////////////////////////////////////////////////////////////////////////
+
+type deleteSkillAliasRequest struct {
+	// A catch-all segment, since alias names such as "ci/cd" can contain slashes
+	AliasName string `uri:"alias_name" binding:"required"`
+}
+
+// deleteSkillAlias removes a bad alias and drops it from the skill processor's alias map
+func (server *Server) deleteSkillAlias(ctx *gin.Context) {
+	slog.Debug("Starting deleteSkillAlias handler")
+
+	var req deleteSkillAliasRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		slog.Debug("Delete skill alias URI bind error", "error", err)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	aliasName := strings.TrimPrefix(req.AliasName, "/")
+	if aliasName == "" {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("alias name is required")))
+		return
+	}
+
+	deleted, err := server.store.DeleteSkillAlias(ctx, aliasName)
+	if err != nil {
+		slog.Debug("Error deleting skill alias", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if deleted == 0 {
+		ctx.JSON(http.StatusNotFound, errorResponse(errors.New("skill alias not found")))
+		return
+	}
+
+	slog.Info("Deleted skill alias", "alias_name", aliasName)
+
+	// The deletion is committed at this point, so a failed refresh is only logged
+	if err := server.reloadSkillAliases(ctx); err != nil {
+		slog.Error("Failed to refresh skill alias map after deleting alias", "error", err)
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+////////////////////////////////////////////////////////////////////////
+
 type createSkillAdminRequest struct {
 	SkillName string `json:"skill_name" binding:"required,min=1,max=100"`
 }
 
 // Allows admins to manually create new verified skills directly in the system.
 func (server *Server) createSkillAdmin(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting createSkillAdmin handler")
+	slog.Debug("Starting createSkillAdmin handler")
 
 	var req createSkillAdminRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		log.Printf("DEBUG: Create skill admin JSON bind error: %v", err)
+		slog.Debug("Create skill admin JSON bind error", "error", err)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Creating verified skill with name: '%s'", req.SkillName)
+	slog.Debug("Creating verified skill", "skill_name", req.SkillName)
 
 	// Normalize skill name (trim whitespace, convert to lowercase for consistency)
 	normalizedSkillName := strings.TrimSpace(strings.ToLower(req.SkillName))
 
 	if normalizedSkillName == "" {
-		log.Printf("DEBUG: Empty skill name after normalization")
+		slog.Debug("Empty skill name after normalization")
 		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("skill name cannot be empty")))
 		return
 	}
@@ -756,7 +1443,7 @@ func (server *Server) createSkillAdmin(ctx *gin.Context) {
 	existingSkill, err := server.store.GetSkillByName(ctx, normalizedSkillName)
 	if err == nil {
 		// Skill already exists
-		log.Printf("DEBUG: Skill already exists with ID: %d, verified: %v", existingSkill.ID, existingSkill.IsVerified)
+		slog.Debug("Skill already exists", "skill_id", existingSkill.ID, "is_verified", existingSkill.IsVerified)
 
 		if existingSkill.IsVerified {
 			// Already verified - return conflict
@@ -764,30 +1451,30 @@ func (server *Server) createSkillAdmin(ctx *gin.Context) {
 			return
 		} else {
 			// Exists but unverified - update to verified instead of creating duplicate
-			log.Printf("DEBUG: Updating existing unverified skill to verified")
+			slog.Debug("Updating existing unverified skill to verified")
 			updatedSkill, updateErr := server.store.UpdateSkillVerification(ctx, db.UpdateSkillVerificationParams{
 				ID:         existingSkill.ID,
 				IsVerified: true,
 			})
 			if updateErr != nil {
-				log.Printf("DEBUG: Error updating skill verification: %v", updateErr)
+				slog.Debug("Error updating skill verification", "error", updateErr)
 				ctx.JSON(http.StatusInternalServerError, errorResponse(updateErr))
 				return
 			}
 
-			log.Printf("DEBUG: Successfully updated skill to verified with ID: %d", updatedSkill.ID)
+			slog.Debug("Successfully updated skill to verified", "skill_id", updatedSkill.ID)
 			ctx.JSON(http.StatusOK, updatedSkill) // 200 OK for update
 			return
 		}
 	} else if err != sql.ErrNoRows && err != pgx.ErrNoRows {
 		// Database error (not "not found")
-		log.Printf("DEBUG: Error checking for existing skill: %v", err)
+		slog.Debug("Error checking for existing skill", "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
 	// If we reach here, the skill doesn't exist - proceed with creation
-	log.Printf("DEBUG: Skill doesn't exist, proceeding with creation")
+	slog.Debug("Skill doesn't exist, proceeding with creation")
 
 	// Skill doesn't exist - create new verified skill
 	arg := db.CreateSkillParams{
@@ -797,7 +1484,7 @@ func (server *Server) createSkillAdmin(ctx *gin.Context) {
 
 	skill, err := server.store.CreateSkill(ctx, arg)
 	if err != nil {
-		log.Printf("DEBUG: Error creating skill: %v", err)
+		slog.Debug("Error creating skill", "error", err)
 
 		// Handle potential duplicate constraint violations at DB level
 		if strings.Contains(err.Error(), "duplicate") || strings.Contains(err.Error(), "unique") {
@@ -809,7 +1496,7 @@ func (server *Server) createSkillAdmin(ctx *gin.Context) {
 		return
 	}
 
-	log.Printf("DEBUG: Successfully created verified skill with ID: %d", skill.ID)
+	slog.Debug("Successfully created verified skill", "skill_id", skill.ID)
 	ctx.JSON(http.StatusCreated, skill)
 
 }
@@ -819,14 +1506,16 @@ func (server *Server) createSkillAdmin(ctx *gin.Context) {
 
 // Request struct for listing users with pagination and filtering
 type listUsersAdminRequest struct {
-	PageID   int32  `form:"page_id" binding:"required,min=1"`               // Page number (1-based)
-	PageSize int32  `form:"page_size" binding:"required,min=1,max=100"`     // Items per page
-	Search   string `form:"search"`                                         // Optional search term
-	Role     string `form:"role"`                                           // Optional role filter
+	PageID   int32  `form:"page_id" binding:"required,min=1"`                      // Page number (1-based)
+	PageSize int32  `form:"page_size" binding:"required,min=1,max=100"`            // Items per page
+	Search   string `form:"search"`                                                // Optional search term
+	Role     string `form:"role" binding:"omitempty,oneof=admin manager engineer"` // Optional role filter
+	TeamID   int64  `form:"team_id" binding:"omitempty,min=1"`                     // Optional team filter
+	HasTeam  *bool  `form:"has_team"`                                              // Optional filter for users with (true) or without (false) a team
 }
 
 // GET /admin/users - List and search users with pagination
-// Supports searching by name/email and filtering by role
+// Supports searching by name/email and filtering by role, team and team membership
 func (server *Server) listUsersAdmin(ctx *gin.Context) {
 	var req listUsersAdminRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
@@ -834,26 +1523,25 @@ func (server *Server) listUsersAdmin(ctx *gin.Context) {
 		return
 	}
 
-	// Validate and prepare role filter
-	roleFilterStr := ""
-	if req.Role != "" {
-		switch req.Role {
-		case "admin", "manager", "engineer":
-			roleFilterStr = req.Role
-		default:
-			ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("invalid role filter")))
-			return
-		}
+	// A team filter only makes sense for users that have a team
+	if req.TeamID != 0 && req.HasTeam != nil && !*req.HasTeam {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("team_id cannot be combined with has_team=false")))
+		return
 	}
 
-	// Prepare search pattern (empty string means no search filter)
-	searchPattern := req.Search
+	// Unset filters are passed as NULL so the query skips them
+	roleFilter := db.NullUserRole{UserRole: db.UserRole(req.Role), Valid: req.Role != ""}
+	teamFilter := pgtype.Int8{Int64: req.TeamID, Valid: req.TeamID != 0}
+	var hasTeamFilter pgtype.Bool
+	if req.HasTeam != nil {
+		hasTeamFilter = pgtype.Bool{Bool: *req.HasTeam, Valid: true}
+	}
 
-	// Use SearchUsers function which handles both search and role filtering efficiently
-	// Empty strings are handled by SQL logic: $1::text = '' OR ... AND $2::text = '' OR ...
 	users, err := server.store.SearchUsers(ctx, db.SearchUsersParams{
-		Column1: searchPattern,  // Search pattern for name/email
-		Column2: roleFilterStr,  // Role filter string
+		Search:  req.Search, // Search pattern for name/email, empty means no search filter
+		Role:    roleFilter,
+		TeamID:  teamFilter,
+		HasTeam: hasTeamFilter,
 		Limit:   req.PageSize,
 		Offset:  (req.PageID - 1) * req.PageSize,
 	})
@@ -864,8 +1552,10 @@ func (server *Server) listUsersAdmin(ctx *gin.Context) {
 
 	// Get total count for pagination metadata
 	totalCount, err := server.store.CountSearchUsers(ctx, db.CountSearchUsersParams{
-		Column1: searchPattern,
-		Column2: roleFilterStr,
+		Search:  req.Search,
+		Role:    roleFilter,
+		TeamID:  teamFilter,
+		HasTeam: hasTeamFilter,
 	})
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
@@ -973,6 +1663,9 @@ func (server *Server) updateUserAdmin(ctx *gin.Context) {
 			TeamID:  req.TeamID,
 		})
 		if err != nil {
+			if abortIfTimedOut(ctx, err) {
+				return
+			}
 			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 			return
 		}
@@ -1019,6 +1712,10 @@ func (server *Server) updateUserAdmin(ctx *gin.Context) {
 	// Execute the user update
 	user, err := server.store.UpdateUser(ctx, updateParams)
 	if err != nil {
+		if db.IsTeamManagerConflict(err) {
+			ctx.JSON(http.StatusConflict, errorResponse(db.ErrTeamAlreadyHasManager))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
@@ -1053,6 +1750,9 @@ func (server *Server) getUserDeletionImpact(ctx *gin.Context) {
 		UserID: id,
 	})
 	if err != nil {
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("user not found")))
 			return
@@ -1127,6 +1827,9 @@ func (server *Server) deleteUserAdmin(ctx *gin.Context) {
 		UserID: id,
 	})
 	if err != nil {
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
 		// Handle business rule violations (e.g., trying to delete admin)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
@@ -1143,3 +1846,219 @@ func (server *Server) deleteUserAdmin(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, response)
 }
+
+////////////////////////////////////////////////////////////////////////
+
+// Request struct for moving an engineer to another team
+type transferUserRequest struct {
+	TeamID int64 `json:"team_id" binding:"required,min=1"`
+}
+
+// POST /admin/users/:id/transfer - Move an engineer to another team
+// Their unfinished tasks on the old team are reopened and unassigned; the response summarizes them
+func (server *Server) transferUserAdmin(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("invalid user ID")))
+		return
+	}
+
+	var req transferUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload, err := getAuthorizationPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.TransferUserToTeamTx(ctx, db.TransferUserToTeamTxParams{
+		UserID:    id,
+		NewTeamID: req.TeamID,
+		ActorID:   actorIDFromPayload(authPayload),
+	})
+	if err != nil {
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, db.ErrUserNotFound), errors.Is(err, db.ErrTeamNotFound):
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		case errors.Is(err, db.ErrTransferNotEngineer):
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		case errors.Is(err, db.ErrUserAlreadyOnTeam):
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+		default:
+			slog.Error("Failed to transfer user", "user_id", id, "team_id", req.TeamID, "error", err)
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	slog.Info("User transferred to team",
+		"user_id", id,
+		"previous_team_id", result.PreviousTeamID,
+		"team_id", req.TeamID,
+		"unassigned_tasks", len(result.UnassignedTasks),
+	)
+
+	// Team membership feeds recommendations, so earlier answers are stale
+	server.notifyRecommender()
+
+	// Summarize the move the same way the deletion impact does
+	tasks := make([]gin.H, len(result.UnassignedTasks))
+	for i, task := range result.UnassignedTasks {
+		tasks[i] = gin.H{
+			"id":       task.ID,
+			"title":    task.Title,
+			"status":   task.Status,
+			"priority": task.Priority,
+		}
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"user": gin.H{
+			"id":           result.User.ID,
+			"name":         result.User.Name,
+			"email":        result.User.Email,
+			"team_id":      result.User.TeamID,
+			"availability": result.User.Availability,
+		},
+		"previous_team_id": result.PreviousTeamID,
+		"unassigned_tasks": gin.H{
+			"count":   len(tasks),
+			"details": tasks,
+		},
+	})
+}
+
+////////////////////////////////////////////////////////////////////////
+
+// Request struct for handing an engineer's tasks to a teammate
+type reassignTasksRequest struct {
+	TargetUserID int64 `json:"target_user_id" binding:"required,min=1"`
+}
+
+// POST /admin/users/:id/reassign-tasks - Move all of an engineer's active tasks to a teammate
+// The target must be an available engineer on the same team; tasks keep their status
+func (server *Server) reassignTasksAdmin(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("invalid user ID")))
+		return
+	}
+
+	var req reassignTasksRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload, err := getAuthorizationPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.ReassignAllTasksTx(ctx, db.ReassignAllTasksTxParams{
+		FromUserID: id,
+		ToUserID:   req.TargetUserID,
+		ActorID:    actorIDFromPayload(authPayload),
+	})
+	if err != nil {
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, db.ErrUserNotFound):
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		case errors.Is(err, db.ErrReassignSameEngineer), errors.Is(err, db.ErrReassignNotEngineer), errors.Is(err, db.ErrReassignDifferentTeam):
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		case errors.Is(err, db.ErrEngineerNotAvailable), errors.Is(err, db.ErrEngineerOnLeave):
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+		default:
+			slog.Error("Failed to reassign tasks", "user_id", id, "target_user_id", req.TargetUserID, "error", err)
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	slog.Info("Tasks reassigned",
+		"user_id", id,
+		"target_user_id", req.TargetUserID,
+		"moved_tasks", len(result.Tasks),
+	)
+
+	// Summarize the move the same way a transfer does
+	tasks := make([]gin.H, len(result.Tasks))
+	for i, task := range result.Tasks {
+		tasks[i] = gin.H{
+			"id":       task.ID,
+			"title":    task.Title,
+			"status":   task.Status,
+			"priority": task.Priority,
+		}
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"from_user": gin.H{
+			"id":           result.FromUser.ID,
+			"name":         result.FromUser.Name,
+			"availability": result.FromUser.Availability,
+		},
+		"to_user": gin.H{
+			"id":           result.ToUser.ID,
+			"name":         result.ToUser.Name,
+			"availability": result.ToUser.Availability,
+		},
+		"moved_tasks": gin.H{
+			"count":   len(tasks),
+			"details": tasks,
+		},
+	})
+}
+
+////////////////////////////////////////////////////////////////////////
+
+// POST /admin/users/:id/skills - Add an existing skill to a user's profile
+func (server *Server) addUserSkillAdmin(ctx *gin.Context) {
+	if userID, ok := server.adminSkillTarget(ctx); ok {
+		server.addSkillForUser(ctx, userID)
+	}
+}
+
+// PATCH /admin/users/:id/skills/:skill_id - Correct a user's proficiency in a skill
+func (server *Server) updateUserSkillAdmin(ctx *gin.Context) {
+	if userID, ok := server.adminSkillTarget(ctx); ok {
+		server.updateSkillForUser(ctx, userID)
+	}
+}
+
+// DELETE /admin/users/:id/skills/:skill_id - Remove a skill from a user's profile
+func (server *Server) removeUserSkillAdmin(ctx *gin.Context) {
+	if userID, ok := server.adminSkillTarget(ctx); ok {
+		server.removeSkillForUser(ctx, userID)
+	}
+}
+
+// adminSkillTarget resolves the user in the route, writing an error response when it is invalid or missing
+func (server *Server) adminSkillTarget(ctx *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("invalid user ID")))
+		return 0, false
+	}
+
+	if _, err := server.store.GetUser(ctx, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("user not found")))
+			return 0, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return 0, false
+	}
+	return id, true
+}
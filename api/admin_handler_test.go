@@ -0,0 +1,571 @@
+// api/admin_handler_test.go
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pranav244872/synapse/db/sqlc"
+	"github.com/pranav244872/synapse/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpireInvitations(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	server := newTestServer(t, store)
+
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	manager := createTestUser(t, store, db.UserRoleManager, team.ID)
+	admin := createTestUser(t, store, db.UserRoleAdmin, 0)
+
+	created, err := store.CreateInvitationTx(ctx, db.CreateInvitationTxParams{
+		InviterID:     manager.ID,
+		EmailToInvite: util.RandomEmail(),
+		RoleToInvite:  db.UserRoleEngineer,
+	})
+	require.NoError(t, err)
+	_, err = store.RefreshInvitationToken(ctx, db.RefreshInvitationTokenParams{
+		ID:              created.Invitation.ID,
+		InvitationToken: util.RandomString(32),
+		ExpiresAt:       created.Invitation.CreatedAt,
+	})
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/api/v1/admin/invitations/expire", nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server, admin.ID, db.UserRoleAdmin, 0)
+
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var rsp struct {
+		ExpiredCount int64 `json:"expired_count"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.GreaterOrEqual(t, rsp.ExpiredCount, int64(1))
+
+	invitation, err := store.GetInvitationByID(ctx, created.Invitation.ID)
+	require.NoError(t, err)
+	require.Equal(t, "expired", invitation.Status)
+}
+
+func TestRunInvitationExpiryStops(t *testing.T) {
+	// A database failure is logged, and the loop returns once its context is done
+	server := newTestServer(t, newUnreachableStore(t))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		server.runInvitationExpiry(ctx, time.Millisecond)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("invitation expiry loop did not stop")
+	}
+}
+
+func TestEscalateStaleTasks(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	server := newTestServer(t, store)
+
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	admin := createTestUser(t, store, db.UserRoleAdmin, 0)
+	project, err := store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: team.ID})
+	require.NoError(t, err)
+	task, err := store.CreateTask(ctx, db.CreateTaskParams{
+		ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+		Title:     util.RandomName(),
+		Status:    db.TaskStatusOpen,
+		Priority:  db.TaskPriorityLow,
+		DueDate:   pgtype.Timestamp{Time: time.Now().UTC().Add(-24 * time.Hour), Valid: true},
+	})
+	require.NoError(t, err)
+
+	escalate := func(userID int64, role db.UserRole) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/api/v1/admin/tasks/escalate", nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server, userID, role, 0)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// Only admins can trigger it
+	require.Equal(t, http.StatusForbidden, escalate(admin.ID, db.UserRoleManager).Code)
+
+	// The overdue task goes straight to high, and the admin is recorded as the actor
+	recorder := escalate(admin.ID, db.UserRoleAdmin)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var rsp struct {
+		EscalatedCount int                        `json:"escalated_count"`
+		Tasks          []db.EscalateStaleTasksRow `json:"tasks"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.GreaterOrEqual(t, rsp.EscalatedCount, 1)
+	require.Len(t, rsp.Tasks, rsp.EscalatedCount)
+
+	updated, err := store.GetTask(ctx, task.ID)
+	require.NoError(t, err)
+	require.Equal(t, db.TaskPriorityHigh, updated.Priority)
+
+	activity, err := store.ListTaskActivity(ctx, task.ID)
+	require.NoError(t, err)
+	require.Equal(t, db.TaskActivityEventPriorityEscalated, activity[0].Event)
+	require.Equal(t, admin.ID, activity[0].ActorID.Int64)
+}
+
+func TestRunTaskEscalationStops(t *testing.T) {
+	// A database failure is logged, and the loop returns once its context is done
+	server := newTestServer(t, newUnreachableStore(t))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		server.runTaskEscalation(ctx, time.Millisecond)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("task escalation loop did not stop")
+	}
+}
+
+func TestTransferUserValidation(t *testing.T) {
+	server := newTestServer(t, newUnreachableStore(t))
+
+	recorder := newAuthorizedRequest(t, server, http.MethodPost, "/api/v1/admin/users/abc/transfer", gin.H{"team_id": 1}, 1, db.UserRoleAdmin, 0)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = newAuthorizedRequest(t, server, http.MethodPost, "/api/v1/admin/users/1/transfer", gin.H{}, 1, db.UserRoleAdmin, 0)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestTransferUser(t *testing.T) {
+	store := newTestStore(t)
+	server := newTestServer(t, store)
+	ctx := context.Background()
+
+	oldTeam, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	newTeam, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	project, err := store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: oldTeam.ID})
+	require.NoError(t, err)
+
+	admin := createTestUser(t, store, db.UserRoleAdmin, 0)
+	manager := createTestUser(t, store, db.UserRoleManager, oldTeam.ID)
+	engineer := createTestUser(t, store, db.UserRoleEngineer, oldTeam.ID)
+	engineerID := strconv.FormatInt(engineer.ID, 10)
+
+	// The engineer has a task in progress, a paused one and a finished one
+	var tasks []db.Task
+	for range 3 {
+		task, err := store.CreateTask(ctx, db.CreateTaskParams{
+			ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+			Title:     util.RandomName(),
+			Status:    db.TaskStatusOpen,
+			Priority:  db.TaskPriorityMedium,
+		})
+		require.NoError(t, err)
+		_, err = store.AssignTaskToUser(ctx, db.AssignTaskToUserTxParams{TaskID: task.ID, UserID: engineer.ID})
+		require.NoError(t, err)
+		tasks = append(tasks, task)
+	}
+	_, err = store.PauseTaskTx(ctx, db.PauseTaskTxParams{TaskID: tasks[1].ID, EngineerID: engineer.ID})
+	require.NoError(t, err)
+	_, err = store.CompleteTaskTx(ctx, db.CompleteTaskTxParams{TaskID: tasks[2].ID})
+	require.NoError(t, err)
+
+	// Only engineers move, and only to teams that exist
+	recorder := newAuthorizedRequest(t, server, http.MethodPost, "/api/v1/admin/users/"+strconv.FormatInt(manager.ID, 10)+"/transfer", gin.H{"team_id": newTeam.ID}, admin.ID, db.UserRoleAdmin, 0)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	recorder = newAuthorizedRequest(t, server, http.MethodPost, "/api/v1/admin/users/"+engineerID+"/transfer", gin.H{"team_id": math.MaxInt32}, admin.ID, db.UserRoleAdmin, 0)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+
+	// The move hands the unfinished tasks back to the old team
+	recorder = newAuthorizedRequest(t, server, http.MethodPost, "/api/v1/admin/users/"+engineerID+"/transfer", gin.H{"team_id": newTeam.ID}, admin.ID, db.UserRoleAdmin, 0)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var rsp struct {
+		User struct {
+			TeamID       int64                 `json:"team_id"`
+			Availability db.AvailabilityStatus `json:"availability"`
+		} `json:"user"`
+		PreviousTeamID  int64 `json:"previous_team_id"`
+		UnassignedTasks struct {
+			Count int `json:"count"`
+		} `json:"unassigned_tasks"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Equal(t, newTeam.ID, rsp.User.TeamID)
+	require.Equal(t, db.AvailabilityStatusAvailable, rsp.User.Availability)
+	require.Equal(t, oldTeam.ID, rsp.PreviousTeamID)
+	require.Equal(t, 2, rsp.UnassignedTasks.Count)
+
+	for _, task := range tasks[:2] {
+		task, err := store.GetTask(ctx, task.ID)
+		require.NoError(t, err)
+		require.Equal(t, db.TaskStatusOpen, task.Status)
+		require.False(t, task.AssigneeID.Valid)
+	}
+	done, err := store.GetTask(ctx, tasks[2].ID)
+	require.NoError(t, err)
+	require.Equal(t, engineer.ID, done.AssigneeID.Int64)
+
+	// Moving to the team they are already on is a conflict
+	recorder = newAuthorizedRequest(t, server, http.MethodPost, "/api/v1/admin/users/"+engineerID+"/transfer", gin.H{"team_id": newTeam.ID}, admin.ID, db.UserRoleAdmin, 0)
+	require.Equal(t, http.StatusConflict, recorder.Code)
+}
+
+func TestReassignTasksValidation(t *testing.T) {
+	server := newTestServer(t, newUnreachableStore(t))
+
+	recorder := newAuthorizedRequest(t, server, http.MethodPost, "/api/v1/admin/users/abc/reassign-tasks", gin.H{"target_user_id": 2}, 1, db.UserRoleAdmin, 0)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = newAuthorizedRequest(t, server, http.MethodPost, "/api/v1/admin/users/1/reassign-tasks", gin.H{}, 1, db.UserRoleAdmin, 0)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = newAuthorizedRequest(t, server, http.MethodPost, "/api/v1/admin/users/1/reassign-tasks", gin.H{"target_user_id": 1}, 1, db.UserRoleAdmin, 0)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestReassignTasks(t *testing.T) {
+	store := newTestStore(t)
+	server := newTestServer(t, store)
+	ctx := context.Background()
+
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	project, err := store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: team.ID})
+	require.NoError(t, err)
+
+	admin := createTestUser(t, store, db.UserRoleAdmin, 0)
+	leaving := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+	successor := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+	leavingID := strconv.FormatInt(leaving.ID, 10)
+
+	task, err := store.CreateTask(ctx, db.CreateTaskParams{
+		ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+		Title:     util.RandomName(),
+		Status:    db.TaskStatusOpen,
+		Priority:  db.TaskPriorityMedium,
+	})
+	require.NoError(t, err)
+	_, err = store.AssignTaskToUser(ctx, db.AssignTaskToUserTxParams{TaskID: task.ID, UserID: leaving.ID})
+	require.NoError(t, err)
+
+	// The target must exist
+	recorder := newAuthorizedRequest(t, server, http.MethodPost, "/api/v1/admin/users/"+leavingID+"/reassign-tasks", gin.H{"target_user_id": math.MaxInt32}, admin.ID, db.UserRoleAdmin, 0)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+
+	// The task in progress moves, and the successor becomes busy
+	recorder = newAuthorizedRequest(t, server, http.MethodPost, "/api/v1/admin/users/"+leavingID+"/reassign-tasks", gin.H{"target_user_id": successor.ID}, admin.ID, db.UserRoleAdmin, 0)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var rsp struct {
+		FromUser struct {
+			Availability db.AvailabilityStatus `json:"availability"`
+		} `json:"from_user"`
+		ToUser struct {
+			Availability db.AvailabilityStatus `json:"availability"`
+		} `json:"to_user"`
+		MovedTasks struct {
+			Count   int `json:"count"`
+			Details []struct {
+				ID int64 `json:"id"`
+			} `json:"details"`
+		} `json:"moved_tasks"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Equal(t, db.AvailabilityStatusAvailable, rsp.FromUser.Availability)
+	require.Equal(t, db.AvailabilityStatusBusy, rsp.ToUser.Availability)
+	require.Equal(t, 1, rsp.MovedTasks.Count)
+	require.Equal(t, task.ID, rsp.MovedTasks.Details[0].ID)
+
+	// A busy engineer cannot take on more work
+	recorder = newAuthorizedRequest(t, server, http.MethodPost, "/api/v1/admin/users/"+leavingID+"/reassign-tasks", gin.H{"target_user_id": successor.ID}, admin.ID, db.UserRoleAdmin, 0)
+	require.Equal(t, http.StatusConflict, recorder.Code)
+}
+
+func TestBulkCreateManagerInvitationsValidation(t *testing.T) {
+	server := newTestServer(t, newUnreachableStore(t))
+
+	tooMany := make([]gin.H, maxBulkManagerInvitations+1)
+	for i := range tooMany {
+		tooMany[i] = gin.H{"email": util.RandomEmail(), "team_id": 1}
+	}
+
+	for _, body := range []any{
+		[]gin.H{},
+		tooMany,
+		[]gin.H{{"email": "not-an-email", "team_id": 1}},
+		[]gin.H{{"email": util.RandomEmail()}},
+	} {
+		recorder := newAuthorizedRequest(t, server, http.MethodPost, "/api/v1/admin/invitations/bulk", body, 1, db.UserRoleAdmin, 0)
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+	}
+}
+
+func TestBulkCreateManagerInvitations(t *testing.T) {
+	store := newTestStore(t)
+	server := newTestServer(t, store)
+	ctx := context.Background()
+
+	admin := createTestUser(t, store, db.UserRoleAdmin, 0)
+	openTeam, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	managedTeam, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	manager := createTestUser(t, store, db.UserRoleManager, managedTeam.ID)
+	_, err = store.SetTeamManager(ctx, db.SetTeamManagerParams{
+		ID:        managedTeam.ID,
+		ManagerID: pgtype.Int8{Int64: manager.ID, Valid: true},
+	})
+	require.NoError(t, err)
+
+	// One good entry, then a repeat of it, a team that already has a manager and a missing team
+	email := util.RandomEmail()
+	recorder := newAuthorizedRequest(t, server, http.MethodPost, "/api/v1/admin/invitations/bulk", []gin.H{
+		{"email": email, "team_id": openTeam.ID},
+		{"email": email, "team_id": openTeam.ID},
+		{"email": util.RandomEmail(), "team_id": managedTeam.ID},
+		{"email": util.RandomEmail(), "team_id": math.MaxInt32},
+	}, admin.ID, db.UserRoleAdmin, 0)
+	require.Equal(t, http.StatusMultiStatus, recorder.Code)
+
+	var rsp struct {
+		Created int `json:"created"`
+		Results []struct {
+			Email      string                  `json:"email"`
+			Status     int                     `json:"status"`
+			Error      string                  `json:"error"`
+			Invitation *db.CreateInvitationRow `json:"invitation"`
+		} `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Equal(t, 1, rsp.Created)
+	require.Len(t, rsp.Results, 4)
+
+	require.Equal(t, http.StatusCreated, rsp.Results[0].Status)
+	require.NotNil(t, rsp.Results[0].Invitation)
+	require.Equal(t, email, rsp.Results[0].Invitation.Email)
+	require.Equal(t, http.StatusConflict, rsp.Results[1].Status)
+	require.Equal(t, http.StatusConflict, rsp.Results[2].Status)
+	require.Equal(t, http.StatusNotFound, rsp.Results[3].Status)
+	for _, result := range rsp.Results[1:] {
+		require.NotEmpty(t, result.Error)
+		require.Nil(t, result.Invitation)
+	}
+
+	// The failures did not roll back the invitation that succeeded
+	_, err = store.GetInvitationByID(ctx, rsp.Results[0].Invitation.ID)
+	require.NoError(t, err)
+}
+
+func TestDeleteTeam(t *testing.T) {
+	store := newTestStore(t)
+	server := newTestServer(t, store)
+	ctx := context.Background()
+
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	engineer := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+	admin := createTestUser(t, store, db.UserRoleAdmin, 0)
+	project, err := store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: team.ID})
+	require.NoError(t, err)
+
+	send := func(method, path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(method, "/api/v1/admin/teams/"+strconv.FormatInt(team.ID, 10)+path, nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server, admin.ID, db.UserRoleAdmin, 0)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// An active project blocks the deletion; both the dry run and the delete say why
+	recorder := send(http.MethodGet, "/delete-impact")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var impact struct {
+		CanDelete      bool   `json:"can_delete"`
+		BlockingReason string `json:"blocking_reason"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &impact))
+	require.False(t, impact.CanDelete)
+	require.NotEmpty(t, impact.BlockingReason)
+
+	recorder = send(http.MethodDelete, "")
+	require.Equal(t, http.StatusConflict, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "blocking_reason")
+
+	// With the project archived, the team is deleted and its members unassigned
+	_, err = store.ArchiveProjectTx(ctx, db.ArchiveProjectTxParams{ProjectID: project.ID, TeamID: team.ID})
+	require.NoError(t, err)
+
+	recorder = send(http.MethodDelete, "")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var rsp struct {
+		RemovedMembers struct {
+			Count int `json:"count"`
+		} `json:"removed_members"`
+		RemovedProjects int64 `json:"removed_projects"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Equal(t, 1, rsp.RemovedMembers.Count)
+	require.Equal(t, int64(1), rsp.RemovedProjects)
+
+	user, err := store.GetUser(ctx, engineer.ID)
+	require.NoError(t, err)
+	require.False(t, user.TeamID.Valid)
+
+	// It is gone now
+	recorder = send(http.MethodGet, "/delete-impact")
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+	recorder = send(http.MethodDelete, "")
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestSkillAliasAdmin(t *testing.T) {
+	store := newTestStore(t)
+	server := newTestServer(t, store)
+	ctx := context.Background()
+
+	admin := createTestUser(t, store, db.UserRoleAdmin, 0)
+	skill, err := store.CreateSkill(ctx, db.CreateSkillParams{SkillName: util.RandomName()})
+	require.NoError(t, err)
+
+	// Aliases may contain slashes, like the seeded "ci/cd"
+	prefix := util.RandomString(10)
+	aliases := []string{prefix + "-a", prefix + "-b", prefix + "/c"}
+	for _, alias := range aliases {
+		_, err := store.CreateSkillAlias(ctx, db.CreateSkillAliasParams{AliasName: alias, SkillID: skill.ID})
+		require.NoError(t, err)
+	}
+
+	send := func(method, path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(method, "/api/v1/admin/skills/aliases"+path, nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server, admin.ID, db.UserRoleAdmin, 0)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+	type page struct {
+		TotalCount int64                       `json:"total_count"`
+		Data       []db.ListAllSkillAliasesRow `json:"data"`
+	}
+	list := func(pageID int) page {
+		recorder := send(http.MethodGet, "?page_size=5&search="+prefix+"&page_id="+strconv.Itoa(pageID))
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var rsp page
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+		return rsp
+	}
+
+	// All three fit on the first page, sorted by alias, with the skill name joined in
+	rsp := list(1)
+	require.Equal(t, int64(3), rsp.TotalCount)
+	require.Len(t, rsp.Data, 3)
+	for i, alias := range aliases {
+		require.Equal(t, alias, rsp.Data[i].AliasName)
+		require.Equal(t, skill.SkillName, rsp.Data[i].SkillName)
+	}
+	rsp = list(2)
+	require.Equal(t, int64(3), rsp.TotalCount)
+	require.Empty(t, rsp.Data)
+
+	// Deleting an alias with a slash in it works, and a second delete finds nothing
+	recorder := send(http.MethodDelete, "/"+aliases[2])
+	require.Equal(t, http.StatusNoContent, recorder.Code)
+	recorder = send(http.MethodDelete, "/"+aliases[2])
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+
+	rsp = list(1)
+	require.Equal(t, int64(2), rsp.TotalCount)
+	require.Len(t, rsp.Data, 2)
+
+	// An underscore in the search only matches an underscore, not any character
+	for _, alias := range []string{prefix + "_x", prefix + "ax"} {
+		_, err := store.CreateSkillAlias(ctx, db.CreateSkillAliasParams{AliasName: alias, SkillID: skill.ID})
+		require.NoError(t, err)
+	}
+	recorder = send(http.MethodGet, "?page_id=1&page_size=5&search="+prefix+"_")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Equal(t, int64(1), rsp.TotalCount)
+	require.Equal(t, prefix+"_x", rsp.Data[0].AliasName)
+}
+
+func TestListAllSkillAliasesValidation(t *testing.T) {
+	server := newTestServer(t, newUnreachableStore(t))
+
+	for _, query := range []string{"", "?page_id=0&page_size=5", "?page_id=1&page_size=500"} {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/api/v1/admin/skills/aliases"+query, nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server, 1, db.UserRoleAdmin, 0)
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusBadRequest, recorder.Code, query)
+	}
+}
+
+func TestUnverifiedSkillQueue(t *testing.T) {
+	store := newTestStore(t)
+	server := newTestServer(t, store)
+	ctx := context.Background()
+
+	admin := createTestUser(t, store, db.UserRoleAdmin, 0)
+	get := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/api/v1/admin/skills"+path, nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server, admin.ID, db.UserRoleAdmin, 0)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+	pendingCount := func() int64 {
+		recorder := get("/pending-count")
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var rsp struct {
+			PendingCount int64 `json:"pending_count"`
+		}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+		return rsp.PendingCount
+	}
+
+	// A new unverified skill joins the queue, and verifying it takes it out
+	before := pendingCount()
+	skill, err := store.CreateSkill(ctx, db.CreateSkillParams{SkillName: util.RandomName()})
+	require.NoError(t, err)
+	require.Equal(t, before+1, pendingCount())
+
+	recorder := get("/unverified/ranked?page_id=1&page_size=5")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var rsp paginatedResponse[db.ListMostUsedUnverifiedSkillsRow]
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Equal(t, before+1, rsp.TotalCount)
+	require.LessOrEqual(t, len(rsp.Data), 5)
+
+	_, err = store.UpdateSkillVerification(ctx, db.UpdateSkillVerificationParams{ID: skill.ID, IsVerified: true})
+	require.NoError(t, err)
+	require.Equal(t, before, pendingCount())
+}
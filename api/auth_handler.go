@@ -3,14 +3,20 @@
 package api
 
 import (
+	"cmp"
+	"context"
 	"errors"
-	"log"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"path"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	db "github.com/pranav244872/synapse/db/sqlc"
@@ -34,9 +40,12 @@ type loginUserRequest struct {
 }
 
 // loginUserResponse defines the structure of a successful login response.
-// It contains a signed JWT token the client can use for authenticated requests.
+// It contains a signed JWT token the client can use for authenticated requests,
+// and a refresh token for getting a new one from /auth/refresh once it expires.
 type loginUserResponse struct {
-	Token string `json:"token"` // Access token for subsequent requests
+	Token                 string    `json:"token"`                    // Access token for subsequent requests
+	RefreshToken          string    `json:"refresh_token"`            // Single-use token for renewing the session
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"` // When the refresh token stops working
 }
 
 ////////////////////////////////////////////////////////////////////////
@@ -89,9 +98,18 @@ func (server *Server) loginUser(ctx *gin.Context) {
 		return
 	}
 
-	// Step 5: Send response with token
+	// Step 5: Issue a refresh token so the client can renew the session without the password
+	refresh, err := server.issueRefreshToken(ctx, user.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Step 6: Send response with both tokens
 	rsp := loginUserResponse{
-		Token: token,
+		Token:                 token,
+		RefreshToken:          refresh.Token,
+		RefreshTokenExpiresAt: refresh.RefreshToken.ExpiresAt.Time,
 	}
 
 	// Return 200 OK with the token so the client can store and use it
@@ -110,6 +128,9 @@ type acceptInvitationRequest struct {
 	ResumeText string `json:"resume_text" binding:"required"`
 }
 
+// defaultMaxResumeLength caps resume text sent to the LLM when MAX_RESUME_LENGTH is not set
+const defaultMaxResumeLength = 20000
+
 // userResponse is a cleaner struct for API output, omitting the password hash.
 type userResponse struct {
 	ID     int64       `json:"id"`
@@ -121,8 +142,10 @@ type userResponse struct {
 
 // acceptInvitationResponse defines the successful response structure.
 type acceptInvitationResponse struct {
-	User  userResponse `json:"user"`
-	Token string       `json:"token"`
+	User                  userResponse `json:"user"`
+	Token                 string       `json:"token"`
+	RefreshToken          string       `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time    `json:"refresh_token_expires_at"`
 }
 
 func (server *Server) acceptInvitation(ctx *gin.Context) {
@@ -132,21 +155,25 @@ func (server *Server) acceptInvitation(ctx *gin.Context) {
 		return
 	}
 
+	if err := server.checkResumeLength(req.ResumeText); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
 	hashedPassword, err := util.HashPassword(req.Password)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	skills, err := server.skillzProcessor.ExtractAndNormalize(ctx, req.ResumeText)
+	skillsWithProficiency, err := server.extractResumeSkills(ctx, req.ResumeText)
 	if err != nil {
+		if abortIfCanceled(ctx) || abortIfTimedOut(ctx, err) {
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not process resume skills"})
 		return
 	}
-	skillsWithProficiency := make(map[string]db.ProficiencyLevel)
-	for _, skillName := range skills {
-		skillsWithProficiency[skillName] = db.ProficiencyLevelBeginner
-	}
 
 	// Prepare parameters for the NEW, correct transaction.
 	txParams := db.AcceptInvitationTxParams{
@@ -159,10 +186,17 @@ func (server *Server) acceptInvitation(ctx *gin.Context) {
 	// Execute the new transaction.
 	result, err := server.store.AcceptInvitationTx(ctx, txParams)
 	if err != nil {
+		if abortIfCanceled(ctx) || abortIfTimedOut(ctx, err) {
+			return
+		}
 		if errors.Is(err, db.ErrInvitationNotPending) {
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
 			return
 		}
+		if errors.Is(err, db.ErrTeamAlreadyHasManager) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
@@ -182,6 +216,12 @@ func (server *Server) acceptInvitation(ctx *gin.Context) {
 		return
 	}
 
+	refresh, err := server.issueRefreshToken(ctx, result.User.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
 	// Format and send the final response.
 	rsp := acceptInvitationResponse{
 		User: userResponse{
@@ -191,71 +231,514 @@ func (server *Server) acceptInvitation(ctx *gin.Context) {
 			Role:   result.User.Role,
 			TeamID: result.User.TeamID,
 		},
-		Token: jwtToken,
+		Token:                 jwtToken,
+		RefreshToken:          refresh.Token,
+		RefreshTokenExpiresAt: refresh.RefreshToken.ExpiresAt.Time,
 	}
 
 	ctx.JSON(http.StatusOK, rsp)
 }
 
+// checkResumeLength rejects oversized resumes up front with a message the user can act on,
+// instead of truncating them or paying for a huge LLM prompt
+func (server *Server) checkResumeLength(resumeText string) error {
+	maxResumeLength := cmp.Or(server.config.MaxResumeLength, defaultMaxResumeLength)
+	if utf8.RuneCountInString(resumeText) > maxResumeLength {
+		return fmt.Errorf("resume too long, please trim to %d characters", maxResumeLength)
+	}
+	return nil
+}
+
+// extractResumeSkills runs a resume through the skillz pipeline: skill extraction, then a level
+// for each skill from the same text. Only the first step can fail. Levels are best effort:
+// without them, or for skills the LLM leaves out, the skill is beginner.
+func (server *Server) extractResumeSkills(ctx context.Context, resumeText string) (map[string]db.ProficiencyLevel, error) {
+	skillsCtx, cancel := server.llmContext(ctx)
+	defer cancel()
+	skills, err := server.skillzProcessor.ExtractAndNormalize(skillsCtx, resumeText)
+	if err != nil {
+		return nil, err
+	}
+
+	proficiencyCtx, cancel := server.llmContext(ctx)
+	defer cancel()
+	proficiencies, err := server.skillzProcessor.ExtractProficiencies(proficiencyCtx, resumeText, skills)
+	if err != nil {
+		slog.Warn("Could not extract skill proficiencies, defaulting to beginner", "error", err)
+	}
+	skillsWithProficiency := make(map[string]db.ProficiencyLevel, len(skills))
+	for _, skillName := range skills {
+		skillsWithProficiency[skillName] = db.ProficiencyLevelBeginner
+		if level, ok := proficiencies[skillName]; ok {
+			skillsWithProficiency[skillName] = db.ProficiencyLevel(level)
+		}
+	}
+	return skillsWithProficiency, nil
+}
+
 ////////////////////////////////////////////////////////////////////////
-// Helper functions
+// Verify Invitation Endpoint (Public)
+// Lets the signup page check a token and pre-fill the form without accepting the invitation.
 ////////////////////////////////////////////////////////////////////////
 
-// notifyRecommender sends a non-blocking POST request to the recommender service
-// to trigger a model refresh. It runs in a separate goroutine.
-func (server *Server) notifyRecommender() {
-	// Fire-and-forget: run this in the background so it doesn't block the API response.
-	go func() {
-		recommenderBaseURL := server.config.RecommenderAPIURL
-		recommenderAPIKey := server.config.RecommenderAPIKey
+// verifyInvitationRequest binds the token from the URL path.
+type verifyInvitationRequest struct {
+	Token string `uri:"token" binding:"required"`
+}
+
+// verifyInvitationResponse describes the invitation a token belongs to.
+type verifyInvitationResponse struct {
+	Email    string      `json:"email"`
+	Role     db.UserRole `json:"role"`
+	TeamName string      `json:"team_name"`
+	Expired  bool        `json:"expired"`
+}
+
+// verifyInvitation lets the signup page check an invitation token before the user fills in the form.
+// It does not accept the invitation. Lapsed invitations are reported with expired set so the page can
+// ask for a resend; unknown, accepted and revoked tokens are not found.
+func (server *Server) verifyInvitation(ctx *gin.Context) {
+	var req verifyInvitationRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// Expiry is decided in SQL so it uses the same clock as acceptInvitation's lookup
+	invitation, err := server.store.GetPendingInvitationByToken(ctx, req.Token)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(db.ErrInvitationNotPending))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := verifyInvitationResponse{
+		Email:   invitation.Email,
+		Role:    invitation.RoleToInvite,
+		Expired: invitation.Expired,
+	}
+
+	if invitation.TeamID.Valid {
+		team, err := server.store.GetTeam(ctx, invitation.TeamID.Int64)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		rsp.TeamName = team.TeamName
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+////////////////////////////////////////////////////////////////////////
+// Password Reset Endpoints (Public): /auth/forgot-password, /auth/reset-password
+////////////////////////////////////////////////////////////////////////
+
+// forgotPasswordRequest defines the JSON body for requesting a password reset.
+type forgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// forgotPasswordMessage is returned whether or not the email belongs to an account,
+// so the endpoint cannot be used to discover which emails are registered.
+const forgotPasswordMessage = "if an account exists for that email, a password reset link has been sent"
+
+func (server *Server) forgotPassword(ctx *gin.Context) {
+	var req forgotPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
 
-		if recommenderBaseURL == "" || recommenderAPIKey == "" {
-			log.Println("WARN: Recommender service URL or API key is not configured. Skipping notification.")
+	resetToken, err := server.store.CreatePasswordResetTx(ctx, req.Email)
+	if err != nil {
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
+		// Unknown emails get the same response as known ones
+		if !errors.Is(err, pgx.ErrNoRows) {
+			slog.Error("Failed to create password reset token", "error", err)
+			ctx.JSON(http.StatusInternalServerError, errorResponse(errors.New("could not process password reset request")))
 			return
 		}
+	} else {
+		// The token itself is never logged or returned; it reaches the user only by email
+		slog.Info("Issued password reset token", "token_id", resetToken.ID, "user_id", resetToken.UserID, "expires_at", resetToken.ExpiresAt.Time.Format(time.RFC3339))
+		server.sendPasswordResetEmail(req.Email, resetToken)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": forgotPasswordMessage})
+}
+
+// resetPasswordRequest defines the JSON body for redeeming a password reset token.
+type resetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+func (server *Server) resetPassword(ctx *gin.Context) {
+	var req resetPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	hashedPassword, err := util.HashPassword(req.Password)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
 
-		// Safely parse the base URL.
-		parsedURL, err := url.Parse(recommenderBaseURL)
-		if err != nil {
-			log.Printf("ERROR: Failed to parse recommender base URL '%s': %v", recommenderBaseURL, err)
+	err = server.store.ResetPasswordTx(ctx, db.ResetPasswordTxParams{
+		Token:        req.Token,
+		PasswordHash: hashedPassword,
+	})
+	if err != nil {
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
+		if errors.Is(err, db.ErrPasswordResetTokenInvalid) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
 			return
 		}
+		slog.Error("Failed to reset password", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "password has been reset"})
+}
+
+////////////////////////////////////////////////////////////////////////
+// Refresh Token Endpoints (Public): /auth/refresh, /auth/logout
+////////////////////////////////////////////////////////////////////////
 
-		// Safely join the path to the base URL.
-		parsedURL.Path = path.Join(parsedURL.Path, "/admin/refresh-model")
-		endpointURL := parsedURL.String()
+// defaultRefreshTokenDuration is how long refresh tokens last when REFRESH_TOKEN_DURATION is not set
+const defaultRefreshTokenDuration = 7 * 24 * time.Hour
 
-		log.Printf("INFO: Notifying recommender service at: %s", endpointURL)
+// refreshTokenRequest carries the refresh token for both /auth/refresh and /auth/logout.
+type refreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// issueRefreshToken creates a refresh token for a user who has just authenticated
+func (server *Server) issueRefreshToken(ctx *gin.Context, userID int64) (db.IssueRefreshTokenResult, error) {
+	return server.store.IssueRefreshToken(ctx, db.IssueRefreshTokenParams{
+		UserID:   userID,
+		Duration: cmp.Or(server.config.RefreshTokenDuration, defaultRefreshTokenDuration),
+	})
+}
+
+////////////////////////////////////////////////////////////////////////
+// Handler: refreshToken
+// Exchanges a refresh token for a new access token and a rotated refresh token.
+// The presented refresh token stops working; presenting it again revokes all of the user's sessions.
+////////////////////////////////////////////////////////////////////////
 
-		// Create a new HTTP client with a reasonable timeout.
-		client := &http.Client{
-			Timeout: 10 * time.Second,
+func (server *Server) refreshToken(ctx *gin.Context) {
+	var req refreshTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// Step 1: Rotate the refresh token
+	result, err := server.store.RotateRefreshTokenTx(ctx, db.RotateRefreshTokenTxParams{
+		Token:    req.RefreshToken,
+		Duration: cmp.Or(server.config.RefreshTokenDuration, defaultRefreshTokenDuration),
+	})
+	if err != nil {
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
+		if errors.Is(err, db.ErrRefreshTokenReused) {
+			slog.Warn("Rotated refresh token was presented again; revoked all of the user's sessions")
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
 		}
+		if errors.Is(err, db.ErrRefreshTokenInvalid) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+		slog.Error("Failed to rotate refresh token", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Step 2: Issue an access token from the user's current role and team
+	token, err := server.tokenMaker.CreateToken(
+		result.User.ID,
+		result.User.Role,
+		result.User.TeamID,
+		server.config.AccessTokenDuration,
+	)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, loginUserResponse{
+		Token:                 token,
+		RefreshToken:          result.Token,
+		RefreshTokenExpiresAt: result.RefreshToken.ExpiresAt.Time,
+	})
+}
+
+////////////////////////////////////////////////////////////////////////
+// Handler: logoutUser
+// Revokes the presented refresh token. Access tokens already issued stay valid until they expire.
+////////////////////////////////////////////////////////////////////////
 
-		// Create the POST request with an empty body.
-		req, err := http.NewRequest("POST", endpointURL, nil)
-		if err != nil {
-			log.Printf("ERROR: Failed to create request for recommender service: %v", err)
+func (server *Server) logoutUser(ctx *gin.Context) {
+	var req refreshTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	err := server.store.RevokeRefreshToken(ctx, req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, db.ErrRefreshTokenInvalid) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 			return
 		}
+		slog.Error("Failed to revoke refresh token", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
 
-		// Set the required API key header for authentication.
-		req.Header.Set("X-Internal-API-Key", recommenderAPIKey)
+	ctx.JSON(http.StatusOK, gin.H{"message": "logged out"})
+}
+
+////////////////////////////////////////////////////////////////////////
+// Who Am I Endpoint (Authenticated): /auth/whoami
+////////////////////////////////////////////////////////////////////////
+
+// tokenClaimsResponse is what the verified token grants, as decoded from its claims.
+// Issuer and ImpersonatedBy are only present on tokens that carry them.
+type tokenClaimsResponse struct {
+	UserID         int64     `json:"user_id"`
+	Role           string    `json:"role"`
+	TeamID         *int64    `json:"team_id"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	Issuer         string    `json:"iss,omitempty"`
+	ImpersonatedBy *int64    `json:"impersonated_by,omitempty"`
+}
+
+// currentUserState is the user's role and team as stored right now
+type currentUserState struct {
+	Role   db.UserRole `json:"role"`
+	TeamID *int64      `json:"team_id"`
+}
+
+// whoamiResponse pairs the token with the database. Drift names the claims that no longer
+// match; "user_id" means the user was deleted after the token was issued.
+type whoamiResponse struct {
+	Token   tokenClaimsResponse `json:"token"`
+	Current *currentUserState   `json:"current"`
+	Drift   []string            `json:"drift"`
+}
+
+////////////////////////////////////////////////////////////////////////
+// Handler: whoami
+// Decodes the caller's own token server-side and compares it with their current
+// database record, so support can see exactly what a token grants.
+////////////////////////////////////////////////////////////////////////
+
+func (server *Server) whoami(ctx *gin.Context) {
+	authPayload, err := getAuthorizationPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	userID, err := getUserIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
 
-		// Send the request.
-		resp, err := client.Do(req)
-		if err != nil {
-			log.Printf("ERROR: Failed to send request to recommender service: %v", err)
+	// Step 1: Decode the claims; numeric claims arrive as float64
+	claims := tokenClaimsResponse{
+		UserID:         userID,
+		TeamID:         optionalIntClaim(authPayload, "team_id"),
+		ImpersonatedBy: optionalIntClaim(authPayload, "impersonated_by"),
+	}
+	claims.Role, _ = authPayload["role"].(string)
+	if exp, err := authPayload.GetExpirationTime(); err == nil && exp != nil {
+		claims.ExpiresAt = exp.Time
+	}
+	claims.Issuer, _ = authPayload.GetIssuer()
+
+	rsp := whoamiResponse{Token: claims, Drift: []string{}}
+
+	// Step 2: Load the user as they are now
+	user, err := server.store.GetUser(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			rsp.Drift = append(rsp.Drift, "user_id")
+			ctx.JSON(http.StatusOK, rsp)
 			return
 		}
-		defer resp.Body.Close()
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	current := &currentUserState{Role: user.Role}
+	if user.TeamID.Valid {
+		current.TeamID = &user.TeamID.Int64
+	}
+	rsp.Current = current
+
+	// Step 3: Flag every claim the database disagrees with
+	if claims.Role != string(user.Role) {
+		rsp.Drift = append(rsp.Drift, "role")
+	}
+	if (claims.TeamID == nil) != (current.TeamID == nil) || (claims.TeamID != nil && *claims.TeamID != *current.TeamID) {
+		rsp.Drift = append(rsp.Drift, "team_id")
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// optionalIntClaim reads a numeric claim, returning nil when the token does not carry it
+func optionalIntClaim(claims jwt.MapClaims, name string) *int64 {
+	value, ok := claims[name].(float64)
+	if !ok {
+		return nil
+	}
+	n := int64(value)
+	return &n
+}
+
+////////////////////////////////////////////////////////////////////////
+// Helper functions
+////////////////////////////////////////////////////////////////////////
+
+// invitationAcceptPath is the frontend page, relative to FRONTEND_URL, where invitees accept an invitation
+const invitationAcceptPath = "/accept-invitation"
+
+// sendInvitationEmail emails the invitee a link for accepting their invitation.
+// Like notifyRecommender it runs in the background, and a failure is only logged:
+// the invitation exists either way, and the token can still be shared by hand.
+// Shutdown waits for the send to finish.
+func (server *Server) sendInvitationEmail(invitation db.CreateInvitationRow) {
+	acceptURL, err := url.Parse(server.config.FrontendURL)
+	if err != nil {
+		slog.Error("Failed to parse frontend URL for invitation email", "url", server.config.FrontendURL, "error", err)
+		return
+	}
+	acceptURL.Path = path.Join(acceptURL.Path, invitationAcceptPath)
+	acceptURL.RawQuery = url.Values{"token": {invitation.InvitationToken}}.Encode()
+
+	subject := "You've been invited to join Synapse"
+	body := fmt.Sprintf("Hi,\n\n%s has invited you to join Synapse as %s.\n\nAccept your invitation here:\n%s\n\nThis link expires on %s.\n",
+		invitation.InviterName,
+		articleFor(string(invitation.RoleToInvite)),
+		acceptURL.String(),
+		invitation.ExpiresAt.Time.Format("January 2, 2006"),
+	)
 
-		// Check the response status. The recommender should return 202 Accepted.
-		if resp.StatusCode != http.StatusAccepted {
-			log.Printf("ERROR: Recommender service returned a non-202 status: %d", resp.StatusCode)
+	server.backgroundJobs.Add(1)
+	go func() {
+		defer server.backgroundJobs.Done()
+		if err := server.emailer.SendEmail(invitation.Email, subject, body); err != nil {
+			slog.Error("Failed to send invitation email", "invitation_id", invitation.ID, "error", err)
 			return
 		}
+		slog.Info("Sent invitation email", "invitation_id", invitation.ID)
+	}()
+}
+
+// passwordResetPath is the frontend page, relative to FRONTEND_URL, where users choose a new password
+const passwordResetPath = "/reset-password"
+
+// sendPasswordResetEmail emails a user the link for redeeming their reset token.
+// As with sendInvitationEmail the send runs in the background and a failure is only logged,
+// so the response never reveals whether the email belongs to an account.
+func (server *Server) sendPasswordResetEmail(email string, resetToken db.PasswordResetToken) {
+	resetURL, err := url.Parse(server.config.FrontendURL)
+	if err != nil {
+		slog.Error("Failed to parse frontend URL for password reset email", "url", server.config.FrontendURL, "error", err)
+		return
+	}
+	resetURL.Path = path.Join(resetURL.Path, passwordResetPath)
+	resetURL.RawQuery = url.Values{"token": {resetToken.Token}}.Encode()
 
-		log.Println("INFO: Successfully notified recommender service to refresh its model.")
+	subject := "Reset your Synapse password"
+	body := fmt.Sprintf("Hi,\n\nSomeone asked to reset the password of your Synapse account.\n\nChoose a new password here:\n%s\n\nThis link expires at %s. If you did not ask for a reset, you can ignore this email.\n",
+		resetURL.String(),
+		resetToken.ExpiresAt.Time.Format("January 2, 2006 15:04 MST"),
+	)
+
+	server.backgroundJobs.Add(1)
+	go func() {
+		defer server.backgroundJobs.Done()
+		if err := server.emailer.SendEmail(email, subject, body); err != nil {
+			slog.Error("Failed to send password reset email", "token_id", resetToken.ID, "error", err)
+			return
+		}
+		slog.Info("Sent password reset email", "token_id", resetToken.ID)
 	}()
 }
+
+// articleFor prefixes a role with "a" or "an", e.g. "an engineer"
+func articleFor(role string) string {
+	if role != "" && strings.ContainsRune("aeiou", rune(role[0])) {
+		return "an " + role
+	}
+	return "a " + role
+}
+
+// notifyRecommender asks the recommender service to refresh its model. It does not block:
+// the refresh is debounced and retried in the background by server.modelRefresh.
+func (server *Server) notifyRecommender() {
+	// The model is about to change, so earlier answers are stale
+	server.recommendations.clear()
+
+	if server.config.RecommenderAPIURL == "" || server.config.RecommenderAPIKey == "" {
+		slog.Warn("Recommender service URL or API key is not configured, skipping notification")
+		return
+	}
+
+	server.modelRefresh.request()
+}
+
+// refreshRecommenderModel sends one POST to the recommender service's /admin/refresh-model
+func (server *Server) refreshRecommenderModel(ctx context.Context) error {
+	// Safely parse the base URL.
+	parsedURL, err := url.Parse(server.config.RecommenderAPIURL)
+	if err != nil {
+		return fmt.Errorf("cannot parse recommender base URL: %w", err)
+	}
+
+	// Safely join the path to the base URL.
+	parsedURL.Path = path.Join(parsedURL.Path, "/admin/refresh-model")
+	endpointURL := parsedURL.String()
+
+	slog.Info("Notifying recommender service", "endpoint_url", endpointURL)
+
+	// Create the POST request with an empty body.
+	req, err := http.NewRequestWithContext(ctx, "POST", endpointURL, nil)
+	if err != nil {
+		return fmt.Errorf("cannot create request for recommender service: %w", err)
+	}
+
+	// Set the required API key header for authentication.
+	req.Header.Set("X-Internal-API-Key", server.config.RecommenderAPIKey)
+
+	// Send the request over the shared recommender client.
+	resp, err := server.recommenderClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Check the response status. The recommender should return 202 Accepted.
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("recommender service returned status %d", resp.StatusCode)
+	}
+	return nil
+}
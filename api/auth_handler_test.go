@@ -0,0 +1,501 @@
+// api/auth_handler_test.go
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pranav244872/synapse/db/sqlc"
+	"github.com/pranav244872/synapse/skillz"
+	"github.com/pranav244872/synapse/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptInvitationClientCanceled(t *testing.T) {
+	// Arrange
+	processor := &mockSkillzProcessor{skills: []string{"Go"}, canceled: make(chan struct{})}
+	server := newTestServer(t, nil)
+	server.skillzProcessor = processor
+
+	body, err := json.Marshal(gin.H{
+		"token":       "some-token",
+		"name":        "Jane Doe",
+		"password":    "secret123",
+		"resume_text": "Go and PostgreSQL",
+	})
+	require.NoError(t, err)
+
+	reqCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequestWithContext(reqCtx, http.MethodPost, "/api/v1/invitations/accept", bytes.NewReader(body))
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", "application/json")
+
+	// Act: disconnect the client while the LLM call is in flight
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		server.router.ServeHTTP(recorder, request)
+	}()
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	// Assert
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler did not return after the request was canceled")
+	}
+
+	select {
+	case <-processor.canceled:
+	default:
+		t.Fatal("LLM call did not observe the cancellation")
+	}
+	require.Zero(t, recorder.Body.Len(), "no response should be written for a canceled request")
+}
+
+func TestAcceptInvitationLLMTimeout(t *testing.T) {
+	// Arrange: an LLM that answers far slower than the configured limit
+	processor := &mockSkillzProcessor{skills: []string{"Go"}, canceled: make(chan struct{})}
+	server := newTestServer(t, nil)
+	server.skillzProcessor = processor
+	server.config.LLMTimeout = 50 * time.Millisecond
+
+	body, err := json.Marshal(gin.H{
+		"token":       "some-token",
+		"name":        "Jane Doe",
+		"password":    "secret123",
+		"resume_text": "Go and PostgreSQL",
+	})
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/api/v1/invitations/accept", bytes.NewReader(body))
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", "application/json")
+
+	// Act
+	start := time.Now()
+	server.router.ServeHTTP(recorder, request)
+
+	// Assert: the call was cut off at the deadline and reported as a timeout
+	require.Less(t, time.Since(start), 2*time.Second)
+	require.Equal(t, http.StatusGatewayTimeout, recorder.Code)
+	require.Contains(t, recorder.Body.String(), errOperationTimedOut.Error())
+	select {
+	case <-processor.canceled:
+	default:
+		t.Fatal("LLM call did not observe the deadline")
+	}
+}
+
+func TestAcceptInvitationProficiencies(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	server := newTestServer(t, store)
+
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	manager := createTestUser(t, store, db.UserRoleManager, team.ID)
+
+	// accept invites a new engineer and signs them up, returning their skills by lowercase name
+	accept := func(t *testing.T, processor skillz.Processor) map[string]db.ProficiencyLevel {
+		server.skillzProcessor = processor
+
+		invitation, err := store.CreateInvitationTx(ctx, db.CreateInvitationTxParams{
+			InviterID:     manager.ID,
+			EmailToInvite: util.RandomEmail(),
+			RoleToInvite:  db.UserRoleEngineer,
+		})
+		require.NoError(t, err)
+
+		body, err := json.Marshal(gin.H{
+			"token":       invitation.Invitation.InvitationToken,
+			"name":        util.RandomName(),
+			"password":    "secret123",
+			"resume_text": "Years of experience with a few tools",
+		})
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/api/v1/invitations/accept", bytes.NewReader(body))
+		require.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+		var rsp acceptInvitationResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+		skills, err := store.GetSkillsForUser(ctx, rsp.User.ID)
+		require.NoError(t, err)
+
+		levels := make(map[string]db.ProficiencyLevel, len(skills))
+		for _, skill := range skills {
+			levels[strings.ToLower(skill.SkillName)] = skill.Proficiency
+		}
+		return levels
+	}
+
+	expert, intermediate, omitted := util.RandomString(10), util.RandomString(10), util.RandomString(10)
+
+	t.Run("Skills get the extracted levels, or beginner when left out", func(t *testing.T) {
+		levels := accept(t, &mockSkillzProcessor{
+			skills:        []string{expert, intermediate, omitted},
+			proficiencies: map[string]string{expert: "expert", intermediate: "intermediate"},
+		})
+		require.Equal(t, map[string]db.ProficiencyLevel{
+			strings.ToLower(expert):       db.ProficiencyLevelExpert,
+			strings.ToLower(intermediate): db.ProficiencyLevelIntermediate,
+			strings.ToLower(omitted):      db.ProficiencyLevelBeginner,
+		}, levels)
+	})
+
+	t.Run("A failed proficiency extraction does not block signup", func(t *testing.T) {
+		levels := accept(t, &mockSkillzProcessor{
+			skills:         []string{expert},
+			proficiencyErr: errors.New("LLM unavailable"),
+		})
+		require.Equal(t, map[string]db.ProficiencyLevel{strings.ToLower(expert): db.ProficiencyLevelBeginner}, levels)
+	})
+}
+
+func TestAcceptInvitationResumeTooLong(t *testing.T) {
+	// Arrange
+	server := newTestServer(t, nil)
+	server.config.MaxResumeLength = 10
+	server.skillzProcessor = &mockSkillzProcessor{unusedBy: t}
+
+	body, err := json.Marshal(gin.H{
+		"token":       "some-token",
+		"name":        "Jane Doe",
+		"password":    "secret123",
+		"resume_text": "Go, PostgreSQL and Kubernetes",
+	})
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/api/v1/invitations/accept", bytes.NewReader(body))
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", "application/json")
+
+	// Act
+	server.router.ServeHTTP(recorder, request)
+
+	// Assert
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "resume too long, please trim to 10 characters")
+}
+
+func TestWhoami(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	engineer := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+	server := newTestServer(t, store)
+
+	// The token is issued once, before the role change below
+	request, err := http.NewRequest(http.MethodGet, "/api/v1/auth/whoami", nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server, engineer.ID, db.UserRoleEngineer, team.ID)
+
+	whoami := func(t *testing.T) whoamiResponse {
+		recorder := httptest.NewRecorder()
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var rsp whoamiResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+		return rsp
+	}
+
+	// While token and database agree there is no drift
+	rsp := whoami(t)
+	require.Equal(t, engineer.ID, rsp.Token.UserID)
+	require.Equal(t, string(db.UserRoleEngineer), rsp.Token.Role)
+	require.Equal(t, team.ID, *rsp.Token.TeamID)
+	require.WithinDuration(t, time.Now().Add(time.Minute), rsp.Token.ExpiresAt, 5*time.Second)
+	require.Nil(t, rsp.Token.ImpersonatedBy)
+	require.Equal(t, db.UserRoleEngineer, rsp.Current.Role)
+	require.Empty(t, rsp.Drift)
+
+	// Promoting the user leaves the token claiming the old role
+	_, err = store.UpdateUser(ctx, db.UpdateUserParams{
+		ID:   engineer.ID,
+		Role: db.NullUserRole{UserRole: db.UserRoleManager, Valid: true},
+	})
+	require.NoError(t, err)
+
+	rsp = whoami(t)
+	require.Equal(t, string(db.UserRoleEngineer), rsp.Token.Role)
+	require.Equal(t, db.UserRoleManager, rsp.Current.Role)
+	require.Equal(t, []string{"role"}, rsp.Drift)
+}
+
+func TestPasswordReset(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	server := newTestServer(t, store)
+	server.config.FrontendURL = "https://app.synapse.dev/"
+	emailer := &recordingEmailer{sent: make(chan sentEmail, 2)}
+	server.emailer = emailer
+	user := createTestUser(t, store, db.UserRoleEngineer, 0)
+
+	post := func(t *testing.T, url string, body gin.H) *httptest.ResponseRecorder {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+		require.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	olderToken, err := store.CreatePasswordResetTx(ctx, user.Email)
+	require.NoError(t, err)
+
+	// Known and unknown emails get the same answer
+	known := post(t, "/api/v1/auth/forgot-password", gin.H{"email": user.Email})
+	unknown := post(t, "/api/v1/auth/forgot-password", gin.H{"email": util.RandomEmail()})
+	require.Equal(t, http.StatusOK, known.Code)
+	require.Equal(t, http.StatusOK, unknown.Code)
+	require.Equal(t, known.Body.String(), unknown.Body.String())
+
+	// Only the account's owner is emailed, with a link carrying the token
+	server.backgroundJobs.Wait()
+	require.Len(t, emailer.sent, 1)
+	email := <-emailer.sent
+	require.Equal(t, user.Email, email.to)
+	match := regexp.MustCompile(`https://app\.synapse\.dev/reset-password\?token=([0-9a-f-]+)`).FindStringSubmatch(email.body)
+	require.NotNil(t, match, email.body)
+	resetToken := match[1]
+
+	session, err := store.IssueRefreshToken(ctx, db.IssueRefreshTokenParams{UserID: user.ID, Duration: time.Hour})
+	require.NoError(t, err)
+
+	// The emailed token works once
+	recorder := post(t, "/api/v1/auth/reset-password", gin.H{"token": resetToken, "password": "new-secret"})
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	updatedUser, err := store.GetUser(ctx, user.ID)
+	require.NoError(t, err)
+	require.NoError(t, util.CheckPasswordHash("new-secret", updatedUser.PasswordHash))
+
+	// Sessions opened before the reset are over
+	recorder = post(t, "/api/v1/auth/refresh", gin.H{"refresh_token": session.Token})
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	// A reused token is rejected
+	recorder = post(t, "/api/v1/auth/reset-password", gin.H{"token": resetToken, "password": "another-secret"})
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Contains(t, recorder.Body.String(), db.ErrPasswordResetTokenInvalid.Error())
+
+	// So is an older link that was never used
+	recorder = post(t, "/api/v1/auth/reset-password", gin.H{"token": olderToken.Token, "password": "another-secret"})
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Contains(t, recorder.Body.String(), db.ErrPasswordResetTokenInvalid.Error())
+}
+
+func TestRefreshToken(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	server := newTestServer(t, store)
+	user := createTestUser(t, store, db.UserRoleEngineer, 0)
+
+	hashedPassword, err := util.HashPassword("secret-password")
+	require.NoError(t, err)
+	require.NoError(t, store.UpdateUserPassword(ctx, db.UpdateUserPasswordParams{ID: user.ID, PasswordHash: hashedPassword}))
+
+	post := func(t *testing.T, url string, body gin.H) (*httptest.ResponseRecorder, loginUserResponse) {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+		require.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		server.router.ServeHTTP(recorder, request)
+
+		var rsp loginUserResponse
+		if recorder.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+		}
+		return recorder, rsp
+	}
+
+	// Login hands out a refresh token alongside the access token
+	recorder, login := post(t, "/api/v1/auth/login", gin.H{"email": user.Email, "password": "secret-password"})
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.NotEmpty(t, login.RefreshToken)
+	require.True(t, login.RefreshTokenExpiresAt.After(time.Now()))
+
+	// Refreshing returns a usable access token and a different refresh token
+	recorder, refreshed := post(t, "/api/v1/auth/refresh", gin.H{"refresh_token": login.RefreshToken})
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.NotEqual(t, login.RefreshToken, refreshed.RefreshToken)
+
+	payload, err := server.tokenMaker.VerifyToken(refreshed.Token)
+	require.NoError(t, err)
+	require.Equal(t, float64(user.ID), payload["user_id"])
+
+	// Replaying the old token is rejected and kills the new one too
+	recorder, _ = post(t, "/api/v1/auth/refresh", gin.H{"refresh_token": login.RefreshToken})
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	require.Contains(t, recorder.Body.String(), db.ErrRefreshTokenReused.Error())
+
+	recorder, _ = post(t, "/api/v1/auth/refresh", gin.H{"refresh_token": refreshed.RefreshToken})
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	// Logout revokes the presented token
+	_, second := post(t, "/api/v1/auth/login", gin.H{"email": user.Email, "password": "secret-password"})
+	recorder, _ = post(t, "/api/v1/auth/logout", gin.H{"refresh_token": second.RefreshToken})
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder, _ = post(t, "/api/v1/auth/refresh", gin.H{"refresh_token": second.RefreshToken})
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	require.Contains(t, recorder.Body.String(), db.ErrRefreshTokenInvalid.Error())
+}
+
+// recordingEmailer captures sent emails on a channel
+type recordingEmailer struct {
+	sent chan sentEmail
+	err  error
+}
+
+type sentEmail struct {
+	to, subject, body string
+}
+
+func (e *recordingEmailer) SendEmail(to, subject, body string) error {
+	e.sent <- sentEmail{to, subject, body}
+	return e.err
+}
+
+func TestSendInvitationEmail(t *testing.T) {
+	server := newTestServer(t, nil)
+	server.config.FrontendURL = "https://app.synapse.dev/"
+	emailer := &recordingEmailer{sent: make(chan sentEmail, 1)}
+	server.emailer = emailer
+
+	invitation := db.CreateInvitationRow{
+		ID:              7,
+		Email:           "jane@example.com",
+		InvitationToken: "3f2a-token",
+		RoleToInvite:    db.UserRoleEngineer,
+		ExpiresAt:       pgtype.Timestamp{Time: time.Date(2025, time.March, 4, 0, 0, 0, 0, time.UTC), Valid: true},
+		InviterName:     "Sam Manager",
+	}
+
+	server.sendInvitationEmail(invitation)
+
+	select {
+	case email := <-emailer.sent:
+		require.Equal(t, "jane@example.com", email.to)
+		require.Contains(t, email.body, "Sam Manager has invited you to join Synapse as an engineer")
+		require.Contains(t, email.body, "https://app.synapse.dev/accept-invitation?token=3f2a-token")
+		require.Contains(t, email.body, "March 4, 2025")
+	case <-time.After(time.Second):
+		t.Fatal("invitation email was not sent")
+	}
+}
+
+func TestSendPasswordResetEmail(t *testing.T) {
+	server := newTestServer(t, nil)
+	server.config.FrontendURL = "https://app.synapse.dev/"
+	emailer := &recordingEmailer{sent: make(chan sentEmail, 1)}
+	server.emailer = emailer
+
+	resetToken := db.PasswordResetToken{
+		ID:        3,
+		Token:     "9c1e-token",
+		ExpiresAt: pgtype.Timestamp{Time: time.Date(2025, time.March, 4, 15, 30, 0, 0, time.UTC), Valid: true},
+	}
+
+	server.sendPasswordResetEmail("jane@example.com", resetToken)
+
+	select {
+	case email := <-emailer.sent:
+		require.Equal(t, "jane@example.com", email.to)
+		require.Contains(t, email.body, "https://app.synapse.dev/reset-password?token=9c1e-token")
+		require.Contains(t, email.body, "March 4, 2025 15:30 UTC")
+	case <-time.After(time.Second):
+		t.Fatal("password reset email was not sent")
+	}
+}
+
+func TestVerifyInvitation(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	server := newTestServer(t, store)
+
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	manager := createTestUser(t, store, db.UserRoleManager, team.ID)
+	result, err := store.CreateInvitationTx(ctx, db.CreateInvitationTxParams{
+		InviterID:     manager.ID,
+		EmailToInvite: util.RandomEmail(),
+		RoleToInvite:  db.UserRoleEngineer,
+	})
+	require.NoError(t, err)
+	invitation := result.Invitation
+
+	verify := func(t *testing.T, token string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/api/v1/invitations/verify/"+token, nil)
+		require.NoError(t, err)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// Checking a token does not use it up
+	for range 2 {
+		recorder := verify(t, invitation.InvitationToken)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var rsp verifyInvitationResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+		require.Equal(t, invitation.Email, rsp.Email)
+		require.Equal(t, db.UserRoleEngineer, rsp.Role)
+		require.Equal(t, team.TeamName, rsp.TeamName)
+		require.False(t, rsp.Expired)
+	}
+
+	require.Equal(t, http.StatusNotFound, verify(t, util.RandomString(36)).Code)
+
+	// A lapsed invitation is still found, but reported as expired
+	lapsed, err := store.CreateInvitation(ctx, db.CreateInvitationParams{
+		Email:           util.RandomEmail(),
+		InvitationToken: util.RandomString(36),
+		RoleToInvite:    db.UserRoleEngineer,
+		InviterID:       manager.ID,
+		TeamID:          pgtype.Int8{Int64: team.ID, Valid: true},
+		ExpiresAt:       pgtype.Timestamp{Time: time.Now().Add(-24 * time.Hour), Valid: true},
+	})
+	require.NoError(t, err)
+	recorder := verify(t, lapsed.InvitationToken)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var rsp verifyInvitationResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Equal(t, lapsed.Email, rsp.Email)
+	require.True(t, rsp.Expired)
+
+	// Once accepted, the token is no longer valid
+	_, err = store.UpdateInvitationStatus(ctx, db.UpdateInvitationStatusParams{ID: invitation.ID, Status: "accepted"})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, verify(t, invitation.InvitationToken).Code)
+}
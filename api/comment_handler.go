@@ -0,0 +1,203 @@
+// api/comment_handler.go
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pranav244872/synapse/db/sqlc"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Task Comment Handlers (Managers and Engineers)
+////////////////////////////////////////////////////////////////////////
+
+// createTaskCommentRequest is the body of a new comment; parent_id makes it a reply
+type createTaskCommentRequest struct {
+	Body     string `json:"body" binding:"required,max=5000"`
+	ParentID int64  `json:"parent_id" binding:"omitempty,min=1"`
+}
+
+// createTaskComment adds a comment, or a reply to one, on a task of the caller's team.
+func (server *Server) createTaskComment(ctx *gin.Context) {
+	slog.Debug("Starting createTaskComment handler")
+
+	var uriReq struct {
+		ID int64 `uri:"id" binding:"required,min=1"`
+	}
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req createTaskCommentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authorID, err := getUserIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	// Team membership is checked inside the transaction against the user's current team
+	comment, err := server.store.CreateTaskCommentTx(ctx, db.CreateTaskCommentTxParams{
+		TaskID:   uriReq.ID,
+		AuthorID: authorID,
+		ParentID: pgtype.Int8{Int64: req.ParentID, Valid: req.ParentID != 0},
+		Body:     req.Body,
+	})
+	if err != nil {
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, db.ErrTaskNotFound):
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		case errors.Is(err, db.ErrCommenterNotInTeam):
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+		case errors.Is(err, db.ErrParentCommentNotOnTask):
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		default:
+			slog.Error("Failed to comment on task", "task_id", uriReq.ID, "error", err)
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, comment)
+}
+
+// listTaskCommentsRequest pages through a task's comments, oldest first
+type listTaskCommentsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listTaskComments lists the comments on a task of the caller's team. Replies are in the same
+// flat list with their parent_id set, so clients can build the threads.
+func (server *Server) listTaskComments(ctx *gin.Context) {
+	slog.Debug("Starting listTaskComments handler")
+
+	var uriReq struct {
+		ID int64 `uri:"id" binding:"required,min=1"`
+	}
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req listTaskCommentsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	userID, err := getUserIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	teamID, err := server.currentTeamID(ctx, userID)
+	if err != nil {
+		ctx.JSON(teamScopeErrorStatus(err), errorResponse(err))
+		return
+	}
+	if _, err := server.assertTaskInTeam(ctx, uriReq.ID, teamID); err != nil {
+		ctx.JSON(teamScopeErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	comments, err := server.store.ListTaskCommentsByTask(ctx, db.ListTaskCommentsByTaskParams{
+		TaskID: uriReq.ID,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	totalCount, err := server.store.CountTaskCommentsByTask(ctx, uriReq.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if comments == nil {
+		comments = []db.ListTaskCommentsByTaskRow{}
+	}
+	ctx.JSON(http.StatusOK, paginatedResponse[db.ListTaskCommentsByTaskRow]{
+		TotalCount: totalCount,
+		Data:       comments,
+	})
+}
+
+// deleteTaskComment soft-deletes a comment, leaving its replies in place. Only its author
+// or a manager of the task's team may delete it.
+func (server *Server) deleteTaskComment(ctx *gin.Context) {
+	slog.Debug("Starting deleteTaskComment handler")
+
+	var uriReq struct {
+		ID int64 `uri:"id" binding:"required,min=1"`
+	}
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	userID, err := getUserIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	comment, err := server.store.GetTaskComment(ctx, uriReq.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("comment not found")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if comment.DeletedAt.Valid {
+		ctx.JSON(http.StatusNotFound, errorResponse(errors.New("comment not found")))
+		return
+	}
+
+	if comment.AuthorID != userID {
+		authPayload, _ := getAuthorizationPayload(ctx)
+		if authPayload["role"] != string(db.UserRoleManager) {
+			ctx.JSON(http.StatusForbidden, errorResponse(errors.New("you can only delete your own comments")))
+			return
+		}
+
+		// Managers moderate their own team's tasks only; the team is read fresh from the database
+		teamID, err := server.currentTeamID(ctx, userID)
+		if err != nil {
+			ctx.JSON(teamScopeErrorStatus(err), errorResponse(err))
+			return
+		}
+		if _, err := server.assertTaskInTeam(ctx, comment.TaskID, teamID); err != nil {
+			ctx.JSON(teamScopeErrorStatus(err), errorResponse(err))
+			return
+		}
+	}
+
+	if err := server.store.DeleteTaskComment(ctx, comment.ID); err != nil {
+		slog.Error("Failed to delete comment", "comment_id", comment.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	slog.Info("User deleted comment on task", "user_id", userID, "comment_id", comment.ID, "task_id", comment.TaskID)
+	ctx.Status(http.StatusNoContent)
+}
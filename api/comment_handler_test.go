@@ -0,0 +1,131 @@
+// api/comment_handler_test.go
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pranav244872/synapse/db/sqlc"
+	"github.com/pranav244872/synapse/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskComments(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	// Arrange: a task on a team with a manager and two engineers, plus a manager of another team
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	otherTeam, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	manager := createTestUser(t, store, db.UserRoleManager, team.ID)
+	author := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+	teammate := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+	otherManager := createTestUser(t, store, db.UserRoleManager, otherTeam.ID)
+
+	project, err := store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: team.ID})
+	require.NoError(t, err)
+	task, err := store.CreateTask(ctx, db.CreateTaskParams{
+		ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+		Title:     util.RandomName(),
+		Status:    db.TaskStatusOpen,
+		Priority:  db.TaskPriorityMedium,
+	})
+	require.NoError(t, err)
+
+	server := newTestServer(t, store)
+	serve := func(t *testing.T, method, url string, body any, user db.User) *httptest.ResponseRecorder {
+		var data []byte
+		if body != nil {
+			var err error
+			data, err = json.Marshal(body)
+			require.NoError(t, err)
+		}
+
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(method, url, bytes.NewReader(data))
+		require.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		addAuthorization(t, request, server, user.ID, user.Role, user.TeamID.Int64)
+
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+	commentsURL := fmt.Sprintf("/api/v1/tasks/%d/comments", task.ID)
+	postComment := func(t *testing.T, user db.User, parentID int64) db.TaskComment {
+		recorder := serve(t, http.MethodPost, commentsURL, gin.H{"body": util.RandomString(20), "parent_id": parentID}, user)
+		require.Equal(t, http.StatusCreated, recorder.Code)
+
+		var comment db.TaskComment
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &comment))
+		return comment
+	}
+
+	t.Run("Team members comment and reply, and see the thread in task details", func(t *testing.T) {
+		comment := postComment(t, author, 0)
+		reply := postComment(t, manager, comment.ID)
+		require.Equal(t, comment.ID, reply.ParentID.Int64)
+
+		recorder := serve(t, http.MethodGet, commentsURL+"?page_id=1&page_size=10", nil, teammate)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var page paginatedResponse[db.ListTaskCommentsByTaskRow]
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &page))
+		require.Equal(t, int64(2), page.TotalCount)
+
+		recorder = serve(t, http.MethodGet, fmt.Sprintf("/api/v1/engineer/tasks/%d", task.ID), nil, teammate)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var details struct {
+			Comments []db.ListTaskCommentsByTaskRow `json:"comments"`
+		}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &details))
+		require.Len(t, details.Comments, 2)
+	})
+
+	t.Run("Other teams can neither comment nor read", func(t *testing.T) {
+		recorder := serve(t, http.MethodPost, commentsURL, gin.H{"body": "hello"}, otherManager)
+		require.Equal(t, http.StatusForbidden, recorder.Code)
+
+		recorder = serve(t, http.MethodGet, commentsURL+"?page_id=1&page_size=10", nil, otherManager)
+		require.Equal(t, http.StatusForbidden, recorder.Code)
+	})
+
+	t.Run("Only the author or a manager of the team may delete", func(t *testing.T) {
+		comment := postComment(t, author, 0)
+		deleteURL := fmt.Sprintf("/api/v1/comments/%d", comment.ID)
+
+		require.Equal(t, http.StatusForbidden, serve(t, http.MethodDelete, deleteURL, nil, teammate).Code)
+		require.Equal(t, http.StatusForbidden, serve(t, http.MethodDelete, deleteURL, nil, otherManager).Code)
+		require.Equal(t, http.StatusNoContent, serve(t, http.MethodDelete, deleteURL, nil, manager).Code)
+		require.Equal(t, http.StatusNotFound, serve(t, http.MethodDelete, deleteURL, nil, manager).Code)
+
+		deleted, err := store.GetTaskComment(ctx, comment.ID)
+		require.NoError(t, err)
+		require.True(t, deleted.DeletedAt.Valid)
+		require.Empty(t, deleted.Body)
+
+		own := postComment(t, author, 0)
+		require.Equal(t, http.StatusNoContent, serve(t, http.MethodDelete, fmt.Sprintf("/api/v1/comments/%d", own.ID), nil, author).Code)
+	})
+
+	t.Run("Deleting a comment keeps other users' replies", func(t *testing.T) {
+		comment := postComment(t, author, 0)
+		reply := postComment(t, teammate, comment.ID)
+
+		recorder := serve(t, http.MethodDelete, fmt.Sprintf("/api/v1/comments/%d", comment.ID), nil, author)
+		require.Equal(t, http.StatusNoContent, recorder.Code)
+
+		kept, err := store.GetTaskComment(ctx, reply.ID)
+		require.NoError(t, err)
+		require.Equal(t, reply.Body, kept.Body)
+		require.Equal(t, comment.ID, kept.ParentID.Int64)
+		require.False(t, kept.DeletedAt.Valid)
+	})
+}
@@ -0,0 +1,67 @@
+// api/docs_handler.go
+
+package api
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+////////////////////////////////////////////////////////////////////////
+// OpenAPI Spec and Swagger UI
+////////////////////////////////////////////////////////////////////////
+
+// openAPIYAML is the hand-maintained API description; TestOpenAPISpec keeps it in step with the router
+//
+//go:embed openapi.yaml
+var openAPIYAML []byte
+
+// openAPIJSON converts the embedded spec to JSON once, on first use
+var openAPIJSON = sync.OnceValues(func() ([]byte, error) {
+	var spec map[string]any
+	if err := yaml.Unmarshal(openAPIYAML, &spec); err != nil {
+		return nil, err
+	}
+	return json.Marshal(spec)
+})
+
+// swaggerUIVersion pins the Swagger UI assets loaded by /docs
+const swaggerUIVersion = "5.17.14"
+
+// swaggerUIPage loads Swagger UI from a CDN and points it at /openapi.json
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Synapse API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@` + swaggerUIVersion + `/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@` + swaggerUIVersion + `/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
+
+// openAPISpec serves the API description as JSON
+func (server *Server) openAPISpec(ctx *gin.Context) {
+	spec, err := openAPIJSON()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.Data(http.StatusOK, gin.MIMEJSON, spec)
+}
+
+// apiDocs serves Swagger UI for the spec on /openapi.json
+func (server *Server) apiDocs(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, gin.MIMEHTML+"; charset=utf-8", []byte(swaggerUIPage))
+}
@@ -0,0 +1,274 @@
+// api/docs_handler_test.go
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// undocumentedRoutes serve the documentation itself or are for scrapers, not API clients
+var undocumentedRoutes = []string{"/openapi.json", "/docs", "/metrics"}
+
+// requestBodySchemas maps each request body schema in openapi.yaml to the struct its handler binds
+var requestBodySchemas = map[string]any{
+	"LoginRequest":                   loginUserRequest{},
+	"AcceptInvitationRequest":        acceptInvitationRequest{},
+	"ForgotPasswordRequest":          forgotPasswordRequest{},
+	"ResetPasswordRequest":           resetPasswordRequest{},
+	"RefreshTokenRequest":            refreshTokenRequest{},
+	"ChangePasswordRequest":          changePasswordRequest{},
+	"UpdateAvailabilityRequest":      updateAvailabilityRequest{},
+	"AddUserSkillRequest":            addUserSkillRequest{},
+	"UpdateUserSkillRequest":         updateUserSkillRequest{},
+	"ReprocessResumeRequest":         reprocessResumeRequest{},
+	"CreateTeamRequest":              createTeamRequest{},
+	"CreateManagerInvitationRequest": createManagerInvitationRequest{},
+	"UpdateUserAdminRequest":         updateUserAdminRequest{},
+	"TransferUserRequest":            transferUserRequest{},
+	"ReassignTasksRequest":           reassignTasksRequest{},
+	"CreateSkillAdminRequest":        createSkillAdminRequest{},
+	"UpdateSkillRequest":             updateSkillBody{},
+	"MergeSkillRequest":              mergeSkillRequest{},
+	"CreateSkillAliasRequest":        createSkillAliasRequest{},
+	"BulkSkillAliasItem":             bulkSkillAliasItem{},
+	"AutoVerifySkillsRequest":        autoVerifySkillsRequest{},
+	"CreateWebhookRequest":           createWebhookRequest{},
+	"UpdateWebhookRequest":           updateWebhookRequest{},
+	"UpdateTeamSettingsRequest":      updateTeamSettingsRequest{},
+	"InviteEngineerRequest":          inviteEngineerRequest{},
+	"CreateProjectRequest":           createProjectRequest{},
+	"UpdateProjectRequest":           updateProjectBody{},
+	"CreateTaskRequest":              createTaskRequest{},
+	"BulkTaskItem":                   bulkTaskItem{},
+	"UpdateTaskRequest":              updateTaskBody{},
+	"AssignTaskRequest":              assignTaskRequest{},
+	"GetRecommendationsRequest":      getRecommendationsRequest{},
+	"DeclineTaskRequest":             declineTaskRequest{},
+	"CreateTaskCommentRequest":       createTaskCommentRequest{},
+}
+
+// queryParameterStructs maps operations to the named struct their handler binds the query string into
+var queryParameterStructs = []struct {
+	method string
+	path   string
+	query  any
+}{
+	{http.MethodGet, "/api/v1/admin/teams", listTeamsRequest{}},
+	{http.MethodGet, "/api/v1/admin/users", listUsersAdminRequest{}},
+	{http.MethodGet, "/api/v1/admin/invitations", listAdminInvitationsRequest{}},
+	{http.MethodGet, "/api/v1/admin/invitations/stats", invitationStatsRequest{}},
+	{http.MethodDelete, "/api/v1/admin/invitations/{id}", removeInvitationQuery{}},
+	{http.MethodGet, "/api/v1/admin/skills", listSkillsAdminRequest{}},
+	{http.MethodGet, "/api/v1/admin/skills/aliases", listAllSkillAliasesRequest{}},
+	{http.MethodGet, "/api/v1/admin/skills/unverified/ranked", listRankedUnverifiedSkillsRequest{}},
+	{http.MethodGet, "/api/v1/skills/search", searchSkillsRequest{}},
+	{http.MethodGet, "/api/v1/admin/webhooks", listWebhooksRequest{}},
+	{http.MethodGet, "/api/v1/manager/dashboard/trends", getDashboardTrendsRequest{}},
+	{http.MethodGet, "/api/v1/manager/team/members/{id}/history", getTeamMemberHistoryRequest{}},
+	{http.MethodGet, "/api/v1/manager/team/members/export", exportRequest{}},
+	{http.MethodGet, "/api/v1/manager/invitations", listSentInvitationsRequest{}},
+	{http.MethodDelete, "/api/v1/manager/invitations/{id}", removeInvitationQuery{}},
+	{http.MethodGet, "/api/v1/manager/projects", listProjectsRequest{}},
+	{http.MethodGet, "/api/v1/manager/board", getProjectBoardRequest{}},
+	{http.MethodGet, "/api/v1/manager/projects/{id}/tasks", listProjectTasksQueryRequest{}},
+	{http.MethodGet, "/api/v1/manager/projects/{id}/tasks/export", exportRequest{}},
+	{http.MethodGet, "/api/v1/tasks/{id}/comments", listTaskCommentsRequest{}},
+}
+
+func TestOpenAPISpec(t *testing.T) {
+	raw, err := openAPIJSON()
+	require.NoError(t, err)
+	var spec map[string]any
+	require.NoError(t, json.Unmarshal(raw, &spec))
+	paths := spec["paths"].(map[string]any)
+
+	t.Run("Every route is documented", func(t *testing.T) {
+		server := newTestServer(t, newUnreachableStore(t))
+		ginParam := regexp.MustCompile(`[:*](\w+)`)
+
+		for _, route := range server.router.Routes() {
+			if slices.Contains(undocumentedRoutes, route.Path) {
+				continue
+			}
+			path := ginParam.ReplaceAllString(route.Path, "{$1}")
+			item, ok := paths[path].(map[string]any)
+			require.True(t, ok, "%s is not in openapi.yaml", path)
+			_, ok = item[strings.ToLower(route.Method)]
+			require.True(t, ok, "%s %s is not in openapi.yaml", route.Method, path)
+		}
+	})
+
+	t.Run("Request bodies match their binding tags", func(t *testing.T) {
+		for path, item := range paths {
+			for method, operation := range item.(map[string]any) {
+				if method == "parameters" {
+					continue
+				}
+				body, ok := operation.(map[string]any)["requestBody"].(map[string]any)
+				if !ok {
+					continue
+				}
+				schema := body["content"].(map[string]any)["application/json"].(map[string]any)["schema"].(map[string]any)
+				if items, ok := schema["items"].(map[string]any); ok {
+					schema = items
+				}
+				ref := schema["$ref"].(string)
+				name := ref[strings.LastIndex(ref, "/")+1:]
+				goType, ok := requestBodySchemas[name]
+				require.True(t, ok, "%s %s: add %s to requestBodySchemas", method, path, name)
+
+				schema = resolveRef(t, spec, schema)
+				properties := schema["properties"].(map[string]any)
+				var required []any
+				if list, ok := schema["required"].([]any); ok {
+					required = list
+				}
+
+				fields := boundFields(reflect.TypeOf(goType), "json")
+				require.Len(t, properties, len(fields), "%s has a property its struct does not bind", name)
+				for _, field := range fields {
+					property, ok := properties[field.name].(map[string]any)
+					require.True(t, ok, "%s is missing property %s", name, field.name)
+					require.Equal(t, field.required(), slices.Contains(required, any(field.name)), "%s.%s required", name, field.name)
+					checkFieldRules(t, name, field, property)
+				}
+			}
+		}
+	})
+
+	t.Run("Query parameters match their binding tags", func(t *testing.T) {
+		for _, tc := range queryParameterStructs {
+			operation := paths[tc.path].(map[string]any)[strings.ToLower(tc.method)].(map[string]any)
+			parameters := map[string]map[string]any{}
+			for _, p := range operation["parameters"].([]any) {
+				parameter := resolveRef(t, spec, p.(map[string]any))
+				if parameter["in"] == "query" {
+					parameters[parameter["name"].(string)] = parameter
+				}
+			}
+
+			fields := boundFields(reflect.TypeOf(tc.query), "form")
+			require.Len(t, parameters, len(fields), "%s %s has a query parameter its struct does not bind", tc.method, tc.path)
+			for _, field := range fields {
+				parameter, ok := parameters[field.name]
+				require.True(t, ok, "%s %s is missing query parameter %s", tc.method, tc.path, field.name)
+				isRequired, _ := parameter["required"].(bool)
+				require.Equal(t, field.required(), isRequired, "%s %s %s required", tc.method, tc.path, field.name)
+				checkFieldRules(t, tc.path, field, parameter["schema"].(map[string]any))
+			}
+		}
+	})
+}
+
+func TestOpenAPIEndpoints(t *testing.T) {
+	server := newTestServer(t, newUnreachableStore(t))
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Header().Get("Content-Type"), "application/json")
+	var spec map[string]any
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &spec))
+	require.Equal(t, "3.0.3", spec["openapi"])
+
+	recorder = httptest.NewRecorder()
+	request = httptest.NewRequest(http.MethodGet, "/docs", nil)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Header().Get("Content-Type"), "text/html")
+	require.Contains(t, recorder.Body.String(), `url: "/openapi.json"`)
+}
+
+// boundField is one struct field a handler binds, with the validation rules from its binding tag
+type boundField struct {
+	name  string
+	kind  reflect.Kind
+	rules []string // Rules that apply to the field itself, i.e. those before any "dive"
+}
+
+func (f boundField) required() bool {
+	return slices.Contains(f.rules, "required")
+}
+
+// boundFields lists the fields of t named by the given tag ("json" or "form")
+func boundFields(t reflect.Type, tag string) []boundField {
+	var fields []boundField
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		goType := field.Type
+		if goType.Kind() == reflect.Pointer {
+			goType = goType.Elem()
+		}
+		rules := strings.Split(field.Tag.Get("binding"), ",")
+		if i := slices.Index(rules, "dive"); i >= 0 {
+			rules = rules[:i]
+		}
+		fields = append(fields, boundField{name: name, kind: goType.Kind(), rules: rules})
+	}
+	return fields
+}
+
+// checkFieldRules asserts the schema carries the field's min, max, oneof and email rules
+func checkFieldRules(t *testing.T, owner string, field boundField, schema map[string]any) {
+	t.Helper()
+
+	minKey, maxKey := "minimum", "maximum"
+	switch field.kind {
+	case reflect.String:
+		minKey, maxKey = "minLength", "maxLength"
+	case reflect.Slice:
+		minKey, maxKey = "minItems", "maxItems"
+	}
+
+	for _, rule := range field.rules {
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "min", "max":
+			schemaKey := map[string]string{"min": minKey, "max": maxKey}[key]
+			want, err := strconv.ParseFloat(value, 64)
+			require.NoError(t, err)
+			require.Equal(t, want, schema[schemaKey], "%s.%s %s", owner, field.name, schemaKey)
+		case "oneof":
+			var enum []any
+			for _, v := range strings.Fields(value) {
+				enum = append(enum, v)
+			}
+			require.Equal(t, enum, schema["enum"], "%s.%s enum", owner, field.name)
+		case "email":
+			require.Equal(t, "email", schema["format"], "%s.%s format", owner, field.name)
+		}
+	}
+}
+
+// resolveRef follows a local "$ref", returning node itself when it has none
+func resolveRef(t *testing.T, spec map[string]any, node map[string]any) map[string]any {
+	ref, ok := node["$ref"].(string)
+	if !ok {
+		return node
+	}
+
+	var current any = spec
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		next, ok := current.(map[string]any)[part]
+		require.True(t, ok, "unresolved $ref %s", ref)
+		current = next
+	}
+	return current.(map[string]any)
+}
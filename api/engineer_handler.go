@@ -3,9 +3,12 @@
 package api
 
 import (
+	"cmp"
 	"errors"
-	"log"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
@@ -19,29 +22,26 @@ import (
 
 // getCurrentTask retrieves the single task currently assigned and in-progress for the engineer.
 func (server *Server) getCurrentTask(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting getCurrentTask handler")
+	slog.Debug("Starting getCurrentTask handler")
 
-	// Extract user authentication information from request context
-	authPayload, err := getAuthorizationPayload(ctx)
+	// Extract the engineer's user ID from the request's token
+	engineerID, err := getUserIDFromPayload(ctx)
 	if err != nil {
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
-	
-	// Convert user ID from token payload to int64 for database queries
-	engineerID := int64(authPayload["user_id"].(float64))
 
 	// Query database for engineer's currently active task
 	task, err := server.store.GetCurrentTaskForEngineer(ctx, pgtype.Int8{Int64: engineerID, Valid: true})
 	if err != nil {
 		// Handle case where engineer has no active tasks
 		if errors.Is(err, pgx.ErrNoRows) {
-			log.Printf("DEBUG: No active task found for engineer %d", engineerID)
+			slog.Debug("No active task found for engineer", "engineer_id", engineerID)
 			ctx.JSON(http.StatusNoContent, nil) // Return 204 No Content as requested
 			return
 		}
 		// Handle database or other system errors
-		log.Printf("ERROR: Failed to get current task for engineer %d: %v", engineerID, err)
+		slog.Error("Failed to get current task for engineer", "engineer_id", engineerID, "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
@@ -50,9 +50,55 @@ func (server *Server) getCurrentTask(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, task)
 }
 
+// maxTaskDetailComments caps the comments embedded in task details; the full thread is paged
+// through GET /tasks/:id/comments
+const maxTaskDetailComments = 100
+
+// taskActivityEntry is one line of a task's activity log, ready to show as is
+type taskActivityEntry struct {
+	ID        int64                `json:"id"`
+	Event     db.TaskActivityEvent `json:"event"`
+	ActorID   *int64               `json:"actorId"`
+	Message   string               `json:"message"`
+	CreatedAt time.Time            `json:"createdAt"`
+}
+
+// describeTaskActivity turns an activity row into a sentence such as "Jane Doe assigned the task to John Roe".
+// Users who are unknown or since deleted show up as "Someone".
+func describeTaskActivity(activity db.ListTaskActivityRow) string {
+	actor := cmp.Or(activity.ActorName, "Someone")
+	assignee := cmp.Or(activity.AssigneeName, "someone")
+
+	switch activity.Event {
+	case db.TaskActivityEventCreated:
+		return actor + " created the task"
+	case db.TaskActivityEventAssigned:
+		return actor + " assigned the task to " + assignee
+	case db.TaskActivityEventReassigned:
+		return actor + " reassigned the task to " + assignee
+	case db.TaskActivityEventStatusChanged:
+		return fmt.Sprintf("%s changed the status from %s to %s",
+			actor, activity.FromStatus.TaskStatus, activity.ToStatus.TaskStatus)
+	case db.TaskActivityEventCompleted:
+		return actor + " completed the task"
+	case db.TaskActivityEventArchived:
+		return actor + " archived the task"
+	case db.TaskActivityEventPriorityEscalated:
+		// The scheduled job runs without an actor
+		if !activity.ActorID.Valid {
+			return fmt.Sprintf("The priority was escalated from %s to %s",
+				activity.FromPriority.TaskPriority, activity.ToPriority.TaskPriority)
+		}
+		return fmt.Sprintf("%s escalated the priority from %s to %s",
+			actor, activity.FromPriority.TaskPriority, activity.ToPriority.TaskPriority)
+	default:
+		return fmt.Sprintf("%s: %s", actor, activity.Event)
+	}
+}
+
 // getTaskDetails retrieves full, rich details for any single task, as long as it belongs to the engineer's team.
 func (server *Server) getTaskDetails(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting getTaskDetails handler")
+	slog.Debug("Starting getTaskDetails handler")
 
 	// Parse task ID from URL path parameters
 	var uriReq struct {
@@ -63,21 +109,21 @@ func (server *Server) getTaskDetails(ctx *gin.Context) {
 		return
 	}
 
-	// Extract team ID from engineer's authentication token
-	authPayload, _ := getAuthorizationPayload(ctx)
-	teamID := int64(authPayload["team_id"].(float64))
-
-	// Retrieve task from database to validate existence
-	task, err := server.store.GetTask(ctx, uriReq.ID)
+	// Resolve the engineer's current team; the token's team_id may be stale
+	engineerID, err := getUserIDFromPayload(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, errorResponse(errors.New("task not found")))
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	teamID, err := server.currentTeamID(ctx, engineerID)
+	if err != nil {
+		ctx.JSON(teamScopeErrorStatus(err), errorResponse(err))
 		return
 	}
 
-	// Get project information to verify team ownership
-	project, err := server.store.GetProject(ctx, task.ProjectID.Int64)
-	if err != nil || project.TeamID != teamID {
-		ctx.JSON(http.StatusForbidden, errorResponse(errors.New("you do not have permission to view this task")))
+	// Verify the task exists and belongs to the engineer's team
+	if _, err := server.assertTaskInTeam(ctx, uriReq.ID, teamID); err != nil {
+		ctx.JSON(teamScopeErrorStatus(err), errorResponse(err))
 		return
 	}
 
@@ -105,6 +151,39 @@ func (server *Server) getTaskDetails(ctx *gin.Context) {
 		skillsRsp[i] = skillResponse{ID: s.ID, SkillName: s.SkillName}
 	}
 
+	// The activity log records who did what to the task, newest first
+	activities, err := server.store.ListTaskActivity(ctx, uriReq.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	activityLog := make([]taskActivityEntry, len(activities))
+	for i, activity := range activities {
+		activityLog[i] = taskActivityEntry{
+			ID:        activity.ID,
+			Event:     activity.Event,
+			Message:   describeTaskActivity(activity),
+			CreatedAt: activity.CreatedAt.Time,
+		}
+		if activity.ActorID.Valid {
+			activityLog[i].ActorID = &activity.ActorID.Int64
+		}
+	}
+
+	// The task's discussion, oldest first
+	comments, err := server.store.ListTaskCommentsByTask(ctx, db.ListTaskCommentsByTaskParams{
+		TaskID: uriReq.ID,
+		Limit:  maxTaskDetailComments,
+		Offset: 0,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if comments == nil {
+		comments = []db.ListTaskCommentsByTaskRow{}
+	}
+
 	// Construct comprehensive task response with all relevant details
 	response := gin.H{
 		"id":             taskDetails.ID,
@@ -112,7 +191,8 @@ func (server *Server) getTaskDetails(ctx *gin.Context) {
 		"description":    taskDetails.Description.String,
 		"projectName":    taskDetails.ProjectName,
 		"requiredSkills": skillsRsp,
-		"activityLog":    []string{}, // Return empty log for now as planned
+		"activityLog":    activityLog,
+		"comments":       comments,
 	}
 
 	ctx.JSON(http.StatusOK, response)
@@ -120,7 +200,7 @@ func (server *Server) getTaskDetails(ctx *gin.Context) {
 
 // completeTask marks the engineer's currently assigned task as 'done'.
 func (server *Server) completeTask(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting completeTask handler")
+	slog.Debug("Starting completeTask handler")
 
 	// Parse task ID from URL path parameters
 	var uriReq struct {
@@ -132,8 +212,11 @@ func (server *Server) completeTask(ctx *gin.Context) {
 	}
 
 	// Extract engineer ID from authentication token
-	authPayload, _ := getAuthorizationPayload(ctx)
-	engineerID := int64(authPayload["user_id"].(float64))
+	engineerID, err := getUserIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
 
 	// Retrieve task to validate assignment and ownership
 	taskToComplete, err := server.store.GetTask(ctx, uriReq.ID)
@@ -151,23 +234,302 @@ func (server *Server) completeTask(ctx *gin.Context) {
 	// Execute task completion transaction (updates task status and engineer availability)
 	result, err := server.store.CompleteTaskTx(ctx, db.CompleteTaskTxParams{TaskID: uriReq.ID})
 	if err != nil {
-		log.Printf("ERROR: Failed to complete task %d: %v", uriReq.ID, err)
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
+		slog.Error("Failed to complete task", "task_id", uriReq.ID, "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
+	// Let the team's webhooks know; they are delivered in the background
+	server.fireTaskCompleted(ctx, result.CompletedTask)
+
 	// Log successful completion and return updated task data
-	log.Printf("DEBUG: Engineer %d completed task %d", engineerID, uriReq.ID)
+	slog.Debug("Engineer completed task", "engineer_id", engineerID, "task_id", uriReq.ID)
 	ctx.JSON(http.StatusOK, result.CompletedTask)
 }
 
+type declineTaskRequest struct {
+	Reason string `json:"reason" binding:"max=500"` // Optional explanation for the manager
+}
+
+// declineTask hands the engineer's assigned task back to the team, reopening it and freeing the engineer.
+func (server *Server) declineTask(ctx *gin.Context) {
+	slog.Debug("Starting declineTask handler")
+
+	// Parse task ID from URL path parameters
+	var uriReq struct {
+		ID int64 `uri:"id" binding:"required,min=1"`
+	}
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// The body with a reason is optional
+	var req declineTaskRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	// Extract engineer ID from authentication token
+	engineerID, err := getUserIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	// Retrieve task to validate assignment and ownership
+	taskToDecline, err := server.store.GetTask(ctx, uriReq.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("task not found")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Verify that the requesting engineer is actually assigned to this task
+	if !taskToDecline.AssigneeID.Valid || taskToDecline.AssigneeID.Int64 != engineerID {
+		ctx.JSON(http.StatusForbidden, errorResponse(errors.New("you can only decline tasks assigned to you")))
+		return
+	}
+
+	// Reopen the task and make the engineer available in one transaction
+	result, err := server.store.DeclineTaskTx(ctx, db.DeclineTaskTxParams{
+		TaskID:     uriReq.ID,
+		EngineerID: engineerID,
+	})
+	if err != nil {
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
+		if errors.Is(err, db.ErrTaskNotAssignedToUser) {
+			ctx.JSON(http.StatusConflict, errorResponse(errors.New("only in-progress tasks can be declined")))
+			return
+		}
+		slog.Error("Failed to decline task", "task_id", uriReq.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// There is no activity log yet, so the decline and its reason are recorded in the server log
+	slog.Info("Engineer declined task", "engineer_id", engineerID, "task_id", uriReq.ID, "reason", req.Reason)
+	ctx.JSON(http.StatusOK, result.Task)
+}
+
+// pauseTask puts the engineer's in-progress task on hold. The task returns to open but stays
+// assigned to them, and they become available unless another task is still in progress.
+func (server *Server) pauseTask(ctx *gin.Context) {
+	slog.Debug("Starting pauseTask handler")
+
+	taskID, engineerID, ok := server.bindOwnTask(ctx, "you can only pause tasks assigned to you")
+	if !ok {
+		return
+	}
+
+	// Pause the task and reconcile the engineer's availability in one transaction
+	result, err := server.store.PauseTaskTx(ctx, db.PauseTaskTxParams{
+		TaskID:     taskID,
+		EngineerID: engineerID,
+	})
+	if err != nil {
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
+		if errors.Is(err, db.ErrTaskNotAssignedToUser) {
+			ctx.JSON(http.StatusConflict, errorResponse(errors.New("only in-progress tasks can be paused")))
+			return
+		}
+		slog.Error("Failed to pause task", "task_id", taskID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	slog.Info("Engineer paused task", "engineer_id", engineerID, "task_id", taskID)
+	ctx.JSON(http.StatusOK, result.Task)
+}
+
+// resumeTask picks a paused task back up, provided the engineer has nothing else in progress.
+func (server *Server) resumeTask(ctx *gin.Context) {
+	slog.Debug("Starting resumeTask handler")
+
+	taskID, engineerID, ok := server.bindOwnTask(ctx, "you can only resume tasks assigned to you")
+	if !ok {
+		return
+	}
+
+	// Resume the task and mark the engineer busy in one transaction
+	result, err := server.store.ResumeTaskTx(ctx, db.PauseTaskTxParams{
+		TaskID:     taskID,
+		EngineerID: engineerID,
+	})
+	if err != nil {
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, db.ErrTaskNotPausedByUser):
+			ctx.JSON(http.StatusConflict, errorResponse(errors.New("only paused tasks can be resumed")))
+		case errors.Is(err, db.ErrEngineerNotAvailable):
+			ctx.JSON(http.StatusConflict, errorResponse(errors.New("finish or pause your current task first")))
+		case errors.Is(err, db.ErrEngineerOnLeave):
+			ctx.JSON(http.StatusConflict, errorResponse(errors.New("set your availability back to available first")))
+		default:
+			slog.Error("Failed to resume task", "task_id", taskID, "error", err)
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	slog.Info("Engineer resumed task", "engineer_id", engineerID, "task_id", taskID)
+	ctx.JSON(http.StatusOK, result.Task)
+}
+
+// claimTask lets an available engineer take an open, unassigned task of their team.
+// The task goes in progress under them and they become busy, as if a manager had assigned it.
+func (server *Server) claimTask(ctx *gin.Context) {
+	slog.Debug("Starting claimTask handler")
+
+	// Parse task ID from URL path parameters
+	var uriReq struct {
+		ID int64 `uri:"id" binding:"required,min=1"`
+	}
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// Resolve the engineer's current team; the token's team_id may be stale
+	engineerID, err := getUserIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	teamID, err := server.currentTeamID(ctx, engineerID)
+	if err != nil {
+		ctx.JSON(teamScopeErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	// Verify the task exists and belongs to the engineer's team
+	if _, err := server.assertTaskInTeam(ctx, uriReq.ID, teamID); err != nil {
+		ctx.JSON(teamScopeErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	// Claim the task and mark the engineer busy; the transaction re-checks both under lock
+	result, err := server.store.ClaimTaskTx(ctx, db.ClaimTaskTxParams{
+		TaskID:     uriReq.ID,
+		EngineerID: engineerID,
+		TeamID:     teamID,
+	})
+	if err != nil {
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, db.ErrTaskNotClaimable):
+			ctx.JSON(http.StatusConflict, errorResponse(errors.New("task is no longer open for claiming")))
+		case errors.Is(err, db.ErrEngineerNotAvailable):
+			ctx.JSON(http.StatusConflict, errorResponse(errors.New("finish or pause your current task first")))
+		case errors.Is(err, db.ErrEngineerOnLeave):
+			ctx.JSON(http.StatusConflict, errorResponse(errors.New("set your availability back to available first")))
+		default:
+			slog.Error("Failed to claim task", "task_id", uriReq.ID, "error", err)
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	slog.Info("Engineer claimed task", "engineer_id", engineerID, "task_id", uriReq.ID)
+	ctx.JSON(http.StatusOK, result.Task)
+}
+
+// bindOwnTask reads the task ID from the URL and checks that the task is assigned to the
+// requesting engineer. On failure it writes the response and returns ok=false.
+func (server *Server) bindOwnTask(ctx *gin.Context, forbiddenMsg string) (taskID, engineerID int64, ok bool) {
+	var uriReq struct {
+		ID int64 `uri:"id" binding:"required,min=1"`
+	}
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return 0, 0, false
+	}
+
+	engineerID, err := getUserIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return 0, 0, false
+	}
+
+	task, err := server.store.GetTask(ctx, uriReq.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("task not found")))
+			return 0, 0, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return 0, 0, false
+	}
+
+	if !task.AssigneeID.Valid || task.AssigneeID.Int64 != engineerID {
+		ctx.JSON(http.StatusForbidden, errorResponse(errors.New(forbiddenMsg)))
+		return 0, 0, false
+	}
+
+	return uriReq.ID, engineerID, true
+}
+
+// listMatchingTasks lists open, unassigned team tasks that fit the engineer's skills, best matches first.
+func (server *Server) listMatchingTasks(ctx *gin.Context) {
+	slog.Debug("Starting listMatchingTasks handler")
+
+	// Parse optional result limit from query string
+	var queryReq struct {
+		Limit int32 `form:"limit" binding:"omitempty,min=1,max=50"`
+	}
+	if err := ctx.ShouldBindQuery(&queryReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// Resolve the engineer's current team; the token's team_id may be stale
+	engineerID, err := getUserIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	teamID, err := server.currentTeamID(ctx, engineerID)
+	if err != nil {
+		ctx.JSON(teamScopeErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	// Query open tasks ranked by skill overlap and proficiency
+	tasks, err := server.skillMatcher.TasksForEngineer(ctx, engineerID, teamID, queryReq.Limit)
+	if err != nil {
+		slog.Error("Failed to list matching tasks for engineer", "engineer_id", engineerID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	slog.Debug("Found matching tasks for engineer", "count", len(tasks), "engineer_id", engineerID)
+	ctx.JSON(http.StatusOK, tasks)
+}
+
 ////////////////////////////////////////////////////////////////////////
 // Engineer Project & History Handlers
 ////////////////////////////////////////////////////////////////////////
 
 // listProjectTasksForEngineer retrieves a read-only list of all tasks for a specific project.
 func (server *Server) listProjectTasksForEngineer(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting listProjectTasksForEngineer handler")
+	slog.Debug("Starting listProjectTasksForEngineer handler")
 
 	// Parse project ID from URL path parameters
 	var uriReq struct {
@@ -178,24 +540,22 @@ func (server *Server) listProjectTasksForEngineer(ctx *gin.Context) {
 		return
 	}
 
-	// Extract team ID from engineer's authentication token
-	authPayload, _ := getAuthorizationPayload(ctx)
-	teamID := int64(authPayload["team_id"].(float64))
-
-	// Retrieve project information to validate existence and team membership
-	project, err := server.store.GetProject(ctx, uriReq.ID)
+	// Resolve the engineer's current team; the token's team_id may be stale
+	engineerID, err := getUserIDFromPayload(ctx)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("project not found")))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	teamID, err := server.currentTeamID(ctx, engineerID)
+	if err != nil {
+		ctx.JSON(teamScopeErrorStatus(err), errorResponse(err))
 		return
 	}
 
-	// Verify engineer belongs to the same team as the project
-	if project.TeamID != teamID {
-		ctx.JSON(http.StatusForbidden, errorResponse(errors.New("you do not have permission to view tasks for this project")))
+	// Retrieve the project only if it belongs to the engineer's team
+	project, err := server.assertProjectInTeam(ctx, uriReq.ID, teamID)
+	if err != nil {
+		ctx.JSON(teamScopeErrorStatus(err), errorResponse(err))
 		return
 	}
 
@@ -216,7 +576,7 @@ func (server *Server) listProjectTasksForEngineer(ctx *gin.Context) {
 
 // getTaskHistory retrieves a paginated list of the engineer's completed tasks.
 func (server *Server) getTaskHistory(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting getTaskHistory handler")
+	slog.Debug("Starting getTaskHistory handler")
 
 	// Parse pagination and search parameters from query string
 	var queryReq struct {
@@ -230,8 +590,11 @@ func (server *Server) getTaskHistory(ctx *gin.Context) {
 	}
 
 	// Extract engineer ID from authentication token
-	authPayload, _ := getAuthorizationPayload(ctx)
-	engineerID := int64(authPayload["user_id"].(float64))
+	engineerID, err := getUserIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
 
 	// Prepare search query with wildcard pattern for database ILIKE operation
 	searchQuery := "%"
@@ -269,3 +632,64 @@ func (server *Server) getTaskHistory(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, response)
 }
+
+////////////////////////////////////////////////////////////////////////
+// Engineer Team Handlers
+////////////////////////////////////////////////////////////////////////
+
+// managerContact is the subset of a manager's profile an engineer may see.
+type managerContact struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// teamManagerResponse carries the engineer's team and its manager, if one is assigned.
+type teamManagerResponse struct {
+	TeamID   int64           `json:"team_id"`
+	TeamName string          `json:"team_name"`
+	Manager  *managerContact `json:"manager"` // null when the team has no manager
+}
+
+// getTeamManager returns the contact details of the manager of the engineer's own team.
+func (server *Server) getTeamManager(ctx *gin.Context) {
+	slog.Debug("Starting getTeamManager handler")
+
+	// Resolve the engineer's current team; the token's team_id may be stale
+	engineerID, err := getUserIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	teamID, err := server.currentTeamID(ctx, engineerID)
+	if err != nil {
+		ctx.JSON(teamScopeErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	// Look up the team and its manager in one query
+	contact, err := server.store.GetTeamManagerContact(ctx, teamID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("team not found")))
+			return
+		}
+		slog.Error("Failed to get manager for team", "team_id", teamID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := teamManagerResponse{
+		TeamID:   contact.TeamID,
+		TeamName: contact.TeamName,
+	}
+	if contact.ManagerID.Valid {
+		response.Manager = &managerContact{
+			ID:    contact.ManagerID.Int64,
+			Name:  contact.ManagerName.String,
+			Email: contact.ManagerEmail.String,
+		}
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
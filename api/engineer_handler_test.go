@@ -0,0 +1,351 @@
+// api/engineer_handler_test.go
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pranav244872/synapse/db/sqlc"
+	"github.com/pranav244872/synapse/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeclineTask(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	// Arrange: a task assigned to one of two engineers on the same team
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	engineer := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+	otherEngineer := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+
+	project, err := store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: team.ID})
+	require.NoError(t, err)
+	task, err := store.CreateTask(ctx, db.CreateTaskParams{
+		ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+		Title:     util.RandomName(),
+		Status:    db.TaskStatusOpen,
+		Priority:  db.TaskPriorityMedium,
+	})
+	require.NoError(t, err)
+	_, err = store.AssignTaskToUser(ctx, db.AssignTaskToUserTxParams{TaskID: task.ID, UserID: engineer.ID})
+	require.NoError(t, err)
+
+	server := newTestServer(t, store)
+	decline := func(t *testing.T, userID int64) *httptest.ResponseRecorder {
+		body, err := json.Marshal(gin.H{"reason": "missing the required skills"})
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		url := fmt.Sprintf("/api/v1/engineer/tasks/%d/decline", task.ID)
+		request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+		require.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		addAuthorization(t, request, server, userID, db.UserRoleEngineer, team.ID)
+
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	t.Run("Someone else's task is rejected", func(t *testing.T) {
+		// Act
+		recorder := decline(t, otherEngineer.ID)
+
+		// Assert
+		require.Equal(t, http.StatusForbidden, recorder.Code)
+
+		unchanged, err := store.GetTask(ctx, task.ID)
+		require.NoError(t, err)
+		require.Equal(t, engineer.ID, unchanged.AssigneeID.Int64)
+	})
+
+	t.Run("Assigned task is reopened", func(t *testing.T) {
+		// Act
+		recorder := decline(t, engineer.ID)
+
+		// Assert
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		reopened, err := store.GetTask(ctx, task.ID)
+		require.NoError(t, err)
+		require.Equal(t, db.TaskStatusOpen, reopened.Status)
+		require.False(t, reopened.AssigneeID.Valid)
+
+		freed, err := store.GetUser(ctx, engineer.ID)
+		require.NoError(t, err)
+		require.Equal(t, db.AvailabilityStatusAvailable, freed.Availability)
+	})
+}
+
+func TestClaimTask(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	// Arrange: an open task, two engineers on its team and one on another team
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	engineer := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+	otherEngineer := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+	otherTeam, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	outsider := createTestUser(t, store, db.UserRoleEngineer, otherTeam.ID)
+
+	project, err := store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: team.ID})
+	require.NoError(t, err)
+	task, err := store.CreateTask(ctx, db.CreateTaskParams{
+		ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+		Title:     util.RandomName(),
+		Status:    db.TaskStatusOpen,
+		Priority:  db.TaskPriorityMedium,
+	})
+	require.NoError(t, err)
+
+	server := newTestServer(t, store)
+	claim := func(t *testing.T, user db.User) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		url := fmt.Sprintf("/api/v1/engineer/tasks/%d/claim", task.ID)
+		request, err := http.NewRequest(http.MethodPost, url, nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server, user.ID, db.UserRoleEngineer, user.TeamID.Int64)
+
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	t.Run("Another team's task is rejected", func(t *testing.T) {
+		require.Equal(t, http.StatusForbidden, claim(t, outsider).Code)
+	})
+
+	t.Run("Transaction timeout answers 504", func(t *testing.T) {
+		store.SetTxTimeout(time.Nanosecond)
+		defer store.SetTxTimeout(db.DefaultTxTimeout)
+
+		recorder := claim(t, engineer)
+		require.Equal(t, http.StatusGatewayTimeout, recorder.Code)
+		require.Contains(t, recorder.Body.String(), errOperationTimedOut.Error())
+
+		unchanged, err := store.GetTask(ctx, task.ID)
+		require.NoError(t, err)
+		require.Equal(t, db.TaskStatusOpen, unchanged.Status)
+	})
+
+	t.Run("Open task is claimed", func(t *testing.T) {
+		recorder := claim(t, engineer)
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+		claimed, err := store.GetTask(ctx, task.ID)
+		require.NoError(t, err)
+		require.Equal(t, db.TaskStatusInProgress, claimed.Status)
+		require.Equal(t, engineer.ID, claimed.AssigneeID.Int64)
+
+		busy, err := store.GetUser(ctx, engineer.ID)
+		require.NoError(t, err)
+		require.Equal(t, db.AvailabilityStatusBusy, busy.Availability)
+	})
+
+	t.Run("Claimed task conflicts", func(t *testing.T) {
+		require.Equal(t, http.StatusConflict, claim(t, otherEngineer).Code)
+	})
+}
+
+func TestGetTeamManager(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	server := newTestServer(t, store)
+
+	getManager := func(t *testing.T, userID, teamID int64) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/api/v1/engineer/team/manager", nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server, userID, db.UserRoleEngineer, teamID)
+
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	t.Run("Team with a manager returns their contact", func(t *testing.T) {
+		// Arrange
+		manager := createTestUser(t, store, db.UserRoleManager, 0)
+		team, err := store.CreateTeam(ctx, db.CreateTeamParams{
+			TeamName:  util.RandomName(),
+			ManagerID: pgtype.Int8{Int64: manager.ID, Valid: true},
+		})
+		require.NoError(t, err)
+		engineer := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+
+		// Act
+		recorder := getManager(t, engineer.ID, team.ID)
+
+		// Assert
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var response teamManagerResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+		require.Equal(t, team.ID, response.TeamID)
+		require.NotNil(t, response.Manager)
+		require.Equal(t, manager.ID, response.Manager.ID)
+		require.Equal(t, manager.Name.String, response.Manager.Name)
+		require.Equal(t, manager.Email, response.Manager.Email)
+	})
+
+	t.Run("Team without a manager returns null", func(t *testing.T) {
+		// Arrange
+		team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+		require.NoError(t, err)
+		engineer := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+
+		// Act
+		recorder := getManager(t, engineer.ID, team.ID)
+
+		// Assert
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.Contains(t, recorder.Body.String(), `"manager":null`)
+	})
+
+	t.Run("Engineer without a team is rejected", func(t *testing.T) {
+		// Arrange
+		engineer := createTestUser(t, store, db.UserRoleEngineer, 0)
+
+		// Act
+		recorder := getManager(t, engineer.ID, 0)
+
+		// Assert
+		require.Equal(t, http.StatusForbidden, recorder.Code)
+	})
+}
+
+func TestEngineerStaleTeamToken(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	// Arrange: an engineer whose token was issued while they were on the old team
+	oldTeam, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	newTeam, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	engineer := createTestUser(t, store, db.UserRoleEngineer, oldTeam.ID)
+
+	project, err := store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: oldTeam.ID})
+	require.NoError(t, err)
+	task, err := store.CreateTask(ctx, db.CreateTaskParams{
+		ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+		Title:     util.RandomName(),
+		Status:    db.TaskStatusOpen,
+		Priority:  db.TaskPriorityMedium,
+	})
+	require.NoError(t, err)
+
+	server := newTestServer(t, store)
+	get := func(t *testing.T, url string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server, engineer.ID, db.UserRoleEngineer, oldTeam.ID)
+
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+	projectTasksURL := fmt.Sprintf("/api/v1/engineer/projects/%d/tasks", project.ID)
+	taskURL := fmt.Sprintf("/api/v1/engineer/tasks/%d", task.ID)
+
+	// While still on the team, the old team's project is visible
+	require.Equal(t, http.StatusOK, get(t, projectTasksURL).Code)
+	require.Equal(t, http.StatusOK, get(t, taskURL).Code)
+
+	// Act: move the engineer to another team without issuing a new token
+	_, err = store.UpdateUser(ctx, db.UpdateUserParams{
+		ID:     engineer.ID,
+		TeamID: pgtype.Int8{Int64: newTeam.ID, Valid: true},
+	})
+	require.NoError(t, err)
+
+	// Assert: the stale team_id in the token no longer grants access
+	require.Equal(t, http.StatusNotFound, get(t, projectTasksURL).Code)
+	require.Equal(t, http.StatusForbidden, get(t, taskURL).Code)
+}
+
+func TestDescribeTaskActivity(t *testing.T) {
+	status := func(s db.TaskStatus) db.NullTaskStatus {
+		return db.NullTaskStatus{TaskStatus: s, Valid: true}
+	}
+	priority := func(p db.TaskPriority) db.NullTaskPriority {
+		return db.NullTaskPriority{TaskPriority: p, Valid: true}
+	}
+
+	testCases := []struct {
+		name     string
+		activity db.ListTaskActivityRow
+		expected string
+	}{
+		{
+			name:     "Created",
+			activity: db.ListTaskActivityRow{Event: db.TaskActivityEventCreated, ActorName: "Ada"},
+			expected: "Ada created the task",
+		},
+		{
+			name:     "Assigned",
+			activity: db.ListTaskActivityRow{Event: db.TaskActivityEventAssigned, ActorName: "Ada", AssigneeName: "Grace"},
+			expected: "Ada assigned the task to Grace",
+		},
+		{
+			name:     "Reassigned",
+			activity: db.ListTaskActivityRow{Event: db.TaskActivityEventReassigned, ActorName: "Ada", AssigneeName: "Linus"},
+			expected: "Ada reassigned the task to Linus",
+		},
+		{
+			name: "StatusChanged",
+			activity: db.ListTaskActivityRow{
+				Event:      db.TaskActivityEventStatusChanged,
+				ActorName:  "Grace",
+				FromStatus: status(db.TaskStatusInProgress),
+				ToStatus:   status(db.TaskStatusOpen),
+			},
+			expected: "Grace changed the status from in_progress to open",
+		},
+		{
+			name:     "Completed",
+			activity: db.ListTaskActivityRow{Event: db.TaskActivityEventCompleted, ActorName: "Grace"},
+			expected: "Grace completed the task",
+		},
+		{
+			name:     "ArchivedByDeletedActor",
+			activity: db.ListTaskActivityRow{Event: db.TaskActivityEventArchived},
+			expected: "Someone archived the task",
+		},
+		{
+			name: "EscalatedByAdmin",
+			activity: db.ListTaskActivityRow{
+				Event:        db.TaskActivityEventPriorityEscalated,
+				ActorID:      pgtype.Int8{Int64: 1, Valid: true},
+				ActorName:    "Ada",
+				FromPriority: priority(db.TaskPriorityLow),
+				ToPriority:   priority(db.TaskPriorityMedium),
+			},
+			expected: "Ada escalated the priority from low to medium",
+		},
+		{
+			name: "EscalatedBySchedule",
+			activity: db.ListTaskActivityRow{
+				Event:        db.TaskActivityEventPriorityEscalated,
+				FromPriority: priority(db.TaskPriorityMedium),
+				ToPriority:   priority(db.TaskPriorityHigh),
+			},
+			expected: "The priority was escalated from medium to high",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, describeTaskActivity(tc.activity))
+		})
+	}
+}
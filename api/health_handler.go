@@ -0,0 +1,161 @@
+// api/health_handler.go
+
+package api
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranav244872/synapse/config"
+	"github.com/pranav244872/synapse/skillz"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Dependency Configuration Self-Check
+////////////////////////////////////////////////////////////////////////
+
+// dependencyCheck reports which settings an external dependency is missing
+type dependencyCheck struct {
+	Name        string   `json:"name"`
+	Missing     []string `json:"missing,omitempty"`
+	Unreachable string   `json:"unreachable,omitempty"` // Set by readyz when the endpoint could not be reached
+	URL         string   `json:"-"`
+}
+
+// configured reports whether every setting the dependency needs is present
+func (c dependencyCheck) configured() bool {
+	return len(c.Missing) == 0
+}
+
+// checkDependencyConfig inspects the LLM and recommender settings without calling either service
+func checkDependencyConfig(cfg config.Config) []dependencyCheck {
+	var llm dependencyCheck
+	if cfg.LLMProvider == skillz.ProviderOpenAI {
+		llm = dependencyCheck{Name: "llm", URL: cmp.Or(cfg.OpenAIBaseURL, skillz.DefaultOpenAIBaseURL)}
+		if cfg.OpenAIAPIKey == "" {
+			llm.Missing = append(llm.Missing, "OPENAI_API_KEY")
+		}
+		if cfg.OpenAIModel == "" {
+			llm.Missing = append(llm.Missing, "OPENAI_MODEL")
+		}
+	} else {
+		llm = dependencyCheck{Name: "llm", URL: cfg.GeminiAPIURL}
+		if cfg.GeminiAPIURL == "" {
+			llm.Missing = append(llm.Missing, "GEMINI_API_URL")
+		}
+		if cfg.GeminiAPIKey == "" {
+			llm.Missing = append(llm.Missing, "GEMINI_API_KEY")
+		}
+	}
+
+	recommender := dependencyCheck{Name: "recommender", URL: cfg.RecommenderAPIURL}
+	if cfg.RecommenderAPIURL == "" {
+		recommender.Missing = append(recommender.Missing, "RECOMMENDER_API_URL")
+	}
+	if cfg.RecommenderAPIKey == "" {
+		recommender.Missing = append(recommender.Missing, "RECOMMENDER_API_KEY")
+	}
+
+	return []dependencyCheck{llm, recommender}
+}
+
+// logDependencyChecks warns about every incomplete dependency and reports whether all are configured
+func logDependencyChecks(checks []dependencyCheck) bool {
+	allConfigured := true
+	for _, check := range checks {
+		if !check.configured() {
+			allConfigured = false
+			slog.Warn("Dependency is not fully configured; endpoints that use it will fail", "dependency", check.Name, "missing", check.Missing)
+		}
+	}
+	return allConfigured
+}
+
+////////////////////////////////////////////////////////////////////////
+// Liveness Endpoint
+////////////////////////////////////////////////////////////////////////
+
+// healthz reports that the process is up. It checks no dependencies, so a
+// database outage never gets a healthy server restarted.
+func (server *Server) healthz(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+////////////////////////////////////////////////////////////////////////
+// Readiness Endpoint
+////////////////////////////////////////////////////////////////////////
+
+const readinessPingTimeout = 2 * time.Second
+
+// readyz reports whether the server can serve traffic.
+// An unreachable database makes the server unready; incomplete dependency settings only mark it degraded.
+// With READINESS_PROBE_DEPENDENCIES set, an unreachable LLM or recommender endpoint also makes it unready.
+// The "failed" field lists every dependency that made the check fail.
+func (server *Server) readyz(ctx *gin.Context) {
+	probeCtx, cancel := context.WithTimeout(ctx, readinessPingTimeout)
+	defer cancel()
+
+	failed := []string{}
+	database := "ok"
+	if err := server.store.Ping(probeCtx); err != nil {
+		slog.Error("Readiness check failed to reach the database", "error", err)
+		database = err.Error()
+		failed = append(failed, "database")
+	}
+
+	checks := make([]dependencyCheck, len(server.dependencies))
+	copy(checks, server.dependencies)
+
+	status := "ready"
+	for i := range checks {
+		if !checks[i].configured() {
+			status = "degraded"
+			continue
+		}
+		if !server.config.ReadinessProbeDependencies {
+			continue
+		}
+		if err := probeEndpoint(probeCtx, checks[i].URL); err != nil {
+			slog.Error("Readiness check failed to reach dependency", "dependency", checks[i].Name, "error", err)
+			checks[i].Unreachable = err.Error()
+			failed = append(failed, checks[i].Name)
+		}
+	}
+
+	if len(failed) > 0 {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":       "unavailable",
+			"database":     database,
+			"dependencies": checks,
+			"failed":       failed,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":       status,
+		"database":     database,
+		"dependencies": checks,
+		"failed":       failed,
+	})
+}
+
+// probeEndpoint reports whether anything answers HTTP at url.
+// Any response counts, since the services reject unauthenticated GETs.
+func probeEndpoint(ctx context.Context, url string) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	return nil
+}
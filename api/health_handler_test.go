@@ -0,0 +1,194 @@
+// api/health_handler_test.go
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pranav244872/synapse/config"
+	db "github.com/pranav244872/synapse/db/sqlc"
+	"github.com/pranav244872/synapse/skillz"
+	"github.com/pranav244872/synapse/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckDependencyConfig(t *testing.T) {
+	complete := config.Config{
+		GeminiAPIURL:      "https://llm.example.com",
+		GeminiAPIKey:      "gemini-key",
+		RecommenderAPIURL: "http://recommender:8000",
+		RecommenderAPIKey: "recommender-key",
+	}
+
+	t.Run("Missing Gemini key is reported", func(t *testing.T) {
+		// Arrange
+		cfg := complete
+		cfg.GeminiAPIKey = ""
+
+		// Act
+		checks := checkDependencyConfig(cfg)
+
+		// Assert
+		require.Len(t, checks, 2)
+		require.Equal(t, "llm", checks[0].Name)
+		require.False(t, checks[0].configured())
+		require.Equal(t, []string{"GEMINI_API_KEY"}, checks[0].Missing)
+		require.True(t, checks[1].configured())
+		require.False(t, logDependencyChecks(checks))
+	})
+
+	t.Run("Complete configuration passes", func(t *testing.T) {
+		checks := checkDependencyConfig(complete)
+		for _, check := range checks {
+			require.True(t, check.configured(), check.Name)
+		}
+		require.True(t, logDependencyChecks(checks))
+	})
+
+	t.Run("OpenAI provider needs its own settings", func(t *testing.T) {
+		cfg := complete
+		cfg.LLMProvider = skillz.ProviderOpenAI
+		cfg.OpenAIModel = "gpt-4o-mini"
+
+		checks := checkDependencyConfig(cfg)
+
+		require.Equal(t, []string{"OPENAI_API_KEY"}, checks[0].Missing)
+		require.Equal(t, skillz.DefaultOpenAIBaseURL, checks[0].URL)
+	})
+
+	t.Run("Startup fails when dependencies are required", func(t *testing.T) {
+		cfg := config.Config{
+			TokenSymmetricKey:   util.RandomString(32),
+			AccessTokenDuration: time.Minute,
+			RequireDependencies: true,
+		}
+
+		_, err := NewServer(cfg, nil, nil)
+		require.Error(t, err)
+	})
+}
+
+func TestReadyz(t *testing.T) {
+	// Arrange: no dependency settings and a database that cannot be reached
+	server := newTestServer(t, newUnreachableStore(t))
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/readyz", nil)
+	require.NoError(t, err)
+
+	// Act
+	server.router.ServeHTTP(recorder, request)
+
+	// Assert
+	require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	var body struct {
+		Status       string            `json:"status"`
+		Dependencies []dependencyCheck `json:"dependencies"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	require.Equal(t, "unavailable", body.Status)
+	require.Len(t, body.Dependencies, 2)
+	require.Contains(t, body.Dependencies[0].Missing, "GEMINI_API_KEY")
+}
+
+func TestHealthz(t *testing.T) {
+	// Liveness must not depend on the database
+	server := newTestServer(t, newUnreachableStore(t))
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/healthz", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.JSONEq(t, `{"status":"ok"}`, recorder.Body.String())
+}
+
+func TestReadyzClosedPool(t *testing.T) {
+	// Arrange: a pool that has been shut down, and a recommender endpoint that no longer listens
+	pool, err := pgxpool.New(context.Background(), unreachableDBSource)
+	require.NoError(t, err)
+	pool.Close()
+
+	recommender := httptest.NewServer(http.NotFoundHandler())
+	recommender.Close()
+
+	server, err := NewServer(config.Config{
+		TokenSymmetricKey:          util.RandomString(32),
+		AccessTokenDuration:        time.Minute,
+		RecommenderAPIURL:          recommender.URL,
+		RecommenderAPIKey:          "recommender-key",
+		ReadinessProbeDependencies: true,
+	}, db.NewStore(pool), nil)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/readyz", nil)
+	require.NoError(t, err)
+
+	// Act
+	server.router.ServeHTTP(recorder, request)
+
+	// Assert: both failures are listed; the unconfigured LLM is not probed
+	require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	var body struct {
+		Status       string            `json:"status"`
+		Dependencies []dependencyCheck `json:"dependencies"`
+		Failed       []string          `json:"failed"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	require.Equal(t, "unavailable", body.Status)
+	require.Equal(t, []string{"database", "recommender"}, body.Failed)
+	require.Empty(t, body.Dependencies[0].Unreachable)
+	require.NotEmpty(t, body.Dependencies[1].Unreachable)
+}
+
+func TestProbeEndpoint(t *testing.T) {
+	// Any HTTP answer counts as reachable, even an error status
+	upstream := httptest.NewServer(http.NotFoundHandler())
+	defer upstream.Close()
+
+	require.NoError(t, probeEndpoint(context.Background(), upstream.URL))
+	require.Error(t, probeEndpoint(context.Background(), "http://127.0.0.1:1"))
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	t.Run("Disabled by default", func(t *testing.T) {
+		server := newTestServer(t, newUnreachableStore(t))
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+		require.NoError(t, err)
+
+		server.router.ServeHTTP(recorder, request)
+
+		require.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("Records requests by route pattern", func(t *testing.T) {
+		// Arrange: two servers prove each gets its own registry
+		cfg := config.Config{
+			TokenSymmetricKey:   util.RandomString(32),
+			AccessTokenDuration: time.Minute,
+			MetricsEnabled:      true,
+		}
+		_, err := NewServer(cfg, newUnreachableStore(t), nil)
+		require.NoError(t, err)
+		server, err := NewServer(cfg, newUnreachableStore(t), nil)
+		require.NoError(t, err)
+
+		// Act: hit a probe, then scrape
+		server.router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		recorder := httptest.NewRecorder()
+		server.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		// Assert
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.Contains(t, recorder.Body.String(), `synapse_http_requests_total{method="GET",route="/healthz",status="200"}`)
+	})
+}
@@ -0,0 +1,212 @@
+// api/idempotency.go
+
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Idempotency Keys
+////////////////////////////////////////////////////////////////////////
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+	maxIdempotentBodySize    = 1 << 20        // Largest request body fingerprinted for an Idempotency-Key
+	defaultIdempotencyKeyTTL = 24 * time.Hour // Used when IDEMPOTENCY_KEY_TTL is not set
+)
+
+var (
+	errIdempotencyKeyTooLong    = errors.New("Idempotency-Key must be at most 255 characters")
+	errIdempotentBodyTooLarge   = errors.New("request body is too large to use with an Idempotency-Key")
+	errIdempotencyKeyInProgress = errors.New("a request with this Idempotency-Key is still being processed")
+	errIdempotencyKeyReused     = errors.New("Idempotency-Key was already used with a different request body")
+)
+
+// idempotencyCacheKey scopes a client's key to the caller and the route, so two users,
+// or one key sent to two different endpoints, never share a response
+type idempotencyCacheKey struct {
+	userID int64
+	route  string // Method and route pattern, e.g. "POST /api/v1/manager/tasks"
+	key    string
+}
+
+// idempotencyEntry is a request seen with a key: in flight until its response is stored
+type idempotencyEntry struct {
+	requestHash [sha256.Size]byte // The request body, so a reused key with another payload is caught
+	completed   bool
+	status      int
+	contentType string
+	body        []byte
+	expiresAt   time.Time
+}
+
+// idempotencyCache remembers the responses of requests sent with an Idempotency-Key for a TTL.
+// A nil cache disables idempotency keys: every request is processed.
+type idempotencyCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time // Replaceable in tests
+	entries map[idempotencyCacheKey]*idempotencyEntry
+}
+
+// newIdempotencyCache returns a cache with the given TTL, or nil (disabled) when ttl is not positive
+func newIdempotencyCache(ttl time.Duration) *idempotencyCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &idempotencyCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[idempotencyCacheKey]*idempotencyEntry),
+	}
+}
+
+// begin claims key for a request with the given body hash. It returns the earlier entry
+// when the key was already seen, or nil when the caller now owns the key and must either
+// complete or release it. Expired entries are dropped first so the map cannot grow without bound.
+func (c *idempotencyCache) begin(key idempotencyCacheKey, requestHash [sha256.Size]byte) *idempotencyEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for k, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+
+	if entry, ok := c.entries[key]; ok {
+		copied := *entry
+		return &copied
+	}
+	c.entries[key] = &idempotencyEntry{requestHash: requestHash, expiresAt: now.Add(c.ttl)}
+	return nil
+}
+
+// complete stores the response of the request that owns key, to be replayed until the TTL ends
+func (c *idempotencyCache) complete(key idempotencyCacheKey, status int, contentType string, body []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	entry.completed = true
+	entry.status = status
+	entry.contentType = contentType
+	entry.body = body
+	entry.expiresAt = c.now().Add(c.ttl)
+}
+
+// release forgets key, e.g. after a failed request, so the client can retry with it
+func (c *idempotencyCache) release(key idempotencyCacheKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+}
+
+// bodyRecorder passes the response through while keeping a copy of its body
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// idempotencyMiddleware makes creation routes safe to retry. A request carrying an
+// Idempotency-Key header is processed once per user, route and key; repeating it within
+// the TTL replays the original successful response instead of creating another row.
+// A repeat that arrives while the first is still running gets 409, and a repeat with a
+// different body gets 422. Failed requests are not remembered, so they can be retried.
+// It must run after authMiddleware.
+func (server *Server) idempotencyMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		idempotencyKey := ctx.GetHeader(idempotencyKeyHeader)
+		if idempotencyKey == "" || server.idempotencyKeys == nil {
+			ctx.Next()
+			return
+		}
+		if len(idempotencyKey) > maxIdempotencyKeyLength {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(errIdempotencyKeyTooLong))
+			return
+		}
+
+		authPayload, err := getAuthorizationPayload(ctx)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(errors.New("unauthorized")))
+			return
+		}
+
+		// The body is read to fingerprint the request, then put back for the handler.
+		// One byte past the cap is enough to tell that a body is too large without buffering all of it.
+		body, err := io.ReadAll(io.LimitReader(ctx.Request.Body, maxIdempotentBodySize+1))
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		if len(body) > maxIdempotentBodySize {
+			ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, errorResponse(errIdempotentBodyTooLarge))
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		key := idempotencyCacheKey{
+			userID: actorIDFromPayload(authPayload).Int64,
+			route:  ctx.Request.Method + " " + ctx.FullPath(),
+			key:    idempotencyKey,
+		}
+		requestHash := sha256.Sum256(body)
+
+		if previous := server.idempotencyKeys.begin(key, requestHash); previous != nil {
+			switch {
+			case previous.requestHash != requestHash:
+				ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, errorResponse(errIdempotencyKeyReused))
+			case !previous.completed:
+				ctx.AbortWithStatusJSON(http.StatusConflict, errorResponse(errIdempotencyKeyInProgress))
+			default:
+				ctx.Header(idempotentReplayedHeader, "true")
+				ctx.Data(previous.status, previous.contentType, previous.body)
+				ctx.Abort()
+			}
+			return
+		}
+
+		// Release the key unless a successful response gets stored, including when the handler panics
+		completed := false
+		defer func() {
+			if !completed {
+				server.idempotencyKeys.release(key)
+			}
+		}()
+
+		recorder := &bodyRecorder{ResponseWriter: ctx.Writer}
+		ctx.Writer = recorder
+		ctx.Next()
+
+		if status := recorder.Status(); status >= 200 && status < 300 {
+			server.idempotencyKeys.complete(key, status, recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+			completed = true
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// api/idempotency_test.go
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	db "github.com/pranav244872/synapse/db/sqlc"
+	"github.com/pranav244872/synapse/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	now := time.Date(2025, time.March, 4, 10, 0, 0, 0, time.UTC)
+	server := &Server{idempotencyKeys: newIdempotencyCache(time.Hour)}
+	server.idempotencyKeys.now = func() time.Time { return now }
+
+	// The handler stands in for an insert: every call creates a new row
+	inserts := 0
+	fail := false
+	var release chan struct{}
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) {
+		var userID float64
+		fmt.Sscan(ctx.GetHeader("X-User"), &userID)
+		ctx.Set(authorizationPayloadKey, jwt.MapClaims{"user_id": userID})
+	})
+	handler := func(ctx *gin.Context) {
+		if release != nil {
+			<-release
+		}
+		if fail {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(fmt.Errorf("insert failed")))
+			return
+		}
+		inserts++
+		ctx.JSON(http.StatusCreated, gin.H{"id": inserts})
+	}
+	router.POST("/projects", server.idempotencyMiddleware(), handler)
+	router.POST("/tasks", server.idempotencyMiddleware(), handler)
+
+	send := func(path, user, key, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		require.NoError(t, err)
+		request.Header.Set("X-User", user)
+		if key != "" {
+			request.Header.Set(idempotencyKeyHeader, key)
+		}
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// A repeated key replays the first response without a second insert
+	first := send("/projects", "1", "key-1", `{"name":"a"}`)
+	require.Equal(t, http.StatusCreated, first.Code)
+	repeat := send("/projects", "1", "key-1", `{"name":"a"}`)
+	require.Equal(t, http.StatusCreated, repeat.Code)
+	require.Equal(t, first.Body.String(), repeat.Body.String())
+	require.Equal(t, "application/json; charset=utf-8", repeat.Header().Get("Content-Type"))
+	require.Equal(t, "true", repeat.Header().Get(idempotentReplayedHeader))
+	require.Empty(t, first.Header().Get(idempotentReplayedHeader))
+	require.Equal(t, 1, inserts)
+
+	// The key is scoped to the user and the route
+	require.Equal(t, `{"id":2}`, send("/projects", "2", "key-1", `{"name":"a"}`).Body.String())
+	require.Equal(t, `{"id":3}`, send("/tasks", "1", "key-1", `{"name":"a"}`).Body.String())
+
+	// Requests without a key are never deduplicated
+	send("/projects", "1", "", `{"name":"a"}`)
+	send("/projects", "1", "", `{"name":"a"}`)
+	require.Equal(t, 5, inserts)
+
+	// Reusing a key with another body is rejected
+	require.Equal(t, http.StatusUnprocessableEntity, send("/projects", "1", "key-1", `{"name":"b"}`).Code)
+	require.Equal(t, 5, inserts)
+
+	// Over-long keys are rejected
+	require.Equal(t, http.StatusBadRequest, send("/projects", "1", strings.Repeat("k", maxIdempotencyKeyLength+1), `{}`).Code)
+
+	// Bodies past the size cap are refused without being buffered in full
+	require.Equal(t, http.StatusRequestEntityTooLarge, send("/projects", "1", "key-big", strings.Repeat(" ", maxIdempotentBodySize+1)).Code)
+	require.Equal(t, 5, inserts)
+
+	// Failed requests are not remembered, so the client can retry with the same key
+	fail = true
+	require.Equal(t, http.StatusInternalServerError, send("/projects", "1", "key-2", `{}`).Code)
+	fail = false
+	require.Equal(t, http.StatusCreated, send("/projects", "1", "key-2", `{}`).Code)
+	require.Equal(t, 6, inserts)
+
+	// Once the TTL passes the key can be used again
+	now = now.Add(time.Hour)
+	require.Equal(t, `{"id":7}`, send("/projects", "1", "key-1", `{"name":"a"}`).Body.String())
+
+	// A repeat that arrives while the first request is running is told to wait
+	release = make(chan struct{})
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- send("/projects", "1", "key-3", `{}`) }()
+	require.Eventually(t, func() bool {
+		server.idempotencyKeys.mu.Lock()
+		defer server.idempotencyKeys.mu.Unlock()
+		return server.idempotencyKeys.entries[idempotencyCacheKey{userID: 1, route: "POST /projects", key: "key-3"}] != nil
+	}, time.Second, time.Millisecond)
+	require.Equal(t, http.StatusConflict, send("/projects", "1", "key-3", `{}`).Code)
+	close(release)
+	require.Equal(t, http.StatusCreated, (<-done).Code)
+}
+
+func TestIdempotencyMiddlewareDisabled(t *testing.T) {
+	server := &Server{idempotencyKeys: newIdempotencyCache(-1)}
+	require.Nil(t, server.idempotencyKeys)
+
+	inserts := 0
+	router := gin.New()
+	router.POST("/projects", server.idempotencyMiddleware(), func(ctx *gin.Context) {
+		inserts++
+		ctx.Status(http.StatusCreated)
+	})
+
+	for range 2 {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/projects", nil)
+		require.NoError(t, err)
+		request.Header.Set(idempotencyKeyHeader, "key")
+		router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusCreated, recorder.Code)
+	}
+	require.Equal(t, 2, inserts)
+}
+
+func TestCreateProjectIdempotencyKey(t *testing.T) {
+	store := newTestStore(t)
+	server := newTestServer(t, store)
+	ctx := context.Background()
+
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	manager := createTestUser(t, store, db.UserRoleManager, team.ID)
+
+	body, err := json.Marshal(gin.H{"name": util.RandomName(), "description": "Retried on a flaky network"})
+	require.NoError(t, err)
+	key := util.RandomString(12)
+	send := func() *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/api/v1/manager/projects", bytes.NewReader(body))
+		require.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set(idempotencyKeyHeader, key)
+		addAuthorization(t, request, server, manager.ID, db.UserRoleManager, team.ID)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	first := send()
+	require.Equal(t, http.StatusCreated, first.Code)
+	repeat := send()
+	require.Equal(t, http.StatusCreated, repeat.Code)
+	require.Equal(t, first.Body.String(), repeat.Body.String())
+
+	// Only one project was inserted
+	count, err := store.CountProjectsByTeam(ctx, team.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
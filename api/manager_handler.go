@@ -3,20 +3,31 @@ package api
 
 import (
 	"bytes"
+	"cmp"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	db "github.com/pranav244872/synapse/db/sqlc"
+	"github.com/pranav244872/synapse/metrics"
+	"github.com/pranav244872/synapse/skillz"
+	"github.com/pranav244872/synapse/util"
+	"golang.org/x/sync/errgroup"
 )
 
 ////////////////////////////////////////////////////////////////////////
@@ -25,31 +36,26 @@ import (
 
 // getDashboardStats provides a single endpoint for all dashboard statistics
 func (server *Server) getDashboardStats(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting getDashboardStats handler")
+	slog.Debug("Starting getDashboardStats handler")
 
-	// Get authorization payload
-	authPayload, err := getAuthorizationPayload(ctx)
+	teamID, err := getTeamIDFromPayload(ctx)
 	if err != nil {
-		log.Printf("DEBUG: Failed to get authorization payload for dashboard stats: %v", err)
-		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("unauthorized")))
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
-
-	teamIDFloat, ok := authPayload["team_id"].(float64)
-	if !ok || teamIDFloat == 0 {
-		log.Printf("DEBUG: Manager is not assigned to a team for dashboard stats")
+	if teamID == 0 {
+		slog.Debug("Manager is not assigned to a team for dashboard stats")
 		err := errors.New("forbidden: manager is not assigned to a team")
 		ctx.JSON(http.StatusForbidden, errorResponse(err))
 		return
 	}
 
-	teamID := int64(teamIDFloat)
-	log.Printf("DEBUG: Getting dashboard stats for team ID: %d", teamID)
+	slog.Debug("Getting dashboard stats", "team_id", teamID)
 
 	// Get active projects count
 	activeProjects, err := server.store.CountActiveProjectsByTeam(ctx, teamID)
 	if err != nil {
-		log.Printf("DEBUG: Error counting active projects: %v", err)
+		slog.Debug("Error counting active projects", "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
@@ -57,7 +63,7 @@ func (server *Server) getDashboardStats(ctx *gin.Context) {
 	// Get open tasks count
 	openTasks, err := server.store.CountOpenTasksByTeam(ctx, teamID)
 	if err != nil {
-		log.Printf("DEBUG: Error counting open tasks: %v", err)
+		slog.Debug("Error counting open tasks", "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
@@ -68,7 +74,18 @@ func (server *Server) getDashboardStats(ctx *gin.Context) {
 		Availability: db.AvailabilityStatusAvailable,
 	})
 	if err != nil {
-		log.Printf("DEBUG: Error counting available engineers: %v", err)
+		slog.Debug("Error counting available engineers", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Get the count of engineers on leave, who are neither available nor busy
+	onLeaveEngineers, err := server.store.CountUsersByTeamAndAvailability(ctx, db.CountUsersByTeamAndAvailabilityParams{
+		TeamID:       pgtype.Int8{Int64: teamID, Valid: true},
+		Availability: db.AvailabilityStatusOnLeave,
+	})
+	if err != nil {
+		slog.Debug("Error counting engineers on leave", "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
@@ -79,7 +96,15 @@ func (server *Server) getDashboardStats(ctx *gin.Context) {
 		Role:   db.UserRoleEngineer,
 	})
 	if err != nil {
-		log.Printf("DEBUG: Error counting total engineers: %v", err)
+		slog.Debug("Error counting total engineers", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Get overdue tasks count; tasks without a due date are never overdue
+	overdueTasks, err := server.store.CountOverdueTasksByTeam(ctx, teamID)
+	if err != nil {
+		slog.Debug("Error counting overdue tasks", "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
@@ -87,43 +112,174 @@ func (server *Server) getDashboardStats(ctx *gin.Context) {
 	response := gin.H{
 		"active_projects":     activeProjects,
 		"open_tasks":          openTasks,
+		"overdue_tasks":       overdueTasks,
 		"available_engineers": availableEngineers,
+		"on_leave_engineers":  onLeaveEngineers,
 		"total_engineers":     totalEngineers,
 	}
 
-	log.Printf("DEBUG: Dashboard stats - Projects: %d, Tasks: %d, Available: %d, Total: %d",
-		activeProjects, openTasks, availableEngineers, totalEngineers)
+	slog.Debug("Dashboard stats", "active_projects", activeProjects, "open_tasks", openTasks, "available_engineers", availableEngineers, "total_engineers", totalEngineers)
 
-	ctx.JSON(http.StatusOK, response)
+	// Dashboards poll this, so unchanged stats are answered with 304
+	respondJSONWithETag(ctx, http.StatusOK, response)
+}
+
+// Default window for dashboard trends when no days are given
+const defaultTrendDays = 30
+
+type getDashboardTrendsRequest struct {
+	Days int32 `form:"days" binding:"omitempty,min=1,max=90"` // Window size in days, ending today (UTC)
+}
+
+// dashboardTrendPoint is one day of the trend series
+type dashboardTrendPoint struct {
+	Date      string `json:"date"` // YYYY-MM-DD, UTC
+	Created   int64  `json:"created"`
+	Completed int64  `json:"completed"`
+}
+
+type dashboardTrendsResponse struct {
+	Days           int32                 `json:"days"`
+	TotalCreated   int64                 `json:"total_created"`
+	TotalCompleted int64                 `json:"total_completed"`
+	Series         []dashboardTrendPoint `json:"series"`
+}
+
+// getDashboardTrends returns daily counts of the team's tasks created and completed,
+// one point per day of the window, oldest first, for velocity and burndown charts
+func (server *Server) getDashboardTrends(ctx *gin.Context) {
+	slog.Debug("Starting getDashboardTrends handler")
+
+	var req getDashboardTrendsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.Days == 0 {
+		req.Days = defaultTrendDays
+	}
+
+	teamID, err := getTeamIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	if teamID == 0 {
+		slog.Debug("Manager is not assigned to a team for dashboard trends")
+		err := errors.New("forbidden: manager is not assigned to a team")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	slog.Debug("Getting dashboard trends", "team_id", teamID, "days", req.Days)
+
+	rows, err := server.store.ListTaskTrendsByTeam(ctx, db.ListTaskTrendsByTeamParams{
+		Days:   req.Days,
+		TeamID: teamID,
+	})
+	if err != nil {
+		slog.Debug("Error listing task trends", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := dashboardTrendsResponse{
+		Days:   req.Days,
+		Series: make([]dashboardTrendPoint, 0, len(rows)),
+	}
+	for _, row := range rows {
+		rsp.Series = append(rsp.Series, dashboardTrendPoint{
+			Date:      row.Day.Time.Format(time.DateOnly),
+			Created:   row.CreatedCount,
+			Completed: row.CompletedCount,
+		})
+		rsp.TotalCreated += row.CreatedCount
+		rsp.TotalCompleted += row.CompletedCount
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// skillGap is one skill the team's open tasks require, with how well the team covers it
+type skillGap struct {
+	SkillID       int64  `json:"skill_id"`
+	SkillName     string `json:"skill_name"`
+	OpenTaskCount int64  `json:"open_task_count"`
+	MemberCount   int64  `json:"member_count"`
+	Uncovered     bool   `json:"uncovered"` // No engineer on the team has the skill
+}
+
+type skillGapsResponse struct {
+	UncoveredCount int        `json:"uncovered_count"`
+	Skills         []skillGap `json:"skills"`
+}
+
+// getSkillGaps compares the skills the team's open tasks demand with the skills its
+// engineers have, listing uncovered skills first to guide hiring and training
+func (server *Server) getSkillGaps(ctx *gin.Context) {
+	slog.Debug("Starting getSkillGaps handler")
+
+	teamID, err := getTeamIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	if teamID == 0 {
+		slog.Debug("Manager is not assigned to a team for skill gaps")
+		err := errors.New("forbidden: manager is not assigned to a team")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	slog.Debug("Getting skill gaps", "team_id", teamID)
+
+	rows, err := server.store.ListSkillGapsByTeam(ctx, teamID)
+	if err != nil {
+		slog.Debug("Error listing skill gaps", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := skillGapsResponse{Skills: make([]skillGap, 0, len(rows))}
+	for _, row := range rows {
+		gap := skillGap{
+			SkillID:       row.SkillID,
+			SkillName:     row.SkillName,
+			OpenTaskCount: row.OpenTaskCount,
+			MemberCount:   row.MemberCount,
+			Uncovered:     row.MemberCount == 0,
+		}
+		if gap.Uncovered {
+			rsp.UncoveredCount++
+		}
+		rsp.Skills = append(rsp.Skills, gap)
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
 }
 
 // getTeamMembers lists all engineers on the manager's team with availability status
 func (server *Server) getTeamMembers(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting getTeamMembers handler")
+	slog.Debug("Starting getTeamMembers handler")
 
-	// Get authorization payload
-	authPayload, err := getAuthorizationPayload(ctx)
+	teamID, err := getTeamIDFromPayload(ctx)
 	if err != nil {
-		log.Printf("DEBUG: Failed to get authorization payload for team members: %v", err)
-		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("unauthorized")))
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
-
-	teamIDFloat, ok := authPayload["team_id"].(float64)
-	if !ok || teamIDFloat == 0 {
-		log.Printf("DEBUG: Manager is not assigned to a team for team members")
+	if teamID == 0 {
+		slog.Debug("Manager is not assigned to a team for team members")
 		err := errors.New("forbidden: manager is not assigned to a team")
 		ctx.JSON(http.StatusForbidden, errorResponse(err))
 		return
 	}
 
-	teamID := int64(teamIDFloat)
-	log.Printf("DEBUG: Getting team members for team ID: %d", teamID)
+	slog.Debug("Getting team members", "team_id", teamID)
 
 	// Get all engineers in the team
 	engineers, err := server.store.ListEngineersByTeam(ctx, pgtype.Int8{Int64: teamID, Valid: true})
 	if err != nil {
-		log.Printf("DEBUG: Error listing engineers by team: %v", err)
+		slog.Debug("Error listing engineers by team", "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
@@ -146,8 +302,366 @@ func (server *Server) getTeamMembers(ctx *gin.Context) {
 		})
 	}
 
-	log.Printf("DEBUG: Found %d engineers in team %d", len(members), teamID)
-	ctx.JSON(http.StatusOK, members)
+	slog.Debug("Found engineers in team", "count", len(members), "team_id", teamID)
+	respondList(ctx, http.StatusOK, members, members, []csvColumn[teamMemberResponse]{
+		{"id", func(m teamMemberResponse) string { return csvInt(m.ID) }},
+		{"name", func(m teamMemberResponse) string { return m.Name }},
+		{"email", func(m teamMemberResponse) string { return m.Email }},
+		{"availability", func(m teamMemberResponse) string { return m.Availability }},
+	})
+}
+
+// exportTeamMembers downloads the team's engineers as CSV or JSON
+func (server *Server) exportTeamMembers(ctx *gin.Context) {
+	slog.Debug("Starting exportTeamMembers handler")
+
+	var req exportRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	teamID, err := getTeamIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	if teamID == 0 {
+		slog.Debug("Manager is not assigned to a team for team members export")
+		err := errors.New("forbidden: manager is not assigned to a team")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	slog.Debug("Exporting team members", "team_id", teamID, "format", req.Format)
+
+	// A team's engineers come back in a single batch
+	fetched := false
+	next := func() ([]db.ListEngineersByTeamRow, error) {
+		if fetched {
+			return nil, nil
+		}
+		fetched = true
+		return server.store.ListEngineersByTeam(ctx, pgtype.Int8{Int64: teamID, Valid: true})
+	}
+
+	streamExport(ctx, req.Format, fmt.Sprintf("team-%d-members", teamID), next, []csvColumn[db.ListEngineersByTeamRow]{
+		{"id", func(u db.ListEngineersByTeamRow) string { return csvInt(u.ID) }},
+		{"name", func(u db.ListEngineersByTeamRow) string { return u.Name.String }},
+		{"email", func(u db.ListEngineersByTeamRow) string { return u.Email }},
+		{"availability", func(u db.ListEngineersByTeamRow) string { return string(u.Availability) }},
+	})
+}
+
+type getTeamMemberHistoryRequest struct {
+	PageID   int32     `form:"page_id" binding:"required,min=1"`
+	PageSize int32     `form:"page_size" binding:"required,min=5,max=50"`
+	Search   string    `form:"search"`                                       // Optional title search
+	From     time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"` // Optional RFC 3339 lower bound on completed_at
+	To       time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`   // Optional RFC 3339 upper bound on completed_at
+}
+
+// getTeamMemberHistory lists the completed tasks of one engineer on the manager's team
+func (server *Server) getTeamMemberHistory(ctx *gin.Context) {
+	slog.Debug("Starting getTeamMemberHistory handler")
+
+	var uriReq struct {
+		ID int64 `uri:"id" binding:"required,min=1"`
+	}
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var queryReq getTeamMemberHistoryRequest
+	if err := ctx.ShouldBindQuery(&queryReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	completedFrom, completedTo, err := timestampRange(queryReq.From, queryReq.To)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	managerTeamID, err := getTeamIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	if managerTeamID == 0 {
+		err := errors.New("forbidden: manager is not assigned to a team")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	// The target must be an engineer on the manager's own team
+	engineer, err := server.store.GetUser(ctx, uriReq.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("engineer not found")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if engineer.Role != db.UserRoleEngineer || !engineer.TeamID.Valid || engineer.TeamID.Int64 != managerTeamID {
+		slog.Debug("User is not an engineer on team", "user_id", engineer.ID, "team_id", managerTeamID)
+		ctx.JSON(http.StatusForbidden, errorResponse(errors.New("forbidden: engineer is not on your team")))
+		return
+	}
+
+	// Prepare search query with wildcard pattern for database ILIKE operation
+	searchQuery := "%"
+	if queryReq.Search != "" {
+		searchQuery = "%" + queryReq.Search + "%"
+	}
+	assigneeID := pgtype.Int8{Int64: engineer.ID, Valid: true}
+
+	history, err := server.store.GetEngineerTaskHistory(ctx, db.GetEngineerTaskHistoryParams{
+		AssigneeID:    assigneeID,
+		Limit:         queryReq.PageSize,
+		Offset:        (queryReq.PageID - 1) * queryReq.PageSize,
+		Search:        searchQuery,
+		CompletedFrom: completedFrom,
+		CompletedTo:   completedTo,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	totalCount, err := server.store.GetEngineerTaskHistoryCount(ctx, db.GetEngineerTaskHistoryCountParams{
+		AssigneeID:    assigneeID,
+		Search:        searchQuery,
+		CompletedFrom: completedFrom,
+		CompletedTo:   completedTo,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	slog.Debug("Found completed tasks for engineer", "count", totalCount, "engineer_id", engineer.ID)
+	ctx.JSON(http.StatusOK, paginatedResponse[db.GetEngineerTaskHistoryRow]{
+		TotalCount: totalCount,
+		Data:       history,
+	})
+}
+
+// updateTeamSettingsRequest changes only the settings that are present in the body.
+// The clear flags drop a team default so new tasks fall back to the server's defaults.
+type updateTeamSettingsRequest struct {
+	AutoAssign               *bool   `json:"auto_assign"`
+	DefaultTaskPriority      *string `json:"default_task_priority" binding:"omitempty,oneof=low medium high critical"`
+	DefaultTaskStatus        *string `json:"default_task_status"`
+	ClearDefaultTaskPriority bool    `json:"clear_default_task_priority"`
+	ClearDefaultTaskStatus   bool    `json:"clear_default_task_status"`
+}
+
+// updateTeamSettings lets a manager change per-team behaviour such as auto-assigning new tasks
+// and the priority and status new tasks start with
+func (server *Server) updateTeamSettings(ctx *gin.Context) {
+	slog.Debug("Starting updateTeamSettings handler")
+
+	var req updateTeamSettingsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.AutoAssign == nil && req.DefaultTaskPriority == nil && req.DefaultTaskStatus == nil &&
+		!req.ClearDefaultTaskPriority && !req.ClearDefaultTaskStatus {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("at least one setting must be provided")))
+		return
+	}
+	if (req.ClearDefaultTaskPriority && req.DefaultTaskPriority != nil) || (req.ClearDefaultTaskStatus && req.DefaultTaskStatus != nil) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("a default cannot be both set and cleared")))
+		return
+	}
+	if req.DefaultTaskStatus != nil && !slices.Contains(taskCreationStatuses, db.TaskStatus(*req.DefaultTaskStatus)) {
+		err := fmt.Errorf("default_task_status must be one of %v", taskCreationStatuses)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	managerTeamID, err := getTeamIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	if managerTeamID == 0 {
+		err := errors.New("forbidden: manager is not assigned to a team")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	arg := db.UpdateTeamSettingsParams{
+		ID:                       managerTeamID,
+		ClearDefaultTaskPriority: req.ClearDefaultTaskPriority,
+		ClearDefaultTaskStatus:   req.ClearDefaultTaskStatus,
+	}
+	if req.AutoAssign != nil {
+		arg.AutoAssign = pgtype.Bool{Bool: *req.AutoAssign, Valid: true}
+	}
+	if req.DefaultTaskPriority != nil {
+		arg.DefaultTaskPriority = db.NullTaskPriority{TaskPriority: db.TaskPriority(*req.DefaultTaskPriority), Valid: true}
+	}
+	if req.DefaultTaskStatus != nil {
+		arg.DefaultTaskStatus = db.NullTaskStatus{TaskStatus: db.TaskStatus(*req.DefaultTaskStatus), Valid: true}
+	}
+
+	team, err := server.store.UpdateTeamSettings(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	slog.Debug("Team settings updated", "team_id", team.ID, "auto_assign", team.AutoAssign, "default_task_priority", team.DefaultTaskPriority.TaskPriority, "default_task_status", team.DefaultTaskStatus.TaskStatus)
+	ctx.JSON(http.StatusOK, team)
+}
+
+const (
+	overviewRequestTimeout      = 5 * time.Second // Shared deadline for all overview sections
+	overviewTopProjectsLimit    = 5               // Number of active projects included in the overview
+	overviewRecentActivityLimit = 10              // Number of recent tasks included in the overview
+)
+
+// overviewSection loads one independent part of the team overview
+type overviewSection func(ctx context.Context) (any, error)
+
+// collectOverviewSections runs all sections concurrently under a shared timeout.
+// A failing section is reported in the returned error map instead of aborting the others.
+func collectOverviewSections(parent context.Context, timeout time.Duration, sections map[string]overviewSection) (map[string]any, map[string]string) {
+	sectionCtx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	results := make(map[string]any, len(sections))
+	sectionErrors := make(map[string]string)
+
+	// A plain errgroup (not WithContext) so one failure does not cancel the other sections
+	var group errgroup.Group
+	for name, load := range sections {
+		group.Go(func() error {
+			data, err := load(sectionCtx)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				slog.Debug("Overview section failed", "section", name, "error", err)
+				results[name] = nil
+				sectionErrors[name] = err.Error()
+				return nil
+			}
+			results[name] = data
+			return nil
+		})
+	}
+	_ = group.Wait()
+
+	return results, sectionErrors
+}
+
+// getTeamOverview returns team details, stats, member availability, top projects and recent activity in one
+// response, so the manager landing page needs a single request
+func (server *Server) getTeamOverview(ctx *gin.Context) {
+	slog.Debug("Starting getTeamOverview handler")
+
+	teamID, err := getTeamIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	if teamID == 0 {
+		slog.Debug("Manager is not assigned to a team for team overview")
+		err := errors.New("forbidden: manager is not assigned to a team")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	slog.Debug("Building team overview", "team_id", teamID)
+
+	sections, sectionErrors := collectOverviewSections(ctx.Request.Context(), overviewRequestTimeout, server.teamOverviewSections(teamID))
+
+	response := gin.H{
+		"team":            sections["team"],
+		"stats":           sections["stats"],
+		"members":         sections["members"],
+		"top_projects":    sections["top_projects"],
+		"recent_activity": sections["recent_activity"],
+		"errors":          sectionErrors,
+	}
+
+	slog.Debug("Team overview built", "team_id", teamID, "failed_sections", len(sectionErrors))
+	ctx.JSON(http.StatusOK, response)
+}
+
+// teamOverviewSections defines the independent reads that make up the team overview
+func (server *Server) teamOverviewSections(teamID int64) map[string]overviewSection {
+	teamIDParam := pgtype.Int8{Int64: teamID, Valid: true}
+
+	return map[string]overviewSection{
+		"team": func(ctx context.Context) (any, error) {
+			team, err := server.store.GetTeamManagerContact(ctx, teamID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get team: %w", err)
+			}
+			return team, nil
+		},
+		"stats": func(ctx context.Context) (any, error) {
+			// Every count comes from one query rather than a round trip each
+			stats, err := server.store.GetTeamOverviewStats(ctx, teamID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to count projects and tasks: %w", err)
+			}
+			return stats, nil
+		},
+		"members": func(ctx context.Context) (any, error) {
+			engineers, err := server.store.ListEngineersByTeam(ctx, teamIDParam)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list team members: %w", err)
+			}
+			byAvailability := map[string]int{
+				string(db.AvailabilityStatusAvailable): 0,
+				string(db.AvailabilityStatusBusy):      0,
+				string(db.AvailabilityStatusOnLeave):   0,
+			}
+			for _, engineer := range engineers {
+				byAvailability[string(engineer.Availability)]++
+			}
+			return gin.H{
+				"total_engineers": len(engineers),
+				"by_availability": byAvailability,
+			}, nil
+		},
+		"top_projects": func(ctx context.Context) (any, error) {
+			projects, err := server.store.ListTopActiveProjectsByTeam(ctx, db.ListTopActiveProjectsByTeamParams{
+				TeamID: teamID,
+				Limit:  overviewTopProjectsLimit,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list top projects: %w", err)
+			}
+			if projects == nil {
+				projects = []db.ListTopActiveProjectsByTeamRow{}
+			}
+			return projects, nil
+		},
+		"recent_activity": func(ctx context.Context) (any, error) {
+			tasks, err := server.store.ListRecentTasksByTeam(ctx, db.ListRecentTasksByTeamParams{
+				TeamID: teamID,
+				Limit:  overviewRecentActivityLimit,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list recent activity: %w", err)
+			}
+			if tasks == nil {
+				tasks = []db.ListRecentTasksByTeamRow{}
+			}
+			return tasks, nil
+		},
+	}
 }
 
 ////////////////////////////////////////////////////////////////////////
@@ -160,35 +674,25 @@ type inviteEngineerRequest struct {
 
 // inviteEngineer handles creating invitations for engineer role by managers
 func (server *Server) inviteEngineer(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting inviteEngineer handler")
+	slog.Debug("Starting inviteEngineer handler")
 
 	var req inviteEngineerRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		log.Printf("DEBUG: Invite engineer JSON bind error: %v", err)
+		slog.Debug("Invite engineer JSON bind error", "error", err)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Creating engineer invitation - Email: %s", req.Email)
+	slog.Debug("Creating engineer invitation", "email", req.Email)
 
 	// Get authorization payload with proper error handling
-	authPayload, err := getAuthorizationPayload(ctx)
+	inviterID, err := getUserIDFromPayload(ctx)
 	if err != nil {
-		log.Printf("DEBUG: Failed to get authorization payload for engineer invitation: %v", err)
-		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("unauthorized")))
-		return
-	}
-
-	// Safely extract user_id with type assertion
-	userIDFloat, ok := authPayload["user_id"].(float64)
-	if !ok {
-		log.Printf("DEBUG: user_id not found or not a float64 in auth payload for engineer invitation. Payload: %+v", authPayload)
-		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("invalid user_id in token")))
+		slog.Debug("Failed to get user ID from authorization payload for engineer invitation", "error", err)
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
-
-	inviterID := int64(userIDFloat)
-	log.Printf("DEBUG: Extracted Manager ID: %d", inviterID)
+	slog.Debug("Extracted manager ID", "inviter_id", inviterID)
 
 	// For engineer invitations by managers, team_id is auto-derived from manager's team
 	// No need to specify TeamID in params - the transaction will handle it
@@ -197,13 +701,17 @@ func (server *Server) inviteEngineer(ctx *gin.Context) {
 		EmailToInvite: req.Email,
 		RoleToInvite:  db.UserRoleEngineer,
 		// TeamID is intentionally omitted - will be auto-derived from manager's team
+		Duration: server.config.InvitationTTL,
 	}
 
-	log.Printf("DEBUG: Calling CreateInvitationTx with params: %+v", arg)
+	slog.Debug("Calling CreateInvitationTx", "inviter_id", arg.InviterID, "role", arg.RoleToInvite)
 
 	result, err := server.store.CreateInvitationTx(ctx, arg)
 	if err != nil {
-		log.Printf("DEBUG: Error creating engineer invitation: %v", err)
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
+		slog.Debug("Error creating engineer invitation", "error", err)
 
 		// Handle specific business logic errors from the transaction
 		switch {
@@ -226,8 +734,10 @@ func (server *Server) inviteEngineer(ctx *gin.Context) {
 		}
 	}
 
-	log.Printf("DEBUG: Successfully created engineer invitation with ID: %d, Token: %s, Expires: %v",
-		result.Invitation.ID, result.Invitation.InvitationToken, result.Invitation.ExpiresAt.Time)
+	slog.Debug("Successfully created engineer invitation", "invitation_id", result.Invitation.ID, "expires_at", result.Invitation.ExpiresAt.Time)
+
+	// Email the invitee their accept link; failures are logged and do not fail the request
+	server.sendInvitationEmail(result.Invitation)
 
 	// Return the created invitation details
 	ctx.JSON(http.StatusCreated, result.Invitation)
@@ -240,35 +750,25 @@ type listSentInvitationsRequest struct {
 
 // listSentInvitations handles retrieving invitations sent by the current manager
 func (server *Server) listSentInvitations(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting listSentInvitations handler")
+	slog.Debug("Starting listSentInvitations handler")
 
 	var req listSentInvitationsRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		log.Printf("DEBUG: List sent invitations query bind error: %v", err)
+		slog.Debug("List sent invitations query bind error", "error", err)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: List sent invitations request params - PageID: %d, PageSize: %d", req.PageID, req.PageSize)
+	slog.Debug("List sent invitations request params", "page_id", req.PageID, "page_size", req.PageSize)
 
 	// Get authorization payload with proper error handling
-	authPayload, err := getAuthorizationPayload(ctx)
+	inviterID, err := getUserIDFromPayload(ctx)
 	if err != nil {
-		log.Printf("DEBUG: Failed to get authorization payload for listing invitations: %v", err)
-		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("unauthorized")))
+		slog.Debug("Failed to get user ID from authorization payload for listing invitations", "error", err)
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
-
-	// Safely extract user_id with type assertion
-	userIDFloat, ok := authPayload["user_id"].(float64)
-	if !ok {
-		log.Printf("DEBUG: user_id not found or not a float64 in auth payload for listing invitations. Payload: %+v", authPayload)
-		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("invalid user_id in token")))
-		return
-	}
-
-	inviterID := int64(userIDFloat)
-	log.Printf("DEBUG: Extracted Manager ID: %d", inviterID)
+	slog.Debug("Extracted manager ID", "inviter_id", inviterID)
 
 	// Query invitations sent by this manager
 	invitations, err := server.store.ListInvitationsByInviter(ctx, db.ListInvitationsByInviterParams{
@@ -277,20 +777,22 @@ func (server *Server) listSentInvitations(ctx *gin.Context) {
 		Offset:    (req.PageID - 1) * req.PageSize,
 	})
 	if err != nil {
-		log.Printf("DEBUG: Error listing invitations by inviter: %v", err)
+		slog.Debug("Error listing invitations by inviter", "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
 	// Get total count for pagination metadata
-	totalCount, err := server.store.CountInvitationsByInviter(ctx, inviterID)
+	totalCount, err := server.store.CountInvitationsByInviter(ctx, db.CountInvitationsByInviterParams{
+		InviterID: inviterID,
+	})
 	if err != nil {
-		log.Printf("DEBUG: Error counting invitations by inviter: %v", err)
+		slog.Debug("Error counting invitations by inviter", "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Retrieved %d invitations sent by manager, total count: %d", len(invitations), totalCount)
+	slog.Debug("Retrieved invitations sent by manager", "count", len(invitations), "total_count", totalCount)
 
 	// Convert to the unified response struct for API consistency
 	finalInvitations := make([]invitationResponse, 0, len(invitations))
@@ -311,7 +813,7 @@ func (server *Server) listSentInvitations(ctx *gin.Context) {
 		Data:       finalInvitations,
 	}
 
-	log.Printf("DEBUG: Successfully returning %d invitations with pagination", len(finalInvitations))
+	slog.Debug("Successfully returning invitations with pagination", "count", len(finalInvitations))
 	ctx.JSON(http.StatusOK, rsp)
 }
 
@@ -319,76 +821,213 @@ type cancelInvitationRequest struct {
 	ID int64 `uri:"id" binding:"required,min=1"`
 }
 
-// cancelInvitation handles canceling pending invitations sent by the current manager
+// cancelInvitation handles revoking (or, with hard=true, deleting) pending invitations sent by the current manager
 func (server *Server) cancelInvitation(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting cancelInvitation handler")
+	slog.Debug("Starting cancelInvitation handler")
 
 	var req cancelInvitationRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		log.Printf("DEBUG: Cancel invitation URI bind error: %v", err)
+		slog.Debug("Cancel invitation URI bind error", "error", err)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Canceling invitation with ID: %d", req.ID)
-
-	// Get authorization payload
-	authPayload, err := getAuthorizationPayload(ctx)
-	if err != nil {
-		log.Printf("DEBUG: Failed to get authorization payload for canceling invitation: %v", err)
-		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("unauthorized")))
+	var query removeInvitationQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		slog.Debug("Cancel invitation query bind error", "error", err)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	userIDFloat, ok := authPayload["user_id"].(float64)
-	if !ok {
-		log.Printf("DEBUG: user_id not found in auth payload for canceling invitation")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("invalid user_id in token")))
+	slog.Debug("Canceling invitation", "invitation_id", req.ID)
+
+	// Get the caller's user ID from the authorization payload
+	managerID, err := getUserIDFromPayload(ctx)
+	if err != nil {
+		slog.Debug("Failed to get user ID from authorization payload for canceling invitation", "error", err)
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
-
-	managerID := int64(userIDFloat)
-	log.Printf("DEBUG: Extracted Manager ID: %d", managerID)
+	slog.Debug("Extracted manager ID", "manager_id", managerID)
 
 	// First, check if the invitation exists and verify ownership
 	invitation, err := server.store.GetInvitationByID(ctx, req.ID)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			log.Printf("DEBUG: Invitation not found")
+			slog.Debug("Invitation not found")
 			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("invitation not found")))
 			return
 		}
-		log.Printf("DEBUG: Error checking invitation: %v", err)
+		slog.Debug("Error checking invitation", "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
 	// Verify that this manager sent the invitation
 	if invitation.InviterID != managerID {
-		log.Printf("DEBUG: Manager %d attempted to cancel invitation %d sent by %d", managerID, req.ID, invitation.InviterID)
+		slog.Debug("Manager attempted to cancel another inviter's invitation", "manager_id", managerID, "invitation_id", req.ID, "inviter_id", invitation.InviterID)
 		ctx.JSON(http.StatusForbidden, errorResponse(errors.New("you can only cancel invitations you sent")))
 		return
 	}
 
 	// Check if invitation can be canceled
 	if invitation.Status != "pending" {
-		log.Printf("DEBUG: Cannot cancel invitation with status: %s", invitation.Status)
+		slog.Debug("Cannot cancel invitation with status", "status", invitation.Status)
 		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("only pending invitations can be canceled")))
 		return
 	}
 
-	// Proceed with deletion
-	err = server.store.DeleteInvitation(ctx, req.ID)
+	// Proceed with revocation (or deletion when explicitly requested)
+	err = server.removePendingInvitation(ctx, req.ID, query.Hard)
 	if err != nil {
-		log.Printf("DEBUG: Error deleting invitation: %v", err)
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		if errors.Is(err, errInvitationNoLongerPending) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("only pending invitations can be canceled")))
+			return
+		}
+		slog.Debug("Error removing invitation", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Successfully canceled invitation with ID: %d", req.ID)
+	slog.Debug("Successfully canceled invitation", "invitation_id", req.ID, "hard_delete", query.Hard)
 	ctx.Status(http.StatusNoContent)
 }
 
+type resendInvitationRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// resendInvitation gives one of the caller's pending invitations a new token and expiry, and emails it again.
+// Shared by managers and admins, who can each only resend invitations they sent.
+func (server *Server) resendInvitation(ctx *gin.Context) {
+	var req resendInvitationRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		slog.Debug("Resend invitation URI bind error", "error", err)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	userID, err := getUserIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.ResendInvitationTx(ctx, db.ResendInvitationTxParams{
+		InvitationID: req.ID,
+		InviterID:    userID,
+		Duration:     server.config.InvitationTTL,
+	})
+	if err != nil {
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, db.ErrInvitationNotFound):
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		case errors.Is(err, db.ErrPermissionDenied):
+			ctx.JSON(http.StatusForbidden, errorResponse(errors.New("you can only resend invitations you sent")))
+		case errors.Is(err, db.ErrInvitationNotResendable):
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+		default:
+			slog.Error("Failed to resend invitation", "invitation_id", req.ID, "error", err)
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	slog.Info("Resent invitation", "invitation_id", result.Invitation.ID, "expires_at", result.Invitation.ExpiresAt.Time)
+
+	// The old link no longer works, so the invitee needs the new one
+	server.sendInvitationEmail(db.CreateInvitationRow(result.Invitation))
+
+	ctx.JSON(http.StatusOK, result.Invitation)
+}
+
+type getInvitationRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// invitationStatusResponse is one invitation's state, without its token
+type invitationStatusResponse struct {
+	ID               int64            `json:"id"`
+	Email            string           `json:"email"`
+	RoleToInvite     db.UserRole      `json:"role_to_invite"`
+	InviterID        int64            `json:"inviter_id"`
+	InviterName      string           `json:"inviter_name"`
+	TeamID           pgtype.Int8      `json:"team_id"`
+	Status           string           `json:"status"`
+	CreatedAt        pgtype.Timestamp `json:"created_at"`
+	ExpiresAt        pgtype.Timestamp `json:"expires_at"`
+	IsExpired        bool             `json:"is_expired"`         // Expired, or still pending past its expiry
+	ExpiresInSeconds int64            `json:"expires_in_seconds"` // Time left to accept a pending invitation, 0 otherwise
+}
+
+// newInvitationStatusResponse computes whether an invitation can still be accepted at now.
+// A pending invitation past its expiry counts as expired before the background job marks it.
+func newInvitationStatusResponse(invitation db.GetInvitationByIDRow, now time.Time) invitationStatusResponse {
+	rsp := invitationStatusResponse{
+		ID:           invitation.ID,
+		Email:        invitation.Email,
+		RoleToInvite: invitation.RoleToInvite,
+		InviterID:    invitation.InviterID,
+		InviterName:  invitation.InviterName,
+		TeamID:       invitation.TeamID,
+		Status:       invitation.Status,
+		CreatedAt:    invitation.CreatedAt,
+		ExpiresAt:    invitation.ExpiresAt,
+	}
+
+	switch invitation.Status {
+	case "expired":
+		rsp.IsExpired = true
+	case "pending":
+		remaining := invitation.ExpiresAt.Time.Sub(now)
+		rsp.IsExpired = remaining <= 0
+		rsp.ExpiresInSeconds = max(0, int64(remaining.Seconds()))
+	}
+	return rsp
+}
+
+// getInvitation returns one invitation's status and expiry, e.g. to decide whether to offer a resend.
+// Shared by managers, who can only view invitations they sent, and admins, who can view any.
+func (server *Server) getInvitation(ctx *gin.Context) {
+	slog.Debug("Starting getInvitation handler")
+
+	var req getInvitationRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		slog.Debug("Get invitation URI bind error", "error", err)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	userID, err := getUserIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	invitation, err := server.store.GetInvitationByID(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("invitation not found")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Verify that the caller sent the invitation, unless they are an admin
+	authPayload, _ := getAuthorizationPayload(ctx)
+	if invitation.InviterID != userID && authPayload["role"] != string(db.UserRoleAdmin) {
+		slog.Debug("Attempted to view another inviter's invitation", "user_id", userID, "invitation_id", req.ID, "inviter_id", invitation.InviterID)
+		ctx.JSON(http.StatusForbidden, errorResponse(errors.New("you can only view invitations you sent")))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newInvitationStatusResponse(invitation, time.Now()))
+}
+
 ////////////////////////////////////////////////////////////////////////
 // Project Handler (for Managers) - Enhanced with Task Counts
 ////////////////////////////////////////////////////////////////////////
@@ -400,36 +1039,31 @@ type createProjectRequest struct {
 
 // createProject handles creating a new project by manager users
 func (server *Server) createProject(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting createProject handler")
+	slog.Debug("Starting createProject handler")
 
 	var req createProjectRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		log.Printf("DEBUG: Create project JSON bind error: %v", err)
+		slog.Debug("Create project JSON bind error", "error", err)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Creating project - Name: '%s', Description: '%s'", req.Name, req.Description)
+	slog.Debug("Creating project", "name", req.Name, "description", req.Description)
 
-	// Get authorization payload with proper error handling
-	authPayload, err := getAuthorizationPayload(ctx)
+	// Extract team ID from authorization payload
+	teamID, err := getTeamIDFromPayload(ctx)
 	if err != nil {
-		log.Printf("DEBUG: Failed to get authorization payload for project creation: %v", err)
-		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("unauthorized")))
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
-
-	// Safely extract team_id with type assertion
-	teamIDFloat, ok := authPayload["team_id"].(float64)
-	if !ok || teamIDFloat == 0 {
-		log.Printf("DEBUG: Manager is not assigned to a team. Auth payload: %+v", authPayload)
+	if teamID == 0 {
+		slog.Debug("Manager is not assigned to a team for project creation")
 		err := errors.New("forbidden: manager is not assigned to a team")
 		ctx.JSON(http.StatusForbidden, errorResponse(err))
 		return
 	}
 
-	teamID := int64(teamIDFloat)
-	log.Printf("DEBUG: Extracted Team ID: %d", teamID)
+	slog.Debug("Extracted team ID", "team_id", teamID)
 
 	arg := db.CreateProjectParams{
 		ProjectName: req.Name,
@@ -437,16 +1071,16 @@ func (server *Server) createProject(ctx *gin.Context) {
 		Description: pgtype.Text{String: req.Description, Valid: true},
 	}
 
-	log.Printf("DEBUG: Calling CreateProject with params: %+v", arg)
+	slog.Debug("Calling CreateProject with params", "arg", arg)
 
 	project, err := server.store.CreateProject(ctx, arg)
 	if err != nil {
-		log.Printf("DEBUG: Error creating project: %v", err)
+		slog.Debug("Error creating project", "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Successfully created project with ID: %d", project.ID)
+	slog.Debug("Successfully created project", "project_id", project.ID)
 	ctx.JSON(http.StatusCreated, project)
 }
 
@@ -465,37 +1099,31 @@ type projectWithTaskCounts struct {
 
 // listProjects handles retrieving projects with archive filtering and task counts
 func (server *Server) listProjects(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting listProjects handler")
+	slog.Debug("Starting listProjects handler")
 
 	var req listProjectsRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		log.Printf("DEBUG: List projects query bind error: %v", err)
+		slog.Debug("List projects query bind error", "error", err)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: List projects request params - PageID: %d, PageSize: %d, Archived: %v",
-		req.PageID, req.PageSize, req.Archived)
+	slog.Debug("List projects request params", "page_id", req.PageID, "page_size", req.PageSize, "archived", req.Archived)
 
-	// Get authorization payload with proper error handling
-	authPayload, err := getAuthorizationPayload(ctx)
+	// Extract team ID from authorization payload
+	teamID, err := getTeamIDFromPayload(ctx)
 	if err != nil {
-		log.Printf("DEBUG: Failed to get authorization payload for listing projects: %v", err)
-		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("unauthorized")))
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
-
-	// Safely extract team_id with type assertion
-	teamIDFloat, ok := authPayload["team_id"].(float64)
-	if !ok || teamIDFloat == 0 {
-		log.Printf("DEBUG: Manager is not assigned to a team for listing projects")
+	if teamID == 0 {
+		slog.Debug("Manager is not assigned to a team for listing projects")
 		err := errors.New("forbidden: manager is not assigned to a team")
 		ctx.JSON(http.StatusForbidden, errorResponse(err))
 		return
 	}
 
-	teamID := int64(teamIDFloat)
-	log.Printf("DEBUG: Extracted Team ID: %d", teamID)
+	slog.Debug("Extracted team ID", "team_id", teamID)
 
 	var projects []db.Project
 	var totalCount int64
@@ -512,7 +1140,7 @@ func (server *Server) listProjects(ctx *gin.Context) {
 		if err == nil {
 			totalCount, err = server.store.CountArchivedProjectsByTeam(ctx, teamID)
 		}
-		log.Printf("DEBUG: Listing archived projects")
+		slog.Debug("Listing archived projects")
 	} else {
 		// Show active projects (default)
 		activeParams := db.ListActiveProjectsByTeamParams{
@@ -524,52 +1152,120 @@ func (server *Server) listProjects(ctx *gin.Context) {
 		if err == nil {
 			totalCount, err = server.store.CountActiveProjectsByTeam(ctx, teamID)
 		}
-		log.Printf("DEBUG: Listing active projects")
+		slog.Debug("Listing active projects")
 	}
 
 	if err != nil {
-		log.Printf("DEBUG: Error listing projects: %v", err)
+		slog.Debug("Error listing projects", "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	// Enhance projects with task counts
-	enhancedProjects := make([]projectWithTaskCounts, 0, len(projects))
-	for _, project := range projects {
-		projectID := pgtype.Int8{Int64: project.ID, Valid: true}
+	// Enhance projects with task counts, fetched for the whole page in one query
+	projectIDs := make([]int64, len(projects))
+	for i, project := range projects {
+		projectIDs[i] = project.ID
+	}
 
-		// Get total active tasks count
-		totalTasks, err := server.store.CountActiveTasksByProject(ctx, projectID)
+	taskCounts := make(map[int64]db.CountTasksByProjectIDsRow, len(projects))
+	if len(projectIDs) > 0 {
+		rows, err := server.store.CountTasksByProjectIDs(ctx, projectIDs)
 		if err != nil {
-			log.Printf("DEBUG: Error counting tasks for project %d: %v", project.ID, err)
-			totalTasks = 0 // Continue with 0 if error
+			slog.Debug("Error counting tasks for projects", "error", err)
+			rows = nil // Continue with 0 counts if error
 		}
-
-		// Get completed tasks count
-		completedTasks, err := server.store.CountTasksByProjectAndStatus(ctx, db.CountTasksByProjectAndStatusParams{
-			ProjectID: projectID,
-			Status:    db.TaskStatusDone,
-		})
-		if err != nil {
-			log.Printf("DEBUG: Error counting completed tasks for project %d: %v", project.ID, err)
-			completedTasks = 0 // Continue with 0 if error
+		for _, row := range rows {
+			taskCounts[row.ProjectID.Int64] = row
 		}
+	}
 
+	enhancedProjects := make([]projectWithTaskCounts, 0, len(projects))
+	for _, project := range projects {
+		// Projects without active tasks have no row and keep 0 counts
+		counts := taskCounts[project.ID]
 		enhancedProjects = append(enhancedProjects, projectWithTaskCounts{
 			Project:        project,
-			TotalTasks:     totalTasks,
-			CompletedTasks: completedTasks,
+			TotalTasks:     counts.TotalCount,
+			CompletedTasks: counts.CompletedCount,
 		})
 	}
 
-	log.Printf("DEBUG: Retrieved %d projects for team %d, total count: %d", len(enhancedProjects), teamID, totalCount)
+	slog.Debug("Retrieved projects for team", "count", len(enhancedProjects), "team_id", teamID, "total_count", totalCount)
 
 	rsp := paginatedResponse[projectWithTaskCounts]{
 		TotalCount: totalCount,
 		Data:       enhancedProjects,
 	}
 
-	ctx.JSON(http.StatusOK, rsp)
+	// The JSON list is polled, so an unchanged page is answered with 304
+	respondListWithETag(ctx, http.StatusOK, rsp, enhancedProjects, projectCSVColumns)
+}
+
+// projectCSVColumns renders listProjects results for "Accept: text/csv"
+var projectCSVColumns = []csvColumn[projectWithTaskCounts]{
+	{"id", func(p projectWithTaskCounts) string { return csvInt(p.ID) }},
+	{"project_name", func(p projectWithTaskCounts) string { return p.ProjectName }},
+	{"description", func(p projectWithTaskCounts) string { return p.Description.String }},
+	{"archived", func(p projectWithTaskCounts) string { return strconv.FormatBool(p.Archived) }},
+	{"archived_at", func(p projectWithTaskCounts) string { return csvTimestamp(p.ArchivedAt) }},
+	{"total_tasks", func(p projectWithTaskCounts) string { return csvInt(p.TotalTasks) }},
+	{"completed_tasks", func(p projectWithTaskCounts) string { return csvInt(p.CompletedTasks) }},
+}
+
+// boardTaskLimit caps the total number of tasks returned on one page of the project board
+const boardTaskLimit = 500
+
+type getProjectBoardRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=20"`
+}
+
+// getProjectBoard returns a page of the team's active projects with their tasks nested
+func (server *Server) getProjectBoard(ctx *gin.Context) {
+	slog.Debug("Starting getProjectBoard handler")
+
+	var req getProjectBoardRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		slog.Debug("Project board query bind error", "error", err)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// Extract team ID from authorization payload
+	teamID, err := getTeamIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	if teamID == 0 {
+		slog.Debug("Manager is not assigned to a team for project board")
+		err := errors.New("forbidden: manager is not assigned to a team")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	board, err := server.store.GetTeamBoardTx(ctx, db.GetTeamBoardTxParams{
+		TeamID:    teamID,
+		Limit:     req.PageSize,
+		Offset:    (req.PageID - 1) * req.PageSize,
+		TaskLimit: boardTaskLimit,
+	})
+	if err != nil {
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
+		slog.Debug("Error loading project board for team", "team_id", teamID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	slog.Debug("Loaded project board", "projects", len(board.Projects), "team_id", teamID, "tasks_truncated", board.TasksTruncated)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"total_count":     board.TotalProjects,
+		"data":            board.Projects,
+		"tasks_truncated": board.TasksTruncated,
+	})
 }
 
 type getProjectRequest struct {
@@ -578,57 +1274,134 @@ type getProjectRequest struct {
 
 // getProject handles retrieving a specific project by ID (team-scoped)
 func (server *Server) getProject(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting getProject handler")
+	slog.Debug("Starting getProject handler")
 
 	var req getProjectRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		log.Printf("DEBUG: Get project URI bind error: %v", err)
+		slog.Debug("Get project URI bind error", "error", err)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Getting project with ID: %d", req.ID)
+	slog.Debug("Getting project", "project_id", req.ID)
 
-	// Get authorization payload with proper error handling
-	authPayload, err := getAuthorizationPayload(ctx)
+	// Extract team ID from authorization payload
+	teamID, err := getTeamIDFromPayload(ctx)
 	if err != nil {
-		log.Printf("DEBUG: Failed to get authorization payload for getting project: %v", err)
-		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("unauthorized")))
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
-
-	// Safely extract team_id with type assertion
-	teamIDFloat, ok := authPayload["team_id"].(float64)
-	if !ok || teamIDFloat == 0 {
-		log.Printf("DEBUG: Manager is not assigned to a team for getting project")
+	if teamID == 0 {
+		slog.Debug("Manager is not assigned to a team for getting project")
 		err := errors.New("forbidden: manager is not assigned to a team")
 		ctx.JSON(http.StatusForbidden, errorResponse(err))
 		return
 	}
 
-	teamID := int64(teamIDFloat)
-	log.Printf("DEBUG: Extracted Team ID: %d", teamID)
+	slog.Debug("Extracted team ID", "team_id", teamID)
 
 	// Use team-scoped project retrieval to ensure manager can only access their team's projects
-	project, err := server.store.GetProjectByIDAndTeam(ctx, db.GetProjectByIDAndTeamParams{
-		ID:     req.ID,
-		TeamID: teamID,
-	})
+	project, err := server.assertProjectInTeam(ctx, req.ID, teamID)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			log.Printf("DEBUG: Project not found or doesn't belong to manager's team")
-			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("project not found")))
-			return
-		}
-		log.Printf("DEBUG: Error getting project by ID and team: %v", err)
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		slog.Debug("Error getting project by ID and team", "error", err)
+		ctx.JSON(teamScopeErrorStatus(err), errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Successfully retrieved project: %s", project.ProjectName)
+	slog.Debug("Successfully retrieved project", "project_name", project.ProjectName)
 	ctx.JSON(http.StatusOK, project)
 }
 
+// projectStatsResponse summarizes a project's active tasks. Every status and priority is present,
+// with 0 when no task has it.
+type projectStatsResponse struct {
+	ProjectID            int64                               `json:"project_id"`
+	TotalTasks           int64                               `json:"total_tasks"`
+	ByStatus             map[db.TaskStatus]int64             `json:"by_status"`
+	ByPriority           map[db.TaskPriority]int64           `json:"by_priority"`
+	ByAssignee           []db.CountTasksByProjectAssigneeRow `json:"by_assignee"`            // Unassigned tasks have a null assignee
+	AvgCompletionSeconds float64                             `json:"avg_completion_seconds"` // From creation to completion of done tasks; 0 when none are done
+}
+
+// getProjectStats returns task counts by status, priority and assignee for one of the team's projects,
+// along with how long its done tasks took on average
+func (server *Server) getProjectStats(ctx *gin.Context) {
+	slog.Debug("Starting getProjectStats handler")
+
+	var req getProjectRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	teamID, err := getTeamIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	if teamID == 0 {
+		err := errors.New("forbidden: manager is not assigned to a team")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	if _, err := server.assertProjectInTeam(ctx, req.ID, teamID); err != nil {
+		ctx.JSON(teamScopeErrorStatus(err), errorResponse(err))
+		return
+	}
+	projectID := pgtype.Int8{Int64: req.ID, Valid: true}
+
+	counts, err := server.store.CountTasksByProjectStatusAndPriority(ctx, projectID)
+	if err != nil {
+		slog.Error("Failed to count project tasks by status and priority", "project_id", req.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	byAssignee, err := server.store.CountTasksByProjectAssignee(ctx, projectID)
+	if err != nil {
+		slog.Error("Failed to count project tasks by assignee", "project_id", req.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if byAssignee == nil {
+		byAssignee = []db.CountTasksByProjectAssigneeRow{}
+	}
+
+	avgCompletion, err := server.store.GetAverageCompletionTimeByProject(ctx, projectID)
+	if err != nil {
+		slog.Error("Failed to get project completion time", "project_id", req.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := projectStatsResponse{
+		ProjectID: req.ID,
+		ByStatus: map[db.TaskStatus]int64{
+			db.TaskStatusOpen:       0,
+			db.TaskStatusInProgress: 0,
+			db.TaskStatusDone:       0,
+		},
+		ByPriority:           make(map[db.TaskPriority]int64, len(taskPriorities)),
+		ByAssignee:           byAssignee,
+		AvgCompletionSeconds: avgCompletion,
+	}
+	for _, priority := range taskPriorities {
+		response.ByPriority[priority] = 0
+	}
+	for _, row := range counts {
+		response.TotalTasks += row.TaskCount
+		response.ByStatus[row.Status] += row.TaskCount
+		response.ByPriority[row.Priority] += row.TaskCount
+	}
+
+	slog.Debug("Returning project stats", "project_id", req.ID, "total_tasks", response.TotalTasks)
+	ctx.JSON(http.StatusOK, response)
+}
+
+// errStaleVersion rejects an update sent with a version that someone else's edit already replaced
+var errStaleVersion = errors.New("stale version: the record was changed by someone else, reload and try again")
+
 type updateProjectRequest struct {
 	ID int64 `uri:"id" binding:"required,min=1"`
 }
@@ -636,111 +1409,112 @@ type updateProjectRequest struct {
 type updateProjectBody struct {
 	Name        *string `json:"name"`
 	Description *string `json:"description"`
+	Version     int32   `json:"version" binding:"required,min=1"` // The version the client read
 }
 
 // updateProject handles updating a project's name and/or description
 func (server *Server) updateProject(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting updateProject handler")
+	slog.Debug("Starting updateProject handler")
 
 	var uriReq updateProjectRequest
 	if err := ctx.ShouldBindUri(&uriReq); err != nil {
-		log.Printf("DEBUG: Update project URI bind error: %v", err)
+		slog.Debug("Update project URI bind error", "error", err)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
 	var bodyReq updateProjectBody
 	if err := ctx.ShouldBindJSON(&bodyReq); err != nil {
-		log.Printf("DEBUG: Update project JSON bind error: %v", err)
+		slog.Debug("Update project JSON bind error", "error", err)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Updating project ID: %d", uriReq.ID)
+	slog.Debug("Updating project", "project_id", uriReq.ID)
 
 	// Validate that at least one field is being updated
 	if bodyReq.Name == nil && bodyReq.Description == nil {
-		log.Printf("DEBUG: No fields provided for update")
+		slog.Debug("No fields provided for update")
 		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("at least one field (name or description) must be provided")))
 		return
 	}
 
-	// Get authorization payload with proper error handling
-	authPayload, err := getAuthorizationPayload(ctx)
+	// Extract team ID from authorization payload
+	teamID, err := getTeamIDFromPayload(ctx)
 	if err != nil {
-		log.Printf("DEBUG: Failed to get authorization payload for updating project: %v", err)
-		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("unauthorized")))
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
-
-	// Safely extract team_id with type assertion
-	teamIDFloat, ok := authPayload["team_id"].(float64)
-	if !ok || teamIDFloat == 0 {
-		log.Printf("DEBUG: Manager is not assigned to a team for updating project")
+	if teamID == 0 {
+		slog.Debug("Manager is not assigned to a team for updating project")
 		err := errors.New("forbidden: manager is not assigned to a team")
 		ctx.JSON(http.StatusForbidden, errorResponse(err))
 		return
 	}
 
-	teamID := int64(teamIDFloat)
-	log.Printf("DEBUG: Extracted Team ID: %d", teamID)
+	slog.Debug("Extracted team ID", "team_id", teamID)
 
 	// First, verify the project exists and belongs to the manager's team
-	existingProject, err := server.store.GetProjectByIDAndTeam(ctx, db.GetProjectByIDAndTeamParams{
-		ID:     uriReq.ID,
-		TeamID: teamID,
-	})
+	existingProject, err := server.assertProjectInTeam(ctx, uriReq.ID, teamID)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			log.Printf("DEBUG: Project not found or doesn't belong to manager's team for update")
-			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("project not found")))
-			return
-		}
-		log.Printf("DEBUG: Error checking project ownership for update: %v", err)
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		slog.Debug("Error checking project ownership for update", "error", err)
+		ctx.JSON(teamScopeErrorStatus(err), errorResponse(err))
 		return
 	}
 
 	// Check if project is archived - cannot update archived projects
 	if existingProject.Archived {
-		log.Printf("DEBUG: Attempted to update archived project")
+		slog.Debug("Attempted to update archived project")
 		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("cannot update archived projects")))
 		return
 	}
 
-	// Prepare update parameters
+	// Someone else already changed the project since the client read it
+	if existingProject.Version != bodyReq.Version {
+		slog.Debug("Stale project version", "project_id", uriReq.ID, "version", bodyReq.Version, "current", existingProject.Version)
+		ctx.JSON(http.StatusConflict, errorResponse(errStaleVersion))
+		return
+	}
+
+	// Prepare update parameters; the version is checked again by the update itself
 	updateParams := db.UpdateProjectParams{
-		ID:     uriReq.ID,
-		TeamID: teamID,
+		ID:      uriReq.ID,
+		TeamID:  teamID,
+		Version: bodyReq.Version,
 	}
 
 	// Set project name (use new value if provided, otherwise use existing)
 	if bodyReq.Name != nil {
 		updateParams.ProjectName = *bodyReq.Name
-		log.Printf("DEBUG: Updating project name to: %s", *bodyReq.Name)
+		slog.Debug("Updating project name", "name", *bodyReq.Name)
 	} else {
 		updateParams.ProjectName = existingProject.ProjectName
-		log.Printf("DEBUG: Keeping existing project name: %s", existingProject.ProjectName)
+		slog.Debug("Keeping existing project name", "project_name", existingProject.ProjectName)
 	}
 
 	// Set description (use new value if provided, otherwise use existing)
 	if bodyReq.Description != nil {
 		updateParams.Description = pgtype.Text{String: *bodyReq.Description, Valid: true}
-		log.Printf("DEBUG: Updating project description")
+		slog.Debug("Updating project description")
 	} else {
 		updateParams.Description = existingProject.Description
-		log.Printf("DEBUG: Keeping existing project description")
+		slog.Debug("Keeping existing project description")
 	}
 
 	// Execute the update
 	updatedProject, err := server.store.UpdateProject(ctx, updateParams)
 	if err != nil {
-		log.Printf("DEBUG: Error updating project: %v", err)
+		// No row matched: a concurrent edit bumped the version after the check above
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusConflict, errorResponse(errStaleVersion))
+			return
+		}
+		slog.Debug("Error updating project", "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Successfully updated project with ID: %d", updatedProject.ID)
+	slog.Debug("Successfully updated project", "project_id", updatedProject.ID)
 	ctx.JSON(http.StatusOK, updatedProject)
 }
 
@@ -750,43 +1524,50 @@ type archiveProjectRequest struct {
 
 // archiveProject handles archiving a project and all its tasks
 func (server *Server) archiveProject(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting archiveProject handler")
+	slog.Debug("Starting archiveProject handler")
 
 	var req archiveProjectRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		log.Printf("DEBUG: Archive project URI bind error: %v", err)
+		slog.Debug("Archive project URI bind error", "error", err)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Archiving project with ID: %d", req.ID)
+	slog.Debug("Archiving project", "project_id", req.ID)
 
 	// Get authorization payload
 	authPayload, err := getAuthorizationPayload(ctx)
 	if err != nil {
-		log.Printf("DEBUG: Failed to get authorization payload for archiving project: %v", err)
+		slog.Debug("Failed to get authorization payload for archiving project", "error", err)
 		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("unauthorized")))
 		return
 	}
 
-	teamIDFloat, ok := authPayload["team_id"].(float64)
-	if !ok || teamIDFloat == 0 {
-		log.Printf("DEBUG: Manager is not assigned to a team for archiving project")
+	teamID, err := getTeamIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	if teamID == 0 {
+		slog.Debug("Manager is not assigned to a team for archiving project")
 		err := errors.New("forbidden: manager is not assigned to a team")
 		ctx.JSON(http.StatusForbidden, errorResponse(err))
 		return
 	}
 
-	teamID := int64(teamIDFloat)
-	log.Printf("DEBUG: Extracted Team ID: %d", teamID)
+	slog.Debug("Extracted team ID", "team_id", teamID)
 
 	// Archive the project and all its tasks using the transaction
 	result, err := server.store.ArchiveProjectTx(ctx, db.ArchiveProjectTxParams{
 		ProjectID: req.ID,
 		TeamID:    teamID,
+		ActorID:   actorIDFromPayload(authPayload),
 	})
 	if err != nil {
-		log.Printf("DEBUG: Error archiving project: %v", err)
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
+		slog.Debug("Error archiving project", "error", err)
 
 		switch {
 		case errors.Is(err, db.ErrProjectNotFound):
@@ -801,8 +1582,12 @@ func (server *Server) archiveProject(ctx *gin.Context) {
 		}
 	}
 
-	log.Printf("DEBUG: Successfully archived project with ID: %d and %d tasks",
-		result.ArchivedProject.ID, result.ArchivedTasksCount)
+	slog.Debug("Successfully archived project", "project_id", result.ArchivedProject.ID, "archived_tasks_count", result.ArchivedTasksCount)
+
+	server.fireWebhook(ctx, teamID, webhookEventProjectArchived, gin.H{
+		"project":              result.ArchivedProject,
+		"archived_tasks_count": result.ArchivedTasksCount,
+	})
 
 	// Return result with both project and task count
 	response := gin.H{
@@ -814,77 +1599,469 @@ func (server *Server) archiveProject(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, response)
 }
 
-////////////////////////////////////////////////////////////////////////
-// Task Handler (for Managers) - Enhanced with Project Tasks and Update
-////////////////////////////////////////////////////////////////////////
-
-type createTaskRequest struct {
-	ProjectID   int64  `json:"project_id" binding:"required,min=1"`
-	Title       string `json:"title" binding:"required"`
-	Description string `json:"description" binding:"required"`
-	Priority    string `json:"priority" binding:"required,oneof=low medium high critical"`
+type unarchiveProjectRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
 }
 
-func (server *Server) createTask(ctx *gin.Context) {
-	var req createTaskRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
+// unarchiveProject handles restoring an archived project and its tasks
+func (server *Server) unarchiveProject(ctx *gin.Context) {
+	slog.Debug("Starting unarchiveProject handler")
+
+	var req unarchiveProjectRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		slog.Debug("Unarchive project URI bind error", "error", err)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	authPayload, _ := getAuthorizationPayload(ctx)
-	managerTeamID, ok := authPayload["team_id"].(float64)
-	if !ok || managerTeamID == 0 {
+	slog.Debug("Unarchiving project", "project_id", req.ID)
+
+	// Get authorization payload
+	authPayload, err := getAuthorizationPayload(ctx)
+	if err != nil {
+		slog.Debug("Failed to get authorization payload for unarchiving project", "error", err)
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("unauthorized")))
+		return
+	}
+
+	teamID, err := getTeamIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	if teamID == 0 {
+		slog.Debug("Manager is not assigned to a team for unarchiving project")
 		err := errors.New("forbidden: manager is not assigned to a team")
 		ctx.JSON(http.StatusForbidden, errorResponse(err))
 		return
 	}
 
-	// Validate project belongs to manager's team and is not archived
-	project, err := server.store.GetProjectByIDAndTeam(ctx, db.GetProjectByIDAndTeamParams{
-		ID:     req.ProjectID,
-		TeamID: int64(managerTeamID),
+	// Restore the project and its tasks using the transaction
+	result, err := server.store.UnarchiveProjectTx(ctx, db.UnarchiveProjectTxParams{
+		ProjectID: req.ID,
+		TeamID:    teamID,
+		ActorID:   actorIDFromPayload(authPayload),
 	})
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("project not found")))
+		if abortIfTimedOut(ctx, err) {
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
+		slog.Debug("Error unarchiving project", "error", err)
 
-	// Cannot create tasks in archived projects
-	if project.Archived {
-		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("cannot create tasks in archived projects")))
-		return
+		switch {
+		case errors.Is(err, db.ErrProjectNotFound):
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		case errors.Is(err, db.ErrProjectNotArchived):
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
 	}
 
-	requiredSkills, err := server.skillzProcessor.ExtractAndNormalize(ctx, req.Description)
-	if err != nil {
-		log.Printf("❌ skillzProcessor error during task creation: %v\n", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not process task description for skills"})
-		return
-	}
+	slog.Debug("Successfully unarchived project", "project_id", result.UnarchivedProject.ID, "unarchived_tasks_count", result.UnarchivedTasksCount)
+
+	// Return result with both project and task count
+	response := gin.H{
+		"unarchived_project":     result.UnarchivedProject,
+		"unarchived_tasks_count": result.UnarchivedTasksCount,
+		"message":                fmt.Sprintf("Project and %d tasks unarchived successfully", result.UnarchivedTasksCount),
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+////////////////////////////////////////////////////////////////////////
+// Task Handler (for Managers) - Enhanced with Project Tasks and Update
+////////////////////////////////////////////////////////////////////////
+
+// taskPriorities lists every valid task priority
+var taskPriorities = []db.TaskPriority{
+	db.TaskPriorityLow,
+	db.TaskPriorityMedium,
+	db.TaskPriorityHigh,
+	db.TaskPriorityCritical,
+}
+
+// taskCreationStatuses are the statuses a team may have new tasks start in. Tasks cannot be created done,
+// and in_progress needs an assignee, which a new task does not have yet.
+var taskCreationStatuses = []db.TaskStatus{db.TaskStatusOpen}
+
+type createTaskRequest struct {
+	ProjectID   int64  `json:"project_id" binding:"required,min=1"`
+	Title       string `json:"title" binding:"required"`
+	Description string `json:"description" binding:"required"`
+	// Optional; defaults to the team's default priority, then to the server's DEFAULT_TASK_PRIORITY
+	Priority string `json:"priority" binding:"omitempty,oneof=low medium high critical"`
+	// When set, only skills that already exist or match an alias are linked;
+	// unknown extracted skills are returned as candidates instead of being created
+	StrictSkills bool `json:"strict_skills"`
+	// Optional RFC 3339 deadline; omitted means no deadline
+	DueDate *time.Time `json:"due_date"`
+}
+
+// createTaskResponse is the created task plus the engineer it was auto-assigned to, if any
+type createTaskResponse struct {
+	db.ProcessNewTaskTxResult
+	AutoAssignedTo  *EnrichedRecommendation `json:"auto_assigned_to,omitempty"`
+	SkillExtraction string                  `json:"skill_extraction"` // One of the skillExtraction values
+}
+
+// How skill extraction went for a new task; only "done" can leave it with required skills
+const (
+	skillExtractionDone    = "done"
+	skillExtractionSkipped = "skipped" // The description was too short to be worth an LLM call
+	skillExtractionFailed  = "failed"  // The LLM failed or timed out, so the task was created without skills
+)
+
+// defaultMinTaskDescriptionLength is the shortest description sent for skill extraction when
+// MIN_TASK_DESCRIPTION_LENGTH is not set; something like "fix bug" yields no useful skills
+const defaultMinTaskDescriptionLength = 20
+
+// extractTaskSkills returns the weighted skills a new task requires and how extraction went.
+// Short descriptions are not sent to the LLM, and a failed extraction is only logged, as in
+// acceptInvitation, so neither stops the task from being created.
+func (server *Server) extractTaskSkills(ctx context.Context, description string) ([]skillz.WeightedSkill, string) {
+	minLength := cmp.Or(server.config.MinTaskDescriptionLength, defaultMinTaskDescriptionLength)
+	if utf8.RuneCountInString(strings.TrimSpace(description)) < minLength {
+		slog.Debug("Task description too short for skill extraction", "min_length", minLength)
+		return nil, skillExtractionSkipped
+	}
+
+	llmCtx, cancel := server.llmContext(ctx)
+	defer cancel()
+	weightedSkills, err := server.skillzProcessor.ExtractRequiredSkillsWithWeight(llmCtx, description)
+	if err != nil {
+		slog.Warn("Could not extract required skills, creating the task without them", "error", err)
+		return nil, skillExtractionFailed
+	}
+	return weightedSkills, skillExtractionDone
+}
+
+// newTaskDefaults resolves a new task's priority and initial status. An omitted priority falls back to
+// the team's default, then to the server's DEFAULT_TASK_PRIORITY; the status follows the team's setting.
+func (server *Server) newTaskDefaults(team db.Team, requestedPriority string) (db.TaskPriority, db.TaskStatus) {
+	priority := cmp.Or(
+		db.TaskPriority(requestedPriority),
+		team.DefaultTaskPriority.TaskPriority,
+		db.TaskPriority(server.config.DefaultTaskPriority),
+		db.TaskPriorityMedium,
+	)
+	status := db.TaskStatusOpen
+	if team.DefaultTaskStatus.Valid && slices.Contains(taskCreationStatuses, team.DefaultTaskStatus.TaskStatus) {
+		status = team.DefaultTaskStatus.TaskStatus
+	}
+	return priority, status
+}
+
+// splitWeightedSkills separates extracted skills into the names a new task requires and their weights
+func splitWeightedSkills(weighted []skillz.WeightedSkill) ([]string, map[string]float64) {
+	names := make([]string, len(weighted))
+	weights := make(map[string]float64, len(weighted))
+	for i, skill := range weighted {
+		names[i] = skill.Name
+		weights[skill.Name] = skill.Weight
+	}
+	return names, weights
+}
+
+func (server *Server) createTask(ctx *gin.Context) {
+	var req createTaskRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload, _ := getAuthorizationPayload(ctx)
+	managerTeamID, err := getTeamIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	if managerTeamID == 0 {
+		err := errors.New("forbidden: manager is not assigned to a team")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	// Validate project belongs to manager's team and is not archived
+	project, err := server.assertProjectInTeam(ctx, req.ProjectID, managerTeamID)
+	if err != nil {
+		ctx.JSON(teamScopeErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	// Cannot create tasks in archived projects
+	if project.Archived {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("cannot create tasks in archived projects")))
+		return
+	}
+
+	// The team's settings decide the defaults and whether the task is auto-assigned
+	team, err := server.store.GetTeam(ctx, managerTeamID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	priority, status := server.newTaskDefaults(team, req.Priority)
+
+	weightedSkills, skillExtraction := server.extractTaskSkills(ctx, req.Description)
+	if abortIfCanceled(ctx) {
+		return
+	}
+	requiredSkills, skillWeights := splitWeightedSkills(weightedSkills)
 
 	arg := db.ProcessNewTaskTxParams{
 		CreateTaskParams: db.CreateTaskParams{
 			ProjectID:   pgtype.Int8{Int64: req.ProjectID, Valid: true},
 			Title:       req.Title,
 			Description: pgtype.Text{String: req.Description, Valid: true},
-			Status:      db.TaskStatusOpen,
-			Priority:    db.TaskPriority(req.Priority),
+			Status:      status,
+			Priority:    priority,
+			DueDate:     dueDateTimestamp(req.DueDate),
 		},
 		RequiredSkillNames: requiredSkills,
+		SkillWeights:       skillWeights,
+		StrictSkills:       req.StrictSkills,
+		ActorID:            actorIDFromPayload(authPayload),
 	}
 
 	result, err := server.store.ProcessNewTask(ctx, arg)
 	if err != nil {
+		if abortIfCanceled(ctx) || abortIfTimedOut(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := createTaskResponse{ProcessNewTaskTxResult: result, SkillExtraction: skillExtraction}
+
+	// Teams that opted in get open tasks handed to the top available recommendation
+	if team.AutoAssign && result.Task.Status == db.TaskStatusOpen {
+		assignment, err := server.autoAssignTask(ctx, result, team.ID, arg.ActorID)
+		if abortIfCanceled(ctx) {
+			return
+		}
+		if err != nil {
+			// The task itself was created, so leave it open rather than failing the request
+			slog.Error("Auto-assign failed for task", "task_id", result.Task.ID, "error", err)
+		} else if assignment != nil {
+			response.Task = assignment.Task
+			response.AutoAssignedTo = &EnrichedRecommendation{
+				UserID: assignment.User.ID,
+				Name:   assignment.User.Name.String,
+				Email:  assignment.User.Email,
+				Score:  assignment.Score,
+			}
+			// Only informational, so a failed count leaves it at zero instead of failing the request
+			if taskCounts, err := server.activeTaskCounts(ctx, []int64{assignment.User.ID}); err != nil {
+				slog.Error("Failed to count auto-assigned user's tasks", "user_id", assignment.User.ID, "error", err)
+			} else {
+				response.AutoAssignedTo.CurrentTaskCount = taskCounts[assignment.User.ID]
+			}
+		}
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// autoAssignment is the outcome of assigning a new task to a recommended engineer
+type autoAssignment struct {
+	db.AssignTaskToUserTxResult
+	Score float64
+}
+
+// autoAssignTask assigns a freshly created task to the highest ranked available engineer on the team,
+// on behalf of the manager who created it. While the recommender is down, engineers are ranked locally.
+// It returns nil without error when the task has no required skills or no candidate is available.
+func (server *Server) autoAssignTask(ctx context.Context, created db.ProcessNewTaskTxResult, teamID int64, actorID pgtype.Int8) (*autoAssignment, error) {
+	if len(created.TaskRequiredSkills) == 0 {
+		return nil, nil
+	}
+
+	recommenderResp, err := server.fetchRecommendations(ctx, created.TaskRequiredSkills, 10)
+	if err != nil {
+		if !isRecommenderOutage(err) {
+			return nil, err
+		}
+		// Rank the team locally instead, the same way getRecommendations falls back
+		slog.Warn("Recommender failed, auto-assigning from fallback recommendations", "task_id", created.Task.ID, "error", err)
+		fallback, err := server.fallbackRecommendations(ctx, teamID, created.TaskRequiredSkills, 10, true)
+		if err != nil {
+			return nil, err
+		}
+		recommenderResp = recommenderAPIResponse{Recommendations: make([]recommenderRecommendation, len(fallback))}
+		for i, rec := range fallback {
+			recommenderResp.Recommendations[i] = recommenderRecommendation{UserID: rec.UserID, Score: rec.Score}
+		}
+	}
+
+	users, err := server.recommendedUsers(ctx, recommenderResp)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rec := range recommenderResp.Recommendations {
+		user, found := users[rec.UserID]
+		if !found {
+			slog.Debug("Recommended user not found", "user_id", rec.UserID)
+			continue
+		}
+		if user.TeamID.Int64 != teamID || user.Role != db.UserRoleEngineer || user.Availability != db.AvailabilityStatusAvailable {
+			continue
+		}
+
+		result, err := server.store.AssignTaskToUser(ctx, db.AssignTaskToUserTxParams{
+			TaskID:  created.Task.ID,
+			UserID:  user.ID,
+			ActorID: actorID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		slog.Debug("Auto-assigned task to user", "task_id", created.Task.ID, "user_id", user.ID)
+		return &autoAssignment{AssignTaskToUserTxResult: result, Score: rec.Score}, nil
+	}
+
+	slog.Debug("No available candidate for task, leaving it open", "task_id", created.Task.ID)
+	return nil, nil
+}
+
+// maxBulkTasks caps how many tasks a single bulk request may create, bounding LLM calls and DB work
+const maxBulkTasks = 50
+
+// bulkTaskExtractionConcurrency bounds how many skill extractions a bulk request runs at once
+const bulkTaskExtractionConcurrency = 5
+
+type bulkCreateTasksURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// bulkTaskItem is one task of a bulk request; an omitted priority uses the same defaults as createTask
+type bulkTaskItem struct {
+	Title       string `json:"title" binding:"required"`
+	Description string `json:"description" binding:"required"`
+	Priority    string `json:"priority" binding:"omitempty,oneof=low medium high critical"`
+}
+
+// bulkCreatedTask is a created task together with the skill names extracted from its description
+type bulkCreatedTask struct {
+	db.ProcessNewTaskTxResult
+	ExtractedSkills []string `json:"extracted_skills"`
+	SkillExtraction string   `json:"skill_extraction"` // One of the skillExtraction values
+}
+
+// bulkCreateTasks creates many tasks in a project at once. Skills are extracted per description the
+// same way as in createTask, then all tasks are inserted in one transaction, so the batch is created
+// entirely or not at all. A failed extraction leaves only that task without skills.
+// Unlike createTask, bulk-created tasks are never auto-assigned.
+func (server *Server) bulkCreateTasks(ctx *gin.Context) {
+	slog.Debug("Starting bulkCreateTasks handler")
+
+	var uriReq bulkCreateTasksURIRequest
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req []bulkTaskItem
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		slog.Debug("Bulk task JSON bind error", "error", err)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if len(req) == 0 || len(req) > maxBulkTasks {
+		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("between 1 and %d tasks are required", maxBulkTasks)))
+		return
+	}
+
+	authPayload, _ := getAuthorizationPayload(ctx)
+	managerTeamID, err := getTeamIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	if managerTeamID == 0 {
+		err := errors.New("forbidden: manager is not assigned to a team")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	// Validate project belongs to manager's team and is not archived
+	project, err := server.assertProjectInTeam(ctx, uriReq.ID, managerTeamID)
+	if err != nil {
+		ctx.JSON(teamScopeErrorStatus(err), errorResponse(err))
+		return
+	}
+	if project.Archived {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("cannot create tasks in archived projects")))
+		return
+	}
+
+	team, err := server.store.GetTeam(ctx, managerTeamID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Extract skills for every description before touching the database
+	extracted := make([][]string, len(req))
+	weights := make([]map[string]float64, len(req))
+	extractions := make([]string, len(req))
+	var group errgroup.Group
+	group.SetLimit(bulkTaskExtractionConcurrency)
+	for i, item := range req {
+		group.Go(func() error {
+			var skills []skillz.WeightedSkill
+			skills, extractions[i] = server.extractTaskSkills(ctx, item.Description)
+			extracted[i], weights[i] = splitWeightedSkills(skills)
+			return nil
+		})
+	}
+	_ = group.Wait() // extractTaskSkills logs its failures instead of returning them
+	if abortIfCanceled(ctx) {
+		return
+	}
+
+	actorID := actorIDFromPayload(authPayload)
+	arg := db.ProcessManyTasksTxParams{Tasks: make([]db.ProcessNewTaskTxParams, len(req))}
+	for i, item := range req {
+		priority, status := server.newTaskDefaults(team, item.Priority)
+		arg.Tasks[i] = db.ProcessNewTaskTxParams{
+			CreateTaskParams: db.CreateTaskParams{
+				ProjectID:   pgtype.Int8{Int64: project.ID, Valid: true},
+				Title:       item.Title,
+				Description: pgtype.Text{String: item.Description, Valid: true},
+				Status:      status,
+				Priority:    priority,
+			},
+			RequiredSkillNames: extracted[i],
+			SkillWeights:       weights[i],
+			ActorID:            actorID,
+		}
+	}
+
+	result, err := server.store.ProcessManyTasksTx(ctx, arg)
+	if err != nil {
+		if abortIfCanceled(ctx) || abortIfTimedOut(ctx, err) {
+			return
+		}
+		slog.Error("Bulk task creation failed for project", "project_id", project.ID, "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, result)
+	tasks := make([]bulkCreatedTask, len(result.Tasks))
+	for i, created := range result.Tasks {
+		tasks[i] = bulkCreatedTask{ProcessNewTaskTxResult: created, ExtractedSkills: extracted[i], SkillExtraction: extractions[i]}
+	}
+
+	slog.Info("Bulk created tasks in project", "count", len(tasks), "project_id", project.ID)
+	ctx.JSON(http.StatusCreated, gin.H{"tasks": tasks})
 }
 
 type listProjectTasksURIRequest struct {
@@ -898,12 +2075,12 @@ type listProjectTasksQueryRequest struct {
 
 // listProjectTasks gets all tasks for a specific project with assignee names
 func (server *Server) listProjectTasks(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting listProjectTasks handler")
+	slog.Debug("Starting listProjectTasks handler")
 
 	// Bind URI parameters
 	var uriReq listProjectTasksURIRequest
 	if err := ctx.ShouldBindUri(&uriReq); err != nil {
-		log.Printf("DEBUG: List project tasks URI bind error: %v", err)
+		slog.Debug("List project tasks URI bind error", "error", err)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
@@ -911,56 +2088,40 @@ func (server *Server) listProjectTasks(ctx *gin.Context) {
 	// Bind query parameters
 	var queryReq listProjectTasksQueryRequest
 	if err := ctx.ShouldBindQuery(&queryReq); err != nil {
-		log.Printf("DEBUG: List project tasks query bind error: %v", err)
+		slog.Debug("List project tasks query bind error", "error", err)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Getting tasks for project ID: %d, PageID: %d, PageSize: %d",
-		uriReq.ID, queryReq.PageID, queryReq.PageSize)
+	slog.Debug("Getting tasks for project", "project_id", uriReq.ID, "page_id", queryReq.PageID, "page_size", queryReq.PageSize)
 
-	// Get authorization payload
-	authPayload, err := getAuthorizationPayload(ctx)
+	teamID, err := getTeamIDFromPayload(ctx)
 	if err != nil {
-		log.Printf("DEBUG: Failed to get authorization payload for project tasks: %v", err)
-		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("unauthorized")))
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
-
-	teamIDFloat, ok := authPayload["team_id"].(float64)
-	if !ok || teamIDFloat == 0 {
-		log.Printf("DEBUG: Manager is not assigned to a team for project tasks")
+	if teamID == 0 {
+		slog.Debug("Manager is not assigned to a team for project tasks")
 		err := errors.New("forbidden: manager is not assigned to a team")
 		ctx.JSON(http.StatusForbidden, errorResponse(err))
 		return
 	}
 
-	teamID := int64(teamIDFloat)
-
 	// Validate project belongs to manager's team
-	_, err = server.store.GetProjectByIDAndTeam(ctx, db.GetProjectByIDAndTeamParams{
-		ID:     uriReq.ID, // Use uriReq.ID instead of req.ID
-		TeamID: teamID,
-	})
-	if err != nil {
-		if err == pgx.ErrNoRows {
-			log.Printf("DEBUG: Project not found or doesn't belong to manager's team")
-			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("project not found")))
-			return
-		}
-		log.Printf("DEBUG: Error validating project ownership: %v", err)
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	if _, err = server.assertProjectInTeam(ctx, uriReq.ID, teamID); err != nil {
+		slog.Debug("Error validating project ownership", "error", err)
+		ctx.JSON(teamScopeErrorStatus(err), errorResponse(err))
 		return
 	}
 
-	// Get tasks with assignee names
-	tasks, err := server.store.ListTasksWithAssigneeNames(ctx, db.ListTasksWithAssigneeNamesParams{
+	// Get tasks with assignee names and required skill names in one query
+	tasks, err := server.store.ListTasksWithSkillsByProject(ctx, db.ListTasksWithSkillsByProjectParams{
 		ProjectID: pgtype.Int8{Int64: uriReq.ID, Valid: true}, // Use uriReq.ID
 		Limit:     queryReq.PageSize,                          // Use queryReq.PageSize
 		Offset:    (queryReq.PageID - 1) * queryReq.PageSize,  // Use queryReq values
 	})
 	if err != nil {
-		log.Printf("DEBUG: Error listing tasks with assignee names: %v", err)
+		slog.Debug("Error listing tasks with skills", "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
@@ -973,15 +2134,17 @@ func (server *Server) listProjectTasks(ctx *gin.Context) {
 		Priority     db.TaskPriority `json:"priority"`
 		AssigneeID   *int64          `json:"assignee_id"`
 		AssigneeName *string         `json:"assignee_name"`
+		SkillNames   []string        `json:"skill_names"`
 	}
 
 	taskResponses := make([]taskWithAssigneeResponse, 0, len(tasks))
 	for _, task := range tasks {
 		response := taskWithAssigneeResponse{
-			ID:       task.ID,
-			Title:    task.Title,
-			Status:   task.Status,
-			Priority: task.Priority,
+			ID:         task.ID,
+			Title:      task.Title,
+			Status:     task.Status,
+			Priority:   task.Priority,
+			SkillNames: task.SkillNames,
 		}
 
 		if task.AssigneeID.Valid {
@@ -995,8 +2158,102 @@ func (server *Server) listProjectTasks(ctx *gin.Context) {
 		taskResponses = append(taskResponses, response)
 	}
 
-	log.Printf("DEBUG: Retrieved %d tasks for project %d", len(taskResponses), uriReq.ID)
-	ctx.JSON(http.StatusOK, taskResponses)
+	slog.Debug("Retrieved tasks for project", "count", len(taskResponses), "project_id", uriReq.ID)
+	respondList(ctx, http.StatusOK, taskResponses, taskResponses, []csvColumn[taskWithAssigneeResponse]{
+		{"id", func(t taskWithAssigneeResponse) string { return csvInt(t.ID) }},
+		{"title", func(t taskWithAssigneeResponse) string { return t.Title }},
+		{"status", func(t taskWithAssigneeResponse) string { return string(t.Status) }},
+		{"priority", func(t taskWithAssigneeResponse) string { return string(t.Priority) }},
+		{"assignee_id", func(t taskWithAssigneeResponse) string {
+			if t.AssigneeID == nil {
+				return ""
+			}
+			return csvInt(*t.AssigneeID)
+		}},
+		{"assignee_name", func(t taskWithAssigneeResponse) string {
+			if t.AssigneeName == nil {
+				return ""
+			}
+			return *t.AssigneeName
+		}},
+		{"skill_names", func(t taskWithAssigneeResponse) string { return strings.Join(t.SkillNames, ";") }},
+	})
+}
+
+// exportProjectTasks downloads every non-archived task of a project on the manager's
+// team as CSV or JSON, fetching the tasks in batches while the response streams
+func (server *Server) exportProjectTasks(ctx *gin.Context) {
+	slog.Debug("Starting exportProjectTasks handler")
+
+	var uriReq struct {
+		ID int64 `uri:"id" binding:"required,min=1"`
+	}
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req exportRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	teamID, err := getTeamIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	if teamID == 0 {
+		slog.Debug("Manager is not assigned to a team for project tasks export")
+		err := errors.New("forbidden: manager is not assigned to a team")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	// Validate project belongs to manager's team
+	if _, err = server.assertProjectInTeam(ctx, uriReq.ID, teamID); err != nil {
+		slog.Debug("Error validating project ownership", "error", err)
+		ctx.JSON(teamScopeErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	slog.Debug("Exporting project tasks", "project_id", uriReq.ID, "format", req.Format)
+
+	// Page through the project by keyset on (created_at, id), so tasks sharing a creation time or
+	// changing mid-export are neither skipped nor repeated; a short batch means the last one was reached
+	params := db.ListTasksWithAssigneeNamesAfterParams{
+		ProjectID: pgtype.Int8{Int64: uriReq.ID, Valid: true},
+		Limit:     exportBatchSize,
+	}
+	done := false
+	next := func() ([]db.ListTasksWithAssigneeNamesAfterRow, error) {
+		if done {
+			return nil, nil
+		}
+		tasks, err := server.store.ListTasksWithAssigneeNamesAfter(ctx, params)
+		if len(tasks) > 0 {
+			last := tasks[len(tasks)-1]
+			params.AfterCreatedAt = last.CreatedAt
+			params.AfterID = pgtype.Int8{Int64: last.ID, Valid: true}
+		}
+		done = len(tasks) < exportBatchSize
+		return tasks, err
+	}
+
+	streamExport(ctx, req.Format, fmt.Sprintf("project-%d-tasks", uriReq.ID), next, []csvColumn[db.ListTasksWithAssigneeNamesAfterRow]{
+		{"id", func(t db.ListTasksWithAssigneeNamesAfterRow) string { return csvInt(t.ID) }},
+		{"title", func(t db.ListTasksWithAssigneeNamesAfterRow) string { return t.Title }},
+		{"status", func(t db.ListTasksWithAssigneeNamesAfterRow) string { return string(t.Status) }},
+		{"priority", func(t db.ListTasksWithAssigneeNamesAfterRow) string { return string(t.Priority) }},
+		{"assignee_id", func(t db.ListTasksWithAssigneeNamesAfterRow) string {
+			if !t.AssigneeID.Valid {
+				return ""
+			}
+			return csvInt(t.AssigneeID.Int64)
+		}},
+		{"assignee_name", func(t db.ListTasksWithAssigneeNamesAfterRow) string { return t.AssigneeName.String }},
+	})
 }
 
 type updateTaskRequest struct {
@@ -1005,19 +2262,40 @@ type updateTaskRequest struct {
 
 // updateTaskBody defines the structure for task update requests
 type updateTaskBody struct {
-	Title       *string `json:"title"`
-	Description *string `json:"description"`
-	Priority    *string `json:"priority" binding:"omitempty,oneof=low medium high critical"`
+	Title        *string    `json:"title"`
+	Description  *string    `json:"description"`
+	Priority     *string    `json:"priority" binding:"omitempty,oneof=low medium high critical"`
+	DueDate      *time.Time `json:"due_date"`
+	ClearDueDate bool       `json:"clear_due_date"` // Removes the task's deadline
+	Status       *string    `json:"status" binding:"omitempty,oneof=open in_progress done"`
+	Version      int32      `json:"version" binding:"required,min=1"` // The version the client read
+}
+
+// validateStatusTransition enforces the task status machine for manager edits:
+// open -> in_progress -> done -> open. Keeping the current status is always allowed.
+func validateStatusTransition(from, to db.TaskStatus) error {
+	if from == to {
+		return nil
+	}
+
+	switch {
+	case from == db.TaskStatusOpen && to == db.TaskStatusInProgress,
+		from == db.TaskStatusInProgress && to == db.TaskStatusDone,
+		from == db.TaskStatusDone && to == db.TaskStatusOpen:
+		return nil
+	}
+
+	return fmt.Errorf("invalid status transition: a task cannot move from %s to %s", from, to)
 }
 
 // updateTask handles updating task details
 func (server *Server) updateTask(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting updateTask handler")
+	slog.Debug("Starting updateTask handler")
 
 	// Parse task ID from URL parameters
 	var uriReq updateTaskRequest
 	if err := ctx.ShouldBindUri(&uriReq); err != nil {
-		log.Printf("DEBUG: Update task URI bind error: %v", err)
+		slog.Debug("Update task URI bind error", "error", err)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
@@ -1025,103 +2303,391 @@ func (server *Server) updateTask(ctx *gin.Context) {
 	// Parse request body containing fields to update
 	var bodyReq updateTaskBody
 	if err := ctx.ShouldBindJSON(&bodyReq); err != nil {
-		log.Printf("DEBUG: Update task JSON bind error: %v", err)
+		slog.Debug("Update task JSON bind error", "error", err)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Updating task ID: %d", uriReq.ID)
+	slog.Debug("Updating task", "task_id", uriReq.ID)
 
 	// Validate that at least one field is provided for update
-	if bodyReq.Title == nil && bodyReq.Description == nil && bodyReq.Priority == nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("at least one field (title, description, priority) must be provided")))
+	hasDetails := bodyReq.Title != nil || bodyReq.Description != nil || bodyReq.Priority != nil ||
+		bodyReq.DueDate != nil || bodyReq.ClearDueDate
+	if !hasDetails && bodyReq.Status == nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("at least one field (title, description, priority, due_date, clear_due_date, status) must be provided")))
 		return
 	}
-
-	// Extract and validate user authorization from context
-	authPayload, err := getAuthorizationPayload(ctx)
-	if err != nil {
-		log.Printf("DEBUG: Failed to get authorization payload for updating task: %v", err)
-		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("unauthorized")))
+	if bodyReq.DueDate != nil && bodyReq.ClearDueDate {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("due_date cannot be both set and cleared")))
 		return
 	}
 
 	// Extract team ID from authorization payload
-	teamIDFloat, ok := authPayload["team_id"].(float64)
-	if !ok || teamIDFloat == 0 {
-		log.Printf("DEBUG: Manager is not assigned to a team for updating task")
+	teamID, err := getTeamIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	if teamID == 0 {
+		slog.Debug("Manager is not assigned to a team for updating task")
 		err := errors.New("forbidden: manager is not assigned to a team")
 		ctx.JSON(http.StatusForbidden, errorResponse(err))
 		return
 	}
 
-	teamID := int64(teamIDFloat)
-
-	// Retrieve existing task from database
-	existingTask, err := server.store.GetTask(ctx, uriReq.ID)
+	// Retrieve existing task and verify it belongs to manager's team through project ownership
+	existingTask, err := server.assertTaskInTeam(ctx, uriReq.ID, teamID)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			log.Printf("DEBUG: Task not found")
-			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("task not found")))
-			return
-		}
-		log.Printf("DEBUG: Error getting existing task: %v", err)
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		slog.Debug("Error checking task ownership", "error", err)
+		ctx.JSON(teamScopeErrorStatus(err), errorResponse(err))
 		return
 	}
 
-	// Verify task belongs to manager's team through project ownership
-	project, err := server.store.GetProject(ctx, existingTask.ProjectID.Int64)
-	if err != nil {
-		log.Printf("DEBUG: Error getting task's project: %v", err)
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	// Prevent updates to archived tasks
+	if existingTask.Archived {
+		slog.Debug("Attempted to update archived task")
+		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("cannot update archived tasks")))
 		return
 	}
 
-	// Check team ownership authorization
-	if project.TeamID != teamID {
-		log.Printf("DEBUG: Task does not belong to manager's team")
-		ctx.JSON(http.StatusForbidden, errorResponse(errors.New("task does not belong to your team")))
+	// Someone else already changed the task since the client read it. UpdateTaskTx checks again
+	// under a row lock; this early check keeps the validation below against the version the client saw.
+	if existingTask.Version != bodyReq.Version {
+		slog.Debug("Stale task version", "task_id", uriReq.ID, "version", bodyReq.Version, "current", existingTask.Version)
+		ctx.JSON(http.StatusConflict, errorResponse(errStaleVersion))
 		return
 	}
 
-	// Prevent updates to archived tasks
-	if existingTask.Archived {
-		log.Printf("DEBUG: Attempted to update archived task")
-		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("cannot update archived tasks")))
+	actorID, err := getUserIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
 
-	// Initialize update parameters with task ID
-	updateParams := db.UpdateTaskParams{
-		ID: uriReq.ID,
+	updateParams := db.UpdateTaskTxParams{
+		TaskID:  uriReq.ID,
+		ActorID: actorID,
+		Version: bodyReq.Version,
 	}
 
-	// Set title field if provided in request
-	if bodyReq.Title != nil {
-		updateParams.Title = pgtype.Text{String: *bodyReq.Title, Valid: true}
-	}
+	// Validate the status change, so an illegal transition rejects the whole update
+	if bodyReq.Status != nil && db.TaskStatus(*bodyReq.Status) != existingTask.Status {
+		newStatus := db.TaskStatus(*bodyReq.Status)
+		if err := validateStatusTransition(existingTask.Status, newStatus); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
 
-	// Set description field if provided in request
-	if bodyReq.Description != nil {
-		updateParams.Description = pgtype.Text{String: *bodyReq.Description, Valid: true}
-	}
+		if newStatus == db.TaskStatusInProgress && !existingTask.AssigneeID.Valid {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("task must be assigned before it can be moved to in_progress")))
+			return
+		}
 
-	// Set priority field if provided in request
-	if bodyReq.Priority != nil {
-		updateParams.Priority = db.NullTaskPriority{TaskPriority: db.TaskPriority(*bodyReq.Priority), Valid: true}
+		updateParams.Status = db.NullTaskStatus{TaskStatus: newStatus, Valid: true}
 	}
 
-	// Execute task update in database
-	updatedTask, err := server.store.UpdateTask(ctx, updateParams)
-	if err != nil {
-		log.Printf("DEBUG: Error updating task: %v", err)
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
+	if hasDetails {
+		details := db.UpdateTaskParams{}
 
-	// Return updated task data to client
-	ctx.JSON(http.StatusOK, updatedTask)
+		// Set title field if provided in request
+		if bodyReq.Title != nil {
+			details.Title = pgtype.Text{String: *bodyReq.Title, Valid: true}
+		}
+
+		// Set description field if provided in request
+		if bodyReq.Description != nil {
+			details.Description = pgtype.Text{String: *bodyReq.Description, Valid: true}
+		}
+
+		// Set priority field if provided in request
+		if bodyReq.Priority != nil {
+			details.Priority = db.NullTaskPriority{TaskPriority: db.TaskPriority(*bodyReq.Priority), Valid: true}
+		}
+
+		// Set or clear the due date if requested
+		if bodyReq.DueDate != nil {
+			details.DueDate = dueDateTimestamp(bodyReq.DueDate)
+		}
+		details.ClearDueDate = bodyReq.ClearDueDate
+
+		updateParams.Details = &details
+	}
+
+	// Check the version, change the status and update the details in one transaction
+	result, err := server.store.UpdateTaskTx(ctx, updateParams)
+	if err != nil {
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, db.ErrStaleTaskVersion):
+			ctx.JSON(http.StatusConflict, errorResponse(errStaleVersion))
+		case errors.Is(err, db.ErrTaskStatusConflict):
+			ctx.JSON(http.StatusConflict, errorResponse(errors.New("task status changed concurrently, reload and try again")))
+		case errors.Is(err, db.ErrEngineerOnLeave):
+			ctx.JSON(http.StatusConflict, errorResponse(errors.New("the assignee is on leave")))
+		default:
+			slog.Error("Failed to update task", "task_id", uriReq.ID, "error", err)
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	if result.Task.Status != result.PreviousStatus {
+		slog.Info("Task status changed", "task_id", uriReq.ID, "from", result.PreviousStatus, "to", result.Task.Status)
+		if result.Task.Status == db.TaskStatusDone {
+			server.fireTaskCompleted(ctx, result.Task)
+		}
+	}
+
+	// The description is what skills are extracted from, so cached recommendations may no longer fit
+	if bodyReq.Description != nil {
+		server.recommendations.invalidateTask(uriReq.ID)
+	}
+
+	// Return updated task data to client
+	ctx.JSON(http.StatusOK, result.Task)
+}
+
+type archiveTaskRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// archiveTask archives a single task of the manager's team, freeing its assignee
+func (server *Server) archiveTask(ctx *gin.Context) {
+	slog.Debug("Starting archiveTask handler")
+
+	var req archiveTaskRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload, _ := getAuthorizationPayload(ctx)
+	managerTeamID, err := getTeamIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	if managerTeamID == 0 {
+		err := errors.New("forbidden: manager is not assigned to a team")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	// Verify the task belongs to the manager's team through its project
+	if _, err := server.assertTaskInTeam(ctx, req.ID, managerTeamID); err != nil {
+		ctx.JSON(teamScopeErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	result, err := server.store.ArchiveTaskTx(ctx, db.ArchiveTaskTxParams{
+		TaskID:  req.ID,
+		ActorID: actorIDFromPayload(authPayload),
+	})
+	if err != nil {
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
+		if errors.Is(err, db.ErrTaskAlreadyArchived) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		slog.Error("Failed to archive task", "task_id", req.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	slog.Info("Task archived", "task_id", req.ID)
+	ctx.JSON(http.StatusOK, result.Task)
+}
+
+// restoreTask brings an archived task of the manager's team back as open and unassigned
+func (server *Server) restoreTask(ctx *gin.Context) {
+	slog.Debug("Starting restoreTask handler")
+
+	var req archiveTaskRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload, _ := getAuthorizationPayload(ctx)
+	managerTeamID, err := getTeamIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	if managerTeamID == 0 {
+		err := errors.New("forbidden: manager is not assigned to a team")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	// Verify the task belongs to the manager's team through its project
+	if _, err := server.assertTaskInTeam(ctx, req.ID, managerTeamID); err != nil {
+		ctx.JSON(teamScopeErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	result, err := server.store.RestoreTaskTx(ctx, db.ArchiveTaskTxParams{
+		TaskID:  req.ID,
+		ActorID: actorIDFromPayload(authPayload),
+	})
+	if err != nil {
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, db.ErrTaskNotArchived), errors.Is(err, db.ErrTaskProjectArchived):
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+		default:
+			slog.Error("Failed to restore task", "task_id", req.ID, "error", err)
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	slog.Info("Task restored", "task_id", req.ID)
+	ctx.JSON(http.StatusOK, result.Task)
+}
+
+type listOverdueTasksRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=100"`
+}
+
+// listOverdueTasks lists a page of the team's tasks that are past their due date and not yet done,
+// most overdue first.
+func (server *Server) listOverdueTasks(ctx *gin.Context) {
+	slog.Debug("Starting listOverdueTasks handler")
+
+	var req listOverdueTasksRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	managerTeamID, err := getTeamIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	if managerTeamID == 0 {
+		err := errors.New("forbidden: manager is not assigned to a team")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	tasks, err := server.store.ListOverdueTasksByTeam(ctx, db.ListOverdueTasksByTeamParams{
+		TeamID: managerTeamID,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if tasks == nil {
+		tasks = []db.Task{}
+	}
+
+	totalCount, err := server.store.CountOverdueTasksByTeam(ctx, managerTeamID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, paginatedResponse[db.Task]{
+		TotalCount: totalCount,
+		Data:       tasks,
+	})
+}
+
+type searchTasksRequest struct {
+	Query      string `form:"q" binding:"max=200"`                                         // Full-text terms or part of a title; empty matches every task
+	Status     string `form:"status" binding:"omitempty,oneof=open in_progress done"`      // Optional status filter
+	Priority   string `form:"priority" binding:"omitempty,oneof=low medium high critical"` // Optional priority filter
+	AssigneeID int64  `form:"assignee_id" binding:"omitempty,min=1"`                       // Optional assignee filter
+	Archived   bool   `form:"archived"`                                                    // Search archived instead of active tasks
+	PageID     int32  `form:"page_id" binding:"required,min=1"`
+	PageSize   int32  `form:"page_size" binding:"required,min=5,max=100"`
+}
+
+// searchTasks searches the tasks in all of the manager's projects by text, status, priority and assignee
+func (server *Server) searchTasks(ctx *gin.Context) {
+	slog.Debug("Starting searchTasks handler")
+
+	var req searchTasksRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		slog.Debug("Search tasks query bind error", "error", err)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	managerTeamID, err := getTeamIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	if managerTeamID == 0 {
+		err := errors.New("forbidden: manager is not assigned to a team")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	// Unset filters are passed as NULL so the query skips them; the team scope comes from the token
+	query := strings.TrimSpace(req.Query)
+	countArg := db.CountSearchTasksByTeamParams{
+		TeamID:       managerTeamID,
+		Query:        query,
+		TitlePattern: "%" + util.EscapeLike(query) + "%", // "100%" matches itself, not everything
+		Status:       db.NullTaskStatus{TaskStatus: db.TaskStatus(req.Status), Valid: req.Status != ""},
+		Priority:     db.NullTaskPriority{TaskPriority: db.TaskPriority(req.Priority), Valid: req.Priority != ""},
+		AssigneeID:   pgtype.Int8{Int64: req.AssigneeID, Valid: req.AssigneeID != 0},
+		Archived:     req.Archived,
+	}
+
+	tasks, err := server.store.SearchTasksByTeam(ctx, db.SearchTasksByTeamParams{
+		TeamID:       countArg.TeamID,
+		Query:        countArg.Query,
+		TitlePattern: countArg.TitlePattern,
+		Status:       countArg.Status,
+		Priority:     countArg.Priority,
+		AssigneeID:   countArg.AssigneeID,
+		Archived:     countArg.Archived,
+		Limit:        req.PageSize,
+		Offset:       (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if tasks == nil {
+		tasks = []db.SearchTasksByTeamRow{}
+	}
+
+	totalCount, err := server.store.CountSearchTasksByTeam(ctx, countArg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	slog.Debug("Searched team tasks", "team_id", countArg.TeamID, "count", len(tasks), "total_count", totalCount)
+	ctx.JSON(http.StatusOK, paginatedResponse[db.SearchTasksByTeamRow]{
+		TotalCount: totalCount,
+		Data:       tasks,
+	})
+}
+
+// dueDateTimestamp converts an optional due date from a request body; nil means no deadline.
+// The column has no time zone, so deadlines are stored in UTC and the overdue queries
+// compare them against the current time in UTC.
+func dueDateTimestamp(dueDate *time.Time) pgtype.Timestamp {
+	if dueDate == nil {
+		return pgtype.Timestamp{}
+	}
+	return pgtype.Timestamp{Time: dueDate.UTC(), Valid: true}
 }
 
 type assignTaskRequest struct {
@@ -1135,7 +2701,7 @@ type assignTaskURI struct {
 // assignTask handles assigning a task to an engineer.
 // It uses a transaction to ensure both the task and user states are updated atomically.
 func (server *Server) assignTask(ctx *gin.Context) {
-	log.Printf("DEBUG: Starting assignTask handler")
+	slog.Debug("Starting assignTask handler")
 
 	var uri assignTaskURI
 	if err := ctx.ShouldBindUri(&uri); err != nil {
@@ -1150,20 +2716,15 @@ func (server *Server) assignTask(ctx *gin.Context) {
 	}
 
 	// --- Ownership and Permission Validation (Essential) ---
-	authPayload, _ := getAuthorizationPayload(ctx)
-	managerTeamID, _ := authPayload["team_id"].(float64)
-
-	// Validate the task belongs to the manager's team
-	task, err := server.store.GetTask(ctx, uri.TaskID)
+	managerTeamID, err := getTeamIDFromPayload(ctx)
 	if err != nil {
-		// Handle not found, etc.
-		ctx.JSON(http.StatusNotFound, errorResponse(errors.New("task not found")))
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
 
-	project, _ := server.store.GetProject(ctx, task.ProjectID.Int64)
-	if project.TeamID != int64(managerTeamID) {
-		ctx.JSON(http.StatusForbidden, errorResponse(errors.New("task does not belong to your team")))
+	// Validate the task belongs to the manager's team
+	if _, err := server.assertTaskInTeam(ctx, uri.TaskID, managerTeamID); err != nil {
+		ctx.JSON(teamScopeErrorStatus(err), errorResponse(err))
 		return
 	}
 
@@ -1173,215 +2734,700 @@ func (server *Server) assignTask(ctx *gin.Context) {
 		ctx.JSON(http.StatusNotFound, errorResponse(errors.New("user to assign not found")))
 		return
 	}
-	if !userToAssign.TeamID.Valid || userToAssign.TeamID.Int64 != int64(managerTeamID) {
+	if !userToAssign.TeamID.Valid || userToAssign.TeamID.Int64 != managerTeamID {
 		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("assignee must be from your team")))
 		return
 	}
 	// --- End Validation ---
 
+	authPayload, _ := getAuthorizationPayload(ctx)
 	arg := db.AssignTaskToUserTxParams{
-		TaskID: uri.TaskID,
-		UserID: req.UserID,
+		TaskID:  uri.TaskID,
+		UserID:  req.UserID,
+		ActorID: actorIDFromPayload(authPayload),
 	}
 
-	// This call is fully transactional and safe
+	// This call is fully transactional and safe; it refuses engineers on leave
 	result, err := server.store.AssignTaskToUser(ctx, arg)
 	if err != nil {
-		log.Printf("DEBUG: Error assigning task: %v", err)
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
+		slog.Debug("Error assigning task", "error", err)
+		if errors.Is(err, db.ErrEngineerOnLeave) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Successfully assigned task %d to user %d", result.Task.ID, result.User.ID)
+	slog.Debug("Successfully assigned task to user", "task_id", result.Task.ID, "user_id", result.User.ID)
 	ctx.JSON(http.StatusOK, result)
 }
 
+// assigneeResponse is an engineer whose availability a reassignment changed, without the password hash
+type assigneeResponse struct {
+	ID           int64                 `json:"id"`
+	Name         string                `json:"name"`
+	Availability db.AvailabilityStatus `json:"availability"`
+}
+
+func newAssigneeResponse(user db.User) assigneeResponse {
+	return assigneeResponse{ID: user.ID, Name: user.Name.String, Availability: user.Availability}
+}
+
+type reassignTaskResponse struct {
+	Task             db.Task           `json:"task"`
+	NewAssignee      assigneeResponse  `json:"new_assignee"`
+	PreviousAssignee *assigneeResponse `json:"previous_assignee"` // Null when the task had no assignee
+}
+
+// reassignTask hands a task of the manager's team to another available engineer on the team.
+// Unlike assignTask, the previous assignee is freed in the same transaction.
+func (server *Server) reassignTask(ctx *gin.Context) {
+	slog.Debug("Starting reassignTask handler")
+
+	var uri assignTaskURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req assignTaskRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload, _ := getAuthorizationPayload(ctx)
+	managerTeamID, err := getTeamIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	if managerTeamID == 0 {
+		err := errors.New("forbidden: manager is not assigned to a team")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	// Validate the task belongs to the manager's team; the transaction checks the new assignee
+	if _, err := server.assertTaskInTeam(ctx, uri.TaskID, managerTeamID); err != nil {
+		ctx.JSON(teamScopeErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	result, err := server.store.ReassignTaskTx(ctx, db.ReassignTaskTxParams{
+		TaskID:        uri.TaskID,
+		NewAssigneeID: req.UserID,
+		TeamID:        managerTeamID,
+		ActorID:       actorIDFromPayload(authPayload),
+	})
+	if err != nil {
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, db.ErrAssigneeNotOnTeam):
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		case errors.Is(err, db.ErrTaskNotReassignable), errors.Is(err, db.ErrTaskAlreadyAssigned), errors.Is(err, db.ErrEngineerNotAvailable), errors.Is(err, db.ErrEngineerOnLeave):
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+		default:
+			slog.Error("Failed to reassign task", "task_id", uri.TaskID, "error", err)
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	response := reassignTaskResponse{
+		Task:        result.Task,
+		NewAssignee: newAssigneeResponse(result.NewAssignee),
+	}
+	if result.PreviousAssignee != nil {
+		previous := newAssigneeResponse(*result.PreviousAssignee)
+		response.PreviousAssignee = &previous
+	}
+
+	slog.Info("Task reassigned", "task_id", uri.TaskID, "user_id", req.UserID)
+	ctx.JSON(http.StatusOK, response)
+}
+
 ////////////////////////////////////////////////////////////////////////
 // Recommendation Handler (for Managers)
 ////////////////////////////////////////////////////////////////////////
 
 type getRecommendationsRequest struct {
-	TaskID int64 `json:"task_id" binding:"required,min=1"`
-	Limit  int   `json:"limit,omitempty"`
+	TaskID      int64 `json:"task_id" binding:"required,min=1"`
+	Limit       int   `json:"limit,omitempty"`
+	ExcludeBusy bool  `json:"exclude_busy,omitempty"` // Leave out engineers whose availability is busy
 }
 
 type recommenderAPIRequest struct {
-	SkillIDs []int32 `json:"skill_ids"`
-	Limit    int     `json:"limit"`
+	SkillIDs []int32                  `json:"skill_ids"` // Kept for recommenders that predate skill weights
+	Skills   []recommenderSkillWeight `json:"skills"`
+	Limit    int                      `json:"limit"`
+}
+
+// recommenderSkillWeight tells the recommender how central a required skill is to the task
+type recommenderSkillWeight struct {
+	ID     int32   `json:"id"`
+	Weight float64 `json:"weight"`
 }
 
 type recommenderAPIResponse struct {
-	Recommendations []struct {
-		UserID int64   `json:"user_id"`
-		Score  float64 `json:"score"`
-	} `json:"recommendations"`
+	Recommendations []recommenderRecommendation `json:"recommendations"`
 }
 
-type EnrichedRecommendation struct {
+// recommenderRecommendation is one ranked engineer in a recommender answer
+type recommenderRecommendation struct {
 	UserID int64   `json:"user_id"`
-	Name   string  `json:"name"`
-	Email  string  `json:"email"`
 	Score  float64 `json:"score"`
 }
 
-func (server *Server) getRecommendations(ctx *gin.Context) {
-	var req getRecommendationsRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		log.Printf("ERROR: Bind error: %v", err)
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
-		return
+// EnrichedRecommendation is a recommended engineer with their current workload,
+// so managers can break ties between equally-skilled engineers
+type EnrichedRecommendation struct {
+	UserID           int64   `json:"user_id"`
+	Name             string  `json:"name"`
+	Email            string  `json:"email"`
+	Score            float64 `json:"score"`
+	CurrentTaskCount int64   `json:"current_task_count"` // Open and in-progress tasks assigned to them
+	Source           string  `json:"source"`             // recommendationSourceRecommender or recommendationSourceFallback
+}
+
+// Where a recommendation came from
+const (
+	recommendationSourceRecommender = "recommender"
+	recommendationSourceFallback    = "fallback" // Ranked locally because the recommender could not answer
+)
+
+// fallbackProficiencyWeights scores an engineer's proficiency in a required skill when ranking locally
+var fallbackProficiencyWeights = map[db.ProficiencyLevel]float64{
+	db.ProficiencyLevelBeginner:     1,
+	db.ProficiencyLevelIntermediate: 2,
+	db.ProficiencyLevelExpert:       3,
+}
+
+// defaultRecommenderTimeout limits one recommender call when RECOMMENDER_TIMEOUT is not set
+const defaultRecommenderTimeout = 10 * time.Second
+
+// Errors returned by fetchRecommendations when the recommender cannot serve a request
+var (
+	errRecommenderUnavailable = errors.New("recommendation service is unavailable")
+	errRecommenderFailed      = errors.New("recommendation service failed")
+	errRecommenderBadResponse = errors.New("failed to parse recommendation response")
+)
+
+// fetchRecommendations asks the recommender service for the best engineers for a task's weighted skills
+func (server *Server) fetchRecommendations(ctx context.Context, requiredSkills []db.TaskRequiredSkill, limit int) (recommenderAPIResponse, error) {
+	var recommenderResp recommenderAPIResponse
+
+	recommenderReqPayload := recommenderAPIRequest{
+		SkillIDs: make([]int32, len(requiredSkills)),
+		Skills:   make([]recommenderSkillWeight, len(requiredSkills)),
+		Limit:    limit,
+	}
+	for i, skill := range requiredSkills {
+		recommenderReqPayload.SkillIDs[i] = int32(skill.SkillID)
+		recommenderReqPayload.Skills[i] = recommenderSkillWeight{ID: int32(skill.SkillID), Weight: skill.Weight}
 	}
+	recommenderBody, _ := json.Marshal(recommenderReqPayload)
 
-	log.Printf("DEBUG: Getting recommendations for task ID: %d", req.TaskID)
-	log.Printf("DEBUG: Recommender API URL: %s", server.config.RecommenderAPIURL)
-	log.Printf("DEBUG: Recommender API Key exists: %t", server.config.RecommenderAPIKey != "")
+	slog.Debug("Calling recommender API", "payload", string(recommenderBody))
 
-	authPayload, _ := getAuthorizationPayload(ctx)
-	managerTeamID, ok := authPayload["team_id"].(float64)
-	if !ok || managerTeamID == 0 {
-		err := errors.New("forbidden: manager is not assigned to a team")
-		log.Printf("ERROR: %v", err)
-		ctx.JSON(http.StatusForbidden, errorResponse(err))
-		return
+	// parse the base URL from the config
+	baseURL, err := url.Parse(server.config.RecommenderAPIURL)
+	if err != nil {
+		return recommenderResp, fmt.Errorf("failed to parse recommender base URL: %w", err)
 	}
 
-	log.Printf("DEBUG: Manager team ID: %v", managerTeamID)
+	// Safely join the '/recommend' path to the base URL
+	baseURL.Path = path.Join(baseURL.Path, "/recommend")
+	endpointURL := baseURL.String()
+
+	slog.Debug("Recommender API endpoint", "endpoint_url", endpointURL)
 
-	task, err := server.store.GetTask(ctx, req.TaskID)
+	// Create the request using the newly constructed url
+	request, err := http.NewRequestWithContext(ctx, "POST", endpointURL, bytes.NewBuffer(recommenderBody))
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			log.Printf("ERROR: Task not found: %d", req.TaskID)
-			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("task not found")))
-			return
+		return recommenderResp, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Internal-API-Key", server.config.RecommenderAPIKey)
+
+	start := time.Now()
+	response, err := server.recommenderClient.Do(request)
+	if err != nil {
+		slog.Error("HTTP request failed", "error", err)
+		metrics.ObserveRecommenderCall(metrics.RecommenderOutcomeUnavailable, start)
+		return recommenderResp, errRecommenderUnavailable
+	}
+	defer response.Body.Close()
+
+	bodyBytes, _ := io.ReadAll(response.Body)
+	slog.Debug("Recommender API response status", "status_code", response.StatusCode)
+	slog.Debug("Recommender API response body", "body", string(bodyBytes))
+
+	if response.StatusCode != http.StatusOK {
+		metrics.ObserveRecommenderCall(metrics.RecommenderOutcomeHTTPError, start)
+		return recommenderResp, fmt.Errorf("%w: %s", errRecommenderFailed, string(bodyBytes))
+	}
+
+	if err := json.Unmarshal(bodyBytes, &recommenderResp); err != nil {
+		slog.Error("Failed to parse JSON response", "error", err)
+		metrics.ObserveRecommenderCall(metrics.RecommenderOutcomeParseError, start)
+		return recommenderResp, errRecommenderBadResponse
+	}
+
+	metrics.ObserveRecommenderCall(metrics.RecommenderOutcomeSuccess, start)
+	return recommenderResp, nil
+}
+
+// activeTaskCounts returns each user's number of unfinished tasks, keyed by user ID.
+// Users without unfinished tasks are absent from the map, so a lookup yields zero.
+func (server *Server) activeTaskCounts(ctx context.Context, userIDs []int64) (map[int64]int64, error) {
+	rows, err := server.store.CountActiveTasksByAssignees(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int64]int64, len(rows))
+	for _, row := range rows {
+		counts[row.AssigneeID.Int64] = row.TaskCount
+	}
+	return counts, nil
+}
+
+// rankSkillMatches scores each engineer by the task's required skills they have, weighting every
+// match by the skill's weight on the task and the engineer's proficiency in it. Scores run from
+// 0 to 1, where 1 means expert in every required skill; ties go to the lower user ID.
+func rankSkillMatches(matches []db.GetTeamMembersWithSkillMatchRow, requiredSkills []db.TaskRequiredSkill) []EnrichedRecommendation {
+	skillWeights := make(map[int64]float64, len(requiredSkills))
+	var maxScore float64
+	for _, skill := range requiredSkills {
+		skillWeights[skill.SkillID] = skill.Weight
+		maxScore += skill.Weight * fallbackProficiencyWeights[db.ProficiencyLevelExpert]
+	}
+
+	var ranked []EnrichedRecommendation
+	byUser := make(map[int64]int) // Index into ranked
+	for _, match := range matches {
+		i, ok := byUser[match.UserID]
+		if !ok {
+			i = len(ranked)
+			byUser[match.UserID] = i
+			ranked = append(ranked, EnrichedRecommendation{
+				UserID: match.UserID,
+				Name:   match.Name.String,
+				Email:  match.Email,
+				Source: recommendationSourceFallback,
+			})
 		}
-		log.Printf("ERROR: GetTask failed: %v", err)
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
+		ranked[i].Score += skillWeights[match.SkillID] * fallbackProficiencyWeights[match.Proficiency]
 	}
+	for i := range ranked {
+		ranked[i].Score /= maxScore
+	}
+
+	slices.SortStableFunc(ranked, func(a, b EnrichedRecommendation) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.UserID, b.UserID))
+	})
+	return ranked
+}
 
-	log.Printf("DEBUG: Found task: %+v", task)
+// isRecommenderOutage reports whether fetchRecommendations failed because the recommender could not
+// answer, as opposed to the request itself being canceled or invalid
+func isRecommenderOutage(err error) bool {
+	return errors.Is(err, errRecommenderUnavailable) || errors.Is(err, errRecommenderFailed) || errors.Is(err, errRecommenderBadResponse)
+}
 
-	project, err := server.store.GetProject(ctx, task.ProjectID.Int64)
+// fallbackRecommendations ranks the team's engineers locally from their skill profiles, so
+// managers still get recommendations while the recommender service is down
+func (server *Server) fallbackRecommendations(ctx context.Context, teamID int64, requiredSkills []db.TaskRequiredSkill, limit int, excludeBusy bool) ([]EnrichedRecommendation, error) {
+	skillIDs := make([]int64, len(requiredSkills))
+	for i, skill := range requiredSkills {
+		skillIDs[i] = skill.SkillID
+	}
+
+	matches, err := server.store.GetTeamMembersWithSkillMatch(ctx, db.GetTeamMembersWithSkillMatchParams{
+		TeamID:   pgtype.Int8{Int64: teamID, Valid: true},
+		SkillIDs: skillIDs,
+	})
 	if err != nil {
-		log.Printf("ERROR: GetProject failed: %v", err)
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return nil, err
+	}
+	// Engineers on leave are never recommended; busy ones only when the caller allows it
+	matches = slices.DeleteFunc(matches, func(match db.GetTeamMembersWithSkillMatchRow) bool {
+		return match.Availability == db.AvailabilityStatusOnLeave ||
+			(excludeBusy && match.Availability == db.AvailabilityStatusBusy)
+	})
+
+	ranked := rankSkillMatches(matches, requiredSkills)
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	userIDs := make([]int64, len(ranked))
+	for i, rec := range ranked {
+		userIDs[i] = rec.UserID
+	}
+	taskCounts, err := server.activeTaskCounts(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range ranked {
+		ranked[i].CurrentTaskCount = taskCounts[ranked[i].UserID]
+	}
+	return ranked, nil
+}
+
+// recommendedUsers loads the users behind a list of recommendations, keyed by user ID.
+// Users that no longer exist are simply absent from the map.
+func (server *Server) recommendedUsers(ctx context.Context, rsp recommenderAPIResponse) (map[int64]db.User, error) {
+	userIDs := make([]int64, len(rsp.Recommendations))
+	for i, rec := range rsp.Recommendations {
+		userIDs[i] = rec.UserID
+	}
+
+	users, err := server.store.ListUsersByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int64]db.User, len(users))
+	for _, user := range users {
+		byID[user.ID] = user
+	}
+	return byID, nil
+}
+
+func (server *Server) getRecommendations(ctx *gin.Context) {
+	var req getRecommendationsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		slog.Error("Bind error", "error", err)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Found project: %+v", project)
+	slog.Debug("Getting recommendations for task", "task_id", req.TaskID)
+	slog.Debug("Recommender API URL", "url", server.config.RecommenderAPIURL)
+	slog.Debug("Recommender API key configured", "configured", server.config.RecommenderAPIKey != "")
 
-	if project.TeamID != int64(managerTeamID) {
-		err := errors.New("forbidden: this task does not belong to your team")
-		log.Printf("ERROR: %v (project team: %d, manager team: %v)", err, project.TeamID, managerTeamID)
+	managerTeamID, err := getTeamIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	if managerTeamID == 0 {
+		err := errors.New("forbidden: manager is not assigned to a team")
+		slog.Debug("Manager is not assigned to a team for recommendations")
 		ctx.JSON(http.StatusForbidden, errorResponse(err))
 		return
 	}
 
-	requiredSkills, err := server.store.GetSkillsForTask(ctx, req.TaskID)
+	slog.Debug("Manager team ID", "manager_team_id", managerTeamID)
+
+	task, err := server.assertTaskInTeam(ctx, req.TaskID, managerTeamID)
+	if err != nil {
+		slog.Error("Task is not available to manager team", "task_id", req.TaskID, "team_id", managerTeamID, "error", err)
+		ctx.JSON(teamScopeErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	slog.Debug("Found task", "task", task)
+
+	requiredSkills, err := server.store.ListTaskRequiredSkills(ctx, req.TaskID)
 	if err != nil {
-		log.Printf("ERROR: GetSkillsForTask failed: %v", err)
+		slog.Error("ListTaskRequiredSkills failed", "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Found %d skills for task", len(requiredSkills))
+	slog.Debug("Found skills for task", "count", len(requiredSkills))
 
 	if len(requiredSkills) == 0 {
-		log.Printf("DEBUG: No skills found, returning empty recommendations")
+		slog.Debug("No skills found, returning empty recommendations")
 		ctx.JSON(http.StatusOK, gin.H{"recommendations": []EnrichedRecommendation{}})
 		return
 	}
 
 	var skillIDs []int32
 	for _, skill := range requiredSkills {
-		skillIDs = append(skillIDs, int32(skill.ID))
+		skillIDs = append(skillIDs, int32(skill.SkillID))
 	}
 
-	log.Printf("DEBUG: Skill IDs: %v", skillIDs)
+	slog.Debug("Skill IDs", "skill_ids", skillIDs)
 
 	limit := 10
 	if req.Limit > 0 && req.Limit <= 50 {
 		limit = req.Limit
 	}
 
-	recommenderReqPayload := recommenderAPIRequest{SkillIDs: skillIDs, Limit: limit}
-	recommenderBody, _ := json.Marshal(recommenderReqPayload)
+	// Reuse a recent answer for the same task and skills instead of asking the recommender again
+	cacheKey := newRecommendationCacheKey(req.TaskID, skillIDs, limit)
+	recommenderResp, cached := server.recommendations.get(cacheKey)
+	if cached {
+		slog.Debug("Using cached recommendations", "task_id", req.TaskID)
+	} else {
+		recommenderResp, err = server.fetchRecommendations(ctx, requiredSkills, limit)
+		if err != nil {
+			if abortIfCanceled(ctx) {
+				return
+			}
+			slog.Error("Failed to fetch recommendations", "error", err)
+			if isRecommenderOutage(err) {
+				// Fall back to a local ranking; it is not cached, so the recommender is retried next time
+				fallback, err := server.fallbackRecommendations(ctx, managerTeamID, requiredSkills, limit, req.ExcludeBusy)
+				if err != nil {
+					if abortIfCanceled(ctx) {
+						return
+					}
+					slog.Error("Failed to rank fallback recommendations", "error", err)
+					ctx.JSON(http.StatusServiceUnavailable, errorResponse(errRecommenderUnavailable))
+					return
+				}
+				slog.Warn("Recommender failed, returning fallback recommendations", "task_id", req.TaskID, "count", len(fallback))
+				ctx.JSON(http.StatusOK, gin.H{"recommendations": fallback})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		server.recommendations.put(cacheKey, recommenderResp)
+	}
 
-	log.Printf("DEBUG: Calling recommender API with payload: %s", string(recommenderBody))
+	slog.Debug("Parsed recommendations from API", "count", len(recommenderResp.Recommendations))
 
-	// parse the base URL from the config
-	baseURL, err := url.Parse(server.config.RecommenderAPIURL)
+	// Load every recommended user in one query, then enrich in memory
+	users, err := server.recommendedUsers(ctx, recommenderResp)
 	if err != nil {
-		log.Printf("ERROR: Failed to parse recommender base URL: %v", err)
+		if abortIfCanceled(ctx) {
+			return
+		}
+		slog.Error("Failed to load recommended users", "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	// Safely join the '/recommend' path to the base URL
-	baseURL.Path = path.Join(baseURL.Path, "/recommend")
-	endpointURL := baseURL.String()
-
-	log.Printf("DEBUG: Calling recommender API at: %s", endpointURL)
-
-	// Create the request using the newly constructed url
-	request, err := http.NewRequest("POST", endpointURL, bytes.NewBuffer(recommenderBody))
+	userIDs := make([]int64, 0, len(users))
+	for id := range users {
+		userIDs = append(userIDs, id)
+	}
+	taskCounts, err := server.activeTaskCounts(ctx, userIDs)
 	if err != nil {
-		log.Printf("ERROR: Failed to create request: %v", err)
+		if abortIfCanceled(ctx) {
+			return
+		}
+		slog.Error("Failed to count recommended users' tasks", "error", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	request.Header.Set("Content-Type", "application/json")
-	request.Header.Set("X-Internal-API-Key", server.config.RecommenderAPIKey)
+	var enrichedRecommendations []EnrichedRecommendation
+	for _, rec := range recommenderResp.Recommendations {
+		user, found := users[rec.UserID]
+		if found && user.TeamID.Int64 == managerTeamID {
+			if user.Availability == db.AvailabilityStatusOnLeave {
+				slog.Debug("Skipping recommended user on leave", "user_id", user.ID)
+				continue
+			}
+			if req.ExcludeBusy && user.Availability == db.AvailabilityStatusBusy {
+				slog.Debug("Skipping busy recommended user", "user_id", user.ID)
+				continue
+			}
+			enrichedRecommendations = append(enrichedRecommendations, EnrichedRecommendation{
+				UserID:           user.ID,
+				Name:             user.Name.String,
+				Email:            user.Email,
+				Score:            rec.Score,
+				CurrentTaskCount: taskCounts[user.ID],
+				Source:           recommendationSourceRecommender,
+			})
+			slog.Debug("Added recommendation for user", "user_id", user.ID)
+		} else if !found {
+			slog.Debug("Recommended user not found", "user_id", rec.UserID)
+		} else {
+			slog.Debug("Recommended user is not on the manager's team", "user_id", rec.UserID, "user_team_id", user.TeamID.Int64, "manager_team_id", managerTeamID)
+		}
+	}
+
+	if abortIfCanceled(ctx) {
+		return
+	}
+
+	slog.Debug("Returning enriched recommendations", "count", len(enrichedRecommendations))
+	ctx.JSON(http.StatusOK, gin.H{"recommendations": enrichedRecommendations})
+}
+
+////////////////////////////////////////////////////////////////////////
+// Skill Insight Handlers
+////////////////////////////////////////////////////////////////////////
+
+// getRelatedSkills lists skills that are often required together with the given skill,
+// so managers can spot required skills missing from a new task.
+func (server *Server) getRelatedSkills(ctx *gin.Context) {
+	slog.Debug("Starting getRelatedSkills handler")
+
+	var uriReq struct {
+		ID int64 `uri:"id" binding:"required,min=1"`
+	}
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var queryReq struct {
+		Limit int32 `form:"limit" binding:"omitempty,min=1,max=50"`
+	}
+	if err := ctx.ShouldBindQuery(&queryReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if queryReq.Limit == 0 {
+		queryReq.Limit = 10
+	}
+
+	// Verify the skill exists so an unknown ID is not mistaken for a skill without companions
+	if _, err := server.store.GetSkill(ctx, uriReq.ID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("skill not found")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	response, err := client.Do(request)
+	related, err := server.skillMatcher.RelatedSkills(ctx, uriReq.ID, queryReq.Limit)
 	if err != nil {
-		log.Printf("ERROR: HTTP request failed: %v", err)
-		ctx.JSON(http.StatusServiceUnavailable, errorResponse(errors.New("recommendation service is unavailable")))
+		slog.Error("Failed to get skills related to skill", "skill_id", uriReq.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
-	defer response.Body.Close()
 
-	bodyBytes, _ := io.ReadAll(response.Body)
-	log.Printf("DEBUG: Recommender API response status: %d", response.StatusCode)
-	log.Printf("DEBUG: Recommender API response body: %s", string(bodyBytes))
+	slog.Debug("Found skills related to skill", "count", len(related), "skill_id", uriReq.ID)
+	ctx.JSON(http.StatusOK, related)
+}
 
-	if response.StatusCode != http.StatusOK {
-		errText := fmt.Sprintf("recommendation service failed: %s", string(bodyBytes))
-		log.Printf("ERROR: %s", errText)
-		ctx.JSON(http.StatusServiceUnavailable, errorResponse(errors.New(errText)))
+////////////////////////////////////////////////////////////////////////
+// Qualified Engineers
+////////////////////////////////////////////////////////////////////////
+
+// listQualifiedEngineers ranks the manager's engineers by how many of the given skills they have
+func (server *Server) listQualifiedEngineers(ctx *gin.Context) {
+	slog.Debug("Starting listQualifiedEngineers handler")
+
+	var queryReq struct {
+		SkillIDs []int64 `form:"skill_ids" binding:"required,min=1,max=50,dive,min=1"`
+	}
+	if err := ctx.ShouldBindQuery(&queryReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	// Reset body reader for JSON decoding
-	var recommenderResp recommenderAPIResponse
-	if err := json.Unmarshal(bodyBytes, &recommenderResp); err != nil {
-		log.Printf("ERROR: Failed to parse JSON response: %v", err)
-		ctx.JSON(http.StatusInternalServerError, errorResponse(errors.New("failed to parse recommendation response")))
+	managerTeamID, err := getTeamIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	if managerTeamID == 0 {
+		err := errors.New("forbidden: manager is not assigned to a team")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	engineers, err := server.skillMatcher.EngineersForSkills(ctx, managerTeamID, queryReq.SkillIDs)
+	if err != nil {
+		slog.Error("Failed to list qualified engineers for team", "team_id", managerTeamID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	log.Printf("DEBUG: Parsed %d recommendations from API", len(recommenderResp.Recommendations))
+	slog.Debug("Found qualified engineers", "count", len(engineers), "skills", len(queryReq.SkillIDs))
+	ctx.JSON(http.StatusOK, engineers)
+}
 
-	var enrichedRecommendations []EnrichedRecommendation
-	for _, rec := range recommenderResp.Recommendations {
-		user, err := server.store.GetUser(ctx, rec.UserID)
-		if err == nil && user.TeamID.Int64 == int64(managerTeamID) {
-			enrichedRecommendations = append(enrichedRecommendations, EnrichedRecommendation{
-				UserID: user.ID,
-				Name:   user.Name.String,
-				Email:  user.Email,
-				Score:  rec.Score,
-			})
-			log.Printf("DEBUG: Added recommendation for user %d (%s)", user.ID, user.Name.String)
-		} else if err != nil {
-			log.Printf("DEBUG: Failed to get user %d: %v", rec.UserID, err)
-		} else {
-			log.Printf("DEBUG: User %d not in same team (user team: %d, manager team: %v)", rec.UserID, user.TeamID.Int64, managerTeamID)
+////////////////////////////////////////////////////////////////////////
+// Team Scope Helpers
+////////////////////////////////////////////////////////////////////////
+
+// Errors returned by the team scope guards, mapped to status codes by teamScopeErrorStatus
+var (
+	errProjectNotFound = errors.New("project not found")
+	errTaskNotFound    = errors.New("task not found")
+	errTaskNotInTeam   = errors.New("task does not belong to your team")
+	errNotInTeam       = errors.New("forbidden: user is not assigned to a team")
+)
+
+// currentTeamID reads the user's team from the database rather than from the token,
+// so a user moved to another team loses access to the old one without waiting for a new token.
+func (server *Server) currentTeamID(ctx context.Context, userID int64) (int64, error) {
+	user, err := server.store.GetUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, errNotInTeam
 		}
+		return 0, fmt.Errorf("failed to get user %d: %w", userID, err)
+	}
+	if !user.TeamID.Valid || user.TeamID.Int64 == 0 {
+		return 0, errNotInTeam
 	}
+	return user.TeamID.Int64, nil
+}
 
-	log.Printf("DEBUG: Returning %d enriched recommendations", len(enrichedRecommendations))
-	ctx.JSON(http.StatusOK, gin.H{"recommendations": enrichedRecommendations})
+// assertProjectInTeam loads a project only if it belongs to the given team.
+// Projects of other teams are reported as not found so their existence is not leaked.
+func (server *Server) assertProjectInTeam(ctx context.Context, projectID, teamID int64) (db.Project, error) {
+	project, err := server.store.GetProjectByIDAndTeam(ctx, db.GetProjectByIDAndTeamParams{
+		ID:     projectID,
+		TeamID: teamID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Project{}, errProjectNotFound
+		}
+		return db.Project{}, fmt.Errorf("failed to get project %d: %w", projectID, err)
+	}
+	return project, nil
+}
+
+// assertTaskInTeam loads a task and verifies, through its project, that it belongs to the given team.
+func (server *Server) assertTaskInTeam(ctx context.Context, taskID, teamID int64) (db.Task, error) {
+	task, err := server.store.GetTask(ctx, taskID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Task{}, errTaskNotFound
+		}
+		return db.Task{}, fmt.Errorf("failed to get task %d: %w", taskID, err)
+	}
+
+	if !task.ProjectID.Valid {
+		return db.Task{}, errTaskNotInTeam
+	}
+
+	project, err := server.store.GetProject(ctx, task.ProjectID.Int64)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Task{}, errTaskNotInTeam
+		}
+		return db.Task{}, fmt.Errorf("failed to get project of task %d: %w", taskID, err)
+	}
+	if project.TeamID != teamID {
+		return db.Task{}, errTaskNotInTeam
+	}
+
+	return task, nil
+}
+
+// teamScopeErrorStatus maps an error from the team scope guards to an HTTP status code
+func teamScopeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, errProjectNotFound), errors.Is(err, errTaskNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errTaskNotInTeam), errors.Is(err, errNotInTeam):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
 }
@@ -0,0 +1,1951 @@
+// api/manager_handler_test.go
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pranav244872/synapse/db/sqlc"
+	"github.com/pranav244872/synapse/skillz"
+	"github.com/pranav244872/synapse/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectOverviewSections(t *testing.T) {
+	sections := map[string]overviewSection{
+		"ok": func(ctx context.Context) (any, error) {
+			return "data", nil
+		},
+		"failing": func(ctx context.Context) (any, error) {
+			return nil, errors.New("boom")
+		},
+		"slow": func(ctx context.Context) (any, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+
+	// ACT
+	results, sectionErrors := collectOverviewSections(context.Background(), 50*time.Millisecond, sections)
+
+	// ASSERT
+	require.Len(t, results, 3)
+	require.Equal(t, "data", results["ok"])
+	require.Nil(t, results["failing"])
+	require.Nil(t, results["slow"])
+
+	require.Len(t, sectionErrors, 2)
+	require.Equal(t, "boom", sectionErrors["failing"])
+	require.Contains(t, sectionErrors["slow"], context.DeadlineExceeded.Error())
+}
+
+func TestGetTeamOverview(t *testing.T) {
+	t.Run("Sections are reported individually when reads fail", func(t *testing.T) {
+		// Arrange
+		server := newTestServer(t, newUnreachableStore(t))
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/api/v1/manager/overview", nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server, 1, db.UserRoleManager, 1)
+
+		// Act
+		server.router.ServeHTTP(recorder, request)
+
+		// Assert
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+		for _, section := range []string{"team", "stats", "members", "top_projects", "recent_activity"} {
+			require.Contains(t, body, section)
+		}
+
+		sectionErrors, ok := body["errors"].(map[string]any)
+		require.True(t, ok)
+		require.Len(t, sectionErrors, 5)
+	})
+
+	t.Run("One request covers the team, its counts and its newest tasks", func(t *testing.T) {
+		// Arrange
+		store := newTestStore(t)
+		ctx := context.Background()
+		server := newTestServer(t, store)
+
+		team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+		require.NoError(t, err)
+		manager := createTestUser(t, store, db.UserRoleManager, team.ID)
+		_, err = store.SetTeamManager(ctx, db.SetTeamManagerParams{ID: team.ID, ManagerID: pgtype.Int8{Int64: manager.ID, Valid: true}})
+		require.NoError(t, err)
+		createTestUser(t, store, db.UserRoleEngineer, team.ID)
+
+		project, err := store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: team.ID})
+		require.NoError(t, err)
+		task, err := store.CreateTask(ctx, db.CreateTaskParams{
+			ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+			Title:     util.RandomName(),
+			Status:    db.TaskStatusOpen,
+			Priority:  db.TaskPriorityMedium,
+			DueDate:   pgtype.Timestamp{Time: time.Now().UTC().Add(-48 * time.Hour), Valid: true},
+		})
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/api/v1/manager/overview", nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server, manager.ID, db.UserRoleManager, team.ID)
+
+		// Act
+		server.router.ServeHTTP(recorder, request)
+
+		// Assert
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var body struct {
+			Team    db.GetTeamManagerContactRow `json:"team"`
+			Stats   db.GetTeamOverviewStatsRow  `json:"stats"`
+			Members struct {
+				TotalEngineers int            `json:"total_engineers"`
+				ByAvailability map[string]int `json:"by_availability"`
+			} `json:"members"`
+			RecentActivity []db.ListRecentTasksByTeamRow `json:"recent_activity"`
+			Errors         map[string]string             `json:"errors"`
+		}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+		require.Empty(t, body.Errors)
+
+		require.Equal(t, team.TeamName, body.Team.TeamName)
+		require.Equal(t, manager.ID, body.Team.ManagerID.Int64)
+		require.Equal(t, db.GetTeamOverviewStatsRow{ActiveProjects: 1, OpenTasks: 1, OverdueTasks: 1}, body.Stats)
+		require.Equal(t, 1, body.Members.TotalEngineers)
+		require.Equal(t, 1, body.Members.ByAvailability[string(db.AvailabilityStatusAvailable)])
+		require.Len(t, body.RecentActivity, 1)
+		require.Equal(t, task.ID, body.RecentActivity[0].ID)
+	})
+
+	t.Run("Manager without team is forbidden", func(t *testing.T) {
+		// Arrange
+		server := newTestServer(t, nil)
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/api/v1/manager/overview", nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server, 1, db.UserRoleManager, 0)
+
+		// Act
+		server.router.ServeHTTP(recorder, request)
+
+		// Assert
+		require.Equal(t, http.StatusForbidden, recorder.Code)
+	})
+}
+
+func TestPolledReadsHonorETags(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	server := newTestServer(t, store)
+
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	manager := createTestUser(t, store, db.UserRoleManager, team.ID)
+
+	get := func(path, ifNoneMatch string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(t, err)
+		if ifNoneMatch != "" {
+			request.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		addAuthorization(t, request, server, manager.ID, db.UserRoleManager, team.ID)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	paths := []string{"/api/v1/manager/dashboard/stats", "/api/v1/manager/projects?page_id=1&page_size=10"}
+	etags := make(map[string]string, len(paths))
+	for _, path := range paths {
+		first := get(path, "")
+		require.Equal(t, http.StatusOK, first.Code, path)
+		etags[path] = first.Header().Get("ETag")
+		require.NotEmpty(t, etags[path], path)
+
+		// Polling again with the tag costs no body
+		second := get(path, etags[path])
+		require.Equal(t, http.StatusNotModified, second.Code, path)
+		require.Empty(t, second.Body.String(), path)
+	}
+
+	// A new project changes both responses
+	_, err = store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: team.ID})
+	require.NoError(t, err)
+	for _, path := range paths {
+		recorder := get(path, etags[path])
+		require.Equal(t, http.StatusOK, recorder.Code, path)
+		require.NotEqual(t, etags[path], recorder.Header().Get("ETag"), path)
+	}
+}
+
+func TestCreateTaskAutoAssign(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	// Arrange: a team with one manager, one available engineer and an active project
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+
+	manager := createTestUser(t, store, db.UserRoleManager, team.ID)
+
+	project, err := store.CreateProject(ctx, db.CreateProjectParams{
+		ProjectName: util.RandomName(),
+		TeamID:      team.ID,
+	})
+	require.NoError(t, err)
+
+	// The mock recommender always ranks the engineer created by each sub-test first
+	var recommendedUserID int64
+	recommender := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/recommend", r.URL.Path)
+		resp := gin.H{"recommendations": []gin.H{{"user_id": recommendedUserID, "score": 0.9}}}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	t.Cleanup(recommender.Close)
+
+	// createTask creates a task needing skillName through the recommender at recommenderURL
+	createTask := func(t *testing.T, recommenderURL, skillName string) createTaskResponse {
+		server := newTestServer(t, store)
+		server.config.RecommenderAPIURL = recommenderURL
+		server.skillzProcessor = &mockSkillzProcessor{skills: []string{skillName}}
+
+		body, err := json.Marshal(gin.H{
+			"project_id":  project.ID,
+			"title":       util.RandomName(),
+			"description": "needs some skill to get it done",
+			"priority":    "high",
+		})
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/api/v1/manager/tasks", bytes.NewReader(body))
+		require.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		addAuthorization(t, request, server, manager.ID, db.UserRoleManager, team.ID)
+
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusCreated, recorder.Code, recorder.Body.String())
+
+		var rsp createTaskResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+		return rsp
+	}
+
+	t.Run("Enabled assigns the top recommendation", func(t *testing.T) {
+		// Arrange
+		engineer := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+		recommendedUserID = engineer.ID
+		_, err := store.SetTeamAutoAssign(ctx, db.SetTeamAutoAssignParams{ID: team.ID, AutoAssign: true})
+		require.NoError(t, err)
+
+		// Act
+		rsp := createTask(t, recommender.URL, "Skill "+util.RandomString(8))
+
+		// Assert
+		require.NotNil(t, rsp.AutoAssignedTo)
+		require.Equal(t, engineer.ID, rsp.AutoAssignedTo.UserID)
+		require.Equal(t, int64(1), rsp.AutoAssignedTo.CurrentTaskCount)
+
+		task, err := store.GetTask(ctx, rsp.Task.ID)
+		require.NoError(t, err)
+		require.Equal(t, engineer.ID, task.AssigneeID.Int64)
+		require.Equal(t, db.TaskStatusInProgress, task.Status)
+
+		assigned, err := store.GetUser(ctx, engineer.ID)
+		require.NoError(t, err)
+		require.Equal(t, db.AvailabilityStatusBusy, assigned.Availability)
+	})
+
+	t.Run("Disabled leaves the task open", func(t *testing.T) {
+		// Arrange
+		engineer := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+		recommendedUserID = engineer.ID
+		_, err := store.SetTeamAutoAssign(ctx, db.SetTeamAutoAssignParams{ID: team.ID, AutoAssign: false})
+		require.NoError(t, err)
+
+		// Act
+		rsp := createTask(t, recommender.URL, "Skill "+util.RandomString(8))
+
+		// Assert
+		require.Nil(t, rsp.AutoAssignedTo)
+
+		task, err := store.GetTask(ctx, rsp.Task.ID)
+		require.NoError(t, err)
+		require.False(t, task.AssigneeID.Valid)
+		require.Equal(t, db.TaskStatusOpen, task.Status)
+	})
+
+	t.Run("Recommender outage assigns from the local ranking", func(t *testing.T) {
+		// Arrange: only this engineer has the task's skill, and the recommender cannot be reached
+		engineer := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+		skill, err := store.CreateSkill(ctx, db.CreateSkillParams{SkillName: "Skill " + util.RandomString(8)})
+		require.NoError(t, err)
+		_, err = store.AddSkillToUser(ctx, db.AddSkillToUserParams{UserID: engineer.ID, SkillID: skill.ID, Proficiency: db.ProficiencyLevelExpert})
+		require.NoError(t, err)
+		_, err = store.SetTeamAutoAssign(ctx, db.SetTeamAutoAssignParams{ID: team.ID, AutoAssign: true})
+		require.NoError(t, err)
+
+		unreachable := httptest.NewServer(http.NotFoundHandler())
+		unreachable.Close()
+
+		// Act
+		rsp := createTask(t, unreachable.URL, skill.SkillName)
+
+		// Assert
+		require.NotNil(t, rsp.AutoAssignedTo)
+		require.Equal(t, engineer.ID, rsp.AutoAssignedTo.UserID)
+
+		task, err := store.GetTask(ctx, rsp.Task.ID)
+		require.NoError(t, err)
+		require.Equal(t, engineer.ID, task.AssigneeID.Int64)
+		require.Equal(t, db.TaskStatusInProgress, task.Status)
+	})
+}
+
+func TestExtractTaskSkills(t *testing.T) {
+	longDescription := "Build the billing API in Go with PostgreSQL"
+
+	testCases := []struct {
+		name        string
+		processor   func(t *testing.T) skillz.Processor
+		description string
+		wantSkills  int
+		wantOutcome string
+	}{
+		{
+			name: "Long descriptions are extracted",
+			processor: func(t *testing.T) skillz.Processor {
+				return &mockSkillzProcessor{skills: []string{"Go", "PostgreSQL"}}
+			},
+			description: longDescription,
+			wantSkills:  2,
+			wantOutcome: skillExtractionDone,
+		},
+		{
+			name: "Short descriptions skip the LLM",
+			processor: func(t *testing.T) skillz.Processor {
+				return &mockSkillzProcessor{unusedBy: t}
+			},
+			description: "  fix bug  ",
+			wantOutcome: skillExtractionSkipped,
+		},
+		{
+			name: "Failures are tolerated",
+			processor: func(t *testing.T) skillz.Processor {
+				return &mockSkillzProcessor{weightErr: errors.New("llm unavailable")}
+			},
+			description: longDescription,
+			wantOutcome: skillExtractionFailed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(t, nil)
+			server.skillzProcessor = tc.processor(t)
+
+			skills, outcome := server.extractTaskSkills(context.Background(), tc.description)
+			require.Equal(t, tc.wantOutcome, outcome)
+			require.Len(t, skills, tc.wantSkills)
+		})
+	}
+
+	t.Run("A configured minimum applies", func(t *testing.T) {
+		server := newTestServer(t, nil)
+		server.skillzProcessor = &mockSkillzProcessor{skills: []string{"Go"}}
+
+		server.config.MinTaskDescriptionLength = len(longDescription) + 1
+		_, outcome := server.extractTaskSkills(context.Background(), longDescription)
+		require.Equal(t, skillExtractionSkipped, outcome)
+
+		server.config.MinTaskDescriptionLength = -1
+		_, outcome = server.extractTaskSkills(context.Background(), "fix bug")
+		require.Equal(t, skillExtractionDone, outcome)
+	})
+}
+
+func TestCreateTaskSkillExtraction(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	manager := createTestUser(t, store, db.UserRoleManager, team.ID)
+	project, err := store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: team.ID})
+	require.NoError(t, err)
+
+	createTask := func(t *testing.T, server *Server, description string) createTaskResponse {
+		body, err := json.Marshal(gin.H{"project_id": project.ID, "title": util.RandomName(), "description": description})
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/api/v1/manager/tasks", bytes.NewReader(body))
+		require.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		addAuthorization(t, request, server, manager.ID, db.UserRoleManager, team.ID)
+
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusCreated, recorder.Code, recorder.Body.String())
+
+		var rsp createTaskResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+		return rsp
+	}
+
+	t.Run("A short description is created without calling the LLM", func(t *testing.T) {
+		server := newTestServer(t, store)
+		server.skillzProcessor = &mockSkillzProcessor{unusedBy: t}
+
+		rsp := createTask(t, server, "fix bug")
+		require.Equal(t, skillExtractionSkipped, rsp.SkillExtraction)
+		require.Empty(t, rsp.TaskRequiredSkills)
+		require.Equal(t, "fix bug", rsp.Task.Description.String)
+	})
+
+	t.Run("A failed extraction still creates the task", func(t *testing.T) {
+		server := newTestServer(t, store)
+		server.skillzProcessor = &mockSkillzProcessor{weightErr: errors.New("llm unavailable")}
+
+		rsp := createTask(t, server, "Build the billing API in Go with PostgreSQL")
+		require.Equal(t, skillExtractionFailed, rsp.SkillExtraction)
+		require.Empty(t, rsp.TaskRequiredSkills)
+
+		task, err := store.GetTask(ctx, rsp.Task.ID)
+		require.NoError(t, err)
+		require.Equal(t, db.TaskStatusOpen, task.Status)
+	})
+}
+
+func TestCreateTaskDefaults(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	// Arrange: a team with a project and no task defaults of its own
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	manager := createTestUser(t, store, db.UserRoleManager, team.ID)
+	project, err := store.CreateProject(ctx, db.CreateProjectParams{
+		ProjectName: util.RandomName(),
+		TeamID:      team.ID,
+	})
+	require.NoError(t, err)
+
+	server := newTestServer(t, store)
+	server.config.DefaultTaskPriority = string(db.TaskPriorityLow)
+	server.skillzProcessor = &mockSkillzProcessor{}
+
+	send := func(t *testing.T, method, url string, body gin.H) *httptest.ResponseRecorder {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(method, url, bytes.NewReader(data))
+		require.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		addAuthorization(t, request, server, manager.ID, db.UserRoleManager, team.ID)
+
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+	createTask := func(t *testing.T, body gin.H) db.Task {
+		body["project_id"] = project.ID
+		body["title"] = util.RandomName()
+		body["description"] = "a task"
+
+		recorder := send(t, http.MethodPost, "/api/v1/manager/tasks", body)
+		require.Equal(t, http.StatusCreated, recorder.Code, recorder.Body.String())
+
+		var rsp createTaskResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+		return rsp.Task
+	}
+
+	t.Run("Omitted priority uses the server default", func(t *testing.T) {
+		task := createTask(t, gin.H{})
+
+		require.Equal(t, db.TaskPriorityLow, task.Priority)
+		require.Equal(t, db.TaskStatusOpen, task.Status)
+	})
+
+	t.Run("Omitted priority uses the team default", func(t *testing.T) {
+		recorder := send(t, http.MethodPut, "/api/v1/manager/team/settings", gin.H{"default_task_priority": "critical"})
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+		task := createTask(t, gin.H{})
+
+		require.Equal(t, db.TaskPriorityCritical, task.Priority)
+	})
+
+	t.Run("Explicit priority wins over the defaults", func(t *testing.T) {
+		task := createTask(t, gin.H{"priority": "medium"})
+
+		require.Equal(t, db.TaskPriorityMedium, task.Priority)
+	})
+
+	t.Run("Cleared team priority falls back to the server default", func(t *testing.T) {
+		recorder := send(t, http.MethodPut, "/api/v1/manager/team/settings", gin.H{"clear_default_task_priority": true})
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+		task := createTask(t, gin.H{})
+
+		require.Equal(t, db.TaskPriorityLow, task.Priority)
+	})
+
+	t.Run("A default cannot be both set and cleared", func(t *testing.T) {
+		body := gin.H{"default_task_priority": "high", "clear_default_task_priority": true}
+		recorder := send(t, http.MethodPut, "/api/v1/manager/team/settings", body)
+
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("Team initial status is applied", func(t *testing.T) {
+		recorder := send(t, http.MethodPut, "/api/v1/manager/team/settings", gin.H{"default_task_status": "open"})
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+		task := createTask(t, gin.H{})
+
+		require.Equal(t, db.TaskStatusOpen, task.Status)
+	})
+
+	t.Run("Only open is an allowed initial status", func(t *testing.T) {
+		for _, status := range []string{"in_progress", "done"} {
+			recorder := send(t, http.MethodPut, "/api/v1/manager/team/settings", gin.H{"default_task_status": status})
+
+			require.Equal(t, http.StatusBadRequest, recorder.Code, status)
+		}
+	})
+}
+
+func TestTeamScopeErrorStatus(t *testing.T) {
+	testCases := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"Project not found", errProjectNotFound, http.StatusNotFound},
+		{"Task not found", errTaskNotFound, http.StatusNotFound},
+		{"Task in another team", errTaskNotInTeam, http.StatusForbidden},
+		{"Wrapped sentinel", fmt.Errorf("checking ownership: %w", errTaskNotInTeam), http.StatusForbidden},
+		{"Database error", errors.New("connection refused"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.wantStatus, teamScopeErrorStatus(tc.err))
+		})
+	}
+}
+
+func TestTeamScopeGuardsDatabaseError(t *testing.T) {
+	// Arrange: a store whose queries always fail must not be reported as 403 or 404
+	server := newTestServer(t, newUnreachableStore(t))
+
+	// Act
+	_, projectErr := server.assertProjectInTeam(context.Background(), 1, 1)
+	_, taskErr := server.assertTaskInTeam(context.Background(), 1, 1)
+
+	// Assert
+	require.Error(t, projectErr)
+	require.Equal(t, http.StatusInternalServerError, teamScopeErrorStatus(projectErr))
+	require.Error(t, taskErr)
+	require.Equal(t, http.StatusInternalServerError, teamScopeErrorStatus(taskErr))
+}
+
+func TestValidateStatusTransition(t *testing.T) {
+	testCases := []struct {
+		from, to db.TaskStatus
+		valid    bool
+	}{
+		{db.TaskStatusOpen, db.TaskStatusInProgress, true},
+		{db.TaskStatusInProgress, db.TaskStatusDone, true},
+		{db.TaskStatusDone, db.TaskStatusOpen, true},
+		{db.TaskStatusOpen, db.TaskStatusOpen, true},
+		{db.TaskStatusOpen, db.TaskStatusDone, false},
+		{db.TaskStatusInProgress, db.TaskStatusOpen, false},
+		{db.TaskStatusDone, db.TaskStatusInProgress, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s to %s", tc.from, tc.to), func(t *testing.T) {
+			err := validateStatusTransition(tc.from, tc.to)
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestGetTeamMemberHistory(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	// Arrange: an engineer with one completed task, plus an engineer on another team
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	otherTeam, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+
+	manager := createTestUser(t, store, db.UserRoleManager, team.ID)
+	engineer := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+	outsider := createTestUser(t, store, db.UserRoleEngineer, otherTeam.ID)
+
+	project, err := store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: team.ID})
+	require.NoError(t, err)
+	task, err := store.CreateTask(ctx, db.CreateTaskParams{
+		ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+		Title:     util.RandomName(),
+		Status:    db.TaskStatusOpen,
+		Priority:  db.TaskPriorityMedium,
+	})
+	require.NoError(t, err)
+	_, err = store.AssignTaskToUser(ctx, db.AssignTaskToUserTxParams{TaskID: task.ID, UserID: engineer.ID})
+	require.NoError(t, err)
+	_, err = store.CompleteTaskTx(ctx, db.CompleteTaskTxParams{TaskID: task.ID})
+	require.NoError(t, err)
+
+	server := newTestServer(t, store)
+	getHistory := func(t *testing.T, userID int64) *httptest.ResponseRecorder {
+		url := fmt.Sprintf("/api/v1/manager/team/members/%d/history?page_id=1&page_size=10", userID)
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server, manager.ID, db.UserRoleManager, team.ID)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	t.Run("Engineer on the team", func(t *testing.T) {
+		// Act
+		recorder := getHistory(t, engineer.ID)
+
+		// Assert
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var rsp paginatedResponse[db.GetEngineerTaskHistoryRow]
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+		require.Equal(t, int64(1), rsp.TotalCount)
+		require.Len(t, rsp.Data, 1)
+		require.Equal(t, task.ID, rsp.Data[0].ID)
+	})
+
+	t.Run("Engineer on another team is forbidden", func(t *testing.T) {
+		// Act
+		recorder := getHistory(t, outsider.ID)
+
+		// Assert
+		require.Equal(t, http.StatusForbidden, recorder.Code)
+	})
+}
+
+func TestUpdateTaskStatus(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	// Arrange: an open, unassigned task and an engineer to give it to
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	manager := createTestUser(t, store, db.UserRoleManager, team.ID)
+	engineer := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+	project, err := store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: team.ID})
+	require.NoError(t, err)
+	task, err := store.CreateTask(ctx, db.CreateTaskParams{
+		ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+		Title:     util.RandomName(),
+		Status:    db.TaskStatusOpen,
+		Priority:  db.TaskPriorityMedium,
+	})
+	require.NoError(t, err)
+
+	server := newTestServer(t, store)
+	patch := func(t *testing.T, status string) *httptest.ResponseRecorder {
+		current, err := store.GetTask(ctx, task.ID)
+		require.NoError(t, err)
+		data, err := json.Marshal(gin.H{"status": status, "version": current.Version})
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		url := fmt.Sprintf("/api/v1/manager/tasks/%d", task.ID)
+		request, err := http.NewRequest(http.MethodPatch, url, bytes.NewReader(data))
+		require.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		addAuthorization(t, request, server, manager.ID, db.UserRoleManager, team.ID)
+
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+	decode := func(t *testing.T, recorder *httptest.ResponseRecorder) db.Task {
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		var got db.Task
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+		return got
+	}
+
+	t.Run("Open cannot jump to done", func(t *testing.T) {
+		require.Equal(t, http.StatusBadRequest, patch(t, "done").Code)
+	})
+
+	t.Run("Starting requires an assignee", func(t *testing.T) {
+		require.Equal(t, http.StatusBadRequest, patch(t, "in_progress").Code)
+	})
+
+	_, err = store.UpdateTask(ctx, db.UpdateTaskParams{
+		ID:         task.ID,
+		AssigneeID: pgtype.Int8{Int64: engineer.ID, Valid: true},
+	})
+	require.NoError(t, err)
+
+	t.Run("Starting an assigned task marks the engineer busy", func(t *testing.T) {
+		got := decode(t, patch(t, "in_progress"))
+		require.Equal(t, db.TaskStatusInProgress, got.Status)
+
+		user, err := store.GetUser(ctx, engineer.ID)
+		require.NoError(t, err)
+		require.Equal(t, db.AvailabilityStatusBusy, user.Availability)
+	})
+
+	t.Run("In progress cannot go back to open", func(t *testing.T) {
+		require.Equal(t, http.StatusBadRequest, patch(t, "open").Code)
+	})
+
+	t.Run("Finishing stamps completed_at and frees the engineer", func(t *testing.T) {
+		got := decode(t, patch(t, "done"))
+		require.Equal(t, db.TaskStatusDone, got.Status)
+		require.True(t, got.CompletedAt.Valid)
+
+		user, err := store.GetUser(ctx, engineer.ID)
+		require.NoError(t, err)
+		require.Equal(t, db.AvailabilityStatusAvailable, user.Availability)
+	})
+
+	t.Run("Reopening clears completed_at and the assignee", func(t *testing.T) {
+		got := decode(t, patch(t, "open"))
+		require.Equal(t, db.TaskStatusOpen, got.Status)
+		require.False(t, got.CompletedAt.Valid)
+		require.False(t, got.AssigneeID.Valid)
+	})
+}
+
+func TestBulkCreateTasksValidation(t *testing.T) {
+	server := newTestServer(t, newUnreachableStore(t))
+
+	tooMany := make([]gin.H, maxBulkTasks+1)
+	for i := range tooMany {
+		tooMany[i] = gin.H{"title": "task", "description": "a task"}
+	}
+
+	testCases := []struct {
+		name string
+		body any
+	}{
+		{"Empty batch", []gin.H{}},
+		{"Batch over the cap", tooMany},
+		{"Missing title", []gin.H{{"description": "a task"}}},
+		{"Invalid priority", []gin.H{{"title": "task", "description": "a task", "priority": "urgent"}}},
+		{"Not an array", gin.H{"title": "task", "description": "a task"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := newAuthorizedRequest(t, server, http.MethodPost, fmt.Sprintf("/api/v1/manager/projects/%d/tasks/bulk", 1), tc.body, 1, db.UserRoleManager, 1)
+			require.Equal(t, http.StatusBadRequest, recorder.Code)
+		})
+	}
+}
+
+func TestBulkCreateTasks(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	// Arrange: a team with an active and an archived project
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	manager := createTestUser(t, store, db.UserRoleManager, team.ID)
+	project, err := store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: team.ID})
+	require.NoError(t, err)
+	archived, err := store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: team.ID})
+	require.NoError(t, err)
+	_, err = store.ArchiveProjectTx(ctx, db.ArchiveProjectTxParams{ProjectID: archived.ID, TeamID: team.ID})
+	require.NoError(t, err)
+
+	server := newTestServer(t, store)
+	server.skillzProcessor = &mockSkillzProcessor{skills: []string{util.RandomName()}}
+
+	batch := []gin.H{
+		{"title": util.RandomName(), "description": "build the REST API for the projects", "priority": "high"},
+		{"title": util.RandomName(), "description": "write the migrations for the new tables"},
+	}
+
+	t.Run("Creates every task with its skills", func(t *testing.T) {
+		recorder := newAuthorizedRequest(t, server, http.MethodPost, fmt.Sprintf("/api/v1/manager/projects/%d/tasks/bulk", project.ID), batch, manager.ID, db.UserRoleManager, team.ID)
+		require.Equal(t, http.StatusCreated, recorder.Code, recorder.Body.String())
+
+		var rsp struct {
+			Tasks []bulkCreatedTask `json:"tasks"`
+		}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+		require.Len(t, rsp.Tasks, 2)
+
+		require.Equal(t, db.TaskPriorityHigh, rsp.Tasks[0].Task.Priority)
+		for _, created := range rsp.Tasks {
+			require.Equal(t, project.ID, created.Task.ProjectID.Int64)
+			require.Len(t, created.TaskRequiredSkills, 1)
+			require.Len(t, created.ExtractedSkills, 1)
+		}
+	})
+
+	t.Run("Extraction is skipped or may fail per task without failing the batch", func(t *testing.T) {
+		defer func() { server.skillzProcessor = &mockSkillzProcessor{skills: []string{util.RandomName()}} }()
+
+		// A short description is not sent to the LLM
+		short := []gin.H{{"title": util.RandomName(), "description": "fix bug"}}
+		recorder := newAuthorizedRequest(t, server, http.MethodPost, fmt.Sprintf("/api/v1/manager/projects/%d/tasks/bulk", project.ID), short, manager.ID, db.UserRoleManager, team.ID)
+		require.Equal(t, http.StatusCreated, recorder.Code, recorder.Body.String())
+		var rsp struct {
+			Tasks []bulkCreatedTask `json:"tasks"`
+		}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+		require.Equal(t, skillExtractionSkipped, rsp.Tasks[0].SkillExtraction)
+		require.Empty(t, rsp.Tasks[0].TaskRequiredSkills)
+
+		// A failed extraction still creates the tasks, without skills
+		server.skillzProcessor = &mockSkillzProcessor{weightErr: errors.New("llm unavailable")}
+		recorder = newAuthorizedRequest(t, server, http.MethodPost, fmt.Sprintf("/api/v1/manager/projects/%d/tasks/bulk", project.ID), batch, manager.ID, db.UserRoleManager, team.ID)
+		require.Equal(t, http.StatusCreated, recorder.Code, recorder.Body.String())
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+		require.Len(t, rsp.Tasks, 2)
+		for _, created := range rsp.Tasks {
+			require.Equal(t, skillExtractionFailed, created.SkillExtraction)
+			require.Empty(t, created.TaskRequiredSkills)
+		}
+	})
+
+	t.Run("Archived project is rejected", func(t *testing.T) {
+		recorder := newAuthorizedRequest(t, server, http.MethodPost, fmt.Sprintf("/api/v1/manager/projects/%d/tasks/bulk", archived.ID), batch, manager.ID, db.UserRoleManager, team.ID)
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("Another team's project is not found", func(t *testing.T) {
+		otherTeam, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+		require.NoError(t, err)
+		otherManager := createTestUser(t, store, db.UserRoleManager, otherTeam.ID)
+
+		recorder := newAuthorizedRequest(t, server, http.MethodPost, fmt.Sprintf("/api/v1/manager/projects/%d/tasks/bulk", project.ID), batch, otherManager.ID, db.UserRoleManager, otherTeam.ID)
+		require.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
+func TestResendInvitation(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	server := newTestServer(t, store)
+	emailer := &recordingEmailer{sent: make(chan sentEmail, 2)}
+	server.emailer = emailer
+
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	otherTeam, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	manager := createTestUser(t, store, db.UserRoleManager, team.ID)
+	otherManager := createTestUser(t, store, db.UserRoleManager, otherTeam.ID)
+
+	created, err := store.CreateInvitationTx(ctx, db.CreateInvitationTxParams{
+		InviterID:     manager.ID,
+		EmailToInvite: util.RandomEmail(),
+		RoleToInvite:  db.UserRoleEngineer,
+	})
+	require.NoError(t, err)
+
+	resend := func(userID, teamID int64) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		url := fmt.Sprintf("/api/v1/manager/invitations/%d/resend", created.Invitation.ID)
+		request, err := http.NewRequest(http.MethodPost, url, nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server, userID, db.UserRoleManager, teamID)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// Another manager cannot resend it
+	require.Equal(t, http.StatusForbidden, resend(otherManager.ID, otherTeam.ID).Code)
+
+	// The sender gets a new token, and the invitee gets a new email
+	recorder := resend(manager.ID, team.ID)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var invitation db.RefreshInvitationTokenRow
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &invitation))
+	require.NotEqual(t, created.Invitation.InvitationToken, invitation.InvitationToken)
+
+	select {
+	case email := <-emailer.sent:
+		require.Equal(t, created.Invitation.Email, email.to)
+		require.Contains(t, email.body, invitation.InvitationToken)
+	case <-time.After(time.Second):
+		t.Fatal("invitation email was not resent")
+	}
+
+	// Once revoked it can no longer be resent
+	_, err = store.RevokeInvitation(ctx, created.Invitation.ID)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusConflict, resend(manager.ID, team.ID).Code)
+}
+
+func TestNewInvitationStatusResponse(t *testing.T) {
+	now := time.Date(2025, time.March, 4, 10, 0, 0, 0, time.UTC)
+	invitation := func(status string, expiresAt time.Time) db.GetInvitationByIDRow {
+		return db.GetInvitationByIDRow{ID: 1, Status: status, ExpiresAt: pgtype.Timestamp{Time: expiresAt, Valid: true}}
+	}
+
+	testCases := []struct {
+		name             string
+		invitation       db.GetInvitationByIDRow
+		isExpired        bool
+		expiresInSeconds int64
+	}{
+		{"Pending", invitation("pending", now.Add(90*time.Minute)), false, 5400},
+		{"Pending past expiry", invitation("pending", now.Add(-time.Minute)), true, 0},
+		{"Expired", invitation("expired", now.Add(-time.Hour)), true, 0},
+		{"Accepted", invitation("accepted", now.Add(time.Hour)), false, 0},
+		{"Revoked", invitation("revoked", now.Add(time.Hour)), false, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rsp := newInvitationStatusResponse(tc.invitation, now)
+			require.Equal(t, tc.isExpired, rsp.IsExpired)
+			require.Equal(t, tc.expiresInSeconds, rsp.ExpiresInSeconds)
+		})
+	}
+}
+
+func TestGetInvitation(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	server := newTestServer(t, store)
+
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	otherTeam, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	manager := createTestUser(t, store, db.UserRoleManager, team.ID)
+	otherManager := createTestUser(t, store, db.UserRoleManager, otherTeam.ID)
+	admin := createTestUser(t, store, db.UserRoleAdmin, 0)
+
+	created, err := store.CreateInvitationTx(ctx, db.CreateInvitationTxParams{
+		InviterID:     manager.ID,
+		EmailToInvite: util.RandomEmail(),
+		RoleToInvite:  db.UserRoleEngineer,
+	})
+	require.NoError(t, err)
+
+	get := func(path string, userID int64, role db.UserRole, teamID int64) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/%d", path, created.Invitation.ID), nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server, userID, role, teamID)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// Another manager cannot view it
+	require.Equal(t, http.StatusForbidden, get("/api/v1/manager/invitations", otherManager.ID, db.UserRoleManager, otherTeam.ID).Code)
+
+	// The sender and admins can, without the token
+	for _, recorder := range []*httptest.ResponseRecorder{
+		get("/api/v1/manager/invitations", manager.ID, db.UserRoleManager, team.ID),
+		get("/api/v1/admin/invitations", admin.ID, db.UserRoleAdmin, 0),
+	} {
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.NotContains(t, recorder.Body.String(), created.Invitation.InvitationToken)
+
+		var rsp invitationStatusResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+		require.Equal(t, created.Invitation.ID, rsp.ID)
+		require.Equal(t, "pending", rsp.Status)
+		require.False(t, rsp.IsExpired)
+		require.Positive(t, rsp.ExpiresInSeconds)
+	}
+}
+
+func TestGetRecommendationsWorkload(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	// Arrange: two engineers with equal scores, one of them already busy with two tasks
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	manager := createTestUser(t, store, db.UserRoleManager, team.ID)
+	idle := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+	busy := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+
+	project, err := store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: team.ID})
+	require.NoError(t, err)
+	for _, status := range []db.TaskStatus{db.TaskStatusInProgress, db.TaskStatusOpen, db.TaskStatusDone} {
+		_, err := store.CreateTask(ctx, db.CreateTaskParams{
+			ProjectID:  pgtype.Int8{Int64: project.ID, Valid: true},
+			Title:      util.RandomName(),
+			Status:     status,
+			Priority:   db.TaskPriorityMedium,
+			AssigneeID: pgtype.Int8{Int64: busy.ID, Valid: true},
+		})
+		require.NoError(t, err)
+	}
+	_, err = store.MarkUserBusyIfAvailable(ctx, busy.ID)
+	require.NoError(t, err)
+
+	task, err := store.CreateTask(ctx, db.CreateTaskParams{
+		ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+		Title:     util.RandomName(),
+		Status:    db.TaskStatusOpen,
+		Priority:  db.TaskPriorityMedium,
+	})
+	require.NoError(t, err)
+	skill, err := store.CreateSkill(ctx, db.CreateSkillParams{SkillName: "Skill " + util.RandomString(8)})
+	require.NoError(t, err)
+	_, err = store.AddSkillToTask(ctx, db.AddSkillToTaskParams{TaskID: task.ID, SkillID: skill.ID})
+	require.NoError(t, err)
+
+	recommender := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := gin.H{"recommendations": []gin.H{{"user_id": busy.ID, "score": 0.8}, {"user_id": idle.ID, "score": 0.8}}}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	t.Cleanup(recommender.Close)
+
+	server := newTestServer(t, store)
+	server.config.RecommenderAPIURL = recommender.URL
+
+	recommend := func(t *testing.T, excludeBusy bool) []EnrichedRecommendation {
+		body, err := json.Marshal(gin.H{"task_id": task.ID, "exclude_busy": excludeBusy})
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/api/v1/manager/recommendations", bytes.NewReader(body))
+		require.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		addAuthorization(t, request, server, manager.ID, db.UserRoleManager, team.ID)
+
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+		var rsp struct {
+			Recommendations []EnrichedRecommendation `json:"recommendations"`
+		}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+		return rsp.Recommendations
+	}
+
+	// Act & Assert: the busy engineer's unfinished tasks are counted; done ones are not
+	recommendations := recommend(t, false)
+	require.Len(t, recommendations, 2)
+	require.Equal(t, busy.ID, recommendations[0].UserID)
+	require.Equal(t, int64(2), recommendations[0].CurrentTaskCount)
+	require.Equal(t, idle.ID, recommendations[1].UserID)
+	require.Zero(t, recommendations[1].CurrentTaskCount)
+
+	// Busy engineers can be left out entirely
+	recommendations = recommend(t, true)
+	require.Len(t, recommendations, 1)
+	require.Equal(t, idle.ID, recommendations[0].UserID)
+}
+
+func TestRankSkillMatches(t *testing.T) {
+	requiredSkills := []db.TaskRequiredSkill{
+		{SkillID: 1, Weight: 1},
+		{SkillID: 2, Weight: 0.5},
+	}
+	match := func(userID, skillID int64, proficiency db.ProficiencyLevel) db.GetTeamMembersWithSkillMatchRow {
+		return db.GetTeamMembersWithSkillMatchRow{UserID: userID, SkillID: skillID, Proficiency: proficiency}
+	}
+
+	ranked := rankSkillMatches([]db.GetTeamMembersWithSkillMatchRow{
+		match(10, 1, db.ProficiencyLevelBeginner),
+		match(10, 2, db.ProficiencyLevelBeginner),
+		match(20, 1, db.ProficiencyLevelExpert),
+		match(20, 2, db.ProficiencyLevelExpert),
+		match(30, 2, db.ProficiencyLevelExpert),
+		match(40, 1, db.ProficiencyLevelBeginner),
+		match(40, 2, db.ProficiencyLevelBeginner),
+	}, requiredSkills)
+
+	// Expert in everything scores 1; ties keep the lower user ID first
+	require.Len(t, ranked, 4)
+	require.Equal(t, []int64{20, 10, 30, 40}, []int64{ranked[0].UserID, ranked[1].UserID, ranked[2].UserID, ranked[3].UserID})
+	require.InDelta(t, 1.0, ranked[0].Score, 1e-9)
+	require.InDelta(t, 1.5/4.5, ranked[1].Score, 1e-9)
+	require.InDelta(t, 1.5/4.5, ranked[2].Score, 1e-9)
+	for _, rec := range ranked {
+		require.Equal(t, recommendationSourceFallback, rec.Source)
+	}
+
+	require.Empty(t, rankSkillMatches(nil, requiredSkills))
+}
+
+func TestGetRecommendationsFallback(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	// Arrange: an expert and a beginner in the task's skill, and a recommender that is down
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	manager := createTestUser(t, store, db.UserRoleManager, team.ID)
+	beginner := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+	expert := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+	createTestUser(t, store, db.UserRoleEngineer, team.ID) // Without the skill
+
+	project, err := store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: team.ID})
+	require.NoError(t, err)
+	task, err := store.CreateTask(ctx, db.CreateTaskParams{
+		ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+		Title:     util.RandomName(),
+		Status:    db.TaskStatusOpen,
+		Priority:  db.TaskPriorityMedium,
+	})
+	require.NoError(t, err)
+	skill, err := store.CreateSkill(ctx, db.CreateSkillParams{SkillName: "Skill " + util.RandomString(8)})
+	require.NoError(t, err)
+	_, err = store.AddSkillToTask(ctx, db.AddSkillToTaskParams{TaskID: task.ID, SkillID: skill.ID})
+	require.NoError(t, err)
+	for user, proficiency := range map[int64]db.ProficiencyLevel{beginner.ID: db.ProficiencyLevelBeginner, expert.ID: db.ProficiencyLevelExpert} {
+		_, err = store.AddSkillToUser(ctx, db.AddSkillToUserParams{UserID: user, SkillID: skill.ID, Proficiency: proficiency})
+		require.NoError(t, err)
+	}
+
+	recommender := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(recommender.Close)
+
+	server := newTestServer(t, store)
+	server.config.RecommenderAPIURL = recommender.URL
+
+	body, err := json.Marshal(gin.H{"task_id": task.ID})
+	require.NoError(t, err)
+
+	// Act
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/api/v1/manager/recommendations", bytes.NewReader(body))
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", "application/json")
+	addAuthorization(t, request, server, manager.ID, db.UserRoleManager, team.ID)
+	server.router.ServeHTTP(recorder, request)
+
+	// Assert
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+	var rsp struct {
+		Recommendations []EnrichedRecommendation `json:"recommendations"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Len(t, rsp.Recommendations, 2)
+	require.Equal(t, expert.ID, rsp.Recommendations[0].UserID)
+	require.Equal(t, beginner.ID, rsp.Recommendations[1].UserID)
+	for _, rec := range rsp.Recommendations {
+		require.Equal(t, recommendationSourceFallback, rec.Source)
+	}
+}
+
+func TestRecommenderClientReusesConnections(t *testing.T) {
+	recommender := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewEncoder(w).Encode(gin.H{"recommendations": []gin.H{{"user_id": 1, "score": 0.5}}}))
+	}))
+	t.Cleanup(recommender.Close)
+
+	server := newTestServer(t, newUnreachableStore(t))
+	server.config.RecommenderAPIURL = recommender.URL
+
+	// Count the connections the shared client opens
+	var dials atomic.Int32
+	transport := server.recommenderClient.Transport.(*http.Transport)
+	dial := transport.DialContext
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dials.Add(1)
+		return dial(ctx, network, addr)
+	}
+
+	requiredSkills := []db.TaskRequiredSkill{{SkillID: 1, Weight: 1}}
+	for range 5 {
+		rsp, err := server.fetchRecommendations(context.Background(), requiredSkills, 10)
+		require.NoError(t, err)
+		require.Len(t, rsp.Recommendations, 1)
+	}
+
+	// Every call after the first reuses the kept-alive connection
+	require.Equal(t, int32(1), dials.Load())
+}
+
+func TestGetRecommendationsCached(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	// Arrange: a task with one skill and a recommender that counts its calls
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	manager := createTestUser(t, store, db.UserRoleManager, team.ID)
+	engineer := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+
+	project, err := store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: team.ID})
+	require.NoError(t, err)
+	task, err := store.CreateTask(ctx, db.CreateTaskParams{
+		ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+		Title:     util.RandomName(),
+		Status:    db.TaskStatusOpen,
+		Priority:  db.TaskPriorityMedium,
+	})
+	require.NoError(t, err)
+	skill, err := store.CreateSkill(ctx, db.CreateSkillParams{SkillName: "Skill " + util.RandomString(8)})
+	require.NoError(t, err)
+	_, err = store.AddSkillToTask(ctx, db.AddSkillToTaskParams{TaskID: task.ID, SkillID: skill.ID})
+	require.NoError(t, err)
+
+	var hits atomic.Int32
+	recommender := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		resp := gin.H{"recommendations": []gin.H{{"user_id": engineer.ID, "score": 0.8}}}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	t.Cleanup(recommender.Close)
+
+	server := newTestServer(t, store)
+	server.config.RecommenderAPIURL = recommender.URL
+
+	send := func(t *testing.T, method, url string, body gin.H) {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(method, url, bytes.NewReader(data))
+		require.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		addAuthorization(t, request, server, manager.ID, db.UserRoleManager, team.ID)
+
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+	recommend := func(t *testing.T) {
+		send(t, http.MethodPost, "/api/v1/manager/recommendations", gin.H{"task_id": task.ID})
+	}
+
+	// Act & Assert: the second request within the TTL is answered from the cache
+	recommend(t)
+	recommend(t)
+	require.Equal(t, int32(1), hits.Load())
+
+	// Changing the description invalidates the task's cached results
+	send(t, http.MethodPatch, fmt.Sprintf("/api/v1/manager/tasks/%d", task.ID), gin.H{"description": "Rewritten description", "version": task.Version})
+	recommend(t)
+	require.Equal(t, int32(2), hits.Load())
+}
+
+func TestSearchTasks(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	// Arrange: tasks across two of the team's projects, plus a matching task on another team
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	otherTeam, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	manager := createTestUser(t, store, db.UserRoleManager, team.ID)
+	engineer := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+
+	createProject := func(teamID int64) db.Project {
+		project, err := store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: teamID})
+		require.NoError(t, err)
+		return project
+	}
+	createTask := func(project db.Project, title, description string, status db.TaskStatus, priority db.TaskPriority, assigneeID int64) db.Task {
+		task, err := store.CreateTask(ctx, db.CreateTaskParams{
+			ProjectID:   pgtype.Int8{Int64: project.ID, Valid: true},
+			Title:       title,
+			Description: pgtype.Text{String: description, Valid: true},
+			Status:      status,
+			Priority:    priority,
+			AssigneeID:  pgtype.Int8{Int64: assigneeID, Valid: assigneeID != 0},
+		})
+		require.NoError(t, err)
+		return task
+	}
+
+	api, web := createProject(team.ID), createProject(team.ID)
+	payments := createTask(api, "Payments webhook", "Verify signatures on incoming payment webhooks", db.TaskStatusInProgress, db.TaskPriorityHigh, engineer.ID)
+	refunds := createTask(web, "Refund form", "Let customers request refunds for payments", db.TaskStatusOpen, db.TaskPriorityHigh, 0)
+	login := createTask(web, "Login page", "Rate limit sign in attempts", db.TaskStatusOpen, db.TaskPriorityLow, engineer.ID)
+	uptime := createTask(web, "Uptime above 99%", "Alert on outages", db.TaskStatusOpen, db.TaskPriorityMedium, 0)
+	archived := createTask(api, "Old payments export", "Superseded", db.TaskStatusDone, db.TaskPriorityLow, 0)
+	_, err = store.ArchiveTask(ctx, archived.ID)
+	require.NoError(t, err)
+	createTask(createProject(otherTeam.ID), "Payments ledger", "Another team's payments work", db.TaskStatusOpen, db.TaskPriorityHigh, 0)
+
+	server := newTestServer(t, store)
+	search := func(t *testing.T, query string) paginatedResponse[db.SearchTasksByTeamRow] {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/api/v1/manager/tasks/search?page_id=1&page_size=10&"+query, nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server, manager.ID, db.UserRoleManager, team.ID)
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+		var rsp paginatedResponse[db.SearchTasksByTeamRow]
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+		require.Equal(t, int64(len(rsp.Data)), rsp.TotalCount)
+		return rsp
+	}
+	taskIDs := func(rsp paginatedResponse[db.SearchTasksByTeamRow]) []int64 {
+		ids := make([]int64, len(rsp.Data))
+		for i, task := range rsp.Data {
+			ids[i] = task.ID
+		}
+		return ids
+	}
+
+	testCases := []struct {
+		name  string
+		query string
+		want  []int64
+	}{
+		{"No filters lists every active task", "", []int64{uptime.ID, login.ID, refunds.ID, payments.ID}},
+		{"Text matches title and description", "q=payments", []int64{payments.ID, refunds.ID}},
+		{"Text matches part of a title", "q=refun", []int64{refunds.ID}},
+		{"LIKE wildcards match themselves", "q=%25", []int64{uptime.ID}},
+		{"An underscore is not a wildcard", "q=_", []int64{}},
+		{"Text and priority", "q=payments&priority=high&status=open", []int64{refunds.ID}},
+		{"Status and assignee", "status=open&assignee_id=" + fmt.Sprint(engineer.ID), []int64{login.ID}},
+		{"Priority and assignee", "priority=high&assignee_id=" + fmt.Sprint(engineer.ID), []int64{payments.ID}},
+		{"Archived tasks", "q=payments&archived=true", []int64{archived.ID}},
+		{"No match", "q=payments&priority=low", []int64{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rsp := search(t, tc.query)
+			require.ElementsMatch(t, tc.want, taskIDs(rsp))
+		})
+	}
+
+	t.Run("Best text match comes first", func(t *testing.T) {
+		rsp := search(t, "q=payments")
+		require.Equal(t, payments.ID, rsp.Data[0].ID)
+		require.Equal(t, api.ProjectName, rsp.Data[0].ProjectName)
+		require.Equal(t, engineer.Name, rsp.Data[0].AssigneeName)
+	})
+
+	t.Run("Pagination", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/api/v1/manager/tasks/search?page_id=2&page_size=5", nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server, manager.ID, db.UserRoleManager, team.ID)
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var rsp paginatedResponse[db.SearchTasksByTeamRow]
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+		require.Equal(t, int64(4), rsp.TotalCount)
+		require.Empty(t, rsp.Data)
+	})
+}
+
+func TestSearchTasksValidation(t *testing.T) {
+	server := newTestServer(t, newUnreachableStore(t))
+
+	testCases := []struct {
+		name   string
+		teamID int64
+		query  string
+		status int
+	}{
+		{"Missing pagination", 1, "q=payments", http.StatusBadRequest},
+		{"Invalid status", 1, "page_id=1&page_size=10&status=blocked", http.StatusBadRequest},
+		{"Invalid priority", 1, "page_id=1&page_size=10&priority=urgent", http.StatusBadRequest},
+		{"Invalid assignee", 1, "page_id=1&page_size=10&assignee_id=0x1", http.StatusBadRequest},
+		{"Manager without a team", 0, "page_id=1&page_size=10", http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/api/v1/manager/tasks/search?"+tc.query, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server, 1, db.UserRoleManager, tc.teamID)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.status, recorder.Code)
+		})
+	}
+}
+
+func TestArchiveAndRestoreTask(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	// Arrange: an engineer working on a task in an active project
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	manager := createTestUser(t, store, db.UserRoleManager, team.ID)
+	engineer := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+	project, err := store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: team.ID})
+	require.NoError(t, err)
+	createTask := func(projectID int64) db.Task {
+		task, err := store.CreateTask(ctx, db.CreateTaskParams{
+			ProjectID: pgtype.Int8{Int64: projectID, Valid: true},
+			Title:     util.RandomName(),
+			Status:    db.TaskStatusOpen,
+			Priority:  db.TaskPriorityMedium,
+		})
+		require.NoError(t, err)
+		return task
+	}
+	task := createTask(project.ID)
+	_, err = store.AssignTaskToUser(ctx, db.AssignTaskToUserTxParams{TaskID: task.ID, UserID: engineer.ID})
+	require.NoError(t, err)
+
+	server := newTestServer(t, store)
+	post := func(t *testing.T, action string, taskID, teamID int64) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/manager/tasks/%d/%s", taskID, action), nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server, manager.ID, db.UserRoleManager, teamID)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	t.Run("Archive frees the assignee", func(t *testing.T) {
+		recorder := post(t, "archive", task.ID, team.ID)
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+		var archived db.Task
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &archived))
+		require.True(t, archived.Archived)
+
+		user, err := store.GetUser(ctx, engineer.ID)
+		require.NoError(t, err)
+		require.Equal(t, db.AvailabilityStatusAvailable, user.Availability)
+	})
+
+	t.Run("Archiving twice is a conflict", func(t *testing.T) {
+		recorder := post(t, "archive", task.ID, team.ID)
+		require.Equal(t, http.StatusConflict, recorder.Code)
+	})
+
+	t.Run("Restore reopens the task unassigned", func(t *testing.T) {
+		recorder := post(t, "restore", task.ID, team.ID)
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+		var restored db.Task
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &restored))
+		require.False(t, restored.Archived)
+		require.Equal(t, db.TaskStatusOpen, restored.Status)
+		require.False(t, restored.AssigneeID.Valid)
+	})
+
+	t.Run("Restoring an active task is a conflict", func(t *testing.T) {
+		recorder := post(t, "restore", task.ID, team.ID)
+		require.Equal(t, http.StatusConflict, recorder.Code)
+	})
+
+	t.Run("Task of an archived project is restored with the project", func(t *testing.T) {
+		other, err := store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: team.ID})
+		require.NoError(t, err)
+		otherTask := createTask(other.ID)
+		require.Equal(t, http.StatusOK, post(t, "archive", otherTask.ID, team.ID).Code)
+		_, err = store.ArchiveProjectTx(ctx, db.ArchiveProjectTxParams{ProjectID: other.ID, TeamID: team.ID})
+		require.NoError(t, err)
+
+		recorder := post(t, "restore", otherTask.ID, team.ID)
+		require.Equal(t, http.StatusConflict, recorder.Code)
+	})
+
+	t.Run("Another team's task is forbidden", func(t *testing.T) {
+		otherTeam, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusForbidden, post(t, "archive", task.ID, otherTeam.ID).Code)
+		require.Equal(t, http.StatusForbidden, post(t, "restore", task.ID, otherTeam.ID).Code)
+	})
+}
+
+func TestReassignTask(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	// Arrange: a task in progress with one engineer and an available teammate
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	manager := createTestUser(t, store, db.UserRoleManager, team.ID)
+	previous := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+	next := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+	project, err := store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: team.ID})
+	require.NoError(t, err)
+	task, err := store.CreateTask(ctx, db.CreateTaskParams{
+		ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+		Title:     util.RandomName(),
+		Status:    db.TaskStatusOpen,
+		Priority:  db.TaskPriorityMedium,
+	})
+	require.NoError(t, err)
+	_, err = store.AssignTaskToUser(ctx, db.AssignTaskToUserTxParams{TaskID: task.ID, UserID: previous.ID})
+	require.NoError(t, err)
+
+	server := newTestServer(t, store)
+	reassign := func(t *testing.T, userID int64) *httptest.ResponseRecorder {
+		body, err := json.Marshal(gin.H{"user_id": userID})
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/manager/tasks/%d/reassign", task.ID), bytes.NewReader(body))
+		require.NoError(t, err)
+		addAuthorization(t, request, server, manager.ID, db.UserRoleManager, team.ID)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	t.Run("Engineer on another team is rejected", func(t *testing.T) {
+		otherTeam, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+		require.NoError(t, err)
+		outsider := createTestUser(t, store, db.UserRoleEngineer, otherTeam.ID)
+		require.Equal(t, http.StatusBadRequest, reassign(t, outsider.ID).Code)
+	})
+
+	t.Run("Hands the task over and frees the previous assignee", func(t *testing.T) {
+		recorder := reassign(t, next.ID)
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+		var rsp reassignTaskResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+		require.Equal(t, next.ID, rsp.Task.AssigneeID.Int64)
+		require.Equal(t, db.AvailabilityStatusBusy, rsp.NewAssignee.Availability)
+		require.NotNil(t, rsp.PreviousAssignee)
+		require.Equal(t, previous.ID, rsp.PreviousAssignee.ID)
+		require.Equal(t, db.AvailabilityStatusAvailable, rsp.PreviousAssignee.Availability)
+	})
+
+	t.Run("Current assignee is a conflict", func(t *testing.T) {
+		require.Equal(t, http.StatusConflict, reassign(t, next.ID).Code)
+	})
+}
+
+func TestGetDashboardTrends(t *testing.T) {
+	getTrends := func(t *testing.T, server *Server, teamID int64, query string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/api/v1/manager/dashboard/trends"+query, nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server, 1, db.UserRoleManager, teamID)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	t.Run("Invalid windows are rejected", func(t *testing.T) {
+		server := newTestServer(t, newUnreachableStore(t))
+		for _, query := range []string{"?days=91", "?days=-1", "?days=abc"} {
+			require.Equal(t, http.StatusBadRequest, getTrends(t, server, 1, query).Code, query)
+		}
+		require.Equal(t, http.StatusForbidden, getTrends(t, server, 0, "").Code)
+	})
+
+	t.Run("Counts only the team's tasks, one point per day", func(t *testing.T) {
+		store := newTestStore(t)
+		server := newTestServer(t, store)
+		ctx := context.Background()
+
+		createTasks := func(count int) []db.Task {
+			team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+			require.NoError(t, err)
+			project, err := store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: team.ID})
+			require.NoError(t, err)
+
+			var tasks []db.Task
+			for range count {
+				task, err := store.CreateTask(ctx, db.CreateTaskParams{
+					ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+					Title:     util.RandomName(),
+					Status:    db.TaskStatusOpen,
+					Priority:  db.TaskPriorityMedium,
+				})
+				require.NoError(t, err)
+				tasks = append(tasks, task)
+			}
+			return tasks
+		}
+		tasks := createTasks(2)
+		createTasks(3) // Another team's tasks are never counted
+
+		engineer := createTestUser(t, store, db.UserRoleEngineer, 0)
+		_, err := store.AssignTaskToUser(ctx, db.AssignTaskToUserTxParams{TaskID: tasks[0].ID, UserID: engineer.ID})
+		require.NoError(t, err)
+		_, err = store.MarkTaskDone(ctx, tasks[0].ID)
+		require.NoError(t, err)
+		project, err := store.GetProject(ctx, tasks[0].ProjectID.Int64)
+		require.NoError(t, err)
+
+		recorder := getTrends(t, server, project.TeamID, "?days=7")
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var rsp dashboardTrendsResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+		require.Equal(t, int32(7), rsp.Days)
+		require.Len(t, rsp.Series, 7)
+		require.Equal(t, int64(2), rsp.TotalCreated)
+		require.Equal(t, int64(1), rsp.TotalCompleted)
+
+		// The tasks were created and completed today, counted on today's UTC date
+		today := rsp.Series[len(rsp.Series)-1]
+		require.Equal(t, time.Now().UTC().Format(time.DateOnly), today.Date)
+		require.Equal(t, int64(2), today.Created)
+		require.Equal(t, int64(1), today.Completed)
+
+		// Days run oldest first without gaps
+		for i := 1; i < len(rsp.Series); i++ {
+			previous, err := time.Parse(time.DateOnly, rsp.Series[i-1].Date)
+			require.NoError(t, err)
+			current, err := time.Parse(time.DateOnly, rsp.Series[i].Date)
+			require.NoError(t, err)
+			require.Equal(t, 24*time.Hour, current.Sub(previous))
+		}
+
+		// Without days the window defaults to 30
+		recorder = getTrends(t, server, project.TeamID, "")
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+		require.Len(t, rsp.Series, defaultTrendDays)
+	})
+}
+
+func TestGetSkillGaps(t *testing.T) {
+	getGaps := func(t *testing.T, server *Server, teamID int64) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/api/v1/manager/dashboard/skill-gaps", nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server, 1, db.UserRoleManager, teamID)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	t.Run("Manager without a team is forbidden", func(t *testing.T) {
+		server := newTestServer(t, newUnreachableStore(t))
+		require.Equal(t, http.StatusForbidden, getGaps(t, server, 0).Code)
+	})
+
+	t.Run("Uncovered skills come first", func(t *testing.T) {
+		store := newTestStore(t)
+		server := newTestServer(t, store)
+		ctx := context.Background()
+
+		team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+		require.NoError(t, err)
+		project, err := store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: team.ID})
+		require.NoError(t, err)
+
+		createTask := func(status db.TaskStatus, skills ...db.Skill) {
+			task, err := store.CreateTask(ctx, db.CreateTaskParams{
+				ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+				Title:     util.RandomName(),
+				Status:    status,
+				Priority:  db.TaskPriorityMedium,
+			})
+			require.NoError(t, err)
+			for _, skill := range skills {
+				_, err = store.AddSkillToTask(ctx, db.AddSkillToTaskParams{TaskID: task.ID, SkillID: skill.ID})
+				require.NoError(t, err)
+			}
+		}
+		createSkill := func() db.Skill {
+			skill, err := store.CreateSkill(ctx, db.CreateSkillParams{SkillName: "Skill " + util.RandomString(8)})
+			require.NoError(t, err)
+			return skill
+		}
+
+		covered, uncovered, closedOnly := createSkill(), createSkill(), createSkill()
+		createTask(db.TaskStatusOpen, covered, uncovered)
+		createTask(db.TaskStatusOpen, covered)
+		createTask(db.TaskStatusDone, closedOnly) // Only open tasks create demand
+
+		engineer := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+		_, err = store.AddSkillToUser(ctx, db.AddSkillToUserParams{
+			UserID:      engineer.ID,
+			SkillID:     covered.ID,
+			Proficiency: db.ProficiencyLevelExpert,
+		})
+		require.NoError(t, err)
+
+		recorder := getGaps(t, server, team.ID)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var rsp skillGapsResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+		require.Equal(t, 1, rsp.UncoveredCount)
+		require.Len(t, rsp.Skills, 2)
+
+		require.Equal(t, uncovered.ID, rsp.Skills[0].SkillID)
+		require.Equal(t, int64(1), rsp.Skills[0].OpenTaskCount)
+		require.Zero(t, rsp.Skills[0].MemberCount)
+		require.True(t, rsp.Skills[0].Uncovered)
+
+		require.Equal(t, covered.ID, rsp.Skills[1].SkillID)
+		require.Equal(t, int64(2), rsp.Skills[1].OpenTaskCount)
+		require.Equal(t, int64(1), rsp.Skills[1].MemberCount)
+		require.False(t, rsp.Skills[1].Uncovered)
+	})
+}
+
+func TestExportProjectTasks(t *testing.T) {
+	export := func(t *testing.T, server *Server, path string, teamID int64) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server, 1, db.UserRoleManager, teamID)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	t.Run("Invalid requests are rejected", func(t *testing.T) {
+		server := newTestServer(t, newUnreachableStore(t))
+		require.Equal(t, http.StatusBadRequest, export(t, server, "/api/v1/manager/projects/1/tasks/export?format=xml", 1).Code)
+		require.Equal(t, http.StatusBadRequest, export(t, server, "/api/v1/manager/team/members/export?format=xml", 1).Code)
+		require.Equal(t, http.StatusForbidden, export(t, server, "/api/v1/manager/projects/1/tasks/export", 0).Code)
+		require.Equal(t, http.StatusForbidden, export(t, server, "/api/v1/manager/team/members/export", 0).Code)
+	})
+
+	t.Run("Exports every task and member", func(t *testing.T) {
+		store := newTestStore(t)
+		server := newTestServer(t, store)
+		ctx := context.Background()
+
+		team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+		require.NoError(t, err)
+		project, err := store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: team.ID})
+		require.NoError(t, err)
+
+		// More tasks than fit in one batch, so the export has to page
+		taskCount := exportBatchSize + 3
+		for range taskCount {
+			_, err := store.CreateTask(ctx, db.CreateTaskParams{
+				ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+				Title:     util.RandomName(),
+				Status:    db.TaskStatusOpen,
+				Priority:  db.TaskPriorityMedium,
+			})
+			require.NoError(t, err)
+		}
+		createTestUser(t, store, db.UserRoleEngineer, team.ID)
+		createTestUser(t, store, db.UserRoleEngineer, team.ID)
+
+		recorder := export(t, server, fmt.Sprintf("/api/v1/manager/projects/%d/tasks/export?format=csv", project.ID), team.ID)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.Equal(t, "text/csv; charset=utf-8", recorder.Header().Get("Content-Type"))
+		require.Equal(t, fmt.Sprintf(`attachment; filename="project-%d-tasks.csv"`, project.ID), recorder.Header().Get("Content-Disposition"))
+		records, err := csv.NewReader(recorder.Body).ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, []string{"id", "title", "status", "priority", "assignee_id", "assignee_name"}, records[0])
+		require.Len(t, records, taskCount+1)
+
+		recorder = export(t, server, fmt.Sprintf("/api/v1/manager/projects/%d/tasks/export", project.ID), team.ID)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var tasks []db.ListTasksWithAssigneeNamesAfterRow
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &tasks))
+		require.Len(t, tasks, taskCount)
+
+		// Every task appears exactly once across the batches
+		seen := make(map[int64]bool, len(tasks))
+		for _, task := range tasks {
+			require.False(t, seen[task.ID], "task %d exported twice", task.ID)
+			seen[task.ID] = true
+		}
+
+		recorder = export(t, server, "/api/v1/manager/team/members/export?format=csv", team.ID)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		records, err = csv.NewReader(recorder.Body).ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, []string{"id", "name", "email", "availability"}, records[0])
+		require.Len(t, records, 3)
+
+		// Another team's project is not exported
+		other, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+		require.NoError(t, err)
+		recorder = export(t, server, fmt.Sprintf("/api/v1/manager/projects/%d/tasks/export", project.ID), other.ID)
+		require.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
+func TestGetProjectStats(t *testing.T) {
+	stats := func(t *testing.T, server *Server, path string, teamID int64) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server, 1, db.UserRoleManager, teamID)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	t.Run("Invalid requests are rejected", func(t *testing.T) {
+		server := newTestServer(t, newUnreachableStore(t))
+		require.Equal(t, http.StatusBadRequest, stats(t, server, "/api/v1/manager/projects/abc/stats", 1).Code)
+		require.Equal(t, http.StatusForbidden, stats(t, server, "/api/v1/manager/projects/1/stats", 0).Code)
+	})
+
+	t.Run("Counts tasks by status, priority and assignee", func(t *testing.T) {
+		store := newTestStore(t)
+		server := newTestServer(t, store)
+		ctx := context.Background()
+
+		team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+		require.NoError(t, err)
+		project, err := store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: team.ID})
+		require.NoError(t, err)
+		path := fmt.Sprintf("/api/v1/manager/projects/%d/stats", project.ID)
+
+		// An empty project still lists every status and priority
+		recorder := stats(t, server, path, team.ID)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var empty projectStatsResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &empty))
+		require.Zero(t, empty.TotalTasks)
+		require.Len(t, empty.ByStatus, 3)
+		require.Len(t, empty.ByPriority, len(taskPriorities))
+		require.NotNil(t, empty.ByAssignee)
+		require.Empty(t, empty.ByAssignee)
+		require.Zero(t, empty.AvgCompletionSeconds)
+
+		engineer := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+		assignee := pgtype.Int8{Int64: engineer.ID, Valid: true}
+		for _, task := range []db.CreateTaskParams{
+			{Status: db.TaskStatusOpen, Priority: db.TaskPriorityHigh, AssigneeID: assignee},
+			{Status: db.TaskStatusInProgress, Priority: db.TaskPriorityHigh, AssigneeID: assignee},
+			{Status: db.TaskStatusDone, Priority: db.TaskPriorityLow, AssigneeID: assignee},
+			{Status: db.TaskStatusOpen, Priority: db.TaskPriorityMedium},
+		} {
+			task.ProjectID = pgtype.Int8{Int64: project.ID, Valid: true}
+			task.Title = util.RandomName()
+			_, err := store.CreateTask(ctx, task)
+			require.NoError(t, err)
+		}
+
+		recorder = stats(t, server, path, team.ID)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var got projectStatsResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+		require.Equal(t, project.ID, got.ProjectID)
+		require.Equal(t, int64(4), got.TotalTasks)
+		require.Equal(t, int64(2), got.ByStatus[db.TaskStatusOpen])
+		require.Equal(t, int64(1), got.ByStatus[db.TaskStatusInProgress])
+		require.Equal(t, int64(1), got.ByStatus[db.TaskStatusDone])
+		require.Equal(t, int64(2), got.ByPriority[db.TaskPriorityHigh])
+		require.Equal(t, int64(1), got.ByPriority[db.TaskPriorityMedium])
+		require.Equal(t, int64(1), got.ByPriority[db.TaskPriorityLow])
+
+		// The engineer has the most tasks, then the unassigned ones
+		require.Len(t, got.ByAssignee, 2)
+		require.Equal(t, assignee, got.ByAssignee[0].AssigneeID)
+		require.Equal(t, int64(3), got.ByAssignee[0].TaskCount)
+		require.Equal(t, int64(1), got.ByAssignee[0].DoneCount)
+		require.False(t, got.ByAssignee[1].AssigneeID.Valid)
+		require.Equal(t, int64(1), got.ByAssignee[1].TaskCount)
+
+		// Another team's project is hidden
+		other, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, stats(t, server, path, other.ID).Code)
+	})
+}
+
+func TestUpdateVersionConflicts(t *testing.T) {
+	send := func(t *testing.T, server *Server, method, url string, teamID int64, body gin.H) *httptest.ResponseRecorder {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(method, url, bytes.NewReader(data))
+		require.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		addAuthorization(t, request, server, 1, db.UserRoleManager, teamID)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	t.Run("Updates must name a version", func(t *testing.T) {
+		server := newTestServer(t, newUnreachableStore(t))
+		require.Equal(t, http.StatusBadRequest, send(t, server, http.MethodPatch, "/api/v1/manager/tasks/1", 1, gin.H{"title": "New"}).Code)
+		require.Equal(t, http.StatusBadRequest, send(t, server, http.MethodPut, "/api/v1/manager/projects/1", 1, gin.H{"name": "New"}).Code)
+		require.Equal(t, http.StatusBadRequest, send(t, server, http.MethodPatch, "/api/v1/manager/tasks/1", 1, gin.H{"title": "New", "version": 0}).Code)
+	})
+
+	t.Run("A stale version is a conflict", func(t *testing.T) {
+		store := newTestStore(t)
+		server := newTestServer(t, store)
+		ctx := context.Background()
+
+		team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+		require.NoError(t, err)
+		project, err := store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: team.ID})
+		require.NoError(t, err)
+		task, err := store.CreateTask(ctx, db.CreateTaskParams{
+			ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+			Title:     util.RandomName(),
+			Status:    db.TaskStatusOpen,
+			Priority:  db.TaskPriorityMedium,
+		})
+		require.NoError(t, err)
+
+		// Two managers read the task at the same version; the first edit wins and bumps it
+		taskURL := fmt.Sprintf("/api/v1/manager/tasks/%d", task.ID)
+		recorder := send(t, server, http.MethodPatch, taskURL, team.ID, gin.H{"title": "First", "version": task.Version})
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		var updatedTask db.Task
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &updatedTask))
+		require.Equal(t, task.Version+1, updatedTask.Version)
+
+		// The second edit is rejected instead of overwriting the first
+		recorder = send(t, server, http.MethodPatch, taskURL, team.ID, gin.H{"title": "Second", "version": task.Version})
+		require.Equal(t, http.StatusConflict, recorder.Code)
+		current, err := store.GetTask(ctx, task.ID)
+		require.NoError(t, err)
+		require.Equal(t, "First", current.Title)
+
+		// A stale status change is refused too, together with any details sent with it
+		engineer := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+		_, err = store.UpdateTask(ctx, db.UpdateTaskParams{ID: task.ID, AssigneeID: pgtype.Int8{Int64: engineer.ID, Valid: true}})
+		require.NoError(t, err)
+		recorder = send(t, server, http.MethodPatch, taskURL, team.ID, gin.H{"status": "in_progress", "version": updatedTask.Version})
+		require.Equal(t, http.StatusConflict, recorder.Code)
+		recorder = send(t, server, http.MethodPatch, taskURL, team.ID, gin.H{"status": "in_progress", "title": "Third", "version": updatedTask.Version})
+		require.Equal(t, http.StatusConflict, recorder.Code)
+		current, err = store.GetTask(ctx, task.ID)
+		require.NoError(t, err)
+		require.Equal(t, db.TaskStatusOpen, current.Status)
+		require.Equal(t, "First", current.Title)
+
+		// Status changes bump the version like any other edit
+		recorder = send(t, server, http.MethodPatch, taskURL, team.ID, gin.H{"status": "in_progress", "title": "Third", "version": current.Version})
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &updatedTask))
+		require.Equal(t, db.TaskStatusInProgress, updatedTask.Status)
+		require.Equal(t, "Third", updatedTask.Title)
+		require.Equal(t, current.Version+2, updatedTask.Version)
+
+		// The same holds for projects
+		projectURL := fmt.Sprintf("/api/v1/manager/projects/%d", project.ID)
+		recorder = send(t, server, http.MethodPut, projectURL, team.ID, gin.H{"name": "First", "version": project.Version})
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		var updatedProject db.Project
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &updatedProject))
+		require.Equal(t, project.Version+1, updatedProject.Version)
+
+		recorder = send(t, server, http.MethodPut, projectURL, team.ID, gin.H{"name": "Second", "version": project.Version})
+		require.Equal(t, http.StatusConflict, recorder.Code)
+		currentProject, err := store.GetProject(ctx, project.ID)
+		require.NoError(t, err)
+		require.Equal(t, "First", currentProject.ProjectName)
+	})
+}
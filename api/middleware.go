@@ -3,14 +3,23 @@
 package api
 
 import (
+	"crypto/rand"
 	"errors"
 	"fmt"
+	"log/slog"
+	"mime"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	db "github.com/pranav244872/synapse/db/sqlc"
+	"github.com/pranav244872/synapse/metrics"
 	"github.com/pranav244872/synapse/token"
 )
 
@@ -21,6 +30,9 @@ const (
 	authorizationPayloadKey = "authorization_payload"
 )
 
+// requestIDHeader carries the request ID; a client-supplied value is kept so calls can be traced end to end
+const requestIDHeader = "X-Request-ID"
+
 ////////////////////////////////////////////////////////////////////////
 // CORS MIDDLEWARE
 ////////////////////////////////////////////////////////////////////////
@@ -31,7 +43,8 @@ func (server *Server) CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", server.config.FrontendURL)
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, Accept")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, Accept, If-None-Match")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag") // Lets frontends send it back in If-None-Match
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, PATCH, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
@@ -43,12 +56,131 @@ func (server *Server) CORSMiddleware() gin.HandlerFunc {
 	}
 }
 
+////////////////////////////////////////////////////////////////////////
+// ACCESS LOG MIDDLEWARE
+////////////////////////////////////////////////////////////////////////
+
+// accessLogMiddleware writes one structured INFO line per request with its method, route, status,
+// latency, request ID and, once authenticated, the user and team. Every request gets a request ID
+// header; the skipped paths (health probes) are never logged, and with sampleRate N > 1 only one
+// request in N is.
+func accessLogMiddleware(logger *slog.Logger, sampleRate int, skipPaths ...string) gin.HandlerFunc {
+	skip := make(map[string]bool, len(skipPaths))
+	for _, path := range skipPaths {
+		skip[path] = true
+	}
+	var requests atomic.Uint64
+
+	return func(ctx *gin.Context) {
+		requestID := ctx.GetHeader(requestIDHeader)
+		if requestID == "" {
+			requestID = rand.Text()
+		}
+		ctx.Header(requestIDHeader, requestID)
+
+		if skip[ctx.Request.URL.Path] {
+			ctx.Next()
+			return
+		}
+
+		start := time.Now()
+		ctx.Next()
+
+		if sampleRate > 1 && (requests.Add(1)-1)%uint64(sampleRate) != 0 {
+			return
+		}
+
+		// The route pattern keeps IDs out of the path; unmatched requests fall back to the raw path
+		path := ctx.FullPath()
+		if path == "" {
+			path = ctx.Request.URL.Path
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", ctx.Request.Method),
+			slog.String("path", path),
+			slog.Int("status", ctx.Writer.Status()),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("request_id", requestID),
+		}
+		if payload, err := getAuthorizationPayload(ctx); err == nil {
+			if userID, ok := payload["user_id"].(float64); ok {
+				attrs = append(attrs, slog.Int64("user_id", int64(userID)))
+			}
+			if teamID, ok := payload["team_id"].(float64); ok && teamID != 0 {
+				attrs = append(attrs, slog.Int64("team_id", int64(teamID)))
+			}
+		}
+
+		logger.LogAttrs(ctx, slog.LevelInfo, "access", attrs...)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////
+// METRICS MIDDLEWARE
+////////////////////////////////////////////////////////////////////////
+
+// metricsMiddleware records each request's count and latency by method, route pattern and status.
+// Unmatched requests share one "unmatched" route so arbitrary paths cannot blow up label cardinality.
+func metricsMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(ctx.Writer.Status())
+
+		metrics.HTTPRequests.WithLabelValues(ctx.Request.Method, route, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(ctx.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+////////////////////////////////////////////////////////////////////////
+// CONTENT-TYPE MIDDLEWARE
+////////////////////////////////////////////////////////////////////////
+
+// jsonContentTypeMiddleware rejects request bodies that are not declared as application/json with 415.
+// Requests without a body are let through, as are the exempt route patterns (e.g. file or CSV uploads),
+// which are matched against the registered route such as "/api/v1/admin/users/import".
+func jsonContentTypeMiddleware(exemptRoutes ...string) gin.HandlerFunc {
+	exempt := make(map[string]bool, len(exemptRoutes))
+	for _, route := range exemptRoutes {
+		exempt[route] = true
+	}
+
+	return func(ctx *gin.Context) {
+		switch ctx.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+		default:
+			ctx.Next()
+			return
+		}
+
+		if ctx.Request.ContentLength == 0 || exempt[ctx.FullPath()] {
+			ctx.Next()
+			return
+		}
+
+		mediaType, _, err := mime.ParseMediaType(ctx.GetHeader("Content-Type"))
+		if err != nil || mediaType != gin.MIMEJSON {
+			err := fmt.Errorf("unsupported content type %q: expected %s", ctx.GetHeader("Content-Type"), gin.MIMEJSON)
+			ctx.AbortWithStatusJSON(http.StatusUnsupportedMediaType, errorResponse(err))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
 ////////////////////////////////////////////////////////////////////////
 // AUTHENTICATION MIDDLEWARE
 ////////////////////////////////////////////////////////////////////////
 
 // authMiddleware checks for a valid JWT and stores its payload in the context.
-func authMiddleware(tokenMaker *token.JWTMaker) gin.HandlerFunc {
+func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
 		if len(authorizationHeader) == 0 {
@@ -87,31 +219,16 @@ func authMiddleware(tokenMaker *token.JWTMaker) gin.HandlerFunc {
 // AUTHORIZATION MIDDLEWARE (ROLE-BASED)
 ////////////////////////////////////////////////////////////////////////
 
-// adminAuthMiddleware checks if the user has the 'admin' role.
-// It must be used AFTER authMiddleware.
-func adminAuthMiddleware() gin.HandlerFunc {
-	return func(ctx *gin.Context) {
-		// Get the payload that authMiddleware stored in the context.
-		payload, err := getAuthorizationPayload(ctx)
-		if err != nil {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
-			return
-		}
-
-		// Check the 'role' claim from the token.
-		if payload["role"] != string(db.UserRoleAdmin) {
-			err := errors.New("this action requires admin privileges")
-			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err)) // 403 Forbidden
-			return
-		}
-
-		ctx.Next()
+// requireRole lets a request through only when the token's role is one of roles, answering 403 otherwise.
+// It must be used AFTER authMiddleware, and is applied where route groups are registered so a handler
+// never runs for a role it is not meant for; handlers are left with their team-scoping checks.
+func requireRole(roles ...db.UserRole) gin.HandlerFunc {
+	allowed := make([]string, len(roles))
+	for i, role := range roles {
+		allowed[i] = string(role)
 	}
-}
+	forbidden := fmt.Errorf("forbidden: this action requires the %s role", strings.Join(allowed, " or "))
 
-// managerAuthMiddleware checks if the user has the 'manager' role.
-// It must be used AFTER authMiddleware.
-func managerAuthMiddleware() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		payload, err := getAuthorizationPayload(ctx)
 		if err != nil {
@@ -119,29 +236,9 @@ func managerAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		if payload["role"] != string(db.UserRoleManager) {
-			err := errors.New("this action requires manager privileges")
-			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err)) // 403 Forbidden
-			return
-		}
-
-		ctx.Next()
-	}
-}
-
-// engineerAuthMiddleware checks if the authenticated user has the 'engineer' role.
-func engineerAuthMiddleware() gin.HandlerFunc {
-	return func(ctx *gin.Context) {
-		authPayload, err := getAuthorizationPayload(ctx)
-		if err != nil {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
-			return
-		}
-
-		role, ok := authPayload["role"].(string)
-		if !ok || role != string(db.UserRoleEngineer) {
-			err := errors.New("forbidden: this action is restricted to engineers")
-			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+		role, _ := payload["role"].(string)
+		if !slices.Contains(allowed, role) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(forbidden))
 			return
 		}
 
@@ -167,3 +264,52 @@ func getAuthorizationPayload(ctx *gin.Context) (jwt.MapClaims, error) {
 
 	return claims, nil
 }
+
+// Errors for tokens whose claims do not have the shape CreateToken gives them
+var (
+	errInvalidUserIDClaim = errors.New("invalid user_id in token")
+	errInvalidTeamIDClaim = errors.New("invalid team_id in token")
+)
+
+// getUserIDFromPayload returns the caller's user ID from the token. Unlike a bare type
+// assertion it cannot panic: a missing or malformed claim is an error, answered with 401.
+func getUserIDFromPayload(ctx *gin.Context) (int64, error) {
+	payload, err := getAuthorizationPayload(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// Numeric claims arrive as float64 once the token is decoded
+	userID, ok := payload["user_id"].(float64)
+	if !ok || userID <= 0 {
+		return 0, errInvalidUserIDClaim
+	}
+	return int64(userID), nil
+}
+
+// getTeamIDFromPayload returns the caller's team ID from the token, or 0 if they are not on a
+// team, since tokens of teamless users carry no team_id. A claim of the wrong type is an error,
+// answered with 401; callers that need a team still answer 0 with 403.
+func getTeamIDFromPayload(ctx *gin.Context) (int64, error) {
+	payload, err := getAuthorizationPayload(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	claim, exists := payload["team_id"]
+	if !exists {
+		return 0, nil
+	}
+	teamID, ok := claim.(float64)
+	if !ok || teamID < 0 {
+		return 0, errInvalidTeamIDClaim
+	}
+	return int64(teamID), nil
+}
+
+// actorIDFromPayload returns the token's user as the actor recorded in activity logs,
+// or NULL if the token carries no user_id.
+func actorIDFromPayload(payload jwt.MapClaims) pgtype.Int8 {
+	userID, ok := payload["user_id"].(float64)
+	return pgtype.Int8{Int64: int64(userID), Valid: ok}
+}
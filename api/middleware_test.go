@@ -0,0 +1,291 @@
+// api/middleware_test.go
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	db "github.com/pranav244872/synapse/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONContentTypeMiddleware(t *testing.T) {
+	t.Run("Plain text to a JSON route is rejected", func(t *testing.T) {
+		// Arrange
+		server := newTestServer(t, nil)
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader("email=a@b.c"))
+		require.NoError(t, err)
+		request.Header.Set("Content-Type", "text/plain")
+
+		// Act
+		server.router.ServeHTTP(recorder, request)
+
+		// Assert
+		require.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+	})
+
+	testCases := []struct {
+		name        string
+		method      string
+		path        string
+		contentType string
+		body        string
+		wantStatus  int
+	}{
+		{"JSON body is accepted", http.MethodPost, "/json", "application/json", `{}`, http.StatusOK},
+		{"JSON with charset is accepted", http.MethodPut, "/json", "application/json; charset=utf-8", `{}`, http.StatusOK},
+		{"Form body is rejected", http.MethodPatch, "/json", "application/x-www-form-urlencoded", "a=b", http.StatusUnsupportedMediaType},
+		{"Missing content type is rejected", http.MethodPost, "/json", "", `{}`, http.StatusUnsupportedMediaType},
+		{"Empty body is let through", http.MethodPost, "/json", "", "", http.StatusOK},
+		{"GET is not checked", http.MethodGet, "/json", "text/plain", "", http.StatusOK},
+		{"Exempt route accepts CSV", http.MethodPost, "/upload/:id", "text/csv", "a,b", http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			router := gin.New()
+			router.Use(jsonContentTypeMiddleware("/upload/:id"))
+			ok := func(ctx *gin.Context) { ctx.Status(http.StatusOK) }
+			router.Handle(tc.method, "/json", ok)
+			router.Handle(tc.method, "/upload/:id", ok)
+
+			path := strings.Replace(tc.path, ":id", "1", 1)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(tc.method, path, strings.NewReader(tc.body))
+			require.NoError(t, err)
+			if tc.contentType != "" {
+				request.Header.Set("Content-Type", tc.contentType)
+			}
+
+			// Act
+			router.ServeHTTP(recorder, request)
+
+			// Assert
+			require.Equal(t, tc.wantStatus, recorder.Code)
+		})
+	}
+}
+
+func TestAccessLogMiddleware(t *testing.T) {
+	// newRouter logs into buf; its route stands in for authMiddleware by setting the claims itself
+	newRouter := func(buf *bytes.Buffer, sampleRate int) *gin.Engine {
+		router := gin.New()
+		router.Use(accessLogMiddleware(slog.New(slog.NewJSONHandler(buf, nil)), sampleRate, "/readyz"))
+		router.GET("/tasks/:id", func(ctx *gin.Context) {
+			ctx.Set(authorizationPayloadKey, jwt.MapClaims{"user_id": float64(7), "team_id": float64(3)})
+			ctx.Status(http.StatusAccepted)
+		})
+		router.GET("/readyz", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+		return router
+	}
+	serve := func(router *gin.Engine, path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(t, err)
+		request.Header.Set(requestIDHeader, "req-123")
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	t.Run("A request produces one entry with the expected fields", func(t *testing.T) {
+		var buf bytes.Buffer
+		recorder := serve(newRouter(&buf, 0), "/tasks/42")
+		require.Equal(t, "req-123", recorder.Header().Get(requestIDHeader))
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 1)
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+		require.Equal(t, "INFO", entry["level"])
+		require.Equal(t, "access", entry["msg"])
+		require.Equal(t, http.MethodGet, entry["method"])
+		require.Equal(t, "/tasks/:id", entry["path"])
+		require.Equal(t, float64(http.StatusAccepted), entry["status"])
+		require.Contains(t, entry, "latency")
+		require.Equal(t, "req-123", entry["request_id"])
+		require.Equal(t, float64(7), entry["user_id"])
+		require.Equal(t, float64(3), entry["team_id"])
+	})
+
+	t.Run("Health probes are not logged", func(t *testing.T) {
+		var buf bytes.Buffer
+		serve(newRouter(&buf, 0), "/readyz")
+		require.Empty(t, buf.String())
+	})
+
+	t.Run("Sampling logs one request in N", func(t *testing.T) {
+		var buf bytes.Buffer
+		router := newRouter(&buf, 3)
+		for range 7 {
+			serve(router, "/tasks/1")
+		}
+		require.Equal(t, 3, strings.Count(buf.String(), "\n")) // Requests 1, 4 and 7
+	})
+}
+
+func TestPayloadIDExtractors(t *testing.T) {
+	// newContext stands in for authMiddleware by storing the given claims, or none if nil
+	newContext := func(claims jwt.MapClaims) *gin.Context {
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		if claims != nil {
+			ctx.Set(authorizationPayloadKey, claims)
+		}
+		return ctx
+	}
+
+	t.Run("Well-formed claims are returned as int64", func(t *testing.T) {
+		ctx := newContext(jwt.MapClaims{"user_id": float64(7), "team_id": float64(3)})
+
+		userID, err := getUserIDFromPayload(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(7), userID)
+
+		teamID, err := getTeamIDFromPayload(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), teamID)
+	})
+
+	t.Run("A token without team_id belongs to a teamless user", func(t *testing.T) {
+		teamID, err := getTeamIDFromPayload(newContext(jwt.MapClaims{"user_id": float64(7)}))
+		require.NoError(t, err)
+		require.Zero(t, teamID)
+	})
+
+	t.Run("Malformed user_id claims are errors", func(t *testing.T) {
+		for _, claims := range []jwt.MapClaims{
+			{},
+			{"user_id": "7"},
+			{"user_id": nil},
+			{"user_id": float64(0)},
+			{"user_id": float64(-1)},
+		} {
+			_, err := getUserIDFromPayload(newContext(claims))
+			require.ErrorIs(t, err, errInvalidUserIDClaim, claims)
+		}
+	})
+
+	t.Run("Malformed team_id claims are errors", func(t *testing.T) {
+		for _, claims := range []jwt.MapClaims{
+			{"team_id": "3"},
+			{"team_id": nil},
+			{"team_id": float64(-3)},
+		} {
+			_, err := getTeamIDFromPayload(newContext(claims))
+			require.ErrorIs(t, err, errInvalidTeamIDClaim, claims)
+		}
+	})
+
+	t.Run("A missing payload is an error", func(t *testing.T) {
+		_, err := getUserIDFromPayload(newContext(nil))
+		require.Error(t, err)
+		_, err = getTeamIDFromPayload(newContext(nil))
+		require.Error(t, err)
+	})
+}
+
+func TestMalformedTokenClaims(t *testing.T) {
+	server := newTestServer(t, newUnreachableStore(t))
+
+	// serve signs claims with the server's key, so only their shape is wrong
+	serve := func(method, path string, claims jwt.MapClaims) *httptest.ResponseRecorder {
+		claims["exp"] = time.Now().Add(time.Minute).Unix()
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(server.config.TokenSymmetricKey))
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(method, path, nil)
+		require.NoError(t, err)
+		request.Header.Set(authorizationHeaderKey, authorizationTypeBearer+" "+token)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+		claims jwt.MapClaims
+	}{
+		{"whoami with a string user_id", http.MethodGet, "/api/v1/auth/whoami", jwt.MapClaims{"user_id": "abc", "role": "engineer"}},
+		{"profile without user_id", http.MethodGet, "/api/v1/users/me", jwt.MapClaims{"role": "engineer"}},
+		{"engineer task with a string user_id", http.MethodGet, "/api/v1/engineer/current-task", jwt.MapClaims{"user_id": "abc", "role": "engineer"}},
+		{"manager dashboard with a string team_id", http.MethodGet, "/api/v1/manager/dashboard/stats", jwt.MapClaims{"user_id": float64(1), "role": "manager", "team_id": "x"}},
+		{"manager team members with a boolean team_id", http.MethodGet, "/api/v1/manager/team/members", jwt.MapClaims{"user_id": float64(1), "role": "manager", "team_id": true}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var recorder *httptest.ResponseRecorder
+			require.NotPanics(t, func() { recorder = serve(tc.method, tc.path, tc.claims) })
+			require.Equal(t, http.StatusUnauthorized, recorder.Code, recorder.Body.String())
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	// The route stands in for authMiddleware by setting the claims itself
+	serve := func(role any) int {
+		router := gin.New()
+		router.GET("/", func(ctx *gin.Context) {
+			ctx.Set(authorizationPayloadKey, jwt.MapClaims{"user_id": float64(1), "role": role})
+		}, requireRole(db.UserRoleManager, db.UserRoleEngineer), func(ctx *gin.Context) {
+			ctx.Status(http.StatusNoContent)
+		})
+
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/", nil)
+		require.NoError(t, err)
+		router.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	require.Equal(t, http.StatusNoContent, serve("manager"))
+	require.Equal(t, http.StatusNoContent, serve("engineer"))
+	require.Equal(t, http.StatusForbidden, serve("admin"))
+	require.Equal(t, http.StatusForbidden, serve(nil))
+	require.Equal(t, http.StatusForbidden, serve(float64(1)))
+}
+
+func TestRouteGroupsRequireRole(t *testing.T) {
+	// The guard answers before any handler reaches the database
+	server := newTestServer(t, newUnreachableStore(t))
+
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+		role   db.UserRole
+	}{
+		{"Admin route with a manager token", http.MethodGet, "/api/v1/admin/users?page_id=1&page_size=5", db.UserRoleManager},
+		{"Admin route with an engineer token", http.MethodDelete, "/api/v1/admin/teams/1", db.UserRoleEngineer},
+		{"Manager route with an engineer token", http.MethodGet, "/api/v1/manager/dashboard/stats", db.UserRoleEngineer},
+		{"Manager route with an admin token", http.MethodGet, "/api/v1/manager/team/members", db.UserRoleAdmin},
+		{"Engineer route with a manager token", http.MethodGet, "/api/v1/engineer/current-task", db.UserRoleManager},
+		{"Engineer-only profile route with an admin token", http.MethodPatch, "/api/v1/users/me/availability", db.UserRoleAdmin},
+		{"Comment route with an admin token", http.MethodGet, "/api/v1/tasks/1/comments", db.UserRoleAdmin},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(tc.method, tc.path, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server, 1, tc.role, 1)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusForbidden, recorder.Code)
+			require.Contains(t, recorder.Body.String(), "requires the")
+		})
+	}
+}
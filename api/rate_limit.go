@@ -0,0 +1,137 @@
+// api/rate_limit.go
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Rate Limiting
+////////////////////////////////////////////////////////////////////////
+
+const (
+	defaultLoginRateLimit = 10 // Login attempts per minute per client IP, used when LOGIN_RATE_LIMIT is not set
+	defaultLLMRateLimit   = 20 // Requests per minute per user to LLM-backed endpoints, used when LLM_RATE_LIMIT is not set
+
+	rateLimiterIdleTTL       = 10 * time.Minute // A key unseen this long has a full bucket again and is forgotten
+	rateLimiterSweepInterval = time.Minute      // How often idle keys are looked for
+)
+
+var errRateLimited = errors.New("too many requests, please retry later")
+
+// rateLimiterEntry is the token bucket of one key
+type rateLimiterEntry struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// rateLimiter keeps a token bucket per key (a client IP or a user). Each bucket holds up to a
+// minute's worth of requests and refills evenly over the minute. A nil limiter allows everything.
+type rateLimiter struct {
+	mu        sync.Mutex
+	limit     rate.Limit
+	burst     int
+	now       func() time.Time // Replaceable in tests
+	lastSweep time.Time
+	entries   map[string]*rateLimiterEntry
+}
+
+// newRateLimiter returns a limiter allowing perMinute requests per key, or nil (disabled)
+// when perMinute is not positive
+func newRateLimiter(perMinute int) *rateLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &rateLimiter{
+		limit:   rate.Limit(float64(perMinute) / time.Minute.Seconds()),
+		burst:   perMinute,
+		now:     time.Now,
+		entries: make(map[string]*rateLimiterEntry),
+	}
+}
+
+// allow takes a token from key's bucket. When the bucket is empty it reports false and how long
+// until the next token. Idle keys are evicted as it goes so the map cannot grow without bound.
+func (l *rateLimiter) allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastSweep) >= rateLimiterSweepInterval {
+		for k, entry := range l.entries {
+			if now.Sub(entry.lastSeen) >= rateLimiterIdleTTL {
+				delete(l.entries, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	entry, ok := l.entries[key]
+	if !ok {
+		entry = &rateLimiterEntry{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.entries[key] = entry
+	}
+	entry.lastSeen = now
+
+	reservation := entry.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		// Give the token back: a rejected request must not push the caller's next slot further out
+		reservation.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// rateLimitByIP keys requests by the client's IP address
+func rateLimitByIP(ctx *gin.Context) string {
+	return "ip:" + ctx.ClientIP()
+}
+
+// rateLimitVerifyByIP keys invitation checks by the client's IP address. The prefix keeps them
+// in their own bucket, so loading the signup page never spends the client's login attempts.
+func rateLimitVerifyByIP(ctx *gin.Context) string {
+	return "verify:" + ctx.ClientIP()
+}
+
+// rateLimitByUser keys requests by the authenticated user, falling back to the client's IP
+// on public routes
+func rateLimitByUser(ctx *gin.Context) string {
+	authPayload, err := getAuthorizationPayload(ctx)
+	if err != nil {
+		return rateLimitByIP(ctx)
+	}
+	actorID := actorIDFromPayload(authPayload)
+	if !actorID.Valid {
+		return rateLimitByIP(ctx)
+	}
+	return fmt.Sprintf("user:%d", actorID.Int64)
+}
+
+// rateLimitMiddleware rejects requests with 429 and a Retry-After header (in whole seconds)
+// once the caller's bucket is empty. On authenticated routes it must run after authMiddleware.
+func rateLimitMiddleware(limiter *rateLimiter, keyFunc func(*gin.Context) string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if limiter == nil {
+			ctx.Next()
+			return
+		}
+
+		if ok, retryAfter := limiter.allow(keyFunc(ctx)); !ok {
+			seconds := max(1, int(math.Ceil(retryAfter.Seconds())))
+			ctx.Header("Retry-After", strconv.Itoa(seconds))
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, errorResponse(errRateLimited))
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,202 @@
+// api/rate_limit_test.go
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pranav244872/synapse/config"
+	"github.com/pranav244872/synapse/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	now := time.Date(2025, time.March, 4, 10, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(3)
+	limiter.now = func() time.Time { return now }
+
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) {
+		if user := ctx.GetHeader("X-User"); user != "" {
+			var userID float64
+			fmt.Sscan(user, &userID)
+			ctx.Set(authorizationPayloadKey, jwt.MapClaims{"user_id": userID})
+		}
+	})
+	router.POST("/tasks", rateLimitMiddleware(limiter, rateLimitByUser), func(ctx *gin.Context) {
+		ctx.Status(http.StatusCreated)
+	})
+
+	send := func(user string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/tasks", nil)
+		require.NoError(t, err)
+		request.Header.Set("X-User", user)
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// The bucket holds a minute's worth of requests, then the caller is turned away
+	for range 3 {
+		require.Equal(t, http.StatusCreated, send("1").Code)
+	}
+	limited := send("1")
+	require.Equal(t, http.StatusTooManyRequests, limited.Code)
+	require.Equal(t, "20", limited.Header().Get("Retry-After"))
+
+	// Other users have their own bucket, and so do anonymous callers by IP
+	require.Equal(t, http.StatusCreated, send("2").Code)
+	require.Equal(t, http.StatusCreated, send("").Code)
+
+	// Rejected requests do not push the next token further out
+	now = now.Add(19 * time.Second)
+	require.Equal(t, http.StatusTooManyRequests, send("1").Code)
+	now = now.Add(time.Second)
+	require.Equal(t, http.StatusCreated, send("1").Code)
+
+	// Keys idle long enough are forgotten
+	now = now.Add(rateLimiterIdleTTL)
+	send("3")
+	require.Len(t, limiter.entries, 1)
+}
+
+func TestRateLimitMiddlewareDisabled(t *testing.T) {
+	limiter := newRateLimiter(-1)
+	require.Nil(t, limiter)
+
+	router := gin.New()
+	router.POST("/login", rateLimitMiddleware(limiter, rateLimitByIP), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	for range 50 {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/login", nil)
+		require.NoError(t, err)
+		router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+	}
+}
+
+func TestLoginRateLimit(t *testing.T) {
+	server, err := NewServer(config.Config{
+		TokenSymmetricKey:   util.RandomString(32),
+		AccessTokenDuration: time.Minute,
+		LoginRateLimit:      2,
+	}, newUnreachableStore(t), nil)
+	require.NoError(t, err)
+
+	login := func(remoteAddr string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		// An invalid body fails validation without reaching the database, but still spends a token
+		request, err := http.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader(`{}`))
+		require.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		request.RemoteAddr = remoteAddr
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	require.Equal(t, http.StatusBadRequest, login("203.0.113.7:1111").Code)
+	require.Equal(t, http.StatusBadRequest, login("203.0.113.7:2222").Code)
+
+	limited := login("203.0.113.7:3333")
+	require.Equal(t, http.StatusTooManyRequests, limited.Code)
+	require.Equal(t, "30", limited.Header().Get("Retry-After"))
+
+	// Another client IP is unaffected
+	require.Equal(t, http.StatusBadRequest, login("198.51.100.4:1111").Code)
+}
+
+func TestVerifyInvitationRateLimitIsSeparateFromLogin(t *testing.T) {
+	server, err := NewServer(config.Config{
+		TokenSymmetricKey:   util.RandomString(32),
+		AccessTokenDuration: time.Minute,
+		LoginRateLimit:      2,
+	}, newUnreachableStore(t), nil)
+	require.NoError(t, err)
+
+	serve := func(method, url string) int {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(method, url, strings.NewReader(`{}`))
+		require.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		request.RemoteAddr = "203.0.113.7:1111"
+		server.router.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+	login := func() int { return serve(http.MethodPost, "/api/v1/auth/login") }
+	verify := func() int { return serve(http.MethodGet, "/api/v1/invitations/verify/some-token") }
+
+	// Checking invitations does not spend login attempts
+	require.NotEqual(t, http.StatusTooManyRequests, verify())
+	require.NotEqual(t, http.StatusTooManyRequests, verify())
+	require.Equal(t, http.StatusTooManyRequests, verify())
+	require.Equal(t, http.StatusBadRequest, login())
+	require.Equal(t, http.StatusBadRequest, login())
+	require.Equal(t, http.StatusTooManyRequests, login())
+}
+
+func TestLoginRateLimitIgnoresSpoofedForwardedFor(t *testing.T) {
+	server, err := NewServer(config.Config{
+		TokenSymmetricKey:   util.RandomString(32),
+		AccessTokenDuration: time.Minute,
+		LoginRateLimit:      2,
+	}, newUnreachableStore(t), nil)
+	require.NoError(t, err)
+
+	login := func(forwardedFor string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader(`{}`))
+		require.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("X-Forwarded-For", forwardedFor)
+		request.RemoteAddr = "203.0.113.7:1111"
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// Rotating the header does not give the client a fresh bucket
+	require.Equal(t, http.StatusBadRequest, login("198.51.100.1").Code)
+	require.Equal(t, http.StatusBadRequest, login("198.51.100.2").Code)
+	require.Equal(t, http.StatusTooManyRequests, login("198.51.100.3").Code)
+}
+
+func TestLoginRateLimitBehindTrustedProxy(t *testing.T) {
+	server, err := NewServer(config.Config{
+		TokenSymmetricKey:   util.RandomString(32),
+		AccessTokenDuration: time.Minute,
+		LoginRateLimit:      1,
+		TrustedProxies:      []string{"10.0.0.0/8"},
+	}, newUnreachableStore(t), nil)
+	require.NoError(t, err)
+
+	login := func(forwardedFor string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader(`{}`))
+		require.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("X-Forwarded-For", forwardedFor)
+		request.RemoteAddr = "10.1.2.3:1111"
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// Behind a configured proxy, each forwarded client gets its own bucket
+	require.Equal(t, http.StatusBadRequest, login("198.51.100.1").Code)
+	require.Equal(t, http.StatusTooManyRequests, login("198.51.100.1").Code)
+	require.Equal(t, http.StatusBadRequest, login("198.51.100.2").Code)
+
+	// A malformed proxy list is refused at startup
+	_, err = NewServer(config.Config{
+		TokenSymmetricKey: util.RandomString(32),
+		TrustedProxies:    []string{"not-an-ip"},
+	}, newUnreachableStore(t), nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,128 @@
+// api/recommendation_cache.go
+
+package api
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Recommendation Cache
+////////////////////////////////////////////////////////////////////////
+
+// defaultRecommendationCacheTTL is how long recommender results are reused when RECOMMENDATION_CACHE_TTL is not set
+const defaultRecommendationCacheTTL = 30 * time.Second
+
+// recommendationCacheKey identifies one recommender query. The skills are part of the key,
+// so a task whose skills changed never gets the results for its old skill set.
+type recommendationCacheKey struct {
+	taskID int64
+	skills string // Sorted skill IDs, e.g. "[3 7 12]"
+	limit  int
+}
+
+type recommendationCacheEntry struct {
+	response  recommenderAPIResponse
+	expiresAt time.Time
+}
+
+// recommendationCache keeps recent recommender responses in memory for a short TTL.
+// A nil cache is valid and disabled: it never hits and ignores writes.
+type recommendationCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time // Replaceable in tests
+	entries map[recommendationCacheKey]recommendationCacheEntry
+}
+
+// newRecommendationCache returns a cache with the given TTL, or nil (disabled) when ttl is not positive
+func newRecommendationCache(ttl time.Duration) *recommendationCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &recommendationCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[recommendationCacheKey]recommendationCacheEntry),
+	}
+}
+
+// newRecommendationCacheKey builds the key for a task, its skill IDs in any order, and a result limit
+func newRecommendationCacheKey(taskID int64, skillIDs []int32, limit int) recommendationCacheKey {
+	sorted := slices.Clone(skillIDs)
+	slices.Sort(sorted)
+	return recommendationCacheKey{
+		taskID: taskID,
+		skills: fmt.Sprint(sorted),
+		limit:  limit,
+	}
+}
+
+// get returns the cached response for key if it has not expired
+func (c *recommendationCache) get(key recommendationCacheKey) (recommenderAPIResponse, bool) {
+	if c == nil {
+		return recommenderAPIResponse{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return recommenderAPIResponse{}, false
+	}
+	if !c.now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return recommenderAPIResponse{}, false
+	}
+	return entry.response, true
+}
+
+// put stores a response for key, first dropping expired entries so the map cannot grow without bound
+func (c *recommendationCache) put(key recommendationCacheKey, response recommenderAPIResponse) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for k, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = recommendationCacheEntry{response: response, expiresAt: now.Add(c.ttl)}
+}
+
+// invalidateTask drops every cached response for a task, e.g. after its description changed
+func (c *recommendationCache) invalidateTask(taskID int64) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k := range c.entries {
+		if k.taskID == taskID {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// clear drops every cached response, e.g. once the recommender retrains its model
+func (c *recommendationCache) clear() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.entries)
+}
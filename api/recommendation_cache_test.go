@@ -0,0 +1,56 @@
+// api/recommendation_cache_test.go
+package api
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecommendationCache(t *testing.T) {
+	now := time.Date(2025, time.March, 4, 10, 0, 0, 0, time.UTC)
+	cache := newRecommendationCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	var response recommenderAPIResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"recommendations":[{"user_id":7,"score":0.9}]}`), &response))
+	key := newRecommendationCacheKey(1, []int32{12, 3, 7}, 5)
+	cache.put(key, response)
+
+	// Skill order does not matter, but the skill set and limit do
+	cached, ok := cache.get(newRecommendationCacheKey(1, []int32{3, 7, 12}, 5))
+	require.True(t, ok)
+	require.Equal(t, response, cached)
+	_, ok = cache.get(newRecommendationCacheKey(1, []int32{3, 7}, 5))
+	require.False(t, ok)
+	_, ok = cache.get(newRecommendationCacheKey(1, []int32{3, 7, 12}, 10))
+	require.False(t, ok)
+
+	// Entries expire after the TTL
+	now = now.Add(time.Minute)
+	_, ok = cache.get(key)
+	require.False(t, ok)
+
+	// Invalidating a task leaves other tasks' entries alone
+	other := newRecommendationCacheKey(2, []int32{3}, 5)
+	cache.put(key, response)
+	cache.put(other, response)
+	cache.invalidateTask(1)
+	_, ok = cache.get(key)
+	require.False(t, ok)
+	_, ok = cache.get(other)
+	require.True(t, ok)
+
+	cache.clear()
+	_, ok = cache.get(other)
+	require.False(t, ok)
+
+	// A non-positive TTL disables caching
+	disabled := newRecommendationCache(-1)
+	require.Nil(t, disabled)
+	disabled.put(key, response)
+	_, ok = disabled.get(key)
+	require.False(t, ok)
+}
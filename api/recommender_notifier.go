@@ -0,0 +1,126 @@
+// api/recommender_notifier.go
+
+package api
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Recommender Model Refresh
+////////////////////////////////////////////////////////////////////////
+
+const (
+	recommenderRefreshDebounce    = 2 * time.Second // Requests within this window share one refresh
+	recommenderRefreshMaxAttempts = 5               // Tries per refresh before it is given up
+	recommenderRefreshBackoff     = 2 * time.Second // Wait before the first retry, doubled for each one after
+)
+
+// recommenderNotifier asks the recommender to refresh its model in the background, retrying
+// with backoff so a brief outage does not lose the update. A burst of requests, such as many
+// users onboarding at once, is coalesced into a single refresh. The worker starts on the first request,
+// is tracked in jobs, and stops, abandoning any refresh in progress, once ctx is done.
+type recommenderNotifier struct {
+	ctx         context.Context
+	jobs        *sync.WaitGroup
+	pending     chan struct{}                   // Holds at most one request; further ones coalesce into it
+	refresh     func(ctx context.Context) error // Sends one refresh call
+	debounce    time.Duration
+	maxAttempts int
+	backoff     time.Duration
+	start       sync.Once
+}
+
+// newRecommenderNotifier returns a notifier with the default debounce and retries that calls refresh until ctx is done
+func newRecommenderNotifier(ctx context.Context, jobs *sync.WaitGroup, refresh func(ctx context.Context) error) *recommenderNotifier {
+	return &recommenderNotifier{
+		ctx:         ctx,
+		jobs:        jobs,
+		pending:     make(chan struct{}, 1),
+		refresh:     refresh,
+		debounce:    recommenderRefreshDebounce,
+		maxAttempts: recommenderRefreshMaxAttempts,
+		backoff:     recommenderRefreshBackoff,
+	}
+}
+
+// request schedules a refresh without blocking. If one is already waiting, this one joins it.
+func (n *recommenderNotifier) request() {
+	if n.ctx.Err() != nil {
+		return
+	}
+	n.start.Do(func() {
+		n.jobs.Add(1)
+		go func() {
+			defer n.jobs.Done()
+			n.run()
+		}()
+	})
+
+	select {
+	case n.pending <- struct{}{}:
+	default:
+	}
+}
+
+// run performs requested refreshes until ctx is done. Requests arriving while a
+// refresh is in flight schedule one more, since the one in flight may predate their change.
+func (n *recommenderNotifier) run() {
+	for {
+		select {
+		case <-n.ctx.Done():
+			return
+		case <-n.pending:
+		}
+		if !sleepContext(n.ctx, n.debounce) {
+			return
+		}
+
+		// Requests made during the debounce window are covered by this refresh
+		select {
+		case <-n.pending:
+		default:
+		}
+
+		n.refreshWithRetry()
+	}
+}
+
+// refreshWithRetry calls refresh, retrying with exponential backoff until it succeeds or runs out of attempts
+func (n *recommenderNotifier) refreshWithRetry() {
+	wait := n.backoff
+	for attempt := 1; ; attempt++ {
+		err := n.refresh(n.ctx)
+		if err == nil {
+			slog.Info("Successfully notified recommender service to refresh its model", "attempt", attempt)
+			return
+		}
+		if attempt == n.maxAttempts {
+			slog.Error("Giving up on recommender model refresh", "attempts", attempt, "error", err)
+			return
+		}
+
+		slog.Warn("Recommender model refresh failed, retrying", "attempt", attempt, "retry_in", wait, "error", err)
+		if !sleepContext(n.ctx, wait) {
+			slog.Warn("Abandoning recommender model refresh on shutdown", "attempts", attempt)
+			return
+		}
+		wait *= 2
+	}
+}
+
+// sleepContext waits for d, returning early with false if ctx is done first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,88 @@
+// api/recommender_notifier_test.go
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifyRecommenderRetries(t *testing.T) {
+	// Arrange: a recommender that fails twice before accepting
+	var calls atomic.Int32
+	recommender := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/admin/refresh-model", r.URL.Path)
+		require.Equal(t, "test-key", r.Header.Get("X-Internal-API-Key"))
+
+		if calls.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(recommender.Close)
+
+	server := newTestServer(t, newUnreachableStore(t))
+	server.config.RecommenderAPIURL = recommender.URL
+	server.config.RecommenderAPIKey = "test-key"
+	server.modelRefresh.debounce = 50 * time.Millisecond
+	server.modelRefresh.backoff = time.Millisecond
+
+	// Act: a burst of onboardings
+	for range 5 {
+		server.notifyRecommender()
+	}
+
+	// Assert: the burst became one refresh, accepted on its third attempt
+	require.Eventually(t, func() bool { return calls.Load() == 3 }, 5*time.Second, 5*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, int32(3), calls.Load())
+}
+
+func TestRecommenderNotifierGivesUp(t *testing.T) {
+	var calls atomic.Int32
+	notifier := newRecommenderNotifier(context.Background(), new(sync.WaitGroup), func(ctx context.Context) error {
+		calls.Add(1)
+		return errors.New("recommender unavailable")
+	})
+	notifier.debounce = time.Millisecond
+	notifier.backoff = time.Millisecond
+	notifier.maxAttempts = 3
+
+	notifier.request()
+
+	require.Eventually(t, func() bool { return calls.Load() == 3 }, 5*time.Second, 5*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, int32(3), calls.Load())
+}
+
+func TestRecommenderNotifierStopsWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int32
+	var jobs sync.WaitGroup
+	notifier := newRecommenderNotifier(ctx, &jobs, func(ctx context.Context) error {
+		calls.Add(1)
+		return errors.New("recommender unavailable")
+	})
+	notifier.debounce = time.Millisecond
+	notifier.backoff = time.Hour
+
+	// The first attempt fails, and the long retry wait is cut short by the cancel
+	notifier.request()
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, 5*time.Second, 5*time.Millisecond)
+	cancel()
+	jobs.Wait()
+
+	// Requests after the cancel are never sent
+	notifier.request()
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, int32(1), calls.Load())
+}
@@ -0,0 +1,235 @@
+// api/render.go
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Content Negotiation for List Endpoints
+////////////////////////////////////////////////////////////////////////
+
+const mimeCSV = "text/csv"
+
+// csvColumn describes one column of a list rendered as CSV
+type csvColumn[T any] struct {
+	header string
+	value  func(T) string
+}
+
+// respondList writes a list in the format the client accepts. JSON is the default and sends
+// body as is, which may wrap the items (e.g. with a total count); "Accept: text/csv" gets
+// a header row followed by one row per item.
+func respondList[T any](ctx *gin.Context, status int, body any, items []T, columns []csvColumn[T]) {
+	if ctx.NegotiateFormat(gin.MIMEJSON, mimeCSV) != mimeCSV {
+		ctx.JSON(status, body)
+		return
+	}
+	respondCSV(ctx, status, items, columns)
+}
+
+// respondListWithETag is respondList for lists that clients poll: the JSON form goes through
+// respondJSONWithETag, so an unchanged page is answered with 304. CSV is a one-off download
+// and is sent without a tag.
+func respondListWithETag[T any](ctx *gin.Context, status int, body any, items []T, columns []csvColumn[T]) {
+	if ctx.NegotiateFormat(gin.MIMEJSON, mimeCSV) != mimeCSV {
+		respondJSONWithETag(ctx, status, body)
+		return
+	}
+	respondCSV(ctx, status, items, columns)
+}
+
+// respondCSV writes a header row followed by one row per item
+func respondCSV[T any](ctx *gin.Context, status int, items []T, columns []csvColumn[T]) {
+	ctx.Header("Content-Type", mimeCSV+"; charset=utf-8")
+	ctx.Status(status)
+
+	writer := csv.NewWriter(ctx.Writer)
+	record := make([]string, len(columns))
+	for i, column := range columns {
+		record[i] = column.header
+	}
+	_ = writer.Write(record)
+
+	for _, item := range items {
+		for i, column := range columns {
+			record[i] = column.value(item)
+		}
+		_ = writer.Write(record)
+	}
+
+	// The status line is already sent, so a failed write can only be logged
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		slog.Error("Failed to write CSV response", "error", err)
+	}
+}
+
+// csvInt formats an integer CSV cell
+func csvInt(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
+
+// csvTimestamp formats a nullable timestamp as RFC 3339, leaving NULL cells empty
+func csvTimestamp(ts pgtype.Timestamp) string {
+	if !ts.Valid {
+		return ""
+	}
+	return ts.Time.Format(time.RFC3339)
+}
+
+////////////////////////////////////////////////////////////////////////
+// Conditional GET
+////////////////////////////////////////////////////////////////////////
+
+// respondJSONWithETag writes body as JSON along with an ETag derived from the encoded bytes.
+// A client that sends the same tag back in If-None-Match gets 304 Not Modified and no body,
+// so polling a resource that has not changed costs a round trip but no payload.
+func respondJSONWithETag(ctx *gin.Context, status int, body any) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	sum := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	ctx.Header("ETag", etag)
+	// Browsers may keep the response but must check back before reusing it
+	ctx.Header("Cache-Control", "private, no-cache")
+
+	if status == http.StatusOK && etagMatches(ctx.GetHeader("If-None-Match"), etag) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+	ctx.Data(status, gin.MIMEJSON+"; charset=utf-8", data)
+}
+
+// etagMatches reports whether an If-None-Match header lists etag. As RFC 9110 asks for GET,
+// the comparison is weak, so W/"x" matches "x"; "*" matches anything.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+////////////////////////////////////////////////////////////////////////
+// Streaming Exports
+////////////////////////////////////////////////////////////////////////
+
+// Rows fetched per query while streaming an export
+const exportBatchSize = 500
+
+type exportRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=json csv"` // Defaults to json
+}
+
+// streamExport writes an export as a downloadable CSV or JSON array. next returns the
+// following batch of items, and an empty batch once exhausted; each batch is written and
+// flushed before the next is fetched, so a large export is never held in memory whole.
+func streamExport[T any](ctx *gin.Context, format, filename string, next func() ([]T, error), columns []csvColumn[T]) {
+	// Fetch the first batch before writing anything so a failure can still be reported
+	batch, err := next()
+	if err != nil {
+		slog.Debug("Error fetching export batch", "filename", filename, "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if format == "" {
+		format = "json"
+	}
+	contentType := gin.MIMEJSON
+	if format == "csv" {
+		contentType = mimeCSV
+	}
+	ctx.Header("Content-Type", contentType+"; charset=utf-8")
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+"."+format))
+	ctx.Status(http.StatusOK)
+
+	var write func([]T) error
+	var finish func() error
+	if format == "csv" {
+		writer := csv.NewWriter(ctx.Writer)
+		record := make([]string, len(columns))
+		for i, column := range columns {
+			record[i] = column.header
+		}
+		_ = writer.Write(record)
+
+		write = func(items []T) error {
+			for _, item := range items {
+				for i, column := range columns {
+					record[i] = column.value(item)
+				}
+				if err := writer.Write(record); err != nil {
+					return err
+				}
+			}
+			writer.Flush()
+			return writer.Error()
+		}
+		finish = func() error {
+			writer.Flush()
+			return writer.Error()
+		}
+	} else {
+		// Items are encoded one by one into a JSON array
+		encoder := json.NewEncoder(ctx.Writer)
+		separator := "["
+		write = func(items []T) error {
+			for _, item := range items {
+				if _, err := io.WriteString(ctx.Writer, separator); err != nil {
+					return err
+				}
+				separator = ","
+				if err := encoder.Encode(item); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		finish = func() error {
+			if separator == "[" {
+				_, err := io.WriteString(ctx.Writer, "[]")
+				return err
+			}
+			_, err := io.WriteString(ctx.Writer, "]")
+			return err
+		}
+	}
+
+	// The status line is already sent, so from here a failure can only be logged
+	for len(batch) > 0 {
+		if err := write(batch); err != nil {
+			slog.Error("Failed to write export", "filename", filename, "error", err)
+			return
+		}
+		ctx.Writer.Flush()
+
+		if batch, err = next(); err != nil {
+			slog.Error("Failed to fetch export batch", "filename", filename, "error", err)
+			return
+		}
+	}
+	if err := finish(); err != nil {
+		slog.Error("Failed to write export", "filename", filename, "error", err)
+	}
+}
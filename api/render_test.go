@@ -0,0 +1,202 @@
+// api/render_test.go
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRespondList(t *testing.T) {
+	type member struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+	members := []member{{ID: 1, Name: "Ada"}, {ID: 2, Name: "Lovelace, Ada"}}
+	columns := []csvColumn[member]{
+		{"id", func(m member) string { return csvInt(m.ID) }},
+		{"name", func(m member) string { return m.Name }},
+	}
+
+	router := gin.New()
+	router.GET("/members", func(ctx *gin.Context) {
+		respondList(ctx, http.StatusOK, members, members, columns)
+	})
+
+	testCases := []struct {
+		name            string
+		accept          string
+		wantContentType string
+		wantBody        string
+	}{
+		{"No Accept header defaults to JSON", "", "application/json; charset=utf-8", `[{"id":1,"name":"Ada"},{"id":2,"name":"Lovelace, Ada"}]`},
+		{"Wildcard gets JSON", "*/*", "application/json; charset=utf-8", `[{"id":1,"name":"Ada"},{"id":2,"name":"Lovelace, Ada"}]`},
+		{"CSV is returned when asked for", "text/csv", "text/csv; charset=utf-8", "id,name\n1,Ada\n2,\"Lovelace, Ada\"\n"},
+		{"Preferred CSV wins over JSON", "text/csv, application/json;q=0.5", "text/csv; charset=utf-8", "id,name\n1,Ada\n2,\"Lovelace, Ada\"\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/members", nil)
+			require.NoError(t, err)
+			if tc.accept != "" {
+				request.Header.Set("Accept", tc.accept)
+			}
+
+			// Act
+			router.ServeHTTP(recorder, request)
+
+			// Assert
+			require.Equal(t, http.StatusOK, recorder.Code)
+			require.Equal(t, tc.wantContentType, recorder.Header().Get("Content-Type"))
+			require.Equal(t, tc.wantBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestStreamExport(t *testing.T) {
+	type member struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+	columns := []csvColumn[member]{
+		{"id", func(m member) string { return csvInt(m.ID) }},
+		{"name", func(m member) string { return m.Name }},
+	}
+	batches := [][]member{{{ID: 1, Name: "Ada"}, {ID: 2, Name: "Lovelace, Ada"}}, {{ID: 3, Name: "Grace"}}}
+
+	router := gin.New()
+	router.GET("/export", func(ctx *gin.Context) {
+		var fetchErr error
+		if ctx.Query("fail") != "" {
+			fetchErr = errors.New("boom")
+		}
+		remaining := batches
+		if ctx.Query("empty") != "" {
+			remaining = nil
+		}
+		next := func() ([]member, error) {
+			if fetchErr != nil || len(remaining) == 0 {
+				return nil, fetchErr
+			}
+			batch := remaining[0]
+			remaining = remaining[1:]
+			return batch, nil
+		}
+		streamExport(ctx, ctx.Query("format"), "members", next, columns)
+	})
+
+	testCases := []struct {
+		name            string
+		query           string
+		wantStatus      int
+		wantContentType string
+		wantBody        string
+	}{
+		{"JSON is the default", "", http.StatusOK, "application/json; charset=utf-8", `[{"id":1,"name":"Ada"}` + "\n" + `,{"id":2,"name":"Lovelace, Ada"}` + "\n" + `,{"id":3,"name":"Grace"}` + "\n]"},
+		{"CSV spans every batch", "?format=csv", http.StatusOK, "text/csv; charset=utf-8", "id,name\n1,Ada\n2,\"Lovelace, Ada\"\n3,Grace\n"},
+		{"Empty JSON export is an empty array", "?format=json&empty=1", http.StatusOK, "application/json; charset=utf-8", "[]"},
+		{"Empty CSV export keeps the header row", "?format=csv&empty=1", http.StatusOK, "text/csv; charset=utf-8", "id,name\n"},
+		{"A failed first batch is reported", "?format=csv&fail=1", http.StatusInternalServerError, "application/json; charset=utf-8", `{"error":"boom"}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/export"+tc.query, nil)
+			require.NoError(t, err)
+
+			// Act
+			router.ServeHTTP(recorder, request)
+
+			// Assert
+			require.Equal(t, tc.wantStatus, recorder.Code)
+			require.Equal(t, tc.wantContentType, recorder.Header().Get("Content-Type"))
+			require.Equal(t, tc.wantBody, recorder.Body.String())
+			if tc.wantStatus == http.StatusOK {
+				require.Contains(t, recorder.Header().Get("Content-Disposition"), "attachment")
+			}
+		})
+	}
+}
+
+func TestRespondJSONWithETag(t *testing.T) {
+	stats := gin.H{"open_tasks": 3}
+	router := gin.New()
+	router.GET("/stats", func(ctx *gin.Context) {
+		respondJSONWithETag(ctx, http.StatusOK, stats)
+	})
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/stats", nil)
+		require.NoError(t, err)
+		if ifNoneMatch != "" {
+			request.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// The first request gets the body and a tag for it
+	first := get("")
+	require.Equal(t, http.StatusOK, first.Code)
+	require.JSONEq(t, `{"open_tasks":3}`, first.Body.String())
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	// Sending the tag back skips the body while nothing changed
+	for _, header := range []string{etag, "W/" + etag, `"other", ` + etag, "*"} {
+		recorder := get(header)
+		require.Equal(t, http.StatusNotModified, recorder.Code, header)
+		require.Empty(t, recorder.Body.String())
+		require.Equal(t, etag, recorder.Header().Get("ETag"))
+	}
+
+	// Once the data changes, the old tag no longer matches
+	stats["open_tasks"] = 4
+	changed := get(etag)
+	require.Equal(t, http.StatusOK, changed.Code)
+	require.JSONEq(t, `{"open_tasks":4}`, changed.Body.String())
+	require.NotEqual(t, etag, changed.Header().Get("ETag"))
+}
+
+func TestRespondListWithETag(t *testing.T) {
+	items := []int64{1, 2}
+	columns := []csvColumn[int64]{{"id", csvInt}}
+	router := gin.New()
+	router.GET("/items", func(ctx *gin.Context) {
+		respondListWithETag(ctx, http.StatusOK, items, items, columns)
+	})
+
+	get := func(accept, ifNoneMatch string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/items", nil)
+		require.NoError(t, err)
+		request.Header.Set("Accept", accept)
+		request.Header.Set("If-None-Match", ifNoneMatch)
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// JSON carries a tag, and sending it back answers 304
+	first := get("application/json", "")
+	require.Equal(t, http.StatusOK, first.Code)
+	require.JSONEq(t, `[1,2]`, first.Body.String())
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	require.Equal(t, http.StatusNotModified, get("application/json", etag).Code)
+
+	// CSV is always sent in full and without a tag
+	csv := get("text/csv", etag)
+	require.Equal(t, http.StatusOK, csv.Code)
+	require.Equal(t, "id\n1\n2\n", csv.Body.String())
+	require.Empty(t, csv.Header().Get("ETag"))
+}
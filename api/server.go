@@ -3,14 +3,29 @@
 package api
 
 import (
+	"cmp"
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/pranav244872/synapse/config"
 	db "github.com/pranav244872/synapse/db/sqlc"
+	"github.com/pranav244872/synapse/mailer"
+	"github.com/pranav244872/synapse/metrics"
 	"github.com/pranav244872/synapse/token"
 	"github.com/pranav244872/synapse/skillz"
+	"github.com/pranav244872/synapse/skillmatch"
+	"github.com/pranav244872/synapse/util"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 ////////////////////////////////////////////////////////////////////////
@@ -19,11 +34,27 @@ import (
 
 // Server defines dependencies for running HTTP API server.
 type Server struct {
-	config          config.Config         // Configuration values from file or environment
-	store           *db.Store              // Database access layer generated by sqlc
-	tokenMaker      *token.JWTMaker       // JWT token generator/verifier
-	skillzProcessor skillz.Processor      // Used to process skills (e.g., from resumes)
-	router          *gin.Engine           // Gin engine that holds all routes and middleware
+	config            config.Config        // Configuration values from file or environment
+	store             *db.Store            // Database access layer generated by sqlc
+	tokenMaker        token.Maker          // Access token generator/verifier, JWT or PASETO
+	skillzProcessor   skillz.Processor     // Used to process skills (e.g., from resumes)
+	skillMatcher      *skillmatch.Service  // Shared skill matching between users, skills and tasks
+	router            *gin.Engine          // Gin engine that holds all routes and middleware
+	dependencies      []dependencyCheck    // Configuration status of the LLM and recommender, reported by readyz
+	metricsRegistry   *prometheus.Registry // Collectors served on /metrics; nil unless METRICS_ENABLED is set
+	emailer           mailer.Emailer       // Sends invitation and password reset emails; a no-op unless SMTP_HOST is set
+	recommendations   *recommendationCache // Recent recommender responses per task; nil when disabled
+	idempotencyKeys   *idempotencyCache    // Responses replayed for repeated Idempotency-Keys; nil when disabled
+	webhooks          *webhookDispatcher   // Delivers team events to registered webhooks in the background
+	loginLimiter      *rateLimiter         // Login attempts and invitation checks per client IP; nil when disabled
+	llmLimiter        *rateLimiter         // Requests per user to LLM-backed endpoints; nil when disabled
+	recommenderClient *http.Client         // Shared by every recommender call so its connections are reused
+	modelRefresh      *recommenderNotifier // Debounces and retries recommender model refreshes in the background
+	httpServer        *http.Server         // Serves the router; Shutdown drains it
+	background        context.Context      // Done once Shutdown is called, stopping the background jobs
+	stopBackground    context.CancelFunc
+	stopDelivery      context.CancelFunc // Abandons the webhook deliveries still queued at the shutdown deadline
+	backgroundJobs    sync.WaitGroup     // Jobs, workers and emails still running in the background, awaited by Shutdown
 }
 
 ////////////////////////////////////////////////////////////////////////
@@ -33,53 +64,155 @@ type Server struct {
 // NewServer creates and returns a new Server instance.
 // Sets up token handling, routing, DB access, and skill processor.
 func NewServer(config config.Config, store *db.Store, skillzProcessor skillz.Processor) (*Server, error) {
-	// Create the JWT token maker using a symmetric key
-	tokenMaker, err := token.NewJWTMaker(config.TokenSymmetricKey)
+	// Create the token maker of the configured type using a symmetric key
+	tokenMaker, err := token.NewMaker(config.TokenType, config.TokenSymmetricKey)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
 	}
 
+	// Self-check the external dependency settings so gaps surface at startup, not on first use
+	dependencies := checkDependencyConfig(config)
+	if !logDependencyChecks(dependencies) && config.RequireDependencies {
+		return nil, fmt.Errorf("incomplete dependency configuration: %+v", dependencies)
+	}
+
+	// An invalid default would only surface as a database error on the first task creation
+	if config.DefaultTaskPriority != "" && !slices.Contains(taskPriorities, db.TaskPriority(config.DefaultTaskPriority)) {
+		return nil, fmt.Errorf("invalid DEFAULT_TASK_PRIORITY %q, must be one of %v", config.DefaultTaskPriority, taskPriorities)
+	}
+
+	// A negative TTL would create invitations that have already lapsed; zero uses db.InvitationDuration
+	if config.InvitationTTL < 0 {
+		return nil, fmt.Errorf("invalid INVITATION_TTL %s, must be positive or 0 for the default", config.InvitationTTL)
+	}
+
+	// Background jobs and workers run until Shutdown cancels this context
+	background, stopBackground := context.WithCancel(context.Background())
+	// Queued webhook deliveries are still sent after that, until Shutdown's deadline cancels this one
+	delivery, stopDelivery := context.WithCancel(context.Background())
+
 	// Construct the server with all dependencies
 	server := &Server{
-		config:          config,
-		store:           store,
-		tokenMaker:      tokenMaker,
-		skillzProcessor: skillzProcessor,
+		config:            config,
+		store:             store,
+		tokenMaker:        tokenMaker,
+		skillzProcessor:   skillzProcessor,
+		skillMatcher:      skillmatch.NewService(store),
+		dependencies:      dependencies,
+		emailer:           newEmailer(config),
+		recommendations:   newRecommendationCache(cmp.Or(config.RecommendationCacheTTL, defaultRecommendationCacheTTL)),
+		idempotencyKeys:   newIdempotencyCache(cmp.Or(config.IdempotencyKeyTTL, defaultIdempotencyKeyTTL)),
+		loginLimiter:      newRateLimiter(cmp.Or(config.LoginRateLimit, defaultLoginRateLimit)),
+		llmLimiter:        newRateLimiter(cmp.Or(config.LLMRateLimit, defaultLLMRateLimit)),
+		recommenderClient: util.NewHTTPClient(cmp.Or(config.RecommenderTimeout, defaultRecommenderTimeout)),
+		background:        background,
+		stopBackground:    stopBackground,
+		stopDelivery:      stopDelivery,
+	}
+	server.webhooks = newWebhookDispatcher(background, delivery, &server.backgroundJobs)
+	server.modelRefresh = newRecommenderNotifier(background, &server.backgroundJobs, server.refreshRecommenderModel)
+
+	// Each server gets its own registry, so building several servers never registers a collector twice
+	if config.MetricsEnabled {
+		server.metricsRegistry = prometheus.NewRegistry()
+		if err := metrics.Register(server.metricsRegistry); err != nil {
+			return nil, fmt.Errorf("cannot register metrics: %w", err)
+		}
 	}
 
 	// Register routes and middleware
-	server.setupRouter()
+	if err := server.setupRouter(); err != nil {
+		return nil, err
+	}
+	server.httpServer = &http.Server{Handler: server.router}
 
 	return server, nil
 }
 
+// newEmailer sends through SMTP when a relay is configured, and only logs otherwise
+func newEmailer(config config.Config) mailer.Emailer {
+	if config.SMTPHost == "" {
+		slog.Warn("SMTP_HOST is not set; invitation emails will not be sent")
+		return mailer.NoopEmailer{}
+	}
+	return mailer.NewSMTPEmailer(config.SMTPHost, config.SMTPPort, config.SMTPFromAddress, config.SMTPUsername, config.SMTPPassword)
+}
+
 ////////////////////////////////////////////////////////////////////////
 // Route Setup - Public and Protected Endpoints
 ////////////////////////////////////////////////////////////////////////
 
+// jsonContentTypeExemptRoutes lists route patterns that accept non-JSON bodies,
+// such as file or CSV uploads, and are skipped by jsonContentTypeMiddleware.
+var jsonContentTypeExemptRoutes = []string{}
+
 // setupRouter defines the HTTP routes and applies middleware
-func (server *Server) setupRouter() {
+func (server *Server) setupRouter() error {
 	router := gin.Default()
 
+	// Only believe X-Forwarded-For from configured proxies. gin trusts every proxy by default,
+	// which would let clients pick their own IP and dodge the per-IP rate limits
+	if err := router.SetTrustedProxies(server.config.TrustedProxies); err != nil {
+		return fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+	}
+
+	// Let *gin.Context report the request context's Done/Err so a client disconnect
+	// cancels the LLM, recommender and database calls that receive it
+	router.ContextWithFallback = true
+
+	// Structured access log first, so its latency covers the rest of the middleware chain
+	if server.config.AccessLogEnabled {
+		router.Use(accessLogMiddleware(slog.Default(), server.config.AccessLogSampleRate, "/healthz", "/readyz", "/metrics"))
+	}
+
+	// Request metrics, and the endpoint Prometheus scrapes them from
+	if server.metricsRegistry != nil {
+		router.Use(metricsMiddleware())
+		router.GET("/metrics", gin.WrapH(promhttp.HandlerFor(server.metricsRegistry, promhttp.HandlerOpts{})))
+	}
+
 	// Apply CORS Middleware first
 	// This ensures CORS headers are set for all responses, including errors
 	router.Use(server.CORSMiddleware())
 
+	// Liveness and readiness probes, outside the versioned API. Handlers are in `api/health_handler.go`
+	router.GET("/healthz", server.healthz)
+	router.GET("/readyz", server.readyz)
+
+	// API description and a browsable UI for it. Handlers are in `api/docs_handler.go`
+	router.GET("/openapi.json", server.openAPISpec)
+	router.GET("/docs", server.apiDocs)
+
 	apiV1 := router.Group("/api/v1")
 
+	// Every body-carrying request must be JSON unless its route is listed in jsonContentTypeExemptRoutes
+	apiV1.Use(jsonContentTypeMiddleware(jsonContentTypeExemptRoutes...))
+
 	// == Public Authentication Routes ==
 	// Handlers are in `api/auth_handler.go`
-	apiV1.POST("/auth/login", server.loginUser)
-	apiV1.POST("/invitations/accept", server.acceptInvitation)
+	// Login and invitation checks are limited per client IP against password and token guessing,
+	// each in its own bucket; accepting an invitation calls the LLM
+	apiV1.POST("/auth/login", rateLimitMiddleware(server.loginLimiter, rateLimitByIP), server.loginUser)
+	apiV1.POST("/invitations/accept", rateLimitMiddleware(server.llmLimiter, rateLimitByIP), server.acceptInvitation)
+	apiV1.GET("/invitations/verify/:token", rateLimitMiddleware(server.loginLimiter, rateLimitVerifyByIP), server.verifyInvitation)
+	apiV1.POST("/auth/forgot-password", server.forgotPassword)
+	apiV1.POST("/auth/reset-password", server.resetPassword)
+	apiV1.POST("/auth/refresh", server.refreshToken)
+	apiV1.POST("/auth/logout", server.logoutUser)
+
+	// Token introspection for the caller, so it needs a valid token of its own
+	apiV1.GET("/auth/whoami", authMiddleware(server.tokenMaker), server.whoami)
 
 	// == Admin Routes ==
 	// Protected by auth and admin middleware. Handlers are in `api/admin_handler.go`.
 	adminRoutes := apiV1.Group("/admin")
-	adminRoutes.Use(authMiddleware(server.tokenMaker), adminAuthMiddleware())
+	adminRoutes.Use(authMiddleware(server.tokenMaker), requireRole(db.UserRoleAdmin))
 	{
         // Team Management
         adminRoutes.POST("/teams", server.createTeamAdmin)
         adminRoutes.GET("/teams", server.listTeams)
+        adminRoutes.GET("/teams/:id/delete-impact", server.getTeamDeletionImpact)
+        adminRoutes.DELETE("/teams/:id", server.deleteTeamAdmin)
 
 		// User Management
 		adminRoutes.GET("/users", server.listUsersAdmin)
@@ -87,65 +220,121 @@ func (server *Server) setupRouter() {
 		adminRoutes.PATCH("/users/:id", server.updateUserAdmin)
 		adminRoutes.DELETE("/users/:id", server.deleteUserAdmin)
 		adminRoutes.GET("/users/:id/delete-impact", server.getUserDeletionImpact)
+		adminRoutes.POST("/users/:id/transfer", server.transferUserAdmin)
+		adminRoutes.POST("/users/:id/reassign-tasks", server.reassignTasksAdmin)
+		adminRoutes.POST("/users/:id/skills", server.addUserSkillAdmin)
+		adminRoutes.PATCH("/users/:id/skills/:skill_id", server.updateUserSkillAdmin)
+		adminRoutes.DELETE("/users/:id/skills/:skill_id", server.removeUserSkillAdmin)
 
         // Invitation Management
         adminRoutes.POST("/invitations", server.createManagerInvitation)
+		adminRoutes.POST("/invitations/bulk", server.bulkCreateManagerInvitations)
         adminRoutes.GET("/invitations", server.listInvitations)
+		adminRoutes.GET("/invitations/stats", server.getInvitationStats)
+		adminRoutes.GET("/invitations/:id", server.getInvitation)
         adminRoutes.DELETE("/invitations/:id", server.deleteInvitation)
+        adminRoutes.POST("/invitations/:id/resend", server.resendInvitation)
+        adminRoutes.POST("/invitations/expire", server.expireInvitations)
 
         // Skill Management
 		adminRoutes.POST("/skills", server.createSkillAdmin)
         adminRoutes.GET("/skills", server.listSkillsAdmin)
+		adminRoutes.GET("/skills/pending-count", server.getPendingSkillCount)
+		adminRoutes.GET("/skills/unverified/ranked", server.listRankedUnverifiedSkills)
         adminRoutes.PATCH("/skills/:id", server.updateSkillVerification)
         adminRoutes.DELETE("/skills/:id", server.deleteSkill)
+		adminRoutes.POST("/skills/:id/merge", server.mergeSkill)
         adminRoutes.POST("/skill-aliases", server.createSkillAlias)
+		adminRoutes.POST("/skills/aliases/bulk", server.bulkCreateSkillAliases)
+		adminRoutes.GET("/skills/aliases", server.listAllSkillAliases)
+		adminRoutes.DELETE("/skills/aliases/*alias_name", server.deleteSkillAlias)
 		adminRoutes.GET("/skills/:id/aliases", server.listSkillAliases)
+
+		// Webhook Management
+		adminRoutes.POST("/webhooks", server.createWebhook)
+		adminRoutes.GET("/webhooks", server.listWebhooks)
+		adminRoutes.PATCH("/webhooks/:id", server.updateWebhook)
+		adminRoutes.DELETE("/webhooks/:id", server.deleteWebhook)
+
+		// Maintenance
+		adminRoutes.POST("/maintenance/auto-verify-skills", server.autoVerifySkills)
+		adminRoutes.POST("/tasks/escalate", server.escalateStaleTasks)
 	}
 
 	// == Manager Routes ==
 	// Protected by auth and manager middleware. Handlers are in `api/manager_handler.go`.
 	managerRoutes := apiV1.Group("/manager")
-	managerRoutes.Use(authMiddleware(server.tokenMaker), managerAuthMiddleware())
+	managerRoutes.Use(authMiddleware(server.tokenMaker), requireRole(db.UserRoleManager))
 	{
 		// Dashboard and Team Management
 		managerRoutes.GET("/dashboard/stats", server.getDashboardStats)
+		managerRoutes.GET("/dashboard/trends", server.getDashboardTrends)
+		managerRoutes.GET("/dashboard/skill-gaps", server.getSkillGaps)
 		managerRoutes.GET("/team/members", server.getTeamMembers)
+		managerRoutes.GET("/team/members/export", server.exportTeamMembers)
+		managerRoutes.GET("/team/members/:id/history", server.getTeamMemberHistory)
+		managerRoutes.GET("/team/qualified-engineers", server.listQualifiedEngineers)
+		managerRoutes.GET("/overview", server.getTeamOverview)
+		managerRoutes.PUT("/team/settings", server.updateTeamSettings)
 
 		// Invitation Management
 		managerRoutes.POST("/invitations", server.inviteEngineer)
 		managerRoutes.GET("/invitations", server.listSentInvitations)
+		managerRoutes.GET("/invitations/:id", server.getInvitation)
 		managerRoutes.DELETE("/invitations/:id", server.cancelInvitation)
+		managerRoutes.POST("/invitations/:id/resend", server.resendInvitation)
 
 		// Project Management
-		managerRoutes.POST("/projects", server.createProject)
+		managerRoutes.POST("/projects", server.idempotencyMiddleware(), server.createProject)
 		managerRoutes.GET("/projects", server.listProjects)
+		managerRoutes.GET("/board", server.getProjectBoard)
 		managerRoutes.GET("/projects/:id", server.getProject)
+		managerRoutes.GET("/projects/:id/stats", server.getProjectStats)
 		managerRoutes.PUT("/projects/:id", server.updateProject)
 		managerRoutes.POST("/projects/:id/archive", server.archiveProject)
+		managerRoutes.POST("/projects/:id/unarchive", server.unarchiveProject)
 		managerRoutes.GET("/projects/:id/tasks", server.listProjectTasks)
+		managerRoutes.GET("/projects/:id/tasks/export", server.exportProjectTasks)
+		managerRoutes.POST("/projects/:id/tasks/bulk", rateLimitMiddleware(server.llmLimiter, rateLimitByUser), server.bulkCreateTasks)
 
 		// Task Management
-		managerRoutes.POST("/tasks", server.createTask)
+		managerRoutes.POST("/tasks", server.idempotencyMiddleware(), rateLimitMiddleware(server.llmLimiter, rateLimitByUser), server.createTask)
+		managerRoutes.GET("/tasks/search", server.searchTasks)
 		managerRoutes.PATCH("/tasks/:id", server.updateTask)
 		managerRoutes.POST("/tasks/:id/assign", server.assignTask)
+		managerRoutes.POST("/tasks/:id/reassign", server.reassignTask)
+		managerRoutes.POST("/tasks/:id/archive", server.archiveTask)
+		managerRoutes.POST("/tasks/:id/restore", server.restoreTask)
+		managerRoutes.GET("/overdue-tasks", server.listOverdueTasks)
 
 		// Engineer Recommendations
 		managerRoutes.POST("/recommendations", server.getRecommendations)
+
+		// Skill Insights
+		managerRoutes.GET("/skills/:id/related", server.getRelatedSkills)
 	}
 
 	// == Engineer Routes ==
 	// Protected by auth and engineer middleware. Handlers are in `api/engineer_handler.go`.
 	engineerRoutes := apiV1.Group("/engineer")
-	engineerRoutes.Use(authMiddleware(server.tokenMaker), engineerAuthMiddleware())
+	engineerRoutes.Use(authMiddleware(server.tokenMaker), requireRole(db.UserRoleEngineer))
 	{
 		// Dashboard and Task Management
 		engineerRoutes.GET("/current-task", server.getCurrentTask)
+		engineerRoutes.GET("/tasks/matching", server.listMatchingTasks)
 		engineerRoutes.GET("/tasks/:id", server.getTaskDetails)
+		engineerRoutes.POST("/tasks/:id/claim", server.claimTask)
 		engineerRoutes.POST("/tasks/:id/complete", server.completeTask)
+		engineerRoutes.POST("/tasks/:id/decline", server.declineTask)
+		engineerRoutes.POST("/tasks/:id/pause", server.pauseTask)
+		engineerRoutes.POST("/tasks/:id/resume", server.resumeTask)
 
 		// Project and History Views
 		engineerRoutes.GET("/projects/:id/tasks", server.listProjectTasksForEngineer)
 		engineerRoutes.GET("/tasks/history", server.getTaskHistory)
+
+		// Team Contact
+		engineerRoutes.GET("/team/manager", server.getTeamManager)
 	}
 
     // == General Authenticated User Routes ==
@@ -154,18 +343,148 @@ func (server *Server) setupRouter() {
     userRoutes.Use(authMiddleware(server.tokenMaker))
     {
         userRoutes.GET("/me", server.getUserProfile)
+        userRoutes.POST("/me/password", server.changePassword)
+        userRoutes.PATCH("/me/availability", requireRole(db.UserRoleEngineer), server.updateMyAvailability)
+
+        // Skill profile, which only engineers have
+        userRoutes.POST("/me/skills", requireRole(db.UserRoleEngineer), server.addMySkill)
+        userRoutes.PATCH("/me/skills/:skill_id", requireRole(db.UserRoleEngineer), server.updateMySkill)
+        userRoutes.DELETE("/me/skills/:skill_id", requireRole(db.UserRoleEngineer), server.removeMySkill)
+        // Calls the LLM twice, so it shares the per-user LLM limit
+        userRoutes.POST("/me/resume", requireRole(db.UserRoleEngineer), rateLimitMiddleware(server.llmLimiter, rateLimitByUser), server.reprocessMyResume)
     }
 
+	// == Skill Search Routes ==
+	// Open to every authenticated role. Handlers are in `api/skill_handler.go`.
+	skillRoutes := apiV1.Group("/skills")
+	skillRoutes.Use(authMiddleware(server.tokenMaker))
+	{
+		skillRoutes.GET("/search", server.searchSkills)
+	}
+
+	// == Task Comment Routes ==
+	// Open to the task's team, managers and engineers alike. Handlers are in `api/comment_handler.go`.
+	commentRoutes := apiV1.Group("")
+	commentRoutes.Use(authMiddleware(server.tokenMaker), requireRole(db.UserRoleManager, db.UserRoleEngineer))
+	{
+		commentRoutes.POST("/tasks/:id/comments", server.createTaskComment)
+		commentRoutes.GET("/tasks/:id/comments", server.listTaskComments)
+		commentRoutes.DELETE("/comments/:id", server.deleteTaskComment)
+	}
+
 	server.router = router
+	return nil
 }
 
 ////////////////////////////////////////////////////////////////////////
 // Server Start - Launches the HTTP Server
 ////////////////////////////////////////////////////////////////////////
 
-// Start runs the server on the specified address (e.g. ":8080")
+// Start runs the server on the specified address (e.g. ":8080"). It blocks until the server
+// fails or Shutdown is called, in which case it returns nil.
 func (server *Server) Start(address string) error {
-	return server.router.Run(address) // This blocks and listens for requests
+	// Background jobs only run for a server that is actually serving
+	if interval := cmp.Or(server.config.InvitationExpiryInterval, defaultInvitationExpiryInterval); interval > 0 {
+		server.backgroundJobs.Add(1)
+		go func() {
+			defer server.backgroundJobs.Done()
+			server.runInvitationExpiry(server.background, interval)
+		}()
+	}
+	if interval := cmp.Or(server.config.TaskEscalationInterval, defaultTaskEscalationInterval); interval > 0 {
+		server.backgroundJobs.Add(1)
+		go func() {
+			defer server.backgroundJobs.Done()
+			server.runTaskEscalation(server.background, interval)
+		}()
+	}
+
+	server.httpServer.Addr = address
+	err := server.httpServer.ListenAndServe() // This blocks and listens for requests
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Shutdown stops accepting connections and waits for in-flight requests to finish, then stops
+// the background jobs and waits for them, so the pool can be closed afterwards. Queued webhook
+// deliveries are still sent until ctx ends. If ctx ends before the requests finish, the
+// remaining connections are left open and ctx's error is returned.
+func (server *Server) Shutdown(ctx context.Context) error {
+	err := server.httpServer.Shutdown(ctx)
+
+	// Requests still running after a timeout may queue more work, but it will not be started
+	server.stopBackground()
+	stop := context.AfterFunc(ctx, server.stopDelivery)
+	defer stop()
+	server.backgroundJobs.Wait()
+	server.stopDelivery()
+
+	return err
+}
+
+////////////////////////////////////////////////////////////////////////
+// Background Jobs
+////////////////////////////////////////////////////////////////////////
+
+// defaultInvitationExpiryInterval is how often invitations are expired when INVITATION_EXPIRY_INTERVAL is not set
+const defaultInvitationExpiryInterval = time.Hour
+
+// runInvitationExpiry marks stale pending invitations as expired once at startup and then every interval,
+// until ctx is done. Failures are logged and retried on the next tick.
+func (server *Server) runInvitationExpiry(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		expired, err := server.store.ExpirePendingInvitations(ctx)
+		if err != nil {
+			slog.Error("Failed to expire pending invitations", "error", err)
+		} else if expired > 0 {
+			slog.Info("Expired stale pending invitations", "count", expired)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// defaultTaskEscalationInterval is how often stale tasks are escalated when TASK_ESCALATION_INTERVAL is not set
+const defaultTaskEscalationInterval = time.Hour
+
+// runTaskEscalation raises the priority of open tasks nobody has picked up once at startup and then every
+// interval, until ctx is done. Failures are logged and retried on the next tick.
+func (server *Server) runTaskEscalation(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := server.store.EscalateStaleTasksTx(ctx, server.taskEscalationParams(pgtype.Int8{}))
+		if err != nil {
+			slog.Error("Failed to escalate stale tasks", "error", err)
+		} else if len(result.Tasks) > 0 {
+			slog.Info("Escalated stale tasks", "count", len(result.Tasks))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// taskEscalationParams applies the configured escalation thresholds to a run started by actorID
+func (server *Server) taskEscalationParams(actorID pgtype.Int8) db.EscalateStaleTasksTxParams {
+	return db.EscalateStaleTasksTxParams{
+		ActorID:     actorID,
+		MediumAfter: server.config.TaskEscalateMediumAfter,
+		HighAfter:   server.config.TaskEscalateHighAfter,
+	}
 }
 
 ////////////////////////////////////////////////////////////////////////
@@ -177,3 +496,39 @@ func (server *Server) Start(address string) error {
 func errorResponse(err error) gin.H {
 	return gin.H{"error": err.Error()}
 }
+
+// statusClientClosedRequest is nginx's non-standard 499, recorded for requests the client gave up on
+const statusClientClosedRequest = 499
+
+// abortIfCanceled stops the handler without writing a body once the client has disconnected.
+// The request is recorded as 499 so access logs and metrics show the cancellation.
+// Call it on error paths of long-running work, before mapping the error to a status code.
+func abortIfCanceled(ctx *gin.Context) bool {
+	if ctx.Request.Context().Err() == nil {
+		return false
+	}
+	slog.Debug("Request canceled by client, skipping response", "method", ctx.Request.Method, "path", ctx.FullPath())
+	ctx.AbortWithStatus(statusClientClosedRequest)
+	return true
+}
+
+// errOperationTimedOut is answered with 504 when an LLM call or a transaction runs past its deadline
+var errOperationTimedOut = errors.New("the operation took too long, please try again")
+
+// abortIfTimedOut answers 504 when err comes from a deadline running out, such as the one set by
+// llmContext or the store's transaction timeout, and reports whether it did.
+// Call it after abortIfCanceled, which covers the client disconnecting instead.
+func abortIfTimedOut(ctx *gin.Context, err error) bool {
+	if !errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	slog.Warn("Operation timed out", "method", ctx.Request.Method, "path", ctx.FullPath(), "error", err)
+	ctx.AbortWithStatusJSON(http.StatusGatewayTimeout, errorResponse(errOperationTimedOut))
+	return true
+}
+
+// llmContext bounds one LLM-backed skill extraction step by LLM_TIMEOUT. The HTTP client has the
+// same limit per call, but this also covers the processing around it and reports a deadline error.
+func (server *Server) llmContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, cmp.Or(server.config.LLMTimeout, skillz.DefaultTimeout))
+}
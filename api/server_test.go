@@ -0,0 +1,127 @@
+// api/server_test.go
+package api
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranav244872/synapse/config"
+	"github.com/pranav244872/synapse/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewServerInvitationTTL(t *testing.T) {
+	cfg := config.Config{TokenSymmetricKey: util.RandomString(32)}
+
+	// A negative TTL would hand out invitations that are already expired
+	cfg.InvitationTTL = -time.Hour
+	_, err := NewServer(cfg, nil, nil)
+	require.ErrorContains(t, err, "INVITATION_TTL")
+
+	// Zero falls back to the store's default
+	cfg.InvitationTTL = 0
+	server, err := NewServer(cfg, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, server.Shutdown(context.Background()))
+}
+
+func TestServerShutdown(t *testing.T) {
+	server := newTestServer(t, newUnreachableStore(t))
+	server.config.InvitationExpiryInterval = -1
+	server.config.TaskEscalationInterval = -1
+
+	// A request that is still running when the shutdown starts
+	entered := make(chan struct{})
+	server.router.GET("/slow", func(ctx *gin.Context) {
+		close(entered)
+		time.Sleep(200 * time.Millisecond)
+		ctx.String(http.StatusOK, "done")
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	started := make(chan error, 1)
+	go func() { started <- server.Start(address) }()
+
+	type result struct {
+		status int
+		body   string
+		err    error
+	}
+	responses := make(chan result, 1)
+	go func() {
+		var resp *http.Response
+		var err error
+		// Retry until the listener is up
+		for range 50 {
+			resp, err = http.Get("http://" + address + "/slow")
+			if err == nil {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if err != nil {
+			responses <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		responses <- result{status: resp.StatusCode, body: string(body), err: err}
+	}()
+
+	select {
+	case <-entered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request never reached the server")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, server.Shutdown(ctx))
+
+	// The in-flight request finished, and Start returned without an error
+	response := <-responses
+	require.NoError(t, response.err)
+	require.Equal(t, http.StatusOK, response.status)
+	require.Equal(t, "done", response.body)
+	require.NoError(t, <-started)
+
+	// Background workers have been told to stop
+	require.Error(t, server.background.Err())
+}
+
+func TestAbortIfCanceled(t *testing.T) {
+	router := gin.New()
+	router.GET("/work", func(ctx *gin.Context) {
+		if abortIfCanceled(ctx) {
+			return
+		}
+		ctx.String(http.StatusOK, "done")
+	})
+
+	serve := func(ctx context.Context) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, "/work", nil)
+		require.NoError(t, err)
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	require.Equal(t, http.StatusOK, serve(context.Background()).Code)
+
+	// A client that went away is recorded as 499, with no body
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	recorder := serve(canceled)
+	require.Equal(t, statusClientClosedRequest, recorder.Code)
+	require.Empty(t, recorder.Body.String())
+}
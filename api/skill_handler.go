@@ -0,0 +1,59 @@
+// api/skill_handler.go
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/pranav244872/synapse/db/sqlc"
+	"github.com/pranav244872/synapse/util"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Skill Search (All Authenticated Roles)
+////////////////////////////////////////////////////////////////////////
+
+// defaultSkillSearchLimit is how many suggestions a search returns when no limit is given
+const defaultSkillSearchLimit = 10
+
+// searchSkillsRequest defines the query of the GET /skills/search endpoint
+type searchSkillsRequest struct {
+	Query string `form:"q" binding:"required,max=100"`
+	Limit int32  `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+// searchSkills suggests verified skills for typeahead. A skill matches when its name or one of
+// its aliases contains the query, so "golang" surfaces "Go". Skills whose name starts with the
+// query come first; the database ranks them before applying the limit.
+func (server *Server) searchSkills(ctx *gin.Context) {
+	slog.Debug("Starting searchSkills handler")
+
+	var req searchSkillsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultSkillSearchLimit
+	}
+	query := util.EscapeLike(req.Query)
+
+	skills, err := server.store.SearchSkillSuggestions(ctx, db.SearchSkillSuggestionsParams{
+		IsVerified: true,
+		Search:     "%" + query + "%",
+		Prefix:     query + "%",
+		Limit:      limit,
+	})
+	if err != nil {
+		slog.Debug("Error searching skills", "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if skills == nil {
+		skills = []db.Skill{}
+	}
+
+	ctx.JSON(http.StatusOK, skills)
+}
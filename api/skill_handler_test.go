@@ -0,0 +1,49 @@
+// api/skill_handler_test.go
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	db "github.com/pranav244872/synapse/db/sqlc"
+	"github.com/pranav244872/synapse/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchSkillsValidation(t *testing.T) {
+	server := newTestServer(t, newUnreachableStore(t))
+
+	for _, query := range []string{"", "q=go&limit=-1", "q=go&limit=500"} {
+		recorder := newAuthorizedRequest(t, server, http.MethodGet, "/api/v1/skills/search?"+query, nil, 1, db.UserRoleEngineer, 1)
+		require.Equal(t, http.StatusBadRequest, recorder.Code, query)
+	}
+}
+
+func TestSearchSkills(t *testing.T) {
+	store := newTestStore(t)
+	server := newTestServer(t, store)
+	ctx := context.Background()
+
+	// A verified skill known by an alias, and an unverified one with a matching name
+	term := util.RandomString(12)
+	verified, err := store.CreateSkill(ctx, db.CreateSkillParams{SkillName: util.RandomName(), IsVerified: true})
+	require.NoError(t, err)
+	_, err = store.CreateSkillAlias(ctx, db.CreateSkillAliasParams{AliasName: term + "-alias", SkillID: verified.ID})
+	require.NoError(t, err)
+	_, err = store.CreateSkill(ctx, db.CreateSkillParams{SkillName: term + "-unverified", IsVerified: false})
+	require.NoError(t, err)
+
+	// Every role may search; only the verified skill is suggested, found through its alias
+	for _, role := range []db.UserRole{db.UserRoleEngineer, db.UserRoleManager, db.UserRoleAdmin} {
+		recorder := newAuthorizedRequest(t, server, http.MethodGet, "/api/v1/skills/search?q="+url.QueryEscape(term), nil, 1, role, 1)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var skills []db.Skill
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &skills))
+		require.Len(t, skills, 1)
+		require.Equal(t, verified.ID, skills[0].ID)
+	}
+}
@@ -2,39 +2,44 @@
 package api
 
 import (
-	"database/sql"
 	"errors"
+	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 	db "github.com/pranav244872/synapse/db/sqlc"
+	"github.com/pranav244872/synapse/util"
 )
 
 // userProfileResponse defines the structure for the /users/me endpoint response.
+// TeamID and TeamName are null for users who are not on a team, such as admins.
 type userProfileResponse struct {
-	Name  string       `json:"name"`
-	Email string       `json:"email"`
-	Role  db.UserRole `json:"role"`
+	ID           int64                         `json:"id"`
+	Name         string                        `json:"name"`
+	Email        string                        `json:"email"`
+	Role         db.UserRole                   `json:"role"`
+	TeamID       *int64                        `json:"team_id"`
+	TeamName     *string                       `json:"team_name"`
+	Availability db.AvailabilityStatus         `json:"availability"`
+	Skills       []db.GetUserSkillsForAdminRow `json:"skills"`
 }
 
 // getUserProfile handles the GET /users/me endpoint.
-// It uses the user ID from the JWT payload to fetch the user's profile.
+// It uses the user ID from the JWT payload to fetch the user's profile, team and skills,
+// so engineers can see their own record without the admin-only user endpoint.
 func (server *Server) getUserProfile(ctx *gin.Context) {
-	// 1. Get the payload from the context (set by the authMiddleware).
-	authPayload, err := getAuthorizationPayload(ctx)
+	// 1. Get the user ID from the token payload (set by the authMiddleware).
+	userID, err := getUserIDFromPayload(ctx)
 	if err != nil {
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
 
-	// 2. Extract the user ID from the payload.
-	// The user ID is stored as a float64 in JWT claims, so we need to cast it.
-	userID := int64(authPayload["user_id"].(float64))
-
-	// 3. Fetch the user's data from the database using their ID.
-	user, err := server.store.GetUser(ctx, userID)
+	// 2. Fetch the user's data and team name from the database using their ID.
+	user, err := server.store.GetUserWithTeamAndSkills(ctx, userID)
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if errors.Is(err, pgx.ErrNoRows) {
 			// This could happen if the user was deleted after the token was issued.
 			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("user not found")))
 			return
@@ -43,13 +48,371 @@ func (server *Server) getUserProfile(ctx *gin.Context) {
 		return
 	}
 
+	// 3. Fetch the user's skills and proficiency levels.
+	skills, err := server.store.GetUserSkillsForAdmin(ctx, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if skills == nil {
+		skills = []db.GetUserSkillsForAdminRow{}
+	}
+
 	// 4. Create the response object with the required fields.
 	rsp := userProfileResponse{
-		Name:  user.Name.String, // pgtype.Text needs to be converted to string
-		Email: user.Email,
-		Role:  user.Role,
+		ID:           user.ID,
+		Name:         user.Name.String, // pgtype.Text needs to be converted to string
+		Email:        user.Email,
+		Role:         user.Role,
+		Availability: user.Availability,
+		Skills:       skills,
+	}
+	if user.TeamID.Valid {
+		rsp.TeamID = &user.TeamID.Int64
+	}
+	if user.TeamName.Valid {
+		rsp.TeamName = &user.TeamName.String
 	}
 
 	// 5. Send the response.
 	ctx.JSON(http.StatusOK, rsp)
 }
+
+// changePasswordRequest defines the JSON body for the POST /users/me/password endpoint.
+// The new password follows the same minimum length as the login endpoint.
+type changePasswordRequest struct {
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
+
+// changePassword handles the POST /users/me/password endpoint.
+// The caller must prove they know the current password before it is replaced.
+// All of the user's refresh tokens are revoked with it. Neither password is ever logged.
+func (server *Server) changePassword(ctx *gin.Context) {
+	// 1. Bind the request; validation errors name the field but never echo its value.
+	var req changePasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// 2. Identify the caller from the JWT payload.
+	userID, err := getUserIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	// 3. Load the user to get their current password hash.
+	user, err := server.store.GetUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("user not found")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// 4. Verify the old password.
+	if err := util.CheckPasswordHash(req.OldPassword, user.PasswordHash); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("old password is incorrect")))
+		return
+	}
+
+	// 5. Hash and store the new password, ending every existing session.
+	hashedPassword, err := util.HashPassword(req.NewPassword)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = server.store.ChangePasswordTx(ctx, db.UpdateUserPasswordParams{
+		ID:           user.ID,
+		PasswordHash: hashedPassword,
+	})
+	if err != nil {
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
+		slog.Error("Failed to update password for user", "user_id", user.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	slog.Info("User changed their password", "user_id", user.ID)
+	ctx.JSON(http.StatusOK, gin.H{"message": "password changed"})
+}
+
+// updateAvailabilityRequest defines the JSON body for the PATCH /users/me/availability endpoint.
+// Busy is left out on purpose: it follows task assignment and is never set by hand.
+type updateAvailabilityRequest struct {
+	Availability string `json:"availability" binding:"required,oneof=available on_leave"`
+}
+
+// updateMyAvailability handles the PATCH /users/me/availability endpoint for engineers.
+// Going on leave keeps new work away from the engineer; it is refused while they have a task
+// in progress, since that task would otherwise sit with someone who is away.
+func (server *Server) updateMyAvailability(ctx *gin.Context) {
+	// 1. Bind the requested availability.
+	var req updateAvailabilityRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// 2. Identify the caller from the JWT payload.
+	userID, err := getUserIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	// 3. Switch the availability, guarded so a busy engineer stays busy.
+	user, err := server.store.UpdateAvailabilityIfNotBusy(ctx, db.UpdateAvailabilityIfNotBusyParams{
+		ID:           userID,
+		Availability: db.AvailabilityStatus(req.Availability),
+	})
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		// No row was updated: either the user is gone or they are busy
+		if _, err := server.store.GetUser(ctx, userID); errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("user not found")))
+			return
+		}
+		ctx.JSON(http.StatusConflict, errorResponse(errors.New("finish or pause your current task first")))
+		return
+	}
+
+	slog.Info("User changed their availability", "user_id", user.ID, "availability", user.Availability)
+	ctx.JSON(http.StatusOK, gin.H{"availability": user.Availability})
+}
+
+////////////////////////////////////////////////////////////////////////
+// User Skills
+////////////////////////////////////////////////////////////////////////
+
+// userSkillURI identifies one of a user's skills in the route
+type userSkillURI struct {
+	SkillID int64 `uri:"skill_id" binding:"required,min=1"`
+}
+
+// addUserSkillRequest defines the JSON body for adding an existing skill to a user
+type addUserSkillRequest struct {
+	SkillID     int64  `json:"skill_id" binding:"required,min=1"`
+	Proficiency string `json:"proficiency" binding:"required,oneof=beginner intermediate expert"`
+}
+
+// updateUserSkillRequest defines the JSON body for correcting a user's proficiency in a skill
+type updateUserSkillRequest struct {
+	Proficiency string `json:"proficiency" binding:"required,oneof=beginner intermediate expert"`
+}
+
+// errUserSkillNotFound is returned when the user does not have the skill in the route
+var errUserSkillNotFound = errors.New("user does not have this skill")
+
+// addMySkill handles the POST /users/me/skills endpoint for engineers
+func (server *Server) addMySkill(ctx *gin.Context) {
+	userID, err := getUserIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	server.addSkillForUser(ctx, userID)
+}
+
+// updateMySkill handles the PATCH /users/me/skills/:skill_id endpoint for engineers
+func (server *Server) updateMySkill(ctx *gin.Context) {
+	userID, err := getUserIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	server.updateSkillForUser(ctx, userID)
+}
+
+// removeMySkill handles the DELETE /users/me/skills/:skill_id endpoint for engineers
+func (server *Server) removeMySkill(ctx *gin.Context) {
+	userID, err := getUserIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	server.removeSkillForUser(ctx, userID)
+}
+
+// addSkillForUser links an existing skill to the user and marks it manual.
+// Shared by the engineer's own endpoint and the admin one.
+func (server *Server) addSkillForUser(ctx *gin.Context, userID int64) {
+	var req addUserSkillRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	userSkill, err := server.store.AddUserSkillTx(ctx, db.AddUserSkillTxParams{
+		UserID:      userID,
+		SkillID:     req.SkillID,
+		Proficiency: db.ProficiencyLevel(req.Proficiency),
+	})
+	if err != nil {
+		if abortIfTimedOut(ctx, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, db.ErrSkillNotFound):
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		case errors.Is(err, db.ErrUserSkillExists):
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+		default:
+			slog.Error("Failed to add skill to user", "user_id", userID, "skill_id", req.SkillID, "error", err)
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	slog.Info("Skill added to user", "user_id", userID, "skill_id", req.SkillID, "proficiency", req.Proficiency)
+	server.notifyRecommender()
+	ctx.JSON(http.StatusCreated, userSkill)
+}
+
+// updateSkillForUser changes the user's proficiency in a skill they already have
+func (server *Server) updateSkillForUser(ctx *gin.Context, userID int64) {
+	var uriReq userSkillURI
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var req updateUserSkillRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	userSkill, err := server.store.UpdateUserSkillProficiency(ctx, db.UpdateUserSkillProficiencyParams{
+		UserID:      userID,
+		SkillID:     uriReq.SkillID,
+		Proficiency: db.ProficiencyLevel(req.Proficiency),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errUserSkillNotFound))
+			return
+		}
+		slog.Error("Failed to update user skill", "user_id", userID, "skill_id", uriReq.SkillID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	slog.Info("User skill proficiency updated", "user_id", userID, "skill_id", uriReq.SkillID, "proficiency", req.Proficiency)
+	server.notifyRecommender()
+	ctx.JSON(http.StatusOK, userSkill)
+}
+
+// removeSkillForUser removes a skill from the user's profile
+func (server *Server) removeSkillForUser(ctx *gin.Context, userID int64) {
+	var uriReq userSkillURI
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	removed, err := server.store.RemoveSkillFromUser(ctx, db.RemoveSkillFromUserParams{
+		UserID:  userID,
+		SkillID: uriReq.SkillID,
+	})
+	if err != nil {
+		slog.Error("Failed to remove user skill", "user_id", userID, "skill_id", uriReq.SkillID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if removed == 0 {
+		ctx.JSON(http.StatusNotFound, errorResponse(errUserSkillNotFound))
+		return
+	}
+
+	slog.Info("Skill removed from user", "user_id", userID, "skill_id", uriReq.SkillID)
+	server.notifyRecommender()
+	ctx.Status(http.StatusNoContent)
+}
+
+// reprocessResumeRequest carries an engineer's updated resume
+type reprocessResumeRequest struct {
+	ResumeText string `json:"resume_text" binding:"required"`
+}
+
+// reprocessResumeResponse lists the skills the updated resume added, raised or removed
+type reprocessResumeResponse struct {
+	Added    []db.UserSkill `json:"added"`
+	Upgraded []db.UserSkill `json:"upgraded"`
+	Removed  []int64        `json:"removed"` // IDs of extracted skills the resume no longer shows
+}
+
+// reprocessMyResume handles the POST /users/me/resume endpoint for engineers.
+// The resume goes through the same extraction as at signup, and the results are merged into
+// the engineer's skills: new ones are added and higher levels raised, and extracted skills the resume
+// no longer shows are removed. Nothing is lowered, and skills added or endorsed by hand are never removed.
+func (server *Server) reprocessMyResume(ctx *gin.Context) {
+	userID, err := getUserIDFromPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	var req reprocessResumeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if err := server.checkResumeLength(req.ResumeText); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	skillsWithProficiency, err := server.extractResumeSkills(ctx, req.ResumeText)
+	if err != nil {
+		if abortIfCanceled(ctx) || abortIfTimedOut(ctx, err) {
+			return
+		}
+		slog.Error("Failed to extract skills from resume", "user_id", userID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not process resume skills"})
+		return
+	}
+
+	result, err := server.store.ReprocessUserSkillsTx(ctx, db.ReprocessUserSkillsTxParams{
+		UserID:                userID,
+		SkillsWithProficiency: skillsWithProficiency,
+	})
+	if err != nil {
+		if abortIfCanceled(ctx) || abortIfTimedOut(ctx, err) {
+			return
+		}
+		slog.Error("Failed to merge resume skills", "user_id", userID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := reprocessResumeResponse{
+		Added:    result.Added,
+		Upgraded: result.Upgraded,
+		Removed:  result.Removed,
+	}
+	if rsp.Added == nil {
+		rsp.Added = []db.UserSkill{}
+	}
+	if rsp.Upgraded == nil {
+		rsp.Upgraded = []db.UserSkill{}
+	}
+	if rsp.Removed == nil {
+		rsp.Removed = []int64{}
+	}
+
+	slog.Info("Resume reprocessed", "user_id", userID, "added", len(rsp.Added), "upgraded", len(rsp.Upgraded), "removed", len(rsp.Removed))
+	if len(rsp.Added) > 0 || len(rsp.Upgraded) > 0 || len(rsp.Removed) > 0 {
+		server.notifyRecommender()
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
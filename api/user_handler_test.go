@@ -0,0 +1,324 @@
+// api/user_handler_test.go
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pranav244872/synapse/db/sqlc"
+	"github.com/pranav244872/synapse/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetUserProfile(t *testing.T) {
+	store := newTestStore(t)
+	server := newTestServer(t, store)
+	ctx := context.Background()
+
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	engineer := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+	skill, err := store.CreateSkill(ctx, db.CreateSkillParams{SkillName: util.RandomName()})
+	require.NoError(t, err)
+	_, err = store.AddSkillToUser(ctx, db.AddSkillToUserParams{UserID: engineer.ID, SkillID: skill.ID, Proficiency: db.ProficiencyLevelExpert})
+	require.NoError(t, err)
+	admin := createTestUser(t, store, db.UserRoleAdmin, 0)
+
+	getProfile := func(userID int64, role db.UserRole, teamID int64) userProfileResponse {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/api/v1/users/me", nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server, userID, role, teamID)
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var rsp userProfileResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+		return rsp
+	}
+
+	// The engineer sees their team by name and their skills
+	rsp := getProfile(engineer.ID, db.UserRoleEngineer, team.ID)
+	require.Equal(t, engineer.ID, rsp.ID)
+	require.Equal(t, engineer.Email, rsp.Email)
+	require.Equal(t, db.UserRoleEngineer, rsp.Role)
+	require.NotNil(t, rsp.TeamID)
+	require.Equal(t, team.ID, *rsp.TeamID)
+	require.NotNil(t, rsp.TeamName)
+	require.Equal(t, team.TeamName, *rsp.TeamName)
+	require.Equal(t, db.AvailabilityStatusAvailable, rsp.Availability)
+	require.Len(t, rsp.Skills, 1)
+	require.Equal(t, skill.SkillName, rsp.Skills[0].SkillName)
+	require.Equal(t, db.ProficiencyLevelExpert, rsp.Skills[0].Proficiency)
+
+	// An admin has no team and no skills
+	rsp = getProfile(admin.ID, db.UserRoleAdmin, 0)
+	require.Nil(t, rsp.TeamID)
+	require.Nil(t, rsp.TeamName)
+	require.NotNil(t, rsp.Skills)
+	require.Empty(t, rsp.Skills)
+}
+
+func TestUpdateMyAvailabilityValidation(t *testing.T) {
+	server := newTestServer(t, newUnreachableStore(t))
+
+	// Busy follows task assignment and cannot be set by hand
+	recorder := newAuthorizedRequest(t, server, http.MethodPatch, "/api/v1/users/me/availability", gin.H{"availability": "busy"}, 1, db.UserRoleEngineer, 1)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	// Only engineers have an availability to manage
+	recorder = newAuthorizedRequest(t, server, http.MethodPatch, "/api/v1/users/me/availability", gin.H{"availability": "on_leave"}, 1, db.UserRoleManager, 1)
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+}
+
+func TestUpdateMyAvailability(t *testing.T) {
+	store := newTestStore(t)
+	server := newTestServer(t, store)
+	ctx := context.Background()
+
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	engineer := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+	manager := createTestUser(t, store, db.UserRoleManager, team.ID)
+	project, err := store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: team.ID})
+	require.NoError(t, err)
+	task, err := store.CreateTask(ctx, db.CreateTaskParams{
+		ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+		Title:     util.RandomName(),
+		Status:    db.TaskStatusOpen,
+		Priority:  db.TaskPriorityMedium,
+	})
+	require.NoError(t, err)
+
+	assign := func() *httptest.ResponseRecorder {
+		data, err := json.Marshal(gin.H{"user_id": engineer.ID})
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/manager/tasks/%d/assign", task.ID), bytes.NewReader(data))
+		require.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		addAuthorization(t, request, server, manager.ID, db.UserRoleManager, team.ID)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// An engineer on leave cannot be given a task
+	recorder := newAuthorizedRequest(t, server, http.MethodPatch, "/api/v1/users/me/availability", gin.H{"availability": "on_leave"}, engineer.ID, db.UserRoleEngineer, team.ID)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "on_leave")
+	require.Equal(t, http.StatusConflict, assign().Code)
+
+	// Once back, they can; while busy they cannot go on leave
+	recorder = newAuthorizedRequest(t, server, http.MethodPatch, "/api/v1/users/me/availability", gin.H{"availability": "available"}, engineer.ID, db.UserRoleEngineer, team.ID)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, http.StatusOK, assign().Code)
+
+	recorder = newAuthorizedRequest(t, server, http.MethodPatch, "/api/v1/users/me/availability", gin.H{"availability": "on_leave"}, engineer.ID, db.UserRoleEngineer, team.ID)
+	require.Equal(t, http.StatusConflict, recorder.Code)
+	user, err := store.GetUser(ctx, engineer.ID)
+	require.NoError(t, err)
+	require.Equal(t, db.AvailabilityStatusBusy, user.Availability)
+}
+
+func TestChangePasswordValidation(t *testing.T) {
+	server := newTestServer(t, newUnreachableStore(t))
+
+	recorder := newAuthorizedRequest(t, server, http.MethodPost, "/api/v1/users/me/password", gin.H{"old_password": "old-secret", "new_password": "short"}, 1, db.UserRoleEngineer, 0)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = newAuthorizedRequest(t, server, http.MethodPost, "/api/v1/users/me/password", gin.H{"new_password": "new-secret"}, 1, db.UserRoleEngineer, 0)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestChangePassword(t *testing.T) {
+	store := newTestStore(t)
+	server := newTestServer(t, store)
+
+	hashedPassword, err := util.HashPassword("old-secret")
+	require.NoError(t, err)
+	user := createTestUser(t, store, db.UserRoleEngineer, 0)
+	err = store.UpdateUserPassword(context.Background(), db.UpdateUserPasswordParams{ID: user.ID, PasswordHash: hashedPassword})
+	require.NoError(t, err)
+	session, err := store.IssueRefreshToken(context.Background(), db.IssueRefreshTokenParams{UserID: user.ID, Duration: time.Hour})
+	require.NoError(t, err)
+
+	// A wrong old password is refused and changes nothing
+	recorder := newAuthorizedRequest(t, server, http.MethodPost, "/api/v1/users/me/password", gin.H{"old_password": "wrong-secret", "new_password": "new-secret"}, user.ID, db.UserRoleEngineer, 0)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	// The right one rotates the password
+	recorder = newAuthorizedRequest(t, server, http.MethodPost, "/api/v1/users/me/password", gin.H{"old_password": "old-secret", "new_password": "new-secret"}, user.ID, db.UserRoleEngineer, 0)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	updatedUser, err := store.GetUser(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.NoError(t, util.CheckPasswordHash("new-secret", updatedUser.PasswordHash))
+	require.Error(t, util.CheckPasswordHash("old-secret", updatedUser.PasswordHash))
+
+	// Refresh tokens issued before the change no longer work
+	data, err := json.Marshal(gin.H{"refresh_token": session.Token})
+	require.NoError(t, err)
+	recorder = httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/api/v1/auth/refresh", bytes.NewReader(data))
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", "application/json")
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestUserSkillValidation(t *testing.T) {
+	server := newTestServer(t, newUnreachableStore(t))
+
+	// Only engineers have a skill profile of their own
+	recorder := newAuthorizedRequest(t, server, http.MethodPost, "/api/v1/users/me/skills", gin.H{"skill_id": 1, "proficiency": "expert"}, 1, db.UserRoleManager, 0)
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+
+	recorder = newAuthorizedRequest(t, server, http.MethodPost, "/api/v1/users/me/skills", gin.H{"skill_id": 1, "proficiency": "guru"}, 1, db.UserRoleEngineer, 0)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = newAuthorizedRequest(t, server, http.MethodPatch, "/api/v1/users/me/skills/0", gin.H{"proficiency": "expert"}, 1, db.UserRoleEngineer, 0)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = newAuthorizedRequest(t, server, http.MethodPatch, "/api/v1/admin/users/abc/skills/1", gin.H{"proficiency": "expert"}, 1, db.UserRoleAdmin, 0)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestUserSkillLifecycle(t *testing.T) {
+	store := newTestStore(t)
+	server := newTestServer(t, store)
+	ctx := context.Background()
+
+	engineer := createTestUser(t, store, db.UserRoleEngineer, 0)
+	admin := createTestUser(t, store, db.UserRoleAdmin, 0)
+	skill, err := store.CreateSkill(ctx, db.CreateSkillParams{SkillName: "Skill " + util.RandomString(8)})
+	require.NoError(t, err)
+	mySkill := fmt.Sprintf("/api/v1/users/me/skills/%d", skill.ID)
+
+	// Adding a skill links it as manual
+	recorder := newAuthorizedRequest(t, server, http.MethodPost, "/api/v1/users/me/skills", gin.H{"skill_id": skill.ID, "proficiency": "beginner"}, engineer.ID, db.UserRoleEngineer, 0)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+	var userSkill db.UserSkill
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &userSkill))
+	require.Equal(t, db.ProficiencyLevelBeginner, userSkill.Proficiency)
+	require.True(t, userSkill.IsManual)
+
+	// The same skill cannot be added twice, and unknown skills are not created
+	recorder = newAuthorizedRequest(t, server, http.MethodPost, "/api/v1/users/me/skills", gin.H{"skill_id": skill.ID, "proficiency": "expert"}, engineer.ID, db.UserRoleEngineer, 0)
+	require.Equal(t, http.StatusConflict, recorder.Code)
+	recorder = newAuthorizedRequest(t, server, http.MethodPost, "/api/v1/users/me/skills", gin.H{"skill_id": math.MaxInt32, "proficiency": "expert"}, engineer.ID, db.UserRoleEngineer, 0)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+
+	// The engineer records their growth
+	recorder = newAuthorizedRequest(t, server, http.MethodPatch, mySkill, gin.H{"proficiency": "intermediate"}, engineer.ID, db.UserRoleEngineer, 0)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &userSkill))
+	require.Equal(t, db.ProficiencyLevelIntermediate, userSkill.Proficiency)
+
+	// An admin corrects it
+	adminSkill := fmt.Sprintf("/api/v1/admin/users/%d/skills/%d", engineer.ID, skill.ID)
+	recorder = newAuthorizedRequest(t, server, http.MethodPatch, adminSkill, gin.H{"proficiency": "expert"}, admin.ID, db.UserRoleAdmin, 0)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &userSkill))
+	require.Equal(t, db.ProficiencyLevelExpert, userSkill.Proficiency)
+
+	// Removing the skill works once; after that it is gone
+	recorder = newAuthorizedRequest(t, server, http.MethodDelete, adminSkill, nil, admin.ID, db.UserRoleAdmin, 0)
+	require.Equal(t, http.StatusNoContent, recorder.Code)
+	recorder = newAuthorizedRequest(t, server, http.MethodDelete, mySkill, nil, engineer.ID, db.UserRoleEngineer, 0)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+	recorder = newAuthorizedRequest(t, server, http.MethodPatch, mySkill, gin.H{"proficiency": "expert"}, engineer.ID, db.UserRoleEngineer, 0)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+
+	// Admin requests for a missing user are refused
+	recorder = newAuthorizedRequest(t, server, http.MethodPost, fmt.Sprintf("/api/v1/admin/users/%d/skills", math.MaxInt32), gin.H{"skill_id": skill.ID, "proficiency": "expert"}, admin.ID, db.UserRoleAdmin, 0)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestReprocessMyResumeValidation(t *testing.T) {
+	server := newTestServer(t, newUnreachableStore(t))
+	server.skillzProcessor = &mockSkillzProcessor{unusedBy: t}
+	server.config.MaxResumeLength = 10
+
+	recorder := newAuthorizedRequest(t, server, http.MethodPost, "/api/v1/users/me/resume", gin.H{"resume_text": "Go"}, 1, db.UserRoleManager, 0)
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+
+	recorder = newAuthorizedRequest(t, server, http.MethodPost, "/api/v1/users/me/resume", gin.H{}, 1, db.UserRoleEngineer, 0)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = newAuthorizedRequest(t, server, http.MethodPost, "/api/v1/users/me/resume", gin.H{"resume_text": "Go, PostgreSQL and Kubernetes"}, 1, db.UserRoleEngineer, 0)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestReprocessMyResume(t *testing.T) {
+	store := newTestStore(t)
+	server := newTestServer(t, store)
+	ctx := context.Background()
+
+	engineer := createTestUser(t, store, db.UserRoleEngineer, 0)
+	known, err := store.CreateSkill(ctx, db.CreateSkillParams{SkillName: "Skill " + util.RandomString(8)})
+	require.NoError(t, err)
+	_, err = store.AddSkillToUser(ctx, db.AddSkillToUserParams{UserID: engineer.ID, SkillID: known.ID, Proficiency: db.ProficiencyLevelBeginner})
+	require.NoError(t, err)
+	newSkill := "Skill " + util.RandomString(8)
+
+	// reprocess extracts exactly the skills in proficiencies
+	reprocess := func(t *testing.T, proficiencies map[string]string) reprocessResumeResponse {
+		skills := make([]string, 0, len(proficiencies))
+		for skill := range proficiencies {
+			skills = append(skills, skill)
+		}
+		server.skillzProcessor = &mockSkillzProcessor{
+			skills:        skills,
+			proficiencies: proficiencies,
+		}
+		recorder := newAuthorizedRequest(t, server, http.MethodPost, "/api/v1/users/me/resume", gin.H{"resume_text": "Go and more"}, engineer.ID, db.UserRoleEngineer, 0)
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+		var rsp reprocessResumeResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+		return rsp
+	}
+
+	// The known skill is raised and the new one added
+	rsp := reprocess(t, map[string]string{known.SkillName: "expert", newSkill: "intermediate"})
+	require.Len(t, rsp.Added, 1)
+	require.Equal(t, db.ProficiencyLevelIntermediate, rsp.Added[0].Proficiency)
+	require.Len(t, rsp.Upgraded, 1)
+	require.Equal(t, known.ID, rsp.Upgraded[0].SkillID)
+	require.Equal(t, db.ProficiencyLevelExpert, rsp.Upgraded[0].Proficiency)
+
+	// A resume rating both lower changes nothing
+	rsp = reprocess(t, map[string]string{known.SkillName: "beginner", newSkill: "beginner"})
+	require.Empty(t, rsp.Added)
+	require.Empty(t, rsp.Upgraded)
+	require.Empty(t, rsp.Removed)
+
+	skills, err := store.ListUserSkills(ctx, engineer.ID)
+	require.NoError(t, err)
+	require.Len(t, skills, 2)
+	for _, skill := range skills {
+		require.NotEqual(t, db.ProficiencyLevelBeginner, skill.Proficiency)
+	}
+
+	// A resume that no longer shows the new skill removes it
+	addedID := skills[0].SkillID
+	if addedID == known.ID {
+		addedID = skills[1].SkillID
+	}
+	rsp = reprocess(t, map[string]string{known.SkillName: "expert"})
+	require.Equal(t, []int64{addedID}, rsp.Removed)
+
+	skills, err = store.ListUserSkills(ctx, engineer.ID)
+	require.NoError(t, err)
+	require.Len(t, skills, 1)
+	require.Equal(t, known.ID, skills[0].SkillID)
+}
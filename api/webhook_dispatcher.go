@@ -0,0 +1,251 @@
+// api/webhook_dispatcher.go
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	db "github.com/pranav244872/synapse/db/sqlc"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Webhook Delivery
+////////////////////////////////////////////////////////////////////////
+
+// Events webhooks can subscribe to
+const (
+	webhookEventTaskCompleted   = "task.completed"
+	webhookEventProjectArchived = "project.archived"
+)
+
+const (
+	webhookSignatureHeader = "X-Synapse-Signature" // "sha256=" and the hex HMAC-SHA256 of the body
+	webhookEventHeader     = "X-Synapse-Event"
+
+	webhookQueueSize    = 256             // Deliveries waiting beyond this are dropped
+	webhookWorkers      = 4               // Deliveries sent at the same time
+	webhookMaxAttempts  = 4               // Tries per delivery before it is given up
+	webhookRetryBackoff = 2 * time.Second // Wait before the first retry, doubled for each one after
+	webhookTimeout      = 10 * time.Second
+)
+
+// webhookPayload is the JSON body POSTed for every event
+type webhookPayload struct {
+	Event      string    `json:"event"`
+	TeamID     int64     `json:"team_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+// webhookDelivery is one signed event on its way to one webhook
+type webhookDelivery struct {
+	webhookID int64
+	url       string
+	secret    string
+	event     string
+	body      []byte
+}
+
+// webhookDispatcher delivers webhooks in the background from a bounded queue, so a slow or
+// unreachable endpoint never holds up an API response. Workers start on the first delivery and
+// are tracked in jobs. Once ctx is done they send what is still queued and stop; deliveryCtx
+// bounds every send, so ending it abandons the rest.
+type webhookDispatcher struct {
+	ctx         context.Context
+	deliveryCtx context.Context
+	jobs        *sync.WaitGroup
+	queue       chan webhookDelivery
+	client      *http.Client
+	workers     int
+	maxAttempts int
+	backoff     time.Duration
+	start       sync.Once
+}
+
+// newWebhookDispatcher returns a dispatcher with the default queue size, workers and retries that
+// takes deliveries until ctx is done and sends them until deliveryCtx is done
+func newWebhookDispatcher(ctx, deliveryCtx context.Context, jobs *sync.WaitGroup) *webhookDispatcher {
+	return &webhookDispatcher{
+		ctx:         ctx,
+		deliveryCtx: deliveryCtx,
+		jobs:        jobs,
+		queue:       make(chan webhookDelivery, webhookQueueSize),
+		client:      &http.Client{Timeout: webhookTimeout},
+		workers:     webhookWorkers,
+		maxAttempts: webhookMaxAttempts,
+		backoff:     webhookRetryBackoff,
+	}
+}
+
+// enqueue hands a delivery to the workers without blocking. It reports false, and the
+// delivery is dropped, when the queue is full or the dispatcher is shutting down.
+func (d *webhookDispatcher) enqueue(delivery webhookDelivery) bool {
+	if d.ctx.Err() != nil {
+		slog.Warn("Shutting down, dropping webhook delivery", "webhook_id", delivery.webhookID, "event", delivery.event)
+		return false
+	}
+	d.start.Do(func() {
+		d.jobs.Add(d.workers)
+		for range d.workers {
+			go func() {
+				defer d.jobs.Done()
+				d.run()
+			}()
+		}
+	})
+
+	select {
+	case d.queue <- delivery:
+		return true
+	default:
+		slog.Warn("Webhook queue is full, dropping delivery", "webhook_id", delivery.webhookID, "event", delivery.event)
+		return false
+	}
+}
+
+// run delivers queued webhooks until ctx is done, then drains the queue
+func (d *webhookDispatcher) run() {
+	for {
+		select {
+		case <-d.ctx.Done():
+			d.drain()
+			return
+		case delivery := <-d.queue:
+			d.deliver(delivery)
+		}
+	}
+}
+
+// drain sends the deliveries still queued at shutdown, giving up on the rest once deliveryCtx is done
+func (d *webhookDispatcher) drain() {
+	for {
+		if d.deliveryCtx.Err() != nil {
+			if dropped := len(d.queue); dropped > 0 {
+				slog.Warn("Shutdown deadline reached, dropping queued webhook deliveries", "count", dropped)
+			}
+			return
+		}
+		select {
+		case delivery := <-d.queue:
+			d.deliver(delivery)
+		default:
+			return
+		}
+	}
+}
+
+// deliver POSTs a delivery, retrying with exponential backoff until it is accepted or runs out of attempts
+func (d *webhookDispatcher) deliver(delivery webhookDelivery) {
+	wait := d.backoff
+	for attempt := 1; ; attempt++ {
+		err := d.post(delivery)
+		if err == nil {
+			slog.Info("Delivered webhook", "webhook_id", delivery.webhookID, "event", delivery.event, "attempt", attempt)
+			return
+		}
+		if attempt == d.maxAttempts {
+			slog.Error("Giving up on webhook delivery", "webhook_id", delivery.webhookID, "event", delivery.event, "attempts", attempt, "error", err)
+			return
+		}
+
+		slog.Warn("Webhook delivery failed, retrying", "webhook_id", delivery.webhookID, "event", delivery.event, "attempt", attempt, "retry_in", wait, "error", err)
+		if !sleepContext(d.deliveryCtx, wait) {
+			slog.Warn("Abandoning webhook delivery on shutdown", "webhook_id", delivery.webhookID, "event", delivery.event, "attempts", attempt)
+			return
+		}
+		wait *= 2
+	}
+}
+
+// post sends a delivery once; any 2xx answer counts as accepted
+func (d *webhookDispatcher) post(delivery webhookDelivery) error {
+	req, err := http.NewRequestWithContext(d.deliveryCtx, http.MethodPost, delivery.url, bytes.NewReader(delivery.body))
+	if err != nil {
+		return fmt.Errorf("cannot create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookEventHeader, delivery.event)
+	req.Header.Set(webhookSignatureHeader, signWebhookPayload(delivery.secret, delivery.body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// signWebhookPayload returns the X-Synapse-Signature value for a body: receivers recompute the
+// HMAC-SHA256 with their secret and compare it in constant time to check the sender
+func signWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// fireTaskCompleted raises task.completed for a task that has just moved to done. Every path that
+// completes a task calls it once its transaction has committed, so subscribers see each completion.
+func (server *Server) fireTaskCompleted(ctx context.Context, task db.Task) {
+	if !task.ProjectID.Valid {
+		return
+	}
+	project, err := server.store.GetProject(context.WithoutCancel(ctx), task.ProjectID.Int64)
+	if err != nil {
+		slog.Error("Failed to get project of completed task for webhooks", "task_id", task.ID, "error", err)
+		return
+	}
+	server.fireWebhook(ctx, project.TeamID, webhookEventTaskCompleted, task)
+}
+
+// fireWebhook queues an event for each of the team's active webhooks subscribed to it.
+// Like notifyRecommender it is fire-and-forget: the action that raised the event has already
+// succeeded, so failures are only logged.
+func (server *Server) fireWebhook(ctx context.Context, teamID int64, event string, data any) {
+	// The lookup runs after the change is committed and must not be cut short by the client leaving
+	webhooks, err := server.store.ListActiveWebhooksForEvent(context.WithoutCancel(ctx), db.ListActiveWebhooksForEventParams{
+		TeamID:    teamID,
+		EventType: event,
+	})
+	if err != nil {
+		slog.Error("Failed to look up webhooks", "team_id", teamID, "event", event, "error", err)
+		return
+	}
+	if len(webhooks) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(webhookPayload{
+		Event:      event,
+		TeamID:     teamID,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	})
+	if err != nil {
+		slog.Error("Failed to encode webhook payload", "event", event, "error", err)
+		return
+	}
+
+	for _, webhook := range webhooks {
+		server.webhooks.enqueue(webhookDelivery{
+			webhookID: webhook.ID,
+			url:       webhook.Url,
+			secret:    webhook.Secret,
+			event:     event,
+			body:      body,
+		})
+	}
+}
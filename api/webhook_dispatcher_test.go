@@ -0,0 +1,275 @@
+// api/webhook_dispatcher_test.go
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pranav244872/synapse/db/sqlc"
+	"github.com/pranav244872/synapse/util"
+	"github.com/stretchr/testify/require"
+)
+
+// receivedWebhook is one request seen by a test webhook endpoint
+type receivedWebhook struct {
+	event     string
+	signature string
+	body      []byte
+}
+
+// newWebhookReceiver starts an endpoint that answers the first failures requests with 500
+// and sends every request it accepts on the returned channel
+func newWebhookReceiver(t *testing.T, failures int32) (*httptest.Server, <-chan receivedWebhook) {
+	received := make(chan receivedWebhook, 16)
+	var calls atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		if calls.Add(1) <= failures {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		received <- receivedWebhook{
+			event:     r.Header.Get(webhookEventHeader),
+			signature: r.Header.Get(webhookSignatureHeader),
+			body:      body,
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(receiver.Close)
+	return receiver, received
+}
+
+func waitForWebhook(t *testing.T, received <-chan receivedWebhook) receivedWebhook {
+	select {
+	case webhook := <-received:
+		return webhook
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not delivered")
+		return receivedWebhook{}
+	}
+}
+
+func TestWebhookDispatcher(t *testing.T) {
+	t.Run("Delivers a signed payload after retrying", func(t *testing.T) {
+		// Arrange: an endpoint that fails twice before accepting
+		receiver, received := newWebhookReceiver(t, 2)
+		dispatcher := newWebhookDispatcher(context.Background(), context.Background(), new(sync.WaitGroup))
+		dispatcher.backoff = time.Millisecond
+
+		body := []byte(`{"event":"task.completed","team_id":7,"data":{"id":42}}`)
+
+		// Act
+		require.True(t, dispatcher.enqueue(webhookDelivery{
+			webhookID: 1,
+			url:       receiver.URL,
+			secret:    "a-very-secret-value",
+			event:     webhookEventTaskCompleted,
+			body:      body,
+		}))
+
+		// Assert
+		webhook := waitForWebhook(t, received)
+		require.Equal(t, webhookEventTaskCompleted, webhook.event)
+		require.Equal(t, body, webhook.body)
+		require.Equal(t, signWebhookPayload("a-very-secret-value", body), webhook.signature)
+		require.NotEqual(t, signWebhookPayload("another-secret-value", body), webhook.signature)
+	})
+
+	t.Run("Drops deliveries when the queue is full", func(t *testing.T) {
+		// No workers are started, so nothing drains the queue
+		dispatcher := &webhookDispatcher{ctx: context.Background(), jobs: new(sync.WaitGroup), queue: make(chan webhookDelivery, 1)}
+		require.True(t, dispatcher.enqueue(webhookDelivery{webhookID: 1}))
+		require.False(t, dispatcher.enqueue(webhookDelivery{webhookID: 2}))
+	})
+
+	t.Run("Sends queued deliveries on shutdown", func(t *testing.T) {
+		receiver, received := newWebhookReceiver(t, 0)
+		ctx, cancel := context.WithCancel(context.Background())
+		var jobs sync.WaitGroup
+		dispatcher := newWebhookDispatcher(ctx, context.Background(), &jobs)
+		dispatcher.workers = 1
+
+		for id := range int64(3) {
+			require.True(t, dispatcher.enqueue(webhookDelivery{webhookID: id, url: receiver.URL, event: webhookEventTaskCompleted}))
+		}
+		cancel()
+		jobs.Wait()
+
+		// Everything queued before the shutdown was delivered, and nothing is taken after it
+		require.Len(t, received, 3)
+		require.False(t, dispatcher.enqueue(webhookDelivery{webhookID: 4, url: receiver.URL}))
+	})
+
+	t.Run("Abandons the queue at the shutdown deadline", func(t *testing.T) {
+		var calls atomic.Int32
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		t.Cleanup(receiver.Close)
+
+		// No workers run, so the queue is left to drain after the deadline has already passed
+		ctx, cancel := context.WithCancel(context.Background())
+		deliveryCtx, stopDelivery := context.WithCancel(context.Background())
+		var jobs sync.WaitGroup
+		dispatcher := newWebhookDispatcher(ctx, deliveryCtx, &jobs)
+		dispatcher.workers = 0
+		for id := range int64(3) {
+			require.True(t, dispatcher.enqueue(webhookDelivery{webhookID: id, url: receiver.URL}))
+		}
+		stopDelivery()
+		cancel()
+
+		dispatcher.drain()
+		require.Zero(t, calls.Load())
+		require.Len(t, dispatcher.queue, 3)
+	})
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	// Known HMAC-SHA256 test vector (RFC 4231 test case 2)
+	signature := signWebhookPayload("Jefe", []byte("what do ya want for nothing?"))
+	require.Equal(t, "sha256=5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843", signature)
+}
+
+func TestWebhookOnTaskCompletion(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	server := newTestServer(t, store)
+	server.webhooks.backoff = time.Millisecond
+
+	// Arrange: a webhook on a team with a manager and an engineer
+	team, err := store.CreateTeam(ctx, db.CreateTeamParams{TeamName: util.RandomName()})
+	require.NoError(t, err)
+	admin := createTestUser(t, store, db.UserRoleAdmin, 0)
+	manager := createTestUser(t, store, db.UserRoleManager, team.ID)
+	engineer := createTestUser(t, store, db.UserRoleEngineer, team.ID)
+
+	project, err := store.CreateProject(ctx, db.CreateProjectParams{ProjectName: util.RandomName(), TeamID: team.ID})
+	require.NoError(t, err)
+
+	receiver, received := newWebhookReceiver(t, 0)
+	body, err := json.Marshal(gin.H{
+		"team_id":     team.ID,
+		"url":         receiver.URL,
+		"event_types": []string{webhookEventTaskCompleted},
+	})
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/api/v1/admin/webhooks", bytes.NewReader(body))
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", "application/json")
+	addAuthorization(t, request, server, admin.ID, db.UserRoleAdmin, 0)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+
+	var created webhookResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &created))
+	require.NotEmpty(t, created.Secret)
+
+	// startTask creates a task on the team's project that the engineer is working on
+	startTask := func(t *testing.T) db.Task {
+		task, err := store.CreateTask(ctx, db.CreateTaskParams{
+			ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+			Title:     util.RandomName(),
+			Status:    db.TaskStatusOpen,
+			Priority:  db.TaskPriorityMedium,
+		})
+		require.NoError(t, err)
+		result, err := store.AssignTaskToUser(ctx, db.AssignTaskToUserTxParams{TaskID: task.ID, UserID: engineer.ID})
+		require.NoError(t, err)
+		return result.Task
+	}
+	requireCompletedWebhook := func(t *testing.T, task db.Task) {
+		webhook := waitForWebhook(t, received)
+		require.Equal(t, webhookEventTaskCompleted, webhook.event)
+		require.Equal(t, signWebhookPayload(created.Secret, webhook.body), webhook.signature)
+
+		var payload struct {
+			Event  string  `json:"event"`
+			TeamID int64   `json:"team_id"`
+			Data   db.Task `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(webhook.body, &payload))
+		require.Equal(t, webhookEventTaskCompleted, payload.Event)
+		require.Equal(t, team.ID, payload.TeamID)
+		require.Equal(t, task.ID, payload.Data.ID)
+		require.Equal(t, db.TaskStatusDone, payload.Data.Status)
+	}
+
+	t.Run("Engineer completes a task", func(t *testing.T) {
+		task := startTask(t)
+
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/engineer/tasks/%d/complete", task.ID), nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server, engineer.ID, db.UserRoleEngineer, team.ID)
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		requireCompletedWebhook(t, task)
+	})
+
+	t.Run("Manager marks a task done", func(t *testing.T) {
+		task := startTask(t)
+
+		body, err := json.Marshal(gin.H{"status": "done", "version": task.Version})
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("/api/v1/manager/tasks/%d", task.ID), bytes.NewReader(body))
+		require.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		addAuthorization(t, request, server, manager.ID, db.UserRoleManager, team.ID)
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+		requireCompletedWebhook(t, task)
+	})
+}
+
+func TestCreateWebhookValidation(t *testing.T) {
+	server := newTestServer(t, newUnreachableStore(t))
+
+	testCases := []struct {
+		name string
+		body gin.H
+	}{
+		{"Missing URL", gin.H{"team_id": 1, "event_types": []string{webhookEventTaskCompleted}}},
+		{"Invalid URL", gin.H{"team_id": 1, "url": "not a url", "event_types": []string{webhookEventTaskCompleted}}},
+		{"No events", gin.H{"team_id": 1, "url": "https://example.com/hook", "event_types": []string{}}},
+		{"Unknown event", gin.H{"team_id": 1, "url": "https://example.com/hook", "event_types": []string{"task.deleted"}}},
+		{"Short secret", gin.H{"team_id": 1, "url": "https://example.com/hook", "secret": "short", "event_types": []string{webhookEventTaskCompleted}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			body, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/admin/webhooks", bytes.NewReader(body))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+			addAuthorization(t, request, server, 1, db.UserRoleAdmin, 0)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusBadRequest, recorder.Code)
+		})
+	}
+}
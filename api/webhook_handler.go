@@ -0,0 +1,204 @@
+// api/webhook_handler.go
+package api
+
+import (
+	"crypto/rand"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pranav244872/synapse/db/sqlc"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Webhook Handlers (Admin)
+////////////////////////////////////////////////////////////////////////
+
+var errWebhookNotFound = errors.New("webhook not found")
+
+// webhookResponse is a webhook as returned to admins. The secret is only shown once, when the
+// webhook is created; afterwards it can be replaced but not read back.
+type webhookResponse struct {
+	ID         int64     `json:"id"`
+	TeamID     int64     `json:"team_id"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"secret,omitempty"`
+	EventTypes []string  `json:"event_types"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func newWebhookResponse(webhook db.Webhook) webhookResponse {
+	return webhookResponse{
+		ID:         webhook.ID,
+		TeamID:     webhook.TeamID,
+		URL:        webhook.Url,
+		EventTypes: webhook.EventTypes,
+		Active:     webhook.Active,
+		CreatedAt:  webhook.CreatedAt.Time,
+	}
+}
+
+type createWebhookRequest struct {
+	TeamID     int64    `json:"team_id" binding:"required,min=1"`
+	URL        string   `json:"url" binding:"required,http_url,max=2048"`
+	Secret     string   `json:"secret" binding:"omitempty,min=16,max=256"` // Generated when empty
+	EventTypes []string `json:"event_types" binding:"required,min=1,unique,dive,oneof=task.completed project.archived"`
+}
+
+// createWebhook registers a URL to receive a team's events, signed with the returned secret
+func (server *Server) createWebhook(ctx *gin.Context) {
+	slog.Debug("Starting createWebhook handler")
+
+	var req createWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, err := server.store.GetTeam(ctx, req.TeamID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("team not found")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if req.Secret == "" {
+		req.Secret = rand.Text()
+	}
+
+	webhook, err := server.store.CreateWebhook(ctx, db.CreateWebhookParams{
+		TeamID:     req.TeamID,
+		Url:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: req.EventTypes,
+	})
+	if err != nil {
+		slog.Error("Failed to create webhook", "team_id", req.TeamID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	slog.Info("Webhook created", "webhook_id", webhook.ID, "team_id", webhook.TeamID, "event_types", webhook.EventTypes)
+	rsp := newWebhookResponse(webhook)
+	rsp.Secret = webhook.Secret
+	ctx.JSON(http.StatusCreated, rsp)
+}
+
+type listWebhooksRequest struct {
+	TeamID int64 `form:"team_id" binding:"omitempty,min=1"` // Optional, only this team's webhooks
+}
+
+// listWebhooks lists the registered webhooks, without their secrets
+func (server *Server) listWebhooks(ctx *gin.Context) {
+	slog.Debug("Starting listWebhooks handler")
+
+	var req listWebhooksRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	webhooks, err := server.store.ListWebhooks(ctx, pgtype.Int8{Int64: req.TeamID, Valid: req.TeamID != 0})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]webhookResponse, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		rsp = append(rsp, newWebhookResponse(webhook))
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type updateWebhookRequest struct {
+	URL        *string  `json:"url" binding:"omitempty,http_url,max=2048"`
+	Secret     *string  `json:"secret" binding:"omitempty,min=16,max=256"`
+	EventTypes []string `json:"event_types" binding:"omitempty,min=1,unique,dive,oneof=task.completed project.archived"`
+	Active     *bool    `json:"active"`
+}
+
+// updateWebhook changes a webhook's URL, secret, event types or whether it is active
+func (server *Server) updateWebhook(ctx *gin.Context) {
+	slog.Debug("Starting updateWebhook handler")
+
+	var uriReq struct {
+		ID int64 `uri:"id" binding:"required,min=1"`
+	}
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.URL == nil && req.Secret == nil && req.EventTypes == nil && req.Active == nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("at least one field (url, secret, event_types, active) must be provided")))
+		return
+	}
+
+	arg := db.UpdateWebhookParams{
+		ID:         uriReq.ID,
+		EventTypes: req.EventTypes,
+	}
+	if req.URL != nil {
+		arg.Url = pgtype.Text{String: *req.URL, Valid: true}
+	}
+	if req.Secret != nil {
+		arg.Secret = pgtype.Text{String: *req.Secret, Valid: true}
+	}
+	if req.Active != nil {
+		arg.Active = pgtype.Bool{Bool: *req.Active, Valid: true}
+	}
+
+	webhook, err := server.store.UpdateWebhook(ctx, arg)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errWebhookNotFound))
+			return
+		}
+		slog.Error("Failed to update webhook", "webhook_id", uriReq.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	slog.Info("Webhook updated", "webhook_id", webhook.ID)
+	ctx.JSON(http.StatusOK, newWebhookResponse(webhook))
+}
+
+// deleteWebhook stops and removes a webhook
+func (server *Server) deleteWebhook(ctx *gin.Context) {
+	slog.Debug("Starting deleteWebhook handler")
+
+	var uriReq struct {
+		ID int64 `uri:"id" binding:"required,min=1"`
+	}
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	deleted, err := server.store.DeleteWebhook(ctx, uriReq.ID)
+	if err != nil {
+		slog.Error("Failed to delete webhook", "webhook_id", uriReq.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if deleted == 0 {
+		ctx.JSON(http.StatusNotFound, errorResponse(errWebhookNotFound))
+		return
+	}
+
+	slog.Info("Webhook deleted", "webhook_id", uriReq.ID)
+	ctx.Status(http.StatusNoContent)
+}
@@ -10,14 +10,49 @@ import (
 // The struct tags (mapstructure) tell Viper how to map environment variables to struct fields.
 type Config struct {
 	DBSource            string        	`mapstructure:"DB_SOURCE"`             	// Database connection string
+	DBTxTimeout			time.Duration	`mapstructure:"DB_TX_TIMEOUT"`			// Limit on one multi-step database transaction; 0 uses 10s, negative disables
 	ServerAddress       string        	`mapstructure:"SERVER_ADDRESS"`        	// Address where the server will run (e.g., "localhost:8080")
 	TokenSymmetricKey   string        	`mapstructure:"TOKEN_SYMMETRIC_KEY"`   	// Secret key for signing tokens
+	TokenType			string			`mapstructure:"TOKEN_TYPE"`			// Access token format: "jwt" (the default) or "paseto", which needs a key of exactly 32 characters
 	AccessTokenDuration time.Duration 	`mapstructure:"ACCESS_TOKEN_DURATION"` 	// Duration tokens will remain valid (e.g., "15m", "1h")
+	RefreshTokenDuration	time.Duration	`mapstructure:"REFRESH_TOKEN_DURATION"`	// How long a refresh token stays valid; 0 uses the default of 7 days
+	LLMProvider			string			`mapstructure:"LLM_PROVIDER"`			// LLM used for skill extraction: "gemini" (the default) or "openai"
 	GeminiAPIURL		string			`mapstructure:"GEMINI_API_URL"`
 	GeminiAPIKey        string        	`mapstructure:"GEMINI_API_KEY"`        	// API key for accessing Gemini (or any external service)
+	OpenAIAPIKey		string			`mapstructure:"OPENAI_API_KEY"`
+	OpenAIBaseURL		string			`mapstructure:"OPENAI_BASE_URL"`		// OpenAI-compatible API root; empty uses https://api.openai.com/v1
+	OpenAIModel			string			`mapstructure:"OPENAI_MODEL"`			// Chat model to call, e.g. "gpt-4o-mini"
+	LLMTimeout			time.Duration	`mapstructure:"LLM_TIMEOUT"`			// Limit on one call to the LLM provider, and on each skill extraction step; 0 uses 60s
 	RecommenderAPIURL	string			`mapstructure:"RECOMMENDER_API_URL"`
 	RecommenderAPIKey	string			`mapstructure:"RECOMMENDER_API_KEY"`	// API key for accessing Recommendations
-	FrontendURL			string			`mapstructure:"FRONTEND_URL"`
+	RecommenderTimeout	time.Duration	`mapstructure:"RECOMMENDER_TIMEOUT"`	// Limit on one call to the recommender; 0 uses 10s
+	FrontendURL			string			`mapstructure:"FRONTEND_URL"`			// Frontend origin, also the base of links sent by email
+	TrustedProxies		[]string		`mapstructure:"TRUSTED_PROXIES"`		// Comma-separated proxy IPs or CIDRs whose X-Forwarded-For is believed; empty trusts none
+	SMTPHost			string		`mapstructure:"SMTP_HOST"`				// SMTP relay for outgoing email; empty disables sending
+	SMTPPort			int			`mapstructure:"SMTP_PORT"`				// SMTP relay port, e.g. 587
+	SMTPUsername		string		`mapstructure:"SMTP_USERNAME"`			// SMTP login; empty sends without authenticating
+	SMTPPassword		string		`mapstructure:"SMTP_PASSWORD"`
+	SMTPFromAddress		string		`mapstructure:"SMTP_FROM_ADDRESS"`		// Sender address on outgoing email
+	InvitationTTL		time.Duration	`mapstructure:"INVITATION_TTL"`		// How long an invitation can be accepted after it is sent or resent; 0 uses 72h, negative is rejected
+	InvitationExpiryInterval	time.Duration	`mapstructure:"INVITATION_EXPIRY_INTERVAL"`	// How often stale pending invitations are marked expired; 0 uses 1h, negative disables
+	TaskEscalationInterval	time.Duration	`mapstructure:"TASK_ESCALATION_INTERVAL"`	// How often open, unassigned tasks are checked for priority escalation; 0 uses 1h, negative disables
+	TaskEscalateMediumAfter	time.Duration	`mapstructure:"TASK_ESCALATE_MEDIUM_AFTER"`	// Age at which an open, unassigned low task becomes medium; 0 uses 7 days
+	TaskEscalateHighAfter	time.Duration	`mapstructure:"TASK_ESCALATE_HIGH_AFTER"`	// Age at which an open, unassigned low or medium task becomes high; 0 uses 14 days. Overdue tasks become high at once
+	RecommendationCacheTTL	time.Duration	`mapstructure:"RECOMMENDATION_CACHE_TTL"`	// How long recommender results are reused per task; 0 uses 30s, negative disables
+	IdempotencyKeyTTL	time.Duration	`mapstructure:"IDEMPOTENCY_KEY_TTL"`	// How long responses to Idempotency-Key requests are replayed; 0 uses 24h, negative disables
+	LoginRateLimit		int			`mapstructure:"LOGIN_RATE_LIMIT"`		// Login attempts, and separately invitation checks, per minute per client IP; 0 uses 10, negative disables
+	LLMRateLimit		int			`mapstructure:"LLM_RATE_LIMIT"`		// Requests per minute per user to endpoints that call the LLM; 0 uses 20, negative disables
+	SkillAutoVerifyMinUsers	int64		`mapstructure:"SKILL_AUTO_VERIFY_MIN_USERS"`	// Users holding an unverified skill before it is auto-verified
+	SkillAutoVerifyMinTasks	int64		`mapstructure:"SKILL_AUTO_VERIFY_MIN_TASKS"`	// Tasks requiring an unverified skill before it is auto-verified
+	RequireDependencies	bool		`mapstructure:"REQUIRE_DEPENDENCIES"`	// Refuse to start when LLM or recommender settings are incomplete
+	DefaultTaskPriority	string		`mapstructure:"DEFAULT_TASK_PRIORITY"`	// Priority for new tasks when neither the request nor the team sets one
+	MaxResumeLength		int			`mapstructure:"MAX_RESUME_LENGTH"`		// Characters of resume text accepted for skill extraction; 0 uses the default
+	MinTaskDescriptionLength	int		`mapstructure:"MIN_TASK_DESCRIPTION_LENGTH"`	// Characters a task description needs before skills are extracted from it; 0 uses 20, negative always extracts
+	AccessLogEnabled	bool		`mapstructure:"ACCESS_LOG_ENABLED"`		// Emit one structured access-log line per request
+	AccessLogSampleRate	int			`mapstructure:"ACCESS_LOG_SAMPLE_RATE"`	// Log one in every N requests under high load; 0 or 1 logs all of them
+	LogLevel			string		`mapstructure:"LOG_LEVEL"`				// Application log level: debug, info, warn or error; empty means info
+	ReadinessProbeDependencies	bool	`mapstructure:"READINESS_PROBE_DEPENDENCIES"`	// Make readyz also check that the LLM and recommender endpoints answer
+	MetricsEnabled		bool		`mapstructure:"METRICS_ENABLED"`		// Serve Prometheus metrics on /metrics
 }
 
 // LoadConfig loads environment variables from a file and environment into the Config struct
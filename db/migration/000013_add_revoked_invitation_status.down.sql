@@ -0,0 +1,42 @@
+-- =============================================
+-- Migration Down: 000013_add_revoked_invitation_status.down.sql
+-- =============================================
+-- This migration reverts the 'revoked' invitation status. Revoked rows are
+-- removed first because neither the old check constraint nor the old unique
+-- email constraint can accommodate them.
+-- Since the up migration, an email may also have been invited more than once
+-- (e.g. an expired invitation followed by a new one). The old constraint allows
+-- one row per email, so only the row that matters most is kept: a pending
+-- invitation, else an accepted one, else the most recent. The others are deleted.
+
+-- Section 1: Remove Revoked Invitations
+-- -------------------------------------------
+DELETE FROM invitations WHERE status = 'revoked';
+
+-- Section 2: Keep One Invitation per Email
+-- -------------------------------------------
+DELETE FROM invitations
+WHERE id IN (
+    SELECT id
+    FROM (
+        SELECT
+            id,
+            ROW_NUMBER() OVER (
+                PARTITION BY email
+                ORDER BY status = 'pending' DESC, status = 'accepted' DESC, created_at DESC, id DESC
+            ) AS rank
+        FROM invitations
+    ) ranked
+    WHERE ranked.rank > 1
+);
+
+-- Section 3: Restore the Table-Wide Unique Email Constraint
+-- -------------------------------------------
+DROP INDEX IF EXISTS idx_invitations_email_pending;
+ALTER TABLE invitations ADD CONSTRAINT invitations_email_key UNIQUE (email);
+
+-- Section 4: Restore the Original Status Values
+-- -------------------------------------------
+ALTER TABLE invitations DROP CONSTRAINT chk_status;
+ALTER TABLE invitations
+ADD CONSTRAINT chk_status CHECK (status IN ('pending', 'accepted', 'expired'));
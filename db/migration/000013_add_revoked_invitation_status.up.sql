@@ -0,0 +1,19 @@
+-- =============================================
+-- Migration Up: 000013_add_revoked_invitation_status.up.sql
+-- =============================================
+-- This migration lets invitations be revoked instead of deleted, so the audit
+-- trail of who was invited (and then un-invited) is preserved.
+-- 1. Adds 'revoked' to the allowed invitation statuses.
+-- 2. Replaces the table-wide unique email constraint with a partial unique index
+--    over pending invitations, so a revoked email can be invited again.
+
+-- Section 1: Allow the 'revoked' Status
+-- -------------------------------------------
+ALTER TABLE invitations DROP CONSTRAINT chk_status;
+ALTER TABLE invitations
+ADD CONSTRAINT chk_status CHECK (status IN ('pending', 'accepted', 'expired', 'revoked'));
+
+-- Section 2: Only One Pending Invitation per Email
+-- -------------------------------------------
+ALTER TABLE invitations DROP CONSTRAINT invitations_email_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_invitations_email_pending ON invitations (email) WHERE status = 'pending';
@@ -0,0 +1,9 @@
+-- =============================================
+-- Migration Down: 000014_add_auto_assign_to_teams.down.sql
+-- =============================================
+-- This migration removes the per-team auto-assign setting.
+
+-- Section 1: Drop Auto-Assign Setting
+-- -------------------------------------------
+ALTER TABLE teams
+DROP COLUMN IF EXISTS auto_assign;
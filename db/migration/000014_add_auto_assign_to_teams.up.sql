@@ -0,0 +1,13 @@
+-- =============================================
+-- Migration Up: 000014_add_auto_assign_to_teams.up.sql
+-- =============================================
+-- This migration adds a per-team setting that controls whether newly created
+-- tasks are automatically assigned to the top recommended engineer.
+
+-- Section 1: Add Auto-Assign Setting
+-- -------------------------------------------
+-- Defaults to false so existing teams keep assigning tasks manually.
+ALTER TABLE teams
+ADD COLUMN auto_assign BOOLEAN NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN teams.auto_assign IS 'When true, new tasks are assigned to the top available recommended engineer on creation';
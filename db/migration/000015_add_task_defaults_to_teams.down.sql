@@ -0,0 +1,10 @@
+-- =============================================
+-- Migration Down: 000015_add_task_defaults_to_teams.down.sql
+-- =============================================
+-- This migration removes the per-team task defaults.
+
+-- Section 1: Drop Task Default Settings
+-- -------------------------------------------
+ALTER TABLE teams
+DROP COLUMN IF EXISTS default_task_status,
+DROP COLUMN IF EXISTS default_task_priority;
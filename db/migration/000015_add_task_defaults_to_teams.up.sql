@@ -0,0 +1,14 @@
+-- =============================================
+-- Migration Up: 000015_add_task_defaults_to_teams.up.sql
+-- =============================================
+-- This migration adds per-team defaults applied to newly created tasks.
+
+-- Section 1: Add Task Default Settings
+-- -------------------------------------------
+-- Both are nullable; NULL falls back to the server-wide defaults.
+ALTER TABLE teams
+ADD COLUMN default_task_priority task_priority,
+ADD COLUMN default_task_status task_status;
+
+COMMENT ON COLUMN teams.default_task_priority IS 'Priority given to new tasks created without one; NULL uses the server default';
+COMMENT ON COLUMN teams.default_task_status IS 'Status new tasks are created in; NULL means open';
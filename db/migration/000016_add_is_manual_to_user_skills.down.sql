@@ -0,0 +1,9 @@
+-- =============================================
+-- Migration Down: 000016_add_is_manual_to_user_skills.down.sql
+-- =============================================
+-- This migration removes the manual flag from user skills.
+
+-- Section 1: Drop Manual Flag
+-- -------------------------------------------
+ALTER TABLE user_skills
+DROP COLUMN IF EXISTS is_manual;
@@ -0,0 +1,12 @@
+-- =============================================
+-- Migration Up: 000016_add_is_manual_to_user_skills.up.sql
+-- =============================================
+-- This migration tells skills a person curated apart from skills extracted from a resume.
+
+-- Section 1: Add Manual Flag
+-- -------------------------------------------
+-- Existing rows all came from resume extraction, so they default to false.
+ALTER TABLE user_skills
+ADD COLUMN is_manual boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN user_skills.is_manual IS 'Set when a person added or endorsed the skill; reprocessing a resume never removes these';
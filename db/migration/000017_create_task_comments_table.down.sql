@@ -0,0 +1,9 @@
+-- =============================================
+-- Migration Down: 000017_create_task_comments_table.down.sql
+-- =============================================
+-- This migration reverts the creation of the 'task_comments' table.
+
+-- Section 1: Drop Task Comments Table
+-- -------------------------------------------
+-- Dropping the table also drops its index and constraints.
+DROP TABLE IF EXISTS task_comments;
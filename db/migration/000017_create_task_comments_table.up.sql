@@ -0,0 +1,36 @@
+-- =============================================
+-- Migration Up: 000017_create_task_comments_table.up.sql
+-- =============================================
+-- This migration creates the 'task_comments' table so managers and engineers can discuss a task.
+-- 1. Creates the 'task_comments' table, with an optional parent for threaded replies.
+-- 2. Indexes comments by task for listing them in order.
+
+-- Section 1: Create Task Comments Table
+-- -------------------------------------------
+CREATE TABLE task_comments (
+    -- Unique identifier for each comment.
+    id BIGSERIAL PRIMARY KEY,
+
+    -- The task being discussed. Comments go away with their task.
+    task_id BIGINT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+
+    -- The user who wrote the comment. Deleting the user removes their comments.
+    author_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+
+    -- The comment being replied to, NULL for a top-level comment.
+    -- Deleting a comment also deletes the replies under it.
+    parent_id BIGINT REFERENCES task_comments(id) ON DELETE CASCADE,
+
+    -- The comment text.
+    body TEXT NOT NULL,
+
+    -- Timestamp for when the comment was written.
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE task_comments IS 'Discussion on a task by members of its team, with threaded replies.';
+
+-- Section 2: Add Indexes
+-- -------------------------------------------
+-- Comments are always listed per task, oldest first.
+CREATE INDEX IF NOT EXISTS idx_task_comments_task_id_created_at ON task_comments (task_id, created_at);
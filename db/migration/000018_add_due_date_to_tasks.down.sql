@@ -0,0 +1,11 @@
+-- =============================================
+-- Migration Down: 000018_add_due_date_to_tasks.down.sql
+-- =============================================
+-- This migration removes task deadlines.
+
+-- Section 1: Drop Due Date
+-- -------------------------------------------
+DROP INDEX IF EXISTS idx_tasks_due_date;
+
+ALTER TABLE tasks
+DROP COLUMN IF EXISTS due_date;
@@ -0,0 +1,18 @@
+-- =============================================
+-- Migration Up: 000018_add_due_date_to_tasks.up.sql
+-- =============================================
+-- This migration gives tasks an optional deadline.
+
+-- Section 1: Add Due Date
+-- -------------------------------------------
+-- Nullable; existing tasks keep no deadline.
+ALTER TABLE tasks
+ADD COLUMN due_date TIMESTAMP;
+
+COMMENT ON COLUMN tasks.due_date IS 'Deadline for the task; NULL means no deadline';
+
+-- Section 2: Add Indexes
+-- -------------------------------------------
+-- Overdue lookups only ever look at live tasks that have a deadline.
+CREATE INDEX IF NOT EXISTS idx_tasks_due_date ON tasks (due_date)
+WHERE due_date IS NOT NULL AND archived = false;
@@ -0,0 +1,9 @@
+-- =============================================
+-- Migration Down: 000019_create_password_reset_tokens_table.down.sql
+-- =============================================
+-- This migration reverts the creation of the 'password_reset_tokens' table.
+
+-- Section 1: Drop Password Reset Tokens Table
+-- -------------------------------------------
+-- Dropping the table also drops its index and constraints.
+DROP TABLE IF EXISTS password_reset_tokens;
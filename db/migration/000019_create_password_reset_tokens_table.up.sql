@@ -0,0 +1,34 @@
+-- =============================================
+-- Migration Up: 000019_create_password_reset_tokens_table.up.sql
+-- =============================================
+-- This migration creates the 'password_reset_tokens' table for the forgot-password flow.
+-- 1. Creates the 'password_reset_tokens' table with single-use, time-limited tokens.
+-- 2. Indexes tokens by user so old ones can be found and cleaned up.
+
+-- Section 1: Create Password Reset Tokens Table
+-- -------------------------------------------
+CREATE TABLE password_reset_tokens (
+    -- Unique identifier for each reset token.
+    id BIGSERIAL PRIMARY KEY,
+
+    -- The user whose password the token resets. Tokens go away with their user.
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+
+    -- The secret sent to the user, a random UUID.
+    token VARCHAR(255) NOT NULL UNIQUE,
+
+    -- The token cannot be used after this time.
+    expires_at TIMESTAMP NOT NULL,
+
+    -- When the token was used, NULL while it is still unused.
+    consumed_at TIMESTAMP,
+
+    -- Timestamp for when the token was issued.
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE password_reset_tokens IS 'Single-use, time-limited tokens for resetting a forgotten password.';
+
+-- Section 2: Add Indexes
+-- -------------------------------------------
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user_id ON password_reset_tokens (user_id);
@@ -0,0 +1,8 @@
+-- =============================================
+-- Migration Down: 000020_add_lower_skill_name_index.down.sql
+-- =============================================
+-- This migration removes the case-insensitive skill name index.
+
+-- Section 1: Drop Indexes
+-- -------------------------------------------
+DROP INDEX IF EXISTS idx_skills_lower_skill_name;
@@ -0,0 +1,10 @@
+-- =============================================
+-- Migration Up: 000020_add_lower_skill_name_index.up.sql
+-- =============================================
+-- This migration supports looking up skills by name regardless of case.
+
+-- Section 1: Add Indexes
+-- -------------------------------------------
+-- Skill resolution compares lower(skill_name), so "node.js" finds the existing "Node.js" row.
+-- Not unique, so databases that already hold names differing only in case still migrate.
+CREATE INDEX IF NOT EXISTS idx_skills_lower_skill_name ON skills (lower(skill_name));
@@ -0,0 +1,13 @@
+-- =============================================
+-- Migration Down: 000021_create_task_activity_table.down.sql
+-- =============================================
+-- This migration reverts the creation of the 'task_activity' table.
+
+-- Section 1: Drop Task Activity Table
+-- -------------------------------------------
+-- Dropping the table also drops its index and constraints.
+DROP TABLE IF EXISTS task_activity;
+
+-- Section 2: Drop Event Type
+-- -------------------------------------------
+DROP TYPE IF EXISTS task_activity_event;
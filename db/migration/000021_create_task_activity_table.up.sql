@@ -0,0 +1,51 @@
+-- =============================================
+-- Migration Up: 000021_create_task_activity_table.up.sql
+-- =============================================
+-- This migration creates the 'task_activity' table, an audit log of what happened to each task.
+-- 1. Creates the 'task_activity_event' enum listing the recorded events.
+-- 2. Creates the 'task_activity' table.
+-- 3. Indexes activity by task for listing it newest first.
+
+-- Section 1: Create Event Type
+-- -------------------------------------------
+CREATE TYPE "task_activity_event" AS ENUM (
+  'created',
+  'assigned',
+  'reassigned',
+  'status_changed',
+  'completed',
+  'archived'
+);
+
+-- Section 2: Create Task Activity Table
+-- -------------------------------------------
+CREATE TABLE task_activity (
+    -- Unique identifier for each entry.
+    id BIGSERIAL PRIMARY KEY,
+
+    -- The task the event happened to. Its history goes away with it.
+    task_id BIGINT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+
+    -- The user who caused the event; NULL when unknown or since deleted.
+    actor_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+
+    -- What happened.
+    event task_activity_event NOT NULL,
+
+    -- The engineer the task was given to, for 'assigned' and 'reassigned'.
+    assignee_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+
+    -- The status before and after, for 'status_changed'.
+    from_status task_status,
+    to_status task_status,
+
+    -- Timestamp for when the event happened.
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE task_activity IS 'Audit log of events on a task: who did what, and when.';
+
+-- Section 3: Add Indexes
+-- -------------------------------------------
+-- Activity is always listed per task, newest first.
+CREATE INDEX IF NOT EXISTS idx_task_activity_task_id_created_at ON task_activity (task_id, created_at);
@@ -0,0 +1,9 @@
+-- =============================================
+-- Migration Down: 000022_create_refresh_tokens_table.down.sql
+-- =============================================
+-- This migration reverts the creation of the 'refresh_tokens' table.
+
+-- Section 1: Drop Refresh Tokens Table
+-- -------------------------------------------
+-- Dropping the table also drops its index and constraints.
+DROP TABLE IF EXISTS refresh_tokens;
@@ -0,0 +1,37 @@
+-- =============================================
+-- Migration Up: 000022_create_refresh_tokens_table.up.sql
+-- =============================================
+-- This migration creates the 'refresh_tokens' table for renewing access tokens without logging in again.
+-- 1. Creates the 'refresh_tokens' table. Only a hash of each token is stored.
+-- 2. Indexes tokens by user so all of a user's sessions can be revoked at once.
+
+-- Section 1: Create Refresh Tokens Table
+-- -------------------------------------------
+CREATE TABLE refresh_tokens (
+    -- Unique identifier for each refresh token.
+    id BIGSERIAL PRIMARY KEY,
+
+    -- The user the token renews sessions for. Tokens go away with their user.
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+
+    -- Hex-encoded SHA-256 of the token handed to the client; the token itself is never stored.
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+
+    -- The token cannot be used after this time.
+    expires_at TIMESTAMP NOT NULL,
+
+    -- When the token was rotated or revoked, NULL while it is still usable.
+    revoked_at TIMESTAMP,
+
+    -- The token issued when this one was rotated. A rotated token presented again signals theft.
+    replaced_by BIGINT REFERENCES refresh_tokens(id) ON DELETE SET NULL,
+
+    -- Timestamp for when the token was issued.
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE refresh_tokens IS 'Hashed, rotating refresh tokens used to renew access tokens.';
+
+-- Section 2: Add Indexes
+-- -------------------------------------------
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens (user_id);
@@ -0,0 +1,10 @@
+-- =============================================
+-- Migration Down: 000023_add_weight_to_task_required_skills.down.sql
+-- =============================================
+-- This migration removes required skill weights.
+
+-- Section 1: Drop Weight
+-- -------------------------------------------
+-- Dropping the column also drops its range check.
+ALTER TABLE task_required_skills
+DROP COLUMN IF EXISTS weight;
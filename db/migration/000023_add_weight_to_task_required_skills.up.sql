@@ -0,0 +1,13 @@
+-- =============================================
+-- Migration Up: 000023_add_weight_to_task_required_skills.up.sql
+-- =============================================
+-- This migration records how central each required skill is to its task.
+
+-- Section 1: Add Weight
+-- -------------------------------------------
+-- Existing links become full-weight, which is how the recommender treated them until now.
+ALTER TABLE task_required_skills
+ADD COLUMN weight DOUBLE PRECISION NOT NULL DEFAULT 1
+CONSTRAINT task_required_skills_weight_range CHECK (weight > 0 AND weight <= 1);
+
+COMMENT ON COLUMN task_required_skills.weight IS 'How central the skill is to the task, from just above 0 (peripheral) to 1 (core)';
@@ -0,0 +1,8 @@
+-- =============================================
+-- Migration Down: 000024_add_task_search_index.down.sql
+-- =============================================
+-- This migration removes the task search index.
+
+-- Section 1: Drop Indexes
+-- -------------------------------------------
+DROP INDEX IF EXISTS idx_tasks_search_tsv;
@@ -0,0 +1,12 @@
+-- =============================================
+-- Migration Up: 000024_add_task_search_index.up.sql
+-- =============================================
+-- This migration supports the manager's team-wide task search.
+
+-- Section 1: Add Indexes
+-- -------------------------------------------
+-- Full-text index over title and description; the expression must match the one in SearchTasksByTeam
+-- and CountSearchTasksByTeam for the planner to use it.
+-- Substring matches on the title are already covered by idx_tasks_title_gin.
+CREATE INDEX IF NOT EXISTS idx_tasks_search_tsv ON "tasks"
+USING GIN (to_tsvector('english', title || ' ' || COALESCE(description, '')));
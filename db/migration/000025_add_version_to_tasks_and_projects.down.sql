@@ -0,0 +1,12 @@
+-- =============================================
+-- Migration Down: 000025_add_version_to_tasks_and_projects.down.sql
+-- =============================================
+-- This migration removes the task and project versions.
+
+-- Section 1: Drop Version Columns
+-- -------------------------------------------
+ALTER TABLE projects
+DROP COLUMN IF EXISTS version;
+
+ALTER TABLE tasks
+DROP COLUMN IF EXISTS version;
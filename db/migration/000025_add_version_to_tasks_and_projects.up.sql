@@ -0,0 +1,17 @@
+-- =============================================
+-- Migration Up: 000025_add_version_to_tasks_and_projects.up.sql
+-- =============================================
+-- This migration lets concurrent edits of a task or project be detected
+-- instead of the last write silently winning.
+
+-- Section 1: Add Version Columns
+-- -------------------------------------------
+-- Existing rows start at version 1; updates bump it.
+ALTER TABLE tasks
+ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+ALTER TABLE projects
+ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN tasks.version IS 'Incremented on every update; clients send the version they read to detect concurrent edits';
+COMMENT ON COLUMN projects.version IS 'Incremented on every update; clients send the version they read to detect concurrent edits';
@@ -0,0 +1,9 @@
+-- =============================================
+-- Migration Down: 000026_create_webhooks_table.down.sql
+-- =============================================
+-- This migration reverts the creation of the 'webhooks' table.
+
+-- Section 1: Drop Webhooks Table
+-- -------------------------------------------
+-- Dropping the table also drops its index and constraints.
+DROP TABLE IF EXISTS webhooks;
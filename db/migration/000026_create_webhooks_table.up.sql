@@ -0,0 +1,38 @@
+-- =============================================
+-- Migration Up: 000026_create_webhooks_table.up.sql
+-- =============================================
+-- This migration creates the 'webhooks' table so external tools can react to team events.
+-- 1. Creates the 'webhooks' table: a team-scoped URL, the secret payloads are signed with,
+--    and the event types it receives.
+-- 2. Indexes webhooks by team, which is how deliveries look them up.
+
+-- Section 1: Create Webhooks Table
+-- -------------------------------------------
+CREATE TABLE webhooks (
+    -- Unique identifier for each webhook.
+    id BIGSERIAL PRIMARY KEY,
+
+    -- The team whose events are delivered. Webhooks go away with their team.
+    team_id BIGINT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+
+    -- Where events are POSTed.
+    url TEXT NOT NULL,
+
+    -- Key for the HMAC-SHA256 signature sent with each delivery.
+    secret TEXT NOT NULL,
+
+    -- Events delivered to the URL, e.g. 'task.completed'.
+    event_types TEXT[] NOT NULL,
+
+    -- Inactive webhooks are kept but receive nothing.
+    active BOOLEAN NOT NULL DEFAULT true,
+
+    -- Timestamp for when the webhook was registered.
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE webhooks IS 'Team-scoped endpoints that receive signed event notifications.';
+
+-- Section 2: Add Indexes
+-- -------------------------------------------
+CREATE INDEX IF NOT EXISTS idx_webhooks_team_id ON webhooks (team_id);
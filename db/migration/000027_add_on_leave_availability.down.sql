@@ -0,0 +1,16 @@
+-- =============================================
+-- Migration Down: 000027_add_on_leave_availability.down.sql
+-- =============================================
+-- This migration makes every engineer on leave available again.
+
+-- Section 1: Clear On Leave Statuses
+-- -------------------------------------------
+UPDATE users
+SET availability = 'available'
+WHERE availability = 'on_leave';
+
+-- Section 2: Revert Availability Type Enhancement
+-- -------------------------------------------
+-- IMPORTANT: PostgreSQL does not support 'DROP VALUE' for ENUM types.
+-- As in 000005, the 'on_leave' value is intentionally left in the
+-- availability_status type; nothing uses it once the users above are reset.
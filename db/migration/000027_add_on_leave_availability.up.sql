@@ -0,0 +1,11 @@
+-- =============================================
+-- Migration Up: 000027_add_on_leave_availability.up.sql
+-- =============================================
+-- This migration lets engineers signal that they are away, so no work is
+-- assigned to them until they come back.
+
+-- Section 1: Extend the Availability Type
+-- -------------------------------------------
+-- Unlike 'busy', which follows task assignment, 'on_leave' is only ever set
+-- by the engineer themselves.
+ALTER TYPE availability_status ADD VALUE 'on_leave';
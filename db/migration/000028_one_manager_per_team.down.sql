@@ -0,0 +1,8 @@
+-- =============================================
+-- Migration Down: 000028_one_manager_per_team.down.sql
+-- =============================================
+-- This migration removes the one-manager-per-team guarantee.
+
+-- Section 1: Drop One Manager Per Team Index
+-- -------------------------------------------
+DROP INDEX IF EXISTS idx_users_one_manager_per_team;
@@ -0,0 +1,14 @@
+-- =============================================
+-- Migration Up: 000028_one_manager_per_team.up.sql
+-- =============================================
+-- This migration makes the database, not just application code, guarantee
+-- that a team has at most one manager.
+
+-- Section 1: One Manager Per Team
+-- -------------------------------------------
+-- idx_teams_manager_id_unique (000011) stops one manager running two teams,
+-- but nothing stopped two manager accounts pointing at the same team: two
+-- manager invitations for a vacant team could both be accepted concurrently.
+-- Engineers and admins are unaffected by this partial index.
+-- NOTE: this fails if a team already has two managers; resolve those first.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_one_manager_per_team ON users (team_id) WHERE role = 'manager';
@@ -0,0 +1,21 @@
+-- =============================================
+-- Migration Down: 000029_add_task_priority_escalation.down.sql
+-- =============================================
+-- This migration removes priority escalations from the activity log.
+
+-- Section 1: Remove Escalation Entries
+-- -------------------------------------------
+DELETE FROM task_activity
+WHERE event = 'priority_escalated';
+
+-- Section 2: Drop Priority Columns
+-- -------------------------------------------
+ALTER TABLE task_activity
+DROP COLUMN IF EXISTS from_priority,
+DROP COLUMN IF EXISTS to_priority;
+
+-- Section 3: Revert Event Type Enhancement
+-- -------------------------------------------
+-- IMPORTANT: PostgreSQL does not support 'DROP VALUE' for ENUM types.
+-- As in 000027, the 'priority_escalated' value is intentionally left in the
+-- task_activity_event type; nothing uses it once the entries above are gone.
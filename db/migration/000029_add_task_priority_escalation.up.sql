@@ -0,0 +1,16 @@
+-- =============================================
+-- Migration Up: 000029_add_task_priority_escalation.up.sql
+-- =============================================
+-- This migration lets the activity log record priority escalations, so
+-- teams can see when and why a waiting task was made more urgent.
+
+-- Section 1: Extend the Event Type
+-- -------------------------------------------
+ALTER TYPE task_activity_event ADD VALUE 'priority_escalated';
+
+-- Section 2: Record Priorities on Activity
+-- -------------------------------------------
+-- Like from_status and to_status, these are NULL unless the event uses them.
+ALTER TABLE task_activity
+ADD COLUMN from_priority task_priority,
+ADD COLUMN to_priority task_priority;
@@ -0,0 +1,27 @@
+-- =============================================
+-- Migration Down: 000030_soft_delete_task_comments.down.sql
+-- =============================================
+-- This migration reverts soft-deleted comments to hard deletes.
+-- Soft-deleted comments are removed first, since without deleted_at they would
+-- show up as empty comments. Their replies are kept as top-level comments.
+
+-- Section 1: Restore the Cascading Parent Key
+-- -------------------------------------------
+ALTER TABLE task_comments DROP CONSTRAINT task_comments_parent_id_fkey;
+ALTER TABLE task_comments
+ADD CONSTRAINT task_comments_parent_id_fkey
+FOREIGN KEY (parent_id) REFERENCES task_comments(id) ON DELETE CASCADE;
+
+-- Section 2: Remove Soft-Deleted Comments
+-- -------------------------------------------
+UPDATE task_comments
+SET parent_id = NULL
+WHERE parent_id IN (SELECT id FROM task_comments WHERE deleted_at IS NOT NULL);
+
+DELETE FROM task_comments
+WHERE deleted_at IS NOT NULL;
+
+-- Section 3: Drop Deletion Timestamp
+-- -------------------------------------------
+ALTER TABLE task_comments
+DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,23 @@
+-- =============================================
+-- Migration Up: 000030_soft_delete_task_comments.up.sql
+-- =============================================
+-- This migration makes deleting a comment a soft delete, so replies written by
+-- other users survive when the comment they answer is removed.
+-- 1. Records when a comment was deleted.
+-- 2. Stops the parent foreign key from cascading deletes to replies.
+
+-- Section 1: Add Deletion Timestamp
+-- -------------------------------------------
+-- NULL while the comment is live. A deleted comment keeps its row, with an
+-- empty body, so its replies stay in the thread.
+ALTER TABLE task_comments
+ADD COLUMN deleted_at TIMESTAMP;
+
+-- Section 2: Stop Cascading to Replies
+-- -------------------------------------------
+-- A comment row is still removed when its author is deleted. Replies by other
+-- users then become top-level comments instead of being removed with it.
+ALTER TABLE task_comments DROP CONSTRAINT task_comments_parent_id_fkey;
+ALTER TABLE task_comments
+ADD CONSTRAINT task_comments_parent_id_fkey
+FOREIGN KEY (parent_id) REFERENCES task_comments(id) ON DELETE SET NULL;
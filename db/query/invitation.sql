@@ -44,6 +44,18 @@ WHERE
     i.invitation_token = $1 AND i.status = 'pending' AND i.expires_at > now()
 LIMIT 1;
 
+-- name: GetPendingInvitationByToken :one
+-- Retrieves a pending invitation by its token, including one whose window has lapsed.
+-- expired is decided by the database clock, the same one GetInvitationByToken filters with.
+SELECT
+    i.email, i.role_to_invite, i.team_id,
+    (i.expires_at <= now())::bool AS expired
+FROM
+    invitations i
+WHERE
+    i.invitation_token = $1 AND i.status = 'pending'
+LIMIT 1;
+
 -- name: GetInvitationByEmail :one
 SELECT
     i.id, i.email, i.invitation_token, i.role_to_invite, i.inviter_id, i.status, i.created_at, i.expires_at, i.team_id,
@@ -77,6 +89,48 @@ LEFT JOIN
 DELETE FROM invitations
 WHERE invitations.id = $1 AND invitations.status = 'pending';
 
+-- name: DeleteInvitationsByTeam :execrows
+-- Deletes every invitation to a team, whatever its status; used when the team itself is deleted.
+DELETE FROM invitations
+WHERE team_id = $1;
+
+-- name: CountInvitationsByTeam :one
+-- Counts every invitation to a team, whatever its status.
+SELECT count(*) FROM invitations
+WHERE team_id = $1;
+
+-- name: RevokeInvitation :execrows
+-- Marks a pending invitation as revoked, keeping the row for auditing.
+-- Returns the number of rows updated so callers can detect a non-pending invitation.
+UPDATE invitations
+SET status = 'revoked'
+WHERE invitations.id = $1 AND invitations.status = 'pending';
+
+-- name: ExpirePendingInvitations :execrows
+-- Marks pending invitations past their expiry as expired.
+-- Returns the number of invitations expired.
+UPDATE invitations
+SET status = 'expired'
+WHERE status = 'pending' AND expires_at < now();
+
+-- name: RefreshInvitationToken :one
+-- Gives a pending invitation a new token and expiry, invalidating the old token.
+-- Returns no rows if the invitation is no longer pending.
+WITH refreshed_invitation AS (
+    UPDATE invitations
+    SET invitation_token = $2, expires_at = $3
+    WHERE invitations.id = $1 AND invitations.status = 'pending'
+    RETURNING *
+)
+SELECT
+    i.id, i.email, i.invitation_token, i.role_to_invite, i.inviter_id, i.status, i.created_at, i.expires_at, i.team_id,
+    COALESCE(u.name, '') as inviter_name,
+    COALESCE(u.email, '') as inviter_email
+FROM
+    refreshed_invitation i
+LEFT JOIN
+    users u ON i.inviter_id = u.id;
+
 -- ----------------------------------------------------------------
 -- Invitation List Queries (Admin & Manager)
 -- ----------------------------------------------------------------
@@ -91,13 +145,19 @@ FROM
     invitations i
 LEFT JOIN
     users u ON i.inviter_id = u.id
+WHERE
+    i.created_at BETWEEN COALESCE(sqlc.narg(created_from)::timestamp, '-infinity'::timestamp)
+        AND COALESCE(sqlc.narg(created_to)::timestamp, 'infinity'::timestamp)
 ORDER BY
     i.created_at DESC
 LIMIT $1
 OFFSET $2;
 
 -- name: CountAllInvitations :one
-SELECT count(*) FROM invitations;
+SELECT count(*) FROM invitations i
+WHERE
+    i.created_at BETWEEN COALESCE(sqlc.narg(created_from)::timestamp, '-infinity'::timestamp)
+        AND COALESCE(sqlc.narg(created_to)::timestamp, 'infinity'::timestamp);
 
 -- name: ListInvitationsByInviter :many
 SELECT
@@ -111,13 +171,19 @@ LEFT JOIN
     users u ON i.inviter_id = u.id
 WHERE
     i.inviter_id = $1
+    AND i.created_at BETWEEN COALESCE(sqlc.narg(created_from)::timestamp, '-infinity'::timestamp)
+        AND COALESCE(sqlc.narg(created_to)::timestamp, 'infinity'::timestamp)
 ORDER BY
     i.created_at DESC
 LIMIT $2
 OFFSET $3;
 
 -- name: CountInvitationsByInviter :one
-SELECT count(*) FROM invitations WHERE inviter_id = $1;
+SELECT count(*) FROM invitations i
+WHERE
+    i.inviter_id = $1
+    AND i.created_at BETWEEN COALESCE(sqlc.narg(created_from)::timestamp, '-infinity'::timestamp)
+        AND COALESCE(sqlc.narg(created_to)::timestamp, 'infinity'::timestamp);
 
 -- name: ListInvitationsByInviterRole :many
 SELECT
@@ -131,6 +197,8 @@ LEFT JOIN
     users u ON i.inviter_id = u.id
 WHERE
     u.role = $1
+    AND i.created_at BETWEEN COALESCE(sqlc.narg(created_from)::timestamp, '-infinity'::timestamp)
+        AND COALESCE(sqlc.narg(created_to)::timestamp, 'infinity'::timestamp)
 ORDER BY
     i.created_at DESC
 LIMIT $2
@@ -141,4 +209,44 @@ SELECT count(*)
 FROM invitations i
 LEFT JOIN
     users u ON i.inviter_id = u.id
-WHERE u.role = $1;
+WHERE u.role = $1
+    AND i.created_at BETWEEN COALESCE(sqlc.narg(created_from)::timestamp, '-infinity'::timestamp)
+        AND COALESCE(sqlc.narg(created_to)::timestamp, 'infinity'::timestamp);
+
+-- ----------------------------------------------------------------
+-- Invitation Statistics (Admin)
+-- ----------------------------------------------------------------
+
+-- name: ListInvitationStatsByInviter :many
+-- Summarizes each inviter's invitations by outcome, with the share that was accepted.
+-- Pending invitations past their expiry count as expired even before a job marks them so.
+-- Sorts by acceptance rate when sort_by is 'acceptance_rate', otherwise by invitations sent.
+SELECT
+    i.inviter_id,
+    COALESCE(u.name, '') AS inviter_name,
+    COALESCE(u.email, '') AS inviter_email,
+    COALESCE(u.role::text, 'unknown')::text AS inviter_role,
+    count(*) AS sent_count,
+    count(*) FILTER (WHERE i.status = 'accepted') AS accepted_count,
+    count(*) FILTER (WHERE i.status = 'pending' AND i.expires_at >= NOW()) AS pending_count,
+    count(*) FILTER (WHERE i.status = 'expired' OR (i.status = 'pending' AND i.expires_at < NOW())) AS expired_count,
+    count(*) FILTER (WHERE i.status = 'revoked') AS revoked_count,
+    ((count(*) FILTER (WHERE i.status = 'accepted'))::float8 / count(*))::float8 AS acceptance_rate
+FROM
+    invitations i
+LEFT JOIN
+    users u ON i.inviter_id = u.id
+GROUP BY
+    i.inviter_id, u.name, u.email, u.role
+ORDER BY
+    CASE WHEN sqlc.arg(sort_by)::text = 'acceptance_rate'
+        THEN (count(*) FILTER (WHERE i.status = 'accepted'))::float8 / count(*)
+    END DESC,
+    sent_count DESC,
+    i.inviter_id
+LIMIT $1
+OFFSET $2;
+
+-- name: CountInvitationInviters :one
+-- Returns the number of distinct inviters, for paginating ListInvitationStatsByInviter.
+SELECT count(DISTINCT inviter_id) FROM invitations;
@@ -0,0 +1,29 @@
+-- SQLC-formatted queries for the "password_reset_tokens" table.
+-- These follow the conventions for use with the sqlc tool.
+
+-- name: ConsumePasswordResetToken :one
+-- Marks a token as used, but only if it is unused and not yet expired.
+-- Returns no rows for an unknown, expired or already consumed token.
+UPDATE password_reset_tokens
+SET consumed_at = NOW()
+WHERE token = $1
+    AND consumed_at IS NULL
+    AND expires_at > NOW()
+RETURNING *;
+
+-- name: ConsumePasswordResetTokensByUser :exec
+-- Marks every unused token a user holds as used, so older reset links stop working.
+UPDATE password_reset_tokens
+SET consumed_at = NOW()
+WHERE user_id = $1
+    AND consumed_at IS NULL;
+
+-- name: CreatePasswordResetToken :one
+-- Issues a new reset token for a user.
+INSERT INTO password_reset_tokens (
+    user_id,
+    token,
+    expires_at
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
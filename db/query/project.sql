@@ -31,11 +31,12 @@ LIMIT $1
 OFFSET $2;
 
 -- name: UpdateProject :one
--- Updates a project's name and description.
+-- Updates a project's name and description if it is still at the expected version, bumping it.
 UPDATE projects
 SET project_name = $3,
-    description = $4
-WHERE id = $1 AND team_id = $2
+    description = $4,
+    version = version + 1
+WHERE id = $1 AND team_id = $2 AND version = $5
 RETURNING *;
 
 -- name: DeleteProject :exec
@@ -45,21 +46,21 @@ WHERE id = $1;
 
 -- Archive a single active project by ID and team, returning its details
 -- name: ArchiveProject :one
-UPDATE projects 
-SET archived = true, archived_at = now()
+UPDATE projects
+SET archived = true, archived_at = now(), version = version + 1
 WHERE id = $1 AND team_id = $2 AND archived = false
-RETURNING id, project_name, team_id, description, archived, archived_at;
+RETURNING id, project_name, team_id, description, archived, archived_at, version;
 
 -- Unarchive a single archived project by ID and team, returning its details
 -- name: UnarchiveProject :one  
 UPDATE projects
-SET archived = false, archived_at = NULL
+SET archived = false, archived_at = NULL, version = version + 1
 WHERE id = $1 AND team_id = $2 AND archived = true
-RETURNING id, project_name, team_id, description, archived, archived_at;
+RETURNING id, project_name, team_id, description, archived, archived_at, version;
 
 -- List paginated active (non-archived) projects for a team
 -- name: ListActiveProjectsByTeam :many
-SELECT id, project_name, team_id, description, archived, archived_at
+SELECT id, project_name, team_id, description, archived, archived_at, version
 FROM projects 
 WHERE team_id = $1 AND archived = false
 ORDER BY id
@@ -67,7 +68,7 @@ LIMIT $2 OFFSET $3;
 
 -- List paginated archived projects for a team, sorted by archive date
 -- name: ListArchivedProjectsByTeam :many
-SELECT id, project_name, team_id, description, archived, archived_at
+SELECT id, project_name, team_id, description, archived, archived_at, version
 FROM projects
 WHERE team_id = $1 AND archived = true  
 ORDER BY archived_at DESC
@@ -85,7 +86,7 @@ WHERE team_id = $1 AND archived = true;
 
 -- List paginated active projects for a team
 -- name: ListProjectsByTeam :many
-SELECT id, project_name, team_id, description, archived, archived_at FROM projects
+SELECT id, project_name, team_id, description, archived, archived_at, version FROM projects
 WHERE team_id = $1 AND archived = false
 ORDER BY id
 LIMIT $2 OFFSET $3;
@@ -93,3 +94,13 @@ LIMIT $2 OFFSET $3;
 -- Count only active projects for a team
 -- name: CountProjectsByTeam :one
 SELECT count(*) FROM projects WHERE team_id = $1 AND archived = false;
+
+-- List the active projects for a team with the most outstanding (not done) work
+-- name: ListTopActiveProjectsByTeam :many
+SELECT p.id, p.project_name, p.description, count(t.id) AS open_task_count
+FROM projects p
+LEFT JOIN tasks t ON t.project_id = p.id AND t.archived = false AND t.status <> 'done'
+WHERE p.team_id = $1 AND p.archived = false
+GROUP BY p.id
+ORDER BY open_task_count DESC, p.id
+LIMIT $2;
@@ -0,0 +1,39 @@
+-- SQLC-formatted queries for the "refresh_tokens" table.
+-- These follow the conventions for use with the sqlc tool.
+
+-- name: CreateRefreshToken :one
+-- Stores the hash of a newly issued refresh token.
+INSERT INTO refresh_tokens (
+    user_id,
+    token_hash,
+    expires_at
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: GetRefreshTokenByHashForUpdate :one
+-- Fetches a refresh token by its hash and locks it, so two concurrent refreshes cannot both rotate it.
+SELECT * FROM refresh_tokens
+WHERE token_hash = $1
+FOR UPDATE;
+
+-- name: RevokeRefreshToken :exec
+-- Marks a refresh token as no longer usable, recording the token that replaced it when it was rotated.
+UPDATE refresh_tokens
+SET revoked_at = NOW(), replaced_by = $2
+WHERE id = $1;
+
+-- name: RevokeRefreshTokenByHash :execrows
+-- Revokes a still-usable refresh token, e.g. on logout.
+-- Returns the number of rows updated so callers can tell whether it was usable.
+UPDATE refresh_tokens
+SET revoked_at = NOW()
+WHERE token_hash = $1
+    AND revoked_at IS NULL;
+
+-- name: RevokeRefreshTokensByUser :exec
+-- Revokes every usable refresh token a user holds, ending all of their sessions.
+UPDATE refresh_tokens
+SET revoked_at = NOW()
+WHERE user_id = $1
+    AND revoked_at IS NULL;
@@ -57,8 +57,9 @@ WHERE id = $1
 RETURNING *;
 
 -- name: ListSkillsByNames :many
+-- Retrieves the skills matching any of the given names, ignoring case.
 SELECT * FROM skills
-WHERE skill_name = ANY($1::text[]);
+WHERE lower(skill_name) = ANY(SELECT lower(unnest($1::text[])));
 
 -- name: CreateManySkills :many
 INSERT INTO skills (skill_name, is_verified)
@@ -91,3 +92,34 @@ LIMIT $3 OFFSET $4;
 SELECT count(*) FROM skills 
 WHERE is_verified = $1 
 AND LOWER(skill_name) LIKE LOWER($2);
+
+-- name: AutoVerifySkillsByUsage :many
+-- Verifies unverified skills that are held by at least min_users users or required by at least min_tasks tasks.
+UPDATE skills s
+SET is_verified = true
+WHERE s.is_verified = false
+AND (
+    (SELECT count(*) FROM user_skills us WHERE us.skill_id = s.id) >= sqlc.arg(min_users)::bigint OR
+    (SELECT count(*) FROM task_required_skills trs WHERE trs.skill_id = s.id) >= sqlc.arg(min_tasks)::bigint
+)
+RETURNING *;
+
+-- name: ListMostUsedUnverifiedSkills :many
+-- Lists unverified skills ranked by how many users hold them plus how many tasks require them,
+-- so the ones with the most impact can be verified first.
+SELECT
+    s.id,
+    s.skill_name,
+    COALESCE(us.user_count, 0)::bigint AS user_count,
+    COALESCE(trs.task_count, 0)::bigint AS task_count
+FROM skills s
+LEFT JOIN (
+    SELECT skill_id, count(*) AS user_count FROM user_skills GROUP BY skill_id
+) us ON us.skill_id = s.id
+LEFT JOIN (
+    SELECT skill_id, count(*) AS task_count FROM task_required_skills GROUP BY skill_id
+) trs ON trs.skill_id = s.id
+WHERE s.is_verified = false
+ORDER BY COALESCE(us.user_count, 0) + COALESCE(trs.task_count, 0) DESC, s.skill_name
+LIMIT $1
+OFFSET $2;
@@ -10,6 +10,18 @@ INSERT INTO skill_aliases (
     $1, $2
 ) RETURNING *;
 
+-- name: CreateSkillAliasIfNotExists :one
+-- Inserts a skill alias unless one with the same name already exists.
+-- Returns no rows for an existing alias, so bulk imports can report it and carry on.
+INSERT INTO skill_aliases (
+    alias_name,
+    skill_id
+) VALUES (
+    $1, $2
+)
+ON CONFLICT (alias_name) DO NOTHING
+RETURNING *;
+
 -- name: GetSkillAlias :one
 -- Retrieves a single skill alias by its name (primary key).
 SELECT * FROM skill_aliases
@@ -38,6 +50,20 @@ FROM
 JOIN
     skills s ON sa.skill_id = s.id;
 
+-- name: ListSkillsByAliasNames :many
+-- Resolves a batch of alias names to their canonical skills.
+SELECT
+    sa.alias_name,
+    s.id,
+    s.skill_name,
+    s.is_verified
+FROM
+    skill_aliases sa
+JOIN
+    skills s ON sa.skill_id = s.id
+WHERE
+    sa.alias_name = ANY(sqlc.arg(alias_names)::text[]);
+
 -- name: UpdateSkillAlias :one
 -- Updates the canonical skill a specific alias points to.
 -- It's uncommon to update an alias; re-mapping is the primary use case.
@@ -47,7 +73,63 @@ SET
 WHERE alias_name = $2
 RETURNING *;
 
--- name: DeleteSkillAlias :exec
+-- name: DeleteSkillAlias :execrows
 -- Deletes a skill alias from the database by its name.
+-- Returns the number of rows deleted, so a missing alias can be reported.
 DELETE FROM skill_aliases
 WHERE alias_name = $1;
+
+-- name: RepointSkillAliases :execrows
+-- Moves every alias of the source skill to the target skill.
+UPDATE skill_aliases
+SET skill_id = sqlc.arg(target_id)
+WHERE skill_id = sqlc.arg(source_id);
+
+-- name: ListAllSkillAliases :many
+-- Lists a page of every alias with the name of its canonical skill, for auditing.
+-- The search pattern is matched against both the alias and the skill name; '%' matches everything.
+-- Backslash escapes a literal '%' or '_' in the pattern.
+SELECT
+    sa.alias_name,
+    sa.skill_id,
+    s.skill_name
+FROM
+    skill_aliases sa
+JOIN
+    skills s ON sa.skill_id = s.id
+WHERE
+    LOWER(sa.alias_name) LIKE LOWER(sqlc.arg(search)) ESCAPE '\'
+    OR LOWER(s.skill_name) LIKE LOWER(sqlc.arg(search)) ESCAPE '\'
+ORDER BY
+    sa.alias_name
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountAllSkillAliases :one
+-- Counts the aliases ListAllSkillAliases pages through for the same search pattern.
+SELECT count(*)
+FROM
+    skill_aliases sa
+JOIN
+    skills s ON sa.skill_id = s.id
+WHERE
+    LOWER(sa.alias_name) LIKE LOWER(sqlc.arg(search)) ESCAPE '\'
+    OR LOWER(s.skill_name) LIKE LOWER(sqlc.arg(search)) ESCAPE '\';
+
+-- name: SearchSkillSuggestions :many
+-- Retrieves skills with the given verification status whose name or one of whose aliases matches
+-- the search pattern, so searching for "golang" finds "Go". Skills whose name matches the prefix
+-- pattern come first, then the rest by name. Both patterns escape wildcards with a backslash.
+SELECT s.*
+FROM skills s
+WHERE s.is_verified = sqlc.arg('is_verified')
+AND (
+    LOWER(s.skill_name) LIKE LOWER(sqlc.arg('search')) ESCAPE '\'
+    OR EXISTS (
+        SELECT 1 FROM skill_aliases sa
+        WHERE sa.skill_id = s.id
+        AND LOWER(sa.alias_name) LIKE LOWER(sqlc.arg('search')) ESCAPE '\'
+    )
+)
+ORDER BY LOWER(s.skill_name) LIKE LOWER(sqlc.arg('prefix')) ESCAPE '\' DESC, s.skill_name
+LIMIT sqlc.arg('limit');
@@ -9,9 +9,10 @@ INSERT INTO tasks (
     description,
     status,
     priority,
-    assignee_id
+    assignee_id,
+    due_date
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
+    $1, $2, $3, $4, $5, $6, $7
 ) RETURNING *;
 
 -- name: GetTask :one
@@ -19,6 +20,12 @@ INSERT INTO tasks (
 SELECT * FROM tasks
 WHERE id = $1 LIMIT 1;
 
+-- name: GetTaskForUpdate :one
+-- Retrieves a task and locks it until the transaction ends, so concurrent edits of it run one at a time.
+SELECT * FROM tasks
+WHERE id = $1
+FOR UPDATE;
+
 -- name: ListTasks :many
 -- Retrieves a paginated list of all tasks, ordered by creation date.
 SELECT * FROM tasks
@@ -28,7 +35,8 @@ OFFSET $2;
 
 -- name: UpdateTask :one
 -- Updates the details of a specific task.
--- Uses sqlc.narg() to allow for partial updates of any field.
+-- Uses sqlc.narg() to allow for partial updates of any field; clear_due_date removes the deadline.
+-- Bumps the version; when an expected version is given, a stale one matches no row.
 UPDATE tasks
 SET
     project_id = COALESCE(sqlc.narg(project_id), project_id),
@@ -37,10 +45,94 @@ SET
     status = COALESCE(sqlc.narg(status), status),
     priority = COALESCE(sqlc.narg(priority), priority),
     assignee_id = COALESCE(sqlc.narg(assignee_id), assignee_id),
-    completed_at = COALESCE(sqlc.narg(completed_at), completed_at)
+    completed_at = COALESCE(sqlc.narg(completed_at), completed_at),
+    due_date = CASE
+        WHEN sqlc.arg(clear_due_date)::bool THEN NULL
+        ELSE COALESCE(sqlc.narg(due_date), due_date)
+    END,
+    version = version + 1
 WHERE id = sqlc.arg(id)
+  AND (sqlc.narg(version)::int IS NULL OR version = sqlc.narg(version))
+RETURNING *;
+
+-- name: UnassignTask :one
+-- Reopens an in-progress task and clears its assignee, only if it is assigned to the given user.
+UPDATE tasks
+SET assignee_id = NULL, status = 'open', version = version + 1
+WHERE id = $1 AND assignee_id = $2 AND status = 'in_progress'
+RETURNING *;
+
+-- name: UnassignActiveTasksInTeam :many
+-- Unassigns and reopens a user's live, unfinished tasks on a team's projects.
+-- Each row also carries the status the task had before, read from the pre-update snapshot.
+UPDATE tasks t
+SET assignee_id = NULL, status = 'open', version = t.version + 1
+FROM tasks previous
+JOIN projects p ON previous.project_id = p.id
+WHERE t.id = previous.id
+  AND previous.assignee_id = sqlc.arg(assignee_id)
+  AND p.team_id = sqlc.arg(team_id)
+  AND previous.status <> 'done'
+  AND previous.archived = false
+RETURNING t.*, previous.status AS previous_status;
+
+-- name: ReassignActiveTasks :many
+-- Moves a user's live, unfinished tasks to another user, each keeping its status.
+UPDATE tasks
+SET assignee_id = sqlc.arg(to_assignee_id), version = version + 1
+WHERE assignee_id = sqlc.arg(from_assignee_id)
+  AND status <> 'done'
+  AND archived = false
+RETURNING *;
+
+-- name: PauseTask :one
+-- Moves an in-progress task back to open while keeping its assignee, only if it is assigned to the given user.
+UPDATE tasks
+SET status = 'open', version = version + 1
+WHERE id = $1 AND assignee_id = $2 AND status = 'in_progress' AND archived = false
+RETURNING *;
+
+-- name: ResumeTask :one
+-- Moves a paused task (open, but still assigned to the given user) back to in progress.
+UPDATE tasks
+SET status = 'in_progress', version = version + 1
+WHERE id = $1 AND assignee_id = $2 AND status = 'open' AND archived = false
+RETURNING *;
+
+-- name: ClaimTask :one
+-- Assigns an open, unassigned task to the given user, only if its project belongs to the given team.
+UPDATE tasks
+SET assignee_id = $2, status = 'in_progress', version = version + 1
+WHERE id = $1 AND status = 'open' AND assignee_id IS NULL AND archived = false
+    AND project_id IN (SELECT id FROM projects WHERE team_id = $3)
+RETURNING *;
+
+-- name: StartTask :one
+-- Moves an open, assigned task to in progress.
+UPDATE tasks
+SET status = 'in_progress', version = version + 1
+WHERE id = $1 AND status = 'open' AND assignee_id IS NOT NULL AND archived = false
+RETURNING *;
+
+-- name: MarkTaskDone :one
+-- Moves an in-progress task to done and stamps its completion time.
+UPDATE tasks
+SET status = 'done', completed_at = now(), version = version + 1
+WHERE id = $1 AND status = 'in_progress' AND archived = false
 RETURNING *;
 
+-- name: ReopenTask :one
+-- Moves a done task back to open, clearing its completion time and assignee.
+UPDATE tasks
+SET status = 'open', completed_at = NULL, assignee_id = NULL, version = version + 1
+WHERE id = $1 AND status = 'done' AND archived = false
+RETURNING *;
+
+-- name: CountInProgressTasksByAssignee :one
+-- Counts a user's active in-progress tasks, which decides whether they are busy.
+SELECT count(*) FROM tasks
+WHERE assignee_id = $1 AND status = 'in_progress' AND archived = false;
+
 -- name: DeleteTask :exec
 -- Deletes a task from the database by its ID.
 DELETE FROM tasks
@@ -49,20 +141,20 @@ WHERE id = $1;
 -- Archive a single active task by ID and return its details
 -- name: ArchiveTask :one
 UPDATE tasks
-SET archived = true, archived_at = now()  
+SET archived = true, archived_at = now(), version = version + 1
 WHERE id = $1 AND archived = false
-RETURNING id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at;
+RETURNING id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at, due_date, version;
 
--- Unarchive a single archived task by ID and return its details
+-- Restore a single archived task by ID as open and unassigned, since its engineer was freed on archive
 -- name: UnarchiveTask :one
-UPDATE tasks  
-SET archived = false, archived_at = NULL
+UPDATE tasks
+SET archived = false, archived_at = NULL, status = 'open', assignee_id = NULL, completed_at = NULL, version = version + 1
 WHERE id = $1 AND archived = true
-RETURNING id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at;
+RETURNING id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at, due_date, version;
 
 -- List paginated active (non-archived) tasks for a project, sorted by creation date
 -- name: ListActiveTasksByProject :many
-SELECT id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at
+SELECT id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at, due_date, version
 FROM tasks
 WHERE project_id = $1 AND archived = false
 ORDER BY created_at DESC
@@ -70,7 +162,7 @@ LIMIT $2 OFFSET $3;
 
 -- List paginated archived tasks for a project, sorted by archive date
 -- name: ListArchivedTasksByProject :many  
-SELECT id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at
+SELECT id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at, due_date, version
 FROM tasks
 WHERE project_id = $1 AND archived = true
 ORDER BY archived_at DESC  
@@ -81,6 +173,16 @@ LIMIT $2 OFFSET $3;
 SELECT count(*) FROM tasks
 WHERE project_id = $1 AND archived = false;
 
+-- Count each user's unfinished (open or in-progress, non-archived) tasks, for a batch of users at once
+-- Users without unfinished tasks are absent from the result
+-- name: CountActiveTasksByAssignees :many
+SELECT
+    assignee_id,
+    count(*) AS task_count
+FROM tasks
+WHERE assignee_id = ANY(sqlc.arg(assignee_ids)::bigint[]) AND status <> 'done' AND archived = false
+GROUP BY assignee_id;
+
 -- Count the number of archived tasks in a project
 -- name: CountArchivedTasksByProject :one
 SELECT count(*) FROM tasks  
@@ -89,29 +191,169 @@ WHERE project_id = $1 AND archived = true;
 -- Archive all completed tasks in a project that are not already archived
 -- name: ArchiveCompletedTasksByProject :exec
 UPDATE tasks
-SET archived = true, archived_at = now()
+SET archived = true, archived_at = now(), version = version + 1
 WHERE project_id = $1 AND status = 'done' AND archived = false;
 
+-- Restore all archived tasks in a project. Done tasks keep their status, assignee and completion time;
+-- any other task was archived on its own, its engineer freed, so it comes back open and unassigned
+-- name: UnarchiveTasksByProject :exec
+UPDATE tasks
+SET archived = false,
+    archived_at = NULL,
+    status = CASE WHEN status = 'done' THEN status ELSE 'open' END,
+    assignee_id = CASE WHEN status = 'done' THEN assignee_id ELSE NULL END,
+    version = version + 1
+WHERE project_id = $1 AND archived = true;
+
 -- List paginated active tasks for a project (updated version)
 -- name: ListTasksByProject :many
-SELECT id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at FROM tasks
+SELECT id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at, due_date, version FROM tasks
 WHERE project_id = $1 AND archived = false
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3;
 
 -- List paginated active tasks assigned to a specific user
 -- name: ListTasksByAssignee :many
-SELECT id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at FROM tasks
+SELECT id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at, due_date, version FROM tasks
 WHERE assignee_id = $1 AND archived = false
 ORDER BY created_at DESC
 LIMIT $2
 OFFSET $3;
 
+-- name: CountOverdueTasksByTeam :one
+-- Counts the team's live tasks that are past their due date and not done.
+SELECT count(*) FROM tasks t
+JOIN projects p ON t.project_id = p.id
+WHERE p.team_id = $1
+  AND t.status <> 'done'
+  AND t.archived = false
+  AND t.due_date < (now() AT TIME ZONE 'UTC');
+
+-- name: ListOverdueTasksByTeam :many
+-- Lists a page of the team's live tasks that are past their due date and not done, most overdue first.
+-- Tasks without a due date have no deadline and are never overdue.
+SELECT t.* FROM tasks t
+JOIN projects p ON t.project_id = p.id
+WHERE p.team_id = $1
+  AND t.status <> 'done'
+  AND t.archived = false
+  AND t.due_date < (now() AT TIME ZONE 'UTC')
+ORDER BY t.due_date, t.id
+LIMIT $2
+OFFSET $3;
+
+-- name: EscalateStaleTasks :many
+-- Raises the priority of live, open, unassigned tasks that have waited too long: low ones to 'medium'
+-- once older than medium_after_seconds, and low or medium ones to 'high' once older than
+-- high_after_seconds or past their due date. High and critical tasks are never touched, and a task
+-- is only raised once per threshold, so running it again changes nothing. A NULL project_id covers
+-- every project.
+-- Each row also carries the priority the task had before, read from the pre-update snapshot.
+UPDATE tasks t
+SET priority = CASE
+        WHEN previous.created_at <= now() - make_interval(secs => sqlc.arg(high_after_seconds)::float8)
+          OR previous.due_date < (now() AT TIME ZONE 'UTC') THEN 'high'::task_priority
+        ELSE 'medium'::task_priority
+    END,
+    version = t.version + 1
+FROM tasks previous
+WHERE t.id = previous.id
+  AND previous.status = 'open'
+  AND previous.assignee_id IS NULL
+  AND previous.archived = false
+  AND (
+      (previous.priority = 'low'
+        AND previous.created_at <= now() - make_interval(secs => sqlc.arg(medium_after_seconds)::float8))
+      OR (previous.priority IN ('low', 'medium')
+        AND (previous.created_at <= now() - make_interval(secs => sqlc.arg(high_after_seconds)::float8)
+          OR previous.due_date < (now() AT TIME ZONE 'UTC')))
+  )
+  AND (sqlc.narg(project_id)::bigint IS NULL OR previous.project_id = sqlc.narg(project_id))
+RETURNING t.*, previous.priority AS previous_priority;
+
+-- name: ListTaskTrendsByTeam :many
+-- Daily counts of the team's tasks created and completed over the last N days (UTC), oldest first.
+-- Every day in the window has a row, so charts need no gap filling; archived tasks still count.
+WITH window_days AS (
+    SELECT generate_series(
+        date_trunc('day', now() AT TIME ZONE 'UTC') - make_interval(days => sqlc.arg(days)::int - 1),
+        date_trunc('day', now() AT TIME ZONE 'UTC'),
+        interval '1 day'
+    )::timestamp AS day
+),
+team_tasks AS (
+    -- The columns hold the database session's local time, so they are moved to UTC like the window
+    SELECT
+        (t.created_at AT TIME ZONE current_setting('TimeZone')) AT TIME ZONE 'UTC' AS created_at,
+        (t.completed_at AT TIME ZONE current_setting('TimeZone')) AT TIME ZONE 'UTC' AS completed_at
+    FROM tasks t
+    JOIN projects p ON t.project_id = p.id
+    WHERE p.team_id = sqlc.arg(team_id)
+),
+created AS (
+    SELECT date_trunc('day', created_at) AS day, count(*) AS task_count
+    FROM team_tasks
+    WHERE created_at >= (SELECT min(day) FROM window_days)
+    GROUP BY 1
+),
+completed AS (
+    SELECT date_trunc('day', completed_at) AS day, count(*) AS task_count
+    FROM team_tasks
+    WHERE completed_at >= (SELECT min(day) FROM window_days)
+    GROUP BY 1
+)
+SELECT
+    w.day,
+    COALESCE(c.task_count, 0)::bigint AS created_count,
+    COALESCE(d.task_count, 0)::bigint AS completed_count
+FROM window_days w
+LEFT JOIN created c ON c.day = w.day
+LEFT JOIN completed d ON d.day = w.day
+ORDER BY w.day;
+
 -- Count the number of active (non-archived) tasks in a project with a specific status
 -- name: CountTasksByProjectAndStatus :one
 SELECT count(*) FROM tasks 
 WHERE project_id = $1 AND status = $2 AND archived = false;
 
+-- Count active (non-archived) tasks and how many of them are done, for a batch of projects at once
+-- Projects without active tasks are absent from the result
+-- name: CountTasksByProjectIDs :many
+SELECT
+    project_id,
+    count(*) AS total_count,
+    count(*) FILTER (WHERE status = 'done') AS completed_count
+FROM tasks
+WHERE project_id = ANY(sqlc.arg(project_ids)::bigint[]) AND archived = false
+GROUP BY project_id;
+
+-- Count a project's active (non-archived) tasks for each combination of status and priority
+-- name: CountTasksByProjectStatusAndPriority :many
+SELECT status, priority, count(*) AS task_count
+FROM tasks
+WHERE project_id = $1 AND archived = false
+GROUP BY status, priority;
+
+-- Count a project's active (non-archived) tasks per assignee, and how many of them are done
+-- Unassigned tasks form one row with a NULL assignee
+-- name: CountTasksByProjectAssignee :many
+SELECT
+    t.assignee_id,
+    u.name AS assignee_name,
+    count(*) AS task_count,
+    count(*) FILTER (WHERE t.status = 'done') AS done_count
+FROM tasks t
+LEFT JOIN users u ON u.id = t.assignee_id
+WHERE t.project_id = $1 AND t.archived = false
+GROUP BY t.assignee_id, u.name
+ORDER BY task_count DESC, u.name;
+
+-- Average time from creation to completion of a project's active done tasks, in seconds; 0 when none are done
+-- name: GetAverageCompletionTimeByProject :one
+SELECT COALESCE(EXTRACT(EPOCH FROM AVG(completed_at - created_at)), 0)::float8 AS avg_completion_seconds
+FROM tasks
+WHERE project_id = $1 AND status = 'done' AND completed_at IS NOT NULL AND archived = false;
+
 -- List tasks in a project along with assignee names, with pagination and sorted by newest first
 -- name: ListTasksWithAssigneeNames :many
 SELECT t.id, t.title, t.status, t.priority, t.assignee_id, 
@@ -122,6 +364,42 @@ WHERE t.project_id = $1 AND t.archived = false
 ORDER BY t.created_at DESC
 LIMIT $2 OFFSET $3;
 
+-- List a project's active tasks with assignee names, newest first, a page at a time by keyset:
+-- pass the last row's created_at and id to continue after it, or NULL to start from the newest
+-- name: ListTasksWithAssigneeNamesAfter :many
+SELECT t.id, t.title, t.status, t.priority, t.assignee_id,
+       u.name as assignee_name, t.created_at
+FROM tasks t
+LEFT JOIN users u ON t.assignee_id = u.id
+WHERE t.project_id = $1 AND t.archived = false
+  AND (sqlc.narg(after_created_at)::timestamp IS NULL
+       OR (t.created_at, t.id) < (sqlc.narg(after_created_at)::timestamp, sqlc.narg(after_id)::bigint))
+ORDER BY t.created_at DESC, t.id DESC
+LIMIT $2;
+
+-- name: ListTasksWithSkillsByProject :many
+-- List tasks in a project with assignee names and the names of their required skills, newest first.
+-- Skills are aggregated per task so a board needs no per-task skill lookups; tasks without skills get an empty array.
+SELECT
+    t.id,
+    t.title,
+    t.status,
+    t.priority,
+    t.assignee_id,
+    u.name AS assignee_name,
+    COALESCE(
+        array_agg(s.skill_name ORDER BY s.skill_name) FILTER (WHERE s.id IS NOT NULL),
+        '{}'
+    )::text[] AS skill_names
+FROM tasks t
+LEFT JOIN users u ON t.assignee_id = u.id
+LEFT JOIN task_required_skills trs ON trs.task_id = t.id
+LEFT JOIN skills s ON s.id = trs.skill_id
+WHERE t.project_id = $1 AND t.archived = false
+GROUP BY t.id, u.name
+ORDER BY t.created_at DESC
+LIMIT $2 OFFSET $3;
+
 -- name: GetAssignedEngineersForProject :many
 -- Get all user IDs who are assigned to active tasks in a specific project
 SELECT DISTINCT t.assignee_id
@@ -175,6 +453,8 @@ WHERE
     AND t.status = 'done'
     AND t.archived = false
     AND t.title ILIKE sqlc.arg(search) -- Use sqlc.arg for the optional search parameter
+    AND (sqlc.narg(completed_from)::timestamp IS NULL OR t.completed_at >= sqlc.narg(completed_from))
+    AND (sqlc.narg(completed_to)::timestamp IS NULL OR t.completed_at <= sqlc.narg(completed_to))
 ORDER BY
     t.completed_at DESC
 LIMIT $2
@@ -189,4 +469,151 @@ WHERE
     assignee_id = $1
     AND status = 'done'
     AND archived = false
-    AND title ILIKE sqlc.arg(search);
+    AND title ILIKE sqlc.arg(search)
+    AND (sqlc.narg(completed_from)::timestamp IS NULL OR completed_at >= sqlc.narg(completed_from))
+    AND (sqlc.narg(completed_to)::timestamp IS NULL OR completed_at <= sqlc.narg(completed_to));
+
+-- name: ListRecentTasksByTeam :many
+-- Lists the most recently created or completed active tasks across all of a team's projects
+SELECT
+    t.id,
+    t.title,
+    t.status,
+    t.assignee_id,
+    u.name AS assignee_name,
+    p.project_name,
+    t.created_at,
+    t.completed_at
+FROM
+    tasks t
+JOIN
+    projects p ON t.project_id = p.id
+LEFT JOIN
+    users u ON t.assignee_id = u.id
+WHERE
+    p.team_id = $1
+    AND t.archived = false
+ORDER BY
+    COALESCE(t.completed_at, t.created_at) DESC
+LIMIT $2;
+
+-- name: ListMatchingOpenTasksForEngineer :many
+-- Lists open, unassigned tasks on a team whose required skills overlap an engineer's skills.
+-- Tasks are ranked by the number of overlapping skills, then by the engineer's proficiency in them.
+SELECT
+    t.id,
+    t.title,
+    t.priority,
+    p.id AS project_id,
+    p.project_name,
+    count(*) AS matching_skill_count,
+    SUM(
+        CASE us.proficiency
+            WHEN 'expert' THEN 3
+            WHEN 'intermediate' THEN 2
+            ELSE 1
+        END
+    )::bigint AS proficiency_score
+FROM
+    tasks t
+JOIN
+    projects p ON t.project_id = p.id
+JOIN
+    task_required_skills trs ON trs.task_id = t.id
+JOIN
+    user_skills us ON us.skill_id = trs.skill_id AND us.user_id = sqlc.arg(engineer_id)
+WHERE
+    p.team_id = sqlc.arg(team_id)
+    AND p.archived = false
+    AND t.status = 'open'
+    AND t.assignee_id IS NULL
+    AND t.archived = false
+GROUP BY
+    t.id, p.id
+ORDER BY
+    matching_skill_count DESC,
+    proficiency_score DESC,
+    t.id
+LIMIT sqlc.arg(result_limit);
+
+-- name: ListTasksByProjectIDs :many
+-- Lists active tasks for a set of projects in one round trip, grouped by project then newest first
+SELECT
+    t.id,
+    t.project_id,
+    t.title,
+    t.status,
+    t.priority,
+    t.assignee_id,
+    u.name AS assignee_name
+FROM
+    tasks t
+LEFT JOIN
+    users u ON t.assignee_id = u.id
+WHERE
+    t.project_id = ANY(sqlc.arg(project_ids)::bigint[])
+    AND t.archived = false
+ORDER BY
+    t.project_id,
+    t.created_at DESC
+LIMIT sqlc.arg(task_limit);
+
+-- name: SearchTasksByTeam :many
+-- Searches the tasks in all of a team's projects, with optional status, priority and assignee filters.
+-- The query matches title and description as full text, or any part of the title; an empty query matches every task.
+-- title_pattern is the query as a LIKE pattern, with its wildcards escaped by the caller.
+-- Best full-text matches come first, then newest first.
+SELECT
+    t.id,
+    t.project_id,
+    p.project_name,
+    t.title,
+    t.description,
+    t.status,
+    t.priority,
+    t.assignee_id,
+    u.name AS assignee_name,
+    t.due_date,
+    t.archived,
+    t.created_at
+FROM
+    tasks t
+JOIN
+    projects p ON t.project_id = p.id
+LEFT JOIN
+    users u ON t.assignee_id = u.id
+WHERE
+    p.team_id = sqlc.arg(team_id)
+    AND (
+        sqlc.arg(query)::text = ''
+        OR to_tsvector('english', t.title || ' ' || COALESCE(t.description, '')) @@ plainto_tsquery('english', sqlc.arg(query))
+        OR t.title ILIKE sqlc.arg(title_pattern) ESCAPE '\'
+    )
+    AND (sqlc.narg(status)::task_status IS NULL OR t.status = sqlc.narg(status))
+    AND (sqlc.narg(priority)::task_priority IS NULL OR t.priority = sqlc.narg(priority))
+    AND (sqlc.narg(assignee_id)::bigint IS NULL OR t.assignee_id = sqlc.narg(assignee_id))
+    AND t.archived = sqlc.arg(archived)
+ORDER BY
+    ts_rank(to_tsvector('english', t.title || ' ' || COALESCE(t.description, '')), plainto_tsquery('english', sqlc.arg(query))) DESC,
+    t.created_at DESC,
+    t.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountSearchTasksByTeam :one
+-- Counts the tasks SearchTasksByTeam matches, for pagination.
+SELECT count(*)
+FROM
+    tasks t
+JOIN
+    projects p ON t.project_id = p.id
+WHERE
+    p.team_id = sqlc.arg(team_id)
+    AND (
+        sqlc.arg(query)::text = ''
+        OR to_tsvector('english', t.title || ' ' || COALESCE(t.description, '')) @@ plainto_tsquery('english', sqlc.arg(query))
+        OR t.title ILIKE sqlc.arg(title_pattern) ESCAPE '\'
+    )
+    AND (sqlc.narg(status)::task_status IS NULL OR t.status = sqlc.narg(status))
+    AND (sqlc.narg(priority)::task_priority IS NULL OR t.priority = sqlc.narg(priority))
+    AND (sqlc.narg(assignee_id)::bigint IS NULL OR t.assignee_id = sqlc.narg(assignee_id))
+    AND t.archived = sqlc.arg(archived);
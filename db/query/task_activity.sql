@@ -0,0 +1,46 @@
+-- SQLC-formatted queries for the "task_activity" table.
+-- These follow the conventions for use with the sqlc tool.
+
+-- name: CreateArchiveActivityByProject :exec
+-- Records an 'archived' event for every task ArchiveCompletedTasksByProject is about to archive.
+-- Run it first, in the same transaction, so both see the same set of tasks.
+INSERT INTO task_activity (task_id, actor_id, event)
+SELECT id, sqlc.narg(actor_id)::bigint, 'archived'
+FROM tasks
+WHERE project_id = sqlc.arg(project_id) AND status = 'done' AND archived = false;
+
+-- name: CreateTaskActivity :one
+-- Records one event on a task. assignee_id, the statuses and the priorities are NULL unless the event uses them.
+INSERT INTO task_activity (
+    task_id,
+    actor_id,
+    event,
+    assignee_id,
+    from_status,
+    to_status,
+    from_priority,
+    to_priority
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: CreateUnarchiveActivityByProject :exec
+-- Records a 'status_changed' event for every task UnarchiveTasksByProject is about to reopen.
+-- Run it first, in the same transaction, so both see the same set of tasks.
+INSERT INTO task_activity (task_id, actor_id, event, from_status, to_status)
+SELECT id, sqlc.narg(actor_id)::bigint, 'status_changed', status, 'open'
+FROM tasks
+WHERE project_id = sqlc.arg(project_id) AND archived = true AND status NOT IN ('open', 'done');
+
+-- name: ListTaskActivity :many
+-- Lists everything that happened to a task, newest first, with the names of the people involved.
+SELECT
+    ta.id, ta.task_id, ta.actor_id, ta.event, ta.assignee_id, ta.from_status, ta.to_status, ta.created_at,
+    ta.from_priority, ta.to_priority,
+    COALESCE(actor.name, '')::text AS actor_name,
+    COALESCE(assignee.name, '')::text AS assignee_name
+FROM task_activity ta
+LEFT JOIN users actor ON ta.actor_id = actor.id
+LEFT JOIN users assignee ON ta.assignee_id = assignee.id
+WHERE ta.task_id = $1
+ORDER BY ta.created_at DESC, ta.id DESC;
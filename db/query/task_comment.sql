@@ -0,0 +1,57 @@
+-- SQLC-formatted queries for the "task_comments" table.
+-- These follow the conventions for use with the sqlc tool.
+
+-- name: CountTaskCommentsByTask :one
+-- Counts all comments on a task, replies included, for pagination.
+SELECT count(*) FROM task_comments
+WHERE task_id = $1;
+
+-- name: CreateTaskComment :one
+-- Adds a comment to a task. parent_id is NULL for a top-level comment.
+INSERT INTO task_comments (
+    task_id,
+    author_id,
+    parent_id,
+    body
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: DeleteTaskComment :exec
+-- Soft-deletes a comment: its body is cleared and deleted_at set, but the row stays
+-- so replies by other users keep their place in the thread.
+UPDATE task_comments
+SET
+    body = '',
+    deleted_at = now()
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetTaskComment :one
+-- Retrieves a single comment by its ID.
+SELECT * FROM task_comments
+WHERE id = $1 LIMIT 1;
+
+-- name: ListTaskCommentsByTask :many
+-- Lists a task's comments oldest first with their author's name.
+-- Replies carry their parent_id, so clients can build the thread from this flat list.
+-- Deleted comments are kept, with an empty body and deleted_at set, so their replies stay attached.
+SELECT
+    tc.id,
+    tc.task_id,
+    tc.author_id,
+    tc.parent_id,
+    tc.body,
+    tc.created_at,
+    tc.deleted_at,
+    u.name AS author_name
+FROM
+    task_comments tc
+JOIN
+    users u ON u.id = tc.author_id
+WHERE
+    tc.task_id = $1
+ORDER BY
+    tc.created_at,
+    tc.id
+LIMIT $2
+OFFSET $3;
@@ -3,13 +3,37 @@
 
 -- name: AddSkillToTask :one
 -- Adds a required skill to a specific task.
+-- A NULL weight stores the column default of 1, i.e. a core skill.
 INSERT INTO task_required_skills (
     task_id,
-    skill_id
+    skill_id,
+    weight
 ) VALUES (
-    $1, $2
+    $1, $2, COALESCE(sqlc.narg(weight)::double precision, 1)
 ) RETURNING *;
 
+-- name: GetCoOccurringSkills :many
+-- Counts how often each other skill is required by the same tasks as the given skill.
+-- The most frequent companions come first, which makes them good "related skill" suggestions.
+SELECT
+    s.id,
+    s.skill_name,
+    s.is_verified,
+    COUNT(*) AS co_occurrences
+FROM
+    task_required_skills base
+JOIN
+    task_required_skills other ON other.task_id = base.task_id AND other.skill_id <> base.skill_id
+JOIN
+    skills s ON s.id = other.skill_id
+WHERE
+    base.skill_id = sqlc.arg(skill_id)
+GROUP BY
+    s.id, s.skill_name, s.is_verified
+ORDER BY
+    co_occurrences DESC, s.skill_name
+LIMIT sqlc.arg(result_limit);
+
 -- name: GetSkillsForTask :many
 -- Retrieves all skills required for a specific task by joining with the skills table.
 SELECT s.* FROM skills s
@@ -22,7 +46,51 @@ SELECT t.* FROM tasks t
 JOIN task_required_skills trs ON t.id = trs.task_id
 WHERE trs.skill_id = $1;
 
+-- name: ListSkillGapsByTeam :many
+-- For each skill the team's open tasks require, counts the open tasks demanding it
+-- and the team's engineers who have it. Skills nobody covers come first, then the most demanded.
+WITH demand AS (
+    SELECT trs.skill_id, count(DISTINCT trs.task_id) AS open_task_count
+    FROM task_required_skills trs
+    JOIN tasks t ON trs.task_id = t.id
+    JOIN projects p ON t.project_id = p.id
+    WHERE p.team_id = sqlc.arg(team_id)
+      AND t.status = 'open'
+      AND t.archived = false
+    GROUP BY trs.skill_id
+),
+coverage AS (
+    SELECT us.skill_id, count(*) AS member_count
+    FROM user_skills us
+    JOIN users u ON us.user_id = u.id
+    WHERE u.team_id = sqlc.arg(team_id)
+      AND u.role = 'engineer'
+    GROUP BY us.skill_id
+)
+SELECT
+    s.id AS skill_id,
+    s.skill_name,
+    d.open_task_count,
+    COALESCE(c.member_count, 0)::bigint AS member_count
+FROM demand d
+JOIN skills s ON s.id = d.skill_id
+LEFT JOIN coverage c ON c.skill_id = d.skill_id
+ORDER BY member_count, d.open_task_count DESC, s.skill_name;
+
+-- name: ListTaskRequiredSkills :many
+-- Lists a task's required skills with their weights.
+SELECT * FROM task_required_skills
+WHERE task_id = $1
+ORDER BY skill_id;
+
 -- name: RemoveSkillFromTask :exec
 -- Removes a required skill from a specific task.
 DELETE FROM task_required_skills
 WHERE task_id = $1 AND skill_id = $2;
+
+-- name: RepointTaskRequiredSkills :execrows
+-- Moves tasks from the source skill to the target skill, skipping tasks that already require the target.
+UPDATE task_required_skills
+SET skill_id = sqlc.arg(target_id)
+WHERE skill_id = sqlc.arg(source_id)
+  AND task_id NOT IN (SELECT task_id FROM task_required_skills WHERE skill_id = sqlc.arg(target_id));
@@ -50,6 +50,33 @@ WHERE id = $1;
 SELECT * FROM teams
 WHERE manager_id = $1 LIMIT 1;
 
+-- Get a team together with its manager's contact details.
+-- The LEFT JOIN keeps teams without a manager, leaving the manager columns NULL.
+-- name: GetTeamManagerContact :one
+SELECT
+    t.id AS team_id,
+    t.team_name,
+    u.id AS manager_id,
+    u.name AS manager_name,
+    u.email AS manager_email
+FROM teams t
+LEFT JOIN users u ON t.manager_id = u.id
+WHERE t.id = $1;
+
+-- name: GetTeamOverviewStats :one
+-- Counts a team's projects and live tasks for the manager overview in one round trip.
+-- Overdue tasks are counted as in CountOverdueTasksByTeam; tasks without a due date never are.
+SELECT
+    (SELECT count(*) FROM projects p
+     WHERE p.team_id = sqlc.arg(team_id) AND p.archived = false) AS active_projects,
+    (SELECT count(*) FROM projects p
+     WHERE p.team_id = sqlc.arg(team_id) AND p.archived = true) AS archived_projects,
+    (SELECT count(*) FROM tasks t JOIN projects p ON t.project_id = p.id
+     WHERE p.team_id = sqlc.arg(team_id) AND t.status = 'open' AND t.archived = false) AS open_tasks,
+    (SELECT count(*) FROM tasks t JOIN projects p ON t.project_id = p.id
+     WHERE p.team_id = sqlc.arg(team_id) AND t.status <> 'done' AND t.archived = false
+       AND t.due_date < now()) AS overdue_tasks;
+
 -- List all teams and include their manager's details.
 -- This uses a LEFT JOIN to ensure teams without a manager are still included.
 -- This is useful for UI displays to avoid separate lookups for manager names.
@@ -74,3 +101,27 @@ UPDATE teams
 SET manager_id = $2
 WHERE id = $1
 RETURNING *;
+
+-- name: SetTeamAutoAssign :one
+-- Enables or disables automatic assignment of new tasks for a team.
+UPDATE teams
+SET auto_assign = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateTeamSettings :one
+-- Updates a team's task settings. Only non-NULL arguments change the stored value;
+-- the clear flags reset a default back to NULL, meaning "no team default".
+UPDATE teams
+SET
+  auto_assign = COALESCE(sqlc.narg(auto_assign), auto_assign),
+  default_task_priority = CASE
+    WHEN sqlc.arg(clear_default_task_priority)::bool THEN NULL
+    ELSE COALESCE(sqlc.narg(default_task_priority), default_task_priority)
+  END,
+  default_task_status = CASE
+    WHEN sqlc.arg(clear_default_task_status)::bool THEN NULL
+    ELSE COALESCE(sqlc.narg(default_task_status), default_task_status)
+  END
+WHERE id = sqlc.arg(id)
+RETURNING *;
@@ -30,6 +30,11 @@ ORDER BY id
 LIMIT $1
 OFFSET $2;
 
+-- name: ListUsersByIDs :many
+-- Retrieves all users with the given IDs in one query. IDs with no user are skipped.
+SELECT * FROM users
+WHERE id = ANY(sqlc.arg(ids)::bigint[]);
+
 -- name: ListUsersByTeam :many
 -- Retrieves a paginated list of all users belonging to a specific team.
 SELECT * FROM users
@@ -50,12 +55,40 @@ SET
 WHERE id = sqlc.arg(id)
 RETURNING *;
 
+-- name: MarkUserBusyIfAvailable :one
+-- Marks an available user busy; returns no rows if they are not available, so concurrent claims serialize on the user row.
+UPDATE users
+SET availability = 'busy'
+WHERE id = $1 AND availability = 'available'
+RETURNING *;
+
+-- name: SetUserAvailabilityUnlessOnLeave :one
+-- Sets a user's availability unless they are on leave; returns no rows if they are, so assignments never override a leave.
+UPDATE users
+SET availability = $2
+WHERE id = $1 AND availability <> 'on_leave'
+RETURNING *;
+
+-- name: UpdateAvailabilityIfNotBusy :one
+-- Lets a user switch between available and on leave; returns no rows while they are busy with a task.
+UPDATE users
+SET availability = $2
+WHERE id = $1 AND availability <> 'busy'
+RETURNING *;
+
 -- name: RemoveUserFromTeam :one
 UPDATE users
 SET team_id = NULL
 WHERE id = $1
 RETURNING *;
 
+-- name: RemoveUsersFromTeam :many
+-- Unassigns every member of a team and returns them; used when the team itself is deleted.
+UPDATE users
+SET team_id = NULL
+WHERE team_id = $1
+RETURNING *;
+
 -- name: DeleteUser :exec
 -- Deletes a user from the database by their ID.
 DELETE FROM users
@@ -65,36 +98,34 @@ WHERE id = $1;
 -- name: CountUsers :one
 SELECT count(*) FROM users;
 
--- Counts users whose name or email matches a search string and optionally filters by role
+-- Counts users whose name or email matches a search string, with optional role, team and has-team filters
 -- name: CountSearchUsers :one
 SELECT count(*) FROM users 
 WHERE (
-    $1::text = '' OR 
-    LOWER(name) LIKE LOWER($1) OR 
-    LOWER(email) LIKE LOWER($1)
+    sqlc.arg(search)::text = '' OR 
+    LOWER(name) LIKE LOWER(sqlc.arg(search)) OR 
+    LOWER(email) LIKE LOWER(sqlc.arg(search))
 )
-AND (
-    $2::text = '' OR 
-    role = $2::user_role
-);
+AND (sqlc.narg(role)::user_role IS NULL OR role = sqlc.narg(role))
+AND (sqlc.narg(team_id)::bigint IS NULL OR team_id = sqlc.narg(team_id))
+AND (sqlc.narg(has_team)::boolean IS NULL OR (team_id IS NOT NULL) = sqlc.narg(has_team));
 
--- Retrieves a paginated list of users with team names, filtered by search string and optional role
+-- Retrieves a paginated list of users with team names, filtered by search string and optional role, team and has-team filters
 -- name: SearchUsers :many
 SELECT u.id, u.name, u.email, u.role, u.team_id, u.availability,
        t.team_name
 FROM users u
 LEFT JOIN teams t ON u.team_id = t.id
 WHERE (
-    $1::text = '' OR 
-    LOWER(u.name) LIKE LOWER($1) OR 
-    LOWER(u.email) LIKE LOWER($1)
-)
-AND (
-    $2::text = '' OR 
-    u.role = $2::user_role
+    sqlc.arg(search)::text = '' OR 
+    LOWER(u.name) LIKE LOWER(sqlc.arg(search)) OR 
+    LOWER(u.email) LIKE LOWER(sqlc.arg(search))
 )
+AND (sqlc.narg(role)::user_role IS NULL OR u.role = sqlc.narg(role))
+AND (sqlc.narg(team_id)::bigint IS NULL OR u.team_id = sqlc.narg(team_id))
+AND (sqlc.narg(has_team)::boolean IS NULL OR (u.team_id IS NOT NULL) = sqlc.narg(has_team))
 ORDER BY u.id
-LIMIT $3 OFFSET $4;
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- Gets a specific user's details along with their team name
 -- name: GetUserWithTeamAndSkills :one
@@ -119,6 +150,12 @@ SET role = $2
 WHERE id = $1
 RETURNING id, name, email, team_id, availability, password_hash, role;
 
+-- Replaces a user's password hash, e.g. after a password reset
+-- name: UpdateUserPassword :exec
+UPDATE users
+SET password_hash = $2
+WHERE id = $1;
+
 -- Updates the team assignment of a user and returns their updated information
 -- name: UpdateUserTeam :one
 UPDATE users
@@ -25,14 +25,108 @@ SELECT u.id, u.name, u.email, us.proficiency FROM users u
 JOIN user_skills us ON u.id = us.user_id
 WHERE us.skill_id = $1;
 
+-- name: GetTeamMembersWithSkillMatch :many
+-- Lists a team's engineers' proficiency in each of the given skills they have, one row per engineer and skill.
+-- Used to rank engineers locally when the recommender service is unavailable.
+SELECT
+    u.id AS user_id,
+    u.name,
+    u.email,
+    u.availability,
+    us.skill_id,
+    us.proficiency
+FROM
+    users u
+JOIN
+    user_skills us ON us.user_id = u.id
+WHERE
+    u.team_id = sqlc.arg(team_id)
+    AND u.role = 'engineer'
+    AND us.skill_id = ANY(sqlc.arg(skill_ids)::bigint[])
+ORDER BY
+    u.id,
+    us.skill_id;
+
+-- name: ListEngineersForSkills :many
+-- Lists a team's engineers who have at least one of the given skills.
+-- Engineers are ranked by how many of the skills they have, then by their proficiency in them.
+SELECT
+    u.id,
+    u.name,
+    u.email,
+    u.availability,
+    count(*) AS matching_skill_count,
+    SUM(
+        CASE us.proficiency
+            WHEN 'expert' THEN 3
+            WHEN 'intermediate' THEN 2
+            ELSE 1
+        END
+    )::bigint AS proficiency_score
+FROM
+    users u
+JOIN
+    user_skills us ON us.user_id = u.id
+WHERE
+    u.team_id = sqlc.arg(team_id)
+    AND u.role = 'engineer'
+    AND us.skill_id = ANY(sqlc.arg(skill_ids)::bigint[])
+GROUP BY
+    u.id
+ORDER BY
+    matching_skill_count DESC,
+    proficiency_score DESC,
+    u.id;
+
+-- name: ListUserSkills :many
+-- Lists a user's skill links, including whether each was curated by hand.
+SELECT * FROM user_skills
+WHERE user_id = $1
+ORDER BY skill_id;
+
+-- name: MarkUserSkillManual :one
+-- Marks a user's skill as added or endorsed by a person, so reprocessing keeps it.
+UPDATE user_skills
+SET is_manual = true
+WHERE user_id = $1 AND skill_id = $2
+RETURNING *;
+
 -- name: UpdateUserSkillProficiency :one
 -- Updates a user's proficiency level for a specific skill.
+-- The skill counts as endorsed by a person from then on, so reprocessing keeps it.
 UPDATE user_skills
-SET proficiency = $3
+SET proficiency = $3, is_manual = true
 WHERE user_id = $1 AND skill_id = $2
 RETURNING *;
 
--- name: RemoveSkillFromUser :exec
--- Removes a skill from a user.
+-- name: RaiseUserSkillProficiency :one
+-- Raises a user's proficiency in a skill to the given level, leaving a higher one as it is.
+-- Unlike UpdateUserSkillProficiency the manual flag is untouched, since the level comes from a resume.
+UPDATE user_skills
+SET proficiency = GREATEST(proficiency, sqlc.arg(proficiency)::proficiency_level)
+WHERE user_id = sqlc.arg(user_id) AND skill_id = sqlc.arg(skill_id)
+RETURNING *;
+
+-- name: RemoveSkillFromUser :execrows
+-- Removes a skill from a user, returning how many links were deleted.
 DELETE FROM user_skills
 WHERE user_id = $1 AND skill_id = $2;
+
+-- name: MergeUserSkillProficiencies :execrows
+-- For users holding both skills of a merge, keeps the higher proficiency on the target skill.
+-- A manual flag on either row carries over, so the merged skill stays protected from reprocessing.
+UPDATE user_skills target
+SET
+    proficiency = GREATEST(target.proficiency, source.proficiency),
+    is_manual = target.is_manual OR source.is_manual
+FROM user_skills source
+WHERE target.skill_id = sqlc.arg(target_id)
+  AND source.skill_id = sqlc.arg(source_id)
+  AND source.user_id = target.user_id;
+
+-- name: RepointUserSkills :execrows
+-- Moves users from the source skill to the target skill, skipping users who already have the target.
+UPDATE user_skills
+SET skill_id = sqlc.arg(target_id)
+WHERE skill_id = sqlc.arg(source_id)
+  AND user_id NOT IN (SELECT user_id FROM user_skills WHERE skill_id = sqlc.arg(target_id));
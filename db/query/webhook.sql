@@ -0,0 +1,48 @@
+-- SQLC-formatted queries for the "webhooks" table.
+-- These follow the conventions for use with the sqlc tool.
+
+-- name: CreateWebhook :one
+-- Registers a webhook for a team's events.
+INSERT INTO webhooks (
+    team_id,
+    url,
+    secret,
+    event_types
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetWebhook :one
+-- Fetches a single webhook by its ID.
+SELECT * FROM webhooks
+WHERE id = $1;
+
+-- name: ListWebhooks :many
+-- Lists webhooks, optionally only one team's, oldest first.
+SELECT * FROM webhooks
+WHERE sqlc.narg(team_id)::bigint IS NULL OR team_id = sqlc.narg(team_id)
+ORDER BY id;
+
+-- name: ListActiveWebhooksForEvent :many
+-- Lists a team's active webhooks subscribed to an event type.
+SELECT * FROM webhooks
+WHERE team_id = sqlc.arg(team_id)
+  AND active = true
+  AND sqlc.arg(event_type)::text = ANY(event_types)
+ORDER BY id;
+
+-- name: UpdateWebhook :one
+-- Updates any of a webhook's URL, secret, event types and active flag.
+UPDATE webhooks
+SET
+    url = COALESCE(sqlc.narg(url), url),
+    secret = COALESCE(sqlc.narg(secret), secret),
+    event_types = COALESCE(sqlc.narg(event_types), event_types),
+    active = COALESCE(sqlc.narg(active), active)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteWebhook :execrows
+-- Deletes a webhook. Returns the number of rows deleted so callers can tell whether it existed.
+DELETE FROM webhooks
+WHERE id = $1;
@@ -12,22 +12,52 @@ import (
 )
 
 const countAllInvitations = `-- name: CountAllInvitations :one
-SELECT count(*) FROM invitations
+SELECT count(*) FROM invitations i
+WHERE
+    i.created_at BETWEEN COALESCE($1::timestamp, '-infinity'::timestamp)
+        AND COALESCE($2::timestamp, 'infinity'::timestamp)
 `
 
-func (q *Queries) CountAllInvitations(ctx context.Context) (int64, error) {
-	row := q.db.QueryRow(ctx, countAllInvitations)
+type CountAllInvitationsParams struct {
+	CreatedFrom pgtype.Timestamp `json:"created_from"`
+	CreatedTo   pgtype.Timestamp `json:"created_to"`
+}
+
+func (q *Queries) CountAllInvitations(ctx context.Context, arg CountAllInvitationsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countAllInvitations, arg.CreatedFrom, arg.CreatedTo)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countInvitationInviters = `-- name: CountInvitationInviters :one
+SELECT count(DISTINCT inviter_id) FROM invitations
+`
+
+// Returns the number of distinct inviters, for paginating ListInvitationStatsByInviter.
+func (q *Queries) CountInvitationInviters(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countInvitationInviters)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
 const countInvitationsByInviter = `-- name: CountInvitationsByInviter :one
-SELECT count(*) FROM invitations WHERE inviter_id = $1
+SELECT count(*) FROM invitations i
+WHERE
+    i.inviter_id = $1
+    AND i.created_at BETWEEN COALESCE($2::timestamp, '-infinity'::timestamp)
+        AND COALESCE($3::timestamp, 'infinity'::timestamp)
 `
 
-func (q *Queries) CountInvitationsByInviter(ctx context.Context, inviterID int64) (int64, error) {
-	row := q.db.QueryRow(ctx, countInvitationsByInviter, inviterID)
+type CountInvitationsByInviterParams struct {
+	InviterID   int64            `json:"inviter_id"`
+	CreatedFrom pgtype.Timestamp `json:"created_from"`
+	CreatedTo   pgtype.Timestamp `json:"created_to"`
+}
+
+func (q *Queries) CountInvitationsByInviter(ctx context.Context, arg CountInvitationsByInviterParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countInvitationsByInviter, arg.InviterID, arg.CreatedFrom, arg.CreatedTo)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -39,10 +69,31 @@ FROM invitations i
 LEFT JOIN
     users u ON i.inviter_id = u.id
 WHERE u.role = $1
+    AND i.created_at BETWEEN COALESCE($2::timestamp, '-infinity'::timestamp)
+        AND COALESCE($3::timestamp, 'infinity'::timestamp)
+`
+
+type CountInvitationsByInviterRoleParams struct {
+	Role        UserRole         `json:"role"`
+	CreatedFrom pgtype.Timestamp `json:"created_from"`
+	CreatedTo   pgtype.Timestamp `json:"created_to"`
+}
+
+func (q *Queries) CountInvitationsByInviterRole(ctx context.Context, arg CountInvitationsByInviterRoleParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countInvitationsByInviterRole, arg.Role, arg.CreatedFrom, arg.CreatedTo)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countInvitationsByTeam = `-- name: CountInvitationsByTeam :one
+SELECT count(*) FROM invitations
+WHERE team_id = $1
 `
 
-func (q *Queries) CountInvitationsByInviterRole(ctx context.Context, role UserRole) (int64, error) {
-	row := q.db.QueryRow(ctx, countInvitationsByInviterRole, role)
+// Counts every invitation to a team, whatever its status.
+func (q *Queries) CountInvitationsByTeam(ctx context.Context, teamID pgtype.Int8) (int64, error) {
+	row := q.db.QueryRow(ctx, countInvitationsByTeam, teamID)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -127,6 +178,36 @@ func (q *Queries) DeleteInvitation(ctx context.Context, id int64) error {
 	return err
 }
 
+const deleteInvitationsByTeam = `-- name: DeleteInvitationsByTeam :execrows
+DELETE FROM invitations
+WHERE team_id = $1
+`
+
+// Deletes every invitation to a team, whatever its status; used when the team itself is deleted.
+func (q *Queries) DeleteInvitationsByTeam(ctx context.Context, teamID pgtype.Int8) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteInvitationsByTeam, teamID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const expirePendingInvitations = `-- name: ExpirePendingInvitations :execrows
+UPDATE invitations
+SET status = 'expired'
+WHERE status = 'pending' AND expires_at < now()
+`
+
+// Marks pending invitations past their expiry as expired.
+// Returns the number of invitations expired.
+func (q *Queries) ExpirePendingInvitations(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, expirePendingInvitations)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const getInvitationByEmail = `-- name: GetInvitationByEmail :one
 SELECT
     i.id, i.email, i.invitation_token, i.role_to_invite, i.inviter_id, i.status, i.created_at, i.expires_at, i.team_id,
@@ -269,6 +350,38 @@ func (q *Queries) GetInvitationByToken(ctx context.Context, invitationToken stri
 	return i, err
 }
 
+const getPendingInvitationByToken = `-- name: GetPendingInvitationByToken :one
+SELECT
+    i.email, i.role_to_invite, i.team_id,
+    (i.expires_at <= now())::bool AS expired
+FROM
+    invitations i
+WHERE
+    i.invitation_token = $1 AND i.status = 'pending'
+LIMIT 1
+`
+
+type GetPendingInvitationByTokenRow struct {
+	Email        string      `json:"email"`
+	RoleToInvite UserRole    `json:"role_to_invite"`
+	TeamID       pgtype.Int8 `json:"team_id"`
+	Expired      bool        `json:"expired"`
+}
+
+// Retrieves a pending invitation by its token, including one whose window has lapsed.
+// expired is decided by the database clock, the same one GetInvitationByToken filters with.
+func (q *Queries) GetPendingInvitationByToken(ctx context.Context, invitationToken string) (GetPendingInvitationByTokenRow, error) {
+	row := q.db.QueryRow(ctx, getPendingInvitationByToken, invitationToken)
+	var i GetPendingInvitationByTokenRow
+	err := row.Scan(
+		&i.Email,
+		&i.RoleToInvite,
+		&i.TeamID,
+		&i.Expired,
+	)
+	return i, err
+}
+
 const listAllInvitations = `-- name: ListAllInvitations :many
 
 SELECT
@@ -280,6 +393,9 @@ FROM
     invitations i
 LEFT JOIN
     users u ON i.inviter_id = u.id
+WHERE
+    i.created_at BETWEEN COALESCE($3::timestamp, '-infinity'::timestamp)
+        AND COALESCE($4::timestamp, 'infinity'::timestamp)
 ORDER BY
     i.created_at DESC
 LIMIT $1
@@ -287,8 +403,10 @@ OFFSET $2
 `
 
 type ListAllInvitationsParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+	Limit       int32            `json:"limit"`
+	Offset      int32            `json:"offset"`
+	CreatedFrom pgtype.Timestamp `json:"created_from"`
+	CreatedTo   pgtype.Timestamp `json:"created_to"`
 }
 
 type ListAllInvitationsRow struct {
@@ -310,7 +428,12 @@ type ListAllInvitationsRow struct {
 // Invitation List Queries (Admin & Manager)
 // ----------------------------------------------------------------
 func (q *Queries) ListAllInvitations(ctx context.Context, arg ListAllInvitationsParams) ([]ListAllInvitationsRow, error) {
-	rows, err := q.db.Query(ctx, listAllInvitations, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, listAllInvitations,
+		arg.Limit,
+		arg.Offset,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -342,6 +465,91 @@ func (q *Queries) ListAllInvitations(ctx context.Context, arg ListAllInvitations
 	return items, nil
 }
 
+const listInvitationStatsByInviter = `-- name: ListInvitationStatsByInviter :many
+
+SELECT
+    i.inviter_id,
+    COALESCE(u.name, '') AS inviter_name,
+    COALESCE(u.email, '') AS inviter_email,
+    COALESCE(u.role::text, 'unknown')::text AS inviter_role,
+    count(*) AS sent_count,
+    count(*) FILTER (WHERE i.status = 'accepted') AS accepted_count,
+    count(*) FILTER (WHERE i.status = 'pending' AND i.expires_at >= NOW()) AS pending_count,
+    count(*) FILTER (WHERE i.status = 'expired' OR (i.status = 'pending' AND i.expires_at < NOW())) AS expired_count,
+    count(*) FILTER (WHERE i.status = 'revoked') AS revoked_count,
+    ((count(*) FILTER (WHERE i.status = 'accepted'))::float8 / count(*))::float8 AS acceptance_rate
+FROM
+    invitations i
+LEFT JOIN
+    users u ON i.inviter_id = u.id
+GROUP BY
+    i.inviter_id, u.name, u.email, u.role
+ORDER BY
+    CASE WHEN $3::text = 'acceptance_rate'
+        THEN (count(*) FILTER (WHERE i.status = 'accepted'))::float8 / count(*)
+    END DESC,
+    sent_count DESC,
+    i.inviter_id
+LIMIT $1
+OFFSET $2
+`
+
+type ListInvitationStatsByInviterParams struct {
+	Limit  int32  `json:"limit"`
+	Offset int32  `json:"offset"`
+	SortBy string `json:"sort_by"`
+}
+
+type ListInvitationStatsByInviterRow struct {
+	InviterID      int64   `json:"inviter_id"`
+	InviterName    string  `json:"inviter_name"`
+	InviterEmail   string  `json:"inviter_email"`
+	InviterRole    string  `json:"inviter_role"`
+	SentCount      int64   `json:"sent_count"`
+	AcceptedCount  int64   `json:"accepted_count"`
+	PendingCount   int64   `json:"pending_count"`
+	ExpiredCount   int64   `json:"expired_count"`
+	RevokedCount   int64   `json:"revoked_count"`
+	AcceptanceRate float64 `json:"acceptance_rate"`
+}
+
+// ----------------------------------------------------------------
+// Invitation Statistics (Admin)
+// ----------------------------------------------------------------
+// Summarizes each inviter's invitations by outcome, with the share that was accepted.
+// Pending invitations past their expiry count as expired even before a job marks them so.
+// Sorts by acceptance rate when sort_by is 'acceptance_rate', otherwise by invitations sent.
+func (q *Queries) ListInvitationStatsByInviter(ctx context.Context, arg ListInvitationStatsByInviterParams) ([]ListInvitationStatsByInviterRow, error) {
+	rows, err := q.db.Query(ctx, listInvitationStatsByInviter, arg.Limit, arg.Offset, arg.SortBy)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInvitationStatsByInviterRow
+	for rows.Next() {
+		var i ListInvitationStatsByInviterRow
+		if err := rows.Scan(
+			&i.InviterID,
+			&i.InviterName,
+			&i.InviterEmail,
+			&i.InviterRole,
+			&i.SentCount,
+			&i.AcceptedCount,
+			&i.PendingCount,
+			&i.ExpiredCount,
+			&i.RevokedCount,
+			&i.AcceptanceRate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listInvitationsByInviter = `-- name: ListInvitationsByInviter :many
 SELECT
     i.id, i.email, i.invitation_token, i.role_to_invite, i.inviter_id, i.status, i.created_at, i.expires_at, i.team_id,
@@ -354,6 +562,8 @@ LEFT JOIN
     users u ON i.inviter_id = u.id
 WHERE
     i.inviter_id = $1
+    AND i.created_at BETWEEN COALESCE($4::timestamp, '-infinity'::timestamp)
+        AND COALESCE($5::timestamp, 'infinity'::timestamp)
 ORDER BY
     i.created_at DESC
 LIMIT $2
@@ -361,9 +571,11 @@ OFFSET $3
 `
 
 type ListInvitationsByInviterParams struct {
-	InviterID int64 `json:"inviter_id"`
-	Limit     int32 `json:"limit"`
-	Offset    int32 `json:"offset"`
+	InviterID   int64            `json:"inviter_id"`
+	Limit       int32            `json:"limit"`
+	Offset      int32            `json:"offset"`
+	CreatedFrom pgtype.Timestamp `json:"created_from"`
+	CreatedTo   pgtype.Timestamp `json:"created_to"`
 }
 
 type ListInvitationsByInviterRow struct {
@@ -382,7 +594,13 @@ type ListInvitationsByInviterRow struct {
 }
 
 func (q *Queries) ListInvitationsByInviter(ctx context.Context, arg ListInvitationsByInviterParams) ([]ListInvitationsByInviterRow, error) {
-	rows, err := q.db.Query(ctx, listInvitationsByInviter, arg.InviterID, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, listInvitationsByInviter,
+		arg.InviterID,
+		arg.Limit,
+		arg.Offset,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -426,6 +644,8 @@ LEFT JOIN
     users u ON i.inviter_id = u.id
 WHERE
     u.role = $1
+    AND i.created_at BETWEEN COALESCE($4::timestamp, '-infinity'::timestamp)
+        AND COALESCE($5::timestamp, 'infinity'::timestamp)
 ORDER BY
     i.created_at DESC
 LIMIT $2
@@ -433,9 +653,11 @@ OFFSET $3
 `
 
 type ListInvitationsByInviterRoleParams struct {
-	Role   UserRole `json:"role"`
-	Limit  int32    `json:"limit"`
-	Offset int32    `json:"offset"`
+	Role        UserRole         `json:"role"`
+	Limit       int32            `json:"limit"`
+	Offset      int32            `json:"offset"`
+	CreatedFrom pgtype.Timestamp `json:"created_from"`
+	CreatedTo   pgtype.Timestamp `json:"created_to"`
 }
 
 type ListInvitationsByInviterRoleRow struct {
@@ -454,7 +676,13 @@ type ListInvitationsByInviterRoleRow struct {
 }
 
 func (q *Queries) ListInvitationsByInviterRole(ctx context.Context, arg ListInvitationsByInviterRoleParams) ([]ListInvitationsByInviterRoleRow, error) {
-	rows, err := q.db.Query(ctx, listInvitationsByInviterRole, arg.Role, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, listInvitationsByInviterRole,
+		arg.Role,
+		arg.Limit,
+		arg.Offset,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -486,6 +714,80 @@ func (q *Queries) ListInvitationsByInviterRole(ctx context.Context, arg ListInvi
 	return items, nil
 }
 
+const refreshInvitationToken = `-- name: RefreshInvitationToken :one
+WITH refreshed_invitation AS (
+    UPDATE invitations
+    SET invitation_token = $2, expires_at = $3
+    WHERE invitations.id = $1 AND invitations.status = 'pending'
+    RETURNING id, email, invitation_token, role_to_invite, inviter_id, status, created_at, expires_at, team_id
+)
+SELECT
+    i.id, i.email, i.invitation_token, i.role_to_invite, i.inviter_id, i.status, i.created_at, i.expires_at, i.team_id,
+    COALESCE(u.name, '') as inviter_name,
+    COALESCE(u.email, '') as inviter_email
+FROM
+    refreshed_invitation i
+LEFT JOIN
+    users u ON i.inviter_id = u.id
+`
+
+type RefreshInvitationTokenParams struct {
+	ID              int64            `json:"id"`
+	InvitationToken string           `json:"invitation_token"`
+	ExpiresAt       pgtype.Timestamp `json:"expires_at"`
+}
+
+type RefreshInvitationTokenRow struct {
+	ID              int64            `json:"id"`
+	Email           string           `json:"email"`
+	InvitationToken string           `json:"invitation_token"`
+	RoleToInvite    UserRole         `json:"role_to_invite"`
+	InviterID       int64            `json:"inviter_id"`
+	Status          string           `json:"status"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	ExpiresAt       pgtype.Timestamp `json:"expires_at"`
+	TeamID          pgtype.Int8      `json:"team_id"`
+	InviterName     string           `json:"inviter_name"`
+	InviterEmail    string           `json:"inviter_email"`
+}
+
+// Gives a pending invitation a new token and expiry, invalidating the old token.
+// Returns no rows if the invitation is no longer pending.
+func (q *Queries) RefreshInvitationToken(ctx context.Context, arg RefreshInvitationTokenParams) (RefreshInvitationTokenRow, error) {
+	row := q.db.QueryRow(ctx, refreshInvitationToken, arg.ID, arg.InvitationToken, arg.ExpiresAt)
+	var i RefreshInvitationTokenRow
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.InvitationToken,
+		&i.RoleToInvite,
+		&i.InviterID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.TeamID,
+		&i.InviterName,
+		&i.InviterEmail,
+	)
+	return i, err
+}
+
+const revokeInvitation = `-- name: RevokeInvitation :execrows
+UPDATE invitations
+SET status = 'revoked'
+WHERE invitations.id = $1 AND invitations.status = 'pending'
+`
+
+// Marks a pending invitation as revoked, keeping the row for auditing.
+// Returns the number of rows updated so callers can detect a non-pending invitation.
+func (q *Queries) RevokeInvitation(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeInvitation, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateInvitationStatus = `-- name: UpdateInvitationStatus :one
 WITH updated_invitation AS (
     UPDATE invitations
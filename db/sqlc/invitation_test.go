@@ -97,6 +97,41 @@ func TestGetInvitationByToken(t *testing.T) {
 
 ////////////////////////////////////////////////////////////////////////
 
+// TestGetPendingInvitationByToken tests that lapsed pending invitations are found and flagged as expired.
+func TestGetPendingInvitationByToken(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		invitation := createRandomInvitation(t)
+
+		pending, err := testQueries.GetPendingInvitationByToken(context.Background(), invitation.InvitationToken)
+		require.NoError(t, err)
+		require.Equal(t, invitation.Email, pending.Email)
+		require.Equal(t, invitation.TeamID, pending.TeamID)
+		require.False(t, pending.Expired)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		expiredInvitation := createExpiredInvitation(t)
+
+		pending, err := testQueries.GetPendingInvitationByToken(context.Background(), expiredInvitation.InvitationToken)
+		require.NoError(t, err)
+		require.True(t, pending.Expired)
+	})
+
+	t.Run("Failure_StatusNotPending", func(t *testing.T) {
+		acceptedInvitation := createRandomInvitation(t)
+		_, err := testQueries.UpdateInvitationStatus(context.Background(), UpdateInvitationStatusParams{
+			ID:     acceptedInvitation.ID,
+			Status: "accepted",
+		})
+		require.NoError(t, err)
+
+		_, err = testQueries.GetPendingInvitationByToken(context.Background(), acceptedInvitation.InvitationToken)
+		require.ErrorIs(t, err, pgx.ErrNoRows)
+	})
+}
+
+////////////////////////////////////////////////////////////////////////
+
 // TestGetInvitationByEmail tests retrieving a pending invitation by email.
 func TestGetInvitationByEmail(t *testing.T) {
 	// Create a standard, valid invitation for use in sub-tests.
@@ -175,3 +210,222 @@ func TestUpdateInvitationStatus(t *testing.T) {
 }
 
 ////////////////////////////////////////////////////////////////////////
+
+// TestListInvitationsByCreatedAtRange checks the optional created_at bounds on list and count queries.
+func TestListInvitationsByCreatedAtRange(t *testing.T) {
+	inviter, _ := createRandomUser(t)
+
+	// Create one invitation per month and backdate each one
+	createdDates := []time.Time{
+		time.Date(2001, time.January, 10, 12, 0, 0, 0, time.UTC),
+		time.Date(2001, time.February, 10, 12, 0, 0, 0, time.UTC),
+		time.Date(2001, time.March, 10, 12, 0, 0, 0, time.UTC),
+	}
+	for _, createdAt := range createdDates {
+		invitation, err := testQueries.CreateInvitation(context.Background(), CreateInvitationParams{
+			Email:           util.RandomEmail(),
+			InvitationToken: util.RandomString(32),
+			RoleToInvite:    UserRoleEngineer,
+			InviterID:       inviter.ID,
+			ExpiresAt:       pgtype.Timestamp{Time: time.Now().Add(24 * time.Hour), Valid: true},
+			TeamID:          inviter.TeamID,
+		})
+		require.NoError(t, err)
+
+		_, err = testPool.Exec(context.Background(), "UPDATE invitations SET created_at = $1 WHERE id = $2", createdAt, invitation.ID)
+		require.NoError(t, err)
+	}
+
+	t.Run("Range restricts list and count", func(t *testing.T) {
+		from := pgtype.Timestamp{Time: time.Date(2001, time.February, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+		to := pgtype.Timestamp{Time: time.Date(2001, time.February, 28, 0, 0, 0, 0, time.UTC), Valid: true}
+
+		invitations, err := testQueries.ListInvitationsByInviter(context.Background(), ListInvitationsByInviterParams{
+			InviterID:   inviter.ID,
+			Limit:       10,
+			Offset:      0,
+			CreatedFrom: from,
+			CreatedTo:   to,
+		})
+		require.NoError(t, err)
+		require.Len(t, invitations, 1)
+		require.True(t, createdDates[1].Equal(invitations[0].CreatedAt.Time))
+
+		count, err := testQueries.CountInvitationsByInviter(context.Background(), CountInvitationsByInviterParams{
+			InviterID:   inviter.ID,
+			CreatedFrom: from,
+			CreatedTo:   to,
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count)
+	})
+
+	t.Run("Open-ended range uses a single bound", func(t *testing.T) {
+		from := pgtype.Timestamp{Time: time.Date(2001, time.February, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+
+		invitations, err := testQueries.ListInvitationsByInviter(context.Background(), ListInvitationsByInviterParams{
+			InviterID:   inviter.ID,
+			Limit:       10,
+			Offset:      0,
+			CreatedFrom: from,
+		})
+		require.NoError(t, err)
+		require.Len(t, invitations, 2)
+
+		count, err := testQueries.CountInvitationsByInviter(context.Background(), CountInvitationsByInviterParams{
+			InviterID:   inviter.ID,
+			CreatedFrom: from,
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), count)
+	})
+
+	t.Run("No range returns everything", func(t *testing.T) {
+		count, err := testQueries.CountInvitationsByInviter(context.Background(), CountInvitationsByInviterParams{
+			InviterID: inviter.ID,
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(3), count)
+	})
+}
+
+////////////////////////////////////////////////////////////////////////
+
+// TestRevokeInvitation checks that a revoked invitation keeps its row but its token stops resolving.
+func TestRevokeInvitation(t *testing.T) {
+	t.Run("Revoked token no longer resolves but row persists", func(t *testing.T) {
+		invitation := createRandomInvitation(t)
+
+		revoked, err := testQueries.RevokeInvitation(context.Background(), invitation.ID)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), revoked)
+
+		// The token must not resolve anymore
+		_, err = testQueries.GetInvitationByToken(context.Background(), invitation.InvitationToken)
+		require.ErrorIs(t, err, pgx.ErrNoRows)
+
+		// But the row is still there for auditing
+		stored, err := testQueries.GetInvitationByID(context.Background(), invitation.ID)
+		require.NoError(t, err)
+		require.Equal(t, "revoked", stored.Status)
+		require.Equal(t, invitation.Email, stored.Email)
+	})
+
+	t.Run("Only pending invitations can be revoked", func(t *testing.T) {
+		invitation := createRandomInvitation(t)
+		_, err := testQueries.UpdateInvitationStatus(context.Background(), UpdateInvitationStatusParams{
+			ID:     invitation.ID,
+			Status: "accepted",
+		})
+		require.NoError(t, err)
+
+		revoked, err := testQueries.RevokeInvitation(context.Background(), invitation.ID)
+		require.NoError(t, err)
+		require.Zero(t, revoked)
+	})
+
+	t.Run("Revoked email can be invited again", func(t *testing.T) {
+		invitation := createRandomInvitation(t)
+		_, err := testQueries.RevokeInvitation(context.Background(), invitation.ID)
+		require.NoError(t, err)
+
+		reinvited, err := testQueries.CreateInvitation(context.Background(), CreateInvitationParams{
+			Email:           invitation.Email,
+			InvitationToken: util.RandomString(32),
+			RoleToInvite:    invitation.RoleToInvite,
+			InviterID:       invitation.InviterID,
+			ExpiresAt:       pgtype.Timestamp{Time: time.Now().Add(24 * time.Hour), Valid: true},
+			TeamID:          invitation.TeamID,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "pending", reinvited.Status)
+	})
+}
+
+////////////////////////////////////////////////////////////////////////
+
+// TestExpirePendingInvitations checks that only pending invitations past their expiry flip to expired.
+func TestExpirePendingInvitations(t *testing.T) {
+	stale := createRandomInvitation(t)
+	_, err := testPool.Exec(context.Background(), "UPDATE invitations SET expires_at = NOW() - INTERVAL '1 hour' WHERE id = $1", stale.ID)
+	require.NoError(t, err)
+
+	fresh := createRandomInvitation(t)
+
+	expired, err := testQueries.ExpirePendingInvitations(context.Background())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, expired, int64(1))
+
+	stored, err := testQueries.GetInvitationByID(context.Background(), stale.ID)
+	require.NoError(t, err)
+	require.Equal(t, "expired", stored.Status)
+
+	stored, err = testQueries.GetInvitationByID(context.Background(), fresh.ID)
+	require.NoError(t, err)
+	require.Equal(t, "pending", stored.Status)
+}
+
+////////////////////////////////////////////////////////////////////////
+
+// TestListInvitationStatsByInviter checks the per-inviter outcome counts and acceptance rates.
+func TestListInvitationStatsByInviter(t *testing.T) {
+	// 1. Setup: One inviter with a mix of outcomes, another whose only invitation was accepted.
+	mixed, _ := createRandomUser(t)
+	perfect, _ := createRandomUser(t)
+
+	invite := func(inviterID int64, expiresIn time.Duration, status string) {
+		invitation, err := testQueries.CreateInvitation(context.Background(), CreateInvitationParams{
+			Email:           util.RandomEmail(),
+			InvitationToken: util.RandomString(32),
+			RoleToInvite:    UserRoleEngineer,
+			InviterID:       inviterID,
+			ExpiresAt:       pgtype.Timestamp{Time: time.Now().Add(expiresIn), Valid: true},
+		})
+		require.NoError(t, err)
+		if status != "pending" {
+			_, err = testQueries.UpdateInvitationStatus(context.Background(), UpdateInvitationStatusParams{
+				ID:     invitation.ID,
+				Status: status,
+			})
+			require.NoError(t, err)
+		}
+	}
+	invite(mixed.ID, time.Hour, "accepted")
+	invite(mixed.ID, time.Hour, "pending")
+	invite(mixed.ID, -time.Hour, "pending") // Lapsed, so counted as expired
+	invite(mixed.ID, -time.Hour, "expired")
+	invite(perfect.ID, time.Hour, "accepted")
+
+	// 2. Execute: Fetch every inviter's stats in one page.
+	inviters, err := testQueries.CountInvitationInviters(context.Background())
+	require.NoError(t, err)
+	stats, err := testQueries.ListInvitationStatsByInviter(context.Background(), ListInvitationStatsByInviterParams{
+		Limit:  int32(inviters),
+		Offset: 0,
+		SortBy: "acceptance_rate",
+	})
+	require.NoError(t, err)
+	require.Len(t, stats, int(inviters))
+
+	byInviter := make(map[int64]ListInvitationStatsByInviterRow)
+	for _, row := range stats {
+		byInviter[row.InviterID] = row
+	}
+
+	// 3. Verify: Counts and rates per inviter.
+	mixedStats := byInviter[mixed.ID]
+	require.Equal(t, int64(4), mixedStats.SentCount)
+	require.Equal(t, int64(1), mixedStats.AcceptedCount)
+	require.Equal(t, int64(1), mixedStats.PendingCount)
+	require.Equal(t, int64(2), mixedStats.ExpiredCount)
+	require.InDelta(t, 0.25, mixedStats.AcceptanceRate, 1e-9)
+
+	perfectStats := byInviter[perfect.ID]
+	require.Equal(t, int64(1), perfectStats.SentCount)
+	require.InDelta(t, 1.0, perfectStats.AcceptanceRate, 1e-9)
+
+	// Sorted by acceptance rate, best first
+	for i := 1; i < len(stats); i++ {
+		require.GreaterOrEqual(t, stats[i-1].AcceptanceRate, stats[i].AcceptanceRate)
+	}
+}
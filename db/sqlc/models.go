@@ -16,6 +16,7 @@ type AvailabilityStatus string
 const (
 	AvailabilityStatusAvailable AvailabilityStatus = "available"
 	AvailabilityStatusBusy      AvailabilityStatus = "busy"
+	AvailabilityStatusOnLeave   AvailabilityStatus = "on_leave"
 )
 
 func (e *AvailabilityStatus) Scan(src interface{}) error {
@@ -96,6 +97,53 @@ func (ns NullProficiencyLevel) Value() (driver.Value, error) {
 	return string(ns.ProficiencyLevel), nil
 }
 
+type TaskActivityEvent string
+
+const (
+	TaskActivityEventCreated           TaskActivityEvent = "created"
+	TaskActivityEventAssigned          TaskActivityEvent = "assigned"
+	TaskActivityEventReassigned        TaskActivityEvent = "reassigned"
+	TaskActivityEventStatusChanged     TaskActivityEvent = "status_changed"
+	TaskActivityEventCompleted         TaskActivityEvent = "completed"
+	TaskActivityEventArchived          TaskActivityEvent = "archived"
+	TaskActivityEventPriorityEscalated TaskActivityEvent = "priority_escalated"
+)
+
+func (e *TaskActivityEvent) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TaskActivityEvent(s)
+	case string:
+		*e = TaskActivityEvent(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TaskActivityEvent: %T", src)
+	}
+	return nil
+}
+
+type NullTaskActivityEvent struct {
+	TaskActivityEvent TaskActivityEvent `json:"task_activity_event"`
+	Valid             bool              `json:"valid"` // Valid is true if TaskActivityEvent is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullTaskActivityEvent) Scan(value interface{}) error {
+	if value == nil {
+		ns.TaskActivityEvent, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.TaskActivityEvent.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullTaskActivityEvent) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.TaskActivityEvent), nil
+}
+
 type TaskPriority string
 
 const (
@@ -238,6 +286,16 @@ type Invitation struct {
 	TeamID          pgtype.Int8      `json:"team_id"`
 }
 
+// Single-use, time-limited tokens for resetting a forgotten password.
+type PasswordResetToken struct {
+	ID         int64            `json:"id"`
+	UserID     int64            `json:"user_id"`
+	Token      string           `json:"token"`
+	ExpiresAt  pgtype.Timestamp `json:"expires_at"`
+	ConsumedAt pgtype.Timestamp `json:"consumed_at"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+}
+
 // Provides context and grouping for related tasks.
 type Project struct {
 	ID          int64       `json:"id"`
@@ -248,6 +306,19 @@ type Project struct {
 	Archived bool `json:"archived"`
 	// Timestamp when project was archived
 	ArchivedAt pgtype.Timestamp `json:"archived_at"`
+	// Incremented on every update; clients send the version they read to detect concurrent edits
+	Version int32 `json:"version"`
+}
+
+// Hashed, rotating refresh tokens used to renew access tokens.
+type RefreshToken struct {
+	ID         int64            `json:"id"`
+	UserID     int64            `json:"user_id"`
+	TokenHash  string           `json:"token_hash"`
+	ExpiresAt  pgtype.Timestamp `json:"expires_at"`
+	RevokedAt  pgtype.Timestamp `json:"revoked_at"`
+	ReplacedBy pgtype.Int8      `json:"replaced_by"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
 }
 
 // Controlled vocabulary to ensure consistency across the system.
@@ -278,12 +349,43 @@ type Task struct {
 	Archived bool `json:"archived"`
 	// Timestamp when task was archived
 	ArchivedAt pgtype.Timestamp `json:"archived_at"`
+	// Deadline for the task; NULL means no deadline
+	DueDate pgtype.Timestamp `json:"due_date"`
+	// Incremented on every update; clients send the version they read to detect concurrent edits
+	Version int32 `json:"version"`
+}
+
+// Audit log of events on a task: who did what, and when.
+type TaskActivity struct {
+	ID           int64             `json:"id"`
+	TaskID       int64             `json:"task_id"`
+	ActorID      pgtype.Int8       `json:"actor_id"`
+	Event        TaskActivityEvent `json:"event"`
+	AssigneeID   pgtype.Int8       `json:"assignee_id"`
+	FromStatus   NullTaskStatus    `json:"from_status"`
+	ToStatus     NullTaskStatus    `json:"to_status"`
+	CreatedAt    pgtype.Timestamp  `json:"created_at"`
+	FromPriority NullTaskPriority  `json:"from_priority"`
+	ToPriority   NullTaskPriority  `json:"to_priority"`
+}
+
+// Discussion on a task by members of its team, with threaded replies.
+type TaskComment struct {
+	ID        int64            `json:"id"`
+	TaskID    int64            `json:"task_id"`
+	AuthorID  int64            `json:"author_id"`
+	ParentID  pgtype.Int8      `json:"parent_id"`
+	Body      string           `json:"body"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	DeletedAt pgtype.Timestamp `json:"deleted_at"`
 }
 
 // Populated by NLP. Defines what skills are needed for each task.
 type TaskRequiredSkill struct {
 	TaskID  int64 `json:"task_id"`
 	SkillID int64 `json:"skill_id"`
+	// How central the skill is to the task, from just above 0 (peripheral) to 1 (core)
+	Weight float64 `json:"weight"`
 }
 
 // Teams provide organizational context and allow filtering of users.
@@ -291,6 +393,12 @@ type Team struct {
 	ID        int64       `json:"id"`
 	TeamName  string      `json:"team_name"`
 	ManagerID pgtype.Int8 `json:"manager_id"`
+	// When true, new tasks are assigned to the top available recommended engineer on creation
+	AutoAssign bool `json:"auto_assign"`
+	// Priority given to new tasks created without one; NULL uses the server default
+	DefaultTaskPriority NullTaskPriority `json:"default_task_priority"`
+	// Status new tasks are created in; NULL means open
+	DefaultTaskStatus NullTaskStatus `json:"default_task_status"`
 }
 
 // The central entity representing talent. Availability is essential for task assignment.
@@ -309,4 +417,17 @@ type UserSkill struct {
 	UserID      int64            `json:"user_id"`
 	SkillID     int64            `json:"skill_id"`
 	Proficiency ProficiencyLevel `json:"proficiency"`
+	// Set when a person added or endorsed the skill; reprocessing a resume never removes these
+	IsManual bool `json:"is_manual"`
+}
+
+// Team-scoped endpoints that receive signed event notifications.
+type Webhook struct {
+	ID         int64            `json:"id"`
+	TeamID     int64            `json:"team_id"`
+	Url        string           `json:"url"`
+	Secret     string           `json:"secret"`
+	EventTypes []string         `json:"event_types"`
+	Active     bool             `json:"active"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
 }
@@ -12,10 +12,10 @@ import (
 )
 
 const archiveProject = `-- name: ArchiveProject :one
-UPDATE projects 
-SET archived = true, archived_at = now()
+UPDATE projects
+SET archived = true, archived_at = now(), version = version + 1
 WHERE id = $1 AND team_id = $2 AND archived = false
-RETURNING id, project_name, team_id, description, archived, archived_at
+RETURNING id, project_name, team_id, description, archived, archived_at, version
 `
 
 type ArchiveProjectParams struct {
@@ -34,6 +34,7 @@ func (q *Queries) ArchiveProject(ctx context.Context, arg ArchiveProjectParams)
 		&i.Description,
 		&i.Archived,
 		&i.ArchivedAt,
+		&i.Version,
 	)
 	return i, err
 }
@@ -84,7 +85,7 @@ INSERT INTO projects (
     description
 ) VALUES (
     $1, $2, $3
-) RETURNING id, project_name, team_id, description, archived, archived_at
+) RETURNING id, project_name, team_id, description, archived, archived_at, version
 `
 
 type CreateProjectParams struct {
@@ -106,6 +107,7 @@ func (q *Queries) CreateProject(ctx context.Context, arg CreateProjectParams) (P
 		&i.Description,
 		&i.Archived,
 		&i.ArchivedAt,
+		&i.Version,
 	)
 	return i, err
 }
@@ -122,7 +124,7 @@ func (q *Queries) DeleteProject(ctx context.Context, id int64) error {
 }
 
 const getProject = `-- name: GetProject :one
-SELECT id, project_name, team_id, description, archived, archived_at FROM projects
+SELECT id, project_name, team_id, description, archived, archived_at, version FROM projects
 WHERE id = $1
 LIMIT 1
 `
@@ -138,12 +140,13 @@ func (q *Queries) GetProject(ctx context.Context, id int64) (Project, error) {
 		&i.Description,
 		&i.Archived,
 		&i.ArchivedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
 const getProjectByIDAndTeam = `-- name: GetProjectByIDAndTeam :one
-SELECT id, project_name, team_id, description, archived, archived_at FROM projects
+SELECT id, project_name, team_id, description, archived, archived_at, version FROM projects
 WHERE id = $1 AND team_id = $2
 LIMIT 1
 `
@@ -164,12 +167,13 @@ func (q *Queries) GetProjectByIDAndTeam(ctx context.Context, arg GetProjectByIDA
 		&i.Description,
 		&i.Archived,
 		&i.ArchivedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
 const listActiveProjectsByTeam = `-- name: ListActiveProjectsByTeam :many
-SELECT id, project_name, team_id, description, archived, archived_at
+SELECT id, project_name, team_id, description, archived, archived_at, version
 FROM projects 
 WHERE team_id = $1 AND archived = false
 ORDER BY id
@@ -199,6 +203,7 @@ func (q *Queries) ListActiveProjectsByTeam(ctx context.Context, arg ListActivePr
 			&i.Description,
 			&i.Archived,
 			&i.ArchivedAt,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -211,7 +216,7 @@ func (q *Queries) ListActiveProjectsByTeam(ctx context.Context, arg ListActivePr
 }
 
 const listArchivedProjectsByTeam = `-- name: ListArchivedProjectsByTeam :many
-SELECT id, project_name, team_id, description, archived, archived_at
+SELECT id, project_name, team_id, description, archived, archived_at, version
 FROM projects
 WHERE team_id = $1 AND archived = true  
 ORDER BY archived_at DESC
@@ -241,6 +246,7 @@ func (q *Queries) ListArchivedProjectsByTeam(ctx context.Context, arg ListArchiv
 			&i.Description,
 			&i.Archived,
 			&i.ArchivedAt,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -253,7 +259,7 @@ func (q *Queries) ListArchivedProjectsByTeam(ctx context.Context, arg ListArchiv
 }
 
 const listProjects = `-- name: ListProjects :many
-SELECT id, project_name, team_id, description, archived, archived_at FROM projects
+SELECT id, project_name, team_id, description, archived, archived_at, version FROM projects
 ORDER BY id
 LIMIT $1
 OFFSET $2
@@ -281,6 +287,7 @@ func (q *Queries) ListProjects(ctx context.Context, arg ListProjectsParams) ([]P
 			&i.Description,
 			&i.Archived,
 			&i.ArchivedAt,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -293,7 +300,7 @@ func (q *Queries) ListProjects(ctx context.Context, arg ListProjectsParams) ([]P
 }
 
 const listProjectsByTeam = `-- name: ListProjectsByTeam :many
-SELECT id, project_name, team_id, description, archived, archived_at FROM projects
+SELECT id, project_name, team_id, description, archived, archived_at, version FROM projects
 WHERE team_id = $1 AND archived = false
 ORDER BY id
 LIMIT $2 OFFSET $3
@@ -322,6 +329,55 @@ func (q *Queries) ListProjectsByTeam(ctx context.Context, arg ListProjectsByTeam
 			&i.Description,
 			&i.Archived,
 			&i.ArchivedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTopActiveProjectsByTeam = `-- name: ListTopActiveProjectsByTeam :many
+SELECT p.id, p.project_name, p.description, count(t.id) AS open_task_count
+FROM projects p
+LEFT JOIN tasks t ON t.project_id = p.id AND t.archived = false AND t.status <> 'done'
+WHERE p.team_id = $1 AND p.archived = false
+GROUP BY p.id
+ORDER BY open_task_count DESC, p.id
+LIMIT $2
+`
+
+type ListTopActiveProjectsByTeamParams struct {
+	TeamID int64 `json:"team_id"`
+	Limit  int32 `json:"limit"`
+}
+
+type ListTopActiveProjectsByTeamRow struct {
+	ID            int64       `json:"id"`
+	ProjectName   string      `json:"project_name"`
+	Description   pgtype.Text `json:"description"`
+	OpenTaskCount int64       `json:"open_task_count"`
+}
+
+// List the active projects for a team with the most outstanding (not done) work
+func (q *Queries) ListTopActiveProjectsByTeam(ctx context.Context, arg ListTopActiveProjectsByTeamParams) ([]ListTopActiveProjectsByTeamRow, error) {
+	rows, err := q.db.Query(ctx, listTopActiveProjectsByTeam, arg.TeamID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTopActiveProjectsByTeamRow
+	for rows.Next() {
+		var i ListTopActiveProjectsByTeamRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectName,
+			&i.Description,
+			&i.OpenTaskCount,
 		); err != nil {
 			return nil, err
 		}
@@ -335,9 +391,9 @@ func (q *Queries) ListProjectsByTeam(ctx context.Context, arg ListProjectsByTeam
 
 const unarchiveProject = `-- name: UnarchiveProject :one
 UPDATE projects
-SET archived = false, archived_at = NULL
+SET archived = false, archived_at = NULL, version = version + 1
 WHERE id = $1 AND team_id = $2 AND archived = true
-RETURNING id, project_name, team_id, description, archived, archived_at
+RETURNING id, project_name, team_id, description, archived, archived_at, version
 `
 
 type UnarchiveProjectParams struct {
@@ -356,6 +412,7 @@ func (q *Queries) UnarchiveProject(ctx context.Context, arg UnarchiveProjectPara
 		&i.Description,
 		&i.Archived,
 		&i.ArchivedAt,
+		&i.Version,
 	)
 	return i, err
 }
@@ -363,9 +420,10 @@ func (q *Queries) UnarchiveProject(ctx context.Context, arg UnarchiveProjectPara
 const updateProject = `-- name: UpdateProject :one
 UPDATE projects
 SET project_name = $3,
-    description = $4
-WHERE id = $1 AND team_id = $2
-RETURNING id, project_name, team_id, description, archived, archived_at
+    description = $4,
+    version = version + 1
+WHERE id = $1 AND team_id = $2 AND version = $5
+RETURNING id, project_name, team_id, description, archived, archived_at, version
 `
 
 type UpdateProjectParams struct {
@@ -373,15 +431,17 @@ type UpdateProjectParams struct {
 	TeamID      int64       `json:"team_id"`
 	ProjectName string      `json:"project_name"`
 	Description pgtype.Text `json:"description"`
+	Version     int32       `json:"version"`
 }
 
-// Updates a project's name and description.
+// Updates a project's name and description if it is still at the expected version, bumping it.
 func (q *Queries) UpdateProject(ctx context.Context, arg UpdateProjectParams) (Project, error) {
 	row := q.db.QueryRow(ctx, updateProject,
 		arg.ID,
 		arg.TeamID,
 		arg.ProjectName,
 		arg.Description,
+		arg.Version,
 	)
 	var i Project
 	err := row.Scan(
@@ -391,6 +451,7 @@ func (q *Queries) UpdateProject(ctx context.Context, arg UpdateProjectParams) (P
 		&i.Description,
 		&i.Archived,
 		&i.ArchivedAt,
+		&i.Version,
 	)
 	return i, err
 }
@@ -94,6 +94,7 @@ func TestUpdateProject(t *testing.T) {
 			TeamID:      project1.TeamID,
 			ProjectName: util.RandomProjectName(),
 			Description: pgtype.Text{String: "A new updated description.", Valid: true},
+			Version:     project1.Version,
 		}
 
 		updatedProject, err := testQueries.UpdateProject(context.Background(), arg)
@@ -106,6 +107,7 @@ func TestUpdateProject(t *testing.T) {
 		require.Equal(t, arg.Description.String, updatedProject.Description.String)
 		require.NotEqual(t, project1.ProjectName, updatedProject.ProjectName)
 		require.NotEqual(t, project1.Description.String, updatedProject.Description.String)
+		require.Equal(t, project1.Version+1, updatedProject.Version)
 	})
 
 	t.Run("SuccessPartialUpdate_NameOnly", func(t *testing.T) {
@@ -119,6 +121,7 @@ func TestUpdateProject(t *testing.T) {
 			TeamID:      project1.TeamID,
 			ProjectName: util.RandomProjectName(),
 			Description: pgtype.Text{Valid: false}, // This should keep the old description
+			Version:     project1.Version,
 		}
 
 		updatedProject, err := testQueries.UpdateProject(context.Background(), arg)
@@ -140,6 +143,7 @@ func TestUpdateProject(t *testing.T) {
 			ID:          project1.ID,
 			TeamID:      -1, // Invalid team ID
 			ProjectName: "This should not be updated",
+			Version:     project1.Version,
 		}
 
 		project, err := testQueries.UpdateProject(context.Background(), arg)
@@ -147,6 +151,27 @@ func TestUpdateProject(t *testing.T) {
 		require.ErrorIs(t, err, pgx.ErrNoRows, "Expected no rows error because WHERE clause failed")
 		require.Empty(t, project)
 	})
+
+	t.Run("Failure_StaleVersion", func(t *testing.T) {
+		project1 := createRandomProject(t)
+
+		// Another edit moves the project past the version the caller read
+		_, err := testQueries.UpdateProject(context.Background(), UpdateProjectParams{
+			ID:          project1.ID,
+			TeamID:      project1.TeamID,
+			ProjectName: util.RandomProjectName(),
+			Version:     project1.Version,
+		})
+		require.NoError(t, err)
+
+		_, err = testQueries.UpdateProject(context.Background(), UpdateProjectParams{
+			ID:          project1.ID,
+			TeamID:      project1.TeamID,
+			ProjectName: "This should not be updated",
+			Version:     project1.Version,
+		})
+		require.ErrorIs(t, err, pgx.ErrNoRows)
+	})
 }
 
 ////////////////////////////////////////////////////////////////////////
@@ -353,3 +378,31 @@ func TestListProjectsByTeam(t *testing.T) {
 }
 
 ////////////////////////////////////////////////////////////////////////
+
+// TestListTopActiveProjectsByTeam checks projects are ranked by outstanding work.
+func TestListTopActiveProjectsByTeam(t *testing.T) {
+	busyProject := createRandomProject(t)
+	quietProject, err := testQueries.CreateProject(context.Background(), CreateProjectParams{
+		ProjectName: util.RandomProjectName(),
+		TeamID:      busyProject.TeamID,
+	})
+	require.NoError(t, err)
+
+	for range 3 {
+		createRandomTaskLocal(t, busyProject.ID)
+	}
+
+	projects, err := testQueries.ListTopActiveProjectsByTeam(context.Background(), ListTopActiveProjectsByTeamParams{
+		TeamID: busyProject.TeamID,
+		Limit:  5,
+	})
+	require.NoError(t, err)
+	require.Len(t, projects, 2)
+
+	require.Equal(t, busyProject.ID, projects[0].ID)
+	require.Equal(t, int64(3), projects[0].OpenTaskCount)
+	require.Equal(t, quietProject.ID, projects[1].ID)
+	require.Zero(t, projects[1].OpenTaskCount)
+}
+
+////////////////////////////////////////////////////////////////////////
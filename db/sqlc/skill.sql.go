@@ -9,6 +9,43 @@ import (
 	"context"
 )
 
+const autoVerifySkillsByUsage = `-- name: AutoVerifySkillsByUsage :many
+UPDATE skills s
+SET is_verified = true
+WHERE s.is_verified = false
+AND (
+    (SELECT count(*) FROM user_skills us WHERE us.skill_id = s.id) >= $1::bigint OR
+    (SELECT count(*) FROM task_required_skills trs WHERE trs.skill_id = s.id) >= $2::bigint
+)
+RETURNING id, skill_name, is_verified
+`
+
+type AutoVerifySkillsByUsageParams struct {
+	MinUsers int64 `json:"min_users"`
+	MinTasks int64 `json:"min_tasks"`
+}
+
+// Verifies unverified skills that are held by at least min_users users or required by at least min_tasks tasks.
+func (q *Queries) AutoVerifySkillsByUsage(ctx context.Context, arg AutoVerifySkillsByUsageParams) ([]Skill, error) {
+	rows, err := q.db.Query(ctx, autoVerifySkillsByUsage, arg.MinUsers, arg.MinTasks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Skill
+	for rows.Next() {
+		var i Skill
+		if err := rows.Scan(&i.ID, &i.SkillName, &i.IsVerified); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const countSearchSkillsByStatus = `-- name: CountSearchSkillsByStatus :one
 SELECT count(*) FROM skills 
 WHERE is_verified = $1 
@@ -136,6 +173,64 @@ func (q *Queries) GetSkillByName(ctx context.Context, lower string) (Skill, erro
 	return i, err
 }
 
+const listMostUsedUnverifiedSkills = `-- name: ListMostUsedUnverifiedSkills :many
+SELECT
+    s.id,
+    s.skill_name,
+    COALESCE(us.user_count, 0)::bigint AS user_count,
+    COALESCE(trs.task_count, 0)::bigint AS task_count
+FROM skills s
+LEFT JOIN (
+    SELECT skill_id, count(*) AS user_count FROM user_skills GROUP BY skill_id
+) us ON us.skill_id = s.id
+LEFT JOIN (
+    SELECT skill_id, count(*) AS task_count FROM task_required_skills GROUP BY skill_id
+) trs ON trs.skill_id = s.id
+WHERE s.is_verified = false
+ORDER BY COALESCE(us.user_count, 0) + COALESCE(trs.task_count, 0) DESC, s.skill_name
+LIMIT $1
+OFFSET $2
+`
+
+type ListMostUsedUnverifiedSkillsParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+type ListMostUsedUnverifiedSkillsRow struct {
+	ID        int64  `json:"id"`
+	SkillName string `json:"skill_name"`
+	UserCount int64  `json:"user_count"`
+	TaskCount int64  `json:"task_count"`
+}
+
+// Lists unverified skills ranked by how many users hold them plus how many tasks require them,
+// so the ones with the most impact can be verified first.
+func (q *Queries) ListMostUsedUnverifiedSkills(ctx context.Context, arg ListMostUsedUnverifiedSkillsParams) ([]ListMostUsedUnverifiedSkillsRow, error) {
+	rows, err := q.db.Query(ctx, listMostUsedUnverifiedSkills, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListMostUsedUnverifiedSkillsRow
+	for rows.Next() {
+		var i ListMostUsedUnverifiedSkillsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SkillName,
+			&i.UserCount,
+			&i.TaskCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listSkills = `-- name: ListSkills :many
 SELECT id, skill_name, is_verified FROM skills
 ORDER BY id
@@ -171,9 +266,10 @@ func (q *Queries) ListSkills(ctx context.Context, arg ListSkillsParams) ([]Skill
 
 const listSkillsByNames = `-- name: ListSkillsByNames :many
 SELECT id, skill_name, is_verified FROM skills
-WHERE skill_name = ANY($1::text[])
+WHERE lower(skill_name) = ANY(SELECT lower(unnest($1::text[])))
 `
 
+// Retrieves the skills matching any of the given names, ignoring case.
 func (q *Queries) ListSkillsByNames(ctx context.Context, dollar_1 []string) ([]Skill, error) {
 	rows, err := q.db.Query(ctx, listSkillsByNames, dollar_1)
 	if err != nil {
@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"math"
 	"testing"
 
 	"github.com/jackc/pgx/v5"
@@ -212,3 +213,103 @@ func TestListSkillsByNames(t *testing.T) {
 }
 
 ////////////////////////////////////////////////////////////////////////
+
+func TestAutoVerifySkillsByUsage(t *testing.T) {
+	popularSkill := createRandomSkill(t)
+	rareSkill := createRandomSkill(t)
+
+	// The popular skill is held by 3 users, the rare one by a single user
+	for range 3 {
+		user, _ := createRandomUser(t)
+		_, err := testQueries.AddSkillToUser(context.Background(), AddSkillToUserParams{
+			UserID:      user.ID,
+			SkillID:     popularSkill.ID,
+			Proficiency: ProficiencyLevelIntermediate,
+		})
+		require.NoError(t, err)
+	}
+	user, _ := createRandomUser(t)
+	_, err := testQueries.AddSkillToUser(context.Background(), AddSkillToUserParams{
+		UserID:      user.ID,
+		SkillID:     rareSkill.ID,
+		Proficiency: ProficiencyLevelIntermediate,
+	})
+	require.NoError(t, err)
+
+	verified, err := testQueries.AutoVerifySkillsByUsage(context.Background(), AutoVerifySkillsByUsageParams{
+		MinUsers: 3,
+		MinTasks: 1000,
+	})
+	require.NoError(t, err)
+
+	verifiedIDs := make(map[int64]bool)
+	for _, skill := range verified {
+		require.True(t, skill.IsVerified)
+		verifiedIDs[skill.ID] = true
+	}
+	require.True(t, verifiedIDs[popularSkill.ID])
+	require.False(t, verifiedIDs[rareSkill.ID])
+
+	// Check the stored state as well
+	popular, err := testQueries.GetSkill(context.Background(), popularSkill.ID)
+	require.NoError(t, err)
+	require.True(t, popular.IsVerified)
+
+	rare, err := testQueries.GetSkill(context.Background(), rareSkill.ID)
+	require.NoError(t, err)
+	require.False(t, rare.IsVerified)
+}
+
+////////////////////////////////////////////////////////////////////////
+
+func TestListMostUsedUnverifiedSkills(t *testing.T) {
+	// 1. Setup: One skill used by two users and a task, one used by a single user, and a verified one.
+	popularSkill := createRandomSkill(t)
+	rareSkill := createRandomSkill(t)
+	verifiedSkill, err := testQueries.CreateSkill(context.Background(), CreateSkillParams{
+		SkillName:  util.RandomName(),
+		IsVerified: true,
+	})
+	require.NoError(t, err)
+
+	for _, skillID := range []int64{popularSkill.ID, popularSkill.ID, rareSkill.ID, verifiedSkill.ID} {
+		user, _ := createRandomUser(t)
+		_, err := testQueries.AddSkillToUser(context.Background(), AddSkillToUserParams{
+			UserID:      user.ID,
+			SkillID:     skillID,
+			Proficiency: ProficiencyLevelIntermediate,
+		})
+		require.NoError(t, err)
+	}
+	task := createRandomTask(t)
+	_, err = testQueries.AddSkillToTask(context.Background(), AddSkillToTaskParams{
+		TaskID:  task.ID,
+		SkillID: popularSkill.ID,
+	})
+	require.NoError(t, err)
+
+	// 2. Execute: Rank every unverified skill.
+	skills, err := testQueries.ListMostUsedUnverifiedSkills(context.Background(), ListMostUsedUnverifiedSkillsParams{
+		Limit:  math.MaxInt32,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+
+	// 3. Verify: The list is ranked by usage and leaves out the verified skill.
+	positions := make(map[int64]int)
+	for i, skill := range skills {
+		if i > 0 {
+			previous := skills[i-1]
+			require.GreaterOrEqual(t, previous.UserCount+previous.TaskCount, skill.UserCount+skill.TaskCount)
+		}
+		positions[skill.ID] = i
+	}
+	require.NotContains(t, positions, verifiedSkill.ID)
+	require.Contains(t, positions, popularSkill.ID)
+	require.Contains(t, positions, rareSkill.ID)
+	require.Less(t, positions[popularSkill.ID], positions[rareSkill.ID])
+
+	popular := skills[positions[popularSkill.ID]]
+	require.Equal(t, int64(2), popular.UserCount)
+	require.Equal(t, int64(1), popular.TaskCount)
+}
@@ -9,6 +9,25 @@ import (
 	"context"
 )
 
+const countAllSkillAliases = `-- name: CountAllSkillAliases :one
+SELECT count(*)
+FROM
+    skill_aliases sa
+JOIN
+    skills s ON sa.skill_id = s.id
+WHERE
+    LOWER(sa.alias_name) LIKE LOWER($1) ESCAPE '\'
+    OR LOWER(s.skill_name) LIKE LOWER($1) ESCAPE '\'
+`
+
+// Counts the aliases ListAllSkillAliases pages through for the same search pattern.
+func (q *Queries) CountAllSkillAliases(ctx context.Context, search string) (int64, error) {
+	row := q.db.QueryRow(ctx, countAllSkillAliases, search)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createSkillAlias = `-- name: CreateSkillAlias :one
 
 INSERT INTO skill_aliases (
@@ -34,15 +53,44 @@ func (q *Queries) CreateSkillAlias(ctx context.Context, arg CreateSkillAliasPara
 	return i, err
 }
 
-const deleteSkillAlias = `-- name: DeleteSkillAlias :exec
+const createSkillAliasIfNotExists = `-- name: CreateSkillAliasIfNotExists :one
+INSERT INTO skill_aliases (
+    alias_name,
+    skill_id
+) VALUES (
+    $1, $2
+)
+ON CONFLICT (alias_name) DO NOTHING
+RETURNING alias_name, skill_id
+`
+
+type CreateSkillAliasIfNotExistsParams struct {
+	AliasName string `json:"alias_name"`
+	SkillID   int64  `json:"skill_id"`
+}
+
+// Inserts a skill alias unless one with the same name already exists.
+// Returns no rows for an existing alias, so bulk imports can report it and carry on.
+func (q *Queries) CreateSkillAliasIfNotExists(ctx context.Context, arg CreateSkillAliasIfNotExistsParams) (SkillAlias, error) {
+	row := q.db.QueryRow(ctx, createSkillAliasIfNotExists, arg.AliasName, arg.SkillID)
+	var i SkillAlias
+	err := row.Scan(&i.AliasName, &i.SkillID)
+	return i, err
+}
+
+const deleteSkillAlias = `-- name: DeleteSkillAlias :execrows
 DELETE FROM skill_aliases
 WHERE alias_name = $1
 `
 
 // Deletes a skill alias from the database by its name.
-func (q *Queries) DeleteSkillAlias(ctx context.Context, aliasName string) error {
-	_, err := q.db.Exec(ctx, deleteSkillAlias, aliasName)
-	return err
+// Returns the number of rows deleted, so a missing alias can be reported.
+func (q *Queries) DeleteSkillAlias(ctx context.Context, aliasName string) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteSkillAlias, aliasName)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
 const getAllSkillAliases = `-- name: GetAllSkillAliases :many
@@ -121,6 +169,59 @@ func (q *Queries) ListAliasesForSkill(ctx context.Context, skillID int64) ([]Ski
 	return items, nil
 }
 
+const listAllSkillAliases = `-- name: ListAllSkillAliases :many
+SELECT
+    sa.alias_name,
+    sa.skill_id,
+    s.skill_name
+FROM
+    skill_aliases sa
+JOIN
+    skills s ON sa.skill_id = s.id
+WHERE
+    LOWER(sa.alias_name) LIKE LOWER($1) ESCAPE '\'
+    OR LOWER(s.skill_name) LIKE LOWER($1) ESCAPE '\'
+ORDER BY
+    sa.alias_name
+LIMIT $2
+OFFSET $3
+`
+
+type ListAllSkillAliasesParams struct {
+	Search string `json:"search"`
+	Limit  int32  `json:"limit"`
+	Offset int32  `json:"offset"`
+}
+
+type ListAllSkillAliasesRow struct {
+	AliasName string `json:"alias_name"`
+	SkillID   int64  `json:"skill_id"`
+	SkillName string `json:"skill_name"`
+}
+
+// Lists a page of every alias with the name of its canonical skill, for auditing.
+// The search pattern is matched against both the alias and the skill name; '%' matches everything.
+// Backslash escapes a literal '%' or '_' in the pattern.
+func (q *Queries) ListAllSkillAliases(ctx context.Context, arg ListAllSkillAliasesParams) ([]ListAllSkillAliasesRow, error) {
+	rows, err := q.db.Query(ctx, listAllSkillAliases, arg.Search, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAllSkillAliasesRow
+	for rows.Next() {
+		var i ListAllSkillAliasesRow
+		if err := rows.Scan(&i.AliasName, &i.SkillID, &i.SkillName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listSkillAliases = `-- name: ListSkillAliases :many
 SELECT alias_name, skill_id FROM skill_aliases
 ORDER BY alias_name
@@ -154,6 +255,124 @@ func (q *Queries) ListSkillAliases(ctx context.Context, arg ListSkillAliasesPara
 	return items, nil
 }
 
+const listSkillsByAliasNames = `-- name: ListSkillsByAliasNames :many
+SELECT
+    sa.alias_name,
+    s.id,
+    s.skill_name,
+    s.is_verified
+FROM
+    skill_aliases sa
+JOIN
+    skills s ON sa.skill_id = s.id
+WHERE
+    sa.alias_name = ANY($1::text[])
+`
+
+type ListSkillsByAliasNamesRow struct {
+	AliasName  string `json:"alias_name"`
+	ID         int64  `json:"id"`
+	SkillName  string `json:"skill_name"`
+	IsVerified bool   `json:"is_verified"`
+}
+
+// Resolves a batch of alias names to their canonical skills.
+func (q *Queries) ListSkillsByAliasNames(ctx context.Context, aliasNames []string) ([]ListSkillsByAliasNamesRow, error) {
+	rows, err := q.db.Query(ctx, listSkillsByAliasNames, aliasNames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSkillsByAliasNamesRow
+	for rows.Next() {
+		var i ListSkillsByAliasNamesRow
+		if err := rows.Scan(
+			&i.AliasName,
+			&i.ID,
+			&i.SkillName,
+			&i.IsVerified,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const repointSkillAliases = `-- name: RepointSkillAliases :execrows
+UPDATE skill_aliases
+SET skill_id = $1
+WHERE skill_id = $2
+`
+
+type RepointSkillAliasesParams struct {
+	TargetID int64 `json:"target_id"`
+	SourceID int64 `json:"source_id"`
+}
+
+// Moves every alias of the source skill to the target skill.
+func (q *Queries) RepointSkillAliases(ctx context.Context, arg RepointSkillAliasesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, repointSkillAliases, arg.TargetID, arg.SourceID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const searchSkillSuggestions = `-- name: SearchSkillSuggestions :many
+SELECT s.id, s.skill_name, s.is_verified
+FROM skills s
+WHERE s.is_verified = $1
+AND (
+    LOWER(s.skill_name) LIKE LOWER($2) ESCAPE '\'
+    OR EXISTS (
+        SELECT 1 FROM skill_aliases sa
+        WHERE sa.skill_id = s.id
+        AND LOWER(sa.alias_name) LIKE LOWER($2) ESCAPE '\'
+    )
+)
+ORDER BY LOWER(s.skill_name) LIKE LOWER($3) ESCAPE '\' DESC, s.skill_name
+LIMIT $4
+`
+
+type SearchSkillSuggestionsParams struct {
+	IsVerified bool   `json:"is_verified"`
+	Search     string `json:"search"`
+	Prefix     string `json:"prefix"`
+	Limit      int32  `json:"limit"`
+}
+
+// Retrieves skills with the given verification status whose name or one of whose aliases matches
+// the search pattern, so searching for "golang" finds "Go". Skills whose name matches the prefix
+// pattern come first, then the rest by name. Both patterns escape wildcards with a backslash.
+func (q *Queries) SearchSkillSuggestions(ctx context.Context, arg SearchSkillSuggestionsParams) ([]Skill, error) {
+	rows, err := q.db.Query(ctx, searchSkillSuggestions,
+		arg.IsVerified,
+		arg.Search,
+		arg.Prefix,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Skill
+	for rows.Next() {
+		var i Skill
+		if err := rows.Scan(&i.ID, &i.SkillName, &i.IsVerified); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateSkillAlias = `-- name: UpdateSkillAlias :one
 UPDATE skill_aliases
 SET
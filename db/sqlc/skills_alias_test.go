@@ -63,8 +63,9 @@ func TestDeleteSkillAlias(t *testing.T) {
 	alias1 := createRandomSkillAlias(t)
 
 	// Delete the alias
-	err := testQueries.DeleteSkillAlias(context.Background(), alias1.AliasName)
+	deleted, err := testQueries.DeleteSkillAlias(context.Background(), alias1.AliasName)
 	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
 
 	// Verify it's gone
 	alias2, err := testQueries.GetSkillAlias(context.Background(), alias1.AliasName)
@@ -168,3 +169,108 @@ func TestGetAllSkillAliases(t *testing.T) {
 }
 
 ////////////////////////////////////////////////////////////////////////
+
+func TestListAllSkillAliases(t *testing.T) {
+	// Three aliases of one skill, found by searching for the skill's name
+	skill := createRandomSkill(t)
+	for range 3 {
+		_, err := testQueries.CreateSkillAlias(context.Background(), CreateSkillAliasParams{
+			AliasName: util.RandomString(8),
+			SkillID:   skill.ID,
+		})
+		require.NoError(t, err)
+	}
+	createRandomSkillAlias(t)
+
+	search := "%" + skill.SkillName + "%"
+	count, err := testQueries.CountAllSkillAliases(context.Background(), search)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), count)
+
+	page, err := testQueries.ListAllSkillAliases(context.Background(), ListAllSkillAliasesParams{
+		Search: search,
+		Limit:  2,
+		Offset: 2,
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Equal(t, skill.ID, page[0].SkillID)
+	require.Equal(t, skill.SkillName, page[0].SkillName)
+
+	// A bare wildcard lists every alias
+	all, err := testQueries.CountAllSkillAliases(context.Background(), "%")
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, all, int64(4))
+}
+
+////////////////////////////////////////////////////////////////////////
+
+func TestSearchSkillSuggestions(t *testing.T) {
+	search := func(t *testing.T, isVerified bool, query string, limit int32) []Skill {
+		query = util.EscapeLike(query)
+		skills, err := testQueries.SearchSkillSuggestions(context.Background(), SearchSkillSuggestionsParams{
+			IsVerified: isVerified,
+			Search:     "%" + query + "%",
+			Prefix:     query + "%",
+			Limit:      limit,
+		})
+		require.NoError(t, err)
+		return skills
+	}
+
+	t.Run("Matches aliases once", func(t *testing.T) {
+		// A skill with two aliases sharing a term is listed once
+		term := util.RandomString(12)
+		skill := createRandomSkill(t)
+		for _, suffix := range []string{"-one", "-two"} {
+			_, err := testQueries.CreateSkillAlias(context.Background(), CreateSkillAliasParams{
+				AliasName: term + suffix,
+				SkillID:   skill.ID,
+			})
+			require.NoError(t, err)
+		}
+
+		skills := search(t, false, term, 10)
+		require.Len(t, skills, 1)
+		require.Equal(t, skill.ID, skills[0].ID)
+
+		// The verification status must match too
+		require.Empty(t, search(t, true, term, 10))
+	})
+
+	t.Run("Ranks prefix matches before the limit", func(t *testing.T) {
+		// The substring matches sort first by name, so a name-ordered limit would miss the prefix match
+		term := util.RandomString(12)
+		for _, name := range []string{"0" + term, "1" + term, term + "-prefix"} {
+			_, err := testQueries.CreateSkill(context.Background(), CreateSkillParams{SkillName: name})
+			require.NoError(t, err)
+		}
+
+		skills := search(t, false, term, 1)
+		require.Len(t, skills, 1)
+		require.Equal(t, term+"-prefix", skills[0].SkillName)
+
+		skills = search(t, false, term, 10)
+		require.Len(t, skills, 3)
+		require.Equal(t, []string{term + "-prefix", "0" + term, "1" + term},
+			[]string{skills[0].SkillName, skills[1].SkillName, skills[2].SkillName})
+	})
+
+	t.Run("Wildcards in the query match literally", func(t *testing.T) {
+		term := util.RandomString(12)
+		for _, name := range []string{term + "50%", term + "500", term + "a_b", term + "axb"} {
+			_, err := testQueries.CreateSkill(context.Background(), CreateSkillParams{SkillName: name})
+			require.NoError(t, err)
+		}
+
+		skills := search(t, false, term+"50%", 10)
+		require.Len(t, skills, 1)
+		require.Equal(t, term+"50%", skills[0].SkillName)
+
+		skills = search(t, false, term+"a_b", 10)
+		require.Len(t, skills, 1)
+		require.Equal(t, term+"a_b", skills[0].SkillName)
+	})
+}
+
+////////////////////////////////////////////////////////////////////////
@@ -3,13 +3,20 @@
 package db
 
 import (
+	"cmp"
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -18,22 +25,45 @@ import (
 // Store Definition
 ////////////////////////////////////////////////////////////////////////
 
+// DefaultTxTimeout limits one transaction when SetTxTimeout has not been called.
+const DefaultTxTimeout = 10 * time.Second
+
 // Store provides all functions to execute db queries and transactions.
 type Store struct {
 	*Queries
-	dbpool *pgxpool.Pool
+	dbpool    *pgxpool.Pool
+	txTimeout time.Duration // Limit on one transaction; 0 or negative means none
 }
 
 // NewStore creates a new Store.
 func NewStore(dbpool *pgxpool.Pool) *Store {
 	return &Store{
-		dbpool:  dbpool,
-		Queries: New(dbpool),
+		dbpool:    dbpool,
+		Queries:   New(dbpool),
+		txTimeout: DefaultTxTimeout,
 	}
 }
 
+// SetTxTimeout changes how long one transaction may run before it is rolled back
+// with context.DeadlineExceeded. Zero or negative removes the limit.
+func (s *Store) SetTxTimeout(timeout time.Duration) {
+	s.txTimeout = timeout
+}
+
+// Ping verifies that the database can be reached.
+func (s *Store) Ping(ctx context.Context) error {
+	return s.dbpool.Ping(ctx)
+}
+
 // execTx executes a function within a database transaction.
+// The transaction is bounded by the store's timeout as well as by ctx.
 func (s *Store) execTx(ctx context.Context, fn func(*Queries) error) error {
+	if s.txTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.txTimeout)
+		defer cancel()
+	}
+
 	tx, err := s.dbpool.Begin(ctx)
 	if err != nil {
 		return err
@@ -123,12 +153,16 @@ func (s *Store) OnboardNewUserWithSkills(
 type ProcessNewTaskTxParams struct {
 	CreateTaskParams    CreateTaskParams
 	RequiredSkillNames  []string
+	SkillWeights        map[string]float64 // Weight of each required skill by name; names without one get the column default of 1
+	StrictSkills        bool // Only link existing or aliased skills instead of creating unknown ones
+	ActorID             pgtype.Int8 // User creating the task, recorded in its activity log
 }
 
 // ProcessNewTaskTxResult contains the result of the ProcessNewTask transaction.
 type ProcessNewTaskTxResult struct {
-	Task               Task
-	TaskRequiredSkills []TaskRequiredSkill
+	Task                Task
+	TaskRequiredSkills  []TaskRequiredSkill
+	CandidateSkillNames []string // Unknown skill names left uncreated in strict mode
 }
 
 // ProcessNewTask creates a task and automatically links required skills extracted from its description.
@@ -139,35 +173,42 @@ func (s *Store) ProcessNewTask(
 	var result ProcessNewTaskTxResult
 
 	err := s.execTx(ctx, func(q *Queries) error {
-		// Step 1: Create the task.
-		createdTask, err := q.CreateTask(ctx, arg.CreateTaskParams)
-		if err != nil {
-			return fmt.Errorf("failed to create task: %w", err)
-		}
-		result.Task = createdTask
+		var err error
+		result, err = s._processNewTask(ctx, q, arg)
+		return err
+	})
 
-		if len(arg.RequiredSkillNames) == 0 {
-			return nil
-		}
+	return result, err
+}
 
-		// Step 2: Resolve skill names to Skill objects.
-		skillMap, err := s._resolveSkills(ctx, q, arg.RequiredSkillNames)
-		if err != nil {
-			return err
-		}
+////////////////////////////////////////////////////////////////////////
+// Transaction: ProcessManyTasksTx
+////////////////////////////////////////////////////////////////////////
 
-		// Step 3: Link all required skills to the task.
-		for _, skill := range skillMap {
-			requiredSkill, linkErr := q.AddSkillToTask(ctx, AddSkillToTaskParams{
-				TaskID:  createdTask.ID,
-				SkillID: skill.ID,
-			})
-			if linkErr != nil {
-				return fmt.Errorf("failed to link skill '%s' to task: %w", skill.SkillName, linkErr)
+// ProcessManyTasksTxParams contains a batch of tasks to create together
+type ProcessManyTasksTxParams struct {
+	Tasks []ProcessNewTaskTxParams
+}
+
+// ProcessManyTasksTxResult contains the created tasks in request order
+type ProcessManyTasksTxResult struct {
+	Tasks []ProcessNewTaskTxResult
+}
+
+// ProcessManyTasksTx creates every task of the batch like ProcessNewTask, in a single transaction:
+// if any task fails, none of them are created.
+func (s *Store) ProcessManyTasksTx(ctx context.Context, arg ProcessManyTasksTxParams) (ProcessManyTasksTxResult, error) {
+	var result ProcessManyTasksTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		result.Tasks = make([]ProcessNewTaskTxResult, 0, len(arg.Tasks))
+		for i, task := range arg.Tasks {
+			created, err := s._processNewTask(ctx, q, task)
+			if err != nil {
+				return fmt.Errorf("task %d: %w", i, err)
 			}
-			result.TaskRequiredSkills = append(result.TaskRequiredSkills, requiredSkill)
+			result.Tasks = append(result.Tasks, created)
 		}
-
 		return nil
 	})
 
@@ -180,8 +221,9 @@ func (s *Store) ProcessNewTask(
 
 // AssignTaskToUserTxParams contains the parameters for assigning a task.
 type AssignTaskToUserTxParams struct {
-	TaskID int64
-	UserID int64
+	TaskID  int64
+	UserID  int64
+	ActorID pgtype.Int8 // User making the assignment, recorded in the task's activity log
 }
 
 // AssignTaskToUserTxResult contains the updated task and user from the assignment.
@@ -191,6 +233,7 @@ type AssignTaskToUserTxResult struct {
 }
 
 // AssignTaskToUser assigns a task to a user and marks them busy within a transaction.
+// It returns ErrEngineerOnLeave if the user is on leave.
 func (s *Store) AssignTaskToUser(
 	ctx context.Context,
 	arg AssignTaskToUserTxParams,
@@ -198,9 +241,13 @@ func (s *Store) AssignTaskToUser(
 	var result AssignTaskToUserTxResult
 
 	err := s.execTx(ctx, func(q *Queries) error {
-		var err error
+		// Step 1: Note the current assignee, to tell an assignment from a reassignment.
+		previous, err := q.GetTask(ctx, arg.TaskID)
+		if err != nil {
+			return fmt.Errorf("failed to get task: %w", err)
+		}
 
-		// Step 1: Update task assignment and status.
+		// Step 2: Update task assignment and status.
 		result.Task, err = q.UpdateTask(ctx, UpdateTaskParams{
 			ID:         arg.TaskID,
 			AssigneeID: pgtype.Int8{Int64: arg.UserID, Valid: true},
@@ -210,15 +257,196 @@ func (s *Store) AssignTaskToUser(
 			return fmt.Errorf("failed to update task assignment: %w", err)
 		}
 
-		// Step 2: Update user availability to 'busy'.
-		result.User, err = q.UpdateUser(ctx, UpdateUserParams{
-			ID:           arg.UserID,
-			Availability: NullAvailabilityStatus{AvailabilityStatus: "busy", Valid: true},
+		// Step 3: Record the assignment in the task's activity log.
+		event := TaskActivityEventAssigned
+		if previous.AssigneeID.Valid {
+			event = TaskActivityEventReassigned
+		}
+		_, err = q.CreateTaskActivity(ctx, CreateTaskActivityParams{
+			TaskID:     arg.TaskID,
+			ActorID:    arg.ActorID,
+			Event:      event,
+			AssigneeID: pgtype.Int8{Int64: arg.UserID, Valid: true},
 		})
 		if err != nil {
+			return fmt.Errorf("failed to record task assignment: %w", err)
+		}
+
+		// Step 4: Update user availability to 'busy', refusing engineers on leave.
+		result.User, err = s._markEngineerBusy(ctx, q, arg.UserID)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+////////////////////////////////////////////////////////////////////////
+// Transaction: ClaimTaskTx
+////////////////////////////////////////////////////////////////////////
+
+// ClaimTaskTxParams contains parameters for an engineer claiming an open task
+type ClaimTaskTxParams struct {
+	TaskID     int64
+	EngineerID int64
+	TeamID     int64 // The engineer's team; only tasks of its projects can be claimed
+}
+
+// ErrTaskNotClaimable is returned when the task is no longer open and unassigned on the engineer's team
+var ErrTaskNotClaimable = errors.New("task is not open and unassigned on this team")
+
+// ClaimTaskTx lets an available engineer assign an open, unassigned task of their team to themselves.
+// Like AssignTaskToUser, the task goes in progress and the engineer becomes busy. Both checks are
+// conditional updates, so when engineers race for one task exactly one of them wins.
+func (s *Store) ClaimTaskTx(ctx context.Context, arg ClaimTaskTxParams) (AssignTaskToUserTxResult, error) {
+	var result AssignTaskToUserTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		var err error
+		engineerID := pgtype.Int8{Int64: arg.EngineerID, Valid: true}
+
+		// Step 1: Mark the engineer busy, refusing if they are not available
+		result.User, err = q.MarkUserBusyIfAvailable(ctx, arg.EngineerID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return s._unavailableError(ctx, q, arg.EngineerID)
+			}
 			return fmt.Errorf("failed to update user availability: %w", err)
 		}
 
+		// Step 2: Claim the task, guarded on it still being open, unassigned and on the team
+		result.Task, err = q.ClaimTask(ctx, ClaimTaskParams{
+			ID:         arg.TaskID,
+			AssigneeID: engineerID,
+			TeamID:     arg.TeamID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrTaskNotClaimable
+			}
+			return fmt.Errorf("failed to claim task: %w", err)
+		}
+
+		// Step 3: Record the self-assignment in the task's activity log
+		_, err = q.CreateTaskActivity(ctx, CreateTaskActivityParams{
+			TaskID:     arg.TaskID,
+			ActorID:    engineerID,
+			Event:      TaskActivityEventAssigned,
+			AssigneeID: engineerID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record task assignment: %w", err)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+////////////////////////////////////////////////////////////////////////
+// Transaction: ReassignTaskTx
+////////////////////////////////////////////////////////////////////////
+
+// ReassignTaskTxParams contains parameters for a manager handing a task to another engineer
+type ReassignTaskTxParams struct {
+	TaskID        int64
+	NewAssigneeID int64
+	TeamID        int64       // The manager's team; the new assignee must be an engineer on it
+	ActorID       pgtype.Int8 // Manager making the reassignment, recorded in the task's activity log
+}
+
+// ReassignTaskTxResult contains the reassigned task and both engineers after their availability changed
+type ReassignTaskTxResult struct {
+	Task             Task
+	NewAssignee      User
+	PreviousAssignee *User // Nil when the task had no assignee
+}
+
+// Error definitions for reassigning tasks
+var (
+	ErrTaskNotReassignable = errors.New("only active tasks that are not done can be reassigned")
+	ErrAssigneeNotOnTeam   = errors.New("new assignee must be an engineer on your team")
+	ErrTaskAlreadyAssigned = errors.New("task is already assigned to this engineer")
+)
+
+// ReassignTaskTx hands a task to an available engineer of the team in one transaction: the new engineer
+// becomes busy, the task goes in progress under them, the previous assignee is freed unless they have
+// other work in progress, and the reassignment is logged. Any failure leaves all of it unchanged.
+func (s *Store) ReassignTaskTx(ctx context.Context, arg ReassignTaskTxParams) (ReassignTaskTxResult, error) {
+	var result ReassignTaskTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		// Step 1: Check the task can change hands, remembering who had it
+		task, err := q.GetTask(ctx, arg.TaskID)
+		if err != nil {
+			return fmt.Errorf("failed to get task: %w", err)
+		}
+		if task.Archived || task.Status == TaskStatusDone {
+			return ErrTaskNotReassignable
+		}
+		if task.AssigneeID.Valid && task.AssigneeID.Int64 == arg.NewAssigneeID {
+			return ErrTaskAlreadyAssigned
+		}
+
+		// Step 2: Validate the new assignee is an engineer on the team
+		newAssignee, err := q.GetUser(ctx, arg.NewAssigneeID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrAssigneeNotOnTeam
+			}
+			return fmt.Errorf("failed to get new assignee: %w", err)
+		}
+		if newAssignee.Role != UserRoleEngineer || !newAssignee.TeamID.Valid || newAssignee.TeamID.Int64 != arg.TeamID {
+			return ErrAssigneeNotOnTeam
+		}
+
+		// Step 3: Mark the new assignee busy, refusing if they are not available
+		result.NewAssignee, err = q.MarkUserBusyIfAvailable(ctx, arg.NewAssigneeID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return s._unavailableError(ctx, q, arg.NewAssigneeID)
+			}
+			return fmt.Errorf("failed to update new assignee availability: %w", err)
+		}
+
+		// Step 4: Hand the task over
+		result.Task, err = q.UpdateTask(ctx, UpdateTaskParams{
+			ID:         arg.TaskID,
+			AssigneeID: pgtype.Int8{Int64: arg.NewAssigneeID, Valid: true},
+			Status:     NullTaskStatus{TaskStatus: TaskStatusInProgress, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update task assignment: %w", err)
+		}
+
+		// Step 5: Free the previous assignee unless they still have another task in progress
+		if task.AssigneeID.Valid {
+			previous, err := s._releaseEngineer(ctx, q, task.AssigneeID.Int64)
+			if err != nil {
+				return err
+			}
+			result.PreviousAssignee = &previous
+		}
+
+		// Step 6: Record the reassignment in the task's activity log
+		event := TaskActivityEventAssigned
+		if task.AssigneeID.Valid {
+			event = TaskActivityEventReassigned
+		}
+		_, err = q.CreateTaskActivity(ctx, CreateTaskActivityParams{
+			TaskID:     arg.TaskID,
+			ActorID:    arg.ActorID,
+			Event:      event,
+			AssigneeID: pgtype.Int8{Int64: arg.NewAssigneeID, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record task reassignment: %w", err)
+		}
+
 		return nil
 	})
 
@@ -231,10 +459,11 @@ func (s *Store) AssignTaskToUser(
 
 // CreateInvitationTxParams contains the input parameters for the CreateInvitation transaction.
 type CreateInvitationTxParams struct {
-	InviterID     int64       // ID of the user sending the invitation
-	EmailToInvite string      // Email address of the invitee
-	RoleToInvite  UserRole    // Role to assign to the invitee (manager or engineer)
-	TeamID        pgtype.Int8 // Required for manager invites; auto-derived for engineer invites
+	InviterID     int64         // ID of the user sending the invitation
+	EmailToInvite string        // Email address of the invitee
+	RoleToInvite  UserRole      // Role to assign to the invitee (manager or engineer)
+	TeamID        pgtype.Int8   // Required for manager invites; auto-derived for engineer invites
+	Duration      time.Duration // How long the invitation can be accepted; 0 uses InvitationDuration
 }
 
 // CreateInvitationTxResult contains the result of the CreateInvitation transaction.
@@ -242,6 +471,10 @@ type CreateInvitationTxResult struct {
 	Invitation CreateInvitationRow // Full invitation details with inviter info
 }
 
+// InvitationDuration is how long an invitation can be accepted after it is sent or resent,
+// unless the caller asks for a different duration.
+const InvitationDuration = 72 * time.Hour
+
 // Error definitions for invitation creation
 var (
 	ErrPermissionDenied           = errors.New("user does not have permission for this action")
@@ -253,6 +486,16 @@ var (
 	ErrTeamAlreadyHasManager      = errors.New("the specified team already has a manager assigned")
 )
 
+// oneManagerPerTeamIndex is the partial unique index that lets a team have at most one manager
+const oneManagerPerTeamIndex = "idx_users_one_manager_per_team"
+
+// IsTeamManagerConflict reports whether err is a violation of the one-manager-per-team index,
+// which the application checks can miss when two managers join the same team concurrently
+func IsTeamManagerConflict(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == oneManagerPerTeamIndex
+}
+
 // CreateInvitationTx handles the creation of a new user invitation within a database transaction.
 // Enforces strict role hierarchy: admins can only invite managers, managers can only invite engineers.
 // Ensures team assignment rules and prevents duplicate invitations.
@@ -340,8 +583,8 @@ func (s *Store) CreateInvitationTx(ctx context.Context, arg CreateInvitationTxPa
 		}
 
 		// Step 5: Set invitation expiration time
-		// Invitations expire after 72 hours (3 days) from creation
-		expirationTime := time.Now().Add(72 * time.Hour)
+		// Invitations expire after the requested duration, 72 hours (3 days) by default
+		expirationTime := time.Now().Add(cmp.Or(arg.Duration, InvitationDuration))
 
 		// Step 6: Create the invitation record with all validated parameters
 		createParams := CreateInvitationParams{
@@ -370,6 +613,77 @@ func (s *Store) CreateInvitationTx(ctx context.Context, arg CreateInvitationTxPa
 	return result, err
 }
 
+////////////////////////////////////////////////////////////////////////
+// Transaction: ResendInvitationTx
+////////////////////////////////////////////////////////////////////////
+
+// ResendInvitationTxParams contains the parameters for resending an invitation.
+type ResendInvitationTxParams struct {
+	InvitationID int64
+	InviterID    int64         // Only the user who sent the invitation may resend it
+	Duration     time.Duration // How long the new token can be accepted; 0 uses InvitationDuration
+}
+
+// ResendInvitationTxResult contains the invitation with its new token and expiry.
+type ResendInvitationTxResult struct {
+	Invitation RefreshInvitationTokenRow
+}
+
+// Error definitions for resending invitations
+var (
+	ErrInvitationNotFound      = errors.New("invitation not found")
+	ErrInvitationNotResendable = errors.New("only pending invitations can be resent")
+)
+
+// ResendInvitationTx gives a pending invitation a new token and a fresh expiry window.
+// Invitations past their expiry are still pending and can be resent; accepted, expired or
+// revoked ones cannot. The old token stops working.
+func (s *Store) ResendInvitationTx(ctx context.Context, arg ResendInvitationTxParams) (ResendInvitationTxResult, error) {
+	var result ResendInvitationTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		// Step 1: Check the invitation exists and belongs to the caller
+		invitation, err := q.GetInvitationByID(ctx, arg.InvitationID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrInvitationNotFound
+			}
+			return fmt.Errorf("failed to get invitation: %w", err)
+		}
+		if invitation.InviterID != arg.InviterID {
+			return fmt.Errorf("%w: only the sender of an invitation can resend it", ErrPermissionDenied)
+		}
+		if invitation.Status != "pending" {
+			return ErrInvitationNotResendable
+		}
+
+		// Step 2: Generate a new token, the same way CreateInvitationTx does
+		token, err := uuid.NewRandom()
+		if err != nil {
+			return fmt.Errorf("failed to generate invitation token: %w", err)
+		}
+
+		// Step 3: Swap in the token and expiry; no rows means it stopped being pending meanwhile
+		result.Invitation, err = q.RefreshInvitationToken(ctx, RefreshInvitationTokenParams{
+			ID:              arg.InvitationID,
+			InvitationToken: token.String(),
+			ExpiresAt: pgtype.Timestamp{
+				Time:  time.Now().Add(cmp.Or(arg.Duration, InvitationDuration)),
+				Valid: true,
+			},
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrInvitationNotResendable
+			}
+			return fmt.Errorf("failed to refresh invitation: %w", err)
+		}
+		return nil
+	})
+
+	return result, err
+}
+
 ////////////////////////////////////////////////////////////////////////
 // Transaction: AcceptInvitationTx
 ////////////////////////////////////////////////////////////////////////
@@ -428,6 +742,10 @@ func (s *Store) AcceptInvitationTx(ctx context.Context, arg AcceptInvitationTxPa
 
 		user, err := q.CreateUser(ctx, createUserParams)
 		if err != nil {
+			// Another manager invitation for the same team was accepted first
+			if IsTeamManagerConflict(err) {
+				return ErrTeamAlreadyHasManager
+			}
 			return fmt.Errorf("failed to create user: %w", err)
 		}
 		result.User = user
@@ -491,88 +809,319 @@ func (s *Store) AcceptInvitationTx(ctx context.Context, arg AcceptInvitationTxPa
 }
 
 ////////////////////////////////////////////////////////////////////////
-// Transaction: SafeDeleteUserTx
+// Transaction: CreatePasswordResetTx / ResetPasswordTx / ChangePasswordTx
 ////////////////////////////////////////////////////////////////////////
 
-// SafeDeleteUserTxParams contains the parameters for safely deleting a user
-type SafeDeleteUserTxParams struct {
-	UserID int64
-}
+// PasswordResetTokenDuration is how long a password reset token stays valid.
+const PasswordResetTokenDuration = time.Hour
 
-// SafeDeleteUserTxResult contains the result of the safe user deletion
-type SafeDeleteUserTxResult struct {
-	DeletedUser        User    // The user that was deleted
-	UpdatedTasks       []Task  // Tasks that had assignee_id set to NULL
-	UpdatedTeams       []Team  // Teams that had manager_id set to NULL
-	RemovedSkills      int64   // Count of user_skills entries removed (CASCADE)
-	RemovedInvitations int64   // Count of invitations removed (CASCADE)
+// ResetPasswordTxParams contains the parameters for redeeming a password reset token.
+type ResetPasswordTxParams struct {
+	Token        string // Token from the reset email
+	PasswordHash string // Pre-hashed new password
 }
 
-// SafeDeleteUserTx safely removes a user and handles all cascading effects
-// according to the database schema foreign key constraints:
-// - tasks.assignee_id → users.id [SET NULL]: Tasks are unassigned and reset to "open"
-// - teams.manager_id → users.id [SET NULL]: Teams become unmanaged
-// - user_skills.user_id → users.id [CASCADE]: Skills are automatically removed
-// - invitations.inviter_id → users.id [CASCADE]: Invitations are automatically removed
-func (s *Store) SafeDeleteUserTx(ctx context.Context, arg SafeDeleteUserTxParams) (SafeDeleteUserTxResult, error) {
-	var result SafeDeleteUserTxResult
+// Error definitions for password reset
+var (
+	ErrPasswordResetTokenInvalid = errors.New("password reset token is invalid, expired or already used")
+)
+
+// CreatePasswordResetTx issues a new reset token for the user with the given email.
+// Returns pgx.ErrNoRows when no such user exists; callers must not reveal that to the client.
+func (s *Store) CreatePasswordResetTx(ctx context.Context, email string) (PasswordResetToken, error) {
+	var result PasswordResetToken
 
 	err := s.execTx(ctx, func(q *Queries) error {
-		// Step 1: Get the user to be deleted for validation and result
-		user, err := q.GetUser(ctx, arg.UserID)
+		// Step 1: Look up the account the reset is for
+		user, err := q.GetUserByEmail(ctx, email)
 		if err != nil {
-			return fmt.Errorf("failed to get user for deletion: %w", err)
+			return err
 		}
-		result.DeletedUser = user
 
-		// Step 2: CRITICAL BUSINESS RULE - Prevent admin deletion for system integrity
-		if user.Role == UserRoleAdmin {
-			return fmt.Errorf("admin users cannot be deleted for system integrity")
+		// Step 2: Generate a secure reset token, the same way invitation tokens are made
+		token, err := uuid.NewRandom()
+		if err != nil {
+			return fmt.Errorf("failed to generate password reset token: %w", err)
 		}
 
-		// Step 3: Handle tasks assigned to this user (SET NULL per schema)
-		// Get all tasks assigned to this user
-		assignedTasks, err := q.ListTasksByAssignee(ctx, ListTasksByAssigneeParams{
-			AssigneeID: pgtype.Int8{Int64: arg.UserID, Valid: true},
-			Limit:      1000, // High limit to get all tasks
-			Offset:     0,
+		// Step 3: Store the token with a short expiry
+		result, err = q.CreatePasswordResetToken(ctx, CreatePasswordResetTokenParams{
+			UserID: user.ID,
+			Token:  token.String(),
+			ExpiresAt: pgtype.Timestamp{
+				Time:  time.Now().Add(PasswordResetTokenDuration),
+				Valid: true,
+			},
 		})
 		if err != nil {
-			return fmt.Errorf("failed to get assigned tasks: %w", err)
+			return fmt.Errorf("failed to create password reset token: %w", err)
 		}
+		return nil
+	})
 
-		// Update each task to remove the assignee and reset status to "open"
-		for _, task := range assignedTasks {
-			updatedTask, err := q.UpdateTask(ctx, UpdateTaskParams{
-				ID:          task.ID,
-				AssigneeID:  pgtype.Int8{Valid: false}, // SET NULL
-				Status:      NullTaskStatus{TaskStatus: "open", Valid: true}, // Reset to open
-			})
-			if err != nil {
-				return fmt.Errorf("failed to unassign task %d: %w", task.ID, err)
+	return result, err
+}
+
+// ResetPasswordTx consumes a reset token, sets the owner's new password and revokes their refresh tokens.
+// Consuming the token and changing the password happen together, so a token works exactly once,
+// and the owner's other outstanding reset tokens are consumed with it.
+func (s *Store) ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) error {
+	return s.execTx(ctx, func(q *Queries) error {
+		// Step 1: Consume the token; unknown, expired and used tokens all come back empty
+		resetToken, err := q.ConsumePasswordResetToken(ctx, arg.Token)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrPasswordResetTokenInvalid
 			}
-			result.UpdatedTasks = append(result.UpdatedTasks, updatedTask)
+			return fmt.Errorf("failed to consume password reset token: %w", err)
 		}
 
-		// Step 4: Handle teams managed by this user (SET NULL per schema)
-		if user.Role == UserRoleManager {
-			// Find team(s) managed by this user
-			team, err := q.GetTeamByManagerID(ctx, pgtype.Int8{Int64: arg.UserID, Valid: true})
-			if err == nil {
-				// Team found, remove manager (SET NULL)
-				updatedTeam, err := q.SetTeamManager(ctx, SetTeamManagerParams{
-					ID:        team.ID,
-					ManagerID: pgtype.Int8{Valid: false}, // SET NULL
-				})
-				if err != nil {
-					return fmt.Errorf("failed to remove manager from team %d: %w", team.ID, err)
-				}
-				result.UpdatedTeams = append(result.UpdatedTeams, updatedTeam)
-				
-				// NOTE: Projects remain with the team (projects.team_id relationship intact)
-				// The team still exists, it just doesn't have a manager
-			}
-			// If no team found (err != nil), it's fine - user might not manage any team
+		// Step 2: Invalidate any other reset links the user was sent
+		if err := q.ConsumePasswordResetTokensByUser(ctx, resetToken.UserID); err != nil {
+			return fmt.Errorf("failed to consume other password reset tokens: %w", err)
+		}
+
+		// Step 3: Store the new password hash
+		err = q.UpdateUserPassword(ctx, UpdateUserPasswordParams{
+			ID:           resetToken.UserID,
+			PasswordHash: arg.PasswordHash,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update password: %w", err)
+		}
+
+		// Step 4: End every session, so a stolen refresh token stops working with the old password
+		if err := q.RevokeRefreshTokensByUser(ctx, resetToken.UserID); err != nil {
+			return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+		}
+		return nil
+	})
+}
+
+// ChangePasswordTx stores a user's new password and revokes all of their refresh tokens,
+// so every session, including the caller's, has to log in again once its access token expires.
+func (s *Store) ChangePasswordTx(ctx context.Context, arg UpdateUserPasswordParams) error {
+	return s.execTx(ctx, func(q *Queries) error {
+		// Step 1: Store the new password hash
+		if err := q.UpdateUserPassword(ctx, arg); err != nil {
+			return fmt.Errorf("failed to update password: %w", err)
+		}
+
+		// Step 2: End every session opened with the old password
+		if err := q.RevokeRefreshTokensByUser(ctx, arg.ID); err != nil {
+			return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+		}
+		return nil
+	})
+}
+
+////////////////////////////////////////////////////////////////////////
+// Transaction: IssueRefreshToken / RotateRefreshTokenTx / RevokeRefreshToken
+////////////////////////////////////////////////////////////////////////
+
+// IssueRefreshTokenParams contains the parameters for issuing a refresh token
+type IssueRefreshTokenParams struct {
+	UserID   int64
+	Duration time.Duration // How long the token stays valid
+}
+
+// IssueRefreshTokenResult holds a new refresh token. Token is the only copy of the secret;
+// the database keeps just its hash.
+type IssueRefreshTokenResult struct {
+	Token        string
+	RefreshToken RefreshToken
+}
+
+// RotateRefreshTokenTxParams contains the parameters for exchanging a refresh token for a new one
+type RotateRefreshTokenTxParams struct {
+	Token    string        // Refresh token presented by the client
+	Duration time.Duration // How long the replacement stays valid
+}
+
+// RotateRefreshTokenTxResult holds the token's owner, as stored now, and the replacement token
+type RotateRefreshTokenTxResult struct {
+	User User
+	IssueRefreshTokenResult
+}
+
+// Error definitions for refresh tokens
+var (
+	ErrRefreshTokenInvalid = errors.New("refresh token is invalid, expired or revoked")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used; all sessions have been revoked")
+)
+
+// IssueRefreshToken creates a new refresh token for a user, e.g. on login.
+func (s *Store) IssueRefreshToken(ctx context.Context, arg IssueRefreshTokenParams) (IssueRefreshTokenResult, error) {
+	return s._issueRefreshToken(ctx, s.Queries, arg)
+}
+
+// RotateRefreshTokenTx exchanges a usable refresh token for a new one and revokes the old one.
+// Presenting a token that was already rotated means it leaked, so every refresh token the user
+// holds is revoked and ErrRefreshTokenReused is returned.
+func (s *Store) RotateRefreshTokenTx(ctx context.Context, arg RotateRefreshTokenTxParams) (RotateRefreshTokenTxResult, error) {
+	var result RotateRefreshTokenTxResult
+	reused := false
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		// Step 1: Find and lock the presented token, so a concurrent refresh waits for this one
+		current, err := q.GetRefreshTokenByHashForUpdate(ctx, hashRefreshToken(arg.Token))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrRefreshTokenInvalid
+			}
+			return fmt.Errorf("failed to get refresh token: %w", err)
+		}
+
+		// Step 2: A rotated token coming back is reuse. The revocation must be committed,
+		// so it is reported after the transaction instead of rolling it back
+		if current.RevokedAt.Valid {
+			if !current.ReplacedBy.Valid {
+				return ErrRefreshTokenInvalid
+			}
+			if err := q.RevokeRefreshTokensByUser(ctx, current.UserID); err != nil {
+				return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+			}
+			reused = true
+			return nil
+		}
+		if !current.ExpiresAt.Time.After(time.Now()) {
+			return ErrRefreshTokenInvalid
+		}
+
+		// Step 3: Load the owner so the new access token carries their current role and team
+		result.User, err = q.GetUser(ctx, current.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to get refresh token owner: %w", err)
+		}
+
+		// Step 4: Issue the replacement and revoke the presented token in its favour
+		result.IssueRefreshTokenResult, err = s._issueRefreshToken(ctx, q, IssueRefreshTokenParams{
+			UserID:   current.UserID,
+			Duration: arg.Duration,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.RevokeRefreshToken(ctx, RevokeRefreshTokenParams{
+			ID:         current.ID,
+			ReplacedBy: pgtype.Int8{Int64: result.RefreshToken.ID, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to revoke rotated refresh token: %w", err)
+		}
+		return nil
+	})
+	if err == nil && reused {
+		return RotateRefreshTokenTxResult{}, ErrRefreshTokenReused
+	}
+
+	return result, err
+}
+
+// RevokeRefreshToken revokes a refresh token, e.g. on logout.
+// Returns ErrRefreshTokenInvalid if the token is unknown or was no longer usable.
+func (s *Store) RevokeRefreshToken(ctx context.Context, token string) error {
+	rows, err := s.RevokeRefreshTokenByHash(ctx, hashRefreshToken(token))
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	if rows == 0 {
+		return ErrRefreshTokenInvalid
+	}
+	return nil
+}
+
+// hashRefreshToken returns the hex-encoded SHA-256 of a refresh token, the form it is stored in.
+// The token is random and high-entropy, so a fast unsalted hash is enough.
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+////////////////////////////////////////////////////////////////////////
+// Transaction: SafeDeleteUserTx
+////////////////////////////////////////////////////////////////////////
+
+// SafeDeleteUserTxParams contains the parameters for safely deleting a user
+type SafeDeleteUserTxParams struct {
+	UserID int64
+}
+
+// SafeDeleteUserTxResult contains the result of the safe user deletion
+type SafeDeleteUserTxResult struct {
+	DeletedUser        User    // The user that was deleted
+	UpdatedTasks       []Task  // Tasks that had assignee_id set to NULL
+	UpdatedTeams       []Team  // Teams that had manager_id set to NULL
+	RemovedSkills      int64   // Count of user_skills entries removed (CASCADE)
+	RemovedInvitations int64   // Count of invitations removed (CASCADE)
+}
+
+// SafeDeleteUserTx safely removes a user and handles all cascading effects
+// according to the database schema foreign key constraints:
+// - tasks.assignee_id → users.id [SET NULL]: Tasks are unassigned and reset to "open"
+// - teams.manager_id → users.id [SET NULL]: Teams become unmanaged
+// - user_skills.user_id → users.id [CASCADE]: Skills are automatically removed
+// - invitations.inviter_id → users.id [CASCADE]: Invitations are automatically removed
+func (s *Store) SafeDeleteUserTx(ctx context.Context, arg SafeDeleteUserTxParams) (SafeDeleteUserTxResult, error) {
+	var result SafeDeleteUserTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		// Step 1: Get the user to be deleted for validation and result
+		user, err := q.GetUser(ctx, arg.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to get user for deletion: %w", err)
+		}
+		result.DeletedUser = user
+
+		// Step 2: CRITICAL BUSINESS RULE - Prevent admin deletion for system integrity
+		if user.Role == UserRoleAdmin {
+			return fmt.Errorf("admin users cannot be deleted for system integrity")
+		}
+
+		// Step 3: Handle tasks assigned to this user (SET NULL per schema)
+		// Get all tasks assigned to this user
+		assignedTasks, err := q.ListTasksByAssignee(ctx, ListTasksByAssigneeParams{
+			AssigneeID: pgtype.Int8{Int64: arg.UserID, Valid: true},
+			Limit:      1000, // High limit to get all tasks
+			Offset:     0,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get assigned tasks: %w", err)
+		}
+
+		// Update each task to remove the assignee and reset status to "open"
+		for _, task := range assignedTasks {
+			updatedTask, err := q.UpdateTask(ctx, UpdateTaskParams{
+				ID:          task.ID,
+				AssigneeID:  pgtype.Int8{Valid: false}, // SET NULL
+				Status:      NullTaskStatus{TaskStatus: "open", Valid: true}, // Reset to open
+			})
+			if err != nil {
+				return fmt.Errorf("failed to unassign task %d: %w", task.ID, err)
+			}
+			result.UpdatedTasks = append(result.UpdatedTasks, updatedTask)
+		}
+
+		// Step 4: Handle teams managed by this user (SET NULL per schema)
+		if user.Role == UserRoleManager {
+			// Find team(s) managed by this user
+			team, err := q.GetTeamByManagerID(ctx, pgtype.Int8{Int64: arg.UserID, Valid: true})
+			if err == nil {
+				// Team found, remove manager (SET NULL)
+				updatedTeam, err := q.SetTeamManager(ctx, SetTeamManagerParams{
+					ID:        team.ID,
+					ManagerID: pgtype.Int8{Valid: false}, // SET NULL
+				})
+				if err != nil {
+					return fmt.Errorf("failed to remove manager from team %d: %w", team.ID, err)
+				}
+				result.UpdatedTeams = append(result.UpdatedTeams, updatedTeam)
+				
+				// NOTE: Projects remain with the team (projects.team_id relationship intact)
+				// The team still exists, it just doesn't have a manager
+			}
+			// If no team found (err != nil), it's fine - user might not manage any team
 		}
 
 		// Step 5: Count user skills before deletion (CASCADE will handle automatic removal)
@@ -698,194 +1247,1571 @@ func (s *Store) GetUserDeletionImpactTx(ctx context.Context, arg GetUserDeletion
 
 
 ////////////////////////////////////////////////////////////////////////
-// Transaction: ValidateUserRoleChangeTx
+// Transaction: SafeDeleteTeamTx / GetTeamDeletionImpactTx (Dry-Run)
 ////////////////////////////////////////////////////////////////////////
 
-// ValidateUserRoleChangeTxParams contains parameters for role change validation
-type ValidateUserRoleChangeTxParams struct {
-	UserID  int64     // ID of user whose role is being changed
-	NewRole UserRole  // The role they're being changed to
-	TeamID  *int64    // Optional team assignment (required for manager promotion)
+// SafeDeleteTeamTxParams contains the parameters for safely deleting a team
+type SafeDeleteTeamTxParams struct {
+	TeamID int64
 }
 
-// ValidateUserRoleChangeTxResult contains the result of role change validation
-type ValidateUserRoleChangeTxResult struct {
-	IsValid      bool   // Whether the role change is valid
-	ErrorMessage string // Error message if not valid
-	CurrentUser  User   // Current user information
-	TargetTeam   *Team  // Target team if promoting to manager
-	ManagedTeam  *Team  // Currently managed team if demoting from manager
+// SafeDeleteTeamTxResult contains the result of the safe team deletion
+type SafeDeleteTeamTxResult struct {
+	DeletedTeam        Team   // The team that was deleted
+	RemovedMembers     []User // Members that had team_id set to NULL
+	RemovedProjects    int64  // Count of archived projects removed, with their tasks (CASCADE)
+	RemovedInvitations int64  // Count of invitations to the team removed
 }
 
-// ValidateUserRoleChangeTx checks if a role change is valid according to business rules:
-// 1. Admin roles cannot be changed (system integrity)
-// 2. Cannot promote users to admin role (security)
-// 3. Manager promotion requires a team without existing manager
-// 4. Manager demotion requires handling of currently managed team
-func (s *Store) ValidateUserRoleChangeTx(ctx context.Context, arg ValidateUserRoleChangeTxParams) (ValidateUserRoleChangeTxResult, error) {
-	var result ValidateUserRoleChangeTxResult
+// ErrTeamHasActiveProjects blocks deleting a team that still has live work
+var ErrTeamHasActiveProjects = errors.New("team has active projects; archive them before deleting the team")
+
+// SafeDeleteTeamTx removes a team once all of its projects are archived, handling every reference to it:
+// - users.team_id → teams.id [SET NULL]: Members, including the manager, are unassigned explicitly and returned
+// - projects.team_id → teams.id [CASCADE]: Archived projects and their tasks are removed
+// - invitations.team_id → teams.id [SET NULL]: Invitations to the team are deleted rather than left dangling
+func (s *Store) SafeDeleteTeamTx(ctx context.Context, arg SafeDeleteTeamTxParams) (SafeDeleteTeamTxResult, error) {
+	var result SafeDeleteTeamTxResult
+	teamID := pgtype.Int8{Int64: arg.TeamID, Valid: true}
 
 	err := s.execTx(ctx, func(q *Queries) error {
-		// Get current user information
-		user, err := q.GetUser(ctx, arg.UserID)
+		// Step 1: Get the team to be deleted for validation and result
+		team, err := q.GetTeam(ctx, arg.TeamID)
 		if err != nil {
-			return fmt.Errorf("user not found: %w", err)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrTeamNotFound
+			}
+			return fmt.Errorf("failed to get team for deletion: %w", err)
 		}
-		result.CurrentUser = user
-
-		// Apply business rule validations
-		switch {
-		case user.Role == arg.NewRole:
-			// No change needed
-			result.IsValid = false
-			result.ErrorMessage = "user already has this role"
-			return nil
+		result.DeletedTeam = team
 
-		case user.Role == UserRoleAdmin:
-			// BUSINESS RULE: Admin role cannot be changed (system integrity)
-			result.IsValid = false
-			result.ErrorMessage = "admin role cannot be changed"
-			return nil
-
-		case arg.NewRole == UserRoleAdmin:
-			// BUSINESS RULE: Cannot promote users to admin role (security)
-			result.IsValid = false
-			result.ErrorMessage = "cannot promote users to admin role"
-			return nil
-
-		case arg.NewRole == UserRoleManager:
-			// BUSINESS RULE: Manager promotion requires team assignment
-			if arg.TeamID == nil {
-				result.IsValid = false
-				result.ErrorMessage = "team assignment required when promoting to manager"
-				return nil
-			}
+		// Step 2: BUSINESS RULE - Active projects must be archived first
+		activeProjects, err := q.CountActiveProjectsByTeam(ctx, arg.TeamID)
+		if err != nil {
+			return fmt.Errorf("failed to count active projects: %w", err)
+		}
+		if activeProjects > 0 {
+			return ErrTeamHasActiveProjects
+		}
 
-			// BUSINESS RULE: Target team must exist and have no current manager
-			team, err := q.GetTeam(ctx, *arg.TeamID)
-			if err != nil {
-				result.IsValid = false
-				result.ErrorMessage = "target team not found"
-				return nil
-			}
+		// Step 3: Count archived projects before deletion (CASCADE will handle automatic removal)
+		result.RemovedProjects, err = q.CountArchivedProjectsByTeam(ctx, arg.TeamID)
+		if err != nil {
+			return fmt.Errorf("failed to count archived projects: %w", err)
+		}
 
-			if team.ManagerID.Valid {
-				result.IsValid = false
-				result.ErrorMessage = "target team already has a manager"
-				return nil
-			}
+		// Step 4: Unassign every member
+		result.RemovedMembers, err = q.RemoveUsersFromTeam(ctx, teamID)
+		if err != nil {
+			return fmt.Errorf("failed to remove members from team: %w", err)
+		}
 
-			result.TargetTeam = &team
+		// Step 5: Delete invitations to the team; they could never be accepted
+		result.RemovedInvitations, err = q.DeleteInvitationsByTeam(ctx, teamID)
+		if err != nil {
+			return fmt.Errorf("failed to delete team invitations: %w", err)
+		}
 
-		case user.Role == UserRoleManager && arg.NewRole != UserRoleManager:
-			// BUSINESS RULE: Manager demotion requires handling current team management
-			team, err := q.GetTeamByManagerID(ctx, pgtype.Int8{Int64: arg.UserID, Valid: true})
-			if err == nil {
-				// User currently manages a team - this will be handled in the update
-				result.ManagedTeam = &team
-			}
-			// If no team found, it's fine - user doesn't manage any team
+		// Step 6: Finally, delete the team
+		err = q.DeleteTeam(ctx, arg.TeamID)
+		if err != nil {
+			return fmt.Errorf("failed to delete team: %w", err)
 		}
 
-		// All validations passed
-		result.IsValid = true
 		return nil
 	})
 
 	return result, err
 }
 
-////////////////////////////////////////////////////////////////////////
-// Transaction: ArchiveProjectTx
-////////////////////////////////////////////////////////////////////////
-
-// ArchiveProjectTxParams contains parameters for archiving a project
-type ArchiveProjectTxParams struct {
-	ProjectID int64
-	TeamID    int64
+// GetTeamDeletionImpactTxResult contains the impact analysis without actual deletion
+type GetTeamDeletionImpactTxResult struct {
+	Team                Team   // The team that would be deleted
+	MembersToUnassign   []User // Members that would have team_id set to NULL
+	ActiveProjects      int64  // Count of active projects, which block the deletion
+	ProjectsToRemove    int64  // Count of archived projects that would be removed with their tasks
+	InvitationsToRemove int64  // Count of invitations to the team that would be removed
+	CanDelete           bool   // Whether deletion is allowed (false while projects are active)
+	BlockingReason      string // Reason why deletion is blocked (if CanDelete is false)
 }
 
-// ArchiveProjectTxResult contains the result of archiving a project
-type ArchiveProjectTxResult struct {
-	ArchivedProject    Project
-	ArchivedTasksCount int64
+// GetTeamDeletionImpactTx analyzes the impact of deleting a team without actually deleting it.
+// This is a READ-ONLY transaction that mirrors the checks of SafeDeleteTeamTx for admin UI.
+func (s *Store) GetTeamDeletionImpactTx(ctx context.Context, arg SafeDeleteTeamTxParams) (GetTeamDeletionImpactTxResult, error) {
+	var result GetTeamDeletionImpactTxResult
+	teamID := pgtype.Int8{Int64: arg.TeamID, Valid: true}
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		// Step 1: Get the team for impact analysis
+		team, err := q.GetTeam(ctx, arg.TeamID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrTeamNotFound
+			}
+			return fmt.Errorf("failed to get team for impact analysis: %w", err)
+		}
+		result.Team = team
+
+		// Step 2: Check if deletion is allowed (same business rule as actual deletion)
+		result.ActiveProjects, err = q.CountActiveProjectsByTeam(ctx, arg.TeamID)
+		if err != nil {
+			return fmt.Errorf("failed to count active projects for analysis: %w", err)
+		}
+		result.CanDelete = result.ActiveProjects == 0
+		if !result.CanDelete {
+			result.BlockingReason = ErrTeamHasActiveProjects.Error()
+			// Still continue analysis to show what WOULD happen
+		}
+
+		// Step 3: Count archived projects that would be removed
+		result.ProjectsToRemove, err = q.CountArchivedProjectsByTeam(ctx, arg.TeamID)
+		if err != nil {
+			return fmt.Errorf("failed to count archived projects for analysis: %w", err)
+		}
+
+		// Step 4: Find members that would be unassigned
+		result.MembersToUnassign, err = q.ListUsersByTeam(ctx, ListUsersByTeamParams{
+			TeamID: teamID,
+			Limit:  1000, // High limit to get all members
+			Offset: 0,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get team members for analysis: %w", err)
+		}
+
+		// Step 5: Count invitations that would be removed
+		result.InvitationsToRemove, err = q.CountInvitationsByTeam(ctx, teamID)
+		if err != nil {
+			return fmt.Errorf("failed to count team invitations for analysis: %w", err)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+////////////////////////////////////////////////////////////////////////
+// Transaction: TransferUserToTeamTx
+////////////////////////////////////////////////////////////////////////
+
+// TransferUserToTeamTxParams contains the engineer to move and the team they move to
+type TransferUserToTeamTxParams struct {
+	UserID    int64
+	NewTeamID int64
+	ActorID   pgtype.Int8 // Admin making the transfer, recorded in the activity log of reopened tasks
+}
+
+// TransferUserToTeamTxResult contains the moved engineer and the work they left behind
+type TransferUserToTeamTxResult struct {
+	User            User        // The engineer after the move
+	PreviousTeamID  pgtype.Int8 // Team they left; NULL if they had none
+	UnassignedTasks []Task      // Unfinished tasks on the old team, now open and unassigned
+}
+
+// Error definitions for team transfers
+var (
+	ErrUserNotFound        = errors.New("user not found")
+	ErrTransferNotEngineer = errors.New("only engineers can be transferred between teams")
+	ErrUserAlreadyOnTeam   = errors.New("user is already on this team")
+)
+
+// TransferUserToTeamTx moves an engineer to another team. Their unfinished tasks on the old
+// team are reopened and unassigned so no task stays assigned across team boundaries,
+// and the engineer starts on the new team available.
+func (s *Store) TransferUserToTeamTx(ctx context.Context, arg TransferUserToTeamTxParams) (TransferUserToTeamTxResult, error) {
+	var result TransferUserToTeamTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		// Step 1: Validate the engineer and the move
+		user, err := q.GetUser(ctx, arg.UserID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrUserNotFound
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+		if user.Role != UserRoleEngineer {
+			return ErrTransferNotEngineer
+		}
+		if user.TeamID.Valid && user.TeamID.Int64 == arg.NewTeamID {
+			return ErrUserAlreadyOnTeam
+		}
+		result.PreviousTeamID = user.TeamID
+
+		// Step 2: The new team must exist
+		if _, err := q.GetTeam(ctx, arg.NewTeamID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrTeamNotFound
+			}
+			return fmt.Errorf("failed to get team: %w", err)
+		}
+
+		// Step 3: Hand unfinished work on the old team back to it
+		if user.TeamID.Valid {
+			released, err := q.UnassignActiveTasksInTeam(ctx, UnassignActiveTasksInTeamParams{
+				AssigneeID: pgtype.Int8{Int64: arg.UserID, Valid: true},
+				TeamID:     user.TeamID.Int64,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to unassign tasks on previous team: %w", err)
+			}
+
+			for _, row := range released {
+				result.UnassignedTasks = append(result.UnassignedTasks, Task{
+					ID:          row.ID,
+					ProjectID:   row.ProjectID,
+					Title:       row.Title,
+					Description: row.Description,
+					Status:      row.Status,
+					Priority:    row.Priority,
+					AssigneeID:  row.AssigneeID,
+					CreatedAt:   row.CreatedAt,
+					CompletedAt: row.CompletedAt,
+					Archived:    row.Archived,
+					ArchivedAt:  row.ArchivedAt,
+					DueDate:     row.DueDate,
+				})
+
+				// Paused tasks were already open; only a real status change is logged
+				if row.PreviousStatus == TaskStatusOpen {
+					continue
+				}
+				_, err := q.CreateTaskActivity(ctx, CreateTaskActivityParams{
+					TaskID:     row.ID,
+					ActorID:    arg.ActorID,
+					Event:      TaskActivityEventStatusChanged,
+					FromStatus: NullTaskStatus{TaskStatus: row.PreviousStatus, Valid: true},
+					ToStatus:   NullTaskStatus{TaskStatus: TaskStatusOpen, Valid: true},
+				})
+				if err != nil {
+					return fmt.Errorf("failed to record status change: %w", err)
+				}
+			}
+		}
+
+		// Step 4: Move the engineer; nothing on the new team is theirs yet, but a leave carries over
+		availability := AvailabilityStatusAvailable
+		if user.Availability == AvailabilityStatusOnLeave {
+			availability = AvailabilityStatusOnLeave
+		}
+		result.User, err = q.UpdateUser(ctx, UpdateUserParams{
+			ID:           arg.UserID,
+			TeamID:       pgtype.Int8{Int64: arg.NewTeamID, Valid: true},
+			Availability: NullAvailabilityStatus{AvailabilityStatus: availability, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update user team: %w", err)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+////////////////////////////////////////////////////////////////////////
+// Transaction: ReassignAllTasksTx
+////////////////////////////////////////////////////////////////////////
+
+// ReassignAllTasksTxParams contains the engineer whose work moves and the engineer taking it over
+type ReassignAllTasksTxParams struct {
+	FromUserID int64
+	ToUserID   int64
+	ActorID    pgtype.Int8 // Admin making the reassignment, recorded in each moved task's activity log
+}
+
+// ReassignAllTasksTxResult contains the moved tasks and both engineers after their availability changed
+type ReassignAllTasksTxResult struct {
+	Tasks    []Task // Open and in-progress tasks now assigned to ToUser, each with its status unchanged
+	FromUser User
+	ToUser   User
+}
+
+// Error definitions for reassigning all of an engineer's tasks
+var (
+	ErrReassignSameEngineer  = errors.New("tasks cannot be reassigned to the same engineer")
+	ErrReassignNotEngineer   = errors.New("tasks can only be reassigned between engineers")
+	ErrReassignDifferentTeam = errors.New("both engineers must be on the same team")
+)
+
+// ReassignAllTasksTx hands every active (open or in-progress) task of one engineer to an available
+// engineer on the same team, e.g. when the first is leaving. The target becomes busy if any moved
+// task is in progress, the source is freed, and each move is logged. Any failure leaves all of it unchanged.
+func (s *Store) ReassignAllTasksTx(ctx context.Context, arg ReassignAllTasksTxParams) (ReassignAllTasksTxResult, error) {
+	var result ReassignAllTasksTxResult
+
+	if arg.FromUserID == arg.ToUserID {
+		return result, ErrReassignSameEngineer
+	}
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		// Step 1: Both users must be engineers on the same team
+		fromUser, err := q.GetUser(ctx, arg.FromUserID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrUserNotFound
+			}
+			return fmt.Errorf("failed to get source user: %w", err)
+		}
+		toUser, err := q.GetUser(ctx, arg.ToUserID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrUserNotFound
+			}
+			return fmt.Errorf("failed to get target user: %w", err)
+		}
+		if fromUser.Role != UserRoleEngineer || toUser.Role != UserRoleEngineer {
+			return ErrReassignNotEngineer
+		}
+		if !fromUser.TeamID.Valid || !toUser.TeamID.Valid || fromUser.TeamID.Int64 != toUser.TeamID.Int64 {
+			return ErrReassignDifferentTeam
+		}
+
+		// Step 2: The target must be free to take the work on
+		if toUser.Availability != AvailabilityStatusAvailable {
+			return s._unavailableError(ctx, q, arg.ToUserID)
+		}
+
+		// Step 3: Move the tasks
+		result.Tasks, err = q.ReassignActiveTasks(ctx, ReassignActiveTasksParams{
+			ToAssigneeID:   pgtype.Int8{Int64: arg.ToUserID, Valid: true},
+			FromAssigneeID: pgtype.Int8{Int64: arg.FromUserID, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to reassign tasks: %w", err)
+		}
+
+		// Step 4: The target is busy if they took over work in progress; the row check guards against a concurrent claim
+		result.ToUser = toUser
+		if slices.ContainsFunc(result.Tasks, func(task Task) bool { return task.Status == TaskStatusInProgress }) {
+			result.ToUser, err = q.MarkUserBusyIfAvailable(ctx, arg.ToUserID)
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return s._unavailableError(ctx, q, arg.ToUserID)
+				}
+				return fmt.Errorf("failed to update target availability: %w", err)
+			}
+		}
+
+		// Step 5: The source has no work in progress left
+		result.FromUser, err = s._releaseEngineer(ctx, q, arg.FromUserID)
+		if err != nil {
+			return err
+		}
+
+		// Step 6: Record each move in the task's activity log
+		for _, task := range result.Tasks {
+			_, err := q.CreateTaskActivity(ctx, CreateTaskActivityParams{
+				TaskID:     task.ID,
+				ActorID:    arg.ActorID,
+				Event:      TaskActivityEventReassigned,
+				AssigneeID: pgtype.Int8{Int64: arg.ToUserID, Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to record reassignment of task %d: %w", task.ID, err)
+			}
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+////////////////////////////////////////////////////////////////////////
+// Transaction: ValidateUserRoleChangeTx
+////////////////////////////////////////////////////////////////////////
+
+// ValidateUserRoleChangeTxParams contains parameters for role change validation
+type ValidateUserRoleChangeTxParams struct {
+	UserID  int64     // ID of user whose role is being changed
+	NewRole UserRole  // The role they're being changed to
+	TeamID  *int64    // Optional team assignment (required for manager promotion)
+}
+
+// ValidateUserRoleChangeTxResult contains the result of role change validation
+type ValidateUserRoleChangeTxResult struct {
+	IsValid      bool   // Whether the role change is valid
+	ErrorMessage string // Error message if not valid
+	CurrentUser  User   // Current user information
+	TargetTeam   *Team  // Target team if promoting to manager
+	ManagedTeam  *Team  // Currently managed team if demoting from manager
+}
+
+// ValidateUserRoleChangeTx checks if a role change is valid according to business rules:
+// 1. Admin roles cannot be changed (system integrity)
+// 2. Cannot promote users to admin role (security)
+// 3. Manager promotion requires a team without existing manager
+// 4. Manager demotion requires handling of currently managed team
+func (s *Store) ValidateUserRoleChangeTx(ctx context.Context, arg ValidateUserRoleChangeTxParams) (ValidateUserRoleChangeTxResult, error) {
+	var result ValidateUserRoleChangeTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		// Get current user information
+		user, err := q.GetUser(ctx, arg.UserID)
+		if err != nil {
+			return fmt.Errorf("user not found: %w", err)
+		}
+		result.CurrentUser = user
+
+		// Apply business rule validations
+		switch {
+		case user.Role == arg.NewRole:
+			// No change needed
+			result.IsValid = false
+			result.ErrorMessage = "user already has this role"
+			return nil
+
+		case user.Role == UserRoleAdmin:
+			// BUSINESS RULE: Admin role cannot be changed (system integrity)
+			result.IsValid = false
+			result.ErrorMessage = "admin role cannot be changed"
+			return nil
+
+		case arg.NewRole == UserRoleAdmin:
+			// BUSINESS RULE: Cannot promote users to admin role (security)
+			result.IsValid = false
+			result.ErrorMessage = "cannot promote users to admin role"
+			return nil
+
+		case arg.NewRole == UserRoleManager:
+			// BUSINESS RULE: Manager promotion requires team assignment
+			if arg.TeamID == nil {
+				result.IsValid = false
+				result.ErrorMessage = "team assignment required when promoting to manager"
+				return nil
+			}
+
+			// BUSINESS RULE: Target team must exist and have no current manager
+			team, err := q.GetTeam(ctx, *arg.TeamID)
+			if err != nil {
+				result.IsValid = false
+				result.ErrorMessage = "target team not found"
+				return nil
+			}
+
+			if team.ManagerID.Valid {
+				result.IsValid = false
+				result.ErrorMessage = "target team already has a manager"
+				return nil
+			}
+
+			result.TargetTeam = &team
+
+		case user.Role == UserRoleManager && arg.NewRole != UserRoleManager:
+			// BUSINESS RULE: Manager demotion requires handling current team management
+			team, err := q.GetTeamByManagerID(ctx, pgtype.Int8{Int64: arg.UserID, Valid: true})
+			if err == nil {
+				// User currently manages a team - this will be handled in the update
+				result.ManagedTeam = &team
+			}
+			// If no team found, it's fine - user doesn't manage any team
+		}
+
+		// All validations passed
+		result.IsValid = true
+		return nil
+	})
+
+	return result, err
+}
+
+////////////////////////////////////////////////////////////////////////
+// Transaction: ArchiveProjectTx
+////////////////////////////////////////////////////////////////////////
+
+// ArchiveProjectTxParams contains parameters for archiving a project
+type ArchiveProjectTxParams struct {
+	ProjectID int64
+	TeamID    int64
+	ActorID   pgtype.Int8 // User archiving the project, recorded in each archived task's activity log
+}
+
+// ArchiveProjectTxResult contains the result of archiving a project
+type ArchiveProjectTxResult struct {
+	ArchivedProject    Project
+	ArchivedTasksCount int64
+}
+
+// Error definitions for project archiving
+var (
+	ErrProjectNotFound        = errors.New("project not found or access denied")
+	ErrProjectAlreadyArchived = errors.New("project is already archived")
+)
+
+// ArchiveProjectTx archives a project and automatically archives all its tasks.
+// Also frees up engineers who were assigned to tasks in this project.
+func (s *Store) ArchiveProjectTx(ctx context.Context, arg ArchiveProjectTxParams) (ArchiveProjectTxResult, error) {
+	var result ArchiveProjectTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		// Step 1: Validate project exists and belongs to team
+		project, err := q.GetProjectByIDAndTeam(ctx, GetProjectByIDAndTeamParams{
+			ID:     arg.ProjectID,
+			TeamID: arg.TeamID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrProjectNotFound
+			}
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+
+		// Step 2: Check if project is already archived
+		if project.Archived {
+			return ErrProjectAlreadyArchived
+		}
+
+		// Step 3: Count active tasks before archiving them
+		activeTasksCount, err := q.CountActiveTasksByProject(ctx, pgtype.Int8{Int64: arg.ProjectID, Valid: true})
+		if err != nil {
+			return fmt.Errorf("failed to count active tasks: %w", err)
+		}
+
+		// Step 4: FREE UP ENGINEERS - Get all assigned engineers before archiving tasks
+		if activeTasksCount > 0 {
+			// Get all users assigned to tasks in this project
+			assignedEngineers, err := q.GetAssignedEngineersForProject(ctx, pgtype.Int8{Int64: arg.ProjectID, Valid: true})
+			if err != nil {
+				return fmt.Errorf("failed to get assigned engineers: %w", err)
+			}
+
+			// Set all assigned engineers back to available; those on leave stay on leave
+			for _, engineer := range assignedEngineers {
+				_, err = q.SetUserAvailabilityUnlessOnLeave(ctx, SetUserAvailabilityUnlessOnLeaveParams{
+					ID:           engineer.Int64,
+					Availability: AvailabilityStatusAvailable,
+				})
+				if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+					return fmt.Errorf("failed to free engineer %d: %w", engineer.Int64, err)
+				}
+			}
+		}
+
+		// Step 5: Archive all tasks in the project, recording it in their activity logs first
+		if activeTasksCount > 0 {
+			err = q.CreateArchiveActivityByProject(ctx, CreateArchiveActivityByProjectParams{
+				ActorID:   arg.ActorID,
+				ProjectID: pgtype.Int8{Int64: arg.ProjectID, Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to record task archiving: %w", err)
+			}
+
+			err = q.ArchiveCompletedTasksByProject(ctx, pgtype.Int8{Int64: arg.ProjectID, Valid: true})
+			if err != nil {
+				return fmt.Errorf("failed to archive project tasks: %w", err)
+			}
+		}
+
+		result.ArchivedTasksCount = activeTasksCount
+
+		// Step 6: Archive the project
+		archivedProject, err := q.ArchiveProject(ctx, ArchiveProjectParams{
+			ID:     arg.ProjectID,
+			TeamID: arg.TeamID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to archive project: %w", err)
+		}
+
+		result.ArchivedProject = archivedProject
+		return nil
+	})
+
+	return result, err
+}
+
+////////////////////////////////////////////////////////////////////////
+// Transaction: UnarchiveProjectTx
+////////////////////////////////////////////////////////////////////////
+
+// UnarchiveProjectTxParams contains parameters for unarchiving a project
+type UnarchiveProjectTxParams struct {
+	ProjectID int64
+	TeamID    int64
+	ActorID   pgtype.Int8 // User unarchiving the project, recorded for each task that is reopened
+}
+
+// UnarchiveProjectTxResult contains the result of unarchiving a project
+type UnarchiveProjectTxResult struct {
+	UnarchivedProject    Project
+	UnarchivedTasksCount int64
+}
+
+// ErrProjectNotArchived is returned when unarchiving a project that is still active
+var ErrProjectNotArchived = errors.New("project is not archived")
+
+// UnarchiveProjectTx reverses ArchiveProjectTx: the project and its archived tasks become active again.
+// Done tasks keep their completion history. Tasks archived on their own before the project had their
+// engineers freed, so those come back open and unassigned.
+func (s *Store) UnarchiveProjectTx(ctx context.Context, arg UnarchiveProjectTxParams) (UnarchiveProjectTxResult, error) {
+	var result UnarchiveProjectTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		// Step 1: Validate project exists and belongs to team
+		project, err := q.GetProjectByIDAndTeam(ctx, GetProjectByIDAndTeamParams{
+			ID:     arg.ProjectID,
+			TeamID: arg.TeamID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrProjectNotFound
+			}
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+
+		// Step 2: Check the project is actually archived
+		if !project.Archived {
+			return ErrProjectNotArchived
+		}
+
+		// Step 3: Count archived tasks before restoring them
+		archivedTasksCount, err := q.CountArchivedTasksByProject(ctx, pgtype.Int8{Int64: arg.ProjectID, Valid: true})
+		if err != nil {
+			return fmt.Errorf("failed to count archived tasks: %w", err)
+		}
+
+		// Step 4: Restore the tasks, recording any reopened ones in their activity logs first
+		if archivedTasksCount > 0 {
+			err = q.CreateUnarchiveActivityByProject(ctx, CreateUnarchiveActivityByProjectParams{
+				ActorID:   arg.ActorID,
+				ProjectID: pgtype.Int8{Int64: arg.ProjectID, Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to record reopened tasks: %w", err)
+			}
+
+			err = q.UnarchiveTasksByProject(ctx, pgtype.Int8{Int64: arg.ProjectID, Valid: true})
+			if err != nil {
+				return fmt.Errorf("failed to unarchive project tasks: %w", err)
+			}
+		}
+
+		result.UnarchivedTasksCount = archivedTasksCount
+
+		// Step 5: Unarchive the project
+		unarchivedProject, err := q.UnarchiveProject(ctx, UnarchiveProjectParams{
+			ID:     arg.ProjectID,
+			TeamID: arg.TeamID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to unarchive project: %w", err)
+		}
+
+		result.UnarchivedProject = unarchivedProject
+		return nil
+	})
+
+	return result, err
+}
+
+////////////////////////////////////////////////////////////////////////
+// Transaction: ArchiveTaskTx / RestoreTaskTx
+////////////////////////////////////////////////////////////////////////
+
+// ArchiveTaskTxParams contains parameters for a manager archiving or restoring a single task
+type ArchiveTaskTxParams struct {
+	TaskID  int64
+	ActorID pgtype.Int8 // Manager archiving or restoring the task, recorded in its activity log
+}
+
+// ArchiveTaskTxResult contains the task and, when one was affected, its assignee
+type ArchiveTaskTxResult struct {
+	Task     Task
+	Assignee *User
+}
+
+// Error definitions for archiving and restoring single tasks
+var (
+	ErrTaskAlreadyArchived = errors.New("task is already archived")
+	ErrTaskNotArchived     = errors.New("task is not archived")
+	ErrTaskProjectArchived = errors.New("task belongs to an archived project; unarchive the project instead")
+)
+
+// ArchiveTaskTx archives one task without touching the rest of its project.
+// Its assignee is freed unless they still have another task in progress.
+func (s *Store) ArchiveTaskTx(ctx context.Context, arg ArchiveTaskTxParams) (ArchiveTaskTxResult, error) {
+	var result ArchiveTaskTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		// Step 1: Archive the task, guarded on it still being active
+		result.Task, err = q.ArchiveTask(ctx, arg.TaskID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrTaskAlreadyArchived
+			}
+			return fmt.Errorf("failed to archive task: %w", err)
+		}
+
+		// Step 2: Record the archiving in the task's activity log
+		_, err = q.CreateTaskActivity(ctx, CreateTaskActivityParams{
+			TaskID:  arg.TaskID,
+			ActorID: arg.ActorID,
+			Event:   TaskActivityEventArchived,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record task archiving: %w", err)
+		}
+
+		if !result.Task.AssigneeID.Valid {
+			return nil
+		}
+
+		// Step 3: Free the assignee; archived tasks no longer count as work in progress
+		assignee, err := s._releaseEngineer(ctx, q, result.Task.AssigneeID.Int64)
+		if err != nil {
+			return err
+		}
+		result.Assignee = &assignee
+
+		return nil
+	})
+
+	return result, err
+}
+
+// RestoreTaskTx reverses ArchiveTaskTx. The task comes back open and unassigned,
+// since its engineer was freed on archive. Tasks of an archived project are restored with the project instead.
+func (s *Store) RestoreTaskTx(ctx context.Context, arg ArchiveTaskTxParams) (ArchiveTaskTxResult, error) {
+	var result ArchiveTaskTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		// Step 1: Check the task is archived, remembering its status for the activity log
+		task, err := q.GetTask(ctx, arg.TaskID)
+		if err != nil {
+			return fmt.Errorf("failed to get task: %w", err)
+		}
+		if !task.Archived {
+			return ErrTaskNotArchived
+		}
+
+		// Step 2: Refuse to bring a task back into an archived project
+		if task.ProjectID.Valid {
+			project, err := q.GetProject(ctx, task.ProjectID.Int64)
+			if err != nil {
+				return fmt.Errorf("failed to get project: %w", err)
+			}
+			if project.Archived {
+				return ErrTaskProjectArchived
+			}
+		}
+
+		// Step 3: Restore the task, guarded on it still being archived
+		result.Task, err = q.UnarchiveTask(ctx, arg.TaskID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrTaskNotArchived
+			}
+			return fmt.Errorf("failed to restore task: %w", err)
+		}
+
+		// Step 4: Record the reset to open in the task's activity log
+		if task.Status != TaskStatusOpen {
+			_, err = q.CreateTaskActivity(ctx, CreateTaskActivityParams{
+				TaskID:     arg.TaskID,
+				ActorID:    arg.ActorID,
+				Event:      TaskActivityEventStatusChanged,
+				FromStatus: NullTaskStatus{TaskStatus: task.Status, Valid: true},
+				ToStatus:   NullTaskStatus{TaskStatus: TaskStatusOpen, Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to record status change: %w", err)
+			}
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+////////////////////////////////////////////////////////////////////////
+// Transaction: CompleteTaskTx
+////////////////////////////////////////////////////////////////////////
+
+// CompleteTaskTxParams contains parameters for task completion
+type CompleteTaskTxParams struct {
+	TaskID int64
+}
+
+// CompleteTaskTxResult contains the result of task completion
+type CompleteTaskTxResult struct {
+	CompletedTask Task
+	UpdatedUser   User
+}
+
+// CompleteTaskTx marks a task as completed and makes the user available again,
+// unless they still have another task in progress.
+// This is called by engineers when they finish their work.
+func (s *Store) CompleteTaskTx(ctx context.Context, arg CompleteTaskTxParams) (CompleteTaskTxResult, error) {
+	var result CompleteTaskTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		// Step 1: Get the task and validate
+		task, err := q.GetTask(ctx, arg.TaskID)
+		if err != nil {
+			return fmt.Errorf("failed to get task: %w", err)
+		}
+
+		if task.Status == "done" {
+			return errors.New("task is already completed")
+		}
+
+		if !task.AssigneeID.Valid {
+			return errors.New("task is not assigned to anyone")
+		}
+
+		// Step 2: Mark task as completed
+		completedTask, err := q.UpdateTask(ctx, UpdateTaskParams{
+			ID:          arg.TaskID,
+			Status:      NullTaskStatus{TaskStatus: "done", Valid: true},
+			CompletedAt: pgtype.Timestamp{Time: time.Now(), Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to complete task: %w", err)
+		}
+		result.CompletedTask = completedTask
+
+		// Step 3: Record the completion, by the assignee, in the task's activity log
+		_, err = q.CreateTaskActivity(ctx, CreateTaskActivityParams{
+			TaskID:  arg.TaskID,
+			ActorID: task.AssigneeID,
+			Event:   TaskActivityEventCompleted,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record task completion: %w", err)
+		}
+
+		// Step 4: Make user available again if this was their only task in progress
+		updatedUser, err := s._releaseEngineer(ctx, q, task.AssigneeID.Int64)
+		if err != nil {
+			return err
+		}
+		result.UpdatedUser = updatedUser
+
+		return nil
+	})
+
+	return result, err
+}
+
+////////////////////////////////////////////////////////////////////////
+// Transaction: DeclineTaskTx
+////////////////////////////////////////////////////////////////////////
+
+// DeclineTaskTxParams contains parameters for an engineer declining their assigned task
+type DeclineTaskTxParams struct {
+	TaskID     int64
+	EngineerID int64
+}
+
+// DeclineTaskTxResult contains the reopened task and the freed engineer
+type DeclineTaskTxResult struct {
+	Task Task
+	User User
+}
+
+// ErrTaskNotAssignedToUser is returned when the task is not an in-progress task of the engineer
+var ErrTaskNotAssignedToUser = errors.New("task is not in progress and assigned to this user")
+
+// DeclineTaskTx hands an assigned task back: the task is reopened without an assignee
+// and the engineer is made available again, unless they still have another task in progress.
+func (s *Store) DeclineTaskTx(ctx context.Context, arg DeclineTaskTxParams) (DeclineTaskTxResult, error) {
+	var result DeclineTaskTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		// Step 1: Reopen the task, guarded on the current assignee
+		result.Task, err = q.UnassignTask(ctx, UnassignTaskParams{
+			ID:         arg.TaskID,
+			AssigneeID: pgtype.Int8{Int64: arg.EngineerID, Valid: true},
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrTaskNotAssignedToUser
+			}
+			return fmt.Errorf("failed to unassign task: %w", err)
+		}
+
+		// Step 2: Record the task going back to open
+		err = s._recordStatusChange(ctx, q, arg.TaskID, arg.EngineerID, TaskStatusInProgress, TaskStatusOpen)
+		if err != nil {
+			return err
+		}
+
+		// Step 3: Make the engineer available again if this was their only task in progress
+		result.User, err = s._releaseEngineer(ctx, q, arg.EngineerID)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+////////////////////////////////////////////////////////////////////////
+// Transaction: PauseTaskTx / ResumeTaskTx
+////////////////////////////////////////////////////////////////////////
+
+// PauseTaskTxParams contains parameters for an engineer pausing or resuming their task
+type PauseTaskTxParams struct {
+	TaskID     int64
+	EngineerID int64
+}
+
+// PauseTaskTxResult contains the task and the engineer after their availability was reconciled
+type PauseTaskTxResult struct {
+	Task Task
+	User User
+}
+
+// Error definitions for resuming a paused task
+var (
+	ErrTaskNotPausedByUser  = errors.New("task is not paused and assigned to this user")
+	ErrEngineerNotAvailable = errors.New("engineer already has a task in progress")
+	ErrEngineerOnLeave      = errors.New("engineer is on leave")
+)
+
+// PauseTaskTx moves the engineer's in-progress task back to open, keeping them as assignee.
+// The engineer becomes available only if no other task of theirs is still in progress.
+func (s *Store) PauseTaskTx(ctx context.Context, arg PauseTaskTxParams) (PauseTaskTxResult, error) {
+	var result PauseTaskTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		var err error
+		assigneeID := pgtype.Int8{Int64: arg.EngineerID, Valid: true}
+
+		// Step 1: Pause the task, guarded on the current assignee
+		result.Task, err = q.PauseTask(ctx, PauseTaskParams{
+			ID:         arg.TaskID,
+			AssigneeID: assigneeID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrTaskNotAssignedToUser
+			}
+			return fmt.Errorf("failed to pause task: %w", err)
+		}
+
+		// Record the pause in the task's activity log
+		err = s._recordStatusChange(ctx, q, arg.TaskID, arg.EngineerID, TaskStatusInProgress, TaskStatusOpen)
+		if err != nil {
+			return err
+		}
+
+		// Step 2: Free the engineer unless they are still working on something else
+		result.User, err = s._releaseEngineer(ctx, q, arg.EngineerID)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// ResumeTaskTx moves a paused task back to in progress and marks the engineer busy.
+// It is refused unless the engineer is available, i.e. has no other task in progress.
+func (s *Store) ResumeTaskTx(ctx context.Context, arg PauseTaskTxParams) (PauseTaskTxResult, error) {
+	var result PauseTaskTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		var err error
+		assigneeID := pgtype.Int8{Int64: arg.EngineerID, Valid: true}
+
+		// Step 1: Mark the engineer busy, refusing if they are not available. As in ClaimTaskTx,
+		// the conditional update locks the user row, so concurrent resumes and claims run one at a time
+		result.User, err = q.MarkUserBusyIfAvailable(ctx, arg.EngineerID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return s._unavailableError(ctx, q, arg.EngineerID)
+			}
+			return fmt.Errorf("failed to update user availability: %w", err)
+		}
+
+		// Step 2: Resume the task, guarded on the current assignee
+		result.Task, err = q.ResumeTask(ctx, ResumeTaskParams{
+			ID:         arg.TaskID,
+			AssigneeID: assigneeID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrTaskNotPausedByUser
+			}
+			return fmt.Errorf("failed to resume task: %w", err)
+		}
+
+		// Step 3: Record the resumption in the task's activity log
+		err = s._recordStatusChange(ctx, q, arg.TaskID, arg.EngineerID, TaskStatusOpen, TaskStatusInProgress)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+////////////////////////////////////////////////////////////////////////
+// Transaction: UpdateTaskTx
+////////////////////////////////////////////////////////////////////////
+
+// UpdateTaskTxParams contains a manager's edit of a task: a status change, detail changes or both
+type UpdateTaskTxParams struct {
+	TaskID  int64
+	ActorID int64
+	Version int32             // The version the client read; a stale one changes nothing
+	Status  NullTaskStatus    // New status, applied before the details; unset keeps the current one
+	Details *UpdateTaskParams // Detail fields to change, or nil; ID and Version are filled in here
+}
+
+// UpdateTaskTxResult contains the updated task and, when a status change affected one, its assignee
+type UpdateTaskTxResult struct {
+	Task           Task
+	Assignee       *User
+	PreviousStatus TaskStatus // Status the task had before the edit
+}
+
+// Error definitions for task edits
+var (
+	ErrStaleTaskVersion        = errors.New("task was changed since the given version")
+	ErrUnsupportedStatusChange = errors.New("unsupported task status change")
+	ErrTaskStatusConflict      = errors.New("task is no longer in the expected status")
+)
+
+// UpdateTaskTx applies a manager's edit of a task atomically. The task is locked and its version
+// checked before anything is written, so a stale edit is refused without side effects.
+// A status change moves the task along one edge of its status machine and keeps the assignee consistent:
+// starting marks them busy, while finishing or reopening frees them unless they have other work in progress.
+// Reopening a done task also clears its completion time and assignee.
+func (s *Store) UpdateTaskTx(ctx context.Context, arg UpdateTaskTxParams) (UpdateTaskTxResult, error) {
+	var result UpdateTaskTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		// Step 1: Lock the task and check nobody changed it since the client read it
+		task, err := q.GetTaskForUpdate(ctx, arg.TaskID)
+		if err != nil {
+			return fmt.Errorf("failed to get task: %w", err)
+		}
+		if task.Version != arg.Version {
+			return ErrStaleTaskVersion
+		}
+		result.Task = task
+		result.PreviousStatus = task.Status
+
+		// Step 2: Apply the status transition, remembering the assignee before a reopen clears it
+		if arg.Status.Valid && arg.Status.TaskStatus != task.Status {
+			to := arg.Status.TaskStatus
+			switch {
+			case task.Status == TaskStatusOpen && to == TaskStatusInProgress:
+				result.Task, err = q.StartTask(ctx, arg.TaskID)
+			case task.Status == TaskStatusInProgress && to == TaskStatusDone:
+				result.Task, err = q.MarkTaskDone(ctx, arg.TaskID)
+			case task.Status == TaskStatusDone && to == TaskStatusOpen:
+				result.Task, err = q.ReopenTask(ctx, arg.TaskID)
+			default:
+				return ErrUnsupportedStatusChange
+			}
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return ErrTaskStatusConflict
+				}
+				return fmt.Errorf("failed to change task status: %w", err)
+			}
+
+			// Record the change in the task's activity log
+			err = s._recordStatusChange(ctx, q, arg.TaskID, arg.ActorID, task.Status, to)
+			if err != nil {
+				return err
+			}
+
+			// Reconcile the assignee's availability
+			if task.AssigneeID.Valid {
+				var assignee User
+				if to == TaskStatusInProgress {
+					assignee, err = s._markEngineerBusy(ctx, q, task.AssigneeID.Int64)
+				} else {
+					assignee, err = s._releaseEngineer(ctx, q, task.AssigneeID.Int64)
+				}
+				if err != nil {
+					return err
+				}
+				result.Assignee = &assignee
+			}
+		}
+
+		if arg.Details == nil {
+			return nil
+		}
+
+		// Step 3: Apply the detail changes on top of the version the status change produced
+		details := *arg.Details
+		details.ID = arg.TaskID
+		details.Version = pgtype.Int4{Int32: result.Task.Version, Valid: true}
+		result.Task, err = q.UpdateTask(ctx, details)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrStaleTaskVersion
+			}
+			return fmt.Errorf("failed to update task: %w", err)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+////////////////////////////////////////////////////////////////////////
+// Transaction: ReprocessUserSkillsTx
+////////////////////////////////////////////////////////////////////////
+
+// ReprocessUserSkillsTxParams contains skills extracted from a user's updated resume
+type ReprocessUserSkillsTxParams struct {
+	UserID                int64
+	SkillsWithProficiency map[string]ProficiencyLevel // Skills extracted from the updated resume
+}
+
+// ReprocessUserSkillsTxResult describes how the user's skills changed
+type ReprocessUserSkillsTxResult struct {
+	Added    []UserSkill // Skills the user did not have yet
+	Upgraded []UserSkill // Existing skills raised to a higher extracted proficiency
+	Removed  []int64     // IDs of previously extracted skills the resume no longer shows
+}
+
+// ReprocessUserSkillsTx merges skills from an updated resume into the user's profile.
+// New skills are added, existing ones only change when the resume now shows a higher proficiency,
+// and skills that are no longer extracted are removed unless a person added or endorsed them.
+// Nothing is ever lowered.
+func (s *Store) ReprocessUserSkillsTx(ctx context.Context, arg ReprocessUserSkillsTxParams) (ReprocessUserSkillsTxResult, error) {
+	var result ReprocessUserSkillsTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		// Step 1: Resolve extracted names to skills (creates new skills if they don't exist)
+		skillNames := make([]string, 0, len(arg.SkillsWithProficiency))
+		for name := range arg.SkillsWithProficiency {
+			skillNames = append(skillNames, name)
+		}
+		skillMap, err := s._resolveSkills(ctx, q, skillNames)
+		if err != nil {
+			return fmt.Errorf("failed to resolve skills: %w", err)
+		}
+
+		// Step 2: Load what the user has today
+		current, err := q.ListUserSkills(ctx, arg.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to list user skills: %w", err)
+		}
+		existing := make(map[int64]ProficiencyLevel, len(current))
+		for _, userSkill := range current {
+			existing[userSkill.SkillID] = userSkill.Proficiency
+		}
+
+		// Step 3: Add new skills and raise the ones now extracted at a higher level
+		extracted := make(map[int64]bool, len(skillMap))
+		for name, skill := range skillMap {
+			extracted[skill.ID] = true
+			proficiency := arg.SkillsWithProficiency[name]
+
+			had, ok := existing[skill.ID]
+			if !ok {
+				userSkill, err := q.AddSkillToUser(ctx, AddSkillToUserParams{
+					UserID:      arg.UserID,
+					SkillID:     skill.ID,
+					Proficiency: proficiency,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to add skill '%s' to user: %w", name, err)
+				}
+				result.Added = append(result.Added, userSkill)
+				continue
+			}
+
+			// The query keeps the higher of the two levels, so a changed level means it was raised
+			userSkill, err := q.RaiseUserSkillProficiency(ctx, RaiseUserSkillProficiencyParams{
+				Proficiency: proficiency,
+				UserID:      arg.UserID,
+				SkillID:     skill.ID,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to raise proficiency of skill '%s': %w", name, err)
+			}
+			if userSkill.Proficiency != had {
+				result.Upgraded = append(result.Upgraded, userSkill)
+			}
+		}
+
+		// Step 4: Drop stale extracted skills; manual and endorsed ones stay
+		for _, userSkill := range current {
+			if extracted[userSkill.SkillID] || userSkill.IsManual {
+				continue
+			}
+			_, err := q.RemoveSkillFromUser(ctx, RemoveSkillFromUserParams{
+				UserID:  arg.UserID,
+				SkillID: userSkill.SkillID,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to remove skill %d from user: %w", userSkill.SkillID, err)
+			}
+			result.Removed = append(result.Removed, userSkill.SkillID)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+////////////////////////////////////////////////////////////////////////
+// Transaction: AddUserSkillTx
+////////////////////////////////////////////////////////////////////////
+
+// AddUserSkillTxParams contains the skill a person adds to a user's profile
+type AddUserSkillTxParams struct {
+	UserID      int64
+	SkillID     int64
+	Proficiency ProficiencyLevel
+}
+
+// ErrUserSkillExists is returned when the user already has the skill being added
+var ErrUserSkillExists = errors.New("user already has this skill")
+
+// AddUserSkillTx links an existing skill to a user and marks it manual,
+// so reprocessing their resume never removes it.
+func (s *Store) AddUserSkillTx(ctx context.Context, arg AddUserSkillTxParams) (UserSkill, error) {
+	var result UserSkill
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		// Step 1: The skill must already exist; this does not create new skills
+		if _, err := q.GetSkill(ctx, arg.SkillID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrSkillNotFound
+			}
+			return fmt.Errorf("failed to get skill: %w", err)
+		}
+
+		// Step 2: Refuse duplicates; proficiency changes go through UpdateUserSkillProficiency
+		current, err := q.ListUserSkills(ctx, arg.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to list user skills: %w", err)
+		}
+		for _, userSkill := range current {
+			if userSkill.SkillID == arg.SkillID {
+				return ErrUserSkillExists
+			}
+		}
+
+		// Step 3: Link the skill
+		_, err = q.AddSkillToUser(ctx, AddSkillToUserParams{
+			UserID:      arg.UserID,
+			SkillID:     arg.SkillID,
+			Proficiency: arg.Proficiency,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add skill to user: %w", err)
+		}
+
+		// Step 4: Protect it from reprocessing
+		result, err = q.MarkUserSkillManual(ctx, MarkUserSkillManualParams{
+			UserID:  arg.UserID,
+			SkillID: arg.SkillID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to mark user skill manual: %w", err)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+////////////////////////////////////////////////////////////////////////
+// Transaction: CreateTaskCommentTx
+////////////////////////////////////////////////////////////////////////
+
+// CreateTaskCommentTxParams contains the parameters for commenting on a task
+type CreateTaskCommentTxParams struct {
+	TaskID   int64
+	AuthorID int64
+	ParentID pgtype.Int8 // Comment being replied to; NULL for a top-level comment
+	Body     string
+}
+
+// Error definitions for task comments
+var (
+	ErrTaskNotFound           = errors.New("task not found")
+	ErrCommenterNotInTeam     = errors.New("only members of the task's team can comment on it")
+	ErrParentCommentNotOnTask = errors.New("the comment being replied to is not on this task")
+)
+
+// CreateTaskCommentTx adds a comment after checking that the author is on the team that owns
+// the task's project and that a reply's parent belongs to the same task.
+func (s *Store) CreateTaskCommentTx(ctx context.Context, arg CreateTaskCommentTxParams) (TaskComment, error) {
+	var comment TaskComment
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		// Step 1: Find the team that owns the task through its project
+		task, err := q.GetTask(ctx, arg.TaskID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrTaskNotFound
+			}
+			return fmt.Errorf("failed to get task: %w", err)
+		}
+		if !task.ProjectID.Valid {
+			return ErrCommenterNotInTeam
+		}
+		project, err := q.GetProject(ctx, task.ProjectID.Int64)
+		if err != nil {
+			return fmt.Errorf("failed to get project of task: %w", err)
+		}
+
+		// Step 2: The author must currently be on that team
+		author, err := q.GetUser(ctx, arg.AuthorID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrCommenterNotInTeam
+			}
+			return fmt.Errorf("failed to get author: %w", err)
+		}
+		if !author.TeamID.Valid || author.TeamID.Int64 != project.TeamID {
+			return ErrCommenterNotInTeam
+		}
+
+		// Step 3: A reply must stay in the task's thread
+		if arg.ParentID.Valid {
+			parent, err := q.GetTaskComment(ctx, arg.ParentID.Int64)
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return ErrParentCommentNotOnTask
+				}
+				return fmt.Errorf("failed to get parent comment: %w", err)
+			}
+			if parent.TaskID != arg.TaskID {
+				return ErrParentCommentNotOnTask
+			}
+		}
+
+		// Step 4: Insert the comment
+		comment, err = q.CreateTaskComment(ctx, CreateTaskCommentParams{
+			TaskID:   arg.TaskID,
+			AuthorID: arg.AuthorID,
+			ParentID: arg.ParentID,
+			Body:     arg.Body,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create comment: %w", err)
+		}
+
+		return nil
+	})
+
+	return comment, err
+}
+
+////////////////////////////////////////////////////////////////////////
+// Transaction: BulkCreateSkillAliasesTx
+////////////////////////////////////////////////////////////////////////
+
+// BulkSkillAlias is one alias to import; the target skill is given by SkillID or, failing that, SkillName
+type BulkSkillAlias struct {
+	AliasName string
+	SkillID   int64
+	SkillName string
+}
+
+// Per-alias outcomes reported by BulkCreateSkillAliasesTx
+const (
+	BulkAliasCreated       = "created"
+	BulkAliasDuplicate     = "duplicate"
+	BulkAliasSkillNotFound = "skill_not_found"
+)
+
+// BulkSkillAliasResult reports what happened to a single alias of the import
+type BulkSkillAliasResult struct {
+	AliasName string `json:"alias_name"`
+	SkillID   int64  `json:"skill_id,omitempty"`
+	Status    string `json:"status"`
 }
 
-// Error definitions for project archiving
+// BulkCreateSkillAliasesTxParams contains the aliases to import, in order
+type BulkCreateSkillAliasesTxParams struct {
+	Aliases []BulkSkillAlias
+}
+
+// BulkCreateSkillAliasesTxResult contains one result per requested alias
+type BulkCreateSkillAliasesTxResult struct {
+	Results []BulkSkillAliasResult
+	Created int
+}
+
+// BulkCreateSkillAliasesTx inserts many aliases in one transaction. Existing aliases and
+// aliases pointing at unknown skills are reported per row instead of failing the import.
+func (s *Store) BulkCreateSkillAliasesTx(ctx context.Context, arg BulkCreateSkillAliasesTxParams) (BulkCreateSkillAliasesTxResult, error) {
+	var result BulkCreateSkillAliasesTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		result.Results = make([]BulkSkillAliasResult, 0, len(arg.Aliases))
+		result.Created = 0
+
+		for _, alias := range arg.Aliases {
+			row := BulkSkillAliasResult{AliasName: alias.AliasName}
+
+			// Step 1: Resolve the target skill by ID, or by name when no ID was given
+			var skill Skill
+			var err error
+			if alias.SkillID != 0 {
+				skill, err = q.GetSkill(ctx, alias.SkillID)
+			} else {
+				skill, err = q.GetSkillByName(ctx, alias.SkillName)
+			}
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					row.Status = BulkAliasSkillNotFound
+					result.Results = append(result.Results, row)
+					continue
+				}
+				return fmt.Errorf("failed to resolve skill for alias %q: %w", alias.AliasName, err)
+			}
+			row.SkillID = skill.ID
+
+			// Step 2: Insert the alias; an existing alias yields no row rather than aborting the transaction
+			_, err = q.CreateSkillAliasIfNotExists(ctx, CreateSkillAliasIfNotExistsParams{
+				AliasName: alias.AliasName,
+				SkillID:   skill.ID,
+			})
+			switch {
+			case errors.Is(err, pgx.ErrNoRows):
+				row.Status = BulkAliasDuplicate
+			case err != nil:
+				return fmt.Errorf("failed to create alias %q: %w", alias.AliasName, err)
+			default:
+				row.Status = BulkAliasCreated
+				result.Created++
+			}
+			result.Results = append(result.Results, row)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+////////////////////////////////////////////////////////////////////////
+// Transaction: MergeSkillsTx
+////////////////////////////////////////////////////////////////////////
+
+// MergeSkillsTxParams names the duplicate skill to fold into the one that is kept
+type MergeSkillsTxParams struct {
+	SourceID int64 // Skill that is merged away and deleted
+	TargetID int64 // Skill that is kept
+}
+
+// MergeSkillsTxResult summarizes the rows the merge touched
+type MergeSkillsTxResult struct {
+	Target              Skill `json:"target"`
+	UserSkillsRepointed int64 `json:"user_skills_repointed"` // Users moved from the source to the target skill
+	UserSkillsMerged    int64 `json:"user_skills_merged"`    // Users who had both; the higher proficiency was kept
+	TaskSkillsRepointed int64 `json:"task_skills_repointed"` // Tasks moved from the source to the target skill
+	AliasesRepointed    int64 `json:"aliases_repointed"`     // Existing aliases of the source now pointing at the target
+	SourceAliasCreated  bool  `json:"source_alias_created"`  // Whether the source's name became a new alias
+}
+
+// Error definitions for skill merges
 var (
-	ErrProjectNotFound        = errors.New("project not found or access denied")
-	ErrProjectAlreadyArchived = errors.New("project is already archived")
+	ErrSkillNotFound  = errors.New("skill not found")
+	ErrMergeSameSkill = errors.New("a skill cannot be merged into itself")
 )
 
-// ArchiveProjectTx archives a project and automatically archives all its tasks.
-// Also frees up engineers who were assigned to tasks in this project.
-func (s *Store) ArchiveProjectTx(ctx context.Context, arg ArchiveProjectTxParams) (ArchiveProjectTxResult, error) {
-	var result ArchiveProjectTxResult
+// MergeSkillsTx folds a duplicate skill into another one. Users, tasks and aliases of the source
+// move to the target, rows that would duplicate the target are merged instead, the source's name
+// becomes an alias of the target, and the source skill is deleted.
+func (s *Store) MergeSkillsTx(ctx context.Context, arg MergeSkillsTxParams) (MergeSkillsTxResult, error) {
+	var result MergeSkillsTxResult
+
+	if arg.SourceID == arg.TargetID {
+		return result, ErrMergeSameSkill
+	}
 
 	err := s.execTx(ctx, func(q *Queries) error {
-		// Step 1: Validate project exists and belongs to team
-		project, err := q.GetProjectByIDAndTeam(ctx, GetProjectByIDAndTeamParams{
-			ID:     arg.ProjectID,
-			TeamID: arg.TeamID,
-		})
+		// Step 1: Both skills must exist
+		source, err := q.GetSkill(ctx, arg.SourceID)
 		if err != nil {
 			if errors.Is(err, pgx.ErrNoRows) {
-				return ErrProjectNotFound
+				return ErrSkillNotFound
 			}
-			return fmt.Errorf("failed to get project: %w", err)
+			return fmt.Errorf("failed to get source skill: %w", err)
 		}
-
-		// Step 2: Check if project is already archived
-		if project.Archived {
-			return ErrProjectAlreadyArchived
+		result.Target, err = q.GetSkill(ctx, arg.TargetID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrSkillNotFound
+			}
+			return fmt.Errorf("failed to get target skill: %w", err)
 		}
 
-		// Step 3: Count active tasks before archiving them
-		activeTasksCount, err := q.CountActiveTasksByProject(ctx, pgtype.Int8{Int64: arg.ProjectID, Valid: true})
+		// Step 2: Users holding both keep the higher proficiency; their source rows go with the source skill
+		result.UserSkillsMerged, err = q.MergeUserSkillProficiencies(ctx, MergeUserSkillProficienciesParams{
+			TargetID: arg.TargetID,
+			SourceID: arg.SourceID,
+		})
 		if err != nil {
-			return fmt.Errorf("failed to count active tasks: %w", err)
+			return fmt.Errorf("failed to merge user proficiencies: %w", err)
 		}
 
-		// Step 4: FREE UP ENGINEERS - Get all assigned engineers before archiving tasks
-		if activeTasksCount > 0 {
-			// Get all users assigned to tasks in this project
-			assignedEngineers, err := q.GetAssignedEngineersForProject(ctx, pgtype.Int8{Int64: arg.ProjectID, Valid: true})
-			if err != nil {
-				return fmt.Errorf("failed to get assigned engineers: %w", err)
-			}
-
-			// Set all assigned engineers back to available
-			for _, engineer := range assignedEngineers {
-				_, err = q.UpdateUser(ctx, UpdateUserParams{
-					ID:           engineer.Int64,
-					Availability: NullAvailabilityStatus{AvailabilityStatus: AvailabilityStatusAvailable, Valid: true},
-				})
-				if err != nil {
-					return fmt.Errorf("failed to free engineer %d: %w", engineer.Int64, err)
-				}
-			}
+		// Step 3: Repoint the remaining user, task and alias rows
+		result.UserSkillsRepointed, err = q.RepointUserSkills(ctx, RepointUserSkillsParams{
+			TargetID: arg.TargetID,
+			SourceID: arg.SourceID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to repoint user skills: %w", err)
 		}
 
-		// Step 5: Archive all tasks in the project
-		if activeTasksCount > 0 {
-			err = q.ArchiveCompletedTasksByProject(ctx, pgtype.Int8{Int64: arg.ProjectID, Valid: true})
-			if err != nil {
-				return fmt.Errorf("failed to archive project tasks: %w", err)
-			}
+		result.TaskSkillsRepointed, err = q.RepointTaskRequiredSkills(ctx, RepointTaskRequiredSkillsParams{
+			TargetID: arg.TargetID,
+			SourceID: arg.SourceID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to repoint task skills: %w", err)
 		}
 
-		result.ArchivedTasksCount = activeTasksCount
-
-		// Step 6: Archive the project
-		archivedProject, err := q.ArchiveProject(ctx, ArchiveProjectParams{
-			ID:     arg.ProjectID,
-			TeamID: arg.TeamID,
+		result.AliasesRepointed, err = q.RepointSkillAliases(ctx, RepointSkillAliasesParams{
+			TargetID: arg.TargetID,
+			SourceID: arg.SourceID,
 		})
 		if err != nil {
-			return fmt.Errorf("failed to archive project: %w", err)
+			return fmt.Errorf("failed to repoint skill aliases: %w", err)
+		}
+
+		// Step 4: Keep normalizing the old name to the target; an existing alias of that name is left as is
+		_, err = q.CreateSkillAliasIfNotExists(ctx, CreateSkillAliasIfNotExistsParams{
+			AliasName: strings.ToLower(source.SkillName),
+			SkillID:   arg.TargetID,
+		})
+		switch {
+		case err == nil:
+			result.SourceAliasCreated = true
+		case !errors.Is(err, pgx.ErrNoRows):
+			return fmt.Errorf("failed to alias source skill name: %w", err)
+		}
+
+		// Step 5: Delete the source; duplicate rows left on it cascade away
+		if err := q.DeleteSkill(ctx, arg.SourceID); err != nil {
+			return fmt.Errorf("failed to delete source skill: %w", err)
 		}
 
-		result.ArchivedProject = archivedProject
 		return nil
 	})
 
@@ -893,61 +2819,148 @@ func (s *Store) ArchiveProjectTx(ctx context.Context, arg ArchiveProjectTxParams
 }
 
 ////////////////////////////////////////////////////////////////////////
-// Transaction: CompleteTaskTx
+// Transaction: GetTeamBoardTx (Read-Only)
 ////////////////////////////////////////////////////////////////////////
 
-// CompleteTaskTxParams contains parameters for task completion
-type CompleteTaskTxParams struct {
-	TaskID int64
+// GetTeamBoardTxParams contains parameters for loading a team's project board
+type GetTeamBoardTxParams struct {
+	TeamID    int64
+	Limit     int32 // Number of projects per page
+	Offset    int32 // Project offset for pagination
+	TaskLimit int32 // Cap on the total number of tasks across all projects on the page
 }
 
-// CompleteTaskTxResult contains the result of task completion
-type CompleteTaskTxResult struct {
-	CompletedTask Task
-	UpdatedUser   User
+// BoardProject is an active project with its active tasks nested underneath
+type BoardProject struct {
+	Project
+	Tasks []ListTasksByProjectIDsRow `json:"tasks"`
 }
 
-// CompleteTaskTx marks a task as completed and makes the user available again.
-// This is called by engineers when they finish their work.
-func (s *Store) CompleteTaskTx(ctx context.Context, arg CompleteTaskTxParams) (CompleteTaskTxResult, error) {
-	var result CompleteTaskTxResult
+// GetTeamBoardTxResult contains one page of the project board
+type GetTeamBoardTxResult struct {
+	Projects       []BoardProject
+	TotalProjects  int64
+	TasksTruncated bool // True when TaskLimit cut off some tasks
+}
+
+// GetTeamBoardTx loads a page of active projects and all of their tasks with two bulk queries,
+// grouping tasks under their projects in Go instead of querying tasks per project.
+func (s *Store) GetTeamBoardTx(ctx context.Context, arg GetTeamBoardTxParams) (GetTeamBoardTxResult, error) {
+	var result GetTeamBoardTxResult
 
 	err := s.execTx(ctx, func(q *Queries) error {
-		// Step 1: Get the task and validate
-		task, err := q.GetTask(ctx, arg.TaskID)
+		// Step 1: Load the page of active projects
+		projects, err := q.ListActiveProjectsByTeam(ctx, ListActiveProjectsByTeamParams{
+			TeamID: arg.TeamID,
+			Limit:  arg.Limit,
+			Offset: arg.Offset,
+		})
 		if err != nil {
-			return fmt.Errorf("failed to get task: %w", err)
+			return fmt.Errorf("failed to list active projects: %w", err)
 		}
 
-		if task.Status == "done" {
-			return errors.New("task is already completed")
+		result.TotalProjects, err = q.CountActiveProjectsByTeam(ctx, arg.TeamID)
+		if err != nil {
+			return fmt.Errorf("failed to count active projects: %w", err)
 		}
 
-		if !task.AssigneeID.Valid {
-			return errors.New("task is not assigned to anyone")
+		result.Projects = make([]BoardProject, 0, len(projects))
+		if len(projects) == 0 {
+			return nil
 		}
 
-		// Step 2: Mark task as completed
-		completedTask, err := q.UpdateTask(ctx, UpdateTaskParams{
-			ID:          arg.TaskID,
-			Status:      NullTaskStatus{TaskStatus: "done", Valid: true},
-			CompletedAt: pgtype.Timestamp{Time: time.Now(), Valid: true},
+		// Step 2: Load tasks for every project on the page in one query.
+		// One extra row is requested to detect whether the cap truncated the result.
+		projectIDs := make([]int64, len(projects))
+		for i, project := range projects {
+			projectIDs[i] = project.ID
+		}
+		tasks, err := q.ListTasksByProjectIDs(ctx, ListTasksByProjectIDsParams{
+			ProjectIds: projectIDs,
+			TaskLimit:  arg.TaskLimit + 1,
 		})
 		if err != nil {
-			return fmt.Errorf("failed to complete task: %w", err)
+			return fmt.Errorf("failed to list tasks for projects: %w", err)
 		}
-		result.CompletedTask = completedTask
+		if len(tasks) > int(arg.TaskLimit) {
+			tasks = tasks[:arg.TaskLimit]
+			result.TasksTruncated = true
+		}
+
+		// Step 3: Group tasks under their projects, preserving project order
+		tasksByProject := make(map[int64][]ListTasksByProjectIDsRow, len(projects))
+		for _, task := range tasks {
+			tasksByProject[task.ProjectID.Int64] = append(tasksByProject[task.ProjectID.Int64], task)
+		}
+		for _, project := range projects {
+			projectTasks := tasksByProject[project.ID]
+			if projectTasks == nil {
+				projectTasks = []ListTasksByProjectIDsRow{}
+			}
+			result.Projects = append(result.Projects, BoardProject{Project: project, Tasks: projectTasks})
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+////////////////////////////////////////////////////////////////////////
+// Transaction: EscalateStaleTasksTx
+////////////////////////////////////////////////////////////////////////
+
+// Default ages at which an open, unassigned task is escalated
+const (
+	DefaultEscalateToMediumAfter = 7 * 24 * time.Hour
+	DefaultEscalateToHighAfter   = 14 * 24 * time.Hour
+)
 
-		// Step 3: Make user available again
-		updatedUser, err := q.UpdateUser(ctx, UpdateUserParams{
-			ID:           task.AssigneeID.Int64,
-			Availability: NullAvailabilityStatus{AvailabilityStatus: "available", Valid: true},
+// EscalateStaleTasksTxParams contains parameters for escalating tasks nobody has picked up
+type EscalateStaleTasksTxParams struct {
+	ActorID     pgtype.Int8   // Who asked for the run; NULL for the scheduled job
+	MediumAfter time.Duration // Age at which low tasks become medium; 0 uses DefaultEscalateToMediumAfter
+	HighAfter   time.Duration // Age at which low and medium tasks become high; 0 uses DefaultEscalateToHighAfter
+	ProjectID   pgtype.Int8   // Limits the run to one project; NULL covers every project
+}
+
+// EscalateStaleTasksTxResult lists the tasks whose priority was raised, with their previous priority
+type EscalateStaleTasksTxResult struct {
+	Tasks []EscalateStaleTasksRow
+}
+
+// EscalateStaleTasksTx raises the priority of open, unassigned tasks that are old or overdue, and records
+// each change in the task's activity log. Tasks only move up to high, never to critical, and a task that was
+// already escalated for its age is left alone, so repeated runs are harmless.
+func (s *Store) EscalateStaleTasksTx(ctx context.Context, arg EscalateStaleTasksTxParams) (EscalateStaleTasksTxResult, error) {
+	var result EscalateStaleTasksTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		// Step 1: Raise every stale task in one statement
+		tasks, err := q.EscalateStaleTasks(ctx, EscalateStaleTasksParams{
+			HighAfterSeconds:   cmp.Or(arg.HighAfter, DefaultEscalateToHighAfter).Seconds(),
+			MediumAfterSeconds: cmp.Or(arg.MediumAfter, DefaultEscalateToMediumAfter).Seconds(),
+			ProjectID:          arg.ProjectID,
 		})
 		if err != nil {
-			return fmt.Errorf("failed to update user availability: %w", err)
+			return fmt.Errorf("failed to escalate stale tasks: %w", err)
 		}
-		result.UpdatedUser = updatedUser
 
+		// Step 2: Record each escalation
+		for _, task := range tasks {
+			_, err := q.CreateTaskActivity(ctx, CreateTaskActivityParams{
+				TaskID:       task.ID,
+				ActorID:      arg.ActorID,
+				Event:        TaskActivityEventPriorityEscalated,
+				FromPriority: NullTaskPriority{TaskPriority: task.PreviousPriority, Valid: true},
+				ToPriority:   NullTaskPriority{TaskPriority: task.Priority, Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to record escalation of task %d: %w", task.ID, err)
+			}
+		}
+
+		result.Tasks = tasks
 		return nil
 	})
 
@@ -958,8 +2971,184 @@ func (s *Store) CompleteTaskTx(ctx context.Context, arg CompleteTaskTxParams) (C
 // Private Helpers
 ////////////////////////////////////////////////////////////////////////
 
-// Creates missing skills as 'unverified' and returns all.
+// Creates a task, records its creation and links its required skills, as one step of a transaction.
+func (s *Store) _processNewTask(ctx context.Context, q *Queries, arg ProcessNewTaskTxParams) (ProcessNewTaskTxResult, error) {
+	var result ProcessNewTaskTxResult
+
+	// Step 1: Create the task.
+	createdTask, err := q.CreateTask(ctx, arg.CreateTaskParams)
+	if err != nil {
+		return result, fmt.Errorf("failed to create task: %w", err)
+	}
+	result.Task = createdTask
+
+	_, err = q.CreateTaskActivity(ctx, CreateTaskActivityParams{
+		TaskID:  createdTask.ID,
+		ActorID: arg.ActorID,
+		Event:   TaskActivityEventCreated,
+	})
+	if err != nil {
+		return result, fmt.Errorf("failed to record task creation: %w", err)
+	}
+
+	if len(arg.RequiredSkillNames) == 0 {
+		return result, nil
+	}
+
+	// Step 2: Resolve each skill name to its Skill, directly, ignoring case, or through an alias.
+	// In strict mode unknown names are reported as candidates instead of created.
+	var resolved map[string]Skill
+	if arg.StrictSkills {
+		resolved, result.CandidateSkillNames, err = s._resolveKnownSkills(ctx, q, arg.RequiredSkillNames)
+	} else {
+		resolved, err = s._resolveSkillNames(ctx, q, arg.RequiredSkillNames)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	// Step 3: Carry each name's weight over to its skill. Several names can resolve to one skill,
+	// which then keeps the highest of their weights.
+	skills := make(map[int64]Skill, len(resolved))
+	weights := make(map[int64]float64, len(arg.SkillWeights))
+	for name, skill := range resolved {
+		skills[skill.ID] = skill
+		if weight, ok := arg.SkillWeights[name]; ok {
+			if current, seen := weights[skill.ID]; !seen || weight > current {
+				weights[skill.ID] = weight
+			}
+		}
+	}
+
+	// Step 4: Link each required skill to the task once, with its weight when known.
+	for _, skill := range skills {
+		weight, weighted := weights[skill.ID]
+		requiredSkill, linkErr := q.AddSkillToTask(ctx, AddSkillToTaskParams{
+			TaskID:  createdTask.ID,
+			SkillID: skill.ID,
+			Weight:  pgtype.Float8{Float64: weight, Valid: weighted},
+		})
+		if linkErr != nil {
+			return result, fmt.Errorf("failed to link skill '%s' to task: %w", skill.SkillName, linkErr)
+		}
+		result.TaskRequiredSkills = append(result.TaskRequiredSkills, requiredSkill)
+	}
+
+	return result, nil
+}
+
+// _issueRefreshToken generates a random refresh token and stores its hash.
+func (s *Store) _issueRefreshToken(ctx context.Context, q *Queries, arg IssueRefreshTokenParams) (IssueRefreshTokenResult, error) {
+	token := rand.Text()
+
+	refreshToken, err := q.CreateRefreshToken(ctx, CreateRefreshTokenParams{
+		UserID:    arg.UserID,
+		TokenHash: hashRefreshToken(token),
+		ExpiresAt: pgtype.Timestamp{
+			Time:  time.Now().Add(arg.Duration),
+			Valid: true,
+		},
+	})
+	if err != nil {
+		return IssueRefreshTokenResult{}, fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	return IssueRefreshTokenResult{Token: token, RefreshToken: refreshToken}, nil
+}
+
+// Marks an engineer available unless another task of theirs is still in progress, in which case they stay busy.
+// Engineers on leave stay on leave. Call it after the task that stopped being their work has been updated,
+// so that task is no longer counted.
+func (s *Store) _releaseEngineer(ctx context.Context, q *Queries, userID int64) (User, error) {
+	active, err := q.CountInProgressTasksByAssignee(ctx, pgtype.Int8{Int64: userID, Valid: true})
+	if err != nil {
+		return User{}, fmt.Errorf("failed to count in-progress tasks: %w", err)
+	}
+	availability := AvailabilityStatusBusy
+	if active == 0 {
+		availability = AvailabilityStatusAvailable
+	}
+
+	user, err := q.SetUserAvailabilityUnlessOnLeave(ctx, SetUserAvailabilityUnlessOnLeaveParams{
+		ID:           userID,
+		Availability: availability,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		user, err = q.GetUser(ctx, userID)
+	}
+	if err != nil {
+		return User{}, fmt.Errorf("failed to update user availability: %w", err)
+	}
+	return user, nil
+}
+
+// Marks an engineer busy with a task, returning ErrEngineerOnLeave if they are on leave.
+func (s *Store) _markEngineerBusy(ctx context.Context, q *Queries, userID int64) (User, error) {
+	user, err := q.SetUserAvailabilityUnlessOnLeave(ctx, SetUserAvailabilityUnlessOnLeaveParams{
+		ID:           userID,
+		Availability: AvailabilityStatusBusy,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return User{}, ErrEngineerOnLeave
+		}
+		return User{}, fmt.Errorf("failed to update user availability: %w", err)
+	}
+	return user, nil
+}
+
+// Explains why an engineer could not be marked busy: ErrEngineerOnLeave if they are on leave,
+// ErrEngineerNotAvailable otherwise.
+func (s *Store) _unavailableError(ctx context.Context, q *Queries, userID int64) error {
+	user, err := q.GetUser(ctx, userID)
+	if err == nil && user.Availability == AvailabilityStatusOnLeave {
+		return ErrEngineerOnLeave
+	}
+	return ErrEngineerNotAvailable
+}
+
+// Records a status change made by a user in the task's activity log.
+func (s *Store) _recordStatusChange(ctx context.Context, q *Queries, taskID, actorID int64, from, to TaskStatus) error {
+	_, err := q.CreateTaskActivity(ctx, CreateTaskActivityParams{
+		TaskID:     taskID,
+		ActorID:    pgtype.Int8{Int64: actorID, Valid: true},
+		Event:      TaskActivityEventStatusChanged,
+		FromStatus: NullTaskStatus{TaskStatus: from, Valid: true},
+		ToStatus:   NullTaskStatus{TaskStatus: to, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record status change: %w", err)
+	}
+	return nil
+}
+
+// Creates missing skills as 'unverified' and returns all, keyed by the given names.
+// Names are matched case-insensitively, so "node.js" resolves to an existing "Node.js" and
+// the stored casing wins. Names differing only in case resolve to one skill, keyed by the first of them.
 func (s *Store) _resolveSkills(ctx context.Context, q *Queries, skillNames []string) (map[string]Skill, error) {
+	resolved, err := s._resolveSkillNames(ctx, q, skillNames)
+	if err != nil {
+		return nil, err
+	}
+
+	// Key each skill by the first name that resolved to it, so callers never link it twice.
+	skillMap := make(map[string]Skill, len(resolved))
+	seen := make(map[int64]bool, len(resolved))
+	for _, name := range skillNames {
+		skill := resolved[name]
+		if seen[skill.ID] {
+			continue
+		}
+		seen[skill.ID] = true
+		skillMap[name] = skill
+	}
+
+	return skillMap, nil
+}
+
+// Like _resolveSkills, but keys the result by every given name, so names differing only in case
+// each map to their shared skill.
+func (s *Store) _resolveSkillNames(ctx context.Context, q *Queries, skillNames []string) (map[string]Skill, error) {
 	if len(skillNames) == 0 {
 		return make(map[string]Skill), nil
 	}
@@ -970,15 +3159,18 @@ func (s *Store) _resolveSkills(ctx context.Context, q *Queries, skillNames []str
 		return nil, fmt.Errorf("failed to batch fetch skills: %w", err)
 	}
 
-	skillMap := make(map[string]Skill, len(skillNames))
+	byLowerName := make(map[string]Skill, len(skillNames))
 	for _, s := range existingSkills {
-		skillMap[s.SkillName] = s
+		byLowerName[strings.ToLower(s.SkillName)] = s
 	}
 
-	// Step 2: Identify and batch-create new skills.
+	// Step 2: Identify and batch-create new skills, once per lowercase name.
 	var newSkillNames []string
+	pending := make(map[string]bool)
 	for _, name := range skillNames {
-		if _, ok := skillMap[name]; !ok {
+		lower := strings.ToLower(name)
+		if _, ok := byLowerName[lower]; !ok && !pending[lower] {
+			pending[lower] = true
 			newSkillNames = append(newSkillNames, name)
 		}
 	}
@@ -993,9 +3185,74 @@ func (s *Store) _resolveSkills(ctx context.Context, q *Queries, skillNames []str
 			return nil, fmt.Errorf("failed to batch create skills: %w", err)
 		}
 		for _, s := range createdSkills {
-			skillMap[s.SkillName] = s
+			byLowerName[strings.ToLower(s.SkillName)] = s
 		}
 	}
 
-	return skillMap, nil
+	// Step 3: Map every given name to its skill.
+	resolved := make(map[string]Skill, len(skillNames))
+	for _, name := range skillNames {
+		resolved[name] = byLowerName[strings.ToLower(name)]
+	}
+
+	return resolved, nil
+}
+
+// Resolves skill names to existing skills, directly or through an alias, without creating any.
+// The result is keyed by the given names; names that match neither are returned as candidates.
+func (s *Store) _resolveKnownSkills(ctx context.Context, q *Queries, skillNames []string) (map[string]Skill, []string, error) {
+	skillMap := make(map[string]Skill, len(skillNames))
+	if len(skillNames) == 0 {
+		return skillMap, nil, nil
+	}
+
+	// Step 1: Batch fetch skills matching by canonical name, ignoring case.
+	existingSkills, err := q.ListSkillsByNames(ctx, skillNames)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to batch fetch skills: %w", err)
+	}
+	byLowerName := make(map[string]Skill, len(existingSkills))
+	for _, s := range existingSkills {
+		byLowerName[strings.ToLower(s.SkillName)] = s
+	}
+	for _, name := range skillNames {
+		if skill, ok := byLowerName[strings.ToLower(name)]; ok {
+			skillMap[name] = skill
+		}
+	}
+
+	// Step 2: Look up the remaining names as aliases, which are stored in lowercase.
+	var aliasNames []string
+	for _, name := range skillNames {
+		if _, ok := byLowerName[strings.ToLower(name)]; !ok {
+			aliasNames = append(aliasNames, strings.ToLower(name))
+		}
+	}
+	if len(aliasNames) == 0 {
+		return skillMap, nil, nil
+	}
+
+	aliasRows, err := q.ListSkillsByAliasNames(ctx, aliasNames)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to batch fetch skill aliases: %w", err)
+	}
+	aliasMap := make(map[string]Skill, len(aliasRows))
+	for _, row := range aliasRows {
+		aliasMap[row.AliasName] = Skill{ID: row.ID, SkillName: row.SkillName, IsVerified: row.IsVerified}
+	}
+
+	// Step 3: Anything still unresolved becomes a candidate.
+	var candidates []string
+	for _, name := range skillNames {
+		if _, ok := byLowerName[strings.ToLower(name)]; ok {
+			continue
+		}
+		if skill, ok := aliasMap[strings.ToLower(name)]; ok {
+			skillMap[name] = skill
+			continue
+		}
+		candidates = append(candidates, name)
+	}
+
+	return skillMap, candidates, nil
 }
@@ -2,10 +2,14 @@ package db
 
 import (
 	"context"
+	"errors"
+	"math"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/pranav244872/synapse/util"
 	"github.com/stretchr/testify/require"
@@ -161,6 +165,145 @@ func TestProcessNewTask(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, linkedSkills, 2)
 	})
+
+	t.Run("Strict Mode - Links Known Skills and Reports Candidates", func(t *testing.T) {
+		// Arrange: one skill matched by name, one through an alias, and one unknown
+		project := createRandomProject(t)
+		knownSkill := createRandomSkill(t)
+		alias := createRandomSkillAlias(t)
+		unknownName := "Unknown " + util.RandomString(10)
+
+		params := ProcessNewTaskTxParams{
+			CreateTaskParams: CreateTaskParams{
+				ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+				Title:     "Vague task",
+				Status:    TaskStatusOpen,
+				Priority:  TaskPriorityLow,
+			},
+			RequiredSkillNames: []string{knownSkill.SkillName, strings.ToUpper(alias.AliasName), unknownName},
+			StrictSkills:       true,
+		}
+
+		// Act
+		result, err := store.ProcessNewTask(context.Background(), params)
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, result.TaskRequiredSkills, 2)
+		require.Equal(t, []string{unknownName}, result.CandidateSkillNames)
+
+		linkedIDs := make(map[int64]bool)
+		for _, required := range result.TaskRequiredSkills {
+			linkedIDs[required.SkillID] = true
+		}
+		require.True(t, linkedIDs[knownSkill.ID])
+		require.True(t, linkedIDs[alias.SkillID])
+
+		// The unknown skill must not have been created
+		_, err = testQueries.GetSkillByName(context.Background(), unknownName)
+		require.ErrorIs(t, err, pgx.ErrNoRows)
+	})
+
+	t.Run("Weights Follow Names Through Case and Aliases", func(t *testing.T) {
+		// Arrange: one skill named in two casings, another by its own name and by an alias
+		project := createRandomProject(t)
+		knownSkill := createRandomSkill(t)
+		alias := createRandomSkillAlias(t)
+		aliasTarget, err := testQueries.GetSkill(context.Background(), alias.SkillID)
+		require.NoError(t, err)
+
+		lowerKnown := strings.ToLower(knownSkill.SkillName)
+		upperAlias := strings.ToUpper(alias.AliasName)
+		params := ProcessNewTaskTxParams{
+			CreateTaskParams: CreateTaskParams{
+				ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+				Title:     "Weighted task",
+				Status:    TaskStatusOpen,
+				Priority:  TaskPriorityLow,
+			},
+			RequiredSkillNames: []string{lowerKnown, knownSkill.SkillName, aliasTarget.SkillName, upperAlias},
+			SkillWeights: map[string]float64{
+				lowerKnown:            0.8,
+				knownSkill.SkillName:  0.2,
+				aliasTarget.SkillName: 0.3,
+				upperAlias:            0.6,
+			},
+			StrictSkills: true,
+		}
+
+		// Act
+		result, err := store.ProcessNewTask(context.Background(), params)
+
+		// Assert: each skill is linked once, with the highest weight any of its names carried
+		require.NoError(t, err)
+		require.Empty(t, result.CandidateSkillNames)
+		weights := make(map[int64]float64)
+		for _, required := range result.TaskRequiredSkills {
+			weights[required.SkillID] = required.Weight
+		}
+		require.Equal(t, map[int64]float64{knownSkill.ID: 0.8, aliasTarget.ID: 0.6}, weights)
+
+		// Outside strict mode, a name in a different casing still carries its weight
+		params.StrictSkills = false
+		params.RequiredSkillNames = []string{lowerKnown}
+		params.SkillWeights = map[string]float64{lowerKnown: 0.4}
+		result, err = store.ProcessNewTask(context.Background(), params)
+		require.NoError(t, err)
+		require.Len(t, result.TaskRequiredSkills, 1)
+		require.Equal(t, knownSkill.ID, result.TaskRequiredSkills[0].SkillID)
+		require.Equal(t, 0.4, result.TaskRequiredSkills[0].Weight)
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Test: ProcessManyTasksTx
+////////////////////////////////////////////////////////////////////////////////
+
+func TestProcessManyTasksTx(t *testing.T) {
+	store := NewStore(testPool)
+	project := createRandomProject(t)
+	skillName := util.RandomString(10)
+
+	newTask := func(projectID int64) ProcessNewTaskTxParams {
+		return ProcessNewTaskTxParams{
+			CreateTaskParams: CreateTaskParams{
+				ProjectID: pgtype.Int8{Int64: projectID, Valid: true},
+				Title:     util.RandomTaskTitle(),
+				Status:    TaskStatusOpen,
+				Priority:  TaskPriorityMedium,
+			},
+			RequiredSkillNames: []string{skillName},
+		}
+	}
+
+	t.Run("Creates all tasks in order", func(t *testing.T) {
+		arg := ProcessManyTasksTxParams{Tasks: []ProcessNewTaskTxParams{newTask(project.ID), newTask(project.ID)}}
+
+		result, err := store.ProcessManyTasksTx(context.Background(), arg)
+		require.NoError(t, err)
+		require.Len(t, result.Tasks, 2)
+		for i, created := range result.Tasks {
+			require.Equal(t, arg.Tasks[i].CreateTaskParams.Title, created.Task.Title)
+			require.Len(t, created.TaskRequiredSkills, 1)
+		}
+		// Both tasks link the same skill, created once
+		require.Equal(t, result.Tasks[0].TaskRequiredSkills[0].SkillID, result.Tasks[1].TaskRequiredSkills[0].SkillID)
+	})
+
+	t.Run("One failing task rolls back the batch", func(t *testing.T) {
+		before, err := testQueries.CountActiveTasksByProject(context.Background(), pgtype.Int8{Int64: project.ID, Valid: true})
+		require.NoError(t, err)
+
+		// The second task points at a project that does not exist
+		_, err = store.ProcessManyTasksTx(context.Background(), ProcessManyTasksTxParams{
+			Tasks: []ProcessNewTaskTxParams{newTask(project.ID), newTask(-1)},
+		})
+		require.Error(t, err)
+
+		after, err := testQueries.CountActiveTasksByProject(context.Background(), pgtype.Int8{Int64: project.ID, Valid: true})
+		require.NoError(t, err)
+		require.Equal(t, before, after)
+	})
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -288,6 +431,96 @@ func TestAssignTaskToUser_Concurrent(t *testing.T) {
 	}
 }
 
+////////////////////////////////////////////////////////////////////////////////
+// Test: ClaimTaskTx
+////////////////////////////////////////////////////////////////////////////////
+
+func TestClaimTaskTx(t *testing.T) {
+	store := NewStore(testPool)
+	project := createRandomProject(t)
+	teamID := pgtype.Int8{Int64: project.TeamID, Valid: true}
+	engineer, _ := createRandomUserWithRoleAndTeam(t, UserRoleEngineer, teamID)
+	task := createRandomTaskLocal(t, project.ID)
+
+	// A task of another team cannot be claimed
+	outsider, _ := createRandomUserWithRoleAndTeam(t, UserRoleEngineer, pgtype.Int8{Int64: createRandomTeam(t).ID, Valid: true})
+	_, err := store.ClaimTaskTx(context.Background(), ClaimTaskTxParams{
+		TaskID:     task.ID,
+		EngineerID: outsider.ID,
+		TeamID:     outsider.TeamID.Int64,
+	})
+	require.ErrorIs(t, err, ErrTaskNotClaimable)
+
+	// The refused claim must not leave the outsider busy
+	outsider, err = testQueries.GetUser(context.Background(), outsider.ID)
+	require.NoError(t, err)
+	require.Equal(t, AvailabilityStatusAvailable, outsider.Availability)
+
+	// An available teammate claims it
+	result, err := store.ClaimTaskTx(context.Background(), ClaimTaskTxParams{
+		TaskID:     task.ID,
+		EngineerID: engineer.ID,
+		TeamID:     project.TeamID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, TaskStatusInProgress, result.Task.Status)
+	require.Equal(t, engineer.ID, result.Task.AssigneeID.Int64)
+	require.Equal(t, AvailabilityStatusBusy, result.User.Availability)
+
+	// A busy engineer cannot claim another task
+	_, err = store.ClaimTaskTx(context.Background(), ClaimTaskTxParams{
+		TaskID:     createRandomTaskLocal(t, project.ID).ID,
+		EngineerID: engineer.ID,
+		TeamID:     project.TeamID,
+	})
+	require.ErrorIs(t, err, ErrEngineerNotAvailable)
+}
+
+func TestClaimTaskTx_Concurrent(t *testing.T) {
+	store := NewStore(testPool)
+	project := createRandomProject(t)
+	teamID := pgtype.Int8{Int64: project.TeamID, Valid: true}
+	task := createRandomTaskLocal(t, project.ID)
+	first, _ := createRandomUserWithRoleAndTeam(t, UserRoleEngineer, teamID)
+	second, _ := createRandomUserWithRoleAndTeam(t, UserRoleEngineer, teamID)
+	engineers := []User{first, second}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(engineers))
+
+	for i, engineer := range engineers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = store.ClaimTaskTx(context.Background(), ClaimTaskTxParams{
+				TaskID:     task.ID,
+				EngineerID: engineer.ID,
+				TeamID:     project.TeamID,
+			})
+		}()
+	}
+	wg.Wait()
+
+	// Exactly one claim wins; the loser is told the task is gone and stays available
+	var winner, loser int
+	switch {
+	case errs[0] == nil && errors.Is(errs[1], ErrTaskNotClaimable):
+		winner, loser = 0, 1
+	case errs[1] == nil && errors.Is(errs[0], ErrTaskNotClaimable):
+		winner, loser = 1, 0
+	default:
+		t.Fatalf("expected exactly one successful claim, got %v and %v", errs[0], errs[1])
+	}
+
+	claimed, err := testQueries.GetTask(context.Background(), task.ID)
+	require.NoError(t, err)
+	require.Equal(t, engineers[winner].ID, claimed.AssigneeID.Int64)
+
+	loserUser, err := testQueries.GetUser(context.Background(), engineers[loser].ID)
+	require.NoError(t, err)
+	require.Equal(t, AvailabilityStatusAvailable, loserUser.Availability)
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // Test: CreateInvitationTx
 ////////////////////////////////////////////////////////////////////////////////
@@ -469,88 +702,1526 @@ func TestCreateInvitationTx(t *testing.T) {
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "inviter with ID 99999999 not found")
 	})
+
+	t.Run("Success: Invitation lapses after the requested duration", func(t *testing.T) {
+		manager, _ := createRandomManagerWithTeam(t)
+		inviteeEmail := util.RandomEmail()
+
+		result, err := store.CreateInvitationTx(context.Background(), CreateInvitationTxParams{
+			InviterID:     manager.ID,
+			EmailToInvite: inviteeEmail,
+			RoleToInvite:  UserRoleEngineer,
+			Duration:      time.Second,
+		})
+		require.NoError(t, err)
+		require.WithinDuration(t, time.Now().Add(time.Second), result.Invitation.ExpiresAt.Time, time.Second)
+
+		_, err = store.GetInvitationByToken(context.Background(), result.Invitation.InvitationToken)
+		require.NoError(t, err)
+
+		time.Sleep(1500 * time.Millisecond)
+
+		// The token no longer resolves, so the invitation cannot be accepted
+		_, err = store.GetInvitationByToken(context.Background(), result.Invitation.InvitationToken)
+		require.ErrorIs(t, err, pgx.ErrNoRows)
+		_, err = store.AcceptInvitationTx(context.Background(), AcceptInvitationTxParams{
+			InvitationToken: result.Invitation.InvitationToken,
+			UserName:        util.RandomName(),
+			PasswordHash:    util.RandomString(32),
+		})
+		require.ErrorIs(t, err, ErrInvitationNotPending)
+
+		// It is still pending, so it blocks a duplicate until it is resent or expired
+		_, err = store.GetInvitationByEmail(context.Background(), inviteeEmail)
+		require.NoError(t, err)
+	})
 }
 
-////////////////////////////////////////////////////////////////////////////////
-//                               TEST HELPERS
-////////////////////////////////////////////////////////////////////////////////
+func TestAcceptInvitationTx_ConcurrentManagers(t *testing.T) {
+	store := NewStore(testPool)
+	admin, _ := createRandomUserWithRoleAndNoTeam(t, UserRoleAdmin)
+	team := createRandomTeam(t)
 
-// createRandomTaskLocal creates a task in a given project with random values.
-func createRandomTaskLocal(t *testing.T, projectID int64) Task {
-	arg := CreateTaskParams{
-		ProjectID: pgtype.Int8{Int64: projectID, Valid: true},
-		Title:     util.RandomTaskTitle(),
-		Description: pgtype.Text{
-			String: util.RandomTaskDescription(),
-			Valid:  true,
-		},
-		Status:     TaskStatusOpen,
-		Priority:   TaskPriority(util.RandomPriority()),
-		AssigneeID: pgtype.Int8{Valid: false},
+	// Two manager invitations for the same vacant team, as two admins racing
+	// CreateInvitationTx could leave behind
+	tokens := make([]string, 2)
+	for i := range tokens {
+		invitation, err := testQueries.CreateInvitation(context.Background(), CreateInvitationParams{
+			Email:           util.RandomEmail(),
+			InvitationToken: util.RandomString(32),
+			RoleToInvite:    UserRoleManager,
+			InviterID:       admin.ID,
+			ExpiresAt:       pgtype.Timestamp{Time: time.Now().Add(time.Hour), Valid: true},
+			TeamID:          pgtype.Int8{Int64: team.ID, Valid: true},
+		})
+		require.NoError(t, err)
+		tokens[i] = invitation.InvitationToken
 	}
 
-	task, err := testQueries.CreateTask(context.Background(), arg)
+	var wg sync.WaitGroup
+	results := make([]AcceptInvitationTxResult, len(tokens))
+	errs := make([]error, len(tokens))
+
+	for i, token := range tokens {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = store.AcceptInvitationTx(context.Background(), AcceptInvitationTxParams{
+				InvitationToken: token,
+				UserName:        util.RandomName(),
+				PasswordHash:    util.RandomString(32),
+			})
+		}()
+	}
+	wg.Wait()
+
+	// Exactly one manager joins; the other is refused cleanly and their invitation stays pending
+	var winner, loser int
+	switch {
+	case errs[0] == nil && errors.Is(errs[1], ErrTeamAlreadyHasManager):
+		winner, loser = 0, 1
+	case errs[1] == nil && errors.Is(errs[0], ErrTeamAlreadyHasManager):
+		winner, loser = 1, 0
+	default:
+		t.Fatalf("expected exactly one accepted invitation, got %v and %v", errs[0], errs[1])
+	}
+
+	updatedTeam, err := testQueries.GetTeam(context.Background(), team.ID)
 	require.NoError(t, err)
-	require.NotEmpty(t, task)
+	require.Equal(t, results[winner].User.ID, updatedTeam.ManagerID.Int64)
 
-	require.Equal(t, arg.ProjectID.Int64, task.ProjectID.Int64)
-	require.False(t, task.AssigneeID.Valid)
-	require.Equal(t, arg.Title, task.Title)
+	_, err = testQueries.GetInvitationByToken(context.Background(), tokens[loser])
+	require.NoError(t, err)
+}
 
-	return task
+////////////////////////////////////////////////////////////////////////////////
+// Test: GetTeamBoardTx
+////////////////////////////////////////////////////////////////////////////////
+
+func TestGetTeamBoardTx(t *testing.T) {
+	store := NewStore(testPool)
+
+	t.Run("Success: Tasks are grouped under their projects", func(t *testing.T) {
+		// Arrange
+		team := createRandomTeam(t)
+		var projects []Project
+		for range 3 {
+			project, err := testQueries.CreateProject(context.Background(), CreateProjectParams{
+				ProjectName: util.RandomProjectName(),
+				TeamID:      team.ID,
+			})
+			require.NoError(t, err)
+			projects = append(projects, project)
+		}
+
+		// Two tasks in the first project, one in the second, none in the third
+		expected := map[int64][]int64{}
+		for _, project := range []Project{projects[0], projects[0], projects[1]} {
+			task := createRandomTaskLocal(t, project.ID)
+			expected[project.ID] = append(expected[project.ID], task.ID)
+		}
+
+		// Act
+		result, err := store.GetTeamBoardTx(context.Background(), GetTeamBoardTxParams{
+			TeamID:    team.ID,
+			Limit:     10,
+			Offset:    0,
+			TaskLimit: 100,
+		})
+
+		// Assert
+		require.NoError(t, err)
+		require.Equal(t, int64(3), result.TotalProjects)
+		require.False(t, result.TasksTruncated)
+		require.Len(t, result.Projects, 3)
+
+		for _, boardProject := range result.Projects {
+			var gotIDs []int64
+			for _, task := range boardProject.Tasks {
+				require.Equal(t, boardProject.ID, task.ProjectID.Int64)
+				gotIDs = append(gotIDs, task.ID)
+			}
+			require.ElementsMatch(t, expected[boardProject.ID], gotIDs)
+			require.NotNil(t, boardProject.Tasks)
+		}
+	})
+
+	t.Run("Success: Task cap truncates the board", func(t *testing.T) {
+		// Arrange
+		project := createRandomProject(t)
+		for range 3 {
+			createRandomTaskLocal(t, project.ID)
+		}
+
+		// Act
+		result, err := store.GetTeamBoardTx(context.Background(), GetTeamBoardTxParams{
+			TeamID:    project.TeamID,
+			Limit:     10,
+			Offset:    0,
+			TaskLimit: 2,
+		})
+
+		// Assert
+		require.NoError(t, err)
+		require.True(t, result.TasksTruncated)
+		require.Len(t, result.Projects, 1)
+		require.Len(t, result.Projects[0].Tasks, 2)
+	})
 }
 
-// createRandomUserWithRole creates a user with a given role and sets it explicitly.
-func createRandomUserWithRole(t *testing.T, role UserRole) (User, string) {
-	user, password := createRandomUser(t)
-	updatedUser, err := testQueries.UpdateUser(context.Background(), UpdateUserParams{
-		ID:   user.ID,
-		Role: NullUserRole{UserRole: role, Valid: true},
+func TestDeclineTaskTx(t *testing.T) {
+	store := NewStore(testPool)
+	project := createRandomProject(t)
+	engineer, _ := createRandomUser(t)
+	otherEngineer, _ := createRandomUser(t)
+	task := createRandomTaskLocal(t, project.ID)
+
+	_, err := store.AssignTaskToUser(context.Background(), AssignTaskToUserTxParams{
+		TaskID: task.ID,
+		UserID: engineer.ID,
 	})
 	require.NoError(t, err)
-	require.Equal(t, role, updatedUser.Role)
-	return updatedUser, password
+
+	// Another engineer cannot decline the task
+	_, err = store.DeclineTaskTx(context.Background(), DeclineTaskTxParams{
+		TaskID:     task.ID,
+		EngineerID: otherEngineer.ID,
+	})
+	require.ErrorIs(t, err, ErrTaskNotAssignedToUser)
+
+	// The assignee can, which reopens the task and frees them
+	result, err := store.DeclineTaskTx(context.Background(), DeclineTaskTxParams{
+		TaskID:     task.ID,
+		EngineerID: engineer.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, TaskStatusOpen, result.Task.Status)
+	require.False(t, result.Task.AssigneeID.Valid)
+	require.Equal(t, AvailabilityStatusAvailable, result.User.Availability)
+
+	// Declining again fails because the task is no longer assigned
+	_, err = store.DeclineTaskTx(context.Background(), DeclineTaskTxParams{
+		TaskID:     task.ID,
+		EngineerID: engineer.ID,
+	})
+	require.ErrorIs(t, err, ErrTaskNotAssignedToUser)
 }
 
-// createRandomUserWithRoleAndNoTeam creates a user with a role and ensures they are not assigned to any team.
-func createRandomUserWithRoleAndNoTeam(t *testing.T, role UserRole) (User, string) {
-	user, password := createRandomUserWithRole(t, role)
+func TestReassignTaskTx(t *testing.T) {
+	store := NewStore(testPool)
+	project := createRandomProject(t)
+	teamID := pgtype.Int8{Int64: project.TeamID, Valid: true}
+	previous, _ := createRandomUserWithRoleAndTeam(t, UserRoleEngineer, teamID)
+	next, _ := createRandomUserWithRoleAndTeam(t, UserRoleEngineer, teamID)
+	task := createRandomTaskLocal(t, project.ID)
 
-	// Explicitly remove user from team to ensure team_id is NULL.
-	unassignedUser, err := testQueries.RemoveUserFromTeam(context.Background(), user.ID)
+	_, err := store.AssignTaskToUser(context.Background(), AssignTaskToUserTxParams{TaskID: task.ID, UserID: previous.ID})
 	require.NoError(t, err)
 
-	require.False(t, unassignedUser.TeamID.Valid, "User should not have a team ID")
-	require.Equal(t, role, unassignedUser.Role)
+	// Engineers of other teams and managers cannot take the task
+	outsider, _ := createRandomUserWithRoleAndTeam(t, UserRoleEngineer, pgtype.Int8{Int64: createRandomTeam(t).ID, Valid: true})
+	manager, _ := createRandomUserWithRoleAndTeam(t, UserRoleManager, teamID)
+	for _, user := range []User{outsider, manager} {
+		_, err = store.ReassignTaskTx(context.Background(), ReassignTaskTxParams{TaskID: task.ID, NewAssigneeID: user.ID, TeamID: project.TeamID})
+		require.ErrorIs(t, err, ErrAssigneeNotOnTeam)
+	}
 
-	return unassignedUser, password
+	// Nor can the engineer who already has it
+	_, err = store.ReassignTaskTx(context.Background(), ReassignTaskTxParams{TaskID: task.ID, NewAssigneeID: previous.ID, TeamID: project.TeamID})
+	require.ErrorIs(t, err, ErrTaskAlreadyAssigned)
+
+	// An available teammate takes it over and the previous assignee is freed
+	result, err := store.ReassignTaskTx(context.Background(), ReassignTaskTxParams{TaskID: task.ID, NewAssigneeID: next.ID, TeamID: project.TeamID})
+	require.NoError(t, err)
+	require.Equal(t, next.ID, result.Task.AssigneeID.Int64)
+	require.Equal(t, TaskStatusInProgress, result.Task.Status)
+	require.Equal(t, AvailabilityStatusBusy, result.NewAssignee.Availability)
+	require.NotNil(t, result.PreviousAssignee)
+	require.Equal(t, AvailabilityStatusAvailable, result.PreviousAssignee.Availability)
+
+	activity, err := testQueries.ListTaskActivity(context.Background(), task.ID)
+	require.NoError(t, err)
+	require.Equal(t, TaskActivityEventReassigned, activity[0].Event)
+	require.Equal(t, next.ID, activity[0].AssigneeID.Int64)
+
+	// Once busy with other work, the previous assignee cannot take the task back
+	_, err = store.AssignTaskToUser(context.Background(), AssignTaskToUserTxParams{TaskID: createRandomTaskLocal(t, project.ID).ID, UserID: previous.ID})
+	require.NoError(t, err)
+	_, err = store.ReassignTaskTx(context.Background(), ReassignTaskTxParams{TaskID: task.ID, NewAssigneeID: previous.ID, TeamID: project.TeamID})
+	require.ErrorIs(t, err, ErrEngineerNotAvailable)
 }
 
-// createRandomManagerWithTeam sets up a team and assigns a manager to it.
-// It updates both the user and the team to ensure referential integrity.
-func createRandomManagerWithTeam(t *testing.T) (User, Team) {
-	team := createRandomTeam(t)
+func TestReassignTaskTx_RollsBack(t *testing.T) {
+	store := NewStore(testPool)
+	project := createRandomProject(t)
+	teamID := pgtype.Int8{Int64: project.TeamID, Valid: true}
+	previous, _ := createRandomUserWithRoleAndTeam(t, UserRoleEngineer, teamID)
+	next, _ := createRandomUserWithRoleAndTeam(t, UserRoleEngineer, teamID)
+	task := createRandomTaskLocal(t, project.ID)
 
-	// Create a user with manager role.
-	manager, _ := createRandomUserWithRole(t, UserRoleManager)
+	_, err := store.AssignTaskToUser(context.Background(), AssignTaskToUserTxParams{TaskID: task.ID, UserID: previous.ID})
+	require.NoError(t, err)
 
-	// Assign manager to team by updating the user's team_id.
-	updatedManager, err := testQueries.UpdateUser(context.Background(), UpdateUserParams{
-		ID:     manager.ID,
-		TeamID: pgtype.Int8{Int64: team.ID, Valid: true},
+	// An actor that does not exist fails the activity insert, the last step after every update
+	_, err = store.ReassignTaskTx(context.Background(), ReassignTaskTxParams{
+		TaskID:        task.ID,
+		NewAssigneeID: next.ID,
+		TeamID:        project.TeamID,
+		ActorID:       pgtype.Int8{Int64: math.MaxInt64, Valid: true},
 	})
+	require.Error(t, err)
+
+	// The task, the new assignee and the previous assignee are all as they were
+	task, err = testQueries.GetTask(context.Background(), task.ID)
 	require.NoError(t, err)
-	require.True(t, updatedManager.TeamID.Valid)
-	require.Equal(t, team.ID, updatedManager.TeamID.Int64)
+	require.Equal(t, previous.ID, task.AssigneeID.Int64)
 
-	// Set team's manager_id to the user.
-	updatedTeam, err := testQueries.UpdateTeam(context.Background(), UpdateTeamParams{
-		ID:        team.ID,
-		ManagerID: pgtype.Int8{Int64: updatedManager.ID, Valid: true},
-	})
+	next, err = testQueries.GetUser(context.Background(), next.ID)
 	require.NoError(t, err)
-	require.True(t, updatedTeam.ManagerID.Valid)
-	require.Equal(t, updatedManager.ID, updatedTeam.ManagerID.Int64)
+	require.Equal(t, AvailabilityStatusAvailable, next.Availability)
 
-	return updatedManager, updatedTeam
+	previous, err = testQueries.GetUser(context.Background(), previous.ID)
+	require.NoError(t, err)
+	require.Equal(t, AvailabilityStatusBusy, previous.Availability)
+}
+
+func TestPauseTaskTx(t *testing.T) {
+	store := NewStore(testPool)
+	project := createRandomProject(t)
+	engineer, _ := createRandomUser(t)
+	first := createRandomTaskLocal(t, project.ID)
+	second := createRandomTaskLocal(t, project.ID)
+
+	for _, task := range []Task{first, second} {
+		_, err := store.AssignTaskToUser(context.Background(), AssignTaskToUserTxParams{
+			TaskID: task.ID,
+			UserID: engineer.ID,
+		})
+		require.NoError(t, err)
+	}
+	params := func(task Task) PauseTaskTxParams {
+		return PauseTaskTxParams{TaskID: task.ID, EngineerID: engineer.ID}
+	}
+
+	// Pausing one task keeps the engineer busy while the other is still in progress
+	result, err := store.PauseTaskTx(context.Background(), params(first))
+	require.NoError(t, err)
+	require.Equal(t, TaskStatusOpen, result.Task.Status)
+	require.Equal(t, engineer.ID, result.Task.AssigneeID.Int64)
+	require.Equal(t, AvailabilityStatusBusy, result.User.Availability)
+
+	// Resuming is refused while another task is in progress
+	_, err = store.ResumeTaskTx(context.Background(), params(first))
+	require.ErrorIs(t, err, ErrEngineerNotAvailable)
+
+	// Pausing the last in-progress task frees them
+	result, err = store.PauseTaskTx(context.Background(), params(second))
+	require.NoError(t, err)
+	require.Equal(t, AvailabilityStatusAvailable, result.User.Availability)
+
+	// A paused task cannot be paused again
+	_, err = store.PauseTaskTx(context.Background(), params(second))
+	require.ErrorIs(t, err, ErrTaskNotAssignedToUser)
+
+	// Resuming puts the task back in progress and the engineer back to busy
+	result, err = store.ResumeTaskTx(context.Background(), params(first))
+	require.NoError(t, err)
+	require.Equal(t, TaskStatusInProgress, result.Task.Status)
+	require.Equal(t, AvailabilityStatusBusy, result.User.Availability)
+
+	// Only the engineer's paused tasks can be resumed
+	_, err = store.PauseTaskTx(context.Background(), params(first))
+	require.NoError(t, err)
+	_, err = store.ResumeTaskTx(context.Background(), params(createRandomTaskLocal(t, project.ID)))
+	require.ErrorIs(t, err, ErrTaskNotPausedByUser)
+
+	// Resuming both paused tasks at once puts exactly one back in progress
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i, task := range []Task{first, second} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = store.ResumeTaskTx(context.Background(), params(task))
+		}()
+	}
+	wg.Wait()
+
+	switch {
+	case errs[0] == nil && errors.Is(errs[1], ErrEngineerNotAvailable):
+	case errs[1] == nil && errors.Is(errs[0], ErrEngineerNotAvailable):
+	default:
+		t.Fatalf("expected exactly one resumed task, got %v and %v", errs[0], errs[1])
+	}
+
+	active, err := testQueries.CountInProgressTasksByAssignee(context.Background(), pgtype.Int8{Int64: engineer.ID, Valid: true})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), active)
+}
+
+func TestUpdateTaskTx(t *testing.T) {
+	store := NewStore(testPool)
+	ctx := context.Background()
+	project := createRandomProject(t)
+	engineer, _ := createRandomUser(t)
+	manager, _ := createRandomUser(t)
+	task := createRandomTaskLocal(t, project.ID)
+
+	assigned, err := store.AssignTaskToUser(ctx, AssignTaskToUserTxParams{TaskID: task.ID, UserID: engineer.ID})
+	require.NoError(t, err)
+
+	// Status changes made outside UpdateTaskTx bump the version too
+	paused, err := store.PauseTaskTx(ctx, PauseTaskTxParams{TaskID: task.ID, EngineerID: engineer.ID})
+	require.NoError(t, err)
+	require.Equal(t, assigned.Task.Version+1, paused.Task.Version)
+
+	// A stale version is refused before anything is written
+	_, err = store.UpdateTaskTx(ctx, UpdateTaskTxParams{
+		TaskID:  task.ID,
+		ActorID: manager.ID,
+		Version: assigned.Task.Version,
+		Status:  NullTaskStatus{TaskStatus: TaskStatusInProgress, Valid: true},
+		Details: &UpdateTaskParams{Title: pgtype.Text{String: "Stale", Valid: true}},
+	})
+	require.ErrorIs(t, err, ErrStaleTaskVersion)
+
+	current, err := store.GetTask(ctx, task.ID)
+	require.NoError(t, err)
+	require.Equal(t, TaskStatusOpen, current.Status)
+	require.Equal(t, paused.Task.Title, current.Title)
+	require.Equal(t, paused.Task.Version, current.Version)
+
+	// With the current version, the status and the details change together
+	result, err := store.UpdateTaskTx(ctx, UpdateTaskTxParams{
+		TaskID:  task.ID,
+		ActorID: manager.ID,
+		Version: current.Version,
+		Status:  NullTaskStatus{TaskStatus: TaskStatusInProgress, Valid: true},
+		Details: &UpdateTaskParams{Title: pgtype.Text{String: "Fresh", Valid: true}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, TaskStatusOpen, result.PreviousStatus)
+	require.Equal(t, TaskStatusInProgress, result.Task.Status)
+	require.Equal(t, "Fresh", result.Task.Title)
+	require.Equal(t, current.Version+2, result.Task.Version)
+	require.NotNil(t, result.Assignee)
+	require.Equal(t, AvailabilityStatusBusy, result.Assignee.Availability)
+
+	// Details alone leave the status and the assignee alone
+	result, err = store.UpdateTaskTx(ctx, UpdateTaskTxParams{
+		TaskID:  task.ID,
+		ActorID: manager.ID,
+		Version: result.Task.Version,
+		Details: &UpdateTaskParams{Priority: NullTaskPriority{TaskPriority: TaskPriorityHigh, Valid: true}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, TaskStatusInProgress, result.Task.Status)
+	require.Equal(t, TaskPriorityHigh, result.Task.Priority)
+	require.Nil(t, result.Assignee)
+
+	// Transitions outside the status machine are refused
+	_, err = store.UpdateTaskTx(ctx, UpdateTaskTxParams{
+		TaskID:  task.ID,
+		ActorID: manager.ID,
+		Version: result.Task.Version,
+		Status:  NullTaskStatus{TaskStatus: TaskStatusOpen, Valid: true},
+	})
+	require.ErrorIs(t, err, ErrUnsupportedStatusChange)
+}
+
+func TestReleaseEngineerKeepsBusyWithOtherWork(t *testing.T) {
+	store := NewStore(testPool)
+	project := createRandomProject(t)
+	engineer, _ := createRandomUser(t)
+	first := createRandomTaskLocal(t, project.ID)
+	second := createRandomTaskLocal(t, project.ID)
+	third := createRandomTaskLocal(t, project.ID)
+
+	for _, task := range []Task{first, second, third} {
+		_, err := store.AssignTaskToUser(context.Background(), AssignTaskToUserTxParams{
+			TaskID: task.ID,
+			UserID: engineer.ID,
+		})
+		require.NoError(t, err)
+	}
+
+	// Completing one task keeps the engineer busy while others are in progress
+	completed, err := store.CompleteTaskTx(context.Background(), CompleteTaskTxParams{TaskID: first.ID})
+	require.NoError(t, err)
+	require.Equal(t, AvailabilityStatusBusy, completed.UpdatedUser.Availability)
+
+	// So does declining one
+	declined, err := store.DeclineTaskTx(context.Background(), DeclineTaskTxParams{
+		TaskID:     second.ID,
+		EngineerID: engineer.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, AvailabilityStatusBusy, declined.User.Availability)
+
+	// Finishing the last in-progress task frees them
+	completed, err = store.CompleteTaskTx(context.Background(), CompleteTaskTxParams{TaskID: third.ID})
+	require.NoError(t, err)
+	require.Equal(t, AvailabilityStatusAvailable, completed.UpdatedUser.Availability)
+}
+
+func TestOnLeaveEngineerGetsNoWork(t *testing.T) {
+	store := NewStore(testPool)
+	project := createRandomProject(t)
+	engineer, _ := createRandomUserWithRoleAndTeam(t, UserRoleEngineer, pgtype.Int8{Int64: project.TeamID, Valid: true})
+	paused := createRandomTaskLocal(t, project.ID)
+
+	// The engineer pauses their task, then goes on leave
+	_, err := store.AssignTaskToUser(context.Background(), AssignTaskToUserTxParams{TaskID: paused.ID, UserID: engineer.ID})
+	require.NoError(t, err)
+	_, err = store.PauseTaskTx(context.Background(), PauseTaskTxParams{TaskID: paused.ID, EngineerID: engineer.ID})
+	require.NoError(t, err)
+	user, err := testQueries.UpdateAvailabilityIfNotBusy(context.Background(), UpdateAvailabilityIfNotBusyParams{
+		ID:           engineer.ID,
+		Availability: AvailabilityStatusOnLeave,
+	})
+	require.NoError(t, err)
+	require.Equal(t, AvailabilityStatusOnLeave, user.Availability)
+
+	// No work reaches them: not by assignment, by claiming or by resuming
+	_, err = store.AssignTaskToUser(context.Background(), AssignTaskToUserTxParams{
+		TaskID: createRandomTaskLocal(t, project.ID).ID,
+		UserID: engineer.ID,
+	})
+	require.ErrorIs(t, err, ErrEngineerOnLeave)
+	_, err = store.ClaimTaskTx(context.Background(), ClaimTaskTxParams{
+		TaskID:     createRandomTaskLocal(t, project.ID).ID,
+		EngineerID: engineer.ID,
+		TeamID:     project.TeamID,
+	})
+	require.ErrorIs(t, err, ErrEngineerOnLeave)
+	_, err = store.ResumeTaskTx(context.Background(), PauseTaskTxParams{TaskID: paused.ID, EngineerID: engineer.ID})
+	require.ErrorIs(t, err, ErrEngineerOnLeave)
+
+	// The refusals rolled back, so they are still on leave and the task is still paused
+	user, err = testQueries.GetUser(context.Background(), engineer.ID)
+	require.NoError(t, err)
+	require.Equal(t, AvailabilityStatusOnLeave, user.Availability)
+	task, err := testQueries.GetTask(context.Background(), paused.ID)
+	require.NoError(t, err)
+	require.Equal(t, TaskStatusOpen, task.Status)
+
+	// Back from leave, they resume; while busy they cannot go on leave
+	_, err = testQueries.UpdateAvailabilityIfNotBusy(context.Background(), UpdateAvailabilityIfNotBusyParams{
+		ID:           engineer.ID,
+		Availability: AvailabilityStatusAvailable,
+	})
+	require.NoError(t, err)
+	_, err = store.ResumeTaskTx(context.Background(), PauseTaskTxParams{TaskID: paused.ID, EngineerID: engineer.ID})
+	require.NoError(t, err)
+	_, err = testQueries.UpdateAvailabilityIfNotBusy(context.Background(), UpdateAvailabilityIfNotBusyParams{
+		ID:           engineer.ID,
+		Availability: AvailabilityStatusOnLeave,
+	})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func TestSafeDeleteTeamTx(t *testing.T) {
+	store := NewStore(testPool)
+	invitation := createRandomInvitation(t)
+	teamID := invitation.TeamID
+	member, _ := createRandomUserWithRoleAndTeam(t, UserRoleEngineer, teamID)
+	project, err := testQueries.CreateProject(context.Background(), CreateProjectParams{
+		ProjectName: util.RandomProjectName(),
+		TeamID:      teamID.Int64,
+	})
+	require.NoError(t, err)
+	arg := SafeDeleteTeamTxParams{TeamID: teamID.Int64}
+
+	// An active project blocks the deletion, and the dry run says why
+	impact, err := store.GetTeamDeletionImpactTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, impact.CanDelete)
+	require.NotEmpty(t, impact.BlockingReason)
+	require.Equal(t, int64(1), impact.ActiveProjects)
+
+	_, err = store.SafeDeleteTeamTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrTeamHasActiveProjects)
+
+	// Once the project is archived the team can go
+	_, err = store.ArchiveProjectTx(context.Background(), ArchiveProjectTxParams{ProjectID: project.ID, TeamID: teamID.Int64})
+	require.NoError(t, err)
+
+	impact, err = store.GetTeamDeletionImpactTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, impact.CanDelete)
+	require.Len(t, impact.MembersToUnassign, 1)
+	require.Equal(t, int64(1), impact.ProjectsToRemove)
+	require.Equal(t, int64(1), impact.InvitationsToRemove)
+
+	result, err := store.SafeDeleteTeamTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, teamID.Int64, result.DeletedTeam.ID)
+	require.Len(t, result.RemovedMembers, 1)
+	require.False(t, result.RemovedMembers[0].TeamID.Valid)
+	require.Equal(t, int64(1), result.RemovedProjects)
+	require.Equal(t, int64(1), result.RemovedInvitations)
+
+	// The member stays, without a team; the team, its project and its invitation are gone
+	user, err := testQueries.GetUser(context.Background(), member.ID)
+	require.NoError(t, err)
+	require.False(t, user.TeamID.Valid)
+	_, err = testQueries.GetProject(context.Background(), project.ID)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	_, err = testQueries.GetInvitationByID(context.Background(), invitation.ID)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	_, err = store.SafeDeleteTeamTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrTeamNotFound)
+}
+
+func TestTransferUserToTeamTx(t *testing.T) {
+	store := NewStore(testPool)
+	project := createRandomProject(t)
+	oldTeamID := pgtype.Int8{Int64: project.TeamID, Valid: true}
+	newTeam := createRandomTeam(t)
+	engineer, _ := createRandomUserWithRoleAndTeam(t, UserRoleEngineer, oldTeamID)
+	admin, _ := createRandomUserWithRoleAndNoTeam(t, UserRoleAdmin)
+	inProgress := createRandomTaskLocal(t, project.ID)
+	paused := createRandomTaskLocal(t, project.ID)
+
+	for _, task := range []Task{inProgress, paused} {
+		_, err := store.AssignTaskToUser(context.Background(), AssignTaskToUserTxParams{
+			TaskID: task.ID,
+			UserID: engineer.ID,
+		})
+		require.NoError(t, err)
+	}
+	_, err := store.PauseTaskTx(context.Background(), PauseTaskTxParams{TaskID: paused.ID, EngineerID: engineer.ID})
+	require.NoError(t, err)
+
+	arg := TransferUserToTeamTxParams{
+		UserID:    engineer.ID,
+		NewTeamID: newTeam.ID,
+		ActorID:   pgtype.Int8{Int64: admin.ID, Valid: true},
+	}
+	result, err := store.TransferUserToTeamTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, newTeam.ID, result.User.TeamID.Int64)
+	require.Equal(t, AvailabilityStatusAvailable, result.User.Availability)
+	require.Equal(t, oldTeamID, result.PreviousTeamID)
+	require.Len(t, result.UnassignedTasks, 2)
+	for _, task := range result.UnassignedTasks {
+		require.Equal(t, TaskStatusOpen, task.Status)
+		require.False(t, task.AssigneeID.Valid)
+	}
+
+	// Only the task that was actually in progress logs a status change, attributed to the admin
+	activity, err := testQueries.ListTaskActivity(context.Background(), inProgress.ID)
+	require.NoError(t, err)
+	require.Equal(t, TaskActivityEventStatusChanged, activity[0].Event)
+	require.Equal(t, TaskStatusInProgress, activity[0].FromStatus.TaskStatus)
+	require.Equal(t, admin.ID, activity[0].ActorID.Int64)
+	activity, err = testQueries.ListTaskActivity(context.Background(), paused.ID)
+	require.NoError(t, err)
+	require.NotEqual(t, admin.ID, activity[0].ActorID.Int64)
+
+	_, err = store.TransferUserToTeamTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrUserAlreadyOnTeam)
+
+	arg.UserID = admin.ID
+	_, err = store.TransferUserToTeamTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrTransferNotEngineer)
+}
+
+func TestReassignAllTasksTx(t *testing.T) {
+	store := NewStore(testPool)
+	project := createRandomProject(t)
+	teamID := pgtype.Int8{Int64: project.TeamID, Valid: true}
+	leaving, _ := createRandomUserWithRoleAndTeam(t, UserRoleEngineer, teamID)
+	successor, _ := createRandomUserWithRoleAndTeam(t, UserRoleEngineer, teamID)
+	admin, _ := createRandomUserWithRoleAndNoTeam(t, UserRoleAdmin)
+	inProgress := createRandomTaskLocal(t, project.ID)
+	paused := createRandomTaskLocal(t, project.ID)
+	done := createRandomTaskLocal(t, project.ID)
+
+	for _, task := range []Task{inProgress, paused, done} {
+		_, err := store.AssignTaskToUser(context.Background(), AssignTaskToUserTxParams{
+			TaskID: task.ID,
+			UserID: leaving.ID,
+		})
+		require.NoError(t, err)
+	}
+	_, err := store.PauseTaskTx(context.Background(), PauseTaskTxParams{TaskID: paused.ID, EngineerID: leaving.ID})
+	require.NoError(t, err)
+	_, err = store.CompleteTaskTx(context.Background(), CompleteTaskTxParams{TaskID: done.ID})
+	require.NoError(t, err)
+
+	arg := ReassignAllTasksTxParams{
+		FromUserID: leaving.ID,
+		ToUserID:   successor.ID,
+		ActorID:    pgtype.Int8{Int64: admin.ID, Valid: true},
+	}
+	result, err := store.ReassignAllTasksTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	// Both active tasks move with their status kept; the finished one stays with its engineer
+	statuses := make(map[int64]TaskStatus)
+	for _, task := range result.Tasks {
+		require.Equal(t, successor.ID, task.AssigneeID.Int64)
+		statuses[task.ID] = task.Status
+	}
+	require.Equal(t, map[int64]TaskStatus{inProgress.ID: TaskStatusInProgress, paused.ID: TaskStatusOpen}, statuses)
+	doneTask, err := testQueries.GetTask(context.Background(), done.ID)
+	require.NoError(t, err)
+	require.Equal(t, leaving.ID, doneTask.AssigneeID.Int64)
+
+	// The successor took over work in progress; the leaving engineer has none left
+	require.Equal(t, AvailabilityStatusBusy, result.ToUser.Availability)
+	require.Equal(t, AvailabilityStatusAvailable, result.FromUser.Availability)
+
+	activity, err := testQueries.ListTaskActivity(context.Background(), paused.ID)
+	require.NoError(t, err)
+	require.Equal(t, TaskActivityEventReassigned, activity[0].Event)
+	require.Equal(t, successor.ID, activity[0].AssigneeID.Int64)
+	require.Equal(t, admin.ID, activity[0].ActorID.Int64)
+
+	// The successor is now busy, so work cannot be moved to them again
+	other, _ := createRandomUserWithRoleAndTeam(t, UserRoleEngineer, teamID)
+	_, err = store.ReassignAllTasksTx(context.Background(), ReassignAllTasksTxParams{FromUserID: other.ID, ToUserID: successor.ID})
+	require.ErrorIs(t, err, ErrEngineerNotAvailable)
+
+	_, err = store.ReassignAllTasksTx(context.Background(), ReassignAllTasksTxParams{FromUserID: leaving.ID, ToUserID: leaving.ID})
+	require.ErrorIs(t, err, ErrReassignSameEngineer)
+
+	_, err = store.ReassignAllTasksTx(context.Background(), ReassignAllTasksTxParams{FromUserID: leaving.ID, ToUserID: admin.ID})
+	require.ErrorIs(t, err, ErrReassignNotEngineer)
+
+	outsider, _ := createRandomUserWithRoleAndTeam(t, UserRoleEngineer, pgtype.Int8{Int64: createRandomTeam(t).ID, Valid: true})
+	_, err = store.ReassignAllTasksTx(context.Background(), ReassignAllTasksTxParams{FromUserID: leaving.ID, ToUserID: outsider.ID})
+	require.ErrorIs(t, err, ErrReassignDifferentTeam)
+}
+
+func TestUnarchiveProjectTx(t *testing.T) {
+	store := NewStore(testPool)
+	project := createRandomProject(t)
+	engineer, _ := createRandomUser(t)
+	task := createRandomTaskLocal(t, project.ID)
+
+	_, err := store.AssignTaskToUser(context.Background(), AssignTaskToUserTxParams{
+		TaskID: task.ID,
+		UserID: engineer.ID,
+	})
+	require.NoError(t, err)
+	completed, err := store.CompleteTaskTx(context.Background(), CompleteTaskTxParams{TaskID: task.ID})
+	require.NoError(t, err)
+	require.True(t, completed.CompletedTask.CompletedAt.Valid)
+
+	// A task archived on its own while in progress has its engineer freed
+	manager, _ := createRandomUserWithRoleAndTeam(t, UserRoleManager, pgtype.Int8{Int64: project.TeamID, Valid: true})
+	inProgress := createRandomTaskLocal(t, project.ID)
+	_, err = store.AssignTaskToUser(context.Background(), AssignTaskToUserTxParams{
+		TaskID: inProgress.ID,
+		UserID: engineer.ID,
+	})
+	require.NoError(t, err)
+	_, err = store.ArchiveTaskTx(context.Background(), ArchiveTaskTxParams{TaskID: inProgress.ID})
+	require.NoError(t, err)
+
+	params := UnarchiveProjectTxParams{
+		ProjectID: project.ID,
+		TeamID:    project.TeamID,
+		ActorID:   pgtype.Int8{Int64: manager.ID, Valid: true},
+	}
+
+	// An active project cannot be unarchived
+	_, err = store.UnarchiveProjectTx(context.Background(), params)
+	require.ErrorIs(t, err, ErrProjectNotArchived)
+
+	_, err = store.ArchiveProjectTx(context.Background(), ArchiveProjectTxParams{ProjectID: project.ID, TeamID: project.TeamID})
+	require.NoError(t, err)
+
+	// Another team cannot see the project
+	_, err = store.UnarchiveProjectTx(context.Background(), UnarchiveProjectTxParams{
+		ProjectID: project.ID,
+		TeamID:    createRandomTeam(t).ID,
+	})
+	require.ErrorIs(t, err, ErrProjectNotFound)
+
+	// Unarchiving restores the project and both tasks
+	result, err := store.UnarchiveProjectTx(context.Background(), params)
+	require.NoError(t, err)
+	require.False(t, result.UnarchivedProject.Archived)
+	require.False(t, result.UnarchivedProject.ArchivedAt.Valid)
+	require.Equal(t, int64(2), result.UnarchivedTasksCount)
+
+	// The done task keeps its completion history
+	restored, err := store.GetTask(context.Background(), task.ID)
+	require.NoError(t, err)
+	require.False(t, restored.Archived)
+	require.False(t, restored.ArchivedAt.Valid)
+	require.Equal(t, TaskStatusDone, restored.Status)
+	require.Equal(t, engineer.ID, restored.AssigneeID.Int64)
+	require.WithinDuration(t, completed.CompletedTask.CompletedAt.Time, restored.CompletedAt.Time, time.Microsecond)
+
+	// The in-progress task comes back open and unassigned, with the change in its activity log
+	reopened, err := store.GetTask(context.Background(), inProgress.ID)
+	require.NoError(t, err)
+	require.False(t, reopened.Archived)
+	require.Equal(t, TaskStatusOpen, reopened.Status)
+	require.False(t, reopened.AssigneeID.Valid)
+
+	activity, err := testQueries.ListTaskActivity(context.Background(), inProgress.ID)
+	require.NoError(t, err)
+	require.Equal(t, TaskActivityEventStatusChanged, activity[0].Event)
+	require.Equal(t, TaskStatusInProgress, activity[0].FromStatus.TaskStatus)
+	require.Equal(t, TaskStatusOpen, activity[0].ToStatus.TaskStatus)
+	require.Equal(t, manager.ID, activity[0].ActorID.Int64)
+
+	activity, err = testQueries.ListTaskActivity(context.Background(), task.ID)
+	require.NoError(t, err)
+	require.NotEqual(t, TaskActivityEventStatusChanged, activity[0].Event)
+}
+
+func TestMergeSkillsTx(t *testing.T) {
+	store := NewStore(testPool)
+	ctx := context.Background()
+	source := createRandomSkill(t)
+	target := createRandomSkill(t)
+
+	// Users: one holds both skills (higher on the source), one only the source
+	both, _ := createRandomUser(t)
+	onlySource, _ := createRandomUser(t)
+	for _, link := range []AddSkillToUserParams{
+		{UserID: both.ID, SkillID: source.ID, Proficiency: ProficiencyLevelExpert},
+		{UserID: both.ID, SkillID: target.ID, Proficiency: ProficiencyLevelBeginner},
+		{UserID: onlySource.ID, SkillID: source.ID, Proficiency: ProficiencyLevelIntermediate},
+	} {
+		_, err := testQueries.AddSkillToUser(ctx, link)
+		require.NoError(t, err)
+	}
+
+	// Tasks: one requires both skills, one only the source
+	project := createRandomProject(t)
+	bothTask := createRandomTaskLocal(t, project.ID)
+	sourceTask := createRandomTaskLocal(t, project.ID)
+	for _, link := range []AddSkillToTaskParams{
+		{TaskID: bothTask.ID, SkillID: source.ID},
+		{TaskID: bothTask.ID, SkillID: target.ID},
+		{TaskID: sourceTask.ID, SkillID: source.ID},
+	} {
+		_, err := testQueries.AddSkillToTask(ctx, link)
+		require.NoError(t, err)
+	}
+
+	sourceAlias, err := testQueries.CreateSkillAlias(ctx, CreateSkillAliasParams{
+		AliasName: util.RandomString(10),
+		SkillID:   source.ID,
+	})
+	require.NoError(t, err)
+
+	// A skill cannot be merged into itself
+	_, err = store.MergeSkillsTx(ctx, MergeSkillsTxParams{SourceID: source.ID, TargetID: source.ID})
+	require.ErrorIs(t, err, ErrMergeSameSkill)
+
+	result, err := store.MergeSkillsTx(ctx, MergeSkillsTxParams{SourceID: source.ID, TargetID: target.ID})
+	require.NoError(t, err)
+	require.Equal(t, target.ID, result.Target.ID)
+	require.Equal(t, int64(1), result.UserSkillsMerged)
+	require.Equal(t, int64(1), result.UserSkillsRepointed)
+	require.Equal(t, int64(1), result.TaskSkillsRepointed)
+	require.Equal(t, int64(1), result.AliasesRepointed)
+	require.True(t, result.SourceAliasCreated)
+
+	// The user who had both keeps the higher proficiency on the target
+	skills, err := testQueries.ListUserSkills(ctx, both.ID)
+	require.NoError(t, err)
+	require.Len(t, skills, 1)
+	require.Equal(t, target.ID, skills[0].SkillID)
+	require.Equal(t, ProficiencyLevelExpert, skills[0].Proficiency)
+
+	skills, err = testQueries.ListUserSkills(ctx, onlySource.ID)
+	require.NoError(t, err)
+	require.Len(t, skills, 1)
+	require.Equal(t, target.ID, skills[0].SkillID)
+	require.Equal(t, ProficiencyLevelIntermediate, skills[0].Proficiency)
+
+	// Both tasks now require only the target
+	for _, task := range []Task{bothTask, sourceTask} {
+		taskSkills, err := testQueries.GetSkillsForTask(ctx, task.ID)
+		require.NoError(t, err)
+		require.Len(t, taskSkills, 1)
+		require.Equal(t, target.ID, taskSkills[0].ID)
+	}
+
+	// The old alias and the source's own name both resolve to the target
+	for _, name := range []string{sourceAlias.AliasName, strings.ToLower(source.SkillName)} {
+		alias, err := testQueries.GetSkillAlias(ctx, name)
+		require.NoError(t, err)
+		require.Equal(t, target.ID, alias.SkillID)
+	}
+
+	// The source skill is gone, and merging it again reports so
+	_, err = testQueries.GetSkill(ctx, source.ID)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	_, err = store.MergeSkillsTx(ctx, MergeSkillsTxParams{SourceID: source.ID, TargetID: target.ID})
+	require.ErrorIs(t, err, ErrSkillNotFound)
+}
+
+func TestReprocessUserSkillsTx(t *testing.T) {
+	store := NewStore(testPool)
+	user, _ := createRandomUser(t)
+	raised := createRandomSkill(t)
+	lowered := createRandomSkill(t)
+	missing := createRandomSkill(t)
+	stale := createRandomSkill(t)
+
+	for skill, proficiency := range map[int64]ProficiencyLevel{
+		raised.ID:  ProficiencyLevelBeginner,
+		lowered.ID: ProficiencyLevelExpert,
+		missing.ID: ProficiencyLevelIntermediate,
+		stale.ID:   ProficiencyLevelExpert,
+	} {
+		_, err := testQueries.AddSkillToUser(context.Background(), AddSkillToUserParams{
+			UserID:      user.ID,
+			SkillID:     skill,
+			Proficiency: proficiency,
+		})
+		require.NoError(t, err)
+	}
+	_, err := testQueries.MarkUserSkillManual(context.Background(), MarkUserSkillManualParams{
+		UserID:  user.ID,
+		SkillID: missing.ID,
+	})
+	require.NoError(t, err)
+
+	// The new resume rates one skill higher and one lower, adds a skill and leaves out
+	// both the endorsed skill and a previously extracted one
+	result, err := store.ReprocessUserSkillsTx(context.Background(), ReprocessUserSkillsTxParams{
+		UserID: user.ID,
+		SkillsWithProficiency: map[string]ProficiencyLevel{
+			raised.SkillName:      ProficiencyLevelExpert,
+			lowered.SkillName:     ProficiencyLevelBeginner,
+			util.RandomString(12): ProficiencyLevelIntermediate,
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Added, 1)
+	require.Len(t, result.Upgraded, 1)
+	require.Equal(t, raised.ID, result.Upgraded[0].SkillID)
+	require.False(t, result.Upgraded[0].IsManual)
+	require.Equal(t, []int64{stale.ID}, result.Removed)
+
+	userSkills, err := testQueries.ListUserSkills(context.Background(), user.ID)
+	require.NoError(t, err)
+	proficiencies := make(map[int64]ProficiencyLevel, len(userSkills))
+	for _, userSkill := range userSkills {
+		proficiencies[userSkill.SkillID] = userSkill.Proficiency
+	}
+
+	// Nothing is lowered, the stale extracted skill is removed and the endorsed skill survives
+	require.Len(t, proficiencies, 4)
+	require.NotContains(t, proficiencies, stale.ID)
+	require.Equal(t, ProficiencyLevelExpert, proficiencies[raised.ID])
+	require.Equal(t, ProficiencyLevelExpert, proficiencies[lowered.ID])
+	require.Equal(t, ProficiencyLevelIntermediate, proficiencies[missing.ID])
+	require.Equal(t, ProficiencyLevelIntermediate, proficiencies[result.Added[0].SkillID])
+}
+
+func TestAddUserSkillTx(t *testing.T) {
+	store := NewStore(testPool)
+	user, _ := createRandomUser(t)
+	skill := createRandomSkill(t)
+	arg := AddUserSkillTxParams{
+		UserID:      user.ID,
+		SkillID:     skill.ID,
+		Proficiency: ProficiencyLevelIntermediate,
+	}
+
+	// The skill is linked as manual, so reprocessing keeps it
+	userSkill, err := store.AddUserSkillTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, ProficiencyLevelIntermediate, userSkill.Proficiency)
+	require.True(t, userSkill.IsManual)
+
+	_, err = store.AddUserSkillTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrUserSkillExists)
+
+	// Unknown skills are refused rather than created
+	arg.SkillID = math.MaxInt64
+	_, err = store.AddUserSkillTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrSkillNotFound)
+}
+
+func TestCreateTaskCommentTx(t *testing.T) {
+	store := NewStore(testPool)
+	project := createRandomProject(t)
+	task := createRandomTaskLocal(t, project.ID)
+	member, _ := createRandomUserWithRoleAndTeam(t, UserRoleEngineer, pgtype.Int8{Int64: project.TeamID, Valid: true})
+	outsider, _ := createRandomUserWithRoleAndTeam(t, UserRoleEngineer, pgtype.Int8{Int64: createRandomTeam(t).ID, Valid: true})
+
+	// A team member can comment and reply
+	comment, err := store.CreateTaskCommentTx(context.Background(), CreateTaskCommentTxParams{
+		TaskID:   task.ID,
+		AuthorID: member.ID,
+		Body:     "Who is picking this up?",
+	})
+	require.NoError(t, err)
+	require.False(t, comment.ParentID.Valid)
+
+	reply, err := store.CreateTaskCommentTx(context.Background(), CreateTaskCommentTxParams{
+		TaskID:   task.ID,
+		AuthorID: member.ID,
+		ParentID: pgtype.Int8{Int64: comment.ID, Valid: true},
+		Body:     "I am.",
+	})
+	require.NoError(t, err)
+	require.Equal(t, comment.ID, reply.ParentID.Int64)
+
+	// Someone from another team cannot
+	_, err = store.CreateTaskCommentTx(context.Background(), CreateTaskCommentTxParams{
+		TaskID:   task.ID,
+		AuthorID: outsider.ID,
+		Body:     "Drive-by comment",
+	})
+	require.ErrorIs(t, err, ErrCommenterNotInTeam)
+
+	// Replies must stay on the same task
+	otherTask := createRandomTaskLocal(t, project.ID)
+	_, err = store.CreateTaskCommentTx(context.Background(), CreateTaskCommentTxParams{
+		TaskID:   otherTask.ID,
+		AuthorID: member.ID,
+		ParentID: pgtype.Int8{Int64: comment.ID, Valid: true},
+		Body:     "Wrong thread",
+	})
+	require.ErrorIs(t, err, ErrParentCommentNotOnTask)
+
+	// Unknown tasks are reported as such
+	_, err = store.CreateTaskCommentTx(context.Background(), CreateTaskCommentTxParams{
+		TaskID:   otherTask.ID + 1000000,
+		AuthorID: member.ID,
+		Body:     "Anyone?",
+	})
+	require.ErrorIs(t, err, ErrTaskNotFound)
+}
+
+func TestTaskActivityLog(t *testing.T) {
+	store := NewStore(testPool)
+	project := createRandomProject(t)
+	teamID := pgtype.Int8{Int64: project.TeamID, Valid: true}
+	manager, _ := createRandomUserWithRoleAndTeam(t, UserRoleManager, teamID)
+	first, _ := createRandomUserWithRoleAndTeam(t, UserRoleEngineer, teamID)
+	second, _ := createRandomUserWithRoleAndTeam(t, UserRoleEngineer, teamID)
+	actor := pgtype.Int8{Int64: manager.ID, Valid: true}
+
+	created, err := store.ProcessNewTask(context.Background(), ProcessNewTaskTxParams{
+		CreateTaskParams: CreateTaskParams{
+			ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+			Title:     util.RandomTaskTitle(),
+			Status:    TaskStatusOpen,
+			Priority:  TaskPriorityMedium,
+		},
+		ActorID: actor,
+	})
+	require.NoError(t, err)
+	taskID := created.Task.ID
+
+	// Walk the task through its life: assigned, reassigned, paused, resumed, completed, archived
+	_, err = store.AssignTaskToUser(context.Background(), AssignTaskToUserTxParams{TaskID: taskID, UserID: first.ID, ActorID: actor})
+	require.NoError(t, err)
+	_, err = store.AssignTaskToUser(context.Background(), AssignTaskToUserTxParams{TaskID: taskID, UserID: second.ID, ActorID: actor})
+	require.NoError(t, err)
+	_, err = store.PauseTaskTx(context.Background(), PauseTaskTxParams{TaskID: taskID, EngineerID: second.ID})
+	require.NoError(t, err)
+	_, err = store.ResumeTaskTx(context.Background(), PauseTaskTxParams{TaskID: taskID, EngineerID: second.ID})
+	require.NoError(t, err)
+	_, err = store.CompleteTaskTx(context.Background(), CompleteTaskTxParams{TaskID: taskID})
+	require.NoError(t, err)
+	_, err = store.ArchiveProjectTx(context.Background(), ArchiveProjectTxParams{ProjectID: project.ID, TeamID: project.TeamID, ActorID: actor})
+	require.NoError(t, err)
+
+	activity, err := store.ListTaskActivity(context.Background(), taskID)
+	require.NoError(t, err)
+
+	// Newest first
+	events := make([]TaskActivityEvent, len(activity))
+	for i, entry := range activity {
+		events[i] = entry.Event
+	}
+	require.Equal(t, []TaskActivityEvent{
+		TaskActivityEventArchived,
+		TaskActivityEventCompleted,
+		TaskActivityEventStatusChanged,
+		TaskActivityEventStatusChanged,
+		TaskActivityEventReassigned,
+		TaskActivityEventAssigned,
+		TaskActivityEventCreated,
+	}, events)
+
+	require.Equal(t, manager.Name.String, activity[0].ActorName)
+	require.Equal(t, second.Name.String, activity[1].ActorName)
+	require.Equal(t, TaskStatusOpen, activity[2].FromStatus.TaskStatus)
+	require.Equal(t, TaskStatusInProgress, activity[2].ToStatus.TaskStatus)
+	require.Equal(t, TaskStatusInProgress, activity[3].FromStatus.TaskStatus)
+	require.Equal(t, TaskStatusOpen, activity[3].ToStatus.TaskStatus)
+	require.Equal(t, second.Name.String, activity[4].AssigneeName)
+	require.Equal(t, first.Name.String, activity[5].AssigneeName)
+	require.Equal(t, manager.ID, activity[6].ActorID.Int64)
+}
+
+func TestResetPasswordTx(t *testing.T) {
+	store := NewStore(testPool)
+	user, oldPassword := createRandomUser(t)
+
+	// Unknown emails are reported as missing rows
+	_, err := store.CreatePasswordResetTx(context.Background(), util.RandomEmail())
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	olderToken, err := store.CreatePasswordResetTx(context.Background(), user.Email)
+	require.NoError(t, err)
+	resetToken, err := store.CreatePasswordResetTx(context.Background(), user.Email)
+	require.NoError(t, err)
+	require.Equal(t, user.ID, resetToken.UserID)
+	require.False(t, resetToken.ConsumedAt.Valid)
+	require.WithinDuration(t, time.Now().Add(PasswordResetTokenDuration), resetToken.ExpiresAt.Time, time.Minute)
+
+	newPassword := util.RandomString(10)
+	hashedPassword, err := util.HashPassword(newPassword)
+	require.NoError(t, err)
+
+	// The first use sets the new password
+	err = store.ResetPasswordTx(context.Background(), ResetPasswordTxParams{
+		Token:        resetToken.Token,
+		PasswordHash: hashedPassword,
+	})
+	require.NoError(t, err)
+
+	updatedUser, err := store.GetUser(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.NoError(t, util.CheckPasswordHash(newPassword, updatedUser.PasswordHash))
+	require.Error(t, util.CheckPasswordHash(oldPassword, updatedUser.PasswordHash))
+
+	// A used token cannot be used again
+	err = store.ResetPasswordTx(context.Background(), ResetPasswordTxParams{
+		Token:        resetToken.Token,
+		PasswordHash: hashedPassword,
+	})
+	require.ErrorIs(t, err, ErrPasswordResetTokenInvalid)
+
+	// Nor can the user's other, older token
+	err = store.ResetPasswordTx(context.Background(), ResetPasswordTxParams{
+		Token:        olderToken.Token,
+		PasswordHash: hashedPassword,
+	})
+	require.ErrorIs(t, err, ErrPasswordResetTokenInvalid)
+
+	// Neither can an expired one
+	expiredToken, err := store.CreatePasswordResetToken(context.Background(), CreatePasswordResetTokenParams{
+		UserID:    user.ID,
+		Token:     util.RandomString(32),
+		ExpiresAt: pgtype.Timestamp{Time: time.Now().Add(-time.Minute), Valid: true},
+	})
+	require.NoError(t, err)
+
+	err = store.ResetPasswordTx(context.Background(), ResetPasswordTxParams{
+		Token:        expiredToken.Token,
+		PasswordHash: hashedPassword,
+	})
+	require.ErrorIs(t, err, ErrPasswordResetTokenInvalid)
+}
+
+func TestEscalateStaleTasksTx(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(testPool)
+	project := createRandomProject(t)
+	engineer, _ := createRandomUserWithRoleAndTeam(t, UserRoleEngineer, pgtype.Int8{Int64: project.TeamID, Valid: true})
+	projectID := pgtype.Int8{Int64: project.ID, Valid: true}
+	day := 24 * time.Hour
+
+	// newTask creates a task that has been waiting for age
+	newTask := func(priority TaskPriority, age time.Duration) Task {
+		task, err := testQueries.CreateTask(ctx, CreateTaskParams{
+			ProjectID: projectID,
+			Title:     util.RandomTaskTitle(),
+			Status:    TaskStatusOpen,
+			Priority:  priority,
+		})
+		require.NoError(t, err)
+		_, err = testPool.Exec(ctx, "UPDATE tasks SET created_at = NOW() - make_interval(secs => $1) WHERE id = $2", age.Seconds(), task.ID)
+		require.NoError(t, err)
+		return task
+	}
+
+	fresh := newTask(TaskPriorityLow, time.Hour)
+	weekOld := newTask(TaskPriorityLow, 8*day)
+	monthOld := newTask(TaskPriorityLow, 30*day)
+	mediumWeekOld := newTask(TaskPriorityMedium, 8*day)
+	mediumMonthOld := newTask(TaskPriorityMedium, 30*day)
+	criticalMonthOld := newTask(TaskPriorityCritical, 30*day)
+
+	overdue := newTask(TaskPriorityLow, time.Hour)
+	_, err := testPool.Exec(ctx, "UPDATE tasks SET due_date = NOW() - INTERVAL '1 hour' WHERE id = $1", overdue.ID)
+	require.NoError(t, err)
+
+	assigned := newTask(TaskPriorityLow, 30*day)
+	_, err = testQueries.UpdateTask(ctx, UpdateTaskParams{
+		ID:         assigned.ID,
+		Status:     NullTaskStatus{TaskStatus: TaskStatusInProgress, Valid: true},
+		AssigneeID: pgtype.Int8{Int64: engineer.ID, Valid: true},
+	})
+	require.NoError(t, err)
+
+	admin, _ := createRandomUserWithRoleAndNoTeam(t, UserRoleAdmin)
+	// Every run is scoped to this test's project, so other tests' tasks are left alone
+	result, err := store.EscalateStaleTasksTx(ctx, EscalateStaleTasksTxParams{
+		ActorID:   pgtype.Int8{Int64: admin.ID, Valid: true},
+		ProjectID: projectID,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Tasks, 4)
+
+	escalated := make(map[int64]EscalateStaleTasksRow)
+	for _, task := range result.Tasks {
+		escalated[task.ID] = task
+	}
+
+	expected := []struct {
+		task     Task
+		priority TaskPriority
+	}{
+		{fresh, TaskPriorityLow},
+		{weekOld, TaskPriorityMedium},
+		{monthOld, TaskPriorityHigh},
+		{mediumWeekOld, TaskPriorityMedium},
+		{mediumMonthOld, TaskPriorityHigh},
+		{criticalMonthOld, TaskPriorityCritical},
+		{overdue, TaskPriorityHigh},
+		{assigned, TaskPriorityLow},
+	}
+	for _, tc := range expected {
+		task, err := testQueries.GetTask(ctx, tc.task.ID)
+		require.NoError(t, err)
+		require.Equal(t, tc.priority, task.Priority, "task created as %s", tc.task.Priority)
+
+		row, ok := escalated[tc.task.ID]
+		require.Equal(t, tc.priority != tc.task.Priority, ok)
+		if !ok {
+			continue
+		}
+		require.Equal(t, tc.task.Priority, row.PreviousPriority)
+		require.Equal(t, tc.priority, row.Priority)
+
+		// The change is in the activity log, attributed to whoever asked for it
+		activity, err := testQueries.ListTaskActivity(ctx, tc.task.ID)
+		require.NoError(t, err)
+		require.NotEmpty(t, activity)
+		require.Equal(t, TaskActivityEventPriorityEscalated, activity[0].Event)
+		require.Equal(t, admin.ID, activity[0].ActorID.Int64)
+		require.Equal(t, tc.task.Priority, activity[0].FromPriority.TaskPriority)
+		require.Equal(t, tc.priority, activity[0].ToPriority.TaskPriority)
+	}
+
+	// Running it again leaves every task where it is
+	result, err = store.EscalateStaleTasksTx(ctx, EscalateStaleTasksTxParams{ProjectID: projectID})
+	require.NoError(t, err)
+	require.Empty(t, result.Tasks)
+
+	// Shorter thresholds escalate younger tasks
+	result, err = store.EscalateStaleTasksTx(ctx, EscalateStaleTasksTxParams{
+		MediumAfter: time.Minute,
+		HighAfter:   30 * time.Minute,
+		ProjectID:   projectID,
+	})
+	require.NoError(t, err)
+	task, err := testQueries.GetTask(ctx, fresh.ID)
+	require.NoError(t, err)
+	require.Equal(t, TaskPriorityHigh, task.Priority)
+}
+
+func TestTxTimeout(t *testing.T) {
+	store := NewStore(testPool)
+	store.SetTxTimeout(time.Nanosecond)
+
+	// The deadline has passed before the transaction can begin
+	_, err := store.ReprocessUserSkillsTx(context.Background(), ReprocessUserSkillsTxParams{UserID: 1})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// Without a limit the same transaction runs
+	store.SetTxTimeout(0)
+	user, _ := createRandomUser(t)
+	_, err = store.ReprocessUserSkillsTx(context.Background(), ReprocessUserSkillsTxParams{UserID: user.ID})
+	require.NoError(t, err)
+
+	// A negative DB_TX_TIMEOUT reaches the store unchanged and also removes the limit,
+	// rather than acting as a deadline that has already passed
+	store.SetTxTimeout(-time.Nanosecond)
+	_, err = store.ReprocessUserSkillsTx(context.Background(), ReprocessUserSkillsTxParams{UserID: user.ID})
+	require.NoError(t, err)
+}
+
+func TestResendInvitationTx(t *testing.T) {
+	store := NewStore(testPool)
+	ctx := context.Background()
+
+	// A pending invitation whose window lapsed can still be resent
+	invitation := createRandomInvitation(t)
+	_, err := testPool.Exec(ctx, "UPDATE invitations SET expires_at = NOW() - INTERVAL '1 day' WHERE id = $1", invitation.ID)
+	require.NoError(t, err)
+
+	result, err := store.ResendInvitationTx(ctx, ResendInvitationTxParams{InvitationID: invitation.ID, InviterID: invitation.InviterID})
+	require.NoError(t, err)
+	require.Equal(t, invitation.ID, result.Invitation.ID)
+	require.Equal(t, "pending", result.Invitation.Status)
+	require.NotEqual(t, invitation.InvitationToken, result.Invitation.InvitationToken)
+	require.WithinDuration(t, time.Now().Add(InvitationDuration), result.Invitation.ExpiresAt.Time, time.Minute)
+
+	// Only the new token resolves
+	_, err = store.GetInvitationByToken(ctx, invitation.InvitationToken)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	_, err = store.GetInvitationByToken(ctx, result.Invitation.InvitationToken)
+	require.NoError(t, err)
+
+	// Someone else's invitation cannot be resent
+	_, err = store.ResendInvitationTx(ctx, ResendInvitationTxParams{InvitationID: invitation.ID, InviterID: invitation.InviterID + 1})
+	require.ErrorIs(t, err, ErrPermissionDenied)
+
+	// Neither can an accepted one, or one that does not exist
+	_, err = store.UpdateInvitationStatus(ctx, UpdateInvitationStatusParams{ID: invitation.ID, Status: "accepted"})
+	require.NoError(t, err)
+	_, err = store.ResendInvitationTx(ctx, ResendInvitationTxParams{InvitationID: invitation.ID, InviterID: invitation.InviterID})
+	require.ErrorIs(t, err, ErrInvitationNotResendable)
+
+	_, err = store.ResendInvitationTx(ctx, ResendInvitationTxParams{InvitationID: -1, InviterID: invitation.InviterID})
+	require.ErrorIs(t, err, ErrInvitationNotFound)
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	store := NewStore(testPool)
+	user, _ := createRandomUser(t)
+	ctx := context.Background()
+
+	issued, err := store.IssueRefreshToken(ctx, IssueRefreshTokenParams{UserID: user.ID, Duration: time.Hour})
+	require.NoError(t, err)
+	require.NotEmpty(t, issued.Token)
+	require.Equal(t, hashRefreshToken(issued.Token), issued.RefreshToken.TokenHash)
+	require.NotEqual(t, issued.Token, issued.RefreshToken.TokenHash)
+
+	// Rotation returns the owner and a new token, and retires the old one in its favour
+	rotated, err := store.RotateRefreshTokenTx(ctx, RotateRefreshTokenTxParams{Token: issued.Token, Duration: time.Hour})
+	require.NoError(t, err)
+	require.Equal(t, user.ID, rotated.User.ID)
+	require.NotEqual(t, issued.Token, rotated.Token)
+
+	old, err := store.GetRefreshTokenByHashForUpdate(ctx, issued.RefreshToken.TokenHash)
+	require.NoError(t, err)
+	require.True(t, old.RevokedAt.Valid)
+	require.Equal(t, rotated.RefreshToken.ID, old.ReplacedBy.Int64)
+
+	// The new token rotates in turn
+	next, err := store.RotateRefreshTokenTx(ctx, RotateRefreshTokenTxParams{Token: rotated.Token, Duration: time.Hour})
+	require.NoError(t, err)
+
+	// Unknown tokens are invalid
+	_, err = store.RotateRefreshTokenTx(ctx, RotateRefreshTokenTxParams{Token: util.RandomString(26), Duration: time.Hour})
+	require.ErrorIs(t, err, ErrRefreshTokenInvalid)
+
+	// Replaying a rotated token revokes every token the user holds, including the latest
+	_, err = store.RotateRefreshTokenTx(ctx, RotateRefreshTokenTxParams{Token: issued.Token, Duration: time.Hour})
+	require.ErrorIs(t, err, ErrRefreshTokenReused)
+
+	latest, err := store.GetRefreshTokenByHashForUpdate(ctx, next.RefreshToken.TokenHash)
+	require.NoError(t, err)
+	require.True(t, latest.RevokedAt.Valid)
+
+	_, err = store.RotateRefreshTokenTx(ctx, RotateRefreshTokenTxParams{Token: next.Token, Duration: time.Hour})
+	require.ErrorIs(t, err, ErrRefreshTokenReused)
+}
+
+func TestRefreshTokenExpiryAndRevocation(t *testing.T) {
+	store := NewStore(testPool)
+	user, _ := createRandomUser(t)
+	ctx := context.Background()
+
+	// Expired tokens cannot be rotated
+	expired, err := store.IssueRefreshToken(ctx, IssueRefreshTokenParams{UserID: user.ID, Duration: -24 * time.Hour})
+	require.NoError(t, err)
+
+	_, err = store.RotateRefreshTokenTx(ctx, RotateRefreshTokenTxParams{Token: expired.Token, Duration: time.Hour})
+	require.ErrorIs(t, err, ErrRefreshTokenInvalid)
+
+	// A logged-out token is invalid, but not treated as reuse
+	issued, err := store.IssueRefreshToken(ctx, IssueRefreshTokenParams{UserID: user.ID, Duration: time.Hour})
+	require.NoError(t, err)
+	other, err := store.IssueRefreshToken(ctx, IssueRefreshTokenParams{UserID: user.ID, Duration: time.Hour})
+	require.NoError(t, err)
+
+	require.NoError(t, store.RevokeRefreshToken(ctx, issued.Token))
+	require.ErrorIs(t, store.RevokeRefreshToken(ctx, issued.Token), ErrRefreshTokenInvalid)
+
+	_, err = store.RotateRefreshTokenTx(ctx, RotateRefreshTokenTxParams{Token: issued.Token, Duration: time.Hour})
+	require.ErrorIs(t, err, ErrRefreshTokenInvalid)
+
+	// The user's other session is untouched
+	_, err = store.RotateRefreshTokenTx(ctx, RotateRefreshTokenTxParams{Token: other.Token, Duration: time.Hour})
+	require.NoError(t, err)
+}
+
+func TestBulkCreateSkillAliasesTx(t *testing.T) {
+	store := NewStore(testPool)
+	skill := createRandomSkill(t)
+	existing := createRandomSkillAlias(t)
+
+	newByID := util.RandomString(10)
+	newByName := util.RandomString(10)
+
+	result, err := store.BulkCreateSkillAliasesTx(context.Background(), BulkCreateSkillAliasesTxParams{
+		Aliases: []BulkSkillAlias{
+			{AliasName: newByID, SkillID: skill.ID},
+			{AliasName: existing.AliasName, SkillID: skill.ID},
+			{AliasName: newByName, SkillName: skill.SkillName},
+			{AliasName: util.RandomString(10), SkillName: util.RandomString(12)},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, result.Created)
+	require.Len(t, result.Results, 4)
+
+	// Results come back in request order, with the duplicate and unknown skill reported
+	require.Equal(t, BulkAliasCreated, result.Results[0].Status)
+	require.Equal(t, BulkAliasDuplicate, result.Results[1].Status)
+	require.Equal(t, BulkAliasCreated, result.Results[2].Status)
+	require.Equal(t, skill.ID, result.Results[2].SkillID)
+	require.Equal(t, BulkAliasSkillNotFound, result.Results[3].Status)
+
+	// The new aliases were committed and the duplicate kept its original skill
+	for _, name := range []string{newByID, newByName} {
+		alias, err := testQueries.GetSkillAlias(context.Background(), name)
+		require.NoError(t, err)
+		require.Equal(t, skill.ID, alias.SkillID)
+	}
+	unchanged, err := testQueries.GetSkillAlias(context.Background(), existing.AliasName)
+	require.NoError(t, err)
+	require.Equal(t, existing.SkillID, unchanged.SkillID)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+//                               TEST HELPERS
+////////////////////////////////////////////////////////////////////////////////
+
+// createRandomTaskLocal creates a task in a given project with random values.
+func createRandomTaskLocal(t *testing.T, projectID int64) Task {
+	arg := CreateTaskParams{
+		ProjectID: pgtype.Int8{Int64: projectID, Valid: true},
+		Title:     util.RandomTaskTitle(),
+		Description: pgtype.Text{
+			String: util.RandomTaskDescription(),
+			Valid:  true,
+		},
+		Status:     TaskStatusOpen,
+		Priority:   TaskPriority(util.RandomPriority()),
+		AssigneeID: pgtype.Int8{Valid: false},
+	}
+
+	task, err := testQueries.CreateTask(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, task)
+
+	require.Equal(t, arg.ProjectID.Int64, task.ProjectID.Int64)
+	require.False(t, task.AssigneeID.Valid)
+	require.Equal(t, arg.Title, task.Title)
+
+	return task
+}
+
+// createRandomUserWithRole creates a user with a given role and sets it explicitly.
+func createRandomUserWithRole(t *testing.T, role UserRole) (User, string) {
+	user, password := createRandomUser(t)
+	updatedUser, err := testQueries.UpdateUser(context.Background(), UpdateUserParams{
+		ID:   user.ID,
+		Role: NullUserRole{UserRole: role, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, role, updatedUser.Role)
+	return updatedUser, password
+}
+
+// createRandomUserWithRoleAndNoTeam creates a user with a role and ensures they are not assigned to any team.
+func createRandomUserWithRoleAndNoTeam(t *testing.T, role UserRole) (User, string) {
+	user, password := createRandomUserWithRole(t, role)
+
+	// Explicitly remove user from team to ensure team_id is NULL.
+	unassignedUser, err := testQueries.RemoveUserFromTeam(context.Background(), user.ID)
+	require.NoError(t, err)
+
+	require.False(t, unassignedUser.TeamID.Valid, "User should not have a team ID")
+	require.Equal(t, role, unassignedUser.Role)
+
+	return unassignedUser, password
+}
+
+// createRandomUserWithRoleAndTeam creates a user with a role and moves them onto the given team.
+func createRandomUserWithRoleAndTeam(t *testing.T, role UserRole, teamID pgtype.Int8) (User, string) {
+	user, password := createRandomUserWithRole(t, role)
+
+	movedUser, err := testQueries.UpdateUser(context.Background(), UpdateUserParams{
+		ID:     user.ID,
+		TeamID: teamID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, teamID.Int64, movedUser.TeamID.Int64)
+
+	return movedUser, password
+}
+
+// createRandomManagerWithTeam sets up a team and assigns a manager to it.
+// It updates both the user and the team to ensure referential integrity.
+func createRandomManagerWithTeam(t *testing.T) (User, Team) {
+	team := createRandomTeam(t)
+
+	// Create a user with manager role.
+	manager, _ := createRandomUserWithRole(t, UserRoleManager)
+
+	// Assign manager to team by updating the user's team_id.
+	updatedManager, err := testQueries.UpdateUser(context.Background(), UpdateUserParams{
+		ID:     manager.ID,
+		TeamID: pgtype.Int8{Int64: team.ID, Valid: true},
+	})
+	require.NoError(t, err)
+	require.True(t, updatedManager.TeamID.Valid)
+	require.Equal(t, team.ID, updatedManager.TeamID.Int64)
+
+	// Set team's manager_id to the user.
+	updatedTeam, err := testQueries.UpdateTeam(context.Background(), UpdateTeamParams{
+		ID:        team.ID,
+		ManagerID: pgtype.Int8{Int64: updatedManager.ID, Valid: true},
+	})
+	require.NoError(t, err)
+	require.True(t, updatedTeam.ManagerID.Valid)
+	require.Equal(t, updatedManager.ID, updatedTeam.ManagerID.Int64)
+
+	return updatedManager, updatedTeam
+}
+
+func TestResolveSkillsIgnoresCase(t *testing.T) {
+	store := NewStore(testPool)
+	suffix := util.RandomString(6)
+	stored := "Node.js " + suffix
+
+	// The first resolution creates the skill with the casing it was given
+	created, err := store._resolveSkills(context.Background(), store.Queries, []string{stored})
+	require.NoError(t, err)
+	require.Contains(t, created, stored)
+
+	// Other casings find the same row instead of creating a duplicate
+	lower := strings.ToLower(stored)
+	resolved, err := store._resolveSkills(context.Background(), store.Queries, []string{lower})
+	require.NoError(t, err)
+	require.Equal(t, created[stored].ID, resolved[lower].ID)
+	require.Equal(t, stored, resolved[lower].SkillName)
+
+	// Names differing only in case within one call resolve to a single skill
+	fresh := "Deno " + suffix
+	resolved, err = store._resolveSkills(context.Background(), store.Queries, []string{fresh, strings.ToUpper(fresh)})
+	require.NoError(t, err)
+	require.Len(t, resolved, 1)
+	require.Equal(t, fresh, resolved[fresh].SkillName)
+
+	known, candidates, err := store._resolveKnownSkills(context.Background(), store.Queries, []string{strings.ToUpper(stored)})
+	require.NoError(t, err)
+	require.Empty(t, candidates)
+	require.Equal(t, created[stored].ID, known[strings.ToUpper(stored)].ID)
 }
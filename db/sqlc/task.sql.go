@@ -13,7 +13,7 @@ import (
 
 const archiveCompletedTasksByProject = `-- name: ArchiveCompletedTasksByProject :exec
 UPDATE tasks
-SET archived = true, archived_at = now()
+SET archived = true, archived_at = now(), version = version + 1
 WHERE project_id = $1 AND status = 'done' AND archived = false
 `
 
@@ -25,9 +25,9 @@ func (q *Queries) ArchiveCompletedTasksByProject(ctx context.Context, projectID
 
 const archiveTask = `-- name: ArchiveTask :one
 UPDATE tasks
-SET archived = true, archived_at = now()  
+SET archived = true, archived_at = now(), version = version + 1
 WHERE id = $1 AND archived = false
-RETURNING id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at
+RETURNING id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at, due_date, version
 `
 
 // Archive a single active task by ID and return its details
@@ -46,10 +46,84 @@ func (q *Queries) ArchiveTask(ctx context.Context, id int64) (Task, error) {
 		&i.CompletedAt,
 		&i.Archived,
 		&i.ArchivedAt,
+		&i.DueDate,
+		&i.Version,
 	)
 	return i, err
 }
 
+const claimTask = `-- name: ClaimTask :one
+UPDATE tasks
+SET assignee_id = $2, status = 'in_progress', version = version + 1
+WHERE id = $1 AND status = 'open' AND assignee_id IS NULL AND archived = false
+    AND project_id IN (SELECT id FROM projects WHERE team_id = $3)
+RETURNING id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at, due_date, version
+`
+
+type ClaimTaskParams struct {
+	ID         int64       `json:"id"`
+	AssigneeID pgtype.Int8 `json:"assignee_id"`
+	TeamID     int64       `json:"team_id"`
+}
+
+// Assigns an open, unassigned task to the given user, only if its project belongs to the given team.
+func (q *Queries) ClaimTask(ctx context.Context, arg ClaimTaskParams) (Task, error) {
+	row := q.db.QueryRow(ctx, claimTask, arg.ID, arg.AssigneeID, arg.TeamID)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Title,
+		&i.Description,
+		&i.Status,
+		&i.Priority,
+		&i.AssigneeID,
+		&i.CreatedAt,
+		&i.CompletedAt,
+		&i.Archived,
+		&i.ArchivedAt,
+		&i.DueDate,
+		&i.Version,
+	)
+	return i, err
+}
+
+const countActiveTasksByAssignees = `-- name: CountActiveTasksByAssignees :many
+SELECT
+    assignee_id,
+    count(*) AS task_count
+FROM tasks
+WHERE assignee_id = ANY($1::bigint[]) AND status <> 'done' AND archived = false
+GROUP BY assignee_id
+`
+
+type CountActiveTasksByAssigneesRow struct {
+	AssigneeID pgtype.Int8 `json:"assignee_id"`
+	TaskCount  int64       `json:"task_count"`
+}
+
+// Count each user's unfinished (open or in-progress, non-archived) tasks, for a batch of users at once
+// Users without unfinished tasks are absent from the result
+func (q *Queries) CountActiveTasksByAssignees(ctx context.Context, assigneeIds []int64) ([]CountActiveTasksByAssigneesRow, error) {
+	rows, err := q.db.Query(ctx, countActiveTasksByAssignees, assigneeIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountActiveTasksByAssigneesRow
+	for rows.Next() {
+		var i CountActiveTasksByAssigneesRow
+		if err := rows.Scan(&i.AssigneeID, &i.TaskCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const countActiveTasksByProject = `-- name: CountActiveTasksByProject :one
 SELECT count(*) FROM tasks
 WHERE project_id = $1 AND archived = false
@@ -76,6 +150,81 @@ func (q *Queries) CountArchivedTasksByProject(ctx context.Context, projectID pgt
 	return count, err
 }
 
+const countInProgressTasksByAssignee = `-- name: CountInProgressTasksByAssignee :one
+SELECT count(*) FROM tasks
+WHERE assignee_id = $1 AND status = 'in_progress' AND archived = false
+`
+
+// Counts a user's active in-progress tasks, which decides whether they are busy.
+func (q *Queries) CountInProgressTasksByAssignee(ctx context.Context, assigneeID pgtype.Int8) (int64, error) {
+	row := q.db.QueryRow(ctx, countInProgressTasksByAssignee, assigneeID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countOverdueTasksByTeam = `-- name: CountOverdueTasksByTeam :one
+SELECT count(*) FROM tasks t
+JOIN projects p ON t.project_id = p.id
+WHERE p.team_id = $1
+  AND t.status <> 'done'
+  AND t.archived = false
+  AND t.due_date < (now() AT TIME ZONE 'UTC')
+`
+
+// Counts the team's live tasks that are past their due date and not done.
+func (q *Queries) CountOverdueTasksByTeam(ctx context.Context, teamID int64) (int64, error) {
+	row := q.db.QueryRow(ctx, countOverdueTasksByTeam, teamID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSearchTasksByTeam = `-- name: CountSearchTasksByTeam :one
+SELECT count(*)
+FROM
+    tasks t
+JOIN
+    projects p ON t.project_id = p.id
+WHERE
+    p.team_id = $1
+    AND (
+        $2::text = ''
+        OR to_tsvector('english', t.title || ' ' || COALESCE(t.description, '')) @@ plainto_tsquery('english', $2)
+        OR t.title ILIKE $3 ESCAPE '\'
+    )
+    AND ($4::task_status IS NULL OR t.status = $4)
+    AND ($5::task_priority IS NULL OR t.priority = $5)
+    AND ($6::bigint IS NULL OR t.assignee_id = $6)
+    AND t.archived = $7
+`
+
+type CountSearchTasksByTeamParams struct {
+	TeamID       int64            `json:"team_id"`
+	Query        string           `json:"query"`
+	TitlePattern string           `json:"title_pattern"`
+	Status       NullTaskStatus   `json:"status"`
+	Priority     NullTaskPriority `json:"priority"`
+	AssigneeID   pgtype.Int8      `json:"assignee_id"`
+	Archived     bool             `json:"archived"`
+}
+
+// Counts the tasks SearchTasksByTeam matches, for pagination.
+func (q *Queries) CountSearchTasksByTeam(ctx context.Context, arg CountSearchTasksByTeamParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countSearchTasksByTeam,
+		arg.TeamID,
+		arg.Query,
+		arg.TitlePattern,
+		arg.Status,
+		arg.Priority,
+		arg.AssigneeID,
+		arg.Archived,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countTasksByProjectAndStatus = `-- name: CountTasksByProjectAndStatus :one
 SELECT count(*) FROM tasks 
 WHERE project_id = $1 AND status = $2 AND archived = false
@@ -94,6 +243,125 @@ func (q *Queries) CountTasksByProjectAndStatus(ctx context.Context, arg CountTas
 	return count, err
 }
 
+const countTasksByProjectAssignee = `-- name: CountTasksByProjectAssignee :many
+SELECT
+    t.assignee_id,
+    u.name AS assignee_name,
+    count(*) AS task_count,
+    count(*) FILTER (WHERE t.status = 'done') AS done_count
+FROM tasks t
+LEFT JOIN users u ON u.id = t.assignee_id
+WHERE t.project_id = $1 AND t.archived = false
+GROUP BY t.assignee_id, u.name
+ORDER BY task_count DESC, u.name
+`
+
+type CountTasksByProjectAssigneeRow struct {
+	AssigneeID   pgtype.Int8 `json:"assignee_id"`
+	AssigneeName pgtype.Text `json:"assignee_name"`
+	TaskCount    int64       `json:"task_count"`
+	DoneCount    int64       `json:"done_count"`
+}
+
+// Count a project's active (non-archived) tasks per assignee, and how many of them are done
+// Unassigned tasks form one row with a NULL assignee
+func (q *Queries) CountTasksByProjectAssignee(ctx context.Context, projectID pgtype.Int8) ([]CountTasksByProjectAssigneeRow, error) {
+	rows, err := q.db.Query(ctx, countTasksByProjectAssignee, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountTasksByProjectAssigneeRow
+	for rows.Next() {
+		var i CountTasksByProjectAssigneeRow
+		if err := rows.Scan(
+			&i.AssigneeID,
+			&i.AssigneeName,
+			&i.TaskCount,
+			&i.DoneCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countTasksByProjectIDs = `-- name: CountTasksByProjectIDs :many
+SELECT
+    project_id,
+    count(*) AS total_count,
+    count(*) FILTER (WHERE status = 'done') AS completed_count
+FROM tasks
+WHERE project_id = ANY($1::bigint[]) AND archived = false
+GROUP BY project_id
+`
+
+type CountTasksByProjectIDsRow struct {
+	ProjectID      pgtype.Int8 `json:"project_id"`
+	TotalCount     int64       `json:"total_count"`
+	CompletedCount int64       `json:"completed_count"`
+}
+
+// Count active (non-archived) tasks and how many of them are done, for a batch of projects at once
+// Projects without active tasks are absent from the result
+func (q *Queries) CountTasksByProjectIDs(ctx context.Context, projectIds []int64) ([]CountTasksByProjectIDsRow, error) {
+	rows, err := q.db.Query(ctx, countTasksByProjectIDs, projectIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountTasksByProjectIDsRow
+	for rows.Next() {
+		var i CountTasksByProjectIDsRow
+		if err := rows.Scan(&i.ProjectID, &i.TotalCount, &i.CompletedCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countTasksByProjectStatusAndPriority = `-- name: CountTasksByProjectStatusAndPriority :many
+SELECT status, priority, count(*) AS task_count
+FROM tasks
+WHERE project_id = $1 AND archived = false
+GROUP BY status, priority
+`
+
+type CountTasksByProjectStatusAndPriorityRow struct {
+	Status    TaskStatus   `json:"status"`
+	Priority  TaskPriority `json:"priority"`
+	TaskCount int64        `json:"task_count"`
+}
+
+// Count a project's active (non-archived) tasks for each combination of status and priority
+func (q *Queries) CountTasksByProjectStatusAndPriority(ctx context.Context, projectID pgtype.Int8) ([]CountTasksByProjectStatusAndPriorityRow, error) {
+	rows, err := q.db.Query(ctx, countTasksByProjectStatusAndPriority, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountTasksByProjectStatusAndPriorityRow
+	for rows.Next() {
+		var i CountTasksByProjectStatusAndPriorityRow
+		if err := rows.Scan(&i.Status, &i.Priority, &i.TaskCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createTask = `-- name: CreateTask :one
 
 INSERT INTO tasks (
@@ -102,19 +370,21 @@ INSERT INTO tasks (
     description,
     status,
     priority,
-    assignee_id
+    assignee_id,
+    due_date
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
-) RETURNING id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at, due_date, version
 `
 
 type CreateTaskParams struct {
-	ProjectID   pgtype.Int8  `json:"project_id"`
-	Title       string       `json:"title"`
-	Description pgtype.Text  `json:"description"`
-	Status      TaskStatus   `json:"status"`
-	Priority    TaskPriority `json:"priority"`
-	AssigneeID  pgtype.Int8  `json:"assignee_id"`
+	ProjectID   pgtype.Int8      `json:"project_id"`
+	Title       string           `json:"title"`
+	Description pgtype.Text      `json:"description"`
+	Status      TaskStatus       `json:"status"`
+	Priority    TaskPriority     `json:"priority"`
+	AssigneeID  pgtype.Int8      `json:"assignee_id"`
+	DueDate     pgtype.Timestamp `json:"due_date"`
 }
 
 // SQLC-formatted queries for the "tasks" table.
@@ -128,6 +398,7 @@ func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) (Task, e
 		arg.Status,
 		arg.Priority,
 		arg.AssigneeID,
+		arg.DueDate,
 	)
 	var i Task
 	err := row.Scan(
@@ -142,6 +413,8 @@ func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) (Task, e
 		&i.CompletedAt,
 		&i.Archived,
 		&i.ArchivedAt,
+		&i.DueDate,
+		&i.Version,
 	)
 	return i, err
 }
@@ -157,6 +430,94 @@ func (q *Queries) DeleteTask(ctx context.Context, id int64) error {
 	return err
 }
 
+const escalateStaleTasks = `-- name: EscalateStaleTasks :many
+UPDATE tasks t
+SET priority = CASE
+        WHEN previous.created_at <= now() - make_interval(secs => $1::float8)
+          OR previous.due_date < (now() AT TIME ZONE 'UTC') THEN 'high'::task_priority
+        ELSE 'medium'::task_priority
+    END,
+    version = t.version + 1
+FROM tasks previous
+WHERE t.id = previous.id
+  AND previous.status = 'open'
+  AND previous.assignee_id IS NULL
+  AND previous.archived = false
+  AND (
+      (previous.priority = 'low'
+        AND previous.created_at <= now() - make_interval(secs => $2::float8))
+      OR (previous.priority IN ('low', 'medium')
+        AND (previous.created_at <= now() - make_interval(secs => $1::float8)
+          OR previous.due_date < (now() AT TIME ZONE 'UTC')))
+  )
+  AND ($3::bigint IS NULL OR previous.project_id = $3)
+RETURNING t.id, t.project_id, t.title, t.description, t.status, t.priority, t.assignee_id, t.created_at, t.completed_at, t.archived, t.archived_at, t.due_date, t.version, previous.priority AS previous_priority
+`
+
+type EscalateStaleTasksParams struct {
+	HighAfterSeconds   float64     `json:"high_after_seconds"`
+	MediumAfterSeconds float64     `json:"medium_after_seconds"`
+	ProjectID          pgtype.Int8 `json:"project_id"`
+}
+
+type EscalateStaleTasksRow struct {
+	ID               int64            `json:"id"`
+	ProjectID        pgtype.Int8      `json:"project_id"`
+	Title            string           `json:"title"`
+	Description      pgtype.Text      `json:"description"`
+	Status           TaskStatus       `json:"status"`
+	Priority         TaskPriority     `json:"priority"`
+	AssigneeID       pgtype.Int8      `json:"assignee_id"`
+	CreatedAt        pgtype.Timestamp `json:"created_at"`
+	CompletedAt      pgtype.Timestamp `json:"completed_at"`
+	Archived         bool             `json:"archived"`
+	ArchivedAt       pgtype.Timestamp `json:"archived_at"`
+	DueDate          pgtype.Timestamp `json:"due_date"`
+	Version          int32            `json:"version"`
+	PreviousPriority TaskPriority     `json:"previous_priority"`
+}
+
+// Raises the priority of live, open, unassigned tasks that have waited too long: low ones to 'medium'
+// once older than medium_after_seconds, and low or medium ones to 'high' once older than
+// high_after_seconds or past their due date. High and critical tasks are never touched, and a task
+// is only raised once per threshold, so running it again changes nothing. A NULL project_id covers
+// every project.
+// Each row also carries the priority the task had before, read from the pre-update snapshot.
+func (q *Queries) EscalateStaleTasks(ctx context.Context, arg EscalateStaleTasksParams) ([]EscalateStaleTasksRow, error) {
+	rows, err := q.db.Query(ctx, escalateStaleTasks, arg.HighAfterSeconds, arg.MediumAfterSeconds, arg.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []EscalateStaleTasksRow
+	for rows.Next() {
+		var i EscalateStaleTasksRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.Priority,
+			&i.AssigneeID,
+			&i.CreatedAt,
+			&i.CompletedAt,
+			&i.Archived,
+			&i.ArchivedAt,
+			&i.DueDate,
+			&i.Version,
+			&i.PreviousPriority,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getAssignedEngineersForProject = `-- name: GetAssignedEngineersForProject :many
 SELECT DISTINCT t.assignee_id
 FROM tasks t
@@ -186,6 +547,20 @@ func (q *Queries) GetAssignedEngineersForProject(ctx context.Context, projectID
 	return items, nil
 }
 
+const getAverageCompletionTimeByProject = `-- name: GetAverageCompletionTimeByProject :one
+SELECT COALESCE(EXTRACT(EPOCH FROM AVG(completed_at - created_at)), 0)::float8 AS avg_completion_seconds
+FROM tasks
+WHERE project_id = $1 AND status = 'done' AND completed_at IS NOT NULL AND archived = false
+`
+
+// Average time from creation to completion of a project's active done tasks, in seconds; 0 when none are done
+func (q *Queries) GetAverageCompletionTimeByProject(ctx context.Context, projectID pgtype.Int8) (float64, error) {
+	row := q.db.QueryRow(ctx, getAverageCompletionTimeByProject, projectID)
+	var avg_completion_seconds float64
+	err := row.Scan(&avg_completion_seconds)
+	return avg_completion_seconds, err
+}
+
 const getCurrentTaskForEngineer = `-- name: GetCurrentTaskForEngineer :one
 SELECT
     t.id,
@@ -241,6 +616,8 @@ WHERE
     AND t.status = 'done'
     AND t.archived = false
     AND t.title ILIKE $4 -- Use sqlc.arg for the optional search parameter
+    AND ($5::timestamp IS NULL OR t.completed_at >= $5)
+    AND ($6::timestamp IS NULL OR t.completed_at <= $6)
 ORDER BY
     t.completed_at DESC
 LIMIT $2
@@ -248,10 +625,12 @@ OFFSET $3
 `
 
 type GetEngineerTaskHistoryParams struct {
-	AssigneeID pgtype.Int8 `json:"assignee_id"`
-	Limit      int32       `json:"limit"`
-	Offset     int32       `json:"offset"`
-	Search     string      `json:"search"`
+	AssigneeID    pgtype.Int8      `json:"assignee_id"`
+	Limit         int32            `json:"limit"`
+	Offset        int32            `json:"offset"`
+	Search        string           `json:"search"`
+	CompletedFrom pgtype.Timestamp `json:"completed_from"`
+	CompletedTo   pgtype.Timestamp `json:"completed_to"`
 }
 
 type GetEngineerTaskHistoryRow struct {
@@ -268,6 +647,8 @@ func (q *Queries) GetEngineerTaskHistory(ctx context.Context, arg GetEngineerTas
 		arg.Limit,
 		arg.Offset,
 		arg.Search,
+		arg.CompletedFrom,
+		arg.CompletedTo,
 	)
 	if err != nil {
 		return nil, err
@@ -303,22 +684,31 @@ WHERE
     AND status = 'done'
     AND archived = false
     AND title ILIKE $2
+    AND ($3::timestamp IS NULL OR completed_at >= $3)
+    AND ($4::timestamp IS NULL OR completed_at <= $4)
 `
 
 type GetEngineerTaskHistoryCountParams struct {
-	AssigneeID pgtype.Int8 `json:"assignee_id"`
-	Search     string      `json:"search"`
+	AssigneeID    pgtype.Int8      `json:"assignee_id"`
+	Search        string           `json:"search"`
+	CompletedFrom pgtype.Timestamp `json:"completed_from"`
+	CompletedTo   pgtype.Timestamp `json:"completed_to"`
 }
 
 func (q *Queries) GetEngineerTaskHistoryCount(ctx context.Context, arg GetEngineerTaskHistoryCountParams) (int64, error) {
-	row := q.db.QueryRow(ctx, getEngineerTaskHistoryCount, arg.AssigneeID, arg.Search)
+	row := q.db.QueryRow(ctx, getEngineerTaskHistoryCount,
+		arg.AssigneeID,
+		arg.Search,
+		arg.CompletedFrom,
+		arg.CompletedTo,
+	)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
 const getTask = `-- name: GetTask :one
-SELECT id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at FROM tasks
+SELECT id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at, due_date, version FROM tasks
 WHERE id = $1 LIMIT 1
 `
 
@@ -338,13 +728,15 @@ func (q *Queries) GetTask(ctx context.Context, id int64) (Task, error) {
 		&i.CompletedAt,
 		&i.Archived,
 		&i.ArchivedAt,
+		&i.DueDate,
+		&i.Version,
 	)
 	return i, err
 }
 
 const getTaskDetailsWithProject = `-- name: GetTaskDetailsWithProject :one
 SELECT
-    t.id, t.project_id, t.title, t.description, t.status, t.priority, t.assignee_id, t.created_at, t.completed_at, t.archived, t.archived_at,
+    t.id, t.project_id, t.title, t.description, t.status, t.priority, t.assignee_id, t.created_at, t.completed_at, t.archived, t.archived_at, t.due_date, t.version,
     p.project_name
 FROM
     tasks t
@@ -366,6 +758,8 @@ type GetTaskDetailsWithProjectRow struct {
 	CompletedAt pgtype.Timestamp `json:"completed_at"`
 	Archived    bool             `json:"archived"`
 	ArchivedAt  pgtype.Timestamp `json:"archived_at"`
+	DueDate     pgtype.Timestamp `json:"due_date"`
+	Version     int32            `json:"version"`
 	ProjectName string           `json:"project_name"`
 }
 
@@ -385,13 +779,43 @@ func (q *Queries) GetTaskDetailsWithProject(ctx context.Context, id int64) (GetT
 		&i.CompletedAt,
 		&i.Archived,
 		&i.ArchivedAt,
+		&i.DueDate,
+		&i.Version,
 		&i.ProjectName,
 	)
 	return i, err
 }
 
+const getTaskForUpdate = `-- name: GetTaskForUpdate :one
+SELECT id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at, due_date, version FROM tasks
+WHERE id = $1
+FOR UPDATE
+`
+
+// Retrieves a task and locks it until the transaction ends, so concurrent edits of it run one at a time.
+func (q *Queries) GetTaskForUpdate(ctx context.Context, id int64) (Task, error) {
+	row := q.db.QueryRow(ctx, getTaskForUpdate, id)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Title,
+		&i.Description,
+		&i.Status,
+		&i.Priority,
+		&i.AssigneeID,
+		&i.CreatedAt,
+		&i.CompletedAt,
+		&i.Archived,
+		&i.ArchivedAt,
+		&i.DueDate,
+		&i.Version,
+	)
+	return i, err
+}
+
 const listActiveTasksByProject = `-- name: ListActiveTasksByProject :many
-SELECT id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at
+SELECT id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at, due_date, version
 FROM tasks
 WHERE project_id = $1 AND archived = false
 ORDER BY created_at DESC
@@ -426,6 +850,8 @@ func (q *Queries) ListActiveTasksByProject(ctx context.Context, arg ListActiveTa
 			&i.CompletedAt,
 			&i.Archived,
 			&i.ArchivedAt,
+			&i.DueDate,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -438,7 +864,7 @@ func (q *Queries) ListActiveTasksByProject(ctx context.Context, arg ListActiveTa
 }
 
 const listArchivedTasksByProject = `-- name: ListArchivedTasksByProject :many
-SELECT id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at
+SELECT id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at, due_date, version
 FROM tasks
 WHERE project_id = $1 AND archived = true
 ORDER BY archived_at DESC  
@@ -473,6 +899,8 @@ func (q *Queries) ListArchivedTasksByProject(ctx context.Context, arg ListArchiv
 			&i.CompletedAt,
 			&i.Archived,
 			&i.ArchivedAt,
+			&i.DueDate,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -484,40 +912,322 @@ func (q *Queries) ListArchivedTasksByProject(ctx context.Context, arg ListArchiv
 	return items, nil
 }
 
-const listTasks = `-- name: ListTasks :many
-SELECT id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at FROM tasks
-ORDER BY created_at DESC
-LIMIT $1
-OFFSET $2
+const listMatchingOpenTasksForEngineer = `-- name: ListMatchingOpenTasksForEngineer :many
+SELECT
+    t.id,
+    t.title,
+    t.priority,
+    p.id AS project_id,
+    p.project_name,
+    count(*) AS matching_skill_count,
+    SUM(
+        CASE us.proficiency
+            WHEN 'expert' THEN 3
+            WHEN 'intermediate' THEN 2
+            ELSE 1
+        END
+    )::bigint AS proficiency_score
+FROM
+    tasks t
+JOIN
+    projects p ON t.project_id = p.id
+JOIN
+    task_required_skills trs ON trs.task_id = t.id
+JOIN
+    user_skills us ON us.skill_id = trs.skill_id AND us.user_id = $1
+WHERE
+    p.team_id = $2
+    AND p.archived = false
+    AND t.status = 'open'
+    AND t.assignee_id IS NULL
+    AND t.archived = false
+GROUP BY
+    t.id, p.id
+ORDER BY
+    matching_skill_count DESC,
+    proficiency_score DESC,
+    t.id
+LIMIT $3
 `
 
-type ListTasksParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+type ListMatchingOpenTasksForEngineerParams struct {
+	EngineerID  int64 `json:"engineer_id"`
+	TeamID      int64 `json:"team_id"`
+	ResultLimit int32 `json:"result_limit"`
 }
 
-// Retrieves a paginated list of all tasks, ordered by creation date.
-func (q *Queries) ListTasks(ctx context.Context, arg ListTasksParams) ([]Task, error) {
-	rows, err := q.db.Query(ctx, listTasks, arg.Limit, arg.Offset)
+type ListMatchingOpenTasksForEngineerRow struct {
+	ID                 int64        `json:"id"`
+	Title              string       `json:"title"`
+	Priority           TaskPriority `json:"priority"`
+	ProjectID          int64        `json:"project_id"`
+	ProjectName        string       `json:"project_name"`
+	MatchingSkillCount int64        `json:"matching_skill_count"`
+	ProficiencyScore   int64        `json:"proficiency_score"`
+}
+
+// Lists open, unassigned tasks on a team whose required skills overlap an engineer's skills.
+// Tasks are ranked by the number of overlapping skills, then by the engineer's proficiency in them.
+func (q *Queries) ListMatchingOpenTasksForEngineer(ctx context.Context, arg ListMatchingOpenTasksForEngineerParams) ([]ListMatchingOpenTasksForEngineerRow, error) {
+	rows, err := q.db.Query(ctx, listMatchingOpenTasksForEngineer, arg.EngineerID, arg.TeamID, arg.ResultLimit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Task
+	var items []ListMatchingOpenTasksForEngineerRow
 	for rows.Next() {
-		var i Task
+		var i ListMatchingOpenTasksForEngineerRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.ProjectID,
 			&i.Title,
-			&i.Description,
-			&i.Status,
 			&i.Priority,
-			&i.AssigneeID,
-			&i.CreatedAt,
+			&i.ProjectID,
+			&i.ProjectName,
+			&i.MatchingSkillCount,
+			&i.ProficiencyScore,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOverdueTasksByTeam = `-- name: ListOverdueTasksByTeam :many
+SELECT t.id, t.project_id, t.title, t.description, t.status, t.priority, t.assignee_id, t.created_at, t.completed_at, t.archived, t.archived_at, t.due_date, t.version FROM tasks t
+JOIN projects p ON t.project_id = p.id
+WHERE p.team_id = $1
+  AND t.status <> 'done'
+  AND t.archived = false
+  AND t.due_date < (now() AT TIME ZONE 'UTC')
+ORDER BY t.due_date, t.id
+LIMIT $2
+OFFSET $3
+`
+
+type ListOverdueTasksByTeamParams struct {
+	TeamID int64 `json:"team_id"`
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+// Lists a page of the team's live tasks that are past their due date and not done, most overdue first.
+// Tasks without a due date have no deadline and are never overdue.
+func (q *Queries) ListOverdueTasksByTeam(ctx context.Context, arg ListOverdueTasksByTeamParams) ([]Task, error) {
+	rows, err := q.db.Query(ctx, listOverdueTasksByTeam, arg.TeamID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Task
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.Priority,
+			&i.AssigneeID,
+			&i.CreatedAt,
+			&i.CompletedAt,
+			&i.Archived,
+			&i.ArchivedAt,
+			&i.DueDate,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecentTasksByTeam = `-- name: ListRecentTasksByTeam :many
+SELECT
+    t.id,
+    t.title,
+    t.status,
+    t.assignee_id,
+    u.name AS assignee_name,
+    p.project_name,
+    t.created_at,
+    t.completed_at
+FROM
+    tasks t
+JOIN
+    projects p ON t.project_id = p.id
+LEFT JOIN
+    users u ON t.assignee_id = u.id
+WHERE
+    p.team_id = $1
+    AND t.archived = false
+ORDER BY
+    COALESCE(t.completed_at, t.created_at) DESC
+LIMIT $2
+`
+
+type ListRecentTasksByTeamParams struct {
+	TeamID int64 `json:"team_id"`
+	Limit  int32 `json:"limit"`
+}
+
+type ListRecentTasksByTeamRow struct {
+	ID           int64            `json:"id"`
+	Title        string           `json:"title"`
+	Status       TaskStatus       `json:"status"`
+	AssigneeID   pgtype.Int8      `json:"assignee_id"`
+	AssigneeName pgtype.Text      `json:"assignee_name"`
+	ProjectName  string           `json:"project_name"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	CompletedAt  pgtype.Timestamp `json:"completed_at"`
+}
+
+// Lists the most recently created or completed active tasks across all of a team's projects
+func (q *Queries) ListRecentTasksByTeam(ctx context.Context, arg ListRecentTasksByTeamParams) ([]ListRecentTasksByTeamRow, error) {
+	rows, err := q.db.Query(ctx, listRecentTasksByTeam, arg.TeamID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListRecentTasksByTeamRow
+	for rows.Next() {
+		var i ListRecentTasksByTeamRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Status,
+			&i.AssigneeID,
+			&i.AssigneeName,
+			&i.ProjectName,
+			&i.CreatedAt,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTaskTrendsByTeam = `-- name: ListTaskTrendsByTeam :many
+WITH window_days AS (
+    SELECT generate_series(
+        date_trunc('day', now() AT TIME ZONE 'UTC') - make_interval(days => $1::int - 1),
+        date_trunc('day', now() AT TIME ZONE 'UTC'),
+        interval '1 day'
+    )::timestamp AS day
+),
+team_tasks AS (
+    -- The columns hold the database session's local time, so they are moved to UTC like the window
+    SELECT
+        (t.created_at AT TIME ZONE current_setting('TimeZone')) AT TIME ZONE 'UTC' AS created_at,
+        (t.completed_at AT TIME ZONE current_setting('TimeZone')) AT TIME ZONE 'UTC' AS completed_at
+    FROM tasks t
+    JOIN projects p ON t.project_id = p.id
+    WHERE p.team_id = $2
+),
+created AS (
+    SELECT date_trunc('day', created_at) AS day, count(*) AS task_count
+    FROM team_tasks
+    WHERE created_at >= (SELECT min(day) FROM window_days)
+    GROUP BY 1
+),
+completed AS (
+    SELECT date_trunc('day', completed_at) AS day, count(*) AS task_count
+    FROM team_tasks
+    WHERE completed_at >= (SELECT min(day) FROM window_days)
+    GROUP BY 1
+)
+SELECT
+    w.day,
+    COALESCE(c.task_count, 0)::bigint AS created_count,
+    COALESCE(d.task_count, 0)::bigint AS completed_count
+FROM window_days w
+LEFT JOIN created c ON c.day = w.day
+LEFT JOIN completed d ON d.day = w.day
+ORDER BY w.day
+`
+
+type ListTaskTrendsByTeamParams struct {
+	Days   int32 `json:"days"`
+	TeamID int64 `json:"team_id"`
+}
+
+type ListTaskTrendsByTeamRow struct {
+	Day            pgtype.Timestamp `json:"day"`
+	CreatedCount   int64            `json:"created_count"`
+	CompletedCount int64            `json:"completed_count"`
+}
+
+// Daily counts of the team's tasks created and completed over the last N days (UTC), oldest first.
+// Every day in the window has a row, so charts need no gap filling; archived tasks still count.
+func (q *Queries) ListTaskTrendsByTeam(ctx context.Context, arg ListTaskTrendsByTeamParams) ([]ListTaskTrendsByTeamRow, error) {
+	rows, err := q.db.Query(ctx, listTaskTrendsByTeam, arg.Days, arg.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTaskTrendsByTeamRow
+	for rows.Next() {
+		var i ListTaskTrendsByTeamRow
+		if err := rows.Scan(&i.Day, &i.CreatedCount, &i.CompletedCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTasks = `-- name: ListTasks :many
+SELECT id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at, due_date, version FROM tasks
+ORDER BY created_at DESC
+LIMIT $1
+OFFSET $2
+`
+
+type ListTasksParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+// Retrieves a paginated list of all tasks, ordered by creation date.
+func (q *Queries) ListTasks(ctx context.Context, arg ListTasksParams) ([]Task, error) {
+	rows, err := q.db.Query(ctx, listTasks, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Task
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.Priority,
+			&i.AssigneeID,
+			&i.CreatedAt,
 			&i.CompletedAt,
 			&i.Archived,
 			&i.ArchivedAt,
+			&i.DueDate,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -530,7 +1240,7 @@ func (q *Queries) ListTasks(ctx context.Context, arg ListTasksParams) ([]Task, e
 }
 
 const listTasksByAssignee = `-- name: ListTasksByAssignee :many
-SELECT id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at FROM tasks
+SELECT id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at, due_date, version FROM tasks
 WHERE assignee_id = $1 AND archived = false
 ORDER BY created_at DESC
 LIMIT $2
@@ -565,6 +1275,8 @@ func (q *Queries) ListTasksByAssignee(ctx context.Context, arg ListTasksByAssign
 			&i.CompletedAt,
 			&i.Archived,
 			&i.ArchivedAt,
+			&i.DueDate,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -577,7 +1289,7 @@ func (q *Queries) ListTasksByAssignee(ctx context.Context, arg ListTasksByAssign
 }
 
 const listTasksByProject = `-- name: ListTasksByProject :many
-SELECT id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at FROM tasks
+SELECT id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at, due_date, version FROM tasks
 WHERE project_id = $1 AND archived = false
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
@@ -611,6 +1323,74 @@ func (q *Queries) ListTasksByProject(ctx context.Context, arg ListTasksByProject
 			&i.CompletedAt,
 			&i.Archived,
 			&i.ArchivedAt,
+			&i.DueDate,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTasksByProjectIDs = `-- name: ListTasksByProjectIDs :many
+SELECT
+    t.id,
+    t.project_id,
+    t.title,
+    t.status,
+    t.priority,
+    t.assignee_id,
+    u.name AS assignee_name
+FROM
+    tasks t
+LEFT JOIN
+    users u ON t.assignee_id = u.id
+WHERE
+    t.project_id = ANY($1::bigint[])
+    AND t.archived = false
+ORDER BY
+    t.project_id,
+    t.created_at DESC
+LIMIT $2
+`
+
+type ListTasksByProjectIDsParams struct {
+	ProjectIds []int64 `json:"project_ids"`
+	TaskLimit  int32   `json:"task_limit"`
+}
+
+type ListTasksByProjectIDsRow struct {
+	ID           int64        `json:"id"`
+	ProjectID    pgtype.Int8  `json:"project_id"`
+	Title        string       `json:"title"`
+	Status       TaskStatus   `json:"status"`
+	Priority     TaskPriority `json:"priority"`
+	AssigneeID   pgtype.Int8  `json:"assignee_id"`
+	AssigneeName pgtype.Text  `json:"assignee_name"`
+}
+
+// Lists active tasks for a set of projects in one round trip, grouped by project then newest first
+func (q *Queries) ListTasksByProjectIDs(ctx context.Context, arg ListTasksByProjectIDsParams) ([]ListTasksByProjectIDsRow, error) {
+	rows, err := q.db.Query(ctx, listTasksByProjectIDs, arg.ProjectIds, arg.TaskLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTasksByProjectIDsRow
+	for rows.Next() {
+		var i ListTasksByProjectIDsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Title,
+			&i.Status,
+			&i.Priority,
+			&i.AssigneeID,
+			&i.AssigneeName,
 		); err != nil {
 			return nil, err
 		}
@@ -675,60 +1455,643 @@ func (q *Queries) ListTasksWithAssigneeNames(ctx context.Context, arg ListTasksW
 	return items, nil
 }
 
-const unarchiveTask = `-- name: UnarchiveTask :one
-UPDATE tasks  
-SET archived = false, archived_at = NULL
-WHERE id = $1 AND archived = true
-RETURNING id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at
+const listTasksWithAssigneeNamesAfter = `-- name: ListTasksWithAssigneeNamesAfter :many
+SELECT t.id, t.title, t.status, t.priority, t.assignee_id,
+       u.name as assignee_name, t.created_at
+FROM tasks t
+LEFT JOIN users u ON t.assignee_id = u.id
+WHERE t.project_id = $1 AND t.archived = false
+  AND ($3::timestamp IS NULL
+       OR (t.created_at, t.id) < ($3::timestamp, $4::bigint))
+ORDER BY t.created_at DESC, t.id DESC
+LIMIT $2
 `
 
-// Unarchive a single archived task by ID and return its details
-func (q *Queries) UnarchiveTask(ctx context.Context, id int64) (Task, error) {
-	row := q.db.QueryRow(ctx, unarchiveTask, id)
-	var i Task
-	err := row.Scan(
-		&i.ID,
-		&i.ProjectID,
-		&i.Title,
-		&i.Description,
-		&i.Status,
-		&i.Priority,
-		&i.AssigneeID,
-		&i.CreatedAt,
-		&i.CompletedAt,
-		&i.Archived,
-		&i.ArchivedAt,
-	)
-	return i, err
+type ListTasksWithAssigneeNamesAfterParams struct {
+	ProjectID      pgtype.Int8      `json:"project_id"`
+	Limit          int32            `json:"limit"`
+	AfterCreatedAt pgtype.Timestamp `json:"after_created_at"`
+	AfterID        pgtype.Int8      `json:"after_id"`
 }
 
-const updateTask = `-- name: UpdateTask :one
-UPDATE tasks
-SET
-    project_id = COALESCE($1, project_id),
-    title = COALESCE($2, title),
-    description = COALESCE($3, description),
-    status = COALESCE($4, status),
-    priority = COALESCE($5, priority),
-    assignee_id = COALESCE($6, assignee_id),
-    completed_at = COALESCE($7, completed_at)
-WHERE id = $8
-RETURNING id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at
-`
+type ListTasksWithAssigneeNamesAfterRow struct {
+	ID           int64            `json:"id"`
+	Title        string           `json:"title"`
+	Status       TaskStatus       `json:"status"`
+	Priority     TaskPriority     `json:"priority"`
+	AssigneeID   pgtype.Int8      `json:"assignee_id"`
+	AssigneeName pgtype.Text      `json:"assignee_name"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+}
 
-type UpdateTaskParams struct {
-	ProjectID   pgtype.Int8      `json:"project_id"`
-	Title       pgtype.Text      `json:"title"`
-	Description pgtype.Text      `json:"description"`
-	Status      NullTaskStatus   `json:"status"`
-	Priority    NullTaskPriority `json:"priority"`
-	AssigneeID  pgtype.Int8      `json:"assignee_id"`
-	CompletedAt pgtype.Timestamp `json:"completed_at"`
-	ID          int64            `json:"id"`
+// List a project's active tasks with assignee names, newest first, a page at a time by keyset:
+// pass the last row's created_at and id to continue after it, or NULL to start from the newest
+func (q *Queries) ListTasksWithAssigneeNamesAfter(ctx context.Context, arg ListTasksWithAssigneeNamesAfterParams) ([]ListTasksWithAssigneeNamesAfterRow, error) {
+	rows, err := q.db.Query(ctx, listTasksWithAssigneeNamesAfter,
+		arg.ProjectID,
+		arg.Limit,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTasksWithAssigneeNamesAfterRow
+	for rows.Next() {
+		var i ListTasksWithAssigneeNamesAfterRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Status,
+			&i.Priority,
+			&i.AssigneeID,
+			&i.AssigneeName,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTasksWithSkillsByProject = `-- name: ListTasksWithSkillsByProject :many
+SELECT
+    t.id,
+    t.title,
+    t.status,
+    t.priority,
+    t.assignee_id,
+    u.name AS assignee_name,
+    COALESCE(
+        array_agg(s.skill_name ORDER BY s.skill_name) FILTER (WHERE s.id IS NOT NULL),
+        '{}'
+    )::text[] AS skill_names
+FROM tasks t
+LEFT JOIN users u ON t.assignee_id = u.id
+LEFT JOIN task_required_skills trs ON trs.task_id = t.id
+LEFT JOIN skills s ON s.id = trs.skill_id
+WHERE t.project_id = $1 AND t.archived = false
+GROUP BY t.id, u.name
+ORDER BY t.created_at DESC
+LIMIT $2 OFFSET $3
+`
+
+type ListTasksWithSkillsByProjectParams struct {
+	ProjectID pgtype.Int8 `json:"project_id"`
+	Limit     int32       `json:"limit"`
+	Offset    int32       `json:"offset"`
+}
+
+type ListTasksWithSkillsByProjectRow struct {
+	ID           int64        `json:"id"`
+	Title        string       `json:"title"`
+	Status       TaskStatus   `json:"status"`
+	Priority     TaskPriority `json:"priority"`
+	AssigneeID   pgtype.Int8  `json:"assignee_id"`
+	AssigneeName pgtype.Text  `json:"assignee_name"`
+	SkillNames   []string     `json:"skill_names"`
+}
+
+// List tasks in a project with assignee names and the names of their required skills, newest first.
+// Skills are aggregated per task so a board needs no per-task skill lookups; tasks without skills get an empty array.
+func (q *Queries) ListTasksWithSkillsByProject(ctx context.Context, arg ListTasksWithSkillsByProjectParams) ([]ListTasksWithSkillsByProjectRow, error) {
+	rows, err := q.db.Query(ctx, listTasksWithSkillsByProject, arg.ProjectID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTasksWithSkillsByProjectRow
+	for rows.Next() {
+		var i ListTasksWithSkillsByProjectRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Status,
+			&i.Priority,
+			&i.AssigneeID,
+			&i.AssigneeName,
+			&i.SkillNames,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markTaskDone = `-- name: MarkTaskDone :one
+UPDATE tasks
+SET status = 'done', completed_at = now(), version = version + 1
+WHERE id = $1 AND status = 'in_progress' AND archived = false
+RETURNING id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at, due_date, version
+`
+
+// Moves an in-progress task to done and stamps its completion time.
+func (q *Queries) MarkTaskDone(ctx context.Context, id int64) (Task, error) {
+	row := q.db.QueryRow(ctx, markTaskDone, id)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Title,
+		&i.Description,
+		&i.Status,
+		&i.Priority,
+		&i.AssigneeID,
+		&i.CreatedAt,
+		&i.CompletedAt,
+		&i.Archived,
+		&i.ArchivedAt,
+		&i.DueDate,
+		&i.Version,
+	)
+	return i, err
+}
+
+const pauseTask = `-- name: PauseTask :one
+UPDATE tasks
+SET status = 'open', version = version + 1
+WHERE id = $1 AND assignee_id = $2 AND status = 'in_progress' AND archived = false
+RETURNING id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at, due_date, version
+`
+
+type PauseTaskParams struct {
+	ID         int64       `json:"id"`
+	AssigneeID pgtype.Int8 `json:"assignee_id"`
+}
+
+// Moves an in-progress task back to open while keeping its assignee, only if it is assigned to the given user.
+func (q *Queries) PauseTask(ctx context.Context, arg PauseTaskParams) (Task, error) {
+	row := q.db.QueryRow(ctx, pauseTask, arg.ID, arg.AssigneeID)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Title,
+		&i.Description,
+		&i.Status,
+		&i.Priority,
+		&i.AssigneeID,
+		&i.CreatedAt,
+		&i.CompletedAt,
+		&i.Archived,
+		&i.ArchivedAt,
+		&i.DueDate,
+		&i.Version,
+	)
+	return i, err
+}
+
+const reassignActiveTasks = `-- name: ReassignActiveTasks :many
+UPDATE tasks
+SET assignee_id = $1, version = version + 1
+WHERE assignee_id = $2
+  AND status <> 'done'
+  AND archived = false
+RETURNING id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at, due_date, version
+`
+
+type ReassignActiveTasksParams struct {
+	ToAssigneeID   pgtype.Int8 `json:"to_assignee_id"`
+	FromAssigneeID pgtype.Int8 `json:"from_assignee_id"`
+}
+
+// Moves a user's live, unfinished tasks to another user, each keeping its status.
+func (q *Queries) ReassignActiveTasks(ctx context.Context, arg ReassignActiveTasksParams) ([]Task, error) {
+	rows, err := q.db.Query(ctx, reassignActiveTasks, arg.ToAssigneeID, arg.FromAssigneeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Task
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.Priority,
+			&i.AssigneeID,
+			&i.CreatedAt,
+			&i.CompletedAt,
+			&i.Archived,
+			&i.ArchivedAt,
+			&i.DueDate,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const reopenTask = `-- name: ReopenTask :one
+UPDATE tasks
+SET status = 'open', completed_at = NULL, assignee_id = NULL, version = version + 1
+WHERE id = $1 AND status = 'done' AND archived = false
+RETURNING id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at, due_date, version
+`
+
+// Moves a done task back to open, clearing its completion time and assignee.
+func (q *Queries) ReopenTask(ctx context.Context, id int64) (Task, error) {
+	row := q.db.QueryRow(ctx, reopenTask, id)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Title,
+		&i.Description,
+		&i.Status,
+		&i.Priority,
+		&i.AssigneeID,
+		&i.CreatedAt,
+		&i.CompletedAt,
+		&i.Archived,
+		&i.ArchivedAt,
+		&i.DueDate,
+		&i.Version,
+	)
+	return i, err
+}
+
+const resumeTask = `-- name: ResumeTask :one
+UPDATE tasks
+SET status = 'in_progress', version = version + 1
+WHERE id = $1 AND assignee_id = $2 AND status = 'open' AND archived = false
+RETURNING id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at, due_date, version
+`
+
+type ResumeTaskParams struct {
+	ID         int64       `json:"id"`
+	AssigneeID pgtype.Int8 `json:"assignee_id"`
+}
+
+// Moves a paused task (open, but still assigned to the given user) back to in progress.
+func (q *Queries) ResumeTask(ctx context.Context, arg ResumeTaskParams) (Task, error) {
+	row := q.db.QueryRow(ctx, resumeTask, arg.ID, arg.AssigneeID)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Title,
+		&i.Description,
+		&i.Status,
+		&i.Priority,
+		&i.AssigneeID,
+		&i.CreatedAt,
+		&i.CompletedAt,
+		&i.Archived,
+		&i.ArchivedAt,
+		&i.DueDate,
+		&i.Version,
+	)
+	return i, err
+}
+
+const searchTasksByTeam = `-- name: SearchTasksByTeam :many
+SELECT
+    t.id,
+    t.project_id,
+    p.project_name,
+    t.title,
+    t.description,
+    t.status,
+    t.priority,
+    t.assignee_id,
+    u.name AS assignee_name,
+    t.due_date,
+    t.archived,
+    t.created_at
+FROM
+    tasks t
+JOIN
+    projects p ON t.project_id = p.id
+LEFT JOIN
+    users u ON t.assignee_id = u.id
+WHERE
+    p.team_id = $1
+    AND (
+        $2::text = ''
+        OR to_tsvector('english', t.title || ' ' || COALESCE(t.description, '')) @@ plainto_tsquery('english', $2)
+        OR t.title ILIKE $3 ESCAPE '\'
+    )
+    AND ($4::task_status IS NULL OR t.status = $4)
+    AND ($5::task_priority IS NULL OR t.priority = $5)
+    AND ($6::bigint IS NULL OR t.assignee_id = $6)
+    AND t.archived = $7
+ORDER BY
+    ts_rank(to_tsvector('english', t.title || ' ' || COALESCE(t.description, '')), plainto_tsquery('english', $2)) DESC,
+    t.created_at DESC,
+    t.id DESC
+LIMIT $8 OFFSET $9
+`
+
+type SearchTasksByTeamParams struct {
+	TeamID       int64            `json:"team_id"`
+	Query        string           `json:"query"`
+	TitlePattern string           `json:"title_pattern"`
+	Status       NullTaskStatus   `json:"status"`
+	Priority     NullTaskPriority `json:"priority"`
+	AssigneeID   pgtype.Int8      `json:"assignee_id"`
+	Archived     bool             `json:"archived"`
+	Limit        int32            `json:"limit"`
+	Offset       int32            `json:"offset"`
+}
+
+type SearchTasksByTeamRow struct {
+	ID           int64            `json:"id"`
+	ProjectID    pgtype.Int8      `json:"project_id"`
+	ProjectName  string           `json:"project_name"`
+	Title        string           `json:"title"`
+	Description  pgtype.Text      `json:"description"`
+	Status       TaskStatus       `json:"status"`
+	Priority     TaskPriority     `json:"priority"`
+	AssigneeID   pgtype.Int8      `json:"assignee_id"`
+	AssigneeName pgtype.Text      `json:"assignee_name"`
+	DueDate      pgtype.Timestamp `json:"due_date"`
+	Archived     bool             `json:"archived"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+}
+
+// Searches the tasks in all of a team's projects, with optional status, priority and assignee filters.
+// The query matches title and description as full text, or any part of the title; an empty query matches every task.
+// title_pattern is the query as a LIKE pattern, with its wildcards escaped by the caller.
+// Best full-text matches come first, then newest first.
+func (q *Queries) SearchTasksByTeam(ctx context.Context, arg SearchTasksByTeamParams) ([]SearchTasksByTeamRow, error) {
+	rows, err := q.db.Query(ctx, searchTasksByTeam,
+		arg.TeamID,
+		arg.Query,
+		arg.TitlePattern,
+		arg.Status,
+		arg.Priority,
+		arg.AssigneeID,
+		arg.Archived,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchTasksByTeamRow
+	for rows.Next() {
+		var i SearchTasksByTeamRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.ProjectName,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.Priority,
+			&i.AssigneeID,
+			&i.AssigneeName,
+			&i.DueDate,
+			&i.Archived,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const startTask = `-- name: StartTask :one
+UPDATE tasks
+SET status = 'in_progress', version = version + 1
+WHERE id = $1 AND status = 'open' AND assignee_id IS NOT NULL AND archived = false
+RETURNING id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at, due_date, version
+`
+
+// Moves an open, assigned task to in progress.
+func (q *Queries) StartTask(ctx context.Context, id int64) (Task, error) {
+	row := q.db.QueryRow(ctx, startTask, id)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Title,
+		&i.Description,
+		&i.Status,
+		&i.Priority,
+		&i.AssigneeID,
+		&i.CreatedAt,
+		&i.CompletedAt,
+		&i.Archived,
+		&i.ArchivedAt,
+		&i.DueDate,
+		&i.Version,
+	)
+	return i, err
+}
+
+const unarchiveTask = `-- name: UnarchiveTask :one
+UPDATE tasks
+SET archived = false, archived_at = NULL, status = 'open', assignee_id = NULL, completed_at = NULL, version = version + 1
+WHERE id = $1 AND archived = true
+RETURNING id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at, due_date, version
+`
+
+// Restore a single archived task by ID as open and unassigned, since its engineer was freed on archive
+func (q *Queries) UnarchiveTask(ctx context.Context, id int64) (Task, error) {
+	row := q.db.QueryRow(ctx, unarchiveTask, id)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Title,
+		&i.Description,
+		&i.Status,
+		&i.Priority,
+		&i.AssigneeID,
+		&i.CreatedAt,
+		&i.CompletedAt,
+		&i.Archived,
+		&i.ArchivedAt,
+		&i.DueDate,
+		&i.Version,
+	)
+	return i, err
+}
+
+const unarchiveTasksByProject = `-- name: UnarchiveTasksByProject :exec
+UPDATE tasks
+SET archived = false,
+    archived_at = NULL,
+    status = CASE WHEN status = 'done' THEN status ELSE 'open' END,
+    assignee_id = CASE WHEN status = 'done' THEN assignee_id ELSE NULL END,
+    version = version + 1
+WHERE project_id = $1 AND archived = true
+`
+
+// Restore all archived tasks in a project. Done tasks keep their status, assignee and completion time;
+// any other task was archived on its own, its engineer freed, so it comes back open and unassigned
+func (q *Queries) UnarchiveTasksByProject(ctx context.Context, projectID pgtype.Int8) error {
+	_, err := q.db.Exec(ctx, unarchiveTasksByProject, projectID)
+	return err
+}
+
+const unassignActiveTasksInTeam = `-- name: UnassignActiveTasksInTeam :many
+UPDATE tasks t
+SET assignee_id = NULL, status = 'open', version = t.version + 1
+FROM tasks previous
+JOIN projects p ON previous.project_id = p.id
+WHERE t.id = previous.id
+  AND previous.assignee_id = $1
+  AND p.team_id = $2
+  AND previous.status <> 'done'
+  AND previous.archived = false
+RETURNING t.id, t.project_id, t.title, t.description, t.status, t.priority, t.assignee_id, t.created_at, t.completed_at, t.archived, t.archived_at, t.due_date, t.version, previous.status AS previous_status
+`
+
+type UnassignActiveTasksInTeamParams struct {
+	AssigneeID pgtype.Int8 `json:"assignee_id"`
+	TeamID     int64       `json:"team_id"`
+}
+
+type UnassignActiveTasksInTeamRow struct {
+	ID             int64            `json:"id"`
+	ProjectID      pgtype.Int8      `json:"project_id"`
+	Title          string           `json:"title"`
+	Description    pgtype.Text      `json:"description"`
+	Status         TaskStatus       `json:"status"`
+	Priority       TaskPriority     `json:"priority"`
+	AssigneeID     pgtype.Int8      `json:"assignee_id"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+	CompletedAt    pgtype.Timestamp `json:"completed_at"`
+	Archived       bool             `json:"archived"`
+	ArchivedAt     pgtype.Timestamp `json:"archived_at"`
+	DueDate        pgtype.Timestamp `json:"due_date"`
+	Version        int32            `json:"version"`
+	PreviousStatus TaskStatus       `json:"previous_status"`
+}
+
+// Unassigns and reopens a user's live, unfinished tasks on a team's projects.
+// Each row also carries the status the task had before, read from the pre-update snapshot.
+func (q *Queries) UnassignActiveTasksInTeam(ctx context.Context, arg UnassignActiveTasksInTeamParams) ([]UnassignActiveTasksInTeamRow, error) {
+	rows, err := q.db.Query(ctx, unassignActiveTasksInTeam, arg.AssigneeID, arg.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UnassignActiveTasksInTeamRow
+	for rows.Next() {
+		var i UnassignActiveTasksInTeamRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.Priority,
+			&i.AssigneeID,
+			&i.CreatedAt,
+			&i.CompletedAt,
+			&i.Archived,
+			&i.ArchivedAt,
+			&i.DueDate,
+			&i.Version,
+			&i.PreviousStatus,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const unassignTask = `-- name: UnassignTask :one
+UPDATE tasks
+SET assignee_id = NULL, status = 'open', version = version + 1
+WHERE id = $1 AND assignee_id = $2 AND status = 'in_progress'
+RETURNING id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at, due_date, version
+`
+
+type UnassignTaskParams struct {
+	ID         int64       `json:"id"`
+	AssigneeID pgtype.Int8 `json:"assignee_id"`
+}
+
+// Reopens an in-progress task and clears its assignee, only if it is assigned to the given user.
+func (q *Queries) UnassignTask(ctx context.Context, arg UnassignTaskParams) (Task, error) {
+	row := q.db.QueryRow(ctx, unassignTask, arg.ID, arg.AssigneeID)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Title,
+		&i.Description,
+		&i.Status,
+		&i.Priority,
+		&i.AssigneeID,
+		&i.CreatedAt,
+		&i.CompletedAt,
+		&i.Archived,
+		&i.ArchivedAt,
+		&i.DueDate,
+		&i.Version,
+	)
+	return i, err
+}
+
+const updateTask = `-- name: UpdateTask :one
+UPDATE tasks
+SET
+    project_id = COALESCE($1, project_id),
+    title = COALESCE($2, title),
+    description = COALESCE($3, description),
+    status = COALESCE($4, status),
+    priority = COALESCE($5, priority),
+    assignee_id = COALESCE($6, assignee_id),
+    completed_at = COALESCE($7, completed_at),
+    due_date = CASE
+        WHEN $8::bool THEN NULL
+        ELSE COALESCE($9, due_date)
+    END,
+    version = version + 1
+WHERE id = $10
+  AND ($11::int IS NULL OR version = $11)
+RETURNING id, project_id, title, description, status, priority, assignee_id, created_at, completed_at, archived, archived_at, due_date, version
+`
+
+type UpdateTaskParams struct {
+	ProjectID    pgtype.Int8      `json:"project_id"`
+	Title        pgtype.Text      `json:"title"`
+	Description  pgtype.Text      `json:"description"`
+	Status       NullTaskStatus   `json:"status"`
+	Priority     NullTaskPriority `json:"priority"`
+	AssigneeID   pgtype.Int8      `json:"assignee_id"`
+	CompletedAt  pgtype.Timestamp `json:"completed_at"`
+	ClearDueDate bool             `json:"clear_due_date"`
+	DueDate      pgtype.Timestamp `json:"due_date"`
+	ID           int64            `json:"id"`
+	Version      pgtype.Int4      `json:"version"`
 }
 
 // Updates the details of a specific task.
-// Uses sqlc.narg() to allow for partial updates of any field.
+// Uses sqlc.narg() to allow for partial updates of any field; clear_due_date removes the deadline.
+// Bumps the version; when an expected version is given, a stale one matches no row.
 func (q *Queries) UpdateTask(ctx context.Context, arg UpdateTaskParams) (Task, error) {
 	row := q.db.QueryRow(ctx, updateTask,
 		arg.ProjectID,
@@ -738,7 +2101,10 @@ func (q *Queries) UpdateTask(ctx context.Context, arg UpdateTaskParams) (Task, e
 		arg.Priority,
 		arg.AssigneeID,
 		arg.CompletedAt,
+		arg.ClearDueDate,
+		arg.DueDate,
 		arg.ID,
+		arg.Version,
 	)
 	var i Task
 	err := row.Scan(
@@ -753,6 +2119,8 @@ func (q *Queries) UpdateTask(ctx context.Context, arg UpdateTaskParams) (Task, e
 		&i.CompletedAt,
 		&i.Archived,
 		&i.ArchivedAt,
+		&i.DueDate,
+		&i.Version,
 	)
 	return i, err
 }
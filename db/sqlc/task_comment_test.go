@@ -0,0 +1,93 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pranav244872/synapse/util"
+	"github.com/stretchr/testify/require"
+)
+
+// createRandomTaskComment adds a comment to the task by the author, as a reply when parentID is set
+func createRandomTaskComment(t *testing.T, taskID, authorID int64, parentID pgtype.Int8) TaskComment {
+	arg := CreateTaskCommentParams{
+		TaskID:   taskID,
+		AuthorID: authorID,
+		ParentID: parentID,
+		Body:     util.RandomString(40),
+	}
+
+	comment, err := testQueries.CreateTaskComment(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.TaskID, comment.TaskID)
+	require.Equal(t, arg.AuthorID, comment.AuthorID)
+	require.Equal(t, arg.ParentID, comment.ParentID)
+	require.Equal(t, arg.Body, comment.Body)
+	require.True(t, comment.CreatedAt.Valid)
+
+	return comment
+}
+
+////////////////////////////////////////////////////////////////////////
+func TestListTaskCommentsByTask(t *testing.T) {
+	project := createRandomProject(t)
+	task := createRandomTaskLocal(t, project.ID)
+	author, _ := createRandomUserWithRoleAndTeam(t, UserRoleEngineer, pgtype.Int8{Int64: project.TeamID, Valid: true})
+
+	first := createRandomTaskComment(t, task.ID, author.ID, pgtype.Int8{})
+	reply := createRandomTaskComment(t, task.ID, author.ID, pgtype.Int8{Int64: first.ID, Valid: true})
+	createRandomTaskComment(t, createRandomTaskLocal(t, project.ID).ID, author.ID, pgtype.Int8{}) // Another task
+
+	comments, err := testQueries.ListTaskCommentsByTask(context.Background(), ListTaskCommentsByTaskParams{
+		TaskID: task.ID,
+		Limit:  10,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, comments, 2)
+
+	// Oldest first, with the reply pointing at its parent
+	require.Equal(t, first.ID, comments[0].ID)
+	require.Equal(t, author.Name, comments[0].AuthorName)
+	require.Equal(t, reply.ID, comments[1].ID)
+	require.Equal(t, first.ID, comments[1].ParentID.Int64)
+
+	count, err := testQueries.CountTaskCommentsByTask(context.Background(), task.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+}
+
+////////////////////////////////////////////////////////////////////////
+func TestDeleteTaskComment(t *testing.T) {
+	project := createRandomProject(t)
+	task := createRandomTaskLocal(t, project.ID)
+	author, _ := createRandomUserWithRoleAndTeam(t, UserRoleEngineer, pgtype.Int8{Int64: project.TeamID, Valid: true})
+	replier, _ := createRandomUserWithRoleAndTeam(t, UserRoleEngineer, pgtype.Int8{Int64: project.TeamID, Valid: true})
+
+	parent := createRandomTaskComment(t, task.ID, author.ID, pgtype.Int8{})
+	reply := createRandomTaskComment(t, task.ID, replier.ID, pgtype.Int8{Int64: parent.ID, Valid: true})
+
+	err := testQueries.DeleteTaskComment(context.Background(), parent.ID)
+	require.NoError(t, err)
+
+	// The comment is kept as an empty placeholder
+	deleted, err := testQueries.GetTaskComment(context.Background(), parent.ID)
+	require.NoError(t, err)
+	require.Empty(t, deleted.Body)
+	require.True(t, deleted.DeletedAt.Valid)
+
+	// Replies stay in the thread under it
+	kept, err := testQueries.GetTaskComment(context.Background(), reply.ID)
+	require.NoError(t, err)
+	require.Equal(t, reply.Body, kept.Body)
+	require.Equal(t, parent.ID, kept.ParentID.Int64)
+	require.False(t, kept.DeletedAt.Valid)
+
+	// Deleting again leaves the original deletion time alone
+	err = testQueries.DeleteTaskComment(context.Background(), parent.ID)
+	require.NoError(t, err)
+	again, err := testQueries.GetTaskComment(context.Background(), parent.ID)
+	require.NoError(t, err)
+	require.Equal(t, deleted.DeletedAt, again.DeletedAt)
+}
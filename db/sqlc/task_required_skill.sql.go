@@ -7,33 +7,98 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const addSkillToTask = `-- name: AddSkillToTask :one
 
 INSERT INTO task_required_skills (
     task_id,
-    skill_id
+    skill_id,
+    weight
 ) VALUES (
-    $1, $2
-) RETURNING task_id, skill_id
+    $1, $2, COALESCE($3::double precision, 1)
+) RETURNING task_id, skill_id, weight
 `
 
 type AddSkillToTaskParams struct {
-	TaskID  int64 `json:"task_id"`
-	SkillID int64 `json:"skill_id"`
+	TaskID  int64         `json:"task_id"`
+	SkillID int64         `json:"skill_id"`
+	Weight  pgtype.Float8 `json:"weight"`
 }
 
 // SQLC-formatted queries for the "task_required_skills" junction table.
 // These follow the conventions for use with the sqlc tool.
 // Adds a required skill to a specific task.
+// A NULL weight stores the column default of 1, i.e. a core skill.
 func (q *Queries) AddSkillToTask(ctx context.Context, arg AddSkillToTaskParams) (TaskRequiredSkill, error) {
-	row := q.db.QueryRow(ctx, addSkillToTask, arg.TaskID, arg.SkillID)
+	row := q.db.QueryRow(ctx, addSkillToTask, arg.TaskID, arg.SkillID, arg.Weight)
 	var i TaskRequiredSkill
-	err := row.Scan(&i.TaskID, &i.SkillID)
+	err := row.Scan(&i.TaskID, &i.SkillID, &i.Weight)
 	return i, err
 }
 
+const getCoOccurringSkills = `-- name: GetCoOccurringSkills :many
+SELECT
+    s.id,
+    s.skill_name,
+    s.is_verified,
+    COUNT(*) AS co_occurrences
+FROM
+    task_required_skills base
+JOIN
+    task_required_skills other ON other.task_id = base.task_id AND other.skill_id <> base.skill_id
+JOIN
+    skills s ON s.id = other.skill_id
+WHERE
+    base.skill_id = $1
+GROUP BY
+    s.id, s.skill_name, s.is_verified
+ORDER BY
+    co_occurrences DESC, s.skill_name
+LIMIT $2
+`
+
+type GetCoOccurringSkillsParams struct {
+	SkillID     int64 `json:"skill_id"`
+	ResultLimit int32 `json:"result_limit"`
+}
+
+type GetCoOccurringSkillsRow struct {
+	ID            int64  `json:"id"`
+	SkillName     string `json:"skill_name"`
+	IsVerified    bool   `json:"is_verified"`
+	CoOccurrences int64  `json:"co_occurrences"`
+}
+
+// Counts how often each other skill is required by the same tasks as the given skill.
+// The most frequent companions come first, which makes them good "related skill" suggestions.
+func (q *Queries) GetCoOccurringSkills(ctx context.Context, arg GetCoOccurringSkillsParams) ([]GetCoOccurringSkillsRow, error) {
+	rows, err := q.db.Query(ctx, getCoOccurringSkills, arg.SkillID, arg.ResultLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCoOccurringSkillsRow
+	for rows.Next() {
+		var i GetCoOccurringSkillsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SkillName,
+			&i.IsVerified,
+			&i.CoOccurrences,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSkillsForTask = `-- name: GetSkillsForTask :many
 SELECT s.id, s.skill_name, s.is_verified FROM skills s
 JOIN task_required_skills trs ON s.id = trs.skill_id
@@ -62,7 +127,7 @@ func (q *Queries) GetSkillsForTask(ctx context.Context, taskID int64) ([]Skill,
 }
 
 const getTasksForSkill = `-- name: GetTasksForSkill :many
-SELECT t.id, t.project_id, t.title, t.description, t.status, t.priority, t.assignee_id, t.created_at, t.completed_at, t.archived, t.archived_at FROM tasks t
+SELECT t.id, t.project_id, t.title, t.description, t.status, t.priority, t.assignee_id, t.created_at, t.completed_at, t.archived, t.archived_at, t.due_date, t.version FROM tasks t
 JOIN task_required_skills trs ON t.id = trs.task_id
 WHERE trs.skill_id = $1
 `
@@ -89,6 +154,72 @@ func (q *Queries) GetTasksForSkill(ctx context.Context, skillID int64) ([]Task,
 			&i.CompletedAt,
 			&i.Archived,
 			&i.ArchivedAt,
+			&i.DueDate,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSkillGapsByTeam = `-- name: ListSkillGapsByTeam :many
+WITH demand AS (
+    SELECT trs.skill_id, count(DISTINCT trs.task_id) AS open_task_count
+    FROM task_required_skills trs
+    JOIN tasks t ON trs.task_id = t.id
+    JOIN projects p ON t.project_id = p.id
+    WHERE p.team_id = $1
+      AND t.status = 'open'
+      AND t.archived = false
+    GROUP BY trs.skill_id
+),
+coverage AS (
+    SELECT us.skill_id, count(*) AS member_count
+    FROM user_skills us
+    JOIN users u ON us.user_id = u.id
+    WHERE u.team_id = $1
+      AND u.role = 'engineer'
+    GROUP BY us.skill_id
+)
+SELECT
+    s.id AS skill_id,
+    s.skill_name,
+    d.open_task_count,
+    COALESCE(c.member_count, 0)::bigint AS member_count
+FROM demand d
+JOIN skills s ON s.id = d.skill_id
+LEFT JOIN coverage c ON c.skill_id = d.skill_id
+ORDER BY member_count, d.open_task_count DESC, s.skill_name
+`
+
+type ListSkillGapsByTeamRow struct {
+	SkillID       int64  `json:"skill_id"`
+	SkillName     string `json:"skill_name"`
+	OpenTaskCount int64  `json:"open_task_count"`
+	MemberCount   int64  `json:"member_count"`
+}
+
+// For each skill the team's open tasks require, counts the open tasks demanding it
+// and the team's engineers who have it. Skills nobody covers come first, then the most demanded.
+func (q *Queries) ListSkillGapsByTeam(ctx context.Context, teamID int64) ([]ListSkillGapsByTeamRow, error) {
+	rows, err := q.db.Query(ctx, listSkillGapsByTeam, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSkillGapsByTeamRow
+	for rows.Next() {
+		var i ListSkillGapsByTeamRow
+		if err := rows.Scan(
+			&i.SkillID,
+			&i.SkillName,
+			&i.OpenTaskCount,
+			&i.MemberCount,
 		); err != nil {
 			return nil, err
 		}
@@ -100,6 +231,33 @@ func (q *Queries) GetTasksForSkill(ctx context.Context, skillID int64) ([]Task,
 	return items, nil
 }
 
+const listTaskRequiredSkills = `-- name: ListTaskRequiredSkills :many
+SELECT task_id, skill_id, weight FROM task_required_skills
+WHERE task_id = $1
+ORDER BY skill_id
+`
+
+// Lists a task's required skills with their weights.
+func (q *Queries) ListTaskRequiredSkills(ctx context.Context, taskID int64) ([]TaskRequiredSkill, error) {
+	rows, err := q.db.Query(ctx, listTaskRequiredSkills, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TaskRequiredSkill
+	for rows.Next() {
+		var i TaskRequiredSkill
+		if err := rows.Scan(&i.TaskID, &i.SkillID, &i.Weight); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const removeSkillFromTask = `-- name: RemoveSkillFromTask :exec
 DELETE FROM task_required_skills
 WHERE task_id = $1 AND skill_id = $2
@@ -115,3 +273,24 @@ func (q *Queries) RemoveSkillFromTask(ctx context.Context, arg RemoveSkillFromTa
 	_, err := q.db.Exec(ctx, removeSkillFromTask, arg.TaskID, arg.SkillID)
 	return err
 }
+
+const repointTaskRequiredSkills = `-- name: RepointTaskRequiredSkills :execrows
+UPDATE task_required_skills
+SET skill_id = $1
+WHERE skill_id = $2
+  AND task_id NOT IN (SELECT task_id FROM task_required_skills WHERE skill_id = $1)
+`
+
+type RepointTaskRequiredSkillsParams struct {
+	TargetID int64 `json:"target_id"`
+	SourceID int64 `json:"source_id"`
+}
+
+// Moves tasks from the source skill to the target skill, skipping tasks that already require the target.
+func (q *Queries) RepointTaskRequiredSkills(ctx context.Context, arg RepointTaskRequiredSkillsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, repointTaskRequiredSkills, arg.TargetID, arg.SourceID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 )
 
@@ -94,3 +95,72 @@ func TestGetTasksForSkill(t *testing.T) {
 }
 
 ////////////////////////////////////////////////////////////////////////
+
+// TestGetCoOccurringSkills checks that skills required alongside a given skill are counted per shared task.
+func TestGetCoOccurringSkills(t *testing.T) {
+	// 1. Setup: "base" appears on three tasks, "frequent" on two of them and "rare" on one.
+	base := createRandomSkill(t)
+	frequent := createRandomSkill(t)
+	rare := createRandomSkill(t)
+	unrelated := createRandomSkill(t)
+
+	link := func(taskID int64, skills ...Skill) {
+		for _, skill := range skills {
+			_, err := testQueries.AddSkillToTask(context.Background(), AddSkillToTaskParams{
+				TaskID:  taskID,
+				SkillID: skill.ID,
+			})
+			require.NoError(t, err)
+		}
+	}
+	link(createRandomTask(t).ID, base, frequent, rare)
+	link(createRandomTask(t).ID, base, frequent)
+	link(createRandomTask(t).ID, base)
+	link(createRandomTask(t).ID, frequent, unrelated) // Does not involve the base skill
+
+	// 2. Execute: Get the skills that co-occur with the base skill.
+	related, err := testQueries.GetCoOccurringSkills(context.Background(), GetCoOccurringSkillsParams{
+		SkillID:     base.ID,
+		ResultLimit: 10,
+	})
+	require.NoError(t, err)
+
+	// 3. Verify: Most frequent first, the base skill itself and unrelated skills excluded.
+	require.Len(t, related, 2)
+	require.Equal(t, frequent.ID, related[0].ID)
+	require.Equal(t, int64(2), related[0].CoOccurrences)
+	require.Equal(t, rare.ID, related[1].ID)
+	require.Equal(t, int64(1), related[1].CoOccurrences)
+}
+
+////////////////////////////////////////////////////////////////////////
+
+// TestListTaskRequiredSkills checks that weights are stored and that an omitted weight defaults to 1.
+func TestListTaskRequiredSkills(t *testing.T) {
+	// 1. Setup: One task requiring a weighted skill and one added without a weight.
+	task := createRandomTask(t)
+	weighted := createRandomSkill(t)
+	unweighted := createRandomSkill(t)
+
+	_, err := testQueries.AddSkillToTask(context.Background(), AddSkillToTaskParams{
+		TaskID:  task.ID,
+		SkillID: weighted.ID,
+		Weight:  pgtype.Float8{Float64: 0.3, Valid: true},
+	})
+	require.NoError(t, err)
+	_, err = testQueries.AddSkillToTask(context.Background(), AddSkillToTaskParams{
+		TaskID:  task.ID,
+		SkillID: unweighted.ID,
+	})
+	require.NoError(t, err)
+
+	// 2. Execute: List the task's required skills.
+	skills, err := testQueries.ListTaskRequiredSkills(context.Background(), task.ID)
+	require.NoError(t, err)
+
+	// 3. Verify: Ordered by skill ID, each with its weight.
+	require.Equal(t, []TaskRequiredSkill{
+		{TaskID: task.ID, SkillID: weighted.ID, Weight: 0.3},
+		{TaskID: task.ID, SkillID: unweighted.ID, Weight: 1},
+	}, skills)
+}
@@ -80,6 +80,53 @@ func TestUpdateTask(t *testing.T) {
 	require.Equal(t, task1.ProjectID.Int64, task2.ProjectID.Int64)
 	require.Equal(t, task1.Description.String, task2.Description.String)
 	require.Equal(t, task1.Priority, task2.Priority)
+	require.Equal(t, task1.Version+1, task2.Version)
+}
+
+func TestUpdateTaskExpectedVersion(t *testing.T) {
+	task1 := createRandomTask(t)
+
+	// The expected version matches, so the update goes through and bumps it
+	task2, err := testQueries.UpdateTask(context.Background(), UpdateTaskParams{
+		ID:      task1.ID,
+		Title:   pgtype.Text{String: util.RandomTaskTitle(), Valid: true},
+		Version: pgtype.Int4{Int32: task1.Version, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, task1.Version+1, task2.Version)
+
+	// The version read before that update is now stale and matches no row
+	_, err = testQueries.UpdateTask(context.Background(), UpdateTaskParams{
+		ID:      task1.ID,
+		Title:   pgtype.Text{String: util.RandomTaskTitle(), Valid: true},
+		Version: pgtype.Int4{Int32: task1.Version, Valid: true},
+	})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	task3, err := testQueries.GetTask(context.Background(), task1.ID)
+	require.NoError(t, err)
+	require.Equal(t, task2.Title, task3.Title)
+}
+
+func TestUpdateTaskClearDueDate(t *testing.T) {
+	task1 := createRandomTask(t)
+	dueDate := pgtype.Timestamp{Time: time.Now().UTC().Add(time.Hour).Truncate(time.Microsecond), Valid: true}
+
+	task2, err := testQueries.UpdateTask(context.Background(), UpdateTaskParams{ID: task1.ID, DueDate: dueDate})
+	require.NoError(t, err)
+	require.Equal(t, dueDate.Time, task2.DueDate.Time)
+
+	// Updating other fields keeps the due date
+	task3, err := testQueries.UpdateTask(context.Background(), UpdateTaskParams{
+		ID:    task1.ID,
+		Title: pgtype.Text{String: util.RandomTaskTitle(), Valid: true},
+	})
+	require.NoError(t, err)
+	require.True(t, task3.DueDate.Valid)
+
+	task4, err := testQueries.UpdateTask(context.Background(), UpdateTaskParams{ID: task1.ID, ClearDueDate: true})
+	require.NoError(t, err)
+	require.False(t, task4.DueDate.Valid)
 }
 
 ////////////////////////////////////////////////////////////////////////
@@ -202,3 +249,267 @@ func TestListTasksByAssignee(t *testing.T) {
 }
 
 ////////////////////////////////////////////////////////////////////////
+
+func TestListRecentTasksByTeam(t *testing.T) {
+	project := createRandomProject(t)
+
+	for range 4 {
+		createRandomTaskLocal(t, project.ID)
+	}
+
+	tasks, err := testQueries.ListRecentTasksByTeam(context.Background(), ListRecentTasksByTeamParams{
+		TeamID: project.TeamID,
+		Limit:  3,
+	})
+
+	// Assertions
+	require.NoError(t, err)
+	require.Len(t, tasks, 3)
+
+	for _, task := range tasks {
+		require.Equal(t, project.ProjectName, task.ProjectName)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////
+
+func TestListMatchingOpenTasksForEngineer(t *testing.T) {
+	project := createRandomProject(t)
+
+	engineer, err := testQueries.CreateUser(context.Background(), CreateUserParams{
+		Name:         pgtype.Text{String: util.RandomName(), Valid: true},
+		Email:        util.RandomEmail(),
+		PasswordHash: util.RandomString(20),
+		Role:         UserRoleEngineer,
+		TeamID:       pgtype.Int8{Int64: project.TeamID, Valid: true},
+	})
+	require.NoError(t, err)
+
+	expertSkill := createRandomSkill(t)
+	beginnerSkill := createRandomSkill(t)
+	unknownSkill := createRandomSkill(t)
+
+	_, err = testQueries.AddSkillToUser(context.Background(), AddSkillToUserParams{
+		UserID: engineer.ID, SkillID: expertSkill.ID, Proficiency: ProficiencyLevelExpert,
+	})
+	require.NoError(t, err)
+	_, err = testQueries.AddSkillToUser(context.Background(), AddSkillToUserParams{
+		UserID: engineer.ID, SkillID: beginnerSkill.ID, Proficiency: ProficiencyLevelBeginner,
+	})
+	require.NoError(t, err)
+
+	// requireSkills creates an open, unassigned task needing the given skills
+	requireSkills := func(skills ...Skill) Task {
+		task := createRandomTaskLocal(t, project.ID)
+		for _, skill := range skills {
+			_, err := testQueries.AddSkillToTask(context.Background(), AddSkillToTaskParams{TaskID: task.ID, SkillID: skill.ID})
+			require.NoError(t, err)
+		}
+		return task
+	}
+
+	weakMatch := requireSkills(beginnerSkill)
+	strongMatch := requireSkills(expertSkill, beginnerSkill)
+	requireSkills(unknownSkill) // Shares no skills with the engineer
+
+	tasks, err := testQueries.ListMatchingOpenTasksForEngineer(context.Background(), ListMatchingOpenTasksForEngineerParams{
+		EngineerID:  engineer.ID,
+		TeamID:      project.TeamID,
+		ResultLimit: 10,
+	})
+
+	// Assertions
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+
+	require.Equal(t, strongMatch.ID, tasks[0].ID)
+	require.Equal(t, int64(2), tasks[0].MatchingSkillCount)
+	require.Equal(t, int64(4), tasks[0].ProficiencyScore)
+
+	require.Equal(t, weakMatch.ID, tasks[1].ID)
+	require.Equal(t, int64(1), tasks[1].MatchingSkillCount)
+	require.Equal(t, int64(1), tasks[1].ProficiencyScore)
+}
+
+////////////////////////////////////////////////////////////////////////
+
+func TestListTasksWithSkillsByProject(t *testing.T) {
+	project := createRandomProject(t)
+	withSkills := createRandomTaskLocal(t, project.ID)
+	withoutSkills := createRandomTaskLocal(t, project.ID)
+
+	// Link two skills to the first task only
+	first := createRandomSkill(t)
+	second := createRandomSkill(t)
+	for _, skill := range []Skill{first, second} {
+		_, err := testQueries.AddSkillToTask(context.Background(), AddSkillToTaskParams{
+			TaskID:  withSkills.ID,
+			SkillID: skill.ID,
+		})
+		require.NoError(t, err)
+	}
+
+	tasks, err := testQueries.ListTasksWithSkillsByProject(context.Background(), ListTasksWithSkillsByProjectParams{
+		ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+		Limit:     10,
+		Offset:    0,
+	})
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+
+	byID := make(map[int64]ListTasksWithSkillsByProjectRow)
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+
+	// The aggregated names match the task's links, and a task without skills gets an empty array
+	require.ElementsMatch(t, []string{first.SkillName, second.SkillName}, byID[withSkills.ID].SkillNames)
+	require.NotNil(t, byID[withoutSkills.ID].SkillNames)
+	require.Empty(t, byID[withoutSkills.ID].SkillNames)
+}
+
+////////////////////////////////////////////////////////////////////////
+
+func TestListTasksWithAssigneeNamesAfter(t *testing.T) {
+	ctx := context.Background()
+	project := createRandomProject(t)
+
+	// Five tasks created at the same instant, so only the id can order them
+	createdAt := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	want := make(map[int64]bool)
+	for range 5 {
+		task := createRandomTaskLocal(t, project.ID)
+		_, err := testPool.Exec(ctx, "UPDATE tasks SET created_at = $1 WHERE id = $2", createdAt, task.ID)
+		require.NoError(t, err)
+		want[task.ID] = true
+	}
+
+	// Paging two at a time visits every task exactly once
+	params := ListTasksWithAssigneeNamesAfterParams{
+		ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+		Limit:     2,
+	}
+	seen := make(map[int64]bool)
+	for {
+		page, err := testQueries.ListTasksWithAssigneeNamesAfter(ctx, params)
+		require.NoError(t, err)
+		for _, task := range page {
+			require.False(t, seen[task.ID], "task %d returned twice", task.ID)
+			seen[task.ID] = true
+		}
+		if len(page) < int(params.Limit) {
+			break
+		}
+		last := page[len(page)-1]
+		params.AfterCreatedAt = last.CreatedAt
+		params.AfterID = pgtype.Int8{Int64: last.ID, Valid: true}
+	}
+	require.Equal(t, want, seen)
+}
+
+////////////////////////////////////////////////////////////////////////
+
+func TestListOverdueTasksByTeam(t *testing.T) {
+	project := createRandomProject(t)
+	createWithDueDate := func(dueDate pgtype.Timestamp) Task {
+		task, err := testQueries.CreateTask(context.Background(), CreateTaskParams{
+			ProjectID: pgtype.Int8{Int64: project.ID, Valid: true},
+			Title:     util.RandomTaskTitle(),
+			Status:    TaskStatusOpen,
+			Priority:  TaskPriorityMedium,
+			DueDate:   dueDate,
+		})
+		require.NoError(t, err)
+		return task
+	}
+	past := func(d time.Duration) pgtype.Timestamp {
+		return pgtype.Timestamp{Time: time.Now().UTC().Add(-d), Valid: true}
+	}
+
+	mostOverdue := createWithDueDate(past(48 * time.Hour))
+	overdue := createWithDueDate(past(time.Hour))
+	createWithDueDate(pgtype.Timestamp{Time: time.Now().UTC().Add(time.Hour), Valid: true}) // Not due yet
+	createWithDueDate(pgtype.Timestamp{})                                                   // No deadline
+	finished := createWithDueDate(past(time.Hour))
+
+	// Finishing a task keeps its due date but takes it off the overdue list
+	done, err := testQueries.UpdateTask(context.Background(), UpdateTaskParams{
+		ID:     finished.ID,
+		Status: NullTaskStatus{TaskStatus: TaskStatusDone, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, finished.DueDate.Time, done.DueDate.Time)
+
+	tasks, err := testQueries.ListOverdueTasksByTeam(context.Background(), ListOverdueTasksByTeamParams{
+		TeamID: project.TeamID,
+		Limit:  5,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	require.Equal(t, mostOverdue.ID, tasks[0].ID)
+	require.Equal(t, overdue.ID, tasks[1].ID)
+
+	// Pages continue in the same order
+	page, err := testQueries.ListOverdueTasksByTeam(context.Background(), ListOverdueTasksByTeamParams{
+		TeamID: project.TeamID,
+		Limit:  1,
+		Offset: 1,
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Equal(t, overdue.ID, page[0].ID)
+
+	count, err := testQueries.CountOverdueTasksByTeam(context.Background(), project.TeamID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+}
+
+func TestCountTasksByProjectIDs(t *testing.T) {
+	busy := createRandomProject(t)
+	empty := createRandomProject(t)
+
+	for _, status := range []TaskStatus{TaskStatusOpen, TaskStatusDone, TaskStatusDone} {
+		_, err := testQueries.CreateTask(context.Background(), CreateTaskParams{
+			ProjectID: pgtype.Int8{Int64: busy.ID, Valid: true},
+			Title:     util.RandomTaskTitle(),
+			Status:    status,
+			Priority:  TaskPriorityMedium,
+		})
+		require.NoError(t, err)
+	}
+
+	rows, err := testQueries.CountTasksByProjectIDs(context.Background(), []int64{busy.ID, empty.ID})
+	require.NoError(t, err)
+
+	// Only the project with tasks gets a row
+	require.Len(t, rows, 1)
+	require.Equal(t, busy.ID, rows[0].ProjectID.Int64)
+	require.Equal(t, int64(3), rows[0].TotalCount)
+	require.Equal(t, int64(2), rows[0].CompletedCount)
+}
+
+func TestCountActiveTasksByAssignees(t *testing.T) {
+	project := createRandomProject(t)
+	busy, _ := createRandomUser(t)
+	idle, _ := createRandomUser(t)
+
+	for _, status := range []TaskStatus{TaskStatusOpen, TaskStatusInProgress, TaskStatusDone} {
+		_, err := testQueries.CreateTask(context.Background(), CreateTaskParams{
+			ProjectID:  pgtype.Int8{Int64: project.ID, Valid: true},
+			Title:      util.RandomTaskTitle(),
+			Status:     status,
+			Priority:   TaskPriorityMedium,
+			AssigneeID: pgtype.Int8{Int64: busy.ID, Valid: true},
+		})
+		require.NoError(t, err)
+	}
+
+	rows, err := testQueries.CountActiveTasksByAssignees(context.Background(), []int64{busy.ID, idle.ID})
+	require.NoError(t, err)
+
+	// Done tasks are not counted, and users without unfinished tasks get no row
+	require.Len(t, rows, 1)
+	require.Equal(t, busy.ID, rows[0].AssigneeID.Int64)
+	require.Equal(t, int64(2), rows[0].TaskCount)
+}
@@ -32,7 +32,7 @@ INSERT INTO teams (
   manager_id
 ) VALUES (
   $1, $2
-) RETURNING id, team_name, manager_id
+) RETURNING id, team_name, manager_id, auto_assign, default_task_priority, default_task_status
 `
 
 type CreateTeamParams struct {
@@ -46,7 +46,14 @@ type CreateTeamParams struct {
 func (q *Queries) CreateTeam(ctx context.Context, arg CreateTeamParams) (Team, error) {
 	row := q.db.QueryRow(ctx, createTeam, arg.TeamName, arg.ManagerID)
 	var i Team
-	err := row.Scan(&i.ID, &i.TeamName, &i.ManagerID)
+	err := row.Scan(
+		&i.ID,
+		&i.TeamName,
+		&i.ManagerID,
+		&i.AutoAssign,
+		&i.DefaultTaskPriority,
+		&i.DefaultTaskStatus,
+	)
 	return i, err
 }
 
@@ -62,7 +69,7 @@ func (q *Queries) DeleteTeam(ctx context.Context, id int64) error {
 }
 
 const getTeam = `-- name: GetTeam :one
-SELECT id, team_name, manager_id FROM teams
+SELECT id, team_name, manager_id, auto_assign, default_task_priority, default_task_status FROM teams
 WHERE id = $1 LIMIT 1
 `
 
@@ -70,12 +77,19 @@ WHERE id = $1 LIMIT 1
 func (q *Queries) GetTeam(ctx context.Context, id int64) (Team, error) {
 	row := q.db.QueryRow(ctx, getTeam, id)
 	var i Team
-	err := row.Scan(&i.ID, &i.TeamName, &i.ManagerID)
+	err := row.Scan(
+		&i.ID,
+		&i.TeamName,
+		&i.ManagerID,
+		&i.AutoAssign,
+		&i.DefaultTaskPriority,
+		&i.DefaultTaskStatus,
+	)
 	return i, err
 }
 
 const getTeamByManagerID = `-- name: GetTeamByManagerID :one
-SELECT id, team_name, manager_id FROM teams
+SELECT id, team_name, manager_id, auto_assign, default_task_priority, default_task_status FROM teams
 WHERE manager_id = $1 LIMIT 1
 `
 
@@ -84,12 +98,88 @@ WHERE manager_id = $1 LIMIT 1
 func (q *Queries) GetTeamByManagerID(ctx context.Context, managerID pgtype.Int8) (Team, error) {
 	row := q.db.QueryRow(ctx, getTeamByManagerID, managerID)
 	var i Team
-	err := row.Scan(&i.ID, &i.TeamName, &i.ManagerID)
+	err := row.Scan(
+		&i.ID,
+		&i.TeamName,
+		&i.ManagerID,
+		&i.AutoAssign,
+		&i.DefaultTaskPriority,
+		&i.DefaultTaskStatus,
+	)
+	return i, err
+}
+
+const getTeamManagerContact = `-- name: GetTeamManagerContact :one
+SELECT
+    t.id AS team_id,
+    t.team_name,
+    u.id AS manager_id,
+    u.name AS manager_name,
+    u.email AS manager_email
+FROM teams t
+LEFT JOIN users u ON t.manager_id = u.id
+WHERE t.id = $1
+`
+
+type GetTeamManagerContactRow struct {
+	TeamID       int64       `json:"team_id"`
+	TeamName     string      `json:"team_name"`
+	ManagerID    pgtype.Int8 `json:"manager_id"`
+	ManagerName  pgtype.Text `json:"manager_name"`
+	ManagerEmail pgtype.Text `json:"manager_email"`
+}
+
+// Get a team together with its manager's contact details.
+// The LEFT JOIN keeps teams without a manager, leaving the manager columns NULL.
+func (q *Queries) GetTeamManagerContact(ctx context.Context, id int64) (GetTeamManagerContactRow, error) {
+	row := q.db.QueryRow(ctx, getTeamManagerContact, id)
+	var i GetTeamManagerContactRow
+	err := row.Scan(
+		&i.TeamID,
+		&i.TeamName,
+		&i.ManagerID,
+		&i.ManagerName,
+		&i.ManagerEmail,
+	)
+	return i, err
+}
+
+const getTeamOverviewStats = `-- name: GetTeamOverviewStats :one
+SELECT
+    (SELECT count(*) FROM projects p
+     WHERE p.team_id = $1 AND p.archived = false) AS active_projects,
+    (SELECT count(*) FROM projects p
+     WHERE p.team_id = $1 AND p.archived = true) AS archived_projects,
+    (SELECT count(*) FROM tasks t JOIN projects p ON t.project_id = p.id
+     WHERE p.team_id = $1 AND t.status = 'open' AND t.archived = false) AS open_tasks,
+    (SELECT count(*) FROM tasks t JOIN projects p ON t.project_id = p.id
+     WHERE p.team_id = $1 AND t.status <> 'done' AND t.archived = false
+       AND t.due_date < now()) AS overdue_tasks
+`
+
+type GetTeamOverviewStatsRow struct {
+	ActiveProjects   int64 `json:"active_projects"`
+	ArchivedProjects int64 `json:"archived_projects"`
+	OpenTasks        int64 `json:"open_tasks"`
+	OverdueTasks     int64 `json:"overdue_tasks"`
+}
+
+// Counts a team's projects and live tasks for the manager overview in one round trip.
+// Overdue tasks are counted as in CountOverdueTasksByTeam; tasks without a due date never are.
+func (q *Queries) GetTeamOverviewStats(ctx context.Context, teamID int64) (GetTeamOverviewStatsRow, error) {
+	row := q.db.QueryRow(ctx, getTeamOverviewStats, teamID)
+	var i GetTeamOverviewStatsRow
+	err := row.Scan(
+		&i.ActiveProjects,
+		&i.ArchivedProjects,
+		&i.OpenTasks,
+		&i.OverdueTasks,
+	)
 	return i, err
 }
 
 const listTeams = `-- name: ListTeams :many
-SELECT id, team_name, manager_id FROM teams
+SELECT id, team_name, manager_id, auto_assign, default_task_priority, default_task_status FROM teams
 ORDER BY id
 LIMIT $1
 OFFSET $2
@@ -110,7 +200,14 @@ func (q *Queries) ListTeams(ctx context.Context, arg ListTeamsParams) ([]Team, e
 	var items []Team
 	for rows.Next() {
 		var i Team
-		if err := rows.Scan(&i.ID, &i.TeamName, &i.ManagerID); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.TeamName,
+			&i.ManagerID,
+			&i.AutoAssign,
+			&i.DefaultTaskPriority,
+			&i.DefaultTaskStatus,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -122,7 +219,7 @@ func (q *Queries) ListTeams(ctx context.Context, arg ListTeamsParams) ([]Team, e
 }
 
 const listTeamsWithManagers = `-- name: ListTeamsWithManagers :many
-SELECT t.id, team_name, manager_id, u.id, name, email, team_id, availability, password_hash, role
+SELECT t.id, team_name, manager_id, auto_assign, default_task_priority, default_task_status, u.id, name, email, team_id, availability, password_hash, role
 FROM teams t
 LEFT JOIN users u ON t.manager_id = u.id
 ORDER BY t.id
@@ -136,16 +233,19 @@ type ListTeamsWithManagersParams struct {
 }
 
 type ListTeamsWithManagersRow struct {
-	ID           int64                  `json:"id"`
-	TeamName     string                 `json:"team_name"`
-	ManagerID    pgtype.Int8            `json:"manager_id"`
-	ID_2         pgtype.Int8            `json:"id_2"`
-	Name         pgtype.Text            `json:"name"`
-	Email        pgtype.Text            `json:"email"`
-	TeamID       pgtype.Int8            `json:"team_id"`
-	Availability NullAvailabilityStatus `json:"availability"`
-	PasswordHash pgtype.Text            `json:"password_hash"`
-	Role         NullUserRole           `json:"role"`
+	ID                  int64                  `json:"id"`
+	TeamName            string                 `json:"team_name"`
+	ManagerID           pgtype.Int8            `json:"manager_id"`
+	AutoAssign          bool                   `json:"auto_assign"`
+	DefaultTaskPriority NullTaskPriority       `json:"default_task_priority"`
+	DefaultTaskStatus   NullTaskStatus         `json:"default_task_status"`
+	ID_2                pgtype.Int8            `json:"id_2"`
+	Name                pgtype.Text            `json:"name"`
+	Email               pgtype.Text            `json:"email"`
+	TeamID              pgtype.Int8            `json:"team_id"`
+	Availability        NullAvailabilityStatus `json:"availability"`
+	PasswordHash        pgtype.Text            `json:"password_hash"`
+	Role                NullUserRole           `json:"role"`
 }
 
 // List all teams and include their manager's details.
@@ -164,6 +264,9 @@ func (q *Queries) ListTeamsWithManagers(ctx context.Context, arg ListTeamsWithMa
 			&i.ID,
 			&i.TeamName,
 			&i.ManagerID,
+			&i.AutoAssign,
+			&i.DefaultTaskPriority,
+			&i.DefaultTaskStatus,
 			&i.ID_2,
 			&i.Name,
 			&i.Email,
@@ -183,7 +286,7 @@ func (q *Queries) ListTeamsWithManagers(ctx context.Context, arg ListTeamsWithMa
 }
 
 const listUnmanagedTeams = `-- name: ListUnmanagedTeams :many
-SELECT id, team_name, manager_id FROM teams
+SELECT id, team_name, manager_id, auto_assign, default_task_priority, default_task_status FROM teams
 WHERE manager_id IS NULL
 ORDER BY team_name
 `
@@ -199,7 +302,14 @@ func (q *Queries) ListUnmanagedTeams(ctx context.Context) ([]Team, error) {
 	var items []Team
 	for rows.Next() {
 		var i Team
-		if err := rows.Scan(&i.ID, &i.TeamName, &i.ManagerID); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.TeamName,
+			&i.ManagerID,
+			&i.AutoAssign,
+			&i.DefaultTaskPriority,
+			&i.DefaultTaskStatus,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -210,11 +320,38 @@ func (q *Queries) ListUnmanagedTeams(ctx context.Context) ([]Team, error) {
 	return items, nil
 }
 
+const setTeamAutoAssign = `-- name: SetTeamAutoAssign :one
+UPDATE teams
+SET auto_assign = $2
+WHERE id = $1
+RETURNING id, team_name, manager_id, auto_assign, default_task_priority, default_task_status
+`
+
+type SetTeamAutoAssignParams struct {
+	ID         int64 `json:"id"`
+	AutoAssign bool  `json:"auto_assign"`
+}
+
+// Enables or disables automatic assignment of new tasks for a team.
+func (q *Queries) SetTeamAutoAssign(ctx context.Context, arg SetTeamAutoAssignParams) (Team, error) {
+	row := q.db.QueryRow(ctx, setTeamAutoAssign, arg.ID, arg.AutoAssign)
+	var i Team
+	err := row.Scan(
+		&i.ID,
+		&i.TeamName,
+		&i.ManagerID,
+		&i.AutoAssign,
+		&i.DefaultTaskPriority,
+		&i.DefaultTaskStatus,
+	)
+	return i, err
+}
+
 const setTeamManager = `-- name: SetTeamManager :one
 UPDATE teams
 SET manager_id = $2
 WHERE id = $1
-RETURNING id, team_name, manager_id
+RETURNING id, team_name, manager_id, auto_assign, default_task_priority, default_task_status
 `
 
 type SetTeamManagerParams struct {
@@ -226,7 +363,14 @@ type SetTeamManagerParams struct {
 func (q *Queries) SetTeamManager(ctx context.Context, arg SetTeamManagerParams) (Team, error) {
 	row := q.db.QueryRow(ctx, setTeamManager, arg.ID, arg.ManagerID)
 	var i Team
-	err := row.Scan(&i.ID, &i.TeamName, &i.ManagerID)
+	err := row.Scan(
+		&i.ID,
+		&i.TeamName,
+		&i.ManagerID,
+		&i.AutoAssign,
+		&i.DefaultTaskPriority,
+		&i.DefaultTaskStatus,
+	)
 	return i, err
 }
 
@@ -236,7 +380,7 @@ SET
   team_name = COALESCE($2, team_name),
   manager_id = $3
 WHERE id = $1
-RETURNING id, team_name, manager_id
+RETURNING id, team_name, manager_id, auto_assign, default_task_priority, default_task_status
 `
 
 type UpdateTeamParams struct {
@@ -251,6 +395,61 @@ type UpdateTeamParams struct {
 func (q *Queries) UpdateTeam(ctx context.Context, arg UpdateTeamParams) (Team, error) {
 	row := q.db.QueryRow(ctx, updateTeam, arg.ID, arg.TeamName, arg.ManagerID)
 	var i Team
-	err := row.Scan(&i.ID, &i.TeamName, &i.ManagerID)
+	err := row.Scan(
+		&i.ID,
+		&i.TeamName,
+		&i.ManagerID,
+		&i.AutoAssign,
+		&i.DefaultTaskPriority,
+		&i.DefaultTaskStatus,
+	)
+	return i, err
+}
+
+const updateTeamSettings = `-- name: UpdateTeamSettings :one
+UPDATE teams
+SET
+  auto_assign = COALESCE($1, auto_assign),
+  default_task_priority = CASE
+    WHEN $2::bool THEN NULL
+    ELSE COALESCE($3, default_task_priority)
+  END,
+  default_task_status = CASE
+    WHEN $4::bool THEN NULL
+    ELSE COALESCE($5, default_task_status)
+  END
+WHERE id = $6
+RETURNING id, team_name, manager_id, auto_assign, default_task_priority, default_task_status
+`
+
+type UpdateTeamSettingsParams struct {
+	AutoAssign               pgtype.Bool      `json:"auto_assign"`
+	ClearDefaultTaskPriority bool             `json:"clear_default_task_priority"`
+	DefaultTaskPriority      NullTaskPriority `json:"default_task_priority"`
+	ClearDefaultTaskStatus   bool             `json:"clear_default_task_status"`
+	DefaultTaskStatus        NullTaskStatus   `json:"default_task_status"`
+	ID                       int64            `json:"id"`
+}
+
+// Updates a team's task settings. Only non-NULL arguments change the stored value;
+// the clear flags reset a default back to NULL, meaning "no team default".
+func (q *Queries) UpdateTeamSettings(ctx context.Context, arg UpdateTeamSettingsParams) (Team, error) {
+	row := q.db.QueryRow(ctx, updateTeamSettings,
+		arg.AutoAssign,
+		arg.ClearDefaultTaskPriority,
+		arg.DefaultTaskPriority,
+		arg.ClearDefaultTaskStatus,
+		arg.DefaultTaskStatus,
+		arg.ID,
+	)
+	var i Team
+	err := row.Scan(
+		&i.ID,
+		&i.TeamName,
+		&i.ManagerID,
+		&i.AutoAssign,
+		&i.DefaultTaskPriority,
+		&i.DefaultTaskStatus,
+	)
 	return i, err
 }
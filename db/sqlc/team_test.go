@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -78,6 +79,23 @@ func TestUpdateTeam(t *testing.T) {
 
 ////////////////////////////////////////////////////////////////////////
 
+func TestSetTeamAutoAssign(t *testing.T) {
+	team1 := createRandomTeam(t)
+	require.False(t, team1.AutoAssign) // Disabled by default
+
+	team2, err := testQueries.SetTeamAutoAssign(context.Background(), SetTeamAutoAssignParams{
+		ID:         team1.ID,
+		AutoAssign: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, team1.ID, team2.ID)
+	require.True(t, team2.AutoAssign)
+
+	team3, err := testQueries.GetTeam(context.Background(), team1.ID)
+	require.NoError(t, err)
+	require.True(t, team3.AutoAssign)
+}
+
 func TestDeleteTeam(t *testing.T) {
 	// Create a team to delete
 	team1 := createRandomTeam(t)
@@ -137,6 +155,80 @@ func TestGetTeamByManagerID(t *testing.T) {
 
 ////////////////////////////////////////////////////////////////////////
 
+func TestGetTeamManagerContact(t *testing.T) {
+	// A team with a manager returns the manager's contact details.
+	team1, manager := createRandomTeamWithManager(t)
+
+	contact, err := testQueries.GetTeamManagerContact(context.Background(), team1.ID)
+	require.NoError(t, err)
+	require.Equal(t, team1.ID, contact.TeamID)
+	require.Equal(t, team1.TeamName, contact.TeamName)
+	require.True(t, contact.ManagerID.Valid)
+	require.Equal(t, manager.ID, contact.ManagerID.Int64)
+	require.Equal(t, manager.Name.String, contact.ManagerName.String)
+	require.Equal(t, manager.Email, contact.ManagerEmail.String)
+
+	// A team without a manager is still returned, with empty manager fields.
+	team2 := createRandomTeam(t)
+
+	contact, err = testQueries.GetTeamManagerContact(context.Background(), team2.ID)
+	require.NoError(t, err)
+	require.Equal(t, team2.ID, contact.TeamID)
+	require.False(t, contact.ManagerID.Valid)
+	require.False(t, contact.ManagerName.Valid)
+	require.False(t, contact.ManagerEmail.Valid)
+}
+
+func TestGetTeamOverviewStats(t *testing.T) {
+	ctx := context.Background()
+	team := createRandomTeam(t)
+
+	// An empty team counts zero everywhere
+	stats, err := testQueries.GetTeamOverviewStats(ctx, team.ID)
+	require.NoError(t, err)
+	require.Equal(t, GetTeamOverviewStatsRow{}, stats)
+
+	newProject := func() Project {
+		project, err := testQueries.CreateProject(ctx, CreateProjectParams{ProjectName: util.RandomName(), TeamID: team.ID})
+		require.NoError(t, err)
+		return project
+	}
+	active := newProject()
+	archived := newProject()
+	_, err = testQueries.ArchiveProject(ctx, ArchiveProjectParams{ID: archived.ID, TeamID: team.ID})
+	require.NoError(t, err)
+
+	newTask := func(status TaskStatus, dueDate pgtype.Timestamp) {
+		_, err := testQueries.CreateTask(ctx, CreateTaskParams{
+			ProjectID: pgtype.Int8{Int64: active.ID, Valid: true},
+			Title:     util.RandomTaskTitle(),
+			Status:    status,
+			Priority:  TaskPriorityMedium,
+			DueDate:   dueDate,
+		})
+		require.NoError(t, err)
+	}
+	overdue := pgtype.Timestamp{Time: time.Now().Add(-48 * time.Hour), Valid: true}
+	newTask(TaskStatusOpen, pgtype.Timestamp{})
+	newTask(TaskStatusOpen, overdue)
+	newTask(TaskStatusInProgress, overdue)
+	newTask(TaskStatusDone, overdue)
+
+	// Another team's work is not counted
+	createRandomProject(t)
+
+	stats, err = testQueries.GetTeamOverviewStats(ctx, team.ID)
+	require.NoError(t, err)
+	require.Equal(t, GetTeamOverviewStatsRow{
+		ActiveProjects:   1,
+		ArchivedProjects: 1,
+		OpenTasks:        2,
+		OverdueTasks:     2,
+	}, stats)
+}
+
+////////////////////////////////////////////////////////////////////////
+
 func TestListTeamsWithManagers(t *testing.T) {
 	// 1. Create 5 teams, some with managers and some without.
 	for i := 0; i < 3; i++ {
@@ -32,20 +32,26 @@ WHERE (
     LOWER(name) LIKE LOWER($1) OR 
     LOWER(email) LIKE LOWER($1)
 )
-AND (
-    $2::text = '' OR 
-    role = $2::user_role
-)
+AND ($2::user_role IS NULL OR role = $2)
+AND ($3::bigint IS NULL OR team_id = $3)
+AND ($4::boolean IS NULL OR (team_id IS NOT NULL) = $4)
 `
 
 type CountSearchUsersParams struct {
-	Column1 string `json:"column_1"`
-	Column2 string `json:"column_2"`
+	Search  string       `json:"search"`
+	Role    NullUserRole `json:"role"`
+	TeamID  pgtype.Int8  `json:"team_id"`
+	HasTeam pgtype.Bool  `json:"has_team"`
 }
 
-// Counts users whose name or email matches a search string and optionally filters by role
+// Counts users whose name or email matches a search string, with optional role, team and has-team filters
 func (q *Queries) CountSearchUsers(ctx context.Context, arg CountSearchUsersParams) (int64, error) {
-	row := q.db.QueryRow(ctx, countSearchUsers, arg.Column1, arg.Column2)
+	row := q.db.QueryRow(ctx, countSearchUsers,
+		arg.Search,
+		arg.Role,
+		arg.TeamID,
+		arg.HasTeam,
+	)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -347,6 +353,40 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 	return items, nil
 }
 
+const listUsersByIDs = `-- name: ListUsersByIDs :many
+SELECT id, name, email, team_id, availability, password_hash, role FROM users
+WHERE id = ANY($1::bigint[])
+`
+
+// Retrieves all users with the given IDs in one query. IDs with no user are skipped.
+func (q *Queries) ListUsersByIDs(ctx context.Context, ids []int64) ([]User, error) {
+	rows, err := q.db.Query(ctx, listUsersByIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.TeamID,
+			&i.Availability,
+			&i.PasswordHash,
+			&i.Role,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listUsersByTeam = `-- name: ListUsersByTeam :many
 SELECT id, name, email, team_id, availability, password_hash, role FROM users
 WHERE team_id = $1
@@ -390,6 +430,29 @@ func (q *Queries) ListUsersByTeam(ctx context.Context, arg ListUsersByTeamParams
 	return items, nil
 }
 
+const markUserBusyIfAvailable = `-- name: MarkUserBusyIfAvailable :one
+UPDATE users
+SET availability = 'busy'
+WHERE id = $1 AND availability = 'available'
+RETURNING id, name, email, team_id, availability, password_hash, role
+`
+
+// Marks an available user busy; returns no rows if they are not available, so concurrent claims serialize on the user row.
+func (q *Queries) MarkUserBusyIfAvailable(ctx context.Context, id int64) (User, error) {
+	row := q.db.QueryRow(ctx, markUserBusyIfAvailable, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.TeamID,
+		&i.Availability,
+		&i.PasswordHash,
+		&i.Role,
+	)
+	return i, err
+}
+
 const removeUserFromTeam = `-- name: RemoveUserFromTeam :one
 UPDATE users
 SET team_id = NULL
@@ -412,6 +475,42 @@ func (q *Queries) RemoveUserFromTeam(ctx context.Context, id int64) (User, error
 	return i, err
 }
 
+const removeUsersFromTeam = `-- name: RemoveUsersFromTeam :many
+UPDATE users
+SET team_id = NULL
+WHERE team_id = $1
+RETURNING id, name, email, team_id, availability, password_hash, role
+`
+
+// Unassigns every member of a team and returns them; used when the team itself is deleted.
+func (q *Queries) RemoveUsersFromTeam(ctx context.Context, teamID pgtype.Int8) ([]User, error) {
+	rows, err := q.db.Query(ctx, removeUsersFromTeam, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.TeamID,
+			&i.Availability,
+			&i.PasswordHash,
+			&i.Role,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const searchUsers = `-- name: SearchUsers :many
 SELECT u.id, u.name, u.email, u.role, u.team_id, u.availability,
        t.team_name
@@ -422,19 +521,20 @@ WHERE (
     LOWER(u.name) LIKE LOWER($1) OR 
     LOWER(u.email) LIKE LOWER($1)
 )
-AND (
-    $2::text = '' OR 
-    u.role = $2::user_role
-)
+AND ($2::user_role IS NULL OR u.role = $2)
+AND ($3::bigint IS NULL OR u.team_id = $3)
+AND ($4::boolean IS NULL OR (u.team_id IS NOT NULL) = $4)
 ORDER BY u.id
-LIMIT $3 OFFSET $4
+LIMIT $5 OFFSET $6
 `
 
 type SearchUsersParams struct {
-	Column1 string `json:"column_1"`
-	Column2 string `json:"column_2"`
-	Limit   int32  `json:"limit"`
-	Offset  int32  `json:"offset"`
+	Search  string       `json:"search"`
+	Role    NullUserRole `json:"role"`
+	TeamID  pgtype.Int8  `json:"team_id"`
+	HasTeam pgtype.Bool  `json:"has_team"`
+	Limit   int32        `json:"limit"`
+	Offset  int32        `json:"offset"`
 }
 
 type SearchUsersRow struct {
@@ -447,11 +547,13 @@ type SearchUsersRow struct {
 	TeamName     pgtype.Text        `json:"team_name"`
 }
 
-// Retrieves a paginated list of users with team names, filtered by search string and optional role
+// Retrieves a paginated list of users with team names, filtered by search string and optional role, team and has-team filters
 func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]SearchUsersRow, error) {
 	rows, err := q.db.Query(ctx, searchUsers,
-		arg.Column1,
-		arg.Column2,
+		arg.Search,
+		arg.Role,
+		arg.TeamID,
+		arg.HasTeam,
 		arg.Limit,
 		arg.Offset,
 	)
@@ -481,6 +583,62 @@ func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]Sea
 	return items, nil
 }
 
+const setUserAvailabilityUnlessOnLeave = `-- name: SetUserAvailabilityUnlessOnLeave :one
+UPDATE users
+SET availability = $2
+WHERE id = $1 AND availability <> 'on_leave'
+RETURNING id, name, email, team_id, availability, password_hash, role
+`
+
+type SetUserAvailabilityUnlessOnLeaveParams struct {
+	ID           int64              `json:"id"`
+	Availability AvailabilityStatus `json:"availability"`
+}
+
+// Sets a user's availability unless they are on leave; returns no rows if they are, so assignments never override a leave.
+func (q *Queries) SetUserAvailabilityUnlessOnLeave(ctx context.Context, arg SetUserAvailabilityUnlessOnLeaveParams) (User, error) {
+	row := q.db.QueryRow(ctx, setUserAvailabilityUnlessOnLeave, arg.ID, arg.Availability)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.TeamID,
+		&i.Availability,
+		&i.PasswordHash,
+		&i.Role,
+	)
+	return i, err
+}
+
+const updateAvailabilityIfNotBusy = `-- name: UpdateAvailabilityIfNotBusy :one
+UPDATE users
+SET availability = $2
+WHERE id = $1 AND availability <> 'busy'
+RETURNING id, name, email, team_id, availability, password_hash, role
+`
+
+type UpdateAvailabilityIfNotBusyParams struct {
+	ID           int64              `json:"id"`
+	Availability AvailabilityStatus `json:"availability"`
+}
+
+// Lets a user switch between available and on leave; returns no rows while they are busy with a task.
+func (q *Queries) UpdateAvailabilityIfNotBusy(ctx context.Context, arg UpdateAvailabilityIfNotBusyParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateAvailabilityIfNotBusy, arg.ID, arg.Availability)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.TeamID,
+		&i.Availability,
+		&i.PasswordHash,
+		&i.Role,
+	)
+	return i, err
+}
+
 const updateUser = `-- name: UpdateUser :one
 UPDATE users
 SET
@@ -523,6 +681,23 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 	return i, err
 }
 
+const updateUserPassword = `-- name: UpdateUserPassword :exec
+UPDATE users
+SET password_hash = $2
+WHERE id = $1
+`
+
+type UpdateUserPasswordParams struct {
+	ID           int64  `json:"id"`
+	PasswordHash string `json:"password_hash"`
+}
+
+// Replaces a user's password hash, e.g. after a password reset
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	_, err := q.db.Exec(ctx, updateUserPassword, arg.ID, arg.PasswordHash)
+	return err
+}
+
 const updateUserRole = `-- name: UpdateUserRole :one
 UPDATE users
 SET role = $2
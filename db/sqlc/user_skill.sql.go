@@ -19,7 +19,7 @@ INSERT INTO user_skills (
     proficiency
 ) VALUES (
     $1, $2, $3
-) RETURNING user_id, skill_id, proficiency
+) RETURNING user_id, skill_id, proficiency, is_manual
 `
 
 type AddSkillToUserParams struct {
@@ -34,7 +34,12 @@ type AddSkillToUserParams struct {
 func (q *Queries) AddSkillToUser(ctx context.Context, arg AddSkillToUserParams) (UserSkill, error) {
 	row := q.db.QueryRow(ctx, addSkillToUser, arg.UserID, arg.SkillID, arg.Proficiency)
 	var i UserSkill
-	err := row.Scan(&i.UserID, &i.SkillID, &i.Proficiency)
+	err := row.Scan(
+		&i.UserID,
+		&i.SkillID,
+		&i.Proficiency,
+		&i.IsManual,
+	)
 	return i, err
 }
 
@@ -72,6 +77,70 @@ func (q *Queries) GetSkillsForUser(ctx context.Context, userID int64) ([]GetSkil
 	return items, nil
 }
 
+const getTeamMembersWithSkillMatch = `-- name: GetTeamMembersWithSkillMatch :many
+SELECT
+    u.id AS user_id,
+    u.name,
+    u.email,
+    u.availability,
+    us.skill_id,
+    us.proficiency
+FROM
+    users u
+JOIN
+    user_skills us ON us.user_id = u.id
+WHERE
+    u.team_id = $1
+    AND u.role = 'engineer'
+    AND us.skill_id = ANY($2::bigint[])
+ORDER BY
+    u.id,
+    us.skill_id
+`
+
+type GetTeamMembersWithSkillMatchParams struct {
+	TeamID   pgtype.Int8 `json:"team_id"`
+	SkillIDs []int64     `json:"skill_ids"`
+}
+
+type GetTeamMembersWithSkillMatchRow struct {
+	UserID       int64              `json:"user_id"`
+	Name         pgtype.Text        `json:"name"`
+	Email        string             `json:"email"`
+	Availability AvailabilityStatus `json:"availability"`
+	SkillID      int64              `json:"skill_id"`
+	Proficiency  ProficiencyLevel   `json:"proficiency"`
+}
+
+// Lists a team's engineers' proficiency in each of the given skills they have, one row per engineer and skill.
+// Used to rank engineers locally when the recommender service is unavailable.
+func (q *Queries) GetTeamMembersWithSkillMatch(ctx context.Context, arg GetTeamMembersWithSkillMatchParams) ([]GetTeamMembersWithSkillMatchRow, error) {
+	rows, err := q.db.Query(ctx, getTeamMembersWithSkillMatch, arg.TeamID, arg.SkillIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTeamMembersWithSkillMatchRow
+	for rows.Next() {
+		var i GetTeamMembersWithSkillMatchRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Name,
+			&i.Email,
+			&i.Availability,
+			&i.SkillID,
+			&i.Proficiency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUsersWithSkill = `-- name: GetUsersWithSkill :many
 SELECT u.id, u.name, u.email, us.proficiency FROM users u
 JOIN user_skills us ON u.id = us.user_id
@@ -112,7 +181,190 @@ func (q *Queries) GetUsersWithSkill(ctx context.Context, skillID int64) ([]GetUs
 	return items, nil
 }
 
-const removeSkillFromUser = `-- name: RemoveSkillFromUser :exec
+const listEngineersForSkills = `-- name: ListEngineersForSkills :many
+SELECT
+    u.id,
+    u.name,
+    u.email,
+    u.availability,
+    count(*) AS matching_skill_count,
+    SUM(
+        CASE us.proficiency
+            WHEN 'expert' THEN 3
+            WHEN 'intermediate' THEN 2
+            ELSE 1
+        END
+    )::bigint AS proficiency_score
+FROM
+    users u
+JOIN
+    user_skills us ON us.user_id = u.id
+WHERE
+    u.team_id = $1
+    AND u.role = 'engineer'
+    AND us.skill_id = ANY($2::bigint[])
+GROUP BY
+    u.id
+ORDER BY
+    matching_skill_count DESC,
+    proficiency_score DESC,
+    u.id
+`
+
+type ListEngineersForSkillsParams struct {
+	TeamID   pgtype.Int8 `json:"team_id"`
+	SkillIDs []int64     `json:"skill_ids"`
+}
+
+type ListEngineersForSkillsRow struct {
+	ID                 int64              `json:"id"`
+	Name               pgtype.Text        `json:"name"`
+	Email              string             `json:"email"`
+	Availability       AvailabilityStatus `json:"availability"`
+	MatchingSkillCount int64              `json:"matching_skill_count"`
+	ProficiencyScore   int64              `json:"proficiency_score"`
+}
+
+// Lists a team's engineers who have at least one of the given skills.
+// Engineers are ranked by how many of the skills they have, then by their proficiency in them.
+func (q *Queries) ListEngineersForSkills(ctx context.Context, arg ListEngineersForSkillsParams) ([]ListEngineersForSkillsRow, error) {
+	rows, err := q.db.Query(ctx, listEngineersForSkills, arg.TeamID, arg.SkillIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListEngineersForSkillsRow
+	for rows.Next() {
+		var i ListEngineersForSkillsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.Availability,
+			&i.MatchingSkillCount,
+			&i.ProficiencyScore,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserSkills = `-- name: ListUserSkills :many
+SELECT user_id, skill_id, proficiency, is_manual FROM user_skills
+WHERE user_id = $1
+ORDER BY skill_id
+`
+
+// Lists a user's skill links, including whether each was curated by hand.
+func (q *Queries) ListUserSkills(ctx context.Context, userID int64) ([]UserSkill, error) {
+	rows, err := q.db.Query(ctx, listUserSkills, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserSkill
+	for rows.Next() {
+		var i UserSkill
+		if err := rows.Scan(
+			&i.UserID,
+			&i.SkillID,
+			&i.Proficiency,
+			&i.IsManual,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markUserSkillManual = `-- name: MarkUserSkillManual :one
+UPDATE user_skills
+SET is_manual = true
+WHERE user_id = $1 AND skill_id = $2
+RETURNING user_id, skill_id, proficiency, is_manual
+`
+
+type MarkUserSkillManualParams struct {
+	UserID  int64 `json:"user_id"`
+	SkillID int64 `json:"skill_id"`
+}
+
+// Marks a user's skill as added or endorsed by a person, so reprocessing keeps it.
+func (q *Queries) MarkUserSkillManual(ctx context.Context, arg MarkUserSkillManualParams) (UserSkill, error) {
+	row := q.db.QueryRow(ctx, markUserSkillManual, arg.UserID, arg.SkillID)
+	var i UserSkill
+	err := row.Scan(
+		&i.UserID,
+		&i.SkillID,
+		&i.Proficiency,
+		&i.IsManual,
+	)
+	return i, err
+}
+
+const mergeUserSkillProficiencies = `-- name: MergeUserSkillProficiencies :execrows
+UPDATE user_skills target
+SET
+    proficiency = GREATEST(target.proficiency, source.proficiency),
+    is_manual = target.is_manual OR source.is_manual
+FROM user_skills source
+WHERE target.skill_id = $1
+  AND source.skill_id = $2
+  AND source.user_id = target.user_id
+`
+
+type MergeUserSkillProficienciesParams struct {
+	TargetID int64 `json:"target_id"`
+	SourceID int64 `json:"source_id"`
+}
+
+// For users holding both skills of a merge, keeps the higher proficiency on the target skill.
+// A manual flag on either row carries over, so the merged skill stays protected from reprocessing.
+func (q *Queries) MergeUserSkillProficiencies(ctx context.Context, arg MergeUserSkillProficienciesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, mergeUserSkillProficiencies, arg.TargetID, arg.SourceID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const raiseUserSkillProficiency = `-- name: RaiseUserSkillProficiency :one
+UPDATE user_skills
+SET proficiency = GREATEST(proficiency, $1::proficiency_level)
+WHERE user_id = $2 AND skill_id = $3
+RETURNING user_id, skill_id, proficiency, is_manual
+`
+
+type RaiseUserSkillProficiencyParams struct {
+	Proficiency ProficiencyLevel `json:"proficiency"`
+	UserID      int64            `json:"user_id"`
+	SkillID     int64            `json:"skill_id"`
+}
+
+// Raises a user's proficiency in a skill to the given level, leaving a higher one as it is.
+// Unlike UpdateUserSkillProficiency the manual flag is untouched, since the level comes from a resume.
+func (q *Queries) RaiseUserSkillProficiency(ctx context.Context, arg RaiseUserSkillProficiencyParams) (UserSkill, error) {
+	row := q.db.QueryRow(ctx, raiseUserSkillProficiency, arg.Proficiency, arg.UserID, arg.SkillID)
+	var i UserSkill
+	err := row.Scan(
+		&i.UserID,
+		&i.SkillID,
+		&i.Proficiency,
+		&i.IsManual,
+	)
+	return i, err
+}
+
+const removeSkillFromUser = `-- name: RemoveSkillFromUser :execrows
 DELETE FROM user_skills
 WHERE user_id = $1 AND skill_id = $2
 `
@@ -122,17 +374,41 @@ type RemoveSkillFromUserParams struct {
 	SkillID int64 `json:"skill_id"`
 }
 
-// Removes a skill from a user.
-func (q *Queries) RemoveSkillFromUser(ctx context.Context, arg RemoveSkillFromUserParams) error {
-	_, err := q.db.Exec(ctx, removeSkillFromUser, arg.UserID, arg.SkillID)
-	return err
+// Removes a skill from a user, returning how many links were deleted.
+func (q *Queries) RemoveSkillFromUser(ctx context.Context, arg RemoveSkillFromUserParams) (int64, error) {
+	result, err := q.db.Exec(ctx, removeSkillFromUser, arg.UserID, arg.SkillID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const repointUserSkills = `-- name: RepointUserSkills :execrows
+UPDATE user_skills
+SET skill_id = $1
+WHERE skill_id = $2
+  AND user_id NOT IN (SELECT user_id FROM user_skills WHERE skill_id = $1)
+`
+
+type RepointUserSkillsParams struct {
+	TargetID int64 `json:"target_id"`
+	SourceID int64 `json:"source_id"`
+}
+
+// Moves users from the source skill to the target skill, skipping users who already have the target.
+func (q *Queries) RepointUserSkills(ctx context.Context, arg RepointUserSkillsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, repointUserSkills, arg.TargetID, arg.SourceID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
 const updateUserSkillProficiency = `-- name: UpdateUserSkillProficiency :one
 UPDATE user_skills
-SET proficiency = $3
+SET proficiency = $3, is_manual = true
 WHERE user_id = $1 AND skill_id = $2
-RETURNING user_id, skill_id, proficiency
+RETURNING user_id, skill_id, proficiency, is_manual
 `
 
 type UpdateUserSkillProficiencyParams struct {
@@ -142,9 +418,15 @@ type UpdateUserSkillProficiencyParams struct {
 }
 
 // Updates a user's proficiency level for a specific skill.
+// The skill counts as endorsed by a person from then on, so reprocessing keeps it.
 func (q *Queries) UpdateUserSkillProficiency(ctx context.Context, arg UpdateUserSkillProficiencyParams) (UserSkill, error) {
 	row := q.db.QueryRow(ctx, updateUserSkillProficiency, arg.UserID, arg.SkillID, arg.Proficiency)
 	var i UserSkill
-	err := row.Scan(&i.UserID, &i.SkillID, &i.Proficiency)
+	err := row.Scan(
+		&i.UserID,
+		&i.SkillID,
+		&i.Proficiency,
+		&i.IsManual,
+	)
 	return i, err
 }
@@ -50,6 +50,9 @@ func TestUpdateUserSkillProficiency(t *testing.T) {
 	require.Equal(t, ProficiencyLevelExpert, userSkill2.Proficiency)
 	// Assert the new proficiency is different from the old one
 	require.NotEqual(t, userSkill1.Proficiency, userSkill2.Proficiency)
+	// A person set the proficiency, so reprocessing must keep the skill
+	require.False(t, userSkill1.IsManual)
+	require.True(t, userSkill2.IsManual)
 }
 
 ////////////////////////////////////////////////////////////////////////
@@ -65,8 +68,9 @@ func TestRemoveSkillFromUser(t *testing.T) {
 	}
 
 	// Remove the skill from the user
-	err := testQueries.RemoveSkillFromUser(context.Background(), arg)
+	removed, err := testQueries.RemoveSkillFromUser(context.Background(), arg)
 	require.NoError(t, err)
+	require.Equal(t, int64(1), removed)
 
 	// Try to retrieve the skills for that user
 	skills, err := testQueries.GetSkillsForUser(context.Background(), userSkill.UserID)
@@ -135,3 +139,99 @@ func TestGetUsersWithSkill(t *testing.T) {
 }
 
 ////////////////////////////////////////////////////////////////////////
+
+// TestListEngineersForSkills checks that only the team's engineers with a matching skill are ranked.
+func TestListEngineersForSkills(t *testing.T) {
+	// 1. Setup: Three engineers on one team; one has both skills, two have one skill at different levels.
+	best, _ := createRandomUser(t)
+	teamID := best.TeamID
+	partial, _ := createRandomUserWithRoleAndTeam(t, UserRoleEngineer, teamID)
+	novice, _ := createRandomUserWithRoleAndTeam(t, UserRoleEngineer, teamID) // Same skill count as partial, lower proficiency
+	manager, _ := createRandomUserWithRoleAndTeam(t, UserRoleManager, teamID)
+	outsider, _ := createRandomUser(t) // Has the skills but is on another team
+
+	first := createRandomSkill(t)
+	second := createRandomSkill(t)
+	addSkill := func(userID int64, skill Skill, proficiency ProficiencyLevel) {
+		_, err := testQueries.AddSkillToUser(context.Background(), AddSkillToUserParams{
+			UserID:      userID,
+			SkillID:     skill.ID,
+			Proficiency: proficiency,
+		})
+		require.NoError(t, err)
+	}
+	addSkill(best.ID, first, ProficiencyLevelExpert)
+	addSkill(best.ID, second, ProficiencyLevelBeginner)
+	addSkill(partial.ID, first, ProficiencyLevelExpert)
+	addSkill(novice.ID, first, ProficiencyLevelBeginner)
+	addSkill(manager.ID, first, ProficiencyLevelExpert)
+	addSkill(outsider.ID, first, ProficiencyLevelExpert)
+
+	// 2. Execute: Rank the team's engineers for both skills.
+	engineers, err := testQueries.ListEngineersForSkills(context.Background(), ListEngineersForSkillsParams{
+		TeamID:   teamID,
+		SkillIDs: []int64{first.ID, second.ID},
+	})
+	require.NoError(t, err)
+
+	// 3. Verify: The manager and the other team's engineer are excluded, and ties on
+	// skill count are broken by proficiency.
+	require.Len(t, engineers, 3)
+	require.Equal(t, best.ID, engineers[0].ID)
+	require.Equal(t, int64(2), engineers[0].MatchingSkillCount)
+	require.Equal(t, int64(4), engineers[0].ProficiencyScore)
+	require.Equal(t, partial.ID, engineers[1].ID)
+	require.Equal(t, int64(1), engineers[1].MatchingSkillCount)
+	require.Equal(t, int64(3), engineers[1].ProficiencyScore)
+	require.Equal(t, novice.ID, engineers[2].ID)
+	require.Equal(t, int64(1), engineers[2].ProficiencyScore)
+
+	// 4. Verify: A duplicated skill ID does not inflate the count.
+	engineers, err = testQueries.ListEngineersForSkills(context.Background(), ListEngineersForSkillsParams{
+		TeamID:   teamID,
+		SkillIDs: []int64{first.ID, first.ID},
+	})
+	require.NoError(t, err)
+	require.Len(t, engineers, 3)
+	require.Equal(t, int64(1), engineers[0].MatchingSkillCount)
+}
+
+// TestGetTeamMembersWithSkillMatch checks that each of the team's engineers' matching skills is listed once.
+func TestGetTeamMembersWithSkillMatch(t *testing.T) {
+	// 1. Setup: An engineer with both skills, a manager and an outsider with one of them.
+	engineer, _ := createRandomUser(t)
+	teamID := engineer.TeamID
+	manager, _ := createRandomUserWithRoleAndTeam(t, UserRoleManager, teamID)
+	outsider, _ := createRandomUser(t)
+
+	first := createRandomSkill(t)
+	second := createRandomSkill(t)
+	unrelated := createRandomSkill(t)
+	for _, link := range []AddSkillToUserParams{
+		{UserID: engineer.ID, SkillID: first.ID, Proficiency: ProficiencyLevelExpert},
+		{UserID: engineer.ID, SkillID: second.ID, Proficiency: ProficiencyLevelBeginner},
+		{UserID: engineer.ID, SkillID: unrelated.ID, Proficiency: ProficiencyLevelExpert},
+		{UserID: manager.ID, SkillID: first.ID, Proficiency: ProficiencyLevelExpert},
+		{UserID: outsider.ID, SkillID: first.ID, Proficiency: ProficiencyLevelExpert},
+	} {
+		_, err := testQueries.AddSkillToUser(context.Background(), link)
+		require.NoError(t, err)
+	}
+
+	// 2. Execute: Match the team against both skills.
+	matches, err := testQueries.GetTeamMembersWithSkillMatch(context.Background(), GetTeamMembersWithSkillMatchParams{
+		TeamID:   teamID,
+		SkillIDs: []int64{first.ID, second.ID},
+	})
+	require.NoError(t, err)
+
+	// 3. Verify: One row per matching skill of the engineer, in skill order.
+	require.Len(t, matches, 2)
+	for _, match := range matches {
+		require.Equal(t, engineer.ID, match.UserID)
+	}
+	require.Equal(t, first.ID, matches[0].SkillID)
+	require.Equal(t, ProficiencyLevelExpert, matches[0].Proficiency)
+	require.Equal(t, second.ID, matches[1].SkillID)
+	require.Equal(t, ProficiencyLevelBeginner, matches[1].Proficiency)
+}
@@ -144,6 +144,19 @@ func TestListUsers(t *testing.T) {
 
 ////////////////////////////////////////////////////////////////////////
 
+func TestListUsersByIDs(t *testing.T) {
+	user1, _ := createRandomUser(t)
+	user2, _ := createRandomUser(t)
+
+	// Unknown IDs are skipped rather than failing the query
+	users, err := testQueries.ListUsersByIDs(context.Background(), []int64{user1.ID, user2.ID, user2.ID + 1000000})
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	require.ElementsMatch(t, []int64{user1.ID, user2.ID}, []int64{users[0].ID, users[1].ID})
+}
+
+////////////////////////////////////////////////////////////////////////
+
 func TestListUsersByTeam(t *testing.T) {
 	team := createRandomTeam(t)
 
@@ -190,3 +203,94 @@ func TestListUsersByTeam(t *testing.T) {
 		require.Equal(t, team.ID, user.TeamID.Int64)
 	}
 }
+
+////////////////////////////////////////////////////////////////////////
+
+func TestSearchUsersByTeam(t *testing.T) {
+	team := createRandomTeam(t)
+
+	// Create 2 engineers and 1 manager in the team
+	for _, role := range []UserRole{UserRoleEngineer, UserRoleEngineer, UserRoleManager} {
+		_, err := testQueries.CreateUser(context.Background(), CreateUserParams{
+			Name:         pgtype.Text{String: util.RandomName(), Valid: true},
+			Email:        util.RandomEmail(),
+			PasswordHash: util.RandomString(20),
+			Role:         role,
+			TeamID:       pgtype.Int8{Int64: team.ID, Valid: true},
+		})
+		require.NoError(t, err)
+	}
+	teamFilter := pgtype.Int8{Int64: team.ID, Valid: true}
+
+	// Filter by team only
+	users, err := testQueries.SearchUsers(context.Background(), SearchUsersParams{
+		TeamID: teamFilter,
+		Limit:  10,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, users, 3)
+	for _, user := range users {
+		require.Equal(t, team.ID, user.TeamID.Int64)
+		require.Equal(t, team.TeamName, user.TeamName.String)
+	}
+
+	count, err := testQueries.CountSearchUsers(context.Background(), CountSearchUsersParams{TeamID: teamFilter})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), count)
+
+	// Combine team and role filters
+	roleFilter := NullUserRole{UserRole: UserRoleManager, Valid: true}
+	managers, err := testQueries.SearchUsers(context.Background(), SearchUsersParams{
+		Role:   roleFilter,
+		TeamID: teamFilter,
+		Limit:  10,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, managers, 1)
+	require.Equal(t, UserRoleManager, managers[0].Role)
+
+	count, err = testQueries.CountSearchUsers(context.Background(), CountSearchUsersParams{
+		Role:   roleFilter,
+		TeamID: teamFilter,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
+
+func TestSearchUsersWithoutTeam(t *testing.T) {
+	unassigned, _ := createRandomUserWithRoleAndNoTeam(t, UserRoleManager)
+	assigned, _ := createRandomUser(t)
+
+	withoutTeam := pgtype.Bool{Bool: false, Valid: true}
+
+	// The unassigned manager is returned when filtering for users without a team
+	users, err := testQueries.SearchUsers(context.Background(), SearchUsersParams{
+		Search:  unassigned.Email,
+		HasTeam: withoutTeam,
+		Limit:   10,
+		Offset:  0,
+	})
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	require.Equal(t, unassigned.ID, users[0].ID)
+	require.False(t, users[0].TeamID.Valid)
+
+	count, err := testQueries.CountSearchUsers(context.Background(), CountSearchUsersParams{
+		Search:  unassigned.Email,
+		HasTeam: withoutTeam,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	// A user with a team is excluded by the same filter
+	users, err = testQueries.SearchUsers(context.Background(), SearchUsersParams{
+		Search:  assigned.Email,
+		HasTeam: withoutTeam,
+		Limit:   10,
+		Offset:  0,
+	})
+	require.NoError(t, err)
+	require.Empty(t, users)
+}
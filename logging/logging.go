@@ -0,0 +1,77 @@
+// Package logging builds the application's leveled, structured logger on top of log/slog.
+// Attributes that may carry secrets or personal data are redacted before they are written.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// redacted replaces the value of any sensitive attribute
+const redacted = "[REDACTED]"
+
+// sensitiveKeyParts marks an attribute as secret when its key contains any of them, e.g. "invitation_token"
+var sensitiveKeyParts = []string{"token", "password", "secret", "api_key", "authorization"}
+
+// ParseLevel maps a LOG_LEVEL setting (debug, info, warn or error, case-insensitive) to a slog level.
+// An empty setting means info.
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("invalid log level %q, must be one of debug, info, warn, error", s)
+	}
+}
+
+// New creates a JSON logger writing to w at the given level, with sensitive attributes redacted.
+func New(w io.Writer, level string) (*slog.Logger, error) {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       lvl,
+		ReplaceAttr: redact,
+	})
+	return slog.New(handler), nil
+}
+
+// redact hides secret attributes entirely and masks email addresses down to their domain.
+// Keys ending in "_id" name database IDs, such as "token_id", and are kept.
+func redact(groups []string, attr slog.Attr) slog.Attr {
+	key := strings.ToLower(attr.Key)
+	if strings.HasSuffix(key, "_id") {
+		return attr
+	}
+
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(key, part) {
+			return slog.String(attr.Key, redacted)
+		}
+	}
+
+	if strings.Contains(key, "email") {
+		return slog.String(attr.Key, MaskEmail(attr.Value.String()))
+	}
+
+	return attr
+}
+
+// MaskEmail keeps only the first character of the local part, e.g. "jane@example.com" becomes "j***@example.com".
+func MaskEmail(email string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || local == "" {
+		return redacted
+	}
+	return local[:1] + "***@" + domain
+}
@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLevel(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected slog.Level
+	}{
+		{"", slog.LevelInfo},
+		{"info", slog.LevelInfo},
+		{"DEBUG", slog.LevelDebug},
+		{"warn", slog.LevelWarn},
+		{"warning", slog.LevelWarn},
+		{" error ", slog.LevelError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			level, err := ParseLevel(tc.input)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, level)
+		})
+	}
+
+	_, err := ParseLevel("verbose")
+	require.Error(t, err)
+}
+
+func TestNewFiltersByLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "info")
+	require.NoError(t, err)
+
+	logger.Debug("hidden")
+	require.Empty(t, buf.String())
+
+	logger.Info("shown")
+	require.Contains(t, buf.String(), `"msg":"shown"`)
+}
+
+func TestNewRedactsSensitiveAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "debug")
+	require.NoError(t, err)
+
+	logger.Info("invitation created",
+		"invitation_token", "3f2a-secret",
+		"new_password", "hunter22",
+		"email", "jane.doe@example.com",
+		"user_id", 42,
+		"token_id", 7,
+	)
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	require.Equal(t, redacted, entry["invitation_token"])
+	require.Equal(t, redacted, entry["new_password"])
+	require.Equal(t, "j***@example.com", entry["email"])
+	require.Equal(t, float64(42), entry["user_id"])
+	require.Equal(t, float64(7), entry["token_id"])
+	require.NotContains(t, buf.String(), "3f2a-secret")
+}
+
+func TestMaskEmail(t *testing.T) {
+	require.Equal(t, "j***@example.com", MaskEmail("jane@example.com"))
+	require.Equal(t, redacted, MaskEmail("not-an-email"))
+	require.Equal(t, redacted, MaskEmail("@example.com"))
+}
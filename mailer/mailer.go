@@ -0,0 +1,98 @@
+// Package mailer sends the application's outgoing email.
+// SMTPEmailer delivers through an SMTP relay; NoopEmailer only logs, for tests and local development.
+package mailer
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Emailer sends a plain-text email to a single recipient
+type Emailer interface {
+	SendEmail(to, subject, body string) error
+}
+
+// errHeaderInjection rejects header values that would start a new header line
+var errHeaderInjection = errors.New("email header contains a line break")
+
+////////////////////////////////////////////////////////////////////////
+// SMTP Emailer
+////////////////////////////////////////////////////////////////////////
+
+// SMTPEmailer sends email through an SMTP server, authenticating with PLAIN auth when a username is set.
+// net/smtp upgrades the connection with STARTTLS whenever the server offers it.
+type SMTPEmailer struct {
+	addr     string
+	host     string
+	from     string
+	username string
+	password string
+}
+
+// NewSMTPEmailer creates an emailer for the SMTP server at host:port, sending from the given address
+func NewSMTPEmailer(host string, port int, from, username, password string) *SMTPEmailer {
+	return &SMTPEmailer{
+		addr:     net.JoinHostPort(host, strconv.Itoa(port)),
+		host:     host,
+		from:     from,
+		username: username,
+		password: password,
+	}
+}
+
+// SendEmail implements Emailer
+func (e *SMTPEmailer) SendEmail(to, subject, body string) error {
+	msg, err := buildMessage(e.from, to, subject, body, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if e.username != "" {
+		auth = smtp.PlainAuth("", e.username, e.password, e.host)
+	}
+
+	if err := smtp.SendMail(e.addr, auth, e.from, []string{to}, msg); err != nil {
+		return fmt.Errorf("failed to send email via %s: %w", e.addr, err)
+	}
+	return nil
+}
+
+// buildMessage formats an RFC 5322 plain-text message with CRLF line endings
+func buildMessage(from, to, subject, body string, date time.Time) ([]byte, error) {
+	for _, value := range []string{from, to, subject} {
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, errHeaderInjection
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(b.String()), nil
+}
+
+////////////////////////////////////////////////////////////////////////
+// No-op Emailer
+////////////////////////////////////////////////////////////////////////
+
+// NoopEmailer logs that an email would have been sent, without its body, and sends nothing
+type NoopEmailer struct{}
+
+// SendEmail implements Emailer
+func (NoopEmailer) SendEmail(to, subject, body string) error {
+	slog.Info("Email sending is not configured; skipping email", "email", to, "subject", subject)
+	return nil
+}
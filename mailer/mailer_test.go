@@ -0,0 +1,26 @@
+package mailer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildMessage(t *testing.T) {
+	date := time.Date(2025, time.March, 4, 10, 0, 0, 0, time.UTC)
+
+	msg, err := buildMessage("noreply@synapse.dev", "jane@example.com", "You're invited", "Hello\nAccept here", date)
+	require.NoError(t, err)
+	require.Equal(t, "From: noreply@synapse.dev\r\n"+
+		"To: jane@example.com\r\n"+
+		"Subject: You're invited\r\n"+
+		"Date: Tue, 04 Mar 2025 10:00:00 +0000\r\n"+
+		"MIME-Version: 1.0\r\n"+
+		"Content-Type: text/plain; charset=UTF-8\r\n"+
+		"\r\n"+
+		"Hello\r\nAccept here", string(msg))
+
+	_, err = buildMessage("noreply@synapse.dev", "jane@example.com\r\nBcc: eve@example.com", "Hi", "", date)
+	require.ErrorIs(t, err, errHeaderInjection)
+}
@@ -1,23 +1,40 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"log"
-	"net/http"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/pranav244872/synapse/api"
 	"github.com/pranav244872/synapse/config"
 	db "github.com/pranav244872/synapse/db/sqlc"
+	"github.com/pranav244872/synapse/logging"
 	"github.com/pranav244872/synapse/skillz"
+	"github.com/pranav244872/synapse/util"
 )
 
+// shutdownTimeout bounds how long in-flight requests get to finish once a stop signal arrives
+const shutdownTimeout = 30 * time.Second
+
 func main() {
 	// Step 1: Load configuration
 	cfg, err := config.LoadConfig(".")
 	if err != nil {
 		log.Fatalf("❌ could not load configuration: %v", err)
 	}
+
+	// Route all logging, including the standard log package, through the leveled, redacting logger
+	logger, err := logging.New(os.Stdout, cfg.LogLevel)
+	if err != nil {
+		log.Fatalf("❌ could not create the logger: %v", err)
+	}
+	slog.SetDefault(logger)
 	log.Println("✅ Configuration loaded successfully.")
 
 	// Step 2: Establish database connection pool
@@ -30,25 +47,33 @@ func main() {
 
 	// Step 3: Initialize the database store
 	store := db.NewStore(connPool)
+	store.SetTxTimeout(cmp.Or(cfg.DBTxTimeout, db.DefaultTxTimeout))
 
-	// Step 4: Load skill aliases from the database to build the alias map
+	// Step 4: Load skill aliases from the database into the alias store.
+	// The store is reloaded later whenever admins change aliases.
 	log.Println("🔄 Loading skill aliases from the database...")
-	aliasRows, err := store.GetAllSkillAliases(context.Background())
+	aliases := skillz.NewAliasStore(store)
+	aliasCount, err := aliases.Reload(context.Background())
 	if err != nil {
 		// It's a fatal error because the skillz processor depends on it.
 		log.Fatalf("❌ could not load skill aliases: %v", err)
 	}
+	log.Printf("✅ Loaded %d skill aliases.", aliasCount)
 
-	aliasMap := make(map[string]string)
-	for _, row := range aliasRows {
-		aliasMap[row.AliasName] = row.CanonicalName
+	// Step 5: Initialize the skill processing service with the configured LLM and the loaded aliases
+	// One HTTP client for every LLM call, so connections to the provider are reused
+	llmHTTPClient := util.NewHTTPClient(cmp.Or(cfg.LLMTimeout, skillz.DefaultTimeout))
+	var llmClient skillz.LLMClient
+	switch cfg.LLMProvider {
+	case "", skillz.ProviderGemini:
+		llmClient = skillz.NewGeminiLLMClient(cfg.GeminiAPIKey, cfg.GeminiAPIURL, llmHTTPClient)
+	case skillz.ProviderOpenAI:
+		llmClient = skillz.NewOpenAILLMClient(cfg.OpenAIAPIKey, cfg.OpenAIBaseURL, cfg.OpenAIModel, llmHTTPClient)
+	default:
+		log.Fatalf("❌ unknown LLM_PROVIDER %q: use %q or %q", cfg.LLMProvider, skillz.ProviderGemini, skillz.ProviderOpenAI)
 	}
-	log.Printf("✅ Loaded %d skill aliases.", len(aliasMap))
-
-	// Step 5: Initialize the skill processing service with the loaded aliases
-	geminiClient := skillz.NewGeminiLLMClient(cfg.GeminiAPIKey, cfg.GeminiAPIURL, &http.Client{})
-	skillzProcessor := skillz.NewLLMProcessor(aliasMap, geminiClient)
-	log.Println("✅ Skillz processor (Gemini) initialized.")
+	skillzProcessor := skillz.NewLLMProcessor(aliases, llmClient)
+	log.Printf("✅ Skillz processor initialized (provider: %s).", cmp.Or(cfg.LLMProvider, skillz.ProviderGemini))
 
 	// Step 6: Create a new API server instance
 	server, err := api.NewServer(cfg, store, skillzProcessor)
@@ -57,9 +82,33 @@ func main() {
 	}
 	log.Println("✅ API server created.")
 
-	// Step 7: Start the HTTP server
-	log.Printf("🚀 Starting server on %s", cfg.ServerAddress)
-	if err := server.Start(cfg.ServerAddress); err != nil {
-		log.Fatalf("❌ failed to start server: %v", err)
+	// Step 7: Start the HTTP server, and stop it gracefully on SIGINT or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("🚀 Starting server on %s", cfg.ServerAddress)
+		serverErr <- server.Start(cfg.ServerAddress)
+	}()
+
+	select {
+	case err := <-serverErr:
+		if err != nil {
+			log.Fatalf("❌ failed to start server: %v", err)
+		}
+	case <-ctx.Done():
+		// A second signal kills the process instead of waiting for the shutdown
+		stop()
+		log.Printf("🛑 Shutting down, waiting up to %s for in-flight requests...", shutdownTimeout)
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("❌ graceful shutdown did not finish: %v", err)
+		}
+		log.Println("✅ Server stopped.")
 	}
+
+	// The deferred connPool.Close runs now that no request or background job uses it
 }
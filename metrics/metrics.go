@@ -0,0 +1,106 @@
+// Package metrics defines the application's Prometheus collectors.
+// Collectors always record, but nothing is exported until Register adds them to a registry,
+// which the API server only does when METRICS_ENABLED is set.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const namespace = "synapse"
+
+// Outcomes of an LLM call; every LLM_PROVIDER client reports the same ones
+const (
+	LLMOutcomeSuccess    = "success"
+	LLMOutcomeParseError = "parse_error" // The model answered, but its response could not be read
+	LLMOutcomeHTTPError  = "http_error"  // The request failed or the provider returned a non-200 status
+)
+
+// Outcomes of a recommender call
+const (
+	RecommenderOutcomeSuccess     = "success"
+	RecommenderOutcomeUnavailable = "unavailable" // The service could not be reached
+	RecommenderOutcomeHTTPError   = "http_error"  // The service returned a non-200 status
+	RecommenderOutcomeParseError  = "parse_error" // The response body was not valid JSON
+)
+
+var (
+	// HTTPRequests counts handled requests by method, route pattern and status code
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests handled, by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestDuration observes request latency by method, route pattern and status code
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency, by method, route and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// LLMCalls counts calls to the LLM provider by outcome
+	LLMCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "llm_calls_total",
+		Help:      "LLM calls, by outcome.",
+	}, []string{"outcome"})
+
+	// LLMCallDuration observes LLM provider call latency by outcome. LLM calls take seconds, so the buckets reach further than the HTTP ones.
+	LLMCallDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "llm_call_duration_seconds",
+		Help:      "LLM call latency, by outcome.",
+		Buckets:   []float64{0.25, 0.5, 1, 2, 4, 8, 16, 32, 64},
+	}, []string{"outcome"})
+
+	// RecommenderCalls counts recommender service calls by outcome
+	RecommenderCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "recommender_calls_total",
+		Help:      "Recommender service calls, by outcome.",
+	}, []string{"outcome"})
+
+	// RecommenderCallDuration observes recommender service latency by outcome
+	RecommenderCallDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "recommender_call_duration_seconds",
+		Help:      "Recommender service call latency, by outcome.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"outcome"})
+)
+
+// Register adds the application collectors, plus the Go runtime and process collectors, to registry
+func Register(registry prometheus.Registerer) error {
+	for _, collector := range []prometheus.Collector{
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		LLMCalls,
+		LLMCallDuration,
+		RecommenderCalls,
+		RecommenderCallDuration,
+	} {
+		if err := registry.Register(collector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ObserveLLMCall records one LLM call that started at start
+func ObserveLLMCall(outcome string, start time.Time) {
+	LLMCalls.WithLabelValues(outcome).Inc()
+	LLMCallDuration.WithLabelValues(outcome).Observe(time.Since(start).Seconds())
+}
+
+// ObserveRecommenderCall records one recommender call that started at start
+func ObserveRecommenderCall(outcome string, start time.Time) {
+	RecommenderCalls.WithLabelValues(outcome).Inc()
+	RecommenderCallDuration.WithLabelValues(outcome).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,129 @@
+// skillmatch/service.go
+package skillmatch
+
+import (
+	"context"
+	"slices"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pranav244872/synapse/db/sqlc"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Store Definition
+////////////////////////////////////////////////////////////////////////
+
+// Store is the subset of database queries the service relies on.
+// *db.Store satisfies it; tests can pass an in-memory fake instead.
+type Store interface {
+	ListEngineersForSkills(ctx context.Context, arg db.ListEngineersForSkillsParams) ([]db.ListEngineersForSkillsRow, error)
+	GetSkillsForUser(ctx context.Context, userID int64) ([]db.GetSkillsForUserRow, error)
+	ListMatchingOpenTasksForEngineer(ctx context.Context, arg db.ListMatchingOpenTasksForEngineerParams) ([]db.ListMatchingOpenTasksForEngineerRow, error)
+	GetCoOccurringSkills(ctx context.Context, arg db.GetCoOccurringSkillsParams) ([]db.GetCoOccurringSkillsRow, error)
+}
+
+////////////////////////////////////////////////////////////////////////
+// Struct and Constructor
+////////////////////////////////////////////////////////////////////////
+
+// DefaultLimit is used by the ranked lookups when the caller passes no limit
+const DefaultLimit int32 = 20
+
+// Service answers the "who fits which skills" questions shared by the recommendation,
+// matching and skill insight endpoints, so handlers do not each hand-roll the joins.
+type Service struct {
+	store Store
+}
+
+// NewService creates a Service backed by the given store.
+func NewService(store Store) *Service {
+	return &Service{store: store}
+}
+
+////////////////////////////////////////////////////////////////////////
+// Public Methods
+////////////////////////////////////////////////////////////////////////
+
+// EngineersForSkills ranks the team's engineers by how many of the skills they have,
+// then by proficiency. Duplicate skill IDs are ignored; no skills means no engineers.
+func (s *Service) EngineersForSkills(ctx context.Context, teamID int64, skillIDs []int64) ([]db.ListEngineersForSkillsRow, error) {
+	skillIDs = uniqueIDs(skillIDs)
+	if len(skillIDs) == 0 {
+		return []db.ListEngineersForSkillsRow{}, nil
+	}
+
+	engineers, err := s.store.ListEngineersForSkills(ctx, db.ListEngineersForSkillsParams{
+		TeamID:   pgtype.Int8{Int64: teamID, Valid: true},
+		SkillIDs: skillIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return emptyIfNil(engineers), nil
+}
+
+// SkillsForUser lists the user's skills with their proficiency.
+func (s *Service) SkillsForUser(ctx context.Context, userID int64) ([]db.GetSkillsForUserRow, error) {
+	skills, err := s.store.GetSkillsForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return emptyIfNil(skills), nil
+}
+
+// TasksForEngineer ranks the team's open, unassigned tasks by how well they fit the engineer's skills.
+func (s *Service) TasksForEngineer(ctx context.Context, engineerID, teamID int64, limit int32) ([]db.ListMatchingOpenTasksForEngineerRow, error) {
+	tasks, err := s.store.ListMatchingOpenTasksForEngineer(ctx, db.ListMatchingOpenTasksForEngineerParams{
+		EngineerID:  engineerID,
+		TeamID:      teamID,
+		ResultLimit: limitOrDefault(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return emptyIfNil(tasks), nil
+}
+
+// RelatedSkills lists the skills most often required together with the given skill.
+func (s *Service) RelatedSkills(ctx context.Context, skillID int64, limit int32) ([]db.GetCoOccurringSkillsRow, error) {
+	related, err := s.store.GetCoOccurringSkills(ctx, db.GetCoOccurringSkillsParams{
+		SkillID:     skillID,
+		ResultLimit: limitOrDefault(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return emptyIfNil(related), nil
+}
+
+////////////////////////////////////////////////////////////////////////
+// Private Helpers
+////////////////////////////////////////////////////////////////////////
+
+// uniqueIDs returns the positive IDs in ascending order without duplicates
+func uniqueIDs(ids []int64) []int64 {
+	unique := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if id > 0 {
+			unique = append(unique, id)
+		}
+	}
+	slices.Sort(unique)
+	return slices.Compact(unique)
+}
+
+// limitOrDefault replaces a missing or negative limit with DefaultLimit
+func limitOrDefault(limit int32) int32 {
+	if limit <= 0 {
+		return DefaultLimit
+	}
+	return limit
+}
+
+// emptyIfNil makes sure callers always get a JSON array, even for no results
+func emptyIfNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
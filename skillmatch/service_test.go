@@ -0,0 +1,155 @@
+// skillmatch/service_test.go
+package skillmatch_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/pranav244872/synapse/db/sqlc"
+	"github.com/pranav244872/synapse/skillmatch"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStore is an in-memory Store that returns canned rows. The ranking itself is
+// the query's job and is covered by the db package's tests; the fake only records
+// the arguments it was called with so tests can check what the service sent.
+type fakeStore struct {
+	engineers  []db.ListEngineersForSkillsRow
+	userSkills map[int64][]db.GetSkillsForUserRow // Keyed by user ID
+	err        error
+
+	calls           int
+	lastTeamID      pgtype.Int8
+	lastSkillIDs    []int64
+	lastResultLimit int32
+}
+
+func newSeededStore() *fakeStore {
+	return &fakeStore{
+		engineers: []db.ListEngineersForSkillsRow{
+			{ID: 1, Email: "ada@example.com", MatchingSkillCount: 2, ProficiencyScore: 4},
+			{ID: 2, Email: "bob@example.com", MatchingSkillCount: 1, ProficiencyScore: 1},
+		},
+		userSkills: map[int64][]db.GetSkillsForUserRow{
+			1: {{ID: 100, SkillName: "Go", Proficiency: db.ProficiencyLevelExpert}, {ID: 200, SkillName: "SQL", Proficiency: db.ProficiencyLevelBeginner}},
+		},
+	}
+}
+
+func (f *fakeStore) ListEngineersForSkills(ctx context.Context, arg db.ListEngineersForSkillsParams) ([]db.ListEngineersForSkillsRow, error) {
+	f.calls++
+	f.lastTeamID = arg.TeamID
+	f.lastSkillIDs = arg.SkillIDs
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.engineers, nil
+}
+
+func (f *fakeStore) GetSkillsForUser(ctx context.Context, userID int64) ([]db.GetSkillsForUserRow, error) {
+	f.calls++
+	return f.userSkills[userID], f.err
+}
+
+func (f *fakeStore) ListMatchingOpenTasksForEngineer(ctx context.Context, arg db.ListMatchingOpenTasksForEngineerParams) ([]db.ListMatchingOpenTasksForEngineerRow, error) {
+	f.calls++
+	f.lastResultLimit = arg.ResultLimit
+	return nil, f.err
+}
+
+func (f *fakeStore) GetCoOccurringSkills(ctx context.Context, arg db.GetCoOccurringSkillsParams) ([]db.GetCoOccurringSkillsRow, error) {
+	f.calls++
+	f.lastResultLimit = arg.ResultLimit
+	return nil, f.err
+}
+
+func TestEngineersForSkills(t *testing.T) {
+	t.Run("The team and skills are passed to the query", func(t *testing.T) {
+		store := newSeededStore()
+		service := skillmatch.NewService(store)
+
+		engineers, err := service.EngineersForSkills(context.Background(), 10, []int64{100, 200})
+		require.NoError(t, err)
+
+		require.Equal(t, pgtype.Int8{Int64: 10, Valid: true}, store.lastTeamID)
+		require.Equal(t, []int64{100, 200}, store.lastSkillIDs)
+		require.Equal(t, store.engineers, engineers) // The query's order is kept
+	})
+
+	t.Run("Duplicate and invalid skill IDs are dropped", func(t *testing.T) {
+		store := newSeededStore()
+		service := skillmatch.NewService(store)
+
+		_, err := service.EngineersForSkills(context.Background(), 10, []int64{200, 100, 200, 0, -1})
+		require.NoError(t, err)
+
+		require.Equal(t, []int64{100, 200}, store.lastSkillIDs)
+	})
+
+	t.Run("No matches returns an empty list rather than nil", func(t *testing.T) {
+		store := newSeededStore()
+		store.engineers = nil
+		service := skillmatch.NewService(store)
+
+		engineers, err := service.EngineersForSkills(context.Background(), 10, []int64{100})
+		require.NoError(t, err)
+
+		require.NotNil(t, engineers)
+		require.Empty(t, engineers)
+	})
+
+	t.Run("No skills returns an empty list without querying", func(t *testing.T) {
+		store := newSeededStore()
+		service := skillmatch.NewService(store)
+
+		engineers, err := service.EngineersForSkills(context.Background(), 10, nil)
+		require.NoError(t, err)
+
+		require.NotNil(t, engineers)
+		require.Empty(t, engineers)
+		require.Zero(t, store.calls)
+	})
+
+	t.Run("Store errors are returned", func(t *testing.T) {
+		store := newSeededStore()
+		store.err = errors.New("connection refused")
+		service := skillmatch.NewService(store)
+
+		_, err := service.EngineersForSkills(context.Background(), 10, []int64{100})
+		require.ErrorIs(t, err, store.err)
+	})
+}
+
+func TestSkillsForUser(t *testing.T) {
+	store := newSeededStore()
+	service := skillmatch.NewService(store)
+
+	skills, err := service.SkillsForUser(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, skills, 2)
+	require.Equal(t, "Go", skills[0].SkillName)
+	require.Equal(t, db.ProficiencyLevelExpert, skills[0].Proficiency)
+
+	// A user without skills gets an empty list rather than nil
+	skills, err = service.SkillsForUser(context.Background(), 99)
+	require.NoError(t, err)
+	require.NotNil(t, skills)
+	require.Empty(t, skills)
+}
+
+func TestRankedLookupsDefaultLimit(t *testing.T) {
+	store := newSeededStore()
+	service := skillmatch.NewService(store)
+
+	tasks, err := service.TasksForEngineer(context.Background(), 1, 10, 0)
+	require.NoError(t, err)
+	require.NotNil(t, tasks)
+	require.Equal(t, skillmatch.DefaultLimit, store.lastResultLimit)
+
+	related, err := service.RelatedSkills(context.Background(), 100, 5)
+	require.NoError(t, err)
+	require.NotNil(t, related)
+	require.Equal(t, int32(5), store.lastResultLimit)
+}
@@ -0,0 +1,73 @@
+// skillz/alias_store.go
+package skillz
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	db "github.com/pranav244872/synapse/db/sqlc"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Alias Store
+////////////////////////////////////////////////////////////////////////
+
+// AliasSource loads every skill alias together with its canonical skill name.
+// The database store satisfies it through GetAllSkillAliases.
+type AliasSource interface {
+	GetAllSkillAliases(ctx context.Context) ([]db.GetAllSkillAliasesRow, error)
+}
+
+// AliasStore holds the alias map used for normalizing skills. It can be reloaded from
+// its source while requests are being served, so new aliases apply without a restart.
+type AliasStore struct {
+	mu       sync.RWMutex
+	aliasMap map[string]string // Lowercase alias -> canonical skill name
+	source   AliasSource
+}
+
+// NewAliasStore creates an empty AliasStore backed by source. Call Reload to fill it.
+func NewAliasStore(source AliasSource) *AliasStore {
+	return &AliasStore{
+		aliasMap: map[string]string{},
+		source:   source,
+	}
+}
+
+// Reload rebuilds the alias map from the source and returns how many aliases it holds.
+// On error the previous map stays in place.
+func (s *AliasStore) Reload(ctx context.Context) (int, error) {
+	if s.source == nil {
+		return 0, errors.New("alias store has no source to reload from")
+	}
+
+	rows, err := s.source.GetAllSkillAliases(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	aliasMap := make(map[string]string, len(rows))
+	for _, row := range rows {
+		aliasMap[row.AliasName] = row.CanonicalName
+	}
+
+	s.Set(aliasMap)
+	return len(aliasMap), nil
+}
+
+// Set replaces the alias map outright, for callers that build it themselves.
+// The map must not be modified afterwards.
+func (s *AliasStore) Set(aliasMap map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.aliasMap = aliasMap
+}
+
+// snapshot returns the current alias map. Maps are swapped, never modified,
+// so the result can be read without holding the lock.
+func (s *AliasStore) snapshot() map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.aliasMap
+}
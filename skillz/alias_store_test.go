@@ -0,0 +1,129 @@
+// skillz/alias_store_test.go
+package skillz_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	db "github.com/pranav244872/synapse/db/sqlc"
+	"github.com/pranav244872/synapse/skillz"
+)
+
+// mockAliasSource serves a fixed set of aliases, standing in for the database
+type mockAliasSource struct {
+	mu   sync.Mutex
+	rows []db.GetAllSkillAliasesRow
+	err  error
+}
+
+func (m *mockAliasSource) GetAllSkillAliases(ctx context.Context) ([]db.GetAllSkillAliasesRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.rows, m.err
+}
+
+func (m *mockAliasSource) add(alias, canonical string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rows = append(m.rows, db.GetAllSkillAliasesRow{AliasName: alias, CanonicalName: canonical})
+}
+
+////////////////////////////////////////////////////////////////////////
+// Test for AliasStore.Reload
+////////////////////////////////////////////////////////////////////////
+
+func TestAliasStore_Reload(t *testing.T) {
+	source := &mockAliasSource{}
+	source.add("golang", "Go")
+
+	aliases := skillz.NewAliasStore(source)
+	p := skillz.NewLLMProcessor(aliases, &mockLLMClient{mockResponse: `["golang", "k8s"]`})
+
+	count, err := aliases.Reload(context.Background())
+	if err != nil {
+		t.Fatalf("Reload() returned unexpected error: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Reload() count = %d, want 1", count)
+	}
+
+	got, err := p.ExtractAndNormalize(context.Background(), "text")
+	if err != nil {
+		t.Fatalf("ExtractAndNormalize() returned unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(stringSliceToMap(got), stringSliceToMap([]string{"Go", "K8s"})) {
+		t.Errorf("before reload got %v, want [Go K8s]", got)
+	}
+
+	// An alias added after startup applies once the store is reloaded
+	source.add("k8s", "Kubernetes")
+	if _, err := p.(skillz.AliasReloader).ReloadAliases(context.Background()); err != nil {
+		t.Fatalf("ReloadAliases() returned unexpected error: %v", err)
+	}
+
+	got, err = p.ExtractAndNormalize(context.Background(), "text")
+	if err != nil {
+		t.Fatalf("ExtractAndNormalize() returned unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(stringSliceToMap(got), stringSliceToMap([]string{"Go", "Kubernetes"})) {
+		t.Errorf("after reload got %v, want [Go Kubernetes]", got)
+	}
+
+	// A failed reload keeps the aliases already loaded
+	source.err = errors.New("database is down")
+	if _, err := aliases.Reload(context.Background()); err == nil {
+		t.Fatal("Reload() expected an error, got nil")
+	}
+	got, _ = p.ExtractAndNormalize(context.Background(), "text")
+	if !reflect.DeepEqual(stringSliceToMap(got), stringSliceToMap([]string{"Go", "Kubernetes"})) {
+		t.Errorf("after failed reload got %v, want [Go Kubernetes]", got)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////
+// Test for concurrent reloads
+////////////////////////////////////////////////////////////////////////
+
+func TestAliasStore_ReloadWhileNormalizing(t *testing.T) {
+	source := &mockAliasSource{}
+	source.add("golang", "Go")
+
+	aliases := skillz.NewAliasStore(source)
+	if _, err := aliases.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload() returned unexpected error: %v", err)
+	}
+	p := skillz.NewLLMProcessor(aliases, &mockLLMClient{mockResponse: `["golang"]`})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if _, err := aliases.Reload(context.Background()); err != nil {
+					t.Errorf("Reload() returned unexpected error: %v", err)
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				got, err := p.ExtractAndNormalize(context.Background(), "text")
+				if err != nil {
+					t.Errorf("ExtractAndNormalize() returned unexpected error: %v", err)
+					return
+				}
+				// Every reload sees the same alias, so the result never changes
+				if len(got) != 1 || got[0] != "Go" {
+					t.Errorf("ExtractAndNormalize() = %v, want [Go]", got)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -3,7 +3,7 @@ package skillz
 
 import (
 	"bytes"
-	"regexp"
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
@@ -12,7 +12,11 @@ import (
 	"golang.org/x/text/language"
 	"io"
 	"net/http"
+	"slices"
 	"strings"
+	"time"
+
+	"github.com/pranav244872/synapse/metrics"
 )
 
 ////////////////////////////////////////////////////////////////////////
@@ -24,7 +28,26 @@ const (
 	// It explicitly asks for a raw, non-standardized JSON array.
 	skillExtractionPrompt = `
 Your goal is to act as an expert CTO and extract a list of underlying, reusable technical capabilities from the text below.
+` + skillExtractionRules + `5.  Return the result as a single, flat JSON array of strings.
+
+Text: """
+%s
+"""`
+
+	// weightedSkillExtractionPrompt extracts a task's skills like skillExtractionPrompt,
+	// but also asks how central each skill is, as one of the skillImportanceWeights levels.
+	weightedSkillExtractionPrompt = `
+Your goal is to act as an expert CTO and extract the underlying, reusable technical capabilities a task requires from its description below.
+` + skillExtractionRules + `5.  Rate how central each skill is to the task: "core" if the task cannot be done without it,
+    "supporting" if it is clearly needed but secondary, "peripheral" if it only helps at the edges.
+6.  Return the result as a single JSON object mapping each skill to its rating, e.g. {"React Hooks": "core", "CSS Flexbox": "supporting"}.
 
+Task description: """
+%s
+"""`
+
+	// skillExtractionRules are shared by the skill extraction prompts
+	skillExtractionRules = `
 RULES:
 1.  Extract specific, granular skills. Prefer multi-word skills that describe a capability over generic single-word terms.
     - GOOD: "React Hooks", "PostgreSQL Performance Tuning", "Docker Multi-stage Builds"
@@ -35,11 +58,7 @@ RULES:
     - INCORRECT SKILLS: ["Fixing login button", "Firefox alignment issue"]
 3.  Identify programming languages, frameworks, libraries, databases, cloud services, tools, and technical concepts.
 4.  Do NOT standardize or normalize aliases (e.g., if you see 'go' and 'golang', extract both).
-5.  Return the result as a single, flat JSON array of strings.
-
-Text: """
-%s
-"""`
+`
 
 	// proficiencyExtractionPrompt is used to estimate a user's proficiency for a given list of skills.
 	// It instructs the model to return a JSON object mapping each skill to a specific proficiency level.
@@ -55,6 +74,17 @@ Resume Text: """
 """`
 )
 
+// skillImportanceWeights turns the importance the LLM rates a task's skill with into its weight
+var skillImportanceWeights = map[string]float64{
+	"core":       1.0,
+	"supporting": 0.6,
+	"peripheral": 0.3,
+}
+
+// DefaultSkillWeight is given to skills the LLM rates with no known importance.
+// It matches how every required skill counted before skills were weighted.
+const DefaultSkillWeight = 1.0
+
 ////////////////////////////////////////////////////////////////////////
 
 // Anything with CallLLM method can act as a LLMClient
@@ -63,6 +93,10 @@ type LLMClient interface {
 	CallLLM(ctx context.Context, prompt string) (string, error)
 }
 
+// DefaultTimeout limits one LLM call when no timeout is configured.
+// Extraction prompts over long resumes can take tens of seconds to answer.
+const DefaultTimeout = 60 * time.Second
+
 ////////////////////////////////////////////////////////////////////////
 
 type GeminiLLMClient struct {
@@ -101,25 +135,30 @@ type GeminiResponse struct {
 // LLMProcessor implements the Processor interface using a Large Language Model
 // It holds the necessary configuration for making API calls and normalizing results
 type LLMProcessor struct {
-	aliasMap  map[string]string // The map for normalizing skills
-	caser     cases.Caser       // A caser for handling unicode-correct title casing
+	aliases   *AliasStore // The live alias map for normalizing skills
+	caser     cases.Caser // A caser for handling unicode-correct title casing
 	llmClient LLMClient
 }
 
-// NewLLMProcessor creates a new LLMProcessor using the provided aliasMap and an LLMClient (real or mock).
-func NewLLMProcessor(aliasMap map[string]string, llmClient LLMClient) Processor {
+// NewLLMProcessor creates a new LLMProcessor using the provided alias store and an LLMClient (real or mock).
+func NewLLMProcessor(aliases *AliasStore, llmClient LLMClient) Processor {
 	return &LLMProcessor{
-		aliasMap: aliasMap,
+		aliases: aliases,
 		// We use cases.Title with english as the base language
 		caser:     cases.Title(language.English),
 		llmClient: llmClient,
 	}
 }
 
+// ReloadAliases refreshes the alias map used for normalization, e.g. after new aliases were added.
+func (p *LLMProcessor) ReloadAliases(ctx context.Context) (int, error) {
+	return p.aliases.Reload(ctx)
+}
+
 // In real code, you'd pass the real Gemini client
 /*
 llmClient := &GeminiLLMClient{apiKey: "your-key", client: &http.Client{}}
-p := NewLLMProcessor(myAliasMap, llmClient)
+p := NewLLMProcessor(NewAliasStore(store), llmClient)
 */
 
 ////////////////////////////////////////////////////////////////////////
@@ -139,8 +178,8 @@ func (p *LLMProcessor) ExtractAndNormalize(ctx context.Context, text string) ([]
 		return nil, fmt.Errorf("skill extraction LLM call failed: %w", err)
 	}
 
-	// 2.5 Strip markdown code fences if present
-	cleanResponse := stripCodeFences(llmResponse)
+	// 2.5 Strip markdown code fences and surrounding prose if present
+	cleanResponse := cleanLLMJSON(llmResponse)
 
 	// 3. Parse JSON array from cleaned response
 	var rawSkills []string
@@ -169,8 +208,8 @@ func (p *LLMProcessor) ExtractProficiencies(ctx context.Context, text string, kn
 		return nil, fmt.Errorf("proficiency extraction LLM call failed: %w", err)
 	}
 
-	// 3.5 Strip markdown code fences if present. 
-	cleanResponse := stripCodeFences(llmResponse)
+	// 3.5 Strip markdown code fences and surrounding prose if present
+	cleanResponse := cleanLLMJSON(llmResponse)
 
 	// 4. Parse the LLM's string response into a map.
 	var proficiencies map[string]string
@@ -181,31 +220,120 @@ func (p *LLMProcessor) ExtractProficiencies(ctx context.Context, text string, kn
 	// 5. Validate the results to ensure only allowed proficiency values are used.
 	p.validateProficiencies(proficiencies)
 
+	// 6. Drop any skills the LLM invented that were not in the known skills list.
+	dropUnknownSkills(proficiencies, knownSkills)
+
 	return proficiencies, nil
 }
 
+// ExtractRequiredSkillsWithWeight extracts a task's skills in a single LLM call that also rates how central
+// each one is. Skills without a known rating get DefaultSkillWeight, as do all skills when the model
+// answers with a plain array instead of the requested object.
+func (p *LLMProcessor) ExtractRequiredSkillsWithWeight(ctx context.Context, description string) ([]WeightedSkill, error) {
+	// 1. Build the specific prompt for this task
+	prompt := fmt.Sprintf(weightedSkillExtractionPrompt, description)
+
+	// 2. Call the LLM with the prompt.
+	llmResponse, err := p.llmClient.CallLLM(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("weighted skill extraction LLM call failed: %w", err)
+	}
+
+	// 2.5 Strip markdown code fences and surrounding prose if present
+	cleanResponse := cleanLLMJSON(llmResponse)
+
+	// 3. Parse the skill-to-importance object, falling back to an unrated array of skills
+	var importances map[string]string
+	if err := json.Unmarshal([]byte(cleanResponse), &importances); err != nil {
+		var rawSkills []string
+		if err := json.Unmarshal([]byte(cleanResponse), &rawSkills); err != nil {
+			return nil, fmt.Errorf("failed to parse LLM weighted skill output as JSON object: %s", llmResponse)
+		}
+		importances = make(map[string]string, len(rawSkills))
+		for _, raw := range rawSkills {
+			importances[raw] = ""
+		}
+	}
+
+	// 4. Normalize the names and turn ratings into weights.
+	return p.normalizeWeighted(importances), nil
+}
+
 ////////////////////////////////////////////////////////////////////////
 // Private Helper Methods
 ////////////////////////////////////////////////////////////////////////
 
-// stripCodeFences removes Markdown code fences (``` optional-language\n ... ```) from the input string.
-// It trims whitespace, then extracts and returns the content inside the fences if present.
-// If no fences are found, it returns the trimmed string unchanged.
-func stripCodeFences(s string) string {
-    s = strings.TrimSpace(s)
-    // Regex to match ``` optionally followed by language id, then newline,
-    // capture everything until the closing ```
-    re := regexp.MustCompile("(?s)^```[a-zA-Z]*\\n(.*)```$")
-    matches := re.FindStringSubmatch(s)
-    if len(matches) == 2 {
-        return matches[1]
-    }
-    return s
+// dropUnknownSkills removes, in place, every proficiency entry whose skill
+// is not present in knownSkills, so hallucinated skills never reach the caller.
+func dropUnknownSkills(proficiencies map[string]string, knownSkills []string) {
+	known := make(map[string]struct{}, len(knownSkills))
+	for _, skill := range knownSkills {
+		known[skill] = struct{}{}
+	}
+
+	for skill := range proficiencies {
+		if _, ok := known[skill]; !ok {
+			delete(proficiencies, skill)
+		}
+	}
+}
+
+// cleanLLMJSON digs the JSON out of a raw LLM response. Models often wrap their answer in a
+// Markdown fence (```json ... ```) or add a sentence before or after it, so this trims whitespace,
+// strips a leading ```json or ``` and a trailing ```, then returns the first balanced [...] or {...}
+// block. Responses without such a block are returned trimmed, for json.Unmarshal to reject.
+func cleanLLMJSON(s string) string {
+	s = strings.TrimSpace(s)
+	if rest, ok := strings.CutPrefix(s, "```json"); ok {
+		s = rest
+	} else {
+		s = strings.TrimPrefix(s, "```")
+	}
+	s = strings.TrimSpace(strings.TrimSuffix(s, "```"))
+
+	start := strings.IndexAny(s, "[{")
+	if start < 0 {
+		return s
+	}
+
+	// Walk to the matching closing bracket, ignoring brackets inside JSON strings
+	var closers []byte
+	inString, escaped := false, false
+	for i := start; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '[':
+			closers = append(closers, ']')
+		case c == '{':
+			closers = append(closers, '}')
+		case c == ']' || c == '}':
+			if len(closers) == 0 || closers[len(closers)-1] != c {
+				return s
+			}
+			closers = closers[:len(closers)-1]
+			if len(closers) == 0 {
+				return s[start : i+1]
+			}
+		}
+	}
+	return s
 }
 
 // CallLLM implements the LLMClient interface using the Gemini API.
 // It takes a prompt, handles the HTTP request/response, and returns the raw text output from the model.
 func (g *GeminiLLMClient) CallLLM(ctx context.Context, prompt string) (string, error) {
+	// Every return below sets outcome, so the deferred metric reports why the call ended
+	start := time.Now()
+	outcome := metrics.LLMOutcomeHTTPError
+	defer func() { metrics.ObserveLLMCall(outcome, start) }()
+
 	requestBody := map[string]any{
 		"contents": []map[string]any{{"parts": []map[string]string{{"text": prompt}}}},
 	}
@@ -231,6 +359,9 @@ func (g *GeminiLLMClient) CallLLM(ctx context.Context, prompt string) (string, e
 		return "", fmt.Errorf("gemini API returned non-200 status: %s", resp.Status)
 	}
 
+	// From here on the API has answered, so failures are about its response
+	outcome = metrics.LLMOutcomeParseError
+
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return "", fmt.Errorf("failed to read response body: %w", err)
@@ -245,28 +376,27 @@ func (g *GeminiLLMClient) CallLLM(ctx context.Context, prompt string) (string, e
 		return "", errors.New("unexpected LLM response format: no content found")
 	}
 
+	outcome = metrics.LLMOutcomeSuccess
 	return apiResp.Candidates[0].Content.Parts[0].Text, nil
 }
 
 // normalize is a private method that takes a slice of raw stringsand
 // converts them into a clean, deduplicated slice of canonical skill names
+//
+// Casing rule: every raw skill is lowercased first. Known aliases map to their
+// canonical name; anything else is Title Cased from the lowercase form. So
+// "node.js", "Node.js" and "NODE.JS" all become "Node.js". The store matches
+// these names against existing skills ignoring case, so a skill already saved
+// with different casing keeps its stored name.
 func (p *LLMProcessor) normalize(rawSkills []string) []string {
 	// We use a map[string]struct{} as a Set to automatically handle duplicates
 	normalizedSet := make(map[string]struct{})
 
+	// Read one consistent view of the aliases, even if they are reloaded meanwhile
+	aliasMap := p.aliases.snapshot()
+
 	for _, raw := range rawSkills {
-		// Standardize the lookup key by converting it into lowercase
-		lookup := strings.ToLower(raw)
-
-		// Check if the raw skill has a known alias in our map.
-		if canonical, ok := p.aliasMap[lookup]; ok {
-			// If yes, use the official canonical name
-			normalizedSet[canonical] = struct{}{}
-		} else {
-			// If no, this is a new skill. We apply Title Case as a default
-			// formatting rule. We use the 'caser' for Unicode-safe casing
-			normalizedSet[p.caser.String(lookup)] = struct{}{}
-		}
+		normalizedSet[p.canonicalName(aliasMap, raw)] = struct{}{}
 	}
 
 	// Convert the set(map) back into a slice
@@ -279,6 +409,48 @@ func (p *LLMProcessor) normalize(rawSkills []string) []string {
 	return normalized
 }
 
+// normalizeWeighted normalizes skill names like normalize and weights each by its importance rating.
+// Raw names that normalize to the same skill keep the highest weight among them.
+// The result is ordered by descending weight, then by name.
+func (p *LLMProcessor) normalizeWeighted(importances map[string]string) []WeightedSkill {
+	aliasMap := p.aliases.snapshot()
+
+	weights := make(map[string]float64, len(importances))
+	for raw, importance := range importances {
+		weight, ok := skillImportanceWeights[strings.ToLower(strings.TrimSpace(importance))]
+		if !ok {
+			weight = DefaultSkillWeight
+		}
+		name := p.canonicalName(aliasMap, raw)
+		weights[name] = max(weights[name], weight)
+	}
+
+	weighted := make([]WeightedSkill, 0, len(weights))
+	for name, weight := range weights {
+		weighted = append(weighted, WeightedSkill{Name: name, Weight: weight})
+	}
+	slices.SortFunc(weighted, func(a, b WeightedSkill) int {
+		return cmp.Or(cmp.Compare(b.Weight, a.Weight), strings.Compare(a.Name, b.Name))
+	})
+	return weighted
+}
+
+// canonicalName standardizes one raw skill: known aliases map to their canonical name,
+// anything else is Title Cased from its lowercase form (see normalize).
+func (p *LLMProcessor) canonicalName(aliasMap map[string]string, raw string) string {
+	// Standardize the lookup key by converting it into lowercase
+	lookup := strings.ToLower(raw)
+
+	// Check if the raw skill has a known alias in our map.
+	if canonical, ok := aliasMap[lookup]; ok {
+		// If yes, use the official canonical name
+		return canonical
+	}
+	// If no, this is a new skill. We apply Title Case as a default
+	// formatting rule. We use the 'caser' for Unicode-safe casing
+	return p.caser.String(lookup)
+}
+
 // validateProficiencies ensures that the proficiency levels returned by the LLM
 // conform to our application's allowed values ('beginner', 'intermediate', 'expert').
 // It modifies the map in-place, changing any invalid value to 'beginner'.
@@ -4,9 +4,13 @@ package skillz_test
 import (
 	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
+	"github.com/pranav244872/synapse/metrics"
 	"github.com/pranav244872/synapse/skillz"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 )
 
 // A mock is a stand-in for real dependency. Our LLMProcessr needs an LLMClient
@@ -90,6 +94,41 @@ func TestLLMProcessor_ExtractAndNormalize(t *testing.T) {
 			want:         nil,
 	 		wantErr:      true, // We expect a JSON parsing error.
 		},
+		{
+			name:      "Fenced Response - JSON Code Block",
+			inputText: "Go and Kubernetes developer.",
+			// Gemini often wraps its answer in a Markdown code block.
+			mockResponse: "```json\n[\"golang\", \"k8s\"]\n```",
+			mockErr:      nil,
+			want:         []string{"Go", "Kubernetes"},
+			wantErr:      false,
+		},
+		{
+			name:         "Fenced Response - Bare Fence on One Line",
+			inputText:    "Go developer.",
+			mockResponse: "```[\"go\"]```",
+			mockErr:      nil,
+			want:         []string{"Go"},
+			wantErr:      false,
+		},
+		{
+			name:      "Prose-Wrapped Response",
+			inputText: "Postgres and Go developer.",
+			// Extra sentences around the array are ignored, even with brackets inside the strings.
+			mockResponse: "Here are the skills I found: [\"postgres\", \"go\", \"C [legacy]\"]. Let me know if you need more!",
+			mockErr:      nil,
+			want:         []string{"PostgreSQL", "Go", "C [Legacy]"},
+			wantErr:      false,
+		},
+		{
+			name:      "Casing Variants - Same Skill",
+			inputText: "node.js, Node.js and NODE.JS",
+			// Unknown skills differing only in case collapse into one Title Cased name.
+			mockResponse: `["node.js", "Node.js", "NODE.JS"]`,
+			mockErr:      nil,
+			want:         []string{"Node.js"},
+			wantErr:      false,
+		},
 		{
 			name:         "Edge Case - Empty LLM Response",
 			inputText:    "No skills mentioned here.",
@@ -114,7 +153,9 @@ func TestLLMProcessor_ExtractAndNormalize(t *testing.T) {
 
 			// 2. Create the LLMProcessor instance we want to test, injecting our mock client.
 			// This is called "Dependency Injection".
-			p := skillz.NewLLMProcessor(testAliasMap, mockClient)
+			aliases := skillz.NewAliasStore(nil)
+			aliases.Set(testAliasMap)
+			p := skillz.NewLLMProcessor(aliases, mockClient)
 
 			// --- ACT ---
 			// 3. Call the method we are testing.
@@ -178,6 +219,38 @@ func TestLLMProcessor_ExtractProficiencies(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Validation Case - Unknown Skill Returned",
+			// The LLM invents a skill ("Kubernetes") that was never in knownSkills.
+			// It must be dropped rather than polluting the result.
+			mockResponse: `{"Go": "expert", "Docker": "intermediate", "Kubernetes": "expert"}`,
+			mockErr:      nil,
+			want: map[string]string{
+				"Go":     "expert",
+				"Docker": "intermediate",
+			},
+			wantErr: false,
+		},
+		{
+			name:         "Fenced Response - JSON Code Block",
+			mockResponse: "```json\n{\"Go\": \"expert\", \"Docker\": \"beginner\"}\n```",
+			mockErr:      nil,
+			want: map[string]string{
+				"Go":     "expert",
+				"Docker": "beginner",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Prose-Wrapped Response",
+			// The object is found between the surrounding sentences.
+			mockResponse: "Sure! Based on the resume:\n{\"Go\": \"intermediate\"}\nThese are estimates.",
+			mockErr:      nil,
+			want: map[string]string{
+				"Go": "intermediate",
+			},
+			wantErr: false,
+		},
 		{
 			name:         "Error Case - LLM Call Fails",
 			mockResponse: "",
@@ -201,8 +274,8 @@ func TestLLMProcessor_ExtractProficiencies(t *testing.T) {
 				mockResponse: tc.mockResponse,
 				mockErr:      tc.mockErr,
 			}
-			// We don't need an alias map for this test, so we can pass nil or an empty map.
-			p := skillz.NewLLMProcessor(nil, mockClient)
+			// We don't need any aliases for this test, so an empty store will do.
+			p := skillz.NewLLMProcessor(skillz.NewAliasStore(nil), mockClient)
 
 			// --- ACT ---
 			// We use a dummy resume text because the mock client doesn't actually use it.
@@ -222,3 +295,123 @@ func TestLLMProcessor_ExtractProficiencies(t *testing.T) {
 		})
 	}
 }
+
+////////////////////////////////////////////////////////////////////////
+// Test for ExtractRequiredSkillsWithWeight
+////////////////////////////////////////////////////////////////////////
+
+func TestLLMProcessor_ExtractRequiredSkillsWithWeight(t *testing.T) {
+	testCases := []struct {
+		name         string
+		mockResponse string
+		mockErr      error
+		want         []skillz.WeightedSkill // Ordered by descending weight, then name
+		wantErr      bool
+	}{
+		{
+			name:         "Happy Path - Rated Skills",
+			mockResponse: `{"golang": "core", "postgres": "supporting", "docker": "peripheral"}`,
+			want: []skillz.WeightedSkill{
+				{Name: "Go", Weight: 1.0},
+				{Name: "PostgreSQL", Weight: 0.6},
+				{Name: "Docker", Weight: 0.3},
+			},
+		},
+		{
+			name: "Aliases Collapse - Highest Weight Wins",
+			// "go" and "golang" are the same skill; the core rating is kept.
+			mockResponse: `{"go": "peripheral", "golang": "Core"}`,
+			want:         []skillz.WeightedSkill{{Name: "Go", Weight: 1.0}},
+		},
+		{
+			name: "Validation Case - Unknown Importance Defaults",
+			// An importance outside core/supporting/peripheral counts as fully required.
+			mockResponse: `{"k8s": "critical", "docker": "supporting"}`,
+			want: []skillz.WeightedSkill{
+				{Name: "Kubernetes", Weight: skillz.DefaultSkillWeight},
+				{Name: "Docker", Weight: 0.6},
+			},
+		},
+		{
+			name: "Plain Array - Every Skill Defaults",
+			// A model that ignores the object format still yields usable skills.
+			mockResponse: "```json\n[\"Docker\", \"golang\"]\n```",
+			want: []skillz.WeightedSkill{
+				{Name: "Docker", Weight: skillz.DefaultSkillWeight},
+				{Name: "Go", Weight: skillz.DefaultSkillWeight},
+			},
+		},
+		{
+			name:         "Edge Case - No Skills",
+			mockResponse: `{}`,
+			want:         []skillz.WeightedSkill{},
+		},
+		{
+			name:    "Error Case - LLM Call Fails",
+			mockErr: errors.New("API timeout"),
+			wantErr: true,
+		},
+		{
+			name:         "Error Case - Malformed JSON from LLM",
+			mockResponse: `{"Go": "core", }`,
+			wantErr:      true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			aliases := skillz.NewAliasStore(nil)
+			aliases.Set(map[string]string{"go": "Go", "golang": "Go", "k8s": "Kubernetes", "postgres": "PostgreSQL"})
+			p := skillz.NewLLMProcessor(aliases, &mockLLMClient{mockResponse: tc.mockResponse, mockErr: tc.mockErr})
+
+			got, err := p.ExtractRequiredSkillsWithWeight(context.Background(), "dummy task description")
+
+			if (err != nil) != tc.wantErr {
+				t.Errorf("ExtractRequiredSkillsWithWeight() error = %v, wantErr %v", err, tc.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ExtractRequiredSkillsWithWeight() got = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+////////////////////////////////////////////////////////////////////////
+// Test for GeminiLLMClient metrics
+////////////////////////////////////////////////////////////////////////
+
+func TestGeminiLLMClient_CallLLMRecordsOutcome(t *testing.T) {
+	testCases := []struct {
+		name            string
+		status          int
+		body            string
+		expectedOutcome string
+	}{
+		{"Success", http.StatusOK, `{"candidates":[{"content":{"parts":[{"text":"[\"Go\"]"}]}}]}`, metrics.LLMOutcomeSuccess},
+		{"Non-200 status", http.StatusInternalServerError, `{}`, metrics.LLMOutcomeHTTPError},
+		{"Malformed response", http.StatusOK, `not json`, metrics.LLMOutcomeParseError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gemini := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.body))
+			}))
+			defer gemini.Close()
+
+			client := skillz.NewGeminiLLMClient("test-key", gemini.URL, gemini.Client())
+			before := testutil.ToFloat64(metrics.LLMCalls.WithLabelValues(tc.expectedOutcome))
+
+			_, err := client.CallLLM(context.Background(), "prompt")
+
+			if (err == nil) != (tc.expectedOutcome == metrics.LLMOutcomeSuccess) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if after := testutil.ToFloat64(metrics.LLMCalls.WithLabelValues(tc.expectedOutcome)); after != before+1 {
+				t.Errorf("expected %s count to grow by 1, got %v -> %v", tc.expectedOutcome, before, after)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+// skillz/openai_llm_client.go
+package skillz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pranav244872/synapse/metrics"
+)
+
+////////////////////////////////////////////////////////////////////////
+
+// Names accepted by LLM_PROVIDER; an empty provider means Gemini
+const (
+	ProviderGemini = "gemini"
+	ProviderOpenAI = "openai"
+)
+
+// DefaultOpenAIBaseURL is the OpenAI API root used when no base URL is configured.
+// Any OpenAI-compatible server (vLLM, Ollama, LiteLLM, ...) can be used by pointing the base URL at it.
+const DefaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// OpenAILLMClient calls the OpenAI chat-completions API
+type OpenAILLMClient struct {
+	apiKey string
+	url    string
+	model  string
+	client *http.Client
+}
+
+// NewOpenAILLMClient creates a new client for the chat-completions endpoint under baseURL,
+// e.g. "https://api.openai.com/v1". An empty baseURL uses DefaultOpenAIBaseURL.
+func NewOpenAILLMClient(apiKey string, baseURL string, model string, client *http.Client) LLMClient {
+	if baseURL == "" {
+		baseURL = DefaultOpenAIBaseURL
+	}
+	return &OpenAILLMClient{
+		apiKey: apiKey,
+		url:    strings.TrimSuffix(baseURL, "/") + "/chat/completions",
+		model:  model,
+		client: client,
+	}
+}
+
+////////////////////////////////////////////////////////////////////////
+
+// openAIMessage is one chat message, in both the request and the response
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// openAIRequest is the chat-completions request body; the prompt is sent as a single user message
+type openAIRequest struct {
+	Model    string          `json:"model"`
+	Messages []openAIMessage `json:"messages"`
+}
+
+// OpenAIResponse defines the structure of the JSON response we expect from the chat-completions API
+// We only map the fields we need to extract the assistant's text output
+type OpenAIResponse struct {
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+}
+
+////////////////////////////////////////////////////////////////////////
+
+// CallLLM implements the LLMClient interface using the OpenAI chat-completions API.
+// It sends the prompt as a user message and returns the text of the first choice.
+func (o *OpenAILLMClient) CallLLM(ctx context.Context, prompt string) (string, error) {
+	// Every return below sets outcome, so the deferred metric reports why the call ended
+	start := time.Now()
+	outcome := metrics.LLMOutcomeHTTPError
+	defer func() { metrics.ObserveLLMCall(outcome, start) }()
+
+	bodyBytes, err := json.Marshal(openAIRequest{
+		Model:    o.model,
+		Messages: []openAIMessage{{Role: "user", Content: prompt}},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", o.url, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return "", fmt.Errorf("failed to create http request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+o.apiKey)
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("http request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("openai API returned non-200 status: %s", resp.Status)
+	}
+
+	// From here on the API has answered, so failures are about its response
+	outcome = metrics.LLMOutcomeParseError
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var apiResp OpenAIResponse
+	if err := json.Unmarshal(respBody, &apiResp); err != nil {
+		return "", fmt.Errorf("failed to unmarshal api response: %w", err)
+	}
+
+	if len(apiResp.Choices) == 0 || apiResp.Choices[0].Message.Content == "" {
+		return "", errors.New("unexpected LLM response format: no content found")
+	}
+
+	outcome = metrics.LLMOutcomeSuccess
+	return apiResp.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,84 @@
+// skillz/openai_llm_client_test.go
+package skillz_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pranav244872/synapse/skillz"
+)
+
+// roundTripFunc lets a plain function stand in for the HTTP transport,
+// so requests can be inspected and answered without a server
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestOpenAILLMClient_CallLLM(t *testing.T) {
+	testCases := []struct {
+		name         string
+		status       int
+		body         string
+		expectedText string
+		expectErr    bool
+	}{
+		{"Success", http.StatusOK, `{"choices":[{"message":{"role":"assistant","content":"[\"Go\"]"}}]}`, `["Go"]`, false},
+		{"Non-200 status", http.StatusUnauthorized, `{"error":{"message":"bad key"}}`, "", true},
+		{"No choices", http.StatusOK, `{"choices":[]}`, "", true},
+		{"Malformed response", http.StatusOK, `not json`, "", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var captured *http.Request
+			var capturedBody []byte
+			transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				captured = r
+				capturedBody, _ = io.ReadAll(r.Body)
+				return &http.Response{
+					StatusCode: tc.status,
+					Status:     http.StatusText(tc.status),
+					Header:     http.Header{"Content-Type": {"application/json"}},
+					Body:       io.NopCloser(strings.NewReader(tc.body)),
+				}, nil
+			})
+
+			client := skillz.NewOpenAILLMClient("test-key", "https://llm.example.com/v1/", "gpt-4o-mini", &http.Client{Transport: transport})
+			text, err := client.CallLLM(context.Background(), "extract skills")
+
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if text != tc.expectedText {
+				t.Errorf("expected text %q, got %q", tc.expectedText, text)
+			}
+
+			// The request follows the chat-completions shape regardless of the outcome
+			if got := captured.URL.String(); got != "https://llm.example.com/v1/chat/completions" {
+				t.Errorf("unexpected URL %s", got)
+			}
+			if got := captured.Header.Get("Authorization"); got != "Bearer test-key" {
+				t.Errorf("unexpected Authorization header %q", got)
+			}
+			var sent struct {
+				Model    string `json:"model"`
+				Messages []struct {
+					Role    string `json:"role"`
+					Content string `json:"content"`
+				} `json:"messages"`
+			}
+			if err := json.Unmarshal(capturedBody, &sent); err != nil {
+				t.Fatalf("request body is not JSON: %v", err)
+			}
+			if sent.Model != "gpt-4o-mini" || len(sent.Messages) != 1 || sent.Messages[0].Role != "user" || sent.Messages[0].Content != "extract skills" {
+				t.Errorf("unexpected request body %s", capturedBody)
+			}
+		})
+	}
+}
@@ -19,4 +19,22 @@ type Processor interface {
 	// ExtractProficiencies takes raw text and a list of known skills, returning a map
 	// of each skill to its estimated proficiency level.
 	ExtractProficiencies(ctx context.Context, text string, knownSkills []string) (map[string]string, error)
+
+	// ExtractRequiredSkillsWithWeight takes a task description and returns its standardized skills,
+	// each weighted by how central it is to the task.
+	ExtractRequiredSkillsWithWeight(ctx context.Context, description string) ([]WeightedSkill, error)
+}
+
+// WeightedSkill is a standardized skill name with its importance to a task,
+// from just above 0 (peripheral) to 1 (core).
+type WeightedSkill struct {
+	Name   string
+	Weight float64
+}
+
+// AliasReloader is implemented by processors that normalize skills with an in-memory alias map.
+// Callers that change the skill_aliases table use it to refresh that map without a restart.
+type AliasReloader interface {
+	// ReloadAliases reloads the alias map from the database and returns how many aliases it holds.
+	ReloadAliases(ctx context.Context) (int, error)
 }
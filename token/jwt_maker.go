@@ -1,6 +1,7 @@
 package token
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,7 +12,7 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-// JWTMaker is a struct that handles creation and verification of JWT tokens.
+// JWTMaker is a Maker that handles creation and verification of JWT tokens.
 type JWTMaker struct {
 	secretKey string // A secret key used to sign and verify JWTs.
 }
@@ -35,17 +36,7 @@ func NewJWTMaker(secretKey string) (*JWTMaker, error) {
 // - duration: how long the token will be valid
 func (maker *JWTMaker) CreateToken(userID int64, role db.UserRole, teamID pgtype.Int8, duration time.Duration) (string, error) {
 	// Define the payload (data stored inside the token)
-	payload := jwt.MapClaims{
-		"user_id": userID,                      // Custom claim: the user's ID
-		"role":    role,                        // Custom claim: the user's role
-		"exp":     time.Now().Add(duration).Unix(), // Standard claim: expiration time
-		"iat":     time.Now().Unix(),               // Standard claim: issued at time
-	}
-
-	// Only add the team_id claim if the user is actually assigned to a team.
-	if teamID.Valid {
-		payload["team_id"] = teamID.Int64
-	}
+	payload := newClaims(userID, role, teamID, duration)
 
 	// Create a new JWT token using the HS256 signing algorithm
 	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
@@ -68,16 +59,19 @@ func (maker *JWTMaker) VerifyToken(tokenString string) (jwt.MapClaims, error) {
 		return []byte(maker.secretKey), nil
 	})
 
-	// If there's an error in parsing (e.g., invalid token), return it
+	// If there's an error in parsing (e.g., invalid token), report whether it only expired
 	if err != nil {
-		return nil, err
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
 	}
 
 	// Convert the claims to a map (key-value format)
 	claims, ok := token.Claims.(jwt.MapClaims)
 	// Also check if the token is valid
 	if !ok || !token.Valid {
-		return nil, fmt.Errorf("invalid token")
+		return nil, ErrInvalidToken
 	}
 
 	// Return the claims from the token (like user_id, role, etc.)
@@ -0,0 +1,21 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/pranav244872/synapse/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWTMaker(t *testing.T) {
+	testMaker(t, func(key string) (Maker, error) { return NewJWTMaker(key) })
+}
+
+func TestNewJWTMakerKeySize(t *testing.T) {
+	_, err := NewJWTMaker(util.RandomString(31))
+	require.Error(t, err)
+
+	// Longer keys are fine for HMAC
+	_, err = NewJWTMaker(util.RandomString(64))
+	require.NoError(t, err)
+}
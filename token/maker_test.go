@@ -0,0 +1,109 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pranav244872/synapse/db/sqlc"
+	"github.com/pranav244872/synapse/util"
+	"github.com/stretchr/testify/require"
+)
+
+// testMaker runs the checks every Maker must pass; newMaker builds one from a 32-character key
+func testMaker(t *testing.T, newMaker func(key string) (Maker, error)) {
+	maker, err := newMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	t.Run("Claims round-trip as a decoded JWT has them", func(t *testing.T) {
+		userID := util.RandomInt(1, 1000)
+		teamID := util.RandomInt(1, 1000)
+		issuedAt := time.Now()
+
+		token, err := maker.CreateToken(userID, db.UserRoleManager, pgtype.Int8{Int64: teamID, Valid: true}, time.Minute)
+		require.NoError(t, err)
+
+		claims, err := maker.VerifyToken(token)
+		require.NoError(t, err)
+		require.Equal(t, float64(userID), claims["user_id"])
+		require.Equal(t, string(db.UserRoleManager), claims["role"])
+		require.Equal(t, float64(teamID), claims["team_id"])
+		require.InDelta(t, float64(issuedAt.Add(time.Minute).Unix()), claims["exp"], 1)
+		require.InDelta(t, float64(issuedAt.Unix()), claims["iat"], 1)
+	})
+
+	t.Run("Users without a team get no team_id", func(t *testing.T) {
+		token, err := maker.CreateToken(1, db.UserRoleAdmin, pgtype.Int8{}, time.Minute)
+		require.NoError(t, err)
+
+		claims, err := maker.VerifyToken(token)
+		require.NoError(t, err)
+		require.NotContains(t, claims, "team_id")
+	})
+
+	t.Run("Expired tokens are rejected", func(t *testing.T) {
+		token, err := maker.CreateToken(1, db.UserRoleEngineer, pgtype.Int8{}, -time.Minute)
+		require.NoError(t, err)
+
+		claims, err := maker.VerifyToken(token)
+		require.ErrorIs(t, err, ErrExpiredToken)
+		require.Nil(t, claims)
+	})
+
+	t.Run("Altered tokens are rejected", func(t *testing.T) {
+		token, err := maker.CreateToken(1, db.UserRoleEngineer, pgtype.Int8{}, time.Minute)
+		require.NoError(t, err)
+
+		// Change one character in the middle of the token
+		tampered := []byte(token)
+		middle := len(tampered) / 2
+		if tampered[middle] == 'A' {
+			tampered[middle] = 'B'
+		} else {
+			tampered[middle] = 'A'
+		}
+
+		_, err = maker.VerifyToken(string(tampered))
+		require.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Tokens from another key are rejected", func(t *testing.T) {
+		other, err := newMaker(util.RandomString(32))
+		require.NoError(t, err)
+		token, err := other.CreateToken(1, db.UserRoleEngineer, pgtype.Int8{}, time.Minute)
+		require.NoError(t, err)
+
+		_, err = maker.VerifyToken(token)
+		require.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Garbage is rejected", func(t *testing.T) {
+		_, err := maker.VerifyToken("not-a-token")
+		require.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
+
+func TestNewMaker(t *testing.T) {
+	key := util.RandomString(32)
+
+	for _, tokenType := range []string{"", TypeJWT} {
+		maker, err := NewMaker(tokenType, key)
+		require.NoError(t, err)
+		require.IsType(t, &JWTMaker{}, maker)
+	}
+
+	maker, err := NewMaker(TypePASETO, key)
+	require.NoError(t, err)
+	require.IsType(t, &PasetoMaker{}, maker)
+
+	_, err = NewMaker("opaque", key)
+	require.Error(t, err)
+
+	// The formats do not accept each other's tokens
+	jwtMaker, err := NewMaker(TypeJWT, key)
+	require.NoError(t, err)
+	token, err := jwtMaker.CreateToken(1, db.UserRoleEngineer, pgtype.Int8{}, time.Minute)
+	require.NoError(t, err)
+	_, err = maker.VerifyToken(token)
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
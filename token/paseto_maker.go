@@ -0,0 +1,125 @@
+package token
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pranav244872/synapse/db/sqlc"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// pasetoHeader starts every PASETO v2 token with a symmetric (local) key
+const pasetoHeader = "v2.local."
+
+// PasetoMaker is a Maker for PASETO v2.local tokens. The claims are encrypted and authenticated
+// with XChaCha20-Poly1305, so unlike a JWT's they cannot be read by the client.
+type PasetoMaker struct {
+	aead cipher.AEAD // XChaCha20-Poly1305 keyed with the symmetric key
+}
+
+// NewPasetoMaker creates a new PasetoMaker with the provided symmetric key.
+// The key must be exactly 32 characters long, the key size of XChaCha20-Poly1305.
+func NewPasetoMaker(symmetricKey string) (*PasetoMaker, error) {
+	if len(symmetricKey) != chacha20poly1305.KeySize {
+		return nil, fmt.Errorf("invalid key size: must be exactly %d characters", chacha20poly1305.KeySize)
+	}
+
+	aead, err := chacha20poly1305.NewX([]byte(symmetricKey))
+	if err != nil {
+		return nil, fmt.Errorf("cannot create cipher: %w", err)
+	}
+	return &PasetoMaker{aead: aead}, nil
+}
+
+// CreateToken generates a PASETO token carrying the same claims as a JWT from JWTMaker.
+func (maker *PasetoMaker) CreateToken(userID int64, role db.UserRole, teamID pgtype.Int8, duration time.Duration) (string, error) {
+	message, err := json.Marshal(newClaims(userID, role, teamID, duration))
+	if err != nil {
+		return "", fmt.Errorf("cannot encode claims: %w", err)
+	}
+
+	randomBytes := make([]byte, chacha20poly1305.NonceSizeX)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", fmt.Errorf("cannot generate nonce: %w", err)
+	}
+	return maker.encrypt(randomBytes, message)
+}
+
+// encrypt seals message into a v2.local token without a footer. v2.local derives the nonce from
+// randomBytes and the message, so a weak random source cannot repeat it; passing fixed bytes
+// reproduces the specification's test vectors.
+func (maker *PasetoMaker) encrypt(randomBytes, message []byte) (string, error) {
+	hash, err := blake2b.New(chacha20poly1305.NonceSizeX, randomBytes)
+	if err != nil {
+		return "", fmt.Errorf("cannot derive nonce: %w", err)
+	}
+	hash.Write(message)
+	nonce := hash.Sum(nil)
+
+	ciphertext := maker.aead.Seal(nil, nonce, message, preAuthEncode([]byte(pasetoHeader), nonce, nil))
+	return pasetoHeader + base64.RawURLEncoding.EncodeToString(append(nonce, ciphertext...)), nil
+}
+
+// decrypt opens a v2.local token without a footer, failing if it was altered or made with another key
+func (maker *PasetoMaker) decrypt(tokenString string) ([]byte, error) {
+	// Footers are never added, so a token carrying one was not made here
+	encoded, ok := strings.CutPrefix(tokenString, pasetoHeader)
+	if !ok || strings.Contains(encoded, ".") {
+		return nil, ErrInvalidToken
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(data) < chacha20poly1305.NonceSizeX+maker.aead.Overhead() {
+		return nil, ErrInvalidToken
+	}
+	nonce, ciphertext := data[:chacha20poly1305.NonceSizeX], data[chacha20poly1305.NonceSizeX:]
+
+	message, err := maker.aead.Open(nil, nonce, ciphertext, preAuthEncode([]byte(pasetoHeader), nonce, nil))
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	return message, nil
+}
+
+// VerifyToken decrypts the token, which fails if it was altered, and checks it has not expired.
+// If valid, it returns the claims inside the token.
+func (maker *PasetoMaker) VerifyToken(tokenString string) (jwt.MapClaims, error) {
+	message, err := maker.decrypt(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims jwt.MapClaims
+	if err := json.Unmarshal(message, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	if !time.Now().Before(time.Unix(int64(exp), 0)) {
+		return nil, ErrExpiredToken
+	}
+	return claims, nil
+}
+
+// preAuthEncode is PASETO's pre-authentication encoding: the number of pieces, then each piece
+// preceded by its length, all lengths as little-endian uint64s. It keeps pieces from being confused.
+func preAuthEncode(pieces ...[]byte) []byte {
+	encoded := binary.LittleEndian.AppendUint64(nil, uint64(len(pieces)))
+	for _, piece := range pieces {
+		encoded = binary.LittleEndian.AppendUint64(encoded, uint64(len(piece)))
+		encoded = append(encoded, piece...)
+	}
+	return encoded
+}
@@ -0,0 +1,78 @@
+package token
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pranav244872/synapse/db/sqlc"
+	"github.com/pranav244872/synapse/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasetoMaker(t *testing.T) {
+	testMaker(t, func(key string) (Maker, error) { return NewPasetoMaker(key) })
+}
+
+func TestNewPasetoMakerKeySize(t *testing.T) {
+	for _, size := range []int{31, 33} {
+		_, err := NewPasetoMaker(util.RandomString(size))
+		require.Error(t, err)
+	}
+}
+
+func TestPasetoTokenIsOpaque(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	token, err := maker.CreateToken(1, db.UserRoleManager, pgtype.Int8{}, time.Minute)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(token, pasetoHeader))
+
+	// A footer, which this maker never adds, makes the token invalid
+	_, err = maker.VerifyToken(token + ".Zm9vdGVy")
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+// TestPasetoV2LocalVectors checks the maker against the footerless v2.local test vectors
+// published with the PASETO specification
+func TestPasetoV2LocalVectors(t *testing.T) {
+	nullKey := bytes.Repeat([]byte{0x00}, 32)
+	fullKey := bytes.Repeat([]byte{0xff}, 32)
+	symmetricKey, err := hex.DecodeString("707172737475767778797a7b7c7d7e7f808182838485868788898a8b8c8d8e8f")
+	require.NoError(t, err)
+	nonce := bytes.Repeat([]byte{0x00}, 24)
+	message := []byte("Love is stronger than hate or fear")
+
+	testCases := []struct {
+		name    string
+		key     []byte
+		message []byte
+		token   string
+	}{
+		{"Empty message, null key", nullKey, nil, "v2.local.driRNhM20GQPvlWfJCepzh6HdijAq-yNUtKpdy5KXjKfpSKrOlqQvQ"},
+		{"Empty message, full key", fullKey, nil, "v2.local.driRNhM20GQPvlWfJCepzh6HdijAq-yNSOvpveyCsjPYfe9mtiJDVg"},
+		{"Empty message, symmetric key", symmetricKey, nil, "v2.local.driRNhM20GQPvlWfJCepzh6HdijAq-yNkIWACdHuLiJiW16f2GuGYA"},
+		{"Message, null key", nullKey, message, "v2.local.BEsKs5AolRYDb_O-bO-lwHWUextpShFSvu6cB-KuR4wR9uDMjd45cPiOF0zxb7rrtOB5tRcS7dWsFwY4ONEuL5sWeunqHC9jxU0"},
+		{"Message, full key", fullKey, message, "v2.local.BEsKs5AolRYDb_O-bO-lwHWUextpShFSjvSia2-chHyMi4LtHA8yFr1V7iZmKBWqzg5geEyNAAaD6xSEfxoET1xXqahe1jqmmPw"},
+		{"Message, symmetric key", symmetricKey, message, "v2.local.BEsKs5AolRYDb_O-bO-lwHWUextpShFSXlvv8MsrNZs3vTSnGQG4qRM9ezDl880jFwknSA6JARj2qKhDHnlSHx1GSCizfcF019U"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			maker, err := NewPasetoMaker(string(tc.key))
+			require.NoError(t, err)
+
+			token, err := maker.encrypt(nonce, tc.message)
+			require.NoError(t, err)
+			require.Equal(t, tc.token, token)
+
+			decrypted, err := maker.decrypt(tc.token)
+			require.NoError(t, err)
+			require.Equal(t, string(tc.message), string(decrypted))
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package util
+
+import (
+	"net/http"
+	"time"
+)
+
+// NewHTTPClient returns a client for calling one upstream service, such as the recommender or an LLM API.
+// Create it once and share it: its transport keeps connections alive between requests, and allows
+// more idle connections per host than the default so concurrent calls do not keep redialing.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = 100
+	transport.MaxIdleConnsPerHost = 32
+	transport.IdleConnTimeout = 90 * time.Second
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+}
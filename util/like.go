@@ -0,0 +1,12 @@
+package util
+
+import "strings"
+
+// likeEscaper backslash-escapes the characters LIKE treats specially
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// EscapeLike makes user input match itself literally inside a LIKE pattern, so a search for
+// "100%" or "snake_case" is not read as wildcards. Queries using it must declare ESCAPE '\'.
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}